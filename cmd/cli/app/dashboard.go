@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/cmd/cli/util"
+	"volcano.sh/volcano/pkg/cli/dashboard"
+	cliutil "volcano.sh/volcano/pkg/cli/util"
+)
+
+func buildDashboardCmd() *cobra.Command {
+	dashboardCmd := &cobra.Command{
+		Use:     "dashboard",
+		Aliases: []string{"top"},
+		Short:   "show the overview of the queues and jobs of the cluster, refreshing it live",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckError(cmd, dashboard.Dashboard(cmd.Context()))
+		},
+	}
+	dashboard.InitFlags(dashboardCmd)
+	cliutil.RegisterFlagCompletions(dashboardCmd, map[string]cliutil.CompletionFunc{"namespace": cliutil.CompleteNamespaces})
+	return dashboardCmd
+}
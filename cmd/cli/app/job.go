@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/cmd/cli/util"
+	"volcano.sh/volcano/pkg/cli/job"
+	cliutil "volcano.sh/volcano/pkg/cli/util"
+)
+
+func buildJobCmd() *cobra.Command {
+	jobCmd := &cobra.Command{
+		Use:   "job",
+		Short: "vcctl command line operation job",
+	}
+
+	jobCommandMap := map[string]struct {
+		Short       string
+		RunFunction func(cmd *cobra.Command, args []string)
+		InitFlags   func(cmd *cobra.Command)
+		// NewJob is set for the commands whose name flag names the job to create.
+		NewJob bool
+		// ValidArgsFunction completes the positional arguments of the command.
+		ValidArgsFunction cliutil.CompletionFunc
+	}{
+		"run": {
+			Short: "run job by parameters from the command line",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.RunJob(cmd.Context()))
+			},
+			InitFlags: job.InitRunFlags,
+			NewJob:    true,
+		},
+		"create": {
+			Short: "create a job from a job template",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.CreateJob(cmd.Context()))
+			},
+			InitFlags: job.InitCreateFlags,
+			NewJob:    true,
+		},
+		"list": {
+			Short: "list job information",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.ListJobs(cmd.Context()))
+			},
+			InitFlags: job.InitListFlags,
+		},
+		"view": {
+			Short: "show job information with its pods, podgroup, queue share, plugin resources and events",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.ViewJob(cmd.Context()))
+			},
+			InitFlags: job.InitViewFlags,
+		},
+		"suspend": {
+			Short: "abort a job",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.SuspendJob(cmd.Context()))
+			},
+			InitFlags: job.InitSuspendFlags,
+		},
+		"resume": {
+			Short: "resume a job",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.ResumeJob(cmd.Context()))
+			},
+			InitFlags: job.InitResumeFlags,
+		},
+		"rerun": {
+			Short: "run a finished job again",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.RerunJob(cmd.Context(), args))
+			},
+			InitFlags:         job.InitRerunFlags,
+			ValidArgsFunction: cliutil.CompleteJobNameArg,
+		},
+		"logs": {
+			Short: "print the logs of all the replicas of a job",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.LogsJob(cmd.Context(), args))
+			},
+			InitFlags:         job.InitLogsFlags,
+			ValidArgsFunction: cliutil.CompleteJobNameArg,
+		},
+		"wait": {
+			Short: "wait until a job reaches a phase, Completed by default",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.WaitJob(cmd.Context(), args))
+			},
+			InitFlags:         job.InitWaitFlags,
+			ValidArgsFunction: cliutil.CompleteJobNameArg,
+		},
+		"top": {
+			Short: "show the CPU and memory usage of the replicas of a job, and of its tasks",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.TopJob(cmd.Context(), args))
+			},
+			InitFlags:         job.InitTopFlags,
+			ValidArgsFunction: cliutil.CompleteJobNameArg,
+		},
+		"exec": {
+			Short: "execute a command in a replica of a job, addressed by task and index",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.ExecJob(cmd.Context(), args, cmd.ArgsLenAtDash()))
+			},
+			InitFlags:         job.InitExecFlags,
+			ValidArgsFunction: cliutil.CompleteJobNameArg,
+		},
+		"diagnose": {
+			Short: "collect the job, its pods, podgroup, queue, events and the recent volcano logs into a tarball",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.DiagnoseJob(cmd.Context(), args))
+			},
+			InitFlags:         job.InitDiagnoseFlags,
+			ValidArgsFunction: cliutil.CompleteJobNameArg,
+		},
+		"delete": {
+			Short: "delete a job",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.DeleteJob(cmd.Context()))
+			},
+			InitFlags: job.InitDeleteFlags,
+		},
+	}
+
+	for command, config := range jobCommandMap {
+		cmd := &cobra.Command{
+			Use:               command,
+			Short:             config.Short,
+			Run:               config.RunFunction,
+			ValidArgsFunction: config.ValidArgsFunction,
+		}
+		config.InitFlags(cmd)
+		completions := map[string]cliutil.CompletionFunc{
+			"namespace": cliutil.CompleteNamespaces,
+			"queue":     cliutil.CompleteQueueNames,
+		}
+		if !config.NewJob {
+			completions["name"] = cliutil.CompleteJobNames
+		}
+		cliutil.RegisterFlagCompletions(cmd, completions)
+		jobCmd.AddCommand(cmd)
+	}
+
+	return jobCmd
+}
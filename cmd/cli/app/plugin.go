@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/pkg/cli/plugin"
+	cliutil "volcano.sh/volcano/pkg/cli/util"
+)
+
+func buildPluginCmd() *cobra.Command {
+	pluginCmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "vcctl plugins, the executables named " + plugin.Prefix + "<name> in the PATH, run as vcctl <name>",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "list the vcctl plugins found in the PATH",
+		Run: func(cmd *cobra.Command, args []string) {
+			plugin.PrintPlugins(plugin.List(cmd.Root(), os.Getenv("PATH")), os.Stdout)
+		},
+	}
+	pluginCmd.AddCommand(listCmd)
+
+	return pluginCmd
+}
+
+// runPlugin runs the plugin named by the arguments of vcctl, if they do not name a builtin command,
+// and exits with the exit code of the plugin.
+func runPlugin(rootCmd *cobra.Command, args []string) {
+	found, err := plugin.HandlePluginCommand(rootCmd, args)
+	if !found {
+		return
+	}
+	if err != nil {
+		var exitErr *cliutil.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+		fmt.Fprintf(os.Stderr, "Failed to run plugin: %v\n", err)
+		os.Exit(cliutil.ExitCodeError)
+	}
+	os.Exit(0)
+}
@@ -14,13 +14,14 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package main
+package app
 
 import (
 	"github.com/spf13/cobra"
 
 	"volcano.sh/volcano/cmd/cli/util"
 	"volcano.sh/volcano/pkg/cli/pod"
+	cliutil "volcano.sh/volcano/pkg/cli/util"
 )
 
 func buildPodCmd() *cobra.Command {
@@ -49,6 +50,11 @@ func buildPodCmd() *cobra.Command {
 			Run:   config.RunFunction,
 		}
 		config.InitFlags(cmd)
+		cliutil.RegisterFlagCompletions(cmd, map[string]cliutil.CompletionFunc{
+			"namespace": cliutil.CompleteNamespaces,
+			"queue":     cliutil.CompleteQueueNames,
+			"job":       cliutil.CompleteJobNames,
+		})
 		podCmd.AddCommand(cmd)
 	}
 	return podCmd
@@ -14,13 +14,14 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package main
+package app
 
 import (
 	"github.com/spf13/cobra"
 
 	"volcano.sh/volcano/cmd/cli/util"
 	"volcano.sh/volcano/pkg/cli/queue"
+	cliutil "volcano.sh/volcano/pkg/cli/util"
 )
 
 func buildQueueCmd() *cobra.Command {
@@ -51,6 +52,14 @@ func buildQueueCmd() *cobra.Command {
 			},
 			InitFlags: queue.InitDeleteFlags,
 		},
+		{
+			Use:   "update",
+			Short: "update the weight, capability, deserved, guarantee or reclaimable of a queue",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, queue.UpdateQueue(cmd.Context()))
+			},
+			InitFlags: queue.InitUpdateFlags,
+		},
 		{
 			Use:   "operate",
 			Short: "operate queue",
@@ -84,6 +93,11 @@ func buildQueueCmd() *cobra.Command {
 			Run:   command.RunFunction,
 		}
 		command.InitFlags(cmd)
+		completions := map[string]cliutil.CompletionFunc{"target-queue": cliutil.CompleteQueueNames}
+		if command.Use != "create" {
+			completions["name"] = cliutil.CompleteQueueNames
+		}
+		cliutil.RegisterFlagCompletions(cmd, completions)
 		queueCmd.AddCommand(cmd)
 	}
 
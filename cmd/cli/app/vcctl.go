@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/component-base/cli"
+
+	cliutil "volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/version"
+)
+
+// NewVcctlCommand creates the vcctl command tree under a root command of the given name, so that vcctl
+// and the kubectl-vc plugin share it.
+func NewVcctlCommand(use string) *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use: use,
+	}
+	cliutil.InitGlobalFlags(rootCmd)
+
+	rootCmd.AddCommand(buildJobCmd())
+	rootCmd.AddCommand(buildQueueCmd())
+	rootCmd.AddCommand(buildJobTemplateCmd())
+	rootCmd.AddCommand(buildJobFlowCmd())
+	rootCmd.AddCommand(buildPodCmd())
+	rootCmd.AddCommand(buildDashboardCmd())
+	rootCmd.AddCommand(buildPluginCmd())
+	rootCmd.AddCommand(versionCommand(use))
+
+	return rootCmd
+}
+
+// Run runs the command tree with the arguments and returns the exit code. The arguments which name
+// no builtin command run the vcctl-<name> plugin of the PATH, if any.
+func Run(rootCmd *cobra.Command, args []string) int {
+	runPlugin(rootCmd, args)
+
+	rootCmd.SetArgs(args)
+	return cli.Run(rootCmd)
+}
+
+func versionCommand(use string) *cobra.Command {
+	var command = &cobra.Command{
+		Use:     "version",
+		Short:   "Print the version information",
+		Long:    "Print the version information",
+		Example: use + " version",
+		Run: func(cmd *cobra.Command, args []string) {
+			version.PrintVersionAndExit()
+		},
+	}
+	return command
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestNewVcctlCommand(t *testing.T) {
+	for _, use := range []string{"vcctl", "kubectl-vc"} {
+		t.Run(use, func(t *testing.T) {
+			rootCmd := NewVcctlCommand(use)
+			if rootCmd.PersistentFlags().Lookup("context") == nil {
+				t.Errorf("expected the global context flag")
+			}
+
+			for _, args := range [][]string{
+				{"job", "list"},
+				{"queue", "get"},
+				{"pod", "list"},
+				{"dashboard"},
+				{"plugin", "list"},
+			} {
+				cmd, _, err := rootCmd.Find(args)
+				if err != nil {
+					t.Fatalf("expected command %v, got error %v", args, err)
+				}
+				if expected := use + " " + strings.Join(args, " "); cmd.CommandPath() != expected {
+					t.Errorf("expected command path %q, got %q", expected, cmd.CommandPath())
+				}
+			}
+		})
+	}
+}
+
+// TestCommandTree builds the whole command tree and checks that no command defines a flag or a shorthand twice,
+// which pflag panics on when the flags of the command are merged.
+func TestCommandTree(t *testing.T) {
+	for _, use := range []string{"vcctl", "kubectl-vc"} {
+		t.Run(use, func(t *testing.T) {
+			var rootCmd *cobra.Command
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("building the command tree panicked: %v", r)
+					}
+				}()
+				rootCmd = NewVcctlCommand(use)
+			}()
+
+			var walk func(cmd *cobra.Command)
+			walk = func(cmd *cobra.Command) {
+				func() {
+					defer func() {
+						if r := recover(); r != nil {
+							t.Errorf("merging the flags of %q panicked: %v", cmd.CommandPath(), r)
+						}
+					}()
+					// merge the persistent flags of the parents into the flags of the command
+					cmd.InheritedFlags()
+					shorthands := map[string]string{}
+					cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+						if flag.Shorthand == "" {
+							return
+						}
+						if other, found := shorthands[flag.Shorthand]; found {
+							t.Errorf("%q: shorthand %q is used by both %q and %q", cmd.CommandPath(), flag.Shorthand, other, flag.Name)
+						}
+						shorthands[flag.Shorthand] = flag.Name
+					})
+				}()
+				for _, sub := range cmd.Commands() {
+					walk(sub)
+				}
+			}
+			walk(rootCmd)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-vc is the kubectl plugin of volcano: installed in the PATH, it runs the vcctl commands as
+// `kubectl vc`, e.g. `kubectl vc job list`.
+package main
+
+import (
+	"os"
+
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+
+	"volcano.sh/volcano/cmd/cli/app"
+)
+
+func main() {
+	os.Exit(app.Run(app.NewVcctlCommand("kubectl-vc"), os.Args[1:]))
+}
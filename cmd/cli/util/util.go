@@ -17,15 +17,24 @@ limitations under the License.
 package util
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"volcano.sh/volcano/pkg/cli/util"
 )
 
 // CheckError prints the error of commands.
 func CheckError(cmd *cobra.Command, err error) {
 	if err != nil {
+		var exitErr *util.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+
 		msg := "Failed to"
 
 		// Ignore the root command.
@@ -34,6 +43,9 @@ func CheckError(cmd *cobra.Command, err error) {
 		}
 
 		fmt.Printf("%s: %v\n", msg, err)
-		os.Exit(-1)
+		if apierrors.IsNotFound(err) {
+			os.Exit(util.ExitCodeNotFound)
+		}
+		os.Exit(util.ExitCodeError)
 	}
 }
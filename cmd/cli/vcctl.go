@@ -18,43 +18,11 @@ package main
 import (
 	"os"
 
-	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
-	"k8s.io/component-base/cli"
 
-	"volcano.sh/volcano/pkg/version"
+	"volcano.sh/volcano/cmd/cli/app"
 )
 
 func main() {
-	rootCmd := cobra.Command{
-		Use: "vcctl",
-	}
-
-	// tell Cobra not to provide the default completion command
-	rootCmd.CompletionOptions.DisableDefaultCmd = true
-
-	rootCmd.AddCommand(buildJobCmd())
-	rootCmd.AddCommand(buildQueueCmd())
-	rootCmd.AddCommand(buildJobTemplateCmd())
-	rootCmd.AddCommand(buildJobFlowCmd())
-	rootCmd.AddCommand(buildPodCmd())
-	rootCmd.AddCommand(versionCommand())
-
-	code := cli.Run(&rootCmd)
-	os.Exit(code)
-}
-
-var versionExample = `vcctl version`
-
-func versionCommand() *cobra.Command {
-	var command = &cobra.Command{
-		Use:     "version",
-		Short:   "Print the version information",
-		Long:    "Print the version information",
-		Example: versionExample,
-		Run: func(cmd *cobra.Command, args []string) {
-			version.PrintVersionAndExit()
-		},
-	}
-	return command
+	os.Exit(app.Run(app.NewVcctlCommand("vcctl"), os.Args[1:]))
 }
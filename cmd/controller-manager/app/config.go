@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/component-base/logs"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/cmd/controller-manager/app/options"
+	"volcano.sh/volcano/pkg/controllers/framework"
+	"volcano.sh/volcano/pkg/filewatcher"
+	"volcano.sh/volcano/pkg/util/logging"
+)
+
+// configReloader reloads the settings of the config file of the controller manager which are changed without
+// a restart: the reloadable config of the controllers and the log verbosity, overall and by controller.
+type configReloader struct {
+	opt   *options.ServerOption
+	store *framework.ReloadableConfigStore
+	// flagVerbosity is the verbosity set by the -v flag, which is restored when the file no longer sets it;
+	// empty if the flag is not registered.
+	flagVerbosity string
+	// setVerbosity sets the verbosity of the logs.
+	setVerbosity func(string) (string, error)
+}
+
+func newConfigReloader(opt *options.ServerOption, store *framework.ReloadableConfigStore) *configReloader {
+	r := &configReloader{opt: opt, store: store, setVerbosity: logs.GlogSetter}
+	if v := flag.Lookup("v"); v != nil {
+		r.flagVerbosity = v.Value.String()
+	}
+	return r
+}
+
+// reload reads the config file again and applies its reloadable settings; an invalid file is ignored, so that
+// the controllers keep the settings they have.
+func (r *configReloader) reload() error {
+	c, err := options.LoadConfiguration(r.opt.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	verbosity := r.flagVerbosity
+	if c.Logging != nil && c.Logging.Verbosity != nil {
+		verbosity = strconv.Itoa(int(*c.Logging.Verbosity))
+	}
+	if verbosity != "" {
+		if _, err := r.setVerbosity(verbosity); err != nil {
+			return fmt.Errorf("failed to set the log verbosity to %s: %v", verbosity, err)
+		}
+	}
+
+	var components map[string]int32
+	if c.Logging != nil {
+		components = c.Logging.Components
+	}
+	logging.SetComponentVerbosity(components)
+
+	config := r.opt.ReloadableConfigOf(c)
+	if config != r.store.Get() {
+		klog.Infof("Reloaded config %s: %+v", r.opt.ConfigFile, config)
+	}
+	r.store.Set(config)
+	return nil
+}
+
+// watch reloads the config file on its changes until the context is done. The directory of the file is watched,
+// so that the file of a ConfigMap volume, which is replaced rather than written, is reloaded too.
+func (r *configReloader) watch(ctx context.Context) {
+	dirPath := filepath.Dir(r.opt.ConfigFile)
+	fileWatcher, err := filewatcher.NewFileWatcher(dirPath)
+	if err != nil {
+		klog.Errorf("Failed to create filewatcher for %s, the config is not reloaded: %v", r.opt.ConfigFile, err)
+		return
+	}
+	defer fileWatcher.Close()
+
+	eventCh := fileWatcher.Events()
+	errCh := fileWatcher.Errors()
+	for {
+		select {
+		case event, ok := <-eventCh:
+			if !ok {
+				return
+			}
+			klog.V(4).Infof("watch %s event: %v", dirPath, event)
+			if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create {
+				if err := r.reload(); err != nil {
+					klog.Errorf("Failed to reload config, the current settings are kept: %v", err)
+				}
+			}
+		case err, ok := <-errCh:
+			if !ok {
+				return
+			}
+			klog.Infof("watch %s error: %v", r.opt.ConfigFile, err)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
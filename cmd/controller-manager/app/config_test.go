@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"volcano.sh/volcano/cmd/controller-manager/app/options"
+	"volcano.sh/volcano/pkg/controllers/framework"
+	"volcano.sh/volcano/pkg/util/logging"
+)
+
+func TestConfigReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	opt := &options.ServerOption{ConfigFile: path}
+	store := framework.NewReloadableConfigStore(framework.ReloadableConfig{})
+	var verbosity string
+	r := &configReloader{opt: opt, store: store, flagVerbosity: "2", setVerbosity: func(v string) (string, error) {
+		verbosity = v
+		return v, nil
+	}}
+
+	testCases := []struct {
+		name              string
+		content           string
+		expectErr         bool
+		expectConfig      framework.ReloadableConfig
+		expectedVerbosity string
+		// expectedJobControllerV6 is whether the V(6) logs of the job controller are written
+		expectedJobControllerV6 bool
+	}{
+		{
+			name: "reloadable settings",
+			content: `
+gc:
+  podGroupTTL: 2h
+  dryRun: true
+logging:
+  verbosity: 5
+  components:
+    job-controller: 6
+`,
+			expectConfig:            framework.ReloadableConfig{GCPodGroupTTL: 2 * time.Hour, GCDryRun: true},
+			expectedVerbosity:       "5",
+			expectedJobControllerV6: true,
+		},
+		{
+			name:      "invalid file keeps the settings",
+			content:   `gc: [`,
+			expectErr: true,
+			// the settings of the previous case
+			expectConfig:            framework.ReloadableConfig{GCPodGroupTTL: 2 * time.Hour, GCDryRun: true},
+			expectedVerbosity:       "5",
+			expectedJobControllerV6: true,
+		},
+		{
+			name:      "negative component verbosity keeps the settings",
+			content:   "logging:\n  components:\n    job-controller: -1\n",
+			expectErr: true,
+			// the settings of the first case
+			expectConfig:            framework.ReloadableConfig{GCPodGroupTTL: 2 * time.Hour, GCDryRun: true},
+			expectedVerbosity:       "5",
+			expectedJobControllerV6: true,
+		},
+		{
+			name: "removed settings are back to the flags",
+			content: `
+gc:
+  commandTTL: 30m
+`,
+			expectConfig:      framework.ReloadableConfig{GCCommandTTL: 30 * time.Minute},
+			expectedVerbosity: "2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write the config: %v", err)
+			}
+			err := r.reload()
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if got := store.Get(); got != tc.expectConfig {
+				t.Errorf("expected config %+v, got %+v", tc.expectConfig, got)
+			}
+			if verbosity != tc.expectedVerbosity {
+				t.Errorf("expected verbosity %s, got %s", tc.expectedVerbosity, verbosity)
+			}
+			if got := logging.Enabled("job-controller", 6); got != tc.expectedJobControllerV6 {
+				t.Errorf("expected V(6) logs of the job controller enabled: %v, got %v", tc.expectedJobControllerV6, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/cmd/controller-manager/app/options"
+	"volcano.sh/volcano/pkg/version"
+)
+
+// redactedValue replaces the secrets of the options served on /debug/config.
+const redactedValue = "REDACTED"
+
+// debugConfig is the runtime configuration of the controller manager served on /debug/config.
+type debugConfig struct {
+	Version      string                `json:"version"`
+	GitSHA       string                `json:"gitSHA"`
+	Options      *options.ServerOption `json:"options"`
+	FeatureGates map[string]bool       `json:"featureGates"`
+}
+
+// startDebugServer serves the pprof profiles and the runtime configuration on their own address,
+// apart from the metrics and the health check, so that they are only reachable when enabled.
+func startDebugServer(opt *options.ServerOption) {
+	go func() {
+		klog.Fatalf("Debug Http Server failed %s", http.ListenAndServe(opt.PprofBindAddress, newDebugMux(opt)))
+	}()
+}
+
+func newDebugMux(opt *options.ServerOption) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/config", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(newDebugConfig(opt)); err != nil {
+			klog.Errorf("Failed to write the debug config: %v", err)
+		}
+	})
+	return mux
+}
+
+// newDebugConfig returns the configuration of the options, without the certificates, the keys and the job
+// notification URLs, which often embed a token.
+func newDebugConfig(opt *options.ServerOption) *debugConfig {
+	redacted := *opt
+	redacted.CertData = nil
+	redacted.KeyData = nil
+	redacted.CaCertData = nil
+	if len(opt.JobNotificationURLs) > 0 {
+		redacted.JobNotificationURLs = make([]string, len(opt.JobNotificationURLs))
+		for i := range redacted.JobNotificationURLs {
+			redacted.JobNotificationURLs[i] = redactedValue
+		}
+	}
+
+	featureGates := map[string]bool{}
+	for feature := range utilfeature.DefaultMutableFeatureGate.GetAll() {
+		featureGates[string(feature)] = utilfeature.DefaultFeatureGate.Enabled(feature)
+	}
+
+	return &debugConfig{
+		Version:      version.Version,
+		GitSHA:       version.GitSHA,
+		Options:      &redacted,
+		FeatureGates: featureGates,
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"volcano.sh/volcano/cmd/controller-manager/app/options"
+)
+
+func TestDebugMux(t *testing.T) {
+	opt := &options.ServerOption{
+		WorkerThreads:       3,
+		CertData:            []byte("cert"),
+		KeyData:             []byte("key"),
+		CaCertData:          []byte("ca"),
+		JobNotificationURLs: []string{"https://hooks.example.com/services/token"},
+	}
+	server := httptest.NewServer(newDebugMux(opt))
+	defer server.Close()
+
+	testCases := []struct {
+		name         string
+		path         string
+		expectStatus int
+	}{
+		{name: "config", path: "/debug/config", expectStatus: http.StatusOK},
+		{name: "pprof index", path: "/debug/pprof/", expectStatus: http.StatusOK},
+		{name: "goroutine profile", path: "/debug/pprof/goroutine?debug=1", expectStatus: http.StatusOK},
+		{name: "metrics are not served", path: "/metrics", expectStatus: http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + tc.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.expectStatus {
+				t.Errorf("expected status %d, got %d", tc.expectStatus, resp.StatusCode)
+			}
+		})
+	}
+
+	resp, err := http.Get(server.URL + "/debug/config")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	config := debugConfig{}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Options.WorkerThreads != 3 {
+		t.Errorf("expected 3 worker threads, got %d", config.Options.WorkerThreads)
+	}
+	if config.Options.CertData != nil || config.Options.KeyData != nil || config.Options.CaCertData != nil {
+		t.Errorf("expected the certificates and keys redacted, got %+v", config.Options)
+	}
+	if !reflect.DeepEqual(config.Options.JobNotificationURLs, []string{redactedValue}) {
+		t.Errorf("expected the job notification URLs redacted, got %v", config.Options.JobNotificationURLs)
+	}
+	if opt.KeyData == nil || opt.JobNotificationURLs[0] == redactedValue {
+		t.Errorf("expected the options unchanged")
+	}
+}
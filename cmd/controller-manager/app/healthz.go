@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/cmd/controller-manager/app/options"
+)
+
+// cacheSyncWaiter is a shared informer factory, whose informers are checked for their sync.
+type cacheSyncWaiter interface {
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+}
+
+// readiness is the readiness of the controller manager reported by /readyz: the leader is ready once the caches
+// of the informers of its controllers are synced, a follower is ready to take over, so that a rolling update does
+// not wait for the lease; /readyz/leader tells the leader apart.
+type readiness struct {
+	leading   atomic.Bool
+	factories []cacheSyncWaiter
+}
+
+// run wraps the run of the controllers, which is only called while leading.
+func (r *readiness) run(run func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		r.leading.Store(true)
+		defer r.leading.Store(false)
+		return run(ctx)
+	}
+}
+
+// checkInformerSync fails while the leader has informers not started or not synced.
+func (r *readiness) checkInformerSync(_ *http.Request) error {
+	if !r.leading.Load() {
+		return nil
+	}
+	stopCh := make(chan struct{})
+	// a closed stopCh checks whether the informers are synced now
+	close(stopCh)
+
+	var started int
+	var notSynced []string
+	for _, factory := range r.factories {
+		for informerType, synced := range factory.WaitForCacheSync(stopCh) {
+			started++
+			if !synced {
+				notSynced = append(notSynced, informerType.String())
+			}
+		}
+	}
+	if started == 0 {
+		return fmt.Errorf("informers not started yet")
+	}
+	if len(notSynced) > 0 {
+		return fmt.Errorf("%d informers not synced yet: %v", len(notSynced), notSynced)
+	}
+	return nil
+}
+
+// serveLeader responds whether the controller manager leads, with 503 for a follower.
+func (r *readiness) serveLeader(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if !r.leading.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, "follower")
+		return
+	}
+	fmt.Fprint(w, "leader")
+}
+
+// newHealthzMux returns the mux of /healthz, which checks the process is alive, and of /readyz.
+func newHealthzMux(r *readiness) *http.ServeMux {
+	mux := http.NewServeMux()
+	healthz.InstallHandler(mux)
+	healthz.InstallReadyzHandler(mux, healthz.NamedCheck("informer-sync", r.checkInformerSync))
+	mux.HandleFunc("/readyz/leader", r.serveLeader)
+	return mux
+}
+
+// startHealthz serves /healthz and /readyz on the health check address, over TLS with the certificates of the options
+// if they are set.
+func startHealthz(opt *options.ServerOption, r *readiness) error {
+	listener, err := net.Listen("tcp", opt.HealthzBindAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s for the health check: %v", opt.HealthzBindAddress, err)
+	}
+
+	server := &http.Server{
+		Handler:           newHealthzMux(r),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if len(opt.CaCertData) != 0 && len(opt.CertData) != 0 && len(opt.KeyData) != 0 {
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(opt.CaCertData); !ok {
+			return fmt.Errorf("failed to append the CA certificate of the health check")
+		}
+		cert, err := tls.X509KeyPair(opt.CertData, opt.KeyData)
+		if err != nil {
+			return fmt.Errorf("failed to load the certificate of the health check: %v", err)
+		}
+		server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      certPool,
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+
+	go func() {
+		var err error
+		if server.TLSConfig != nil {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		klog.Fatalf("Health check server failed: %v", err)
+	}()
+	return nil
+}
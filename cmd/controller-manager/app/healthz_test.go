@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+type fakeCacheSyncWaiter map[reflect.Type]bool
+
+func (f fakeCacheSyncWaiter) WaitForCacheSync(_ <-chan struct{}) map[reflect.Type]bool {
+	return f
+}
+
+func TestHealthzMux(t *testing.T) {
+	podType := reflect.TypeOf(&v1.Pod{})
+	testCases := []struct {
+		name              string
+		leading           bool
+		informers         fakeCacheSyncWaiter
+		expectReady       int
+		expectLeader      int
+		expectLeaderState string
+	}{
+		{
+			name:              "follower",
+			expectReady:       http.StatusOK,
+			expectLeader:      http.StatusServiceUnavailable,
+			expectLeaderState: "follower",
+		},
+		{
+			name:              "leader with informers not started",
+			leading:           true,
+			informers:         fakeCacheSyncWaiter{},
+			expectReady:       http.StatusInternalServerError,
+			expectLeader:      http.StatusOK,
+			expectLeaderState: "leader",
+		},
+		{
+			name:              "leader with informers not synced",
+			leading:           true,
+			informers:         fakeCacheSyncWaiter{podType: false},
+			expectReady:       http.StatusInternalServerError,
+			expectLeader:      http.StatusOK,
+			expectLeaderState: "leader",
+		},
+		{
+			name:              "leader with informers synced",
+			leading:           true,
+			informers:         fakeCacheSyncWaiter{podType: true},
+			expectReady:       http.StatusOK,
+			expectLeader:      http.StatusOK,
+			expectLeaderState: "leader",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ready := &readiness{factories: []cacheSyncWaiter{tc.informers, fakeCacheSyncWaiter{}}}
+			ready.leading.Store(tc.leading)
+			mux := newHealthzMux(ready)
+
+			get := func(path string) *httptest.ResponseRecorder {
+				recorder := httptest.NewRecorder()
+				mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+				return recorder
+			}
+			if code := get("/healthz").Code; code != http.StatusOK {
+				t.Errorf("expected /healthz status %d, got %d", http.StatusOK, code)
+			}
+			if code := get("/readyz").Code; code != tc.expectReady {
+				t.Errorf("expected /readyz status %d, got %d", tc.expectReady, code)
+			}
+			leader := get("/readyz/leader")
+			if leader.Code != tc.expectLeader || leader.Body.String() != tc.expectLeaderState {
+				t.Errorf("expected /readyz/leader %d %s, got %d %s", tc.expectLeader, tc.expectLeaderState, leader.Code, leader.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"volcano.sh/volcano/pkg/controllers/framework"
+	"volcano.sh/volcano/pkg/util/logging"
+)
+
+// Configuration is the content of the config file of the controller manager. The settings it sets supersede
+// the flags, the unset ones keep the values of the flags. The TTLs and the dry-run mode of the garbage
+// collector and the log verbosity are reloaded when the file changes, the other settings need a restart.
+// The default plugins of the jobs are not set here but in the admission config of the webhook manager.
+type Configuration struct {
+	// Controllers are the tuning of the controllers by name, as in the controller config file.
+	Controllers map[string]framework.ControllerConfig `json:"controllers,omitempty"`
+	// Jobs are the settings of the job controller.
+	Jobs *JobsConfiguration `json:"jobs,omitempty"`
+	// GC are the settings of the garbage collector.
+	GC *GCConfiguration `json:"gc,omitempty"`
+	// Logging are the settings of the logs.
+	Logging *LoggingConfiguration `json:"logging,omitempty"`
+}
+
+// JobsConfiguration are the settings of the job controller, as the flags of the same names.
+type JobsConfiguration struct {
+	MaxRequeueNum          *int             `json:"maxRequeueNum,omitempty"`
+	NotificationURLs       []string         `json:"notificationURLs,omitempty"`
+	NotificationTimeout    *metav1.Duration `json:"notificationTimeout,omitempty"`
+	PropagatedLabels       []string         `json:"propagatedLabels,omitempty"`
+	PropagatedAnnotations  []string         `json:"propagatedAnnotations,omitempty"`
+	EventAggregationPeriod *metav1.Duration `json:"eventAggregationPeriod,omitempty"`
+	AccountingPeriod       *metav1.Duration `json:"accountingPeriod,omitempty"`
+}
+
+// GCConfiguration are the settings of the garbage collector, as the --gc-* flags of the same names.
+type GCConfiguration struct {
+	ScanInterval                *metav1.Duration `json:"scanInterval,omitempty"`
+	PluginResourceSweepInterval *metav1.Duration `json:"pluginResourceSweepInterval,omitempty"`
+	DeleteQPS                   *float32         `json:"deleteQPS,omitempty"`
+	DeleteBurst                 *int             `json:"deleteBurst,omitempty"`
+	DeleteBatchSize             *int             `json:"deleteBatchSize,omitempty"`
+	// PodGroupTTL, CommandTTL and DryRun are reloaded when the file changes.
+	PodGroupTTL *metav1.Duration `json:"podGroupTTL,omitempty"`
+	CommandTTL  *metav1.Duration `json:"commandTTL,omitempty"`
+	DryRun      *bool            `json:"dryRun,omitempty"`
+}
+
+// LoggingConfiguration are the settings of the logs.
+type LoggingConfiguration struct {
+	// Verbosity is the verbosity of the logs, as the -v flag; it is reloaded when the file changes.
+	Verbosity *int32 `json:"verbosity,omitempty"`
+	// Components are the verbosity of the logs of the controllers by name, e.g. job-controller, which supersede
+	// Verbosity for them; they are reloaded when the file changes.
+	Components map[string]int32 `json:"components,omitempty"`
+}
+
+// LoadConfiguration reads and parses the config file.
+func LoadConfiguration(path string) (*Configuration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+	c := &Configuration{}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	if c.Logging != nil && c.Logging.Verbosity != nil && *c.Logging.Verbosity < 0 {
+		return nil, fmt.Errorf("log verbosity of config %s must not be negative, got %d", path, *c.Logging.Verbosity)
+	}
+	if c.Logging != nil {
+		if err := logging.ValidateComponentVerbosity(c.Logging.Components); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %v", path, err)
+		}
+	}
+	return c, nil
+}
+
+// loadConfigFile applies the config file, if any, on the flags.
+func (s *ServerOption) loadConfigFile() error {
+	s.flagReloadableConfig = s.ReloadableConfig()
+	if s.ConfigFile == "" {
+		return nil
+	}
+	c, err := LoadConfiguration(s.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	if c.Controllers != nil {
+		if err := s.setControllerConfigs(c.Controllers); err != nil {
+			return err
+		}
+	}
+	if jobs := c.Jobs; jobs != nil {
+		setIfNotNil(&s.MaxRequeueNum, jobs.MaxRequeueNum)
+		setDurationIfNotNil(&s.JobNotificationTimeout, jobs.NotificationTimeout)
+		setDurationIfNotNil(&s.JobEventAggregationPeriod, jobs.EventAggregationPeriod)
+		setDurationIfNotNil(&s.JobAccountingPeriod, jobs.AccountingPeriod)
+		if jobs.NotificationURLs != nil {
+			s.JobNotificationURLs = jobs.NotificationURLs
+		}
+		if jobs.PropagatedLabels != nil {
+			s.PropagatedJobLabels = jobs.PropagatedLabels
+		}
+		if jobs.PropagatedAnnotations != nil {
+			s.PropagatedJobAnnotations = jobs.PropagatedAnnotations
+		}
+	}
+	if gc := c.GC; gc != nil {
+		setDurationIfNotNil(&s.GCScanInterval, gc.ScanInterval)
+		setDurationIfNotNil(&s.GCPluginResourceSweepInterval, gc.PluginResourceSweepInterval)
+		setIfNotNil(&s.GCDeleteQPS, gc.DeleteQPS)
+		setIfNotNil(&s.GCDeleteBurst, gc.DeleteBurst)
+		setIfNotNil(&s.GCDeleteBatchSize, gc.DeleteBatchSize)
+	}
+	reloadable := s.ReloadableConfigOf(c)
+	s.GCPodGroupTTL = reloadable.GCPodGroupTTL
+	s.GCCommandTTL = reloadable.GCCommandTTL
+	s.GCDryRun = reloadable.GCDryRun
+	return nil
+}
+
+// ReloadableConfig returns the reloadable config of the controllers set by the options.
+func (s *ServerOption) ReloadableConfig() framework.ReloadableConfig {
+	return framework.ReloadableConfig{
+		GCPodGroupTTL: s.GCPodGroupTTL,
+		GCCommandTTL:  s.GCCommandTTL,
+		GCDryRun:      s.GCDryRun,
+	}
+}
+
+// ReloadableConfigOf returns the reloadable config of the controllers set by the config file, or else by the flags,
+// so that the settings removed from the file are back to the values of the flags.
+func (s *ServerOption) ReloadableConfigOf(c *Configuration) framework.ReloadableConfig {
+	config := s.flagReloadableConfig
+	if gc := c.GC; gc != nil {
+		setDurationIfNotNil(&config.GCPodGroupTTL, gc.PodGroupTTL)
+		setDurationIfNotNil(&config.GCCommandTTL, gc.CommandTTL)
+		setIfNotNil(&config.GCDryRun, gc.DryRun)
+	}
+	return config
+}
+
+func setIfNotNil[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func setDurationIfNotNil(dst *time.Duration, src *metav1.Duration) {
+	if src != nil {
+		*dst = src.Duration
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/controllers/framework"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	flags := func() *ServerOption {
+		return &ServerOption{
+			knownControllers:       []string{"gc-controller", "job-controller"},
+			MaxRequeueNum:          defaultMaxRequeueNum,
+			JobNotificationTimeout: defaultNotificationTimeout,
+			PropagatedJobLabels:    []string{"team"},
+			GCScanInterval:         defaultGCScanInterval,
+			GCPodGroupTTL:          defaultGCPodGroupTTL,
+			GCCommandTTL:           defaultGCCommandTTL,
+			GCDeleteBatchSize:      framework.DefaultGCDeleteBatchSize,
+		}
+	}
+	testCases := []struct {
+		name       string
+		content    string
+		expected   func(s *ServerOption)
+		reloadable framework.ReloadableConfig
+		expectErr  bool
+	}{
+		{
+			name:       "empty file keeps the flags",
+			content:    ``,
+			expected:   func(s *ServerOption) {},
+			reloadable: framework.ReloadableConfig{GCPodGroupTTL: defaultGCPodGroupTTL, GCCommandTTL: defaultGCCommandTTL},
+		},
+		{
+			name: "settings supersede the flags",
+			content: `
+controllers:
+  job:
+    workers: 8
+jobs:
+  maxRequeueNum: 5
+  propagatedLabels: [cost-center]
+gc:
+  scanInterval: 30s
+  deleteBatchSize: 50
+  podGroupTTL: 2h
+  dryRun: true
+logging:
+  verbosity: 4
+`,
+			expected: func(s *ServerOption) {
+				s.ControllerConfigs = map[string]framework.ControllerConfig{"job-controller": {Workers: 8}}
+				s.MaxRequeueNum = 5
+				s.PropagatedJobLabels = []string{"cost-center"}
+				s.GCScanInterval = 30 * time.Second
+				s.GCDeleteBatchSize = 50
+				s.GCPodGroupTTL = 2 * time.Hour
+				s.GCDryRun = true
+			},
+			reloadable: framework.ReloadableConfig{GCPodGroupTTL: 2 * time.Hour, GCCommandTTL: defaultGCCommandTTL, GCDryRun: true},
+		},
+		{
+			name: "unknown field",
+			content: `
+gc:
+  podGroupTtl: 2h
+`,
+			expectErr: true,
+		},
+		{
+			name: "unknown controller",
+			content: `
+controllers:
+  cron:
+    workers: 2
+`,
+			expectErr: true,
+		},
+		{
+			name: "negative verbosity",
+			content: `
+logging:
+  verbosity: -1
+`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write the config: %v", err)
+			}
+			s := flags()
+			s.ConfigFile = path
+			err := s.loadConfigFile()
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			expected := flags()
+			expected.ConfigFile = path
+			tc.expected(expected)
+			expected.flagReloadableConfig = s.flagReloadableConfig
+			assert.Equal(t, expected, s)
+			assert.Equal(t, tc.reloadable, s.ReloadableConfig())
+		})
+	}
+}
+
+func TestReloadableConfigOf(t *testing.T) {
+	s := &ServerOption{GCPodGroupTTL: time.Hour, GCCommandTTL: -time.Second}
+	if err := s.loadConfigFile(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ttl := metav1.Duration{Duration: 10 * time.Minute}
+	dryRun := true
+	c := &Configuration{GC: &GCConfiguration{PodGroupTTL: &ttl, DryRun: &dryRun}}
+	assert.Equal(t, framework.ReloadableConfig{GCPodGroupTTL: 10 * time.Minute, GCCommandTTL: -time.Second, GCDryRun: true},
+		s.ReloadableConfigOf(c))
+
+	// the settings removed from the file are back to the flags
+	assert.Equal(t, framework.ReloadableConfig{GCPodGroupTTL: time.Hour, GCCommandTTL: -time.Second},
+		s.ReloadableConfigOf(&Configuration{}))
+}
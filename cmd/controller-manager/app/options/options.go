@@ -19,15 +19,22 @@ package options
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/component-base/config"
 	componentbaseconfigvalidation "k8s.io/component-base/config/validation"
+	"sigs.k8s.io/yaml"
 
+	"volcano.sh/volcano/pkg/controllers/framework"
 	"volcano.sh/volcano/pkg/kube"
+	"volcano.sh/volcano/pkg/util/logging"
+	"volcano.sh/volcano/pkg/util/tracing"
 )
 
 const (
@@ -37,10 +44,20 @@ const (
 	defaultMaxRequeueNum       = 15
 	defaultSchedulerName       = "volcano"
 	defaultHealthzAddress      = ":11251"
+	defaultMetricsAddress      = ":8081"
+	defaultPprofAddress        = "127.0.0.1:8082"
 	defaultLockObjectNamespace = "volcano-system"
 	defaultPodGroupWorkers     = 5
 	defaultGCWorkers           = 1
 	defaultControllers         = "*"
+	controllerSuffix           = "-controller"
+	defaultNotificationTimeout = 5 * time.Second
+	defaultGCScanInterval      = time.Minute
+	defaultGCSweepInterval     = 10 * time.Minute
+	defaultGCPodGroupTTL       = -time.Second
+	defaultGCCommandTTL        = time.Hour
+	defaultAutoscalerSync      = 30 * time.Second
+	defaultShutdownDrain       = 20 * time.Second
 )
 
 // ServerOption is the main context object for the controllers.
@@ -70,6 +87,14 @@ type ServerOption struct {
 	// defaulting to 0.0.0.0:11251
 	HealthzBindAddress string
 	EnableHealthz      bool
+	// MetricsBindAddress is the IP address and port for the Prometheus metrics server to serve on,
+	// defaulting to 0.0.0.0:8081
+	MetricsBindAddress string
+	EnableMetrics      bool
+	// PprofBindAddress is the IP address and port for the pprof profiles and the runtime configuration
+	// to be served on, defaulting to 127.0.0.1:8082
+	PprofBindAddress string
+	EnablePprof      bool
 	// To determine whether inherit owner's annotations for pods when create podgroup
 	InheritOwnerAnnotations bool
 	// WorkerThreadsForPG is the number of threads syncing podgroup operations
@@ -85,6 +110,76 @@ type ServerOption struct {
 	// Case3: "-gc-controller,-job-controller,-jobflow-controller,-jobtemplate-controller,-pg-controller,-queue-controller"
 	// to disable specific controllers,
 	Controllers []string
+	// knownControllers are the names of the registered controllers, which the controllers option is checked against.
+	knownControllers []string
+
+	// JobNotificationURLs are the HTTP endpoints job state changes are posted to.
+	JobNotificationURLs []string
+	// JobNotificationTimeout is the timeout of posting a job notification.
+	JobNotificationTimeout time.Duration
+	// PropagatedJobLabels are the job labels stamped onto the pods and resources created for the job.
+	PropagatedJobLabels []string
+	// PropagatedJobAnnotations are the job annotations stamped onto the pods and resources created for the job.
+	PropagatedJobAnnotations []string
+	// JobEventAggregationPeriod is how often the warning events of the job pods are collapsed into
+	// per-task events on the job; the events are not aggregated if zero.
+	JobEventAggregationPeriod time.Duration
+	// JobAccountingPeriod is how often the resource-hours consumed by the finished pods are recorded
+	// on their jobs; the jobs are not accounted if zero.
+	JobAccountingPeriod time.Duration
+	// QueueProvisionConfig is the path of the template of the queues provisioned for the namespaces.
+	QueueProvisionConfig string
+
+	// GCScanInterval is how often the garbage collector checks the finished podgroups and the stale commands.
+	GCScanInterval time.Duration
+	// GCPluginResourceSweepInterval is how often the garbage collector checks the resources of the job plugins for orphans.
+	GCPluginResourceSweepInterval time.Duration
+	// GCPodGroupTTL is how long a finished podgroup is kept; a negative TTL disables the cleanup.
+	GCPodGroupTTL time.Duration
+	// GCCommandTTL is how long a command not handled by any controller is kept; a negative TTL disables the cleanup.
+	GCCommandTTL time.Duration
+	// GCDryRun makes the garbage collector only log the resources it would delete.
+	GCDryRun bool
+	// GCDeleteQPS and GCDeleteBurst limit the rate of the deletes of the garbage collector.
+	GCDeleteQPS   float32
+	GCDeleteBurst int
+	// GCDeleteBatchSize is the maximal number of resources the garbage collector deletes in a batch,
+	// taking the namespaces in turn.
+	GCDeleteBatchSize int
+	// AutoscalerPrometheusAddress is the address of the Prometheus queried for the metrics of the
+	// autoscaling policies of the tasks; tasks are not autoscaled if empty.
+	AutoscalerPrometheusAddress string
+	// AutoscalerMetricsConfig is the path of the Prometheus queries of the metrics the autoscaling
+	// policies of the tasks refer to by name.
+	AutoscalerMetricsConfig string
+	// AutoscalerSyncPeriod is how often the metrics of the autoscaled tasks are queried.
+	AutoscalerSyncPeriod time.Duration
+	// ShutdownDrainTimeout is how long the controllers are waited for to handle the requests left in their queues
+	// when the controller manager stops.
+	ShutdownDrainTimeout time.Duration
+	// ControllerConfigFile is the path of the file of the worker counts and the resync periods of the controllers.
+	ControllerConfigFile string
+	// ControllerConfigs are the worker counts and the resync periods of the controllers by name, loaded from
+	// ControllerConfigFile.
+	ControllerConfigs map[string]framework.ControllerConfig
+	// Namespaces are the namespaces whose resources the controllers manage, all of them if empty; the namespaces
+	// prefixed with '-' are excluded instead.
+	Namespaces []string
+	// Tracing configures the export of the spans of the jobs.
+	Tracing tracing.Options
+	// Logging configures the format of the logs.
+	Logging logging.Options
+	// ConfigFile is the path of the config file, whose settings supersede the flags; the reloadable ones are
+	// reloaded when the file changes.
+	ConfigFile string
+	// flagReloadableConfig is the reloadable config set by the flags, which the config file is applied on.
+	flagReloadableConfig framework.ReloadableConfig
+}
+
+// controllerConfigFile is the content of the controller config file.
+type controllerConfigFile struct {
+	// Controllers are the tuning of the controllers by name, whose "-controller" suffix may be left out.
+	Controllers map[string]framework.ControllerConfig `json:"controllers"`
 }
 
 type DecryptFunc func(c *ServerOption) error
@@ -96,6 +191,7 @@ func NewServerOption() *ServerOption {
 
 // AddFlags adds flags for a specific CMServer to the specified FlagSet.
 func (s *ServerOption) AddFlags(fs *pflag.FlagSet, knownControllers []string) {
+	s.knownControllers = knownControllers
 	fs.StringVar(&s.KubeClientOptions.Master, "master", s.KubeClientOptions.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig)")
 	fs.StringVar(&s.KubeClientOptions.KubeConfig, "kubeconfig", s.KubeClientOptions.KubeConfig, "Path to kubeconfig file with authorization and master location information.")
 	fs.StringVar(&s.CaCertFile, "ca-cert-file", s.CaCertFile, "File containing the x509 Certificate for HTTPS.")
@@ -114,11 +210,57 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet, knownControllers []string) {
 	fs.IntVar(&s.MaxRequeueNum, "max-requeue-num", defaultMaxRequeueNum, "The number of times a job, queue or command will be requeued before it is dropped out of the queue")
 	fs.StringVar(&s.HealthzBindAddress, "healthz-address", defaultHealthzAddress, "The address to listen on for the health check server.")
 	fs.BoolVar(&s.EnableHealthz, "enable-healthz", false, "Enable the health check; it is false by default")
+	fs.StringVar(&s.MetricsBindAddress, "metrics-address", defaultMetricsAddress, "The address to listen on for the /metrics endpoint of the Prometheus metrics.")
+	fs.BoolVar(&s.EnableMetrics, "enable-metrics", false, "Enable the Prometheus metrics of the jobs, the work queues and the API calls; it is false by default")
+	fs.StringVar(&s.PprofBindAddress, "pprof-address", defaultPprofAddress, "The address to listen on for the /debug/pprof profiles and the /debug/config runtime configuration.")
+	fs.BoolVar(&s.EnablePprof, "enable-pprof", false, "Enable the pprof profiles and the runtime configuration dump on the debug address; it is false by default")
 	fs.BoolVar(&s.InheritOwnerAnnotations, "inherit-owner-annotations", true, "Enable inherit owner annotations for pods when create podgroup; it is enabled by default")
 	fs.Uint32Var(&s.WorkerThreadsForPG, "worker-threads-for-podgroup", defaultPodGroupWorkers, "The number of threads syncing podgroup operations. The larger the number, the faster the podgroup processing, but requires more CPU load.")
 	fs.Uint32Var(&s.WorkerThreadsForGC, "worker-threads-for-gc", defaultGCWorkers, "The number of threads for recycling jobs. The larger the number, the faster the job recycling, but requires more CPU load.")
 	fs.StringSliceVar(&s.Controllers, "controllers", []string{defaultControllers}, fmt.Sprintf("Specify controller gates. Use '*' for all controllers, all knownController: %s ,and we can use "+
-		"'-' to disable controllers, e.g. \"-job-controller,-queue-controller\" to disable job and queue controllers.", knownControllers))
+		"'-' to disable controllers, e.g. \"-job-controller,-queue-controller\" to disable job and queue controllers; "+
+		"the '-controller' suffix may be left out, e.g. \"job,queue\" to only run the job and queue controllers.", knownControllers))
+	fs.StringSliceVar(&s.JobNotificationURLs, "job-notification-urls", nil, "The HTTP endpoints job state changes, retries and pod evictions are posted to as JSON; notifications are disabled if empty")
+	fs.DurationVar(&s.JobNotificationTimeout, "job-notification-timeout", defaultNotificationTimeout, "The timeout of posting a job notification to an endpoint")
+	fs.StringSliceVar(&s.PropagatedJobLabels, "propagate-job-labels", nil, "The job labels stamped onto the pods, PVCs, PodDisruptionBudgets and plugin resources created for the job; "+
+		"a key ending with '*' matches all the keys with that prefix")
+	fs.StringSliceVar(&s.PropagatedJobAnnotations, "propagate-job-annotations", nil, "The job annotations stamped onto the pods, PVCs, PodDisruptionBudgets and plugin resources created for the job; "+
+		"a key ending with '*' matches all the keys with that prefix")
+	fs.DurationVar(&s.JobEventAggregationPeriod, "job-event-aggregation-period", 0, "How often the warning events of the job pods are collapsed into per-task summary events on the job, "+
+		"e.g. '37/500 worker pods FailedScheduling: ...'; the events are not aggregated if zero")
+	fs.DurationVar(&s.JobAccountingPeriod, "job-accounting-period", 0, "How often the resource-hours consumed by the finished pods are recorded in the status of their jobs, "+
+		"which are exported as the volcano_job_resource_hours metric; the jobs are not accounted if zero")
+	fs.StringVar(&s.QueueProvisionConfig, "queue-provision-config", "", "The YAML file of the template of the queues provisioned for the namespaces, "+
+		"a queue is created for each selected namespace and deleted with it; queues are not provisioned if empty")
+	fs.DurationVar(&s.GCScanInterval, "gc-scan-interval", defaultGCScanInterval, "How often the garbage collector checks the finished podgroups and the stale commands")
+	fs.DurationVar(&s.GCPluginResourceSweepInterval, "gc-plugin-resource-sweep-interval", defaultGCSweepInterval, "How often the garbage collector checks "+
+		"the secrets, configmaps and services of the job plugins whose job no longer exists")
+	fs.DurationVar(&s.GCPodGroupTTL, "gc-podgroup-ttl", defaultGCPodGroupTTL, "How long a finished podgroup without a controller is kept before it is deleted; "+
+		"a negative value, the default, disables the cleanup")
+	fs.DurationVar(&s.GCCommandTTL, "gc-command-ttl", defaultGCCommandTTL, "How long a command not handled by any controller is kept before it is deleted; "+
+		"a negative value disables the cleanup")
+	fs.BoolVar(&s.GCDryRun, "gc-dry-run", false, "Only log the jobs, podgroups, commands and plugin resources the garbage collector would delete, without deleting them")
+	fs.Float32Var(&s.GCDeleteQPS, "gc-delete-qps", framework.DefaultGCDeleteQPS, "The maximal number of resources the garbage collector deletes per second")
+	fs.IntVar(&s.GCDeleteBurst, "gc-delete-burst", framework.DefaultGCDeleteBurst, "The maximal burst of the deletes of the garbage collector")
+	fs.IntVar(&s.GCDeleteBatchSize, "gc-delete-batch-size", framework.DefaultGCDeleteBatchSize, "The maximal number of resources the garbage collector deletes in a batch, "+
+		"taking the namespaces in turn; the resources of a batch are deleted in parallel by --worker-threads-for-gc threads")
+	fs.StringVar(&s.AutoscalerPrometheusAddress, "autoscaler-prometheus-address", "", "The address of the Prometheus queried for the metrics "+
+		"of the autoscaling policies of the job tasks, e.g. http://prometheus.monitoring:9090; tasks are not autoscaled if empty")
+	fs.StringVar(&s.AutoscalerMetricsConfig, "autoscaler-metrics-config", "", "The YAML file of the Prometheus queries of the metrics "+
+		"the autoscaling policies of the job tasks refer to by name, where $namespace, $job and $task are replaced; tasks are not autoscaled if empty")
+	fs.DurationVar(&s.AutoscalerSyncPeriod, "autoscaler-sync-period", defaultAutoscalerSync, "How often the metrics of the autoscaled job tasks are queried")
+	fs.DurationVar(&s.ShutdownDrainTimeout, "shutdown-drain-timeout", defaultShutdownDrain, "How long the controllers are waited for to handle the requests "+
+		"left in their queues and send their events on SIGTERM or SIGINT; it should be shorter than the termination grace period of the pod")
+	fs.StringVar(&s.ControllerConfigFile, "controller-config", "", "The YAML file of the number of workers and the resync period of the "+
+		"event handlers of the controllers by name, overriding the worker threads flags; the controllers keep their defaults if empty")
+	fs.StringSliceVar(&s.Namespaces, "namespaces", nil, "The namespaces whose resources the controllers manage, e.g. \"team-a,team-b\", "+
+		"or the namespaces they leave out, prefixed with '-', e.g. \"-kube-system,-team-c\"; all the namespaces are managed if empty. "+
+		"The informers only cache the namespace given if it is the only one")
+	s.Tracing.AddFlags(fs)
+	s.Logging.AddFlags(fs)
+	fs.StringVar(&s.ConfigFile, "config", "", "The YAML file of the configuration of the controller manager, whose settings supersede "+
+		"the flags and --controller-config; the TTLs and the dry-run mode of the garbage collector and the log verbosity are reloaded "+
+		"when the file changes")
 }
 
 // CheckOptionOrDie checks all options and returns all errors if they are invalid.
@@ -126,11 +268,45 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet, knownControllers []string) {
 func (s *ServerOption) CheckOptionOrDie() error {
 	var allErrors []error
 
+	// Load and check the controller config file
+	if err := s.loadControllerConfig(); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
+	// Load the config file, which supersedes the flags and the controller config file
+	if err := s.loadConfigFile(); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
 	// Check controllers option
 	if err := s.checkControllers(); err != nil {
 		allErrors = append(allErrors, err)
 	}
 
+	// Check garbage collection options
+	if err := s.checkGC(); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
+	if s.JobEventAggregationPeriod < 0 {
+		allErrors = append(allErrors, fmt.Errorf("job-event-aggregation-period must not be negative"))
+	}
+	if s.JobAccountingPeriod < 0 {
+		allErrors = append(allErrors, fmt.Errorf("job-accounting-period must not be negative"))
+	}
+
+	// Check namespaces option
+	if _, err := s.NamespaceScope(); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
+	if err := s.Tracing.Validate(); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := s.Logging.Validate(); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
 	// Check leader election flag when LeaderElection is enabled.
 	leaderElectionErr := componentbaseconfigvalidation.ValidateLeaderElectionConfiguration(
 		&s.LeaderElection, field.NewPath("leaderElection")).ToAggregate()
@@ -149,20 +325,109 @@ func (s *ServerOption) checkControllers() error {
 			if len(s.Controllers) > 1 {
 				return fmt.Errorf("wildcard '*' cannot be combined with other input")
 			}
+			continue
+		}
+		_, name := ParseControllerOption(c)
+		if len(s.knownControllers) > 0 && !slices.Contains(s.knownControllers, name) {
+			return fmt.Errorf("controllers option %s is not a known controller, known controllers: %v", c, s.knownControllers)
+		}
+		if existenceMap[name] {
+			return fmt.Errorf("controllers option %s cannot have both '-' and '+' prefixes", c)
+		}
+		existenceMap[name] = true
+	}
+	return nil
+}
+
+// ParseControllerOption splits an item of the controllers option into its '+' or '-' prefix, if any,
+// and the name of the controller, whose "-controller" suffix may be left out, e.g. "-gc" for "-gc-controller".
+func ParseControllerOption(c string) (prefix, name string) {
+	if strings.HasPrefix(c, "-") || strings.HasPrefix(c, "+") {
+		prefix, c = c[:1], c[1:]
+	}
+	if !strings.HasSuffix(c, controllerSuffix) {
+		c += controllerSuffix
+	}
+	return prefix, c
+}
+
+// loadControllerConfig loads the controller configs from the controller config file, by the full names of the controllers.
+func (s *ServerOption) loadControllerConfig() error {
+	if s.ControllerConfigFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.ControllerConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to read controller config %s: %v", s.ControllerConfigFile, err)
+	}
+	file := controllerConfigFile{}
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return fmt.Errorf("failed to parse controller config %s: %v", s.ControllerConfigFile, err)
+	}
+	return s.setControllerConfigs(file.Controllers)
+}
+
+// setControllerConfigs sets the controller configs by the full names of the controllers, checking them.
+func (s *ServerOption) setControllerConfigs(configs map[string]framework.ControllerConfig) error {
+	controllerConfigs := make(map[string]framework.ControllerConfig, len(configs))
+	for c, config := range configs {
+		_, name := ParseControllerOption(c)
+		if len(s.knownControllers) > 0 && !slices.Contains(s.knownControllers, name) {
+			return fmt.Errorf("controller config %s is not a known controller, known controllers: %v", c, s.knownControllers)
+		}
+		if _, found := controllerConfigs[name]; found {
+			return fmt.Errorf("controller config of %s is duplicated", name)
+		}
+		if config.ResyncPeriod.Duration < 0 {
+			return fmt.Errorf("resync period of controller %s must not be negative, got %v", name, config.ResyncPeriod.Duration)
+		}
+		if config.QPS < 0 || config.Burst < 0 {
+			return fmt.Errorf("qps and burst of controller %s must not be negative, got %v and %d", name, config.QPS, config.Burst)
+		}
+		controllerConfigs[name] = config
+	}
+	s.ControllerConfigs = controllerConfigs
+	return nil
+}
+
+// NamespaceScope returns the namespaces the controllers manage, checking the namespaces option.
+func (s *ServerOption) NamespaceScope() (framework.NamespaceScope, error) {
+	scope := framework.NamespaceScope{}
+	for _, ns := range s.Namespaces {
+		excluded := strings.HasPrefix(ns, "-")
+		name := strings.TrimPrefix(ns, "-")
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return scope, fmt.Errorf("namespaces option %s is not a valid namespace: %s", ns, strings.Join(errs, ", "))
+		}
+		if slices.Contains(scope.Included, name) || slices.Contains(scope.Excluded, name) {
+			return scope, fmt.Errorf("namespaces option %s is duplicated", name)
+		}
+		if excluded {
+			scope.Excluded = append(scope.Excluded, name)
 		} else {
-			if strings.HasPrefix(c, "-") || strings.HasPrefix(c, "+") {
-				if existenceMap[c[1:]] {
-					return fmt.Errorf("controllers option %s cannot have both '-' and '+' prefixes", c)
-				}
-				existenceMap[c[1:]] = true
-			} else {
-				if existenceMap[c] {
-					return fmt.Errorf("controllers option %s cannot have both '-' and '+' prefixes", c)
-				}
-				existenceMap[c] = true
-			}
+			scope.Included = append(scope.Included, name)
 		}
 	}
+	if len(scope.Included) > 0 && len(scope.Excluded) > 0 {
+		return scope, fmt.Errorf("namespaces option cannot both include and exclude namespaces")
+	}
+	return scope, nil
+}
+
+// checkGC checks the garbage collection options and returns error if they are invalid
+func (s *ServerOption) checkGC() error {
+	if s.GCScanInterval <= 0 {
+		return fmt.Errorf("gc-scan-interval must be positive, got %v", s.GCScanInterval)
+	}
+	if s.GCPluginResourceSweepInterval <= 0 {
+		return fmt.Errorf("gc-plugin-resource-sweep-interval must be positive, got %v", s.GCPluginResourceSweepInterval)
+	}
+	if s.GCDeleteQPS <= 0 || s.GCDeleteBurst <= 0 {
+		return fmt.Errorf("gc-delete-qps and gc-delete-burst must be positive, got %v and %d", s.GCDeleteQPS, s.GCDeleteBurst)
+	}
+	if s.GCDeleteBatchSize <= 0 {
+		return fmt.Errorf("gc-delete-batch-size must be positive, got %d", s.GCDeleteBatchSize)
+	}
 	return nil
 }
 
@@ -18,6 +18,8 @@ package options
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 	"time"
@@ -35,6 +37,7 @@ import (
 	"volcano.sh/volcano/pkg/controllers/framework"
 	_ "volcano.sh/volcano/pkg/controllers/garbagecollector"
 	_ "volcano.sh/volcano/pkg/controllers/job"
+	_ "volcano.sh/volcano/pkg/controllers/jobautoscaler"
 	_ "volcano.sh/volcano/pkg/controllers/jobflow"
 	_ "volcano.sh/volcano/pkg/controllers/jobtemplate"
 	_ "volcano.sh/volcano/pkg/controllers/podgroup"
@@ -42,6 +45,7 @@ import (
 	"volcano.sh/volcano/pkg/features"
 	"volcano.sh/volcano/pkg/kube"
 	commonutil "volcano.sh/volcano/pkg/util"
+	"volcano.sh/volcano/pkg/util/logging"
 )
 
 func TestAddFlags(t *testing.T) {
@@ -73,6 +77,13 @@ func TestAddFlags(t *testing.T) {
 		"--leader-elect-renew-deadline=20s",
 		"--leader-elect-retry-period=10s",
 		"--feature-gates=ResourceTopology=false",
+		"--job-notification-urls=http://tracker:8080/events",
+		"--propagate-job-labels=cost-center,example.com/*",
+		"--queue-provision-config=/etc/volcano/queue-provision.yaml",
+		"--gc-command-ttl=-1s",
+		"--gc-dry-run",
+		"--gc-delete-batch-size=500",
+		"--enable-metrics",
 	}
 	fs.Parse(args)
 
@@ -89,6 +100,9 @@ func TestAddFlags(t *testing.T) {
 		SchedulerNames:          []string{"volcano", "volcano2"},
 		MaxRequeueNum:           defaultMaxRequeueNum,
 		HealthzBindAddress:      ":11251",
+		MetricsBindAddress:      ":8081",
+		EnableMetrics:           true,
+		PprofBindAddress:        "127.0.0.1:8082",
 		InheritOwnerAnnotations: true,
 		LeaderElection: config.LeaderElectionConfiguration{
 			LeaderElect:       true,
@@ -99,10 +113,26 @@ func TestAddFlags(t *testing.T) {
 			ResourceNamespace: defaultLockObjectNamespace,
 			ResourceName:      "vc-controller-manager",
 		},
-		LockObjectNamespace: defaultLockObjectNamespace,
-		WorkerThreadsForPG:  5,
-		WorkerThreadsForGC:  1,
-		Controllers:         []string{"*"},
+		LockObjectNamespace:           defaultLockObjectNamespace,
+		WorkerThreadsForPG:            5,
+		WorkerThreadsForGC:            1,
+		Controllers:                   []string{"*"},
+		knownControllers:              knownControllers(),
+		JobNotificationURLs:           []string{"http://tracker:8080/events"},
+		JobNotificationTimeout:        defaultNotificationTimeout,
+		PropagatedJobLabels:           []string{"cost-center", "example.com/*"},
+		QueueProvisionConfig:          "/etc/volcano/queue-provision.yaml",
+		GCScanInterval:                defaultGCScanInterval,
+		GCPluginResourceSweepInterval: defaultGCSweepInterval,
+		GCPodGroupTTL:                 defaultGCPodGroupTTL,
+		GCCommandTTL:                  -time.Second,
+		GCDryRun:                      true,
+		GCDeleteQPS:                   framework.DefaultGCDeleteQPS,
+		GCDeleteBurst:                 framework.DefaultGCDeleteBurst,
+		GCDeleteBatchSize:             500,
+		AutoscalerSyncPeriod:          defaultAutoscalerSync,
+		ShutdownDrainTimeout:          defaultShutdownDrain,
+		Logging:                       logging.Options{Format: logging.TextFormat},
 	}
 	expectedFeatureGates := map[featuregate.Feature]bool{features.ResourceTopology: false}
 
@@ -154,6 +184,29 @@ func TestCheckControllers(t *testing.T) {
 			},
 			expectErr: fmt.Errorf("wildcard '*' cannot be combined with other input"),
 		},
+		{
+			name: "normal case: use short controller names",
+			serverOption: &ServerOption{
+				Controllers:      []string{"job", "queue", "-gc"},
+				knownControllers: []string{"gc-controller", "job-controller", "queue-controller"},
+			},
+			expectErr: nil,
+		},
+		{
+			name: "fail case: use short and full name of gc-controller",
+			serverOption: &ServerOption{
+				Controllers: []string{"-gc", "+gc-controller"},
+			},
+			expectErr: fmt.Errorf("controllers option %s cannot have both '-' and '+' prefixes", "+gc-controller"),
+		},
+		{
+			name: "fail case: use unknown controller",
+			serverOption: &ServerOption{
+				Controllers:      []string{"-gcc"},
+				knownControllers: []string{"gc-controller", "job-controller"},
+			},
+			expectErr: fmt.Errorf("controllers option -gcc is not a known controller, known controllers: [gc-controller job-controller]"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -167,3 +220,142 @@ func TestCheckControllers(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadControllerConfig(t *testing.T) {
+	known := []string{"gc-controller", "job-controller", "queue-controller"}
+	testCases := []struct {
+		name      string
+		content   string
+		expected  map[string]framework.ControllerConfig
+		expectErr bool
+	}{
+		{
+			name: "full and short names",
+			content: `
+controllers:
+  job-controller:
+    workers: 10
+    resyncPeriod: 10m
+  queue:
+    workers: 2
+`,
+			expected: map[string]framework.ControllerConfig{
+				"job-controller":   {Workers: 10, ResyncPeriod: metav1.Duration{Duration: 10 * time.Minute}},
+				"queue-controller": {Workers: 2},
+			},
+		},
+		{
+			name: "unknown controller",
+			content: `
+controllers:
+  cron:
+    workers: 2
+`,
+			expectErr: true,
+		},
+		{
+			name: "duplicated controller",
+			content: `
+controllers:
+  gc:
+    workers: 2
+  gc-controller:
+    workers: 3
+`,
+			expectErr: true,
+		},
+		{
+			name: "unknown field",
+			content: `
+controllers:
+  gc:
+    worker: 2
+`,
+			expectErr: true,
+		},
+		{
+			name: "client budget",
+			content: `
+controllers:
+  gc:
+    qps: 20
+    burst: 40
+`,
+			expected: map[string]framework.ControllerConfig{
+				"gc-controller": {QPS: 20, Burst: 40},
+			},
+		},
+		{
+			name: "negative qps",
+			content: `
+controllers:
+  gc:
+    qps: -1
+`,
+			expectErr: true,
+		},
+		{
+			name: "negative resync period",
+			content: `
+controllers:
+  gc:
+    resyncPeriod: -1m
+`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "controllers.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write the controller config: %v", err)
+			}
+			s := &ServerOption{ControllerConfigFile: path, knownControllers: known}
+			err := s.loadControllerConfig()
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if err == nil {
+				assert.Equal(t, tc.expected, s.ControllerConfigs)
+			}
+		})
+	}
+}
+
+func TestNamespaceScope(t *testing.T) {
+	testCases := []struct {
+		name       string
+		namespaces []string
+		expected   framework.NamespaceScope
+		expectErr  bool
+	}{
+		{name: "all namespaces", expected: framework.NamespaceScope{}},
+		{
+			name:       "included namespaces",
+			namespaces: []string{"team-a", "team-b"},
+			expected:   framework.NamespaceScope{Included: []string{"team-a", "team-b"}},
+		},
+		{
+			name:       "excluded namespaces",
+			namespaces: []string{"-kube-system", "-team-c"},
+			expected:   framework.NamespaceScope{Excluded: []string{"kube-system", "team-c"}},
+		},
+		{name: "included and excluded namespaces", namespaces: []string{"team-a", "-team-c"}, expectErr: true},
+		{name: "duplicated namespace", namespaces: []string{"team-a", "team-a"}, expectErr: true},
+		{name: "invalid namespace", namespaces: []string{"Team_A"}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &ServerOption{Namespaces: tc.namespaces}
+			scope, err := s.NamespaceScope()
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if err == nil {
+				assert.Equal(t, tc.expected, scope)
+			}
+		})
+	}
+}
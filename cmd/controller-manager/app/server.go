@@ -19,9 +19,17 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	v1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/informers"
 	kubeclientset "k8s.io/client-go/kubernetes"
@@ -31,15 +39,21 @@ import (
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/klog/v2"
 
-	"volcano.sh/apis/pkg/apis/helpers"
 	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
 	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
 	"volcano.sh/volcano/cmd/controller-manager/app/options"
 	"volcano.sh/volcano/pkg/controllers/framework"
 	"volcano.sh/volcano/pkg/kube"
 	"volcano.sh/volcano/pkg/signals"
+	"volcano.sh/volcano/pkg/util/tracing"
+
+	// Register rest client metrics
+	_ "k8s.io/component-base/metrics/prometheus/restclient"
+	// Register work queue metrics
+	_ "k8s.io/component-base/metrics/prometheus/workqueue"
 )
 
 // Run the controller.
@@ -49,18 +63,60 @@ func Run(opt *options.ServerOption) error {
 		return err
 	}
 
-	if opt.EnableHealthz {
-		if err := helpers.StartHealthz(opt.HealthzBindAddress, "volcano-controller", opt.CaCertData, opt.CertData, opt.KeyData); err != nil {
+	if opt.EnableMetrics {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", promHandler())
+			klog.Fatalf("Prometheus Http Server failed %s", http.ListenAndServe(opt.MetricsBindAddress, mux))
+		}()
+	}
+
+	if opt.EnablePprof {
+		startDebugServer(opt)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.TODO(), opt.Tracing, "volcano-controllers")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.TODO()); err != nil {
+			klog.Errorf("Failed to flush the spans: %v", err)
+		}
+	}()
+
+	ctx := signals.SetupSignalContext()
+
+	reloadableConfig := framework.NewReloadableConfigStore(opt.ReloadableConfig())
+	if opt.ConfigFile != "" {
+		reloader := newConfigReloader(opt, reloadableConfig)
+		if err := reloader.reload(); err != nil {
 			return err
 		}
+		go reloader.watch(ctx)
 	}
 
-	run := startControllers(config, opt)
+	if err := checkNamespaceScope(opt); err != nil {
+		return err
+	}
 
-	ctx := signals.SetupSignalContext()
+	ready := &readiness{}
+	run := startControllers(config, opt, reloadableConfig, ready)
+
+	if opt.EnableHealthz {
+		if err := startHealthz(opt, ready); err != nil {
+			return err
+		}
+	}
 
 	if !opt.LeaderElection.LeaderElect {
-		run(ctx)
+		if err := run(ctx); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			// a graceful shutdown, as in runLeaderElection
+			return nil
+		}
 		return fmt.Errorf("finished without leader elect")
 	}
 
@@ -84,7 +140,7 @@ func Run(opt *options.ServerOption) error {
 	if len(opt.LockObjectNamespace) > 0 {
 		opt.LeaderElection.ResourceNamespace = opt.LockObjectNamespace
 	}
-	rl, err := resourcelock.New(opt.LeaderElection.ResourceLock,
+	rl, err := resourcelock.New(migrateResourceLock(opt.LeaderElection.ResourceLock),
 		opt.LeaderElection.ResourceNamespace,
 		opt.LeaderElection.ResourceName,
 		leaderElectionClient.CoreV1(),
@@ -97,83 +153,275 @@ func Run(opt *options.ServerOption) error {
 		return fmt.Errorf("couldn't create resource lock: %v", err)
 	}
 
-	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+	return runLeaderElection(ctx, leaderelection.LeaderElectionConfig{
 		Lock:          rl,
 		LeaseDuration: opt.LeaderElection.LeaseDuration.Duration,
 		RenewDeadline: opt.LeaderElection.RenewDeadline.Duration,
 		RetryPeriod:   opt.LeaderElection.RetryPeriod.Duration,
-		Callbacks: leaderelection.LeaderCallbacks{
-			OnStartedLeading: run,
-			OnStoppedLeading: func() {
-				klog.Fatalf("leaderelection lost")
-			},
+		// the lease is released once the controllers stopped, see runLeaderElection
+		ReleaseOnCancel: true,
+	}, run)
+}
+
+// runLeaderElection runs the controllers while leading, until the context is done or the lease is lost.
+// The controllers are stopped before the lease is released, so that the next leader takes over right away
+// without running along with them; it returns nil on a graceful shutdown.
+func runLeaderElection(ctx context.Context, lec leaderelection.LeaderElectionConfig, run func(ctx context.Context) error) error {
+	leaderCtx, leaderCancel := context.WithCancel(context.Background())
+	defer leaderCancel()
+
+	var leading atomic.Bool
+	var runErr error
+	runDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-leaderCtx.Done():
+			return
+		}
+		if leading.Load() {
+			<-runDone
+		}
+		leaderCancel()
+	}()
+
+	lec.Callbacks = leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(leadingCtx context.Context) {
+			leading.Store(true)
+			defer close(runDone)
+			runCtx, cancel := context.WithCancel(leadingCtx)
+			defer cancel()
+			go func() {
+				select {
+				case <-ctx.Done():
+				case <-runCtx.Done():
+				}
+				cancel()
+			}()
+			runErr = run(runCtx)
+			leaderCancel()
 		},
-	})
+		OnStoppedLeading: func() {
+			klog.Infof("Stopped leading, the controllers are stopping")
+		},
+	}
+	leaderelection.RunOrDie(leaderCtx, lec)
+
+	if leading.Load() {
+		<-runDone
+	}
+	if runErr != nil {
+		return runErr
+	}
+	if ctx.Err() != nil {
+		klog.Infof("The controllers stopped and the lease is released")
+		return nil
+	}
 	return fmt.Errorf("lost lease")
 }
 
-func startControllers(config *rest.Config, opt *options.ServerOption) func(ctx context.Context) {
+// clusterScopedControllers are the controllers which reconcile the resources of all the namespaces together, e.g. the
+// status of the queues counts the podgroups of all the namespaces, so that they can not be restricted to some of them.
+var clusterScopedControllers = []string{"queue-controller"}
+
+// checkNamespaceScope checks that the controllers restricted to some namespaces can be.
+func checkNamespaceScope(opt *options.ServerOption) error {
+	if len(opt.Namespaces) == 0 {
+		return nil
+	}
+	for _, name := range clusterScopedControllers {
+		if isControllerEnabled(name, opt.Controllers) {
+			return fmt.Errorf("controller <%s> manages all the namespaces, it must be disabled with --namespaces, "+
+				"and run by a controller manager managing all the namespaces", name)
+		}
+	}
+	return nil
+}
+
+// migrateResourceLock returns the leases lock for the configmaps and endpoints locks removed from client-go,
+// so that the deployments still setting them keep working.
+func migrateResourceLock(lock string) string {
+	switch lock {
+	case "configmaps", "configmapsleases", "endpoints", "endpointsleases":
+		klog.Warningf("The %s resource lock is removed, the %s lock is used instead", lock, resourcelock.LeasesResourceLock)
+		return resourcelock.LeasesResourceLock
+	}
+	return lock
+}
+
+// startControllers returns the run of the controllers, whose leadership and informers are reported by the readiness.
+func startControllers(config *rest.Config, opt *options.ServerOption, reloadableConfig *framework.ReloadableConfigStore,
+	ready *readiness) func(ctx context.Context) error {
 	controllerOpt := &framework.ControllerOption{}
 
 	controllerOpt.SchedulerNames = opt.SchedulerNames
 	controllerOpt.WorkerNum = opt.WorkerThreads
 	controllerOpt.MaxRequeueNum = opt.MaxRequeueNum
 
-	// TODO: add user agent for different controllers
+	// the clients shared by the informers; each controller gets clients with its own user agent
 	controllerOpt.KubeClient = kubeclientset.NewForConfigOrDie(config)
 	controllerOpt.VolcanoClient = vcclientset.NewForConfigOrDie(config)
-	controllerOpt.SharedInformerFactory = informers.NewSharedInformerFactory(controllerOpt.KubeClient, 0)
-	controllerOpt.VCSharedInformerFactory = informerfactory.NewSharedInformerFactory(controllerOpt.VolcanoClient, 0)
+	// the informers check the resync of the event handlers as often as the shortest resync period of the controllers
+	resync := minResyncPeriod(opt.ControllerConfigs)
+	// the namespaces option is checked by CheckOptionOrDie
+	controllerOpt.Namespaces, _ = opt.NamespaceScope()
+	namespace := controllerOpt.Namespaces.InformerNamespace()
+	controllerOpt.SharedInformerFactory = informers.NewSharedInformerFactoryWithOptions(controllerOpt.KubeClient, resync,
+		informers.WithNamespace(namespace))
+	controllerOpt.VCSharedInformerFactory = informerfactory.NewSharedInformerFactoryWithOptions(controllerOpt.VolcanoClient, resync,
+		informerfactory.WithNamespace(namespace))
+	controllerOpt.Namespaces.RegisterInformers(controllerOpt.SharedInformerFactory, controllerOpt.VCSharedInformerFactory)
 	controllerOpt.InheritOwnerAnnotations = opt.InheritOwnerAnnotations
 	controllerOpt.WorkerThreadsForPG = opt.WorkerThreadsForPG
 	controllerOpt.WorkerThreadsForGC = opt.WorkerThreadsForGC
+	controllerOpt.JobNotificationURLs = opt.JobNotificationURLs
+	controllerOpt.JobNotificationTimeout = opt.JobNotificationTimeout
+	controllerOpt.PropagatedJobLabels = opt.PropagatedJobLabels
+	controllerOpt.PropagatedJobAnnotations = opt.PropagatedJobAnnotations
+	controllerOpt.JobEventAggregationPeriod = opt.JobEventAggregationPeriod
+	controllerOpt.JobAccountingPeriod = opt.JobAccountingPeriod
+	controllerOpt.QueueProvisionConfig = opt.QueueProvisionConfig
+	controllerOpt.GCScanInterval = opt.GCScanInterval
+	controllerOpt.GCPluginResourceSweepInterval = opt.GCPluginResourceSweepInterval
+	controllerOpt.GCDeleteQPS = opt.GCDeleteQPS
+	controllerOpt.GCDeleteBurst = opt.GCDeleteBurst
+	controllerOpt.GCDeleteBatchSize = opt.GCDeleteBatchSize
+	controllerOpt.AutoscalerPrometheusAddress = opt.AutoscalerPrometheusAddress
+	controllerOpt.AutoscalerMetricsConfig = opt.AutoscalerMetricsConfig
+	controllerOpt.AutoscalerSyncPeriod = opt.AutoscalerSyncPeriod
+	controllerOpt.ReloadableConfig = reloadableConfig
 	controllerOpt.Config = config
 
-	return func(ctx context.Context) {
-		framework.ForeachController(func(c framework.Controller) {
-			// if controller is not enabled, skip it
-			if !isControllerEnabled(c.Name(), opt.Controllers) {
-				klog.Infof("Controller <%s> is not enable", c.Name())
-				return
-			}
-			if err := c.Initialize(controllerOpt); err != nil {
-				klog.Errorf("Failed to initialize controller <%s>: %v", c.Name(), err)
-				return
-			}
-
-			go c.Run(ctx.Done())
-		})
+	ready.factories = []cacheSyncWaiter{controllerOpt.SharedInformerFactory, controllerOpt.VCSharedInformerFactory}
+	return ready.run(func(ctx context.Context) error {
+		return runControllers(ctx, controllerOpt, opt.Controllers, opt.ControllerConfigs, opt.ShutdownDrainTimeout)
+	})
+}
+
+// runControllers initializes the enabled controllers, runs them until the context is done and waits for them to stop,
+// at most for the drain timeout. A controller failing to initialize fails all of them, rather than the controller manager
+// running without it.
+func runControllers(ctx context.Context, controllerOpt *framework.ControllerOption, controllers []string,
+	configs map[string]framework.ControllerConfig, drainTimeout time.Duration) error {
+	var enabled []framework.Controller
+	var errs []error
+	framework.ForeachController(func(c framework.Controller) {
+		// if controller is not enabled, skip it
+		if !isControllerEnabled(c.Name(), controllers) {
+			klog.Infof("Controller <%s> is not enable", c.Name())
+			return
+		}
+		if err := c.Initialize(newControllerOption(controllerOpt, c.Name(), configs[c.Name()])); err != nil {
+			errs = append(errs, fmt.Errorf("failed to initialize controller <%s>: %v", c.Name(), err))
+			return
+		}
+		enabled = append(enabled, c)
+	})
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	// the informers of all the controllers are started at once, so that the readiness waits for all their caches
+	if controllerOpt.SharedInformerFactory != nil {
+		controllerOpt.SharedInformerFactory.Start(ctx.Done())
+	}
+	if controllerOpt.VCSharedInformerFactory != nil {
+		controllerOpt.VCSharedInformerFactory.Start(ctx.Done())
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range enabled {
+		wg.Add(1)
+		go func(c framework.Controller) {
+			defer wg.Done()
+			klog.Infof("Controller <%s> is starting", c.Name())
+			c.Run(ctx.Done())
+			klog.Infof("Controller <%s> is stopped", c.Name())
+		}(c)
+	}
+
+	<-ctx.Done()
+	klog.Infof("Stopping the controllers, waiting at most %v for them to drain their queues", drainTimeout)
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(drainTimeout):
+		klog.Warningf("The controllers did not stop within %v, the requests left are handled by the next leader", drainTimeout)
+	}
+	return nil
+}
 
-		<-ctx.Done()
+// newControllerOption returns the option of the controller, with its workers and resync period from its config, and
+// clients whose user agent is the name of the controller, so that the requests of the controllers can be told apart
+// in the audit logs and the API priority and fairness. The clients are rate limited apart from the other controllers,
+// with the QPS and burst of its config if set, so that a burst of a controller does not hold the others back.
+func newControllerOption(controllerOpt *framework.ControllerOption, name string, config framework.ControllerConfig) *framework.ControllerOption {
+	opt := *controllerOpt
+	opt.Workers = config.Workers
+	opt.ResyncPeriod = config.ResyncPeriod.Duration
+	if controllerOpt.Config == nil {
+		return &opt
+	}
+	// AddUserAgent sets the user agent of the config given, which is shared by the controllers
+	restConfig := rest.AddUserAgent(rest.CopyConfig(controllerOpt.Config), name)
+	if config.QPS > 0 {
+		restConfig.QPS = config.QPS
+	}
+	if config.Burst > 0 {
+		restConfig.Burst = config.Burst
 	}
+	opt.KubeClient = kubeclientset.NewForConfigOrDie(restConfig)
+	opt.VolcanoClient = vcclientset.NewForConfigOrDie(restConfig)
+	opt.Config = restConfig
+	return &opt
+}
+
+// minResyncPeriod returns the shortest resync period of the controllers, or zero if none resyncs.
+func minResyncPeriod(configs map[string]framework.ControllerConfig) time.Duration {
+	var resync time.Duration
+	for _, config := range configs {
+		if period := config.ResyncPeriod.Duration; period > 0 && (resync == 0 || period < resync) {
+			resync = period
+		}
+	}
+	return resync
+}
+
+// promHandler serves the metrics of the controllers, with the work queue and rest client metrics
+// registered in the legacy registry of the Kubernetes components.
+func promHandler() http.Handler {
+	return promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, legacyregistry.DefaultGatherer}, promhttp.HandlerOpts{}))
 }
 
 // isControllerEnabled check if a specified controller enabled or not.
 // If the input controllers starts with a "+name" or "name", it is considered as an explicit inclusion.
-// Otherwise, it is considered as an explicit exclusion.
+// Otherwise, it is considered as an explicit exclusion. The "-controller" suffix of the names may be left out.
+// The controllers not listed are enabled by '*', or when only exclusions are listed, e.g. "-gc".
 func isControllerEnabled(name string, controllers []string) bool {
 	hasStar := false
+	hasInclusion := false
 	// if no explicit inclusion or exclusion, enable all controllers by default
 	if len(controllers) == 0 {
 		return true
 	}
 	for _, ctrl := range controllers {
-		// if we get here, there was an explicit inclusion
-		if ctrl == name {
-			return true
-		}
-		// if we get here, there was an explicit inclusion
-		if ctrl == "+"+name {
-			return true
-		}
-		// if we get here, there was an explicit exclusion
-		if ctrl == "-"+name {
-			return false
-		}
 		if ctrl == "*" {
 			hasStar = true
+			continue
+		}
+		prefix, ctrlName := options.ParseControllerOption(ctrl)
+		if prefix != "-" {
+			hasInclusion = true
+		}
+		if ctrlName == name {
+			// if we get here, there was an explicit inclusion or exclusion
+			return prefix != "-"
 		}
 	}
 	// if we get here, there was no explicit inclusion or exclusion
-	return hasStar
+	return hasStar || !hasInclusion
 }
@@ -1,12 +1,24 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"sort"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"volcano.sh/volcano/cmd/controller-manager/app/options"
 	"volcano.sh/volcano/pkg/controllers/framework"
 	_ "volcano.sh/volcano/pkg/controllers/garbagecollector"
 	_ "volcano.sh/volcano/pkg/controllers/job"
+	_ "volcano.sh/volcano/pkg/controllers/jobautoscaler"
 	_ "volcano.sh/volcano/pkg/controllers/jobflow"
 	_ "volcano.sh/volcano/pkg/controllers/jobtemplate"
 	_ "volcano.sh/volcano/pkg/controllers/podgroup"
@@ -53,6 +65,24 @@ func TestIsControllerEnabled(t *testing.T) {
 			inputControllers:  []string{"+gc-controller", "+jobtemplate-controller", "+jobflow-controller"},
 			isEnable:          false,
 		},
+		{
+			name:              "job-controller should be enable, input short controller names",
+			gotControllerName: "job-controller",
+			inputControllers:  []string{"job", "queue"},
+			isEnable:          true,
+		},
+		{
+			name:              "gc-controller should be disable, input short negation",
+			gotControllerName: "gc-controller",
+			inputControllers:  []string{"-gc"},
+			isEnable:          false,
+		},
+		{
+			name:              "queue-controller should be enable, input only negations",
+			gotControllerName: "queue-controller",
+			inputControllers:  []string{"-gc", "-job-controller"},
+			isEnable:          true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -64,3 +94,256 @@ func TestIsControllerEnabled(t *testing.T) {
 		})
 	}
 }
+
+type fakeController struct {
+	name    string
+	initErr error
+	stuck   bool
+	running atomic.Bool
+	stopped atomic.Bool
+	opt     *framework.ControllerOption
+}
+
+func (c *fakeController) Name() string {
+	return c.name
+}
+
+func (c *fakeController) Initialize(opt *framework.ControllerOption) error {
+	c.opt = opt
+	return c.initErr
+}
+
+func (c *fakeController) Run(stopCh <-chan struct{}) {
+	c.running.Store(true)
+	<-stopCh
+	if c.stuck {
+		select {}
+	}
+	c.stopped.Store(true)
+}
+
+func TestRunControllers(t *testing.T) {
+	fake := &fakeController{name: "fake-controller"}
+	broken := &fakeController{name: "broken-controller", initErr: fmt.Errorf("no informer")}
+	for _, c := range []framework.Controller{fake, broken} {
+		if err := framework.RegisterController(c); err != nil {
+			t.Fatalf("failed to register controller %s: %v", c.Name(), err)
+		}
+	}
+	controllerOpt := &framework.ControllerOption{Config: &rest.Config{Host: "127.0.0.1", UserAgent: "vc-controller-manager"}}
+
+	if err := runControllers(context.TODO(), controllerOpt, []string{"fake", "broken"}, nil, time.Second); err == nil {
+		t.Errorf("expected the error of the broken controller")
+	}
+	if fake.running.Load() {
+		t.Errorf("expected no controller run when one failed to initialize")
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan error)
+	go func() {
+		done <- runControllers(ctx, controllerOpt, []string{"fake"}, map[string]framework.ControllerConfig{
+			"fake-controller": {Workers: 4, ResyncPeriod: metav1.Duration{Duration: time.Minute}},
+		}, time.Second)
+	}()
+	for i := 0; i < 100 && !fake.running.Load(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !fake.running.Load() {
+		t.Fatalf("expected the fake controller running")
+	}
+	if fake.opt.Config.UserAgent != "vc-controller-manager/fake-controller" {
+		t.Errorf("expected the user agent of the controller, got %s", fake.opt.Config.UserAgent)
+	}
+	if fake.opt.Workers != 4 || fake.opt.ResyncPeriod != time.Minute {
+		t.Errorf("expected the config of the controller, got %d workers and %v resync", fake.opt.Workers, fake.opt.ResyncPeriod)
+	}
+	if controllerOpt.Config.UserAgent != "vc-controller-manager" {
+		t.Errorf("expected the shared option unchanged, got %s", controllerOpt.Config.UserAgent)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !fake.stopped.Load() {
+		t.Errorf("expected the fake controller stopped")
+	}
+}
+
+func TestMigrateResourceLock(t *testing.T) {
+	testCases := []struct {
+		lock     string
+		expected string
+	}{
+		{lock: "leases", expected: resourcelock.LeasesResourceLock},
+		{lock: "configmaps", expected: resourcelock.LeasesResourceLock},
+		{lock: "configmapsleases", expected: resourcelock.LeasesResourceLock},
+		{lock: "endpointsleases", expected: resourcelock.LeasesResourceLock},
+		{lock: "unknown", expected: "unknown"},
+	}
+	for _, tc := range testCases {
+		if lock := migrateResourceLock(tc.lock); lock != tc.expected {
+			t.Errorf("expected lock %s for %s, got %s", tc.expected, tc.lock, lock)
+		}
+	}
+}
+
+func TestRunLeaderElection(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Namespace: "volcano-system", Name: "vc-controller-manager"},
+		Client:     client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: "test"},
+	}
+	holder := func() string {
+		lease, err := client.CoordinationV1().Leases("volcano-system").Get(context.TODO(), "vc-controller-manager", metav1.GetOptions{})
+		if err != nil || lease.Spec.HolderIdentity == nil {
+			return ""
+		}
+		return *lease.Spec.HolderIdentity
+	}
+
+	started := make(chan struct{})
+	var holderWhileStopping string
+	run := func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		holderWhileStopping = holder()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan error)
+	go func() {
+		done <- runLeaderElection(ctx, leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   15 * time.Second,
+			RenewDeadline:   10 * time.Second,
+			RetryPeriod:     2 * time.Second,
+			ReleaseOnCancel: true,
+		}, run)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the controllers started while leading")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected a graceful shutdown, got %v", err)
+	}
+	if holderWhileStopping != "test" {
+		t.Errorf("expected the lease held while the controllers stop, got holder %q", holderWhileStopping)
+	}
+	if h := holder(); h != "" {
+		t.Errorf("expected the lease released, got holder %q", h)
+	}
+}
+
+func TestRunControllersDrainTimeout(t *testing.T) {
+	stuck := &fakeController{name: "stuck-controller", stuck: true}
+	if err := framework.RegisterController(stuck); err != nil {
+		t.Fatalf("failed to register controller %s: %v", stuck.Name(), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	start := time.Now()
+	if err := runControllers(ctx, &framework.ControllerOption{}, []string{"stuck"}, nil, 100*time.Millisecond); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("expected to wait for the drain timeout, waited %v", elapsed)
+	}
+}
+
+func TestMinResyncPeriod(t *testing.T) {
+	testCases := []struct {
+		name     string
+		configs  map[string]framework.ControllerConfig
+		expected time.Duration
+	}{
+		{name: "no config", expected: 0},
+		{
+			name: "no resync",
+			configs: map[string]framework.ControllerConfig{
+				"job-controller": {Workers: 10},
+			},
+			expected: 0,
+		},
+		{
+			name: "shortest resync",
+			configs: map[string]framework.ControllerConfig{
+				"job-controller":   {ResyncPeriod: metav1.Duration{Duration: 10 * time.Minute}},
+				"queue-controller": {ResyncPeriod: metav1.Duration{Duration: 5 * time.Minute}},
+				"pg-controller":    {Workers: 2},
+			},
+			expected: 5 * time.Minute,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if resync := minResyncPeriod(tc.configs); resync != tc.expected {
+				t.Errorf("expected resync %v, got %v", tc.expected, resync)
+			}
+		})
+	}
+}
+
+func TestNewControllerOption(t *testing.T) {
+	base := &framework.ControllerOption{
+		Config: &rest.Config{Host: "https://127.0.0.1:6443", UserAgent: "vc-controller-manager", QPS: 50, Burst: 100},
+	}
+	testCases := []struct {
+		name        string
+		config      framework.ControllerConfig
+		expectQPS   float32
+		expectBurst int
+	}{
+		{name: "flags", expectQPS: 50, expectBurst: 100},
+		{name: "own budget", config: framework.ControllerConfig{QPS: 10, Burst: 20}, expectQPS: 10, expectBurst: 20},
+		{name: "own qps only", config: framework.ControllerConfig{QPS: 200}, expectQPS: 200, expectBurst: 100},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := newControllerOption(base, "gc-controller", tc.config)
+			if expected := rest.DefaultKubernetesUserAgent() + "/gc-controller"; opt.Config.UserAgent != expected {
+				t.Errorf("expected user agent %s, got %s", expected, opt.Config.UserAgent)
+			}
+			if base.Config.UserAgent != "vc-controller-manager" {
+				t.Errorf("expected the shared config to be left unchanged, got user agent %s", base.Config.UserAgent)
+			}
+			if opt.Config.QPS != tc.expectQPS || opt.Config.Burst != tc.expectBurst {
+				t.Errorf("expected qps %v and burst %d, got %v and %d", tc.expectQPS, tc.expectBurst, opt.Config.QPS, opt.Config.Burst)
+			}
+			if base.Config.QPS != 50 || base.Config.Burst != 100 {
+				t.Errorf("expected the shared config to be left unchanged, got qps %v and burst %d", base.Config.QPS, base.Config.Burst)
+			}
+		})
+	}
+}
+
+func TestCheckNamespaceScope(t *testing.T) {
+	testCases := []struct {
+		name        string
+		namespaces  []string
+		controllers []string
+		expectErr   bool
+	}{
+		{name: "all namespaces", controllers: []string{"*"}},
+		{name: "queue controller scoped", namespaces: []string{"team-a"}, controllers: []string{"*"}, expectErr: true},
+		{name: "queue controller disabled", namespaces: []string{"team-a"}, controllers: []string{"-queue"}},
+		{name: "selected controllers", namespaces: []string{"-team-c"}, controllers: []string{"job", "pg"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &options.ServerOption{Namespaces: tc.namespaces, Controllers: tc.controllers}
+			if err := checkNamespaceScope(opt); (err != nil) != tc.expectErr {
+				t.Errorf("expected error: %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"net/http"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/cmd/scheduler/app/options"
+	"volcano.sh/volcano/pkg/scheduler"
+	"volcano.sh/volcano/pkg/util/httpauth"
+)
+
+// cacheDumpPath is the path the snapshot of the cache is served on, which the users are granted
+// as a non-resource URL, e.g. by a ClusterRole with the `get` verb on it.
+const cacheDumpPath = "/debug/cache"
+
+// startCacheDumpServer serves the snapshot of the cache with TLS on its own address, to the users
+// the API server authenticates and authorizes.
+func startCacheDumpServer(opt *options.ServerOption, sched *scheduler.Scheduler) error {
+	authn, authz, err := httpauth.NewDelegatingAuth(opt.KubeClientOptions.KubeConfig)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(cacheDumpPath, httpauth.WithDelegatedAuth(authn, authz, sched.CacheDumper()))
+	go func() {
+		klog.Fatalf("Cache dump Https Server failed %s", http.ListenAndServeTLS(opt.CacheDumpAddress, opt.CertFile, opt.KeyFile, mux))
+	}()
+	return nil
+}
@@ -27,6 +27,8 @@ import (
 	componentbaseconfigvalidation "k8s.io/component-base/config/validation"
 
 	"volcano.sh/volcano/pkg/kube"
+	"volcano.sh/volcano/pkg/util/logging"
+	"volcano.sh/volcano/pkg/util/tracing"
 )
 
 const (
@@ -46,8 +48,17 @@ const (
 	defaultPercentageOfNodesToFind    = 0
 	defaultLockObjectNamespace        = "volcano-system"
 	defaultNodeWorkers                = 20
+
+	defaultUnschedulableReportThreshold = 5 * time.Minute
+
+	defaultDecisionLogMaxSize    = 100
+	defaultDecisionLogMaxBackups = 5
 )
 
+// DefaultParallelism is the number of workers the predicates and the scores of a task are evaluated with
+// when the parallelism is not configured.
+const DefaultParallelism = 16
+
 // ServerOption is the main context object for the controller manager.
 type ServerOption struct {
 	KubeClientOptions kube.ClientOptions
@@ -80,11 +91,34 @@ type ServerOption struct {
 	MinNodesToFind             int32
 	MinPercentageOfNodesToFind int32
 	PercentageOfNodesToFind    int32
+	// Parallelism is the number of workers the predicates and the scores of a task are evaluated with across the nodes.
+	Parallelism int32
 
 	NodeSelector      []string
 	CacheDumpFileDir  string
 	EnableCacheDumper bool
+	// CacheDumpAddress is the address /debug/cache serves the snapshot of the cache on, to the users
+	// allowed by RBAC; it is not served when empty.
+	CacheDumpAddress  string
 	NodeWorkerThreads uint32
+	// DecisionLogFile is the file the decisions of every scheduling session are appended to as JSON lines;
+	// the decision log is disabled when it is empty.
+	DecisionLogFile string
+	// DecisionLogMaxSize is the size in megabytes the decision log file is rotated at, keeping
+	// DecisionLogMaxBackups rotated files.
+	DecisionLogMaxSize    int
+	DecisionLogMaxBackups int
+	// UnschedulableReportPeriod is the period the report of the unschedulable podgroups is published at;
+	// the report is not published when it is 0.
+	UnschedulableReportPeriod time.Duration
+	// UnschedulableReportThreshold is how long a podgroup is unschedulable for before it is reported.
+	UnschedulableReportThreshold time.Duration
+	// UnschedulableReportNamespace is the namespace of the ConfigMap the report is published into.
+	UnschedulableReportNamespace string
+	// Tracing configures the export of the spans of the jobs.
+	Tracing tracing.Options
+	// Logging configures the format of the logs.
+	Logging logging.Options
 
 	// IgnoredCSIProvisioners contains a list of provisioners, and pod request pvc with these provisioners will
 	// not be counted in pod pvc resource request and node.Allocatable, because the spec.drivers of csinode resource
@@ -136,6 +170,9 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	// The percentage of nodes that would be scored in each scheduling cycle; if <= 0, an adpative percentage will be calcuated
 	fs.Int32Var(&s.PercentageOfNodesToFind, "percentage-nodes-to-find", defaultPercentageOfNodesToFind, "The percentage of nodes to find and score, if <=0 will be calcuated based on the cluster size")
 
+	// The number of workers evaluating the predicates and the scores of a task across the nodes
+	fs.Int32Var(&s.Parallelism, "parallelism", DefaultParallelism, "The number of workers the predicates and the scores of a task are evaluated with across the nodes; it must be positive")
+
 	fs.StringVar(&s.PluginsDir, "plugins-dir", defaultPluginsDir, "vc-scheduler will load custom plugins which are in this directory")
 	fs.BoolVar(&s.EnableCSIStorage, "csi-storage", false,
 		"Enable tracking of available storage capacity that CSI drivers provide; it is false by default")
@@ -144,12 +181,41 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.StringSliceVar(&s.NodeSelector, "node-selector", nil, "volcano only work with the labeled node, like: --node-selector=volcano.sh/role:train --node-selector=volcano.sh/role:serving")
 	fs.BoolVar(&s.EnableCacheDumper, "cache-dumper", true, "Enable the cache dumper, it's true by default")
 	fs.StringVar(&s.CacheDumpFileDir, "cache-dump-dir", "/tmp", "The target dir where the json file put at when dump cache info to json file")
+	fs.StringVar(&s.CacheDumpAddress, "cache-dump-address", "", "The address to serve the snapshot of the cache on at /debug/cache, to the users allowed to get that non-resource URL; "+
+		"it is served with --tls-cert-file and --tls-private-key-file, which are required, and disabled if empty")
 	fs.Uint32Var(&s.NodeWorkerThreads, "node-worker-threads", defaultNodeWorkers, "The number of threads syncing node operations.")
+	fs.StringVar(&s.DecisionLogFile, "decision-log-file", "", "The file the decisions of every scheduling session are appended to as JSON lines, e.g. the candidate nodes, predicate failures and scores of the tasks; it is disabled by default")
+	fs.IntVar(&s.DecisionLogMaxSize, "decision-log-max-size", defaultDecisionLogMaxSize, "The size in megabytes the decision log file is rotated at")
+	fs.IntVar(&s.DecisionLogMaxBackups, "decision-log-max-backups", defaultDecisionLogMaxBackups, "The number of rotated decision log files kept, all of them if 0")
+	fs.DurationVar(&s.UnschedulableReportPeriod, "unschedulable-report-period", 0, "The period the report of the podgroups unschedulable for longer than --unschedulable-report-threshold "+
+		"is published at into the ConfigMap volcano-unschedulable-podgroups; it is disabled if 0, which is the default")
+	fs.DurationVar(&s.UnschedulableReportThreshold, "unschedulable-report-threshold", defaultUnschedulableReportThreshold, "How long a podgroup is unschedulable for before it is reported")
+	fs.StringVar(&s.UnschedulableReportNamespace, "unschedulable-report-namespace", defaultLockObjectNamespace, "The namespace of the ConfigMap the report of the unschedulable podgroups is published into")
+	s.Tracing.AddFlags(fs)
+	s.Logging.AddFlags(fs)
 	fs.StringSliceVar(&s.IgnoredCSIProvisioners, "ignored-provisioners", nil, "The provisioners that will be ignored during pod pvc request computation and preemption.")
 }
 
 // CheckOptionOrDie check leader election flag when LeaderElection is enabled.
 func (s *ServerOption) CheckOptionOrDie() error {
+	if err := s.Tracing.Validate(); err != nil {
+		return err
+	}
+	if err := s.Logging.Validate(); err != nil {
+		return err
+	}
+	if s.Parallelism <= 0 {
+		return fmt.Errorf("--parallelism must be positive")
+	}
+	if s.CacheDumpAddress != "" && (s.CertFile == "" || s.KeyFile == "") {
+		return fmt.Errorf("--cache-dump-address requires --tls-cert-file and --tls-private-key-file")
+	}
+	if s.UnschedulableReportPeriod < 0 {
+		return fmt.Errorf("--unschedulable-report-period must not be negative")
+	}
+	if s.UnschedulableReportThreshold < 0 {
+		return fmt.Errorf("--unschedulable-report-threshold must not be negative")
+	}
 	return componentbaseconfigvalidation.ValidateLeaderElectionConfiguration(&s.LeaderElection, field.NewPath("leaderElection")).ToAggregate()
 }
 
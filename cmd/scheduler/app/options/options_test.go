@@ -33,6 +33,7 @@ import (
 	"volcano.sh/volcano/pkg/features"
 	"volcano.sh/volcano/pkg/kube"
 	commonutil "volcano.sh/volcano/pkg/util"
+	"volcano.sh/volcano/pkg/util/logging"
 )
 
 func TestAddFlags(t *testing.T) {
@@ -75,13 +76,17 @@ func TestAddFlags(t *testing.T) {
 			QPS:        defaultQPS,
 			Burst:      defaultBurst,
 		},
-		PluginsDir:                 defaultPluginsDir,
-		HealthzBindAddress:         ":11251",
-		MinNodesToFind:             defaultMinNodesToFind,
-		MinPercentageOfNodesToFind: defaultMinPercentageOfNodesToFind,
-		PercentageOfNodesToFind:    defaultPercentageOfNodesToFind,
-		NodeWorkerThreads:          defaultNodeWorkers,
-		CacheDumpFileDir:           "/tmp",
+		PluginsDir:                   defaultPluginsDir,
+		HealthzBindAddress:           ":11251",
+		MinNodesToFind:               defaultMinNodesToFind,
+		Parallelism:                  DefaultParallelism,
+		MinPercentageOfNodesToFind:   defaultMinPercentageOfNodesToFind,
+		PercentageOfNodesToFind:      defaultPercentageOfNodesToFind,
+		NodeWorkerThreads:            defaultNodeWorkers,
+		CacheDumpFileDir:             "/tmp",
+		UnschedulableReportThreshold: defaultUnschedulableReportThreshold,
+		UnschedulableReportNamespace: defaultLockObjectNamespace,
+		Logging:                      logging.Options{Format: logging.TextFormat},
 	}
 	expectedFeatureGates := map[featuregate.Feature]bool{
 		features.PodDisruptionBudgetsSupport: false,
@@ -30,6 +30,7 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/signals"
 	commonutil "volcano.sh/volcano/pkg/util"
+	"volcano.sh/volcano/pkg/util/tracing"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -68,6 +69,16 @@ func Run(opt *options.ServerOption) error {
 		}
 	}
 
+	shutdownTracing, err := tracing.Setup(context.TODO(), opt.Tracing, "volcano-scheduler")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.TODO()); err != nil {
+			klog.Errorf("Failed to flush the spans: %v", err)
+		}
+	}()
+
 	sched, err := scheduler.NewScheduler(config, opt)
 	if err != nil {
 		panic(err)
@@ -80,6 +91,12 @@ func Run(opt *options.ServerOption) error {
 		}()
 	}
 
+	if opt.CacheDumpAddress != "" {
+		if err := startCacheDumpServer(opt, sched); err != nil {
+			return err
+		}
+	}
+
 	if opt.EnableHealthz {
 		if err := helpers.StartHealthz(opt.HealthzBindAddress, "volcano-scheduler", opt.CaCertData, opt.CertData, opt.KeyData); err != nil {
 			return err
@@ -35,6 +35,7 @@ import (
 	"volcano.sh/volcano/cmd/scheduler/app"
 	"volcano.sh/volcano/cmd/scheduler/app/options"
 	commonutil "volcano.sh/volcano/pkg/util"
+	"volcano.sh/volcano/pkg/util/logging"
 	"volcano.sh/volcano/pkg/version"
 
 	// Import default actions/plugins.
@@ -74,6 +75,7 @@ func main() {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
+	logging.Setup(s.Logging)
 	if s.CaCertFile != "" && s.CertFile != "" && s.KeyFile != "" {
 		if err := s.ParseCAFiles(nil); err != nil {
 			klog.Fatalf("Failed to parse CA file: %v", err)
@@ -19,18 +19,29 @@ package options
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/spf13/pflag"
+	whv1 "k8s.io/api/admissionregistration/v1"
 
 	"volcano.sh/volcano/pkg/kube"
+	"volcano.sh/volcano/pkg/util/logging"
+	"volcano.sh/volcano/pkg/util/tracing"
 )
 
 const (
 	defaultSchedulerName    = "volcano"
 	defaultQPS              = 50.0
 	defaultBurst            = 100
-	defaultEnabledAdmission = "/jobs/mutate,/jobs/validate,/podgroups/mutate,/pods/validate,/pods/mutate,/queues/mutate,/queues/validate"
+	defaultEnabledAdmission = "/jobs/mutate,/jobs/validate,/jobflows/mutate,/jobflows/validate,/podgroups/mutate,/podgroups/validate,/pods/validate,/pods/mutate,/queues/mutate,/queues/validate"
 	defaultHealthzAddress   = ":11251"
+	defaultCertSecretName   = "volcano-admission-secret"
+	defaultCertValidity     = 365 * 24 * time.Hour
+	defaultCertRotateBefore = 30 * 24 * time.Hour
+	defaultCertCheckPeriod  = time.Hour
+
+	defaultControllerServiceAccount = "volcano-system/volcano-controllers"
 )
 
 // Config admission-controller server config.
@@ -56,6 +67,45 @@ type Config struct {
 	// HealthzBindAddress is the IP address and port for the health check server to serve on
 	// defaulting to :11251
 	HealthzBindAddress string
+
+	// SelfSignedCerts provisions the serving certificates in CertSecretName instead of reading
+	// them from files, and rotates them before they expire.
+	SelfSignedCerts  bool
+	CertSecretName   string
+	CertValidity     time.Duration
+	CertRotateBefore time.Duration
+	// CertCheckPeriod is the period of checking the certificates for rotation or, when read
+	// from files, for renewal, e.g. by cert-manager.
+	CertCheckPeriod time.Duration
+	// PatchCABundle patches the CA certificate into the webhook configurations, it can be
+	// disabled when the CA bundle is injected by others, e.g. the cert-manager CA injector.
+	PatchCABundle bool
+
+	// WebhookFailurePolicies overrides the failurePolicy of the webhooks by path, e.g.
+	// /pods/mutate=Ignore, the path "*" applies to all the webhooks.
+	WebhookFailurePolicies map[string]string
+	// WebhookTimeouts overrides the timeoutSeconds of the webhooks by path.
+	WebhookTimeouts map[string]string
+
+	// ControllerServiceAccounts are the service accounts of the Volcano controllers, as namespace/name;
+	// the ACLs of the queues do not apply to them.
+	ControllerServiceAccounts []string
+
+	// Tracing configures the export of the spans of the jobs.
+	Tracing tracing.Options
+	// Logging configures the format of the logs.
+	Logging logging.Options
+
+	// decryptFunc is the function ParseCAFiles decrypted the CA data with, applied again when
+	// the files are reloaded.
+	decryptFunc DecryptFunc
+}
+
+// WebhookPolicy is the failurePolicy and timeout set on the configuration of a webhook,
+// nil fields are left as deployed.
+type WebhookPolicy struct {
+	FailurePolicy  *whv1.FailurePolicyType
+	TimeoutSeconds *int32
 }
 
 type DecryptFunc func(c *Config) error
@@ -88,6 +138,23 @@ func (c *Config) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&c.ConfigPath, "admission-conf", "", "The configmap file of this webhook")
 	fs.BoolVar(&c.EnableHealthz, "enable-healthz", false, "Enable the health check; it is false by default")
 	fs.StringVar(&c.HealthzBindAddress, "healthz-address", defaultHealthzAddress, "The address to listen on for the health check server.")
+	fs.BoolVar(&c.SelfSignedCerts, "self-signed-certs", false, "Provision self-signed serving certificates in the secret --cert-secret-name and rotate them before expiry, "+
+		"instead of reading them from --tls-cert-file, --tls-private-key-file and --ca-cert-file")
+	fs.StringVar(&c.CertSecretName, "cert-secret-name", defaultCertSecretName, "The secret in --webhook-namespace holding the self-signed certificates")
+	fs.DurationVar(&c.CertValidity, "cert-validity", defaultCertValidity, "The validity of the self-signed certificates")
+	fs.DurationVar(&c.CertRotateBefore, "cert-rotate-before", defaultCertRotateBefore, "Rotate the self-signed certificates when they expire within this duration")
+	fs.DurationVar(&c.CertCheckPeriod, "cert-check-period", defaultCertCheckPeriod, "The period of checking the certificates for rotation, or reloading them from files")
+	fs.BoolVar(&c.PatchCABundle, "patch-ca-bundle", true, "Patch the CA certificate into the webhook configurations; "+
+		"disable it when the CA bundle is injected by others, e.g. the cert-manager CA injector")
+	fs.StringToStringVar(&c.WebhookFailurePolicies, "webhook-failure-policy", nil, "The failurePolicy, Fail or Ignore, of the webhooks by path, "+
+		"e.g. /pods/mutate=Ignore,*=Fail; the path * applies to the webhooks not listed")
+	fs.StringToStringVar(&c.WebhookTimeouts, "webhook-timeout", nil, "The timeoutSeconds, between 1 and 30, of the webhooks by path, "+
+		"e.g. /pods/mutate=5; the path * applies to the webhooks not listed")
+	fs.StringSliceVar(&c.ControllerServiceAccounts, "controller-service-accounts", []string{defaultControllerServiceAccount},
+		"The service accounts of the Volcano controllers, as namespace/name, which submit the podgroups, pods and moves of the jobs "+
+			"on behalf of their owners; the ACLs of the queues do not apply to them")
+	c.Tracing.AddFlags(fs)
+	c.Logging.AddFlags(fs)
 }
 
 // CheckPortOrDie check valid port range.
@@ -98,6 +165,20 @@ func (c *Config) CheckPortOrDie() error {
 	return nil
 }
 
+// CheckCertsOrDie checks the options of the self-signed certificates.
+func (c *Config) CheckCertsOrDie() error {
+	if !c.SelfSignedCerts {
+		return nil
+	}
+	if c.WebhookNamespace == "" || c.WebhookName == "" {
+		return fmt.Errorf("self-signed certificates require --webhook-namespace and --webhook-service-name")
+	}
+	if c.CertRotateBefore >= c.CertValidity {
+		return fmt.Errorf("--cert-rotate-before must be less than --cert-validity")
+	}
+	return nil
+}
+
 // readCAFiles read data from ca file path
 func (c *Config) readCAFiles() error {
 	var err error
@@ -121,6 +202,7 @@ func (c *Config) readCAFiles() error {
 
 // ParseCAFiles parse ca file by decryptFunc
 func (c *Config) ParseCAFiles(decryptFunc DecryptFunc) error {
+	c.decryptFunc = decryptFunc
 	if err := c.readCAFiles(); err != nil {
 		return err
 	}
@@ -132,3 +214,59 @@ func (c *Config) ParseCAFiles(decryptFunc DecryptFunc) error {
 
 	return nil
 }
+
+// ReloadCAFiles reads and decrypts the CA files again, the same way as ParseCAFiles did, and returns
+// their data without changing the config, e.g. to serve the certificates renewed by cert-manager.
+func (c *Config) ReloadCAFiles() (caCert, cert, key []byte, err error) {
+	reloaded := *c
+	if err := reloaded.ParseCAFiles(c.decryptFunc); err != nil {
+		return nil, nil, nil, err
+	}
+	return reloaded.CaCertData, reloaded.CertData, reloaded.KeyData, nil
+}
+
+// ParseWebhookPolicies returns the failurePolicy and timeout of the webhooks by path.
+func (c *Config) ParseWebhookPolicies() (map[string]WebhookPolicy, error) {
+	policies := map[string]WebhookPolicy{}
+	for path, value := range c.WebhookFailurePolicies {
+		failurePolicy := whv1.FailurePolicyType(value)
+		if failurePolicy != whv1.Fail && failurePolicy != whv1.Ignore {
+			return nil, fmt.Errorf("invalid failure policy %s of webhook %s, expect Fail or Ignore", value, path)
+		}
+		policy := policies[path]
+		policy.FailurePolicy = &failurePolicy
+		policies[path] = policy
+	}
+	for path, value := range c.WebhookTimeouts {
+		timeout, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || timeout < 1 || timeout > 30 {
+			return nil, fmt.Errorf("invalid timeout %s of webhook %s, expect seconds between 1 and 30", value, path)
+		}
+		timeoutSeconds := int32(timeout)
+		policy := policies[path]
+		policy.TimeoutSeconds = &timeoutSeconds
+		policies[path] = policy
+	}
+
+	// the default of "*" fills in the fields not given for a path
+	if defaults, found := policies["*"]; found {
+		for path, policy := range policies {
+			if policy.FailurePolicy == nil {
+				policy.FailurePolicy = defaults.FailurePolicy
+			}
+			if policy.TimeoutSeconds == nil {
+				policy.TimeoutSeconds = defaults.TimeoutSeconds
+			}
+			policies[path] = policy
+		}
+	}
+	return policies, nil
+}
+
+// GetWebhookPolicy returns the failurePolicy and timeout of the webhook of the path.
+func GetWebhookPolicy(policies map[string]WebhookPolicy, path string) WebhookPolicy {
+	if policy, found := policies[path]; found {
+		return policy
+	}
+	return policies["*"]
+}
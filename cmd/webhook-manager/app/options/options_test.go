@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+	whv1 "k8s.io/api/admissionregistration/v1"
+)
+
+func TestParseWebhookPolicies(t *testing.T) {
+	ignore, fail := whv1.Ignore, whv1.Fail
+	five, ten := int32(5), int32(10)
+
+	testCases := []struct {
+		name      string
+		args      []string
+		expected  map[string]WebhookPolicy
+		expectErr bool
+	}{
+		{
+			name:     "not set",
+			expected: map[string]WebhookPolicy{},
+		},
+		{
+			name: "per path with default",
+			args: []string{"--webhook-failure-policy=/pods/mutate=Ignore,*=Fail", "--webhook-timeout=*=10,/pods/mutate=5"},
+			expected: map[string]WebhookPolicy{
+				"/pods/mutate": {FailurePolicy: &ignore, TimeoutSeconds: &five},
+				"*":            {FailurePolicy: &fail, TimeoutSeconds: &ten},
+			},
+		},
+		{
+			name: "default fills in the missing field",
+			args: []string{"--webhook-failure-policy=/jobs/validate=Ignore", "--webhook-timeout=*=10"},
+			expected: map[string]WebhookPolicy{
+				"/jobs/validate": {FailurePolicy: &ignore, TimeoutSeconds: &ten},
+				"*":              {TimeoutSeconds: &ten},
+			},
+		},
+		{
+			name:      "invalid failure policy",
+			args:      []string{"--webhook-failure-policy=/pods/mutate=Retry"},
+			expectErr: true,
+		},
+		{
+			name:      "timeout out of range",
+			args:      []string{"--webhook-timeout=/pods/mutate=60"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewConfig()
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			c.AddFlags(fs)
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatalf("failed to parse flags: %v", err)
+			}
+			policies, err := c.ParseWebhookPolicies()
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got policies %v", policies)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(policies, tc.expected) {
+				t.Errorf("expected policies %v, got %v", tc.expected, policies)
+			}
+			if policy := GetWebhookPolicy(policies, "/queues/mutate"); !reflect.DeepEqual(policy, policies["*"]) {
+				t.Errorf("expected the default policy for unlisted webhooks, got %v", policy)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package app
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -25,36 +26,50 @@ import (
 	"syscall"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	"volcano.sh/apis/pkg/apis/helpers"
 	"volcano.sh/apis/pkg/apis/scheduling/scheme"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
 	"volcano.sh/volcano/cmd/webhook-manager/app/options"
 	"volcano.sh/volcano/pkg/kube"
 	commonutil "volcano.sh/volcano/pkg/util"
+	"volcano.sh/volcano/pkg/util/tracing"
+	"volcano.sh/volcano/pkg/webhooks/certs"
 	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 	"volcano.sh/volcano/pkg/webhooks/router"
 )
 
 // Run start the service of admission controller.
 func Run(config *options.Config) error {
-	if config.EnableHealthz {
-		if err := helpers.StartHealthz(config.HealthzBindAddress, "volcano-admission", config.CaCertData, config.CertData, config.KeyData); err != nil {
-			return err
-		}
-	}
-
 	if config.WebhookURL == "" && config.WebhookNamespace == "" && config.WebhookName == "" {
 		return fmt.Errorf("failed to start webhooks as both 'url' and 'namespace/name' of webhook are empty")
 	}
 
+	webhookPolicies, err := config.ParseWebhookPolicies()
+	if err != nil {
+		return err
+	}
+
 	restConfig, err := kube.BuildConfig(config.KubeClientOptions)
 	if err != nil {
 		return fmt.Errorf("unable to build k8s config: %v", err)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.TODO(), config.Tracing, "volcano-admission")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.TODO()); err != nil {
+			klog.Errorf("Failed to flush the spans: %v", err)
+		}
+	}()
+
 	admissionConf := wkconfig.LoadAdmissionConf(config.ConfigPath)
 	if admissionConf == nil {
 		klog.Errorf("loadAdmissionConf failed.")
@@ -64,10 +79,38 @@ func Run(config *options.Config) error {
 
 	vClient := getVolcanoClient(restConfig)
 	kubeClient := getKubeClient(restConfig)
+	dynamicClient := getDynamicClient(restConfig)
+
+	current := &certs.Certificates{CACert: config.CaCertData, Cert: config.CertData, Key: config.KeyData}
+	var provisioner *certs.Provisioner
+	if config.SelfSignedCerts {
+		provisioner = &certs.Provisioner{
+			KubeClient:    kubeClient,
+			Namespace:     config.WebhookNamespace,
+			ServiceName:   config.WebhookName,
+			SecretName:    config.CertSecretName,
+			Validity:      config.CertValidity,
+			RotateBefore:  config.CertRotateBefore,
+			CheckInterval: config.CertCheckPeriod,
+		}
+		if current, err = provisioner.Ensure(); err != nil {
+			return fmt.Errorf("failed to provision certificates: %v", err)
+		}
+		config.CaCertData, config.CertData, config.KeyData = current.CACert, current.Cert, current.Key
+	}
+
+	if config.EnableHealthz {
+		if err := helpers.StartHealthz(config.HealthzBindAddress, "volcano-admission", config.CaCertData, config.CertData, config.KeyData); err != nil {
+			return err
+		}
+	}
 
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
 	recorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: commonutil.GenerateComponentName(config.SchedulerNames)})
+	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	vcInformerFactory := vcinformer.NewSharedInformerFactory(vClient, 0)
+	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
 	if err := router.ForEachAdmission(config, func(service *router.AdmissionService) error {
 		if service.Config != nil {
 			service.Config.VolcanoClient = vClient
@@ -75,29 +118,96 @@ func Run(config *options.Config) error {
 			service.Config.SchedulerNames = config.SchedulerNames
 			service.Config.Recorder = recorder
 			service.Config.ConfigData = admissionConf
+			service.Config.ControllerServiceAccounts = config.ControllerServiceAccounts
+			service.Config.KubeInformerFactory = kubeInformerFactory
+			service.Config.VolcanoInformerFactory = vcInformerFactory
+			service.Config.DynamicInformerFactory = dynamicInformerFactory
+			if service.Informers != nil {
+				service.Informers(service.Config)
+			}
 		}
 
 		klog.V(3).Infof("Registered '%s' as webhook.", service.Path)
 		http.HandleFunc(service.Path, service.Handler)
 
-		klog.V(3).Infof("Add CaCert for webhook <%s>", service.Path)
-		if err = addCaCertForWebhook(kubeClient, service, config.CaCertData); err != nil {
-			return fmt.Errorf("failed to add caCert for webhook %v", err)
+		var caBundle []byte
+		if config.PatchCABundle {
+			klog.V(3).Infof("Add CaCert for webhook <%s>", service.Path)
+			caBundle = config.CaCertData
+		}
+		if err = updateWebhookConfig(kubeClient, service, caBundle, options.GetWebhookPolicy(webhookPolicies, service.Path)); err != nil {
+			return fmt.Errorf("failed to update configuration of webhook %v", err)
 		}
 		return nil
 	}); err != nil {
 		return err
 	}
 
-	klog.V(3).Infof("Successfully added caCert for all webhooks")
+	klog.V(3).Infof("Successfully updated the configurations of all webhooks")
 
 	webhookServeError := make(chan struct{})
 	stopChannel := make(chan os.Signal, 1)
 	signal.Notify(stopChannel, syscall.SIGTERM, syscall.SIGINT)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// the admissions read from the listers, so they are served only once the informers are synced
+	kubeInformerFactory.Start(stopCh)
+	vcInformerFactory.Start(stopCh)
+	dynamicInformerFactory.Start(stopCh)
+	for informerType, ok := range kubeInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			return fmt.Errorf("failed to sync the informer of %v", informerType)
+		}
+	}
+	for informerType, ok := range vcInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			return fmt.Errorf("failed to sync the informer of %v", informerType)
+		}
+	}
+	for resource, ok := range dynamicInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			return fmt.Errorf("failed to sync the informer of %v", resource)
+		}
+	}
+
+	tlsConfig := configTLS(config, restConfig)
+	if len(config.CertData) != 0 && len(config.KeyData) != 0 {
+		// serve the certificate through the holder, so that it is rotated without restarting the server
+		holder := &certs.Holder{}
+		if err := holder.Set(current); err != nil {
+			return fmt.Errorf("invalid serving certificate: %v", err)
+		}
+		tlsConfig.Certificates = nil
+		tlsConfig.GetCertificate = holder.GetCertificate
+
+		// the CA bundle, which trusts the previous CA too, is patched before the rotated certificate is served
+		rotator := &certs.Rotator{Holder: holder}
+		if config.PatchCABundle {
+			rotator.PublishCABundle = func(caBundle []byte) error {
+				return router.ForEachAdmission(config, func(service *router.AdmissionService) error {
+					return updateWebhookConfig(kubeClient, service, caBundle, options.GetWebhookPolicy(webhookPolicies, service.Path))
+				})
+			}
+		}
+		onChange := rotator.OnChange
+		if provisioner != nil {
+			go provisioner.Run(current, onChange, stopCh)
+		} else if config.CertCheckPeriod > 0 {
+			source := &certs.FileSource{Load: func() (*certs.Certificates, error) {
+				caCert, cert, key, err := config.ReloadCAFiles()
+				if err != nil {
+					return nil, err
+				}
+				return &certs.Certificates{CACert: caCert, Cert: cert, Key: key}, nil
+			}}
+			go source.Run(current, config.CertCheckPeriod, onChange, stopCh)
+		}
+	}
 
 	server := &http.Server{
 		Addr:      config.ListenAddress + ":" + strconv.Itoa(config.Port),
-		TLSConfig: configTLS(config, restConfig),
+		TLSConfig: tlsConfig,
 	}
 	go func() {
 		err = server.ListenAndServeTLS("", "")
@@ -29,8 +29,10 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	"volcano.sh/apis/pkg/client/clientset/versioned"
@@ -40,14 +42,15 @@ import (
 
 const volcanoAdmissionPrefix = "volcano-admission-service"
 
-func addCaCertForWebhook(kubeClient *kubernetes.Clientset, service *router.AdmissionService, caBundle []byte) error {
+// updateWebhookConfig patches the CA bundle, unless it is nil, and the failurePolicy and timeout
+// of the policy into the webhook configurations of the service. The configurations are updated on
+// conflict, as the replicas of the admission service update them concurrently when they start.
+func updateWebhookConfig(kubeClient kubernetes.Interface, service *router.AdmissionService, caBundle []byte, policy options.WebhookPolicy) error {
 	if service.MutatingConfig != nil {
 		// update MutatingWebhookConfigurations
 		var mutatingWebhookName = volcanoAdmissionPrefix + strings.ReplaceAll(service.Path, "/", "-")
-		var mutatingWebhook *v1.MutatingWebhookConfiguration
-		webhookChanged := false
 		if err := wait.Poll(time.Second, 5*time.Minute, func() (done bool, err error) {
-			mutatingWebhook, err = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), mutatingWebhookName, metav1.GetOptions{})
+			_, err = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), mutatingWebhookName, metav1.GetOptions{})
 			if err != nil {
 				if apierrors.IsNotFound(err) {
 					klog.Errorln(err)
@@ -60,27 +63,36 @@ func addCaCertForWebhook(kubeClient *kubernetes.Clientset, service *router.Admis
 			return fmt.Errorf("failed to get mutating webhook %v", err)
 		}
 
-		for index := 0; index < len(mutatingWebhook.Webhooks); index++ {
-			if mutatingWebhook.Webhooks[index].ClientConfig.CABundle == nil ||
-				!bytes.Equal(mutatingWebhook.Webhooks[index].ClientConfig.CABundle, caBundle) {
-				mutatingWebhook.Webhooks[index].ClientConfig.CABundle = caBundle
-				webhookChanged = true
+		if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			mutatingWebhook, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), mutatingWebhookName, metav1.GetOptions{})
+			if err != nil {
+				return err
 			}
-		}
-		if webhookChanged {
-			if _, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.TODO(), mutatingWebhook, metav1.UpdateOptions{}); err != nil {
-				return fmt.Errorf("failed to update mutating admission webhooks %v %v", mutatingWebhookName, err)
+			webhookChanged := false
+			for index := 0; index < len(mutatingWebhook.Webhooks); index++ {
+				webhook := &mutatingWebhook.Webhooks[index]
+				if updateWebhookClientConfig(&webhook.ClientConfig, caBundle) {
+					webhookChanged = true
+				}
+				if updateWebhookPolicy(&webhook.FailurePolicy, &webhook.TimeoutSeconds, policy) {
+					webhookChanged = true
+				}
+			}
+			if !webhookChanged {
+				return nil
 			}
+			_, err = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.TODO(), mutatingWebhook, metav1.UpdateOptions{})
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to update mutating admission webhooks %v %v", mutatingWebhookName, err)
 		}
 	}
 
 	if service.ValidatingConfig != nil {
 		// update ValidatingWebhookConfigurations
 		var validatingWebhookName = volcanoAdmissionPrefix + strings.ReplaceAll(service.Path, "/", "-")
-		var validatingWebhook *v1.ValidatingWebhookConfiguration
-		webhookChanged := false
 		if err := wait.Poll(time.Second, 5*time.Minute, func() (done bool, err error) {
-			validatingWebhook, err = kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), validatingWebhookName, metav1.GetOptions{})
+			_, err = kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), validatingWebhookName, metav1.GetOptions{})
 			if err != nil {
 				if apierrors.IsNotFound(err) {
 					klog.Errorln(err)
@@ -93,23 +105,57 @@ func addCaCertForWebhook(kubeClient *kubernetes.Clientset, service *router.Admis
 			return fmt.Errorf("failed to get validating webhook %v", err)
 		}
 
-		for index := 0; index < len(validatingWebhook.Webhooks); index++ {
-			if validatingWebhook.Webhooks[index].ClientConfig.CABundle == nil ||
-				!bytes.Equal(validatingWebhook.Webhooks[index].ClientConfig.CABundle, caBundle) {
-				validatingWebhook.Webhooks[index].ClientConfig.CABundle = caBundle
-				webhookChanged = true
+		if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			validatingWebhook, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), validatingWebhookName, metav1.GetOptions{})
+			if err != nil {
+				return err
 			}
-		}
-		if webhookChanged {
-			if _, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.TODO(), validatingWebhook, metav1.UpdateOptions{}); err != nil {
-				return fmt.Errorf("failed to update validating admission webhooks %v %v", validatingWebhookName, err)
+			webhookChanged := false
+			for index := 0; index < len(validatingWebhook.Webhooks); index++ {
+				webhook := &validatingWebhook.Webhooks[index]
+				if updateWebhookClientConfig(&webhook.ClientConfig, caBundle) {
+					webhookChanged = true
+				}
+				if updateWebhookPolicy(&webhook.FailurePolicy, &webhook.TimeoutSeconds, policy) {
+					webhookChanged = true
+				}
+			}
+			if !webhookChanged {
+				return nil
 			}
+			_, err = kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.TODO(), validatingWebhook, metav1.UpdateOptions{})
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to update validating admission webhooks %v %v", validatingWebhookName, err)
 		}
 	}
 
 	return nil
 }
 
+func updateWebhookClientConfig(clientConfig *v1.WebhookClientConfig, caBundle []byte) bool {
+	if caBundle == nil || (clientConfig.CABundle != nil && bytes.Equal(clientConfig.CABundle, caBundle)) {
+		return false
+	}
+	clientConfig.CABundle = caBundle
+	return true
+}
+
+func updateWebhookPolicy(failurePolicy **v1.FailurePolicyType, timeoutSeconds **int32, policy options.WebhookPolicy) bool {
+	changed := false
+	if policy.FailurePolicy != nil && (*failurePolicy == nil || **failurePolicy != *policy.FailurePolicy) {
+		value := *policy.FailurePolicy
+		*failurePolicy = &value
+		changed = true
+	}
+	if policy.TimeoutSeconds != nil && (*timeoutSeconds == nil || **timeoutSeconds != *policy.TimeoutSeconds) {
+		value := *policy.TimeoutSeconds
+		*timeoutSeconds = &value
+		changed = true
+	}
+	return changed
+}
+
 // getKubeClient Get a clientset with restConfig.
 func getKubeClient(restConfig *rest.Config) *kubernetes.Clientset {
 	clientset, err := kubernetes.NewForConfig(restConfig)
@@ -128,6 +174,15 @@ func getVolcanoClient(restConfig *rest.Config) *versioned.Clientset {
 	return clientset
 }
 
+// getDynamicClient get a dynamic client for the resources without typed clientset, e.g. policies.
+func getDynamicClient(restConfig *rest.Config) dynamic.Interface {
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
+
 // configTLS is a helper function that generate tls certificates from directly defined tls config or kubeconfig
 // These are passed in as command line for cluster certification. If tls config is passed in, we use the directly
 // defined tls config, else use that defined in kubeconfig.
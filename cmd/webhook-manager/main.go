@@ -30,10 +30,14 @@ import (
 
 	"volcano.sh/volcano/cmd/webhook-manager/app"
 	"volcano.sh/volcano/cmd/webhook-manager/app/options"
+	"volcano.sh/volcano/pkg/util/logging"
 	"volcano.sh/volcano/pkg/version"
+	_ "volcano.sh/volcano/pkg/webhooks/admission/jobflows/mutate"
+	_ "volcano.sh/volcano/pkg/webhooks/admission/jobflows/validate"
 	_ "volcano.sh/volcano/pkg/webhooks/admission/jobs/mutate"
 	_ "volcano.sh/volcano/pkg/webhooks/admission/jobs/validate"
 	_ "volcano.sh/volcano/pkg/webhooks/admission/podgroups/mutate"
+	_ "volcano.sh/volcano/pkg/webhooks/admission/podgroups/validate"
 	_ "volcano.sh/volcano/pkg/webhooks/admission/pods/mutate"
 	_ "volcano.sh/volcano/pkg/webhooks/admission/pods/validate"
 	_ "volcano.sh/volcano/pkg/webhooks/admission/queues/mutate"
@@ -55,6 +59,11 @@ func main() {
 		version.PrintVersionAndExit()
 		return
 	}
+	if err := config.Logging.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	logging.Setup(config.Logging)
 
 	klog.StartFlushDaemon(*logFlushFreq)
 	defer klog.Flush()
@@ -63,8 +72,19 @@ func main() {
 		klog.Fatalf("Configured port is invalid: %v", err)
 	}
 
-	if err := config.ParseCAFiles(nil); err != nil {
-		klog.Fatalf("Failed to parse CA file: %v", err)
+	if err := config.CheckCertsOrDie(); err != nil {
+		klog.Fatalf("Configured certificates are invalid: %v", err)
+	}
+
+	if err := config.Tracing.Validate(); err != nil {
+		klog.Fatalf("Configured tracing is invalid: %v", err)
+	}
+
+	// self-signed certificates are provisioned when the server starts
+	if !config.SelfSignedCerts {
+		if err := config.ParseCAFiles(nil); err != nil {
+			klog.Fatalf("Failed to parse CA file: %v", err)
+		}
 	}
 
 	if err := app.Run(config); err != nil {
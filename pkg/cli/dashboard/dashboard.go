@@ -0,0 +1,394 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+type dashboardFlags struct {
+	util.CommonFlags
+
+	// Namespace of the jobs, all namespaces if empty
+	Namespace string
+	// Interval is the refresh interval
+	Interval time.Duration
+	// Since is how long failed jobs are shown after they failed
+	Since time.Duration
+	// Limit is the maximum number of jobs shown by section
+	Limit int
+	// Once prints the dashboard once instead of refreshing it
+	Once bool
+	// ReportNamespace is the namespace the scheduler publishes the report of the unschedulable podgroups into
+	ReportNamespace string
+}
+
+var flags = &dashboardFlags{}
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// InitFlags init the dashboard command flags.
+func InitFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &flags.CommonFlags)
+
+	cmd.Flags().StringVarP(&flags.Namespace, "namespace", "n", "", "the namespace of jobs, all namespaces if not set")
+	cmd.Flags().DurationVarP(&flags.Interval, "interval", "", 2*time.Second, "the refresh interval")
+	cmd.Flags().DurationVarP(&flags.Since, "since", "", time.Hour, "show the jobs failed within this duration")
+	cmd.Flags().IntVarP(&flags.Limit, "limit", "", 10, "the maximum number of jobs shown by section")
+	cmd.Flags().BoolVarP(&flags.Once, "once", "", false, "print the dashboard once instead of refreshing it")
+	cmd.Flags().StringVarP(&flags.ReportNamespace, "report-namespace", "", "volcano-system", "the namespace the scheduler publishes the report of the unschedulable podgroups into")
+}
+
+// Dashboard prints the overview of the queues and jobs of the cluster, refreshing it until interrupted.
+func Dashboard(ctx context.Context) error {
+	config, err := util.BuildConfig(flags.Master, flags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if flags.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	vcClient := versioned.NewForConfigOrDie(config)
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+
+	if flags.Once {
+		s, err := takeSnapshot(ctx, vcClient, kubeClient)
+		if err != nil {
+			return err
+		}
+		printSnapshot(s, os.Stdout)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(flags.Interval)
+	defer ticker.Stop()
+	for {
+		s, err := takeSnapshot(ctx, vcClient, kubeClient)
+		if ctx.Err() != nil {
+			return nil
+		}
+		fmt.Print(clearScreen)
+		if err != nil {
+			// keep refreshing, the api server may be unavailable for a while
+			fmt.Printf("Failed to refresh the dashboard: %v\n", err)
+		} else {
+			printSnapshot(s, os.Stdout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// queueSummary is the share and usage of a queue.
+type queueSummary struct {
+	Name      string
+	State     string
+	Weight    int32
+	Deserved  v1.ResourceList
+	Allocated v1.ResourceList
+	// Share is the highest share of the deserved resources allocated to the queue, negative if not deserved
+	Share   float64
+	Running int32
+	Pending int32
+}
+
+// jobSummary is a job shown by the dashboard.
+type jobSummary struct {
+	Namespace string
+	Name      string
+	Queue     string
+	Phase     v1alpha1.JobPhase
+	Since     time.Time
+	Reason    string
+}
+
+// snapshot is the content of the dashboard at a time.
+type snapshot struct {
+	Time   time.Time
+	Queues []queueSummary
+	// Phases is the number of jobs by phase
+	Phases  map[v1alpha1.JobPhase]int
+	Running []jobSummary
+	Pending []jobSummary
+	Failed  []jobSummary
+	// Unschedulable is the report of the unschedulable podgroups, nil if the scheduler does not publish it
+	Unschedulable *api.UnschedulableReport
+}
+
+func takeSnapshot(ctx context.Context, vcClient versioned.Interface, kubeClient kubernetes.Interface) (*snapshot, error) {
+	queues, err := vcClient.SchedulingV1beta1().Queues().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := vcClient.BatchV1alpha1().Jobs(flags.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	s := buildSnapshot(queues.Items, jobs.Items, time.Now(), flags.Since, flags.Limit)
+
+	cm, err := kubeClient.CoreV1().ConfigMaps(flags.ReportNamespace).Get(ctx, api.UnschedulableReportConfigMap, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err) || apierrors.IsForbidden(err):
+		// the report is not published, or not readable by the user
+	case err != nil:
+		return nil, err
+	default:
+		report, err := api.ParseUnschedulableReport(cm.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the report of the unschedulable podgroups: %v", err)
+		}
+		s.Unschedulable = filterReport(report, flags.Namespace, flags.Limit)
+	}
+	return s, nil
+}
+
+// filterReport keeps the podgroups of the report in the namespace, all namespaces if empty, up to the limit.
+func filterReport(report *api.UnschedulableReport, namespace string, limit int) *api.UnschedulableReport {
+	if namespace != "" {
+		podGroups := report.PodGroups[:0]
+		report.Reasons = map[string]int{}
+		for _, pg := range report.PodGroups {
+			if pg.Namespace == namespace {
+				podGroups = append(podGroups, pg)
+				report.Reasons[pg.Reason]++
+			}
+		}
+		report.PodGroups = podGroups
+		report.Total = len(podGroups)
+	}
+	if limit > 0 && len(report.PodGroups) > limit {
+		report.PodGroups = report.PodGroups[:limit]
+	}
+	return report
+}
+
+// buildSnapshot summarizes the queues and the jobs, the failed jobs limited to the ones failed since the duration.
+func buildSnapshot(queues []v1beta1.Queue, jobs []v1alpha1.Job, now time.Time, since time.Duration, limit int) *snapshot {
+	s := &snapshot{Time: now, Phases: map[v1alpha1.JobPhase]int{}}
+
+	for _, queue := range queues {
+		s.Queues = append(s.Queues, queueSummary{
+			Name:      queue.Name,
+			State:     string(queue.Status.State),
+			Weight:    queue.Spec.Weight,
+			Deserved:  queue.Spec.Deserved,
+			Allocated: queue.Status.Allocated,
+			Share:     queueShare(queue.Status.Allocated, queue.Spec.Deserved),
+			Running:   queue.Status.Running,
+			Pending:   queue.Status.Pending + queue.Status.Inqueue,
+		})
+	}
+	sort.Slice(s.Queues, func(i, j int) bool {
+		if s.Queues[i].Share != s.Queues[j].Share {
+			return s.Queues[i].Share > s.Queues[j].Share
+		}
+		return s.Queues[i].Name < s.Queues[j].Name
+	})
+
+	for _, job := range jobs {
+		phase := job.Status.State.Phase
+		if phase == "" {
+			phase = v1alpha1.Pending
+		}
+		s.Phases[phase]++
+
+		summary := jobSummary{
+			Namespace: job.Namespace,
+			Name:      job.Name,
+			Queue:     job.Spec.Queue,
+			Phase:     phase,
+			Since:     job.Status.State.LastTransitionTime.Time,
+			Reason:    strings.TrimSpace(strings.Join(nonEmpty(job.Status.State.Reason, job.Status.State.Message), ": ")),
+		}
+		if summary.Since.IsZero() {
+			summary.Since = job.CreationTimestamp.Time
+		}
+		switch phase {
+		case v1alpha1.Running:
+			s.Running = append(s.Running, summary)
+		case v1alpha1.Pending:
+			s.Pending = append(s.Pending, summary)
+		case v1alpha1.Failed, v1alpha1.Aborted, v1alpha1.Terminated:
+			if now.Sub(summary.Since) <= since {
+				s.Failed = append(s.Failed, summary)
+			}
+		}
+	}
+
+	// the longest running and pending jobs first, the most recently failed first
+	oldestFirst := func(jobs []jobSummary) {
+		sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Since.Before(jobs[j].Since) })
+	}
+	oldestFirst(s.Running)
+	oldestFirst(s.Pending)
+	sort.SliceStable(s.Failed, func(i, j int) bool { return s.Failed[i].Since.After(s.Failed[j].Since) })
+	s.Running = truncate(s.Running, limit)
+	s.Pending = truncate(s.Pending, limit)
+	s.Failed = truncate(s.Failed, limit)
+	return s
+}
+
+// queueShare returns the highest share of the deserved resources allocated, -1 if the queue deserves nothing.
+func queueShare(allocated, deserved v1.ResourceList) float64 {
+	share := -1.0
+	for name, quantity := range deserved {
+		if quantity.IsZero() {
+			continue
+		}
+		used := allocated[name]
+		if s := float64(used.MilliValue()) / float64(quantity.MilliValue()); s > share {
+			share = s
+		}
+	}
+	return share
+}
+
+func printSnapshot(s *snapshot, writer io.Writer) {
+	fmt.Fprintf(writer, "Volcano dashboard at %s\n\n", s.Time.Format(time.RFC3339))
+
+	fmt.Fprintf(writer, "%-20s%-8s%-8s%-8s%-8s%-8s%-30s%s\n", "Queue", "State", "Weight", "Share", "Running", "Pending", "Allocated", "Deserved")
+	for _, q := range s.Queues {
+		share := "-"
+		if q.Share >= 0 {
+			share = fmt.Sprintf("%.0f%%", q.Share*100)
+		}
+		fmt.Fprintf(writer, "%-20s%-8s%-8d%-8s%-8d%-8d%-30s%s\n", q.Name, q.State, q.Weight, share, q.Running, q.Pending,
+			formatResources(q.Allocated), formatResources(q.Deserved))
+	}
+
+	phases := make([]string, 0, len(s.Phases))
+	for phase := range s.Phases {
+		phases = append(phases, string(phase))
+	}
+	sort.Strings(phases)
+	counts := make([]string, 0, len(phases))
+	for _, phase := range phases {
+		counts = append(counts, fmt.Sprintf("%s: %d", phase, s.Phases[v1alpha1.JobPhase(phase)]))
+	}
+	fmt.Fprintf(writer, "\nJobs: %s\n", strings.Join(counts, ", "))
+
+	printJobs(writer, "Running Jobs", "Running For", s.Running, s.Time)
+	printJobs(writer, "Pending Jobs", "Pending For", s.Pending, s.Time)
+	printJobs(writer, "Recently Failed Jobs", "Ago", s.Failed, s.Time)
+	if s.Unschedulable != nil {
+		printUnschedulable(writer, s.Unschedulable, s.Time)
+	}
+}
+
+func printUnschedulable(writer io.Writer, report *api.UnschedulableReport, now time.Time) {
+	reasons := make([]string, 0, len(report.Reasons))
+	for reason := range report.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	counts := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		counts = append(counts, fmt.Sprintf("%s: %d", reason, report.Reasons[reason]))
+	}
+	fmt.Fprintf(writer, "\nUnschedulable PodGroups (for more than %s, %d in total, reported %s ago):\n", report.Threshold, report.Total,
+		duration.HumanDuration(now.Sub(report.Time)))
+	if len(report.PodGroups) == 0 {
+		fmt.Fprintf(writer, "  <none>\n")
+		return
+	}
+	fmt.Fprintf(writer, "  %s\n", strings.Join(counts, ", "))
+	fmt.Fprintf(writer, "  %-40s%-15s%-14s%-28s%s\n", "PodGroup", "Queue", "For", "Reason", "Message")
+	for _, pg := range report.PodGroups {
+		fmt.Fprintf(writer, "  %-40s%-15s%-14s%-28s%s\n", pg.Namespace+"/"+pg.Name, pg.Queue,
+			duration.HumanDuration(now.Sub(pg.Since)), pg.Reason, pg.Message)
+	}
+}
+
+func printJobs(writer io.Writer, title, age string, jobs []jobSummary, now time.Time) {
+	fmt.Fprintf(writer, "\n%s:\n", title)
+	if len(jobs) == 0 {
+		fmt.Fprintf(writer, "  <none>\n")
+		return
+	}
+	fmt.Fprintf(writer, "  %-40s%-15s%-12s%-14s%s\n", "Job", "Queue", "Phase", age, "Reason")
+	for _, job := range jobs {
+		reason := job.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(writer, "  %-40s%-15s%-12s%-14s%s\n", job.Namespace+"/"+job.Name, job.Queue, job.Phase,
+			duration.HumanDuration(now.Sub(job.Since)), reason)
+	}
+}
+
+func formatResources(resources v1.ResourceList) string {
+	if len(resources) == 0 {
+		return "<none>"
+	}
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := resources[v1.ResourceName(name)]
+		parts = append(parts, name+"="+quantity.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func truncate(jobs []jobSummary, limit int) []jobSummary {
+	if limit > 0 && len(jobs) > limit {
+		return jobs[:limit]
+	}
+	return jobs
+}
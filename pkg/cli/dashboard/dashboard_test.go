@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func buildQueue(name, deserved, allocated string) v1beta1.Queue {
+	queue := v1beta1.Queue{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	queue.Status.State = v1beta1.QueueStateOpen
+	if deserved != "" {
+		queue.Spec.Deserved = v1.ResourceList{v1.ResourceCPU: resource.MustParse(deserved)}
+	}
+	queue.Status.Allocated = v1.ResourceList{v1.ResourceCPU: resource.MustParse(allocated)}
+	return queue
+}
+
+func buildJob(name string, phase v1alpha1.JobPhase, since time.Time, reason string) v1alpha1.Job {
+	return v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       v1alpha1.JobSpec{Queue: "team-a"},
+		Status: v1alpha1.JobStatus{State: v1alpha1.JobState{
+			Phase:              phase,
+			Reason:             reason,
+			LastTransitionTime: metav1.NewTime(since),
+		}},
+	}
+}
+
+func TestBuildSnapshot(t *testing.T) {
+	now := time.Now()
+	queues := []v1beta1.Queue{
+		buildQueue("default", "", "2"),
+		buildQueue("team-a", "10", "5"),
+		buildQueue("team-b", "4", "6"),
+	}
+	jobs := []v1alpha1.Job{
+		buildJob("running-new", v1alpha1.Running, now.Add(-time.Minute), ""),
+		buildJob("running-old", v1alpha1.Running, now.Add(-time.Hour), ""),
+		buildJob("pending", v1alpha1.Pending, now.Add(-time.Minute), ""),
+		buildJob("failed-recently", v1alpha1.Failed, now.Add(-10*time.Minute), "PodFailed"),
+		buildJob("failed-long-ago", v1alpha1.Failed, now.Add(-2*time.Hour), "PodFailed"),
+		buildJob("completed", v1alpha1.Completed, now.Add(-time.Minute), ""),
+	}
+
+	s := buildSnapshot(queues, jobs, now, time.Hour, 10)
+
+	var queueNames []string
+	for _, q := range s.Queues {
+		queueNames = append(queueNames, q.Name)
+	}
+	if expected := []string{"team-b", "team-a", "default"}; !reflect.DeepEqual(queueNames, expected) {
+		t.Errorf("expected queues by share %v, got %v", expected, queueNames)
+	}
+	if s.Queues[1].Share != 0.5 || s.Queues[2].Share != -1 {
+		t.Errorf("unexpected shares %+v", s.Queues)
+	}
+
+	names := func(jobs []jobSummary) []string {
+		var result []string
+		for _, job := range jobs {
+			result = append(result, job.Name)
+		}
+		return result
+	}
+	if expected := []string{"running-old", "running-new"}; !reflect.DeepEqual(names(s.Running), expected) {
+		t.Errorf("expected running jobs %v, got %v", expected, names(s.Running))
+	}
+	if expected := []string{"failed-recently"}; !reflect.DeepEqual(names(s.Failed), expected) {
+		t.Errorf("expected failed jobs %v, got %v", expected, names(s.Failed))
+	}
+	expectedPhases := map[v1alpha1.JobPhase]int{v1alpha1.Running: 2, v1alpha1.Pending: 1, v1alpha1.Failed: 2, v1alpha1.Completed: 1}
+	if !reflect.DeepEqual(s.Phases, expectedPhases) {
+		t.Errorf("expected phases %v, got %v", expectedPhases, s.Phases)
+	}
+
+	if limited := buildSnapshot(queues, jobs, now, time.Hour, 1); len(limited.Running) != 1 || limited.Running[0].Name != "running-old" {
+		t.Errorf("expected the longest running job only, got %v", names(limited.Running))
+	}
+
+	out := &bytes.Buffer{}
+	printSnapshot(s, out)
+	for _, line := range []string{
+		"team-a              Open    0       50%     0       0       cpu=5                         cpu=10",
+		"Jobs: Completed: 1, Failed: 2, Pending: 1, Running: 2",
+		"default/failed-recently                 team-a         Failed      10m           PodFailed",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected line %q in\n%s", line, out.String())
+		}
+	}
+}
+
+func TestUnschedulableReport(t *testing.T) {
+	now := time.Now()
+	buildReport := func() *api.UnschedulableReport {
+		return &api.UnschedulableReport{
+			Time:      now.Add(-30 * time.Second),
+			Threshold: "5m0s",
+			Total:     3,
+			Reasons:   map[string]int{api.NodeResourcesInsufficientReason: 2, api.QueueQuotaInsufficientReason: 1},
+			PodGroups: []api.UnschedulablePodGroup{
+				{Namespace: "default", Name: "train-1", Queue: "team-a", Since: now.Add(-time.Hour), Reason: api.NodeResourcesInsufficientReason,
+					Message: "nodes are short of 4 nvidia.com/gpu for 2 pending tasks"},
+				{Namespace: "research", Name: "train-2", Queue: "team-b", Since: now.Add(-20 * time.Minute), Reason: api.QueueQuotaInsufficientReason},
+				{Namespace: "default", Name: "train-3", Queue: "team-a", Since: now.Add(-10 * time.Minute), Reason: api.NodeResourcesInsufficientReason},
+			},
+		}
+	}
+
+	if report := filterReport(buildReport(), "", 2); report.Total != 3 || len(report.PodGroups) != 2 {
+		t.Errorf("expected 2 of 3 podgroups, got %d of %d", len(report.PodGroups), report.Total)
+	}
+	report := filterReport(buildReport(), "default", 10)
+	if expected := map[string]int{api.NodeResourcesInsufficientReason: 2}; report.Total != 2 || !reflect.DeepEqual(report.Reasons, expected) {
+		t.Errorf("expected the podgroups of namespace default only, got %d by reasons %v", report.Total, report.Reasons)
+	}
+
+	out := &bytes.Buffer{}
+	printUnschedulable(out, report, now)
+	for _, line := range []string{
+		"Unschedulable PodGroups (for more than 5m0s, 2 in total, reported 30s ago):",
+		"NodeResourcesInsufficient: 2",
+		"default/train-1                         team-a         60m           NodeResourcesInsufficient   nodes are short of 4 nvidia.com/gpu for 2 pending tasks",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected line %q in\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+type createFlags struct {
+	util.CommonFlags
+
+	Namespace    string
+	Name         string
+	TemplateName string
+	Parameters   []string
+}
+
+var createJobFlags = &createFlags{}
+
+// InitCreateFlags init create command flags.
+func InitCreateFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &createJobFlags.CommonFlags)
+
+	cmd.Flags().StringVarP(&createJobFlags.Namespace, "namespace", "n", "default", "the namespace of job and job template")
+	cmd.Flags().StringVarP(&createJobFlags.Name, "name", "N", "", "the name of job, generated from the name of job template if not set")
+	cmd.Flags().StringVarP(&createJobFlags.TemplateName, "from-template", "t", "", "the name of job template to create job from")
+	cmd.Flags().StringArrayVar(&createJobFlags.Parameters, "set", nil, "the value of a job template parameter, as name=value")
+}
+
+// CreateJob creates a job from a job template, substituting the parameters of the template.
+func CreateJob(ctx context.Context) error {
+	config, err := util.BuildConfig(createJobFlags.Master, createJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	if createJobFlags.TemplateName == "" {
+		err := fmt.Errorf("job template name is mandatory to create a job")
+		return err
+	}
+
+	values, err := jobhelpers.ParseTemplateValues(createJobFlags.Parameters)
+	if err != nil {
+		return err
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	template, err := jobClient.FlowV1alpha1().JobTemplates(createJobFlags.Namespace).Get(ctx, createJobFlags.TemplateName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	job, err := newJobFromTemplate(template, createJobFlags.Name, values)
+	if err != nil {
+		return err
+	}
+
+	newJob, err := jobClient.BatchV1alpha1().Jobs(createJobFlags.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("create job %v from job template %v successfully\n", newJob.Name, template.Name)
+	return nil
+}
+
+// newJobFromTemplate builds the job expanded from the job template with the given parameter values.
+func newJobFromTemplate(template *flowv1alpha1.JobTemplate, name string, values map[string]string) (*vcbatch.Job, error) {
+	spec, resolved, err := jobhelpers.ExpandJobTemplate(template, values)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &vcbatch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: template.Namespace,
+		},
+		Spec: *spec,
+	}
+	if name == "" {
+		job.GenerateName = template.Name + "-"
+	}
+	jobhelpers.SetTemplateValues(job, template.Name, resolved)
+	return job, nil
+}
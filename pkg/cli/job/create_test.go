@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+func TestNewJobFromTemplate(t *testing.T) {
+	template := &flowv1alpha1.JobTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "test",
+			Name:        "train",
+			Annotations: map[string]string{jobhelpers.TemplateParametersKey: `[{"name":"queue","required":true}]`},
+		},
+		Spec: v1alpha1batch.JobSpec{Queue: "${queue}"},
+	}
+
+	testCases := []struct {
+		Name               string
+		JobName            string
+		Values             map[string]string
+		ExpectName         string
+		ExpectGenerateName string
+		ExpectError        bool
+	}{
+		{
+			Name:        "create job with name",
+			JobName:     "train-1",
+			Values:      map[string]string{"queue": "q1"},
+			ExpectName:  "train-1",
+			ExpectError: false,
+		},
+		{
+			Name:               "create job with generated name",
+			Values:             map[string]string{"queue": "q1"},
+			ExpectGenerateName: "train-",
+			ExpectError:        false,
+		},
+		{
+			Name:        "create job without required parameter",
+			ExpectError: true,
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job, err := newJobFromTemplate(template, testcase.JobName, testcase.Values)
+			if (err != nil) != testcase.ExpectError {
+				t.Fatalf("expected error: %v, got %v", testcase.ExpectError, err)
+			}
+			if err != nil {
+				return
+			}
+			if job.Name != testcase.ExpectName || job.GenerateName != testcase.ExpectGenerateName {
+				t.Errorf("expected name %q and generateName %q, got %q and %q",
+					testcase.ExpectName, testcase.ExpectGenerateName, job.Name, job.GenerateName)
+			}
+			if job.Spec.Queue != "q1" {
+				t.Errorf("expected queue q1, got %s", job.Spec.Queue)
+			}
+			if job.Annotations[jobhelpers.FromTemplateKey] != "train" {
+				t.Errorf("expected job created from template train, got %v", job.Annotations)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"volcano.sh/apis/pkg/client/clientset/versioned"
 	"volcano.sh/volcano/pkg/cli/util"
@@ -33,6 +34,8 @@ type deleteFlags struct {
 
 	Namespace string
 	JobName   string
+	Reason    string
+	Message   string
 }
 
 var deleteJobFlags = &deleteFlags{}
@@ -42,8 +45,13 @@ func InitDeleteFlags(cmd *cobra.Command) {
 	util.InitFlags(cmd, &deleteJobFlags.CommonFlags)
 	cmd.Flags().StringVarP(&deleteJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
 	cmd.Flags().StringVarP(&deleteJobFlags.JobName, "name", "N", "", "the name of job")
+	cmd.Flags().StringVarP(&deleteJobFlags.Reason, "reason", "", "", "the reason of the deletion recorded in an event of the job, a CamelCase word, e.g. Maintenance")
+	cmd.Flags().StringVarP(&deleteJobFlags.Message, "message", "m", "", "the message of the deletion recorded in an event of the job")
 }
 
+// deletedReason is the reason of the event of the deletion when only a message is given.
+const deletedReason = "Deleted"
+
 // DeleteJob delete the job.
 func DeleteJob(ctx context.Context) error {
 	config, err := util.BuildConfig(deleteJobFlags.Master, deleteJobFlags.Kubeconfig)
@@ -56,7 +64,26 @@ func DeleteJob(ctx context.Context) error {
 		return err
 	}
 
+	if err := validateReason(deleteJobFlags.Reason); err != nil {
+		return err
+	}
+
 	jobClient := versioned.NewForConfigOrDie(config)
+	if deleteJobFlags.Reason != "" || deleteJobFlags.Message != "" {
+		job, err := jobClient.BatchV1alpha1().Jobs(deleteJobFlags.Namespace).Get(ctx, deleteJobFlags.JobName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		reason := deleteJobFlags.Reason
+		if reason == "" {
+			reason = deletedReason
+		}
+		// The event outlives the job for the event TTL, so that its users can see why it was deleted.
+		if err := recordJobEvent(ctx, kubernetes.NewForConfigOrDie(config), job, reason, deleteJobFlags.Message); err != nil {
+			return err
+		}
+	}
+
 	err = jobClient.BatchV1alpha1().Jobs(deleteJobFlags.Namespace).Delete(ctx, deleteJobFlags.JobName, metav1.DeleteOptions{})
 	if err != nil {
 		return err
@@ -50,15 +50,25 @@ func TestDeleteJobJob(t *testing.T) {
 
 	testCases := []struct {
 		Name        string
+		Reason      string
+		Message     string
 		ExpectValue error
 	}{
 		{
 			Name:        "DeleteJob",
 			ExpectValue: nil,
 		},
+		{
+			Name:        "DeleteJobWithReason",
+			Reason:      "Maintenance",
+			Message:     "node pool is drained for the upgrade",
+			ExpectValue: nil,
+		},
 	}
 
 	for i, testcase := range testCases {
+		deleteJobFlags.Reason = testcase.Reason
+		deleteJobFlags.Message = testcase.Message
 		err := DeleteJob(context.TODO())
 		if err != nil {
 			t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, err)
@@ -77,5 +87,11 @@ func TestInitDeleteFlags(t *testing.T) {
 	if cmd.Flag("name") == nil {
 		t.Errorf("Could not find the flag name")
 	}
+	if cmd.Flag("reason") == nil {
+		t.Errorf("Could not find the flag reason")
+	}
+	if cmd.Flag("message") == nil {
+		t.Errorf("Could not find the flag message")
+	}
 
 }
@@ -0,0 +1,329 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// PodGroupName returns the name of the podgroup created by the job controller for the job.
+func PodGroupName(job *v1alpha1.Job) string {
+	return job.Name + "-" + string(job.UID)
+}
+
+// PrintPods print the pods of the job, ordered by task and index, into writer.
+func PrintPods(pods []v1.Pod, writer io.Writer) {
+	if len(pods) == 0 {
+		WriteLine(writer, Level0, "Pods:       \t<none>\n")
+		return
+	}
+	WriteLine(writer, Level0, "Pods:\n  Name\tTask\tIndex\tPhase\tNode\tRestarts\tAge\n")
+	for i := range pods {
+		pod := &pods[i]
+		restarts := int32(0)
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			nodeName = "<none>"
+		}
+		WriteLine(writer, Level1, "%s \t%s \t%d \t%s \t%s \t%d \t%s\n",
+			pod.Name, pod.Labels[v1alpha1.TaskSpecKey], api.GetPodIndex(pod), pod.Status.Phase, nodeName,
+			restarts, translateTimestampSince(pod.CreationTimestamp))
+	}
+}
+
+// PrintPodGroup print the status and the conditions of the podgroup of the job into writer.
+func PrintPodGroup(podGroup *v1beta1.PodGroup, writer io.Writer) {
+	if podGroup == nil {
+		WriteLine(writer, Level0, "PodGroup:   \t<none>\n")
+		return
+	}
+	WriteLine(writer, Level0, "PodGroup:\n")
+	WriteLine(writer, Level1, "Name:         \t%s\n", podGroup.Name)
+	WriteLine(writer, Level1, "Phase:        \t%s\n", podGroup.Status.Phase)
+	WriteLine(writer, Level1, "Min Member:   \t%d\n", podGroup.Spec.MinMember)
+	if podGroup.Spec.MinResources != nil {
+		WriteLine(writer, Level1, "Min Resources:\t%s\n", formatResources(*podGroup.Spec.MinResources))
+	}
+	WriteLine(writer, Level1, "Running:      \t%d\n", podGroup.Status.Running)
+	WriteLine(writer, Level1, "Succeeded:    \t%d\n", podGroup.Status.Succeeded)
+	WriteLine(writer, Level1, "Failed:       \t%d\n", podGroup.Status.Failed)
+	if len(podGroup.Status.Conditions) > 0 {
+		WriteLine(writer, Level1, "Conditions:\n    Type\tStatus\tReason\tTransitionTime\tMessage\n")
+		for _, c := range podGroup.Status.Conditions {
+			WriteLine(writer, Level2, "%s \t%s \t%s \t%s \t%s\n",
+				c.Type, c.Status, c.Reason, c.LastTransitionTime, strings.TrimSpace(c.Message))
+		}
+	}
+}
+
+// PrintQueueShare print the queue of the job, with the share of the queue allocated to the job, into writer.
+func PrintQueueShare(queue *v1beta1.Queue, pods []v1.Pod, writer io.Writer) {
+	if queue == nil {
+		WriteLine(writer, Level0, "Queue:      \t<none>\n")
+		return
+	}
+	jobAllocated := allocatedResources(pods)
+
+	WriteLine(writer, Level0, "Queue:\n")
+	WriteLine(writer, Level1, "Name:         \t%s\n", queue.Name)
+	WriteLine(writer, Level1, "State:        \t%s\n", queue.Status.State)
+	WriteLine(writer, Level1, "Weight:       \t%d\n", queue.Spec.Weight)
+	if len(queue.Spec.Capability) > 0 {
+		WriteLine(writer, Level1, "Capability:   \t%s\n", formatResources(queue.Spec.Capability))
+	}
+	if len(queue.Spec.Guarantee.Resource) > 0 {
+		WriteLine(writer, Level1, "Guarantee:    \t%s\n", formatResources(queue.Spec.Guarantee.Resource))
+	}
+	if len(queue.Spec.Deserved) > 0 {
+		WriteLine(writer, Level1, "Deserved:     \t%s\n", formatResources(queue.Spec.Deserved))
+	}
+	WriteLine(writer, Level1, "Allocated:    \t%s\n", formatResources(queue.Status.Allocated))
+	if len(queue.Spec.Deserved) > 0 {
+		WriteLine(writer, Level1, "Queue Share:  \t%s\n", formatShare(shareOf(queue.Status.Allocated, queue.Spec.Deserved)))
+	}
+	WriteLine(writer, Level1, "Job Allocated:\t%s\n", formatResources(jobAllocated))
+	WriteLine(writer, Level1, "Job Share:    \t%s\n", formatShare(shareOf(jobAllocated, queue.Status.Allocated)))
+}
+
+// PluginResource is a resource created for the job by one of its plugins.
+type PluginResource struct {
+	Plugin string
+	Kind   string
+	Name   string
+	Status string
+}
+
+// GetPluginResources get the resources created for the job by its plugins, and the volumes created by
+// the job controller.
+func GetPluginResources(ctx context.Context, kubeClient kubernetes.Interface, job *v1alpha1.Job) []PluginResource {
+	var resources []PluginResource
+	status := func(found string, err error) string {
+		if err == nil {
+			return found
+		}
+		if apierrors.IsNotFound(err) {
+			return "NotFound"
+		}
+		return err.Error()
+	}
+
+	plugins := make([]string, 0, len(job.Spec.Plugins))
+	for name := range job.Spec.Plugins {
+		plugins = append(plugins, name)
+	}
+	sort.Strings(plugins)
+	for _, plugin := range plugins {
+		switch plugin {
+		case "svc":
+			svc, err := kubeClient.CoreV1().Services(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+			clusterIP := ""
+			if err == nil {
+				clusterIP = svc.Spec.ClusterIP
+			}
+			resources = append(resources, PluginResource{Plugin: plugin, Kind: "Service", Name: job.Name,
+				Status: status("ClusterIP: "+clusterIP, err)})
+
+			cmName := fmt.Sprintf("%s-%s", job.Name, plugin)
+			_, err = kubeClient.CoreV1().ConfigMaps(job.Namespace).Get(ctx, cmName, metav1.GetOptions{})
+			resources = append(resources, PluginResource{Plugin: plugin, Kind: "ConfigMap", Name: cmName,
+				Status: status("Found", err)})
+
+			if !hasBoolArgument(job.Spec.Plugins[plugin], "disable-network-policy") {
+				_, err = kubeClient.NetworkingV1().NetworkPolicies(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+				resources = append(resources, PluginResource{Plugin: plugin, Kind: "NetworkPolicy", Name: job.Name,
+					Status: status("Found", err)})
+			}
+		case "ssh":
+			secretName := fmt.Sprintf("%s-%s", job.Name, plugin)
+			_, err := kubeClient.CoreV1().Secrets(job.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+			resources = append(resources, PluginResource{Plugin: plugin, Kind: "Secret", Name: secretName,
+				Status: status("Found", err)})
+		}
+	}
+
+	var claims []string
+	for key, name := range job.Status.ControlledResources {
+		if strings.HasPrefix(key, "volume-pvc-") {
+			claims = append(claims, name)
+		}
+	}
+	sort.Strings(claims)
+	for _, name := range claims {
+		pvc, err := kubeClient.CoreV1().PersistentVolumeClaims(job.Namespace).Get(ctx, name, metav1.GetOptions{})
+		phase := ""
+		if err == nil {
+			phase = string(pvc.Status.Phase)
+		}
+		resources = append(resources, PluginResource{Plugin: "volumes", Kind: "PersistentVolumeClaim", Name: name,
+			Status: status(phase, err)})
+	}
+
+	pdbs, err := kubeClient.PolicyV1().PodDisruptionBudgets(job.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{v1alpha1.JobNameKey: job.Name}).String(),
+	})
+	if err == nil {
+		for _, pdb := range pdbs.Items {
+			resources = append(resources, PluginResource{Plugin: "pdb", Kind: "PodDisruptionBudget", Name: pdb.Name,
+				Status: fmt.Sprintf("Allowed Disruptions: %d", pdb.Status.DisruptionsAllowed)})
+		}
+	}
+	return resources
+}
+
+// PrintPluginResources print the resources created for the job into writer.
+func PrintPluginResources(resources []PluginResource, writer io.Writer) {
+	if len(resources) == 0 {
+		WriteLine(writer, Level0, "Plugin Resources:\t<none>\n")
+		return
+	}
+	WriteLine(writer, Level0, "Plugin Resources:\n  Plugin\tKind\tName\tStatus\n")
+	for _, r := range resources {
+		WriteLine(writer, Level1, "%s \t%s \t%s \t%s\n", r.Plugin, r.Kind, r.Name, r.Status)
+	}
+}
+
+// GetJobEvents get the events of the job, of its podgroup and of its pods, oldest first.
+func GetJobEvents(ctx context.Context, kubeClient kubernetes.Interface, job *v1alpha1.Job, pods []v1.Pod) []v1.Event {
+	events, err := kubeClient.CoreV1().Events(job.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+
+	podNames := map[string]bool{}
+	for _, pod := range pods {
+		podNames[pod.Name] = true
+	}
+	podGroupName := PodGroupName(job)
+
+	var jobEvents []v1.Event
+	for _, e := range events.Items {
+		object := e.InvolvedObject
+		switch {
+		case object.Kind == "Job" && object.Name == job.Name,
+			object.Kind == "PodGroup" && object.Name == podGroupName,
+			object.Kind == "Pod" && podNames[object.Name],
+			object.Kind == "" && strings.HasPrefix(e.Name, job.Name+"."):
+			jobEvents = append(jobEvents, e)
+		}
+	}
+	sort.SliceStable(jobEvents, func(i, j int) bool {
+		return eventTime(&jobEvents[i]).Before(eventTime(&jobEvents[j]))
+	})
+	return jobEvents
+}
+
+func eventTime(e *v1.Event) metav1.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp
+	}
+	if !e.EventTime.IsZero() {
+		return metav1.Time{Time: e.EventTime.Time}
+	}
+	return e.FirstTimestamp
+}
+
+// allocatedResources returns the resources requested by the pods bound to a node and not terminated.
+func allocatedResources(pods []v1.Pod) v1.ResourceList {
+	allocated := v1.ResourceList{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			for name, quantity := range c.Resources.Requests {
+				total := allocated[name]
+				total.Add(quantity)
+				allocated[name] = total
+			}
+		}
+	}
+	return allocated
+}
+
+// shareOf returns the share of total used by every resource of total.
+func shareOf(used, total v1.ResourceList) map[v1.ResourceName]float64 {
+	share := map[v1.ResourceName]float64{}
+	for name, quantity := range total {
+		if quantity.IsZero() {
+			continue
+		}
+		u := used[name]
+		share[name] = float64(u.MilliValue()) / float64(quantity.MilliValue())
+	}
+	return share
+}
+
+func formatShare(share map[v1.ResourceName]float64) string {
+	if len(share) == 0 {
+		return "<none>"
+	}
+	names := make([]string, 0, len(share))
+	for name := range share {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %.1f%%", name, share[v1.ResourceName(name)]*100))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatResources(resources v1.ResourceList) string {
+	if len(resources) == 0 {
+		return "<none>"
+	}
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := resources[v1.ResourceName(name)]
+		parts = append(parts, fmt.Sprintf("%s: %s", name, quantity.String()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// hasBoolArgument returns whether the plugin arguments turn the boolean flag on.
+func hasBoolArgument(arguments []string, flag string) bool {
+	for _, argument := range arguments {
+		switch strings.TrimLeft(argument, "-") {
+		case flag, flag + "=true":
+			return true
+		}
+	}
+	return false
+}
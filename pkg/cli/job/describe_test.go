@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestGetPluginResources(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default"},
+		Spec: v1alpha1.JobSpec{
+			Plugins: map[string][]string{"svc": {"--disable-network-policy=true"}, "ssh": {}, "env": {}},
+		},
+		Status: v1alpha1.JobStatus{
+			ControlledResources: map[string]string{"plugin-svc": "svc", "volume-pvc-data": "data"},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default"},
+			Spec:       v1.ServiceSpec{ClusterIP: "None"},
+		},
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "job1-svc", Namespace: "default"}},
+		&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default"}},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+		},
+	)
+
+	expected := []PluginResource{
+		{Plugin: "ssh", Kind: "Secret", Name: "job1-ssh", Status: "NotFound"},
+		{Plugin: "svc", Kind: "Service", Name: "job1", Status: "ClusterIP: None"},
+		{Plugin: "svc", Kind: "ConfigMap", Name: "job1-svc", Status: "Found"},
+		{Plugin: "volumes", Kind: "PersistentVolumeClaim", Name: "data", Status: "Bound"},
+	}
+	resources := GetPluginResources(context.TODO(), kubeClient, job)
+	if !reflect.DeepEqual(resources, expected) {
+		t.Errorf("expected %v, got %v", expected, resources)
+	}
+}
+
+func TestGetJobEvents(t *testing.T) {
+	job := &v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default", UID: "uid"}}
+	pods := []v1.Pod{*buildJobPod("job1", "worker", "0")}
+	now := metav1.Now()
+	event := func(name, kind, objectName string, at metav1.Time) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: kind, Name: objectName},
+			LastTimestamp:  at,
+		}
+	}
+	kubeClient := fake.NewSimpleClientset(
+		event("job1.1", "Job", "job1", now),
+		event("job1-uid.1", "PodGroup", "job1-uid", metav1.NewTime(now.Add(-2e9))),
+		event("job1-worker-0.1", "Pod", "job1-worker-0", metav1.NewTime(now.Add(-1e9))),
+		event("job2.1", "Job", "job2", now),
+		event("job1-worker-1.1", "Pod", "job1-worker-1", now),
+	)
+
+	var names []string
+	for _, e := range GetJobEvents(context.TODO(), kubeClient, job, pods) {
+		names = append(names, e.Name)
+	}
+	expected := []string{"job1-uid.1", "job1-worker-0.1", "job1.1"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestShareOf(t *testing.T) {
+	pod := func(node string, phase v1.PodPhase, cpu string) v1.Pod {
+		return v1.Pod{
+			Spec: v1.PodSpec{
+				NodeName: node,
+				Containers: []v1.Container{{Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+				}}},
+			},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
+	pods := []v1.Pod{
+		pod("node1", v1.PodRunning, "1"),
+		pod("node1", v1.PodRunning, "500m"),
+		pod("", v1.PodPending, "1"),
+		pod("node2", v1.PodSucceeded, "1"),
+	}
+	queueAllocated := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+
+	share := formatShare(shareOf(allocatedResources(pods), queueAllocated))
+	if expected := "cpu: 25.0%, memory: 0.0%"; share != expected {
+		t.Errorf("expected share %q, got %q", expected, share)
+	}
+	if share := formatShare(shareOf(allocatedResources(pods), v1.ResourceList{})); share != "<none>" {
+		t.Errorf("expected no share, got %q", share)
+	}
+}
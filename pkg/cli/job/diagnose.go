@@ -0,0 +1,266 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+type diagnoseFlags struct {
+	util.CommonFlags
+
+	Namespace          string
+	JobName            string
+	File               string
+	SystemNamespace    string
+	ControllerSelector string
+	SchedulerSelector  string
+	Since              time.Duration
+	TailLines          int64
+}
+
+var diagnoseJobFlags = &diagnoseFlags{}
+
+// InitDiagnoseFlags init the diagnose command flags.
+func InitDiagnoseFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &diagnoseJobFlags.CommonFlags)
+
+	cmd.Flags().StringVarP(&diagnoseJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&diagnoseJobFlags.JobName, "name", "N", "", "the name of job, or given as the argument")
+	cmd.Flags().StringVarP(&diagnoseJobFlags.File, "file", "f", "", "the file of the bundle, <job>-diagnose-<time>.tar.gz if not set")
+	cmd.Flags().StringVarP(&diagnoseJobFlags.SystemNamespace, "system-namespace", "", "volcano-system", "the namespace of the volcano controllers and scheduler")
+	cmd.Flags().StringVarP(&diagnoseJobFlags.ControllerSelector, "controller-selector", "", "app=volcano-controller", "the label selector of the controller pods")
+	cmd.Flags().StringVarP(&diagnoseJobFlags.SchedulerSelector, "scheduler-selector", "", "app=volcano-scheduler", "the label selector of the scheduler pods")
+	cmd.Flags().DurationVarP(&diagnoseJobFlags.Since, "since", "", time.Hour, "only collect the logs more recent than the duration")
+	cmd.Flags().Int64VarP(&diagnoseJobFlags.TailLines, "tail", "", 10000, "the number of the most recent lines of the logs of each container to collect, all if negative")
+}
+
+// bundleFile is a file of the diagnostics bundle.
+type bundleFile struct {
+	Name string
+	Data []byte
+}
+
+// logSource is a component of volcano whose logs are collected.
+type logSource struct {
+	Name     string
+	Selector string
+}
+
+// DiagnoseJob collects the job, its pods, podgroup and queue, their events and the recent logs of the
+// controllers and the scheduler into a tarball, e.g. to attach to a support ticket.
+func DiagnoseJob(ctx context.Context, args []string) error {
+	config, err := util.BuildConfig(diagnoseJobFlags.Master, diagnoseJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		diagnoseJobFlags.JobName = args[0]
+	}
+	if diagnoseJobFlags.JobName == "" {
+		return fmt.Errorf("job name (specified by --name, -N or the argument) is mandatory to diagnose a particular job")
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	job, err := jobClient.BatchV1alpha1().Jobs(diagnoseJobFlags.Namespace).Get(ctx, diagnoseJobFlags.JobName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	sources := []logSource{
+		{Name: "controller", Selector: diagnoseJobFlags.ControllerSelector},
+		{Name: "scheduler", Selector: diagnoseJobFlags.SchedulerSelector},
+	}
+	files := collectDiagnostics(ctx, jobClient, kubeClient, job, sources)
+
+	now := time.Now()
+	dir := fmt.Sprintf("%s-diagnose-%s", job.Name, now.Format("20060102150405"))
+	file := diagnoseJobFlags.File
+	if file == "" {
+		file = dir + ".tar.gz"
+	}
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := writeBundle(out, dir, files, now); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("diagnostics of job %s/%s written to %s\n", job.Namespace, job.Name, file)
+	return nil
+}
+
+// collectDiagnostics collects the files of the bundle of the job. The objects are collected at best,
+// the ones which are not available are listed in errors.txt.
+func collectDiagnostics(ctx context.Context, jobClient versioned.Interface, kubeClient kubernetes.Interface,
+	job *v1alpha1.Job, sources []logSource) []bundleFile {
+	var files []bundleFile
+	var errs bytes.Buffer
+	addObject := func(name string, obj interface{}) {
+		var buf bytes.Buffer
+		if err := util.PrintObject(&buf, util.OutputYAML, obj); err != nil {
+			fmt.Fprintf(&errs, "%s: %v\n", name, err)
+			return
+		}
+		files = append(files, bundleFile{Name: name, Data: buf.Bytes()})
+	}
+
+	job = job.DeepCopy()
+	job.ManagedFields = nil
+	job.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	job.Kind = "Job"
+	addObject("job.yaml", job)
+
+	pods, err := listJobPods(ctx, kubeClient, job.Namespace, job.Name, "")
+	if err != nil {
+		fmt.Fprintf(&errs, "pods: %v\n", err)
+	}
+	podList := &v1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"}}
+	for _, pod := range pods {
+		pod.ManagedFields = nil
+		pod.APIVersion = "v1"
+		pod.Kind = "Pod"
+		podList.Items = append(podList.Items, pod)
+	}
+	addObject("pods.yaml", podList)
+	var summary bytes.Buffer
+	PrintPods(pods, &summary)
+	files = append(files, bundleFile{Name: "pods.txt", Data: summary.Bytes()})
+
+	podGroup, err := jobClient.SchedulingV1beta1().PodGroups(job.Namespace).Get(ctx, PodGroupName(job), metav1.GetOptions{})
+	if err != nil {
+		fmt.Fprintf(&errs, "podgroup: %v\n", err)
+	} else {
+		podGroup.ManagedFields = nil
+		podGroup.APIVersion = v1beta1.SchemeGroupVersion.String()
+		podGroup.Kind = "PodGroup"
+		addObject("podgroup.yaml", podGroup)
+	}
+
+	if job.Spec.Queue != "" {
+		queue, err := jobClient.SchedulingV1beta1().Queues().Get(ctx, job.Spec.Queue, metav1.GetOptions{})
+		if err != nil {
+			fmt.Fprintf(&errs, "queue: %v\n", err)
+		} else {
+			queue.ManagedFields = nil
+			queue.APIVersion = v1beta1.SchemeGroupVersion.String()
+			queue.Kind = "Queue"
+			addObject("queue.yaml", queue)
+		}
+	}
+
+	var events bytes.Buffer
+	PrintEvents(GetJobEvents(ctx, kubeClient, job, pods), &events)
+	files = append(files, bundleFile{Name: "events.txt", Data: events.Bytes()})
+
+	for _, source := range sources {
+		logs, err := collectLogs(ctx, kubeClient, source)
+		if err != nil {
+			fmt.Fprintf(&errs, "%s logs: %v\n", source.Name, err)
+		}
+		files = append(files, logs...)
+	}
+
+	if errs.Len() > 0 {
+		files = append(files, bundleFile{Name: "errors.txt", Data: errs.Bytes()})
+	}
+	return files
+}
+
+// collectLogs collects the recent logs of the containers of the pods of the component.
+func collectLogs(ctx context.Context, kubeClient kubernetes.Interface, source logSource) ([]bundleFile, error) {
+	pods, err := kubeClient.CoreV1().Pods(diagnoseJobFlags.SystemNamespace).List(ctx, metav1.ListOptions{LabelSelector: source.Selector})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []bundleFile
+	var errs []error
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			options := &v1.PodLogOptions{Container: container.Name}
+			if diagnoseJobFlags.Since > 0 {
+				since := int64(diagnoseJobFlags.Since.Seconds())
+				options.SinceSeconds = &since
+			}
+			if diagnoseJobFlags.TailLines >= 0 {
+				tail := diagnoseJobFlags.TailLines
+				options.TailLines = &tail
+			}
+			data, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).DoRaw(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s/%s: %v", pod.Name, container.Name, err))
+				continue
+			}
+			files = append(files, bundleFile{
+				Name: path.Join("logs", source.Name, fmt.Sprintf("%s_%s.log", pod.Name, container.Name)),
+				Data: data,
+			})
+		}
+	}
+	return files, utilerrors.NewAggregate(errs)
+}
+
+// writeBundle writes the files into a gzipped tarball, under the directory.
+func writeBundle(writer io.Writer, dir string, files []bundleFile, modTime time.Time) error {
+	gz := gzip.NewWriter(writer)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    path.Join(dir, file.Name),
+			Mode:    0644,
+			Size:    int64(len(file.Data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.Data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
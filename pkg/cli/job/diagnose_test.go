@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	vcfake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+)
+
+func TestCollectDiagnostics(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default", UID: "uid1"},
+		Spec:       v1alpha1.JobSpec{Queue: "q1"},
+	}
+	podGroup := &v1beta1.PodGroup{ObjectMeta: metav1.ObjectMeta{Name: PodGroupName(job), Namespace: "default"}}
+	controller := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "volcano-controllers-0",
+			Namespace: "volcano-system",
+			Labels:    map[string]string{"app": "volcano-controller"},
+		},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "volcano-controllers"}}},
+	}
+	kubeClient := kubefake.NewSimpleClientset(buildJobPod("job1", "worker", "0"), controller)
+	jobClient := vcfake.NewSimpleClientset(job, podGroup)
+	diagnoseJobFlags.SystemNamespace = "volcano-system"
+	diagnoseJobFlags.Since = time.Hour
+	diagnoseJobFlags.TailLines = 100
+
+	sources := []logSource{
+		{Name: "controller", Selector: "app=volcano-controller"},
+		{Name: "scheduler", Selector: "app=volcano-scheduler"},
+	}
+	files := collectDiagnostics(context.TODO(), jobClient, kubeClient, job, sources)
+
+	var names []string
+	contents := map[string]string{}
+	for _, file := range files {
+		names = append(names, file.Name)
+		contents[file.Name] = string(file.Data)
+	}
+	expected := []string{"job.yaml", "pods.yaml", "pods.txt", "podgroup.yaml", "events.txt",
+		"logs/controller/volcano-controllers-0_volcano-controllers.log", "errors.txt"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected files %v, got %v", expected, names)
+	}
+	if !strings.Contains(contents["pods.yaml"], "name: job1-worker-0") {
+		t.Errorf("expected the pods of the job in pods.yaml, got %s", contents["pods.yaml"])
+	}
+	if !strings.Contains(contents["job.yaml"], "kind: Job") {
+		t.Errorf("expected the kind of the job in job.yaml, got %s", contents["job.yaml"])
+	}
+	// the queue q1 does not exist
+	if !strings.HasPrefix(contents["errors.txt"], "queue: ") {
+		t.Errorf("expected the missing queue in errors.txt, got %s", contents["errors.txt"])
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	files := []bundleFile{
+		{Name: "job.yaml", Data: []byte("kind: Job\n")},
+		{Name: "logs/controller/c-0_c.log", Data: []byte("log line\n")},
+	}
+
+	var buf bytes.Buffer
+	if err := writeBundle(&buf, "job1-diagnose", files, time.Now()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("expected a gzip stream, got %v", err)
+	}
+	tr := tar.NewReader(gz)
+	got := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expected a tar stream, got %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		got[header.Name] = string(data)
+	}
+	expected := map[string]string{
+		"job1-diagnose/job.yaml":                  "kind: Job\n",
+		"job1-diagnose/logs/controller/c-0_c.log": "log line\n",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected bundle %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+type execFlags struct {
+	util.CommonFlags
+
+	Namespace string
+	JobName   string
+	TaskName  string
+	Index     int
+	Container string
+	Stdin     bool
+	TTY       bool
+}
+
+var execJobFlags = &execFlags{}
+
+// resizePeriod is how often the size of the terminal is checked to resize the TTY of the command.
+const resizePeriod = 250 * time.Millisecond
+
+// InitExecFlags init the exec command flags.
+func InitExecFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &execJobFlags.CommonFlags)
+
+	cmd.Flags().StringVarP(&execJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&execJobFlags.JobName, "name", "N", "", "the name of job, or given as the argument")
+	cmd.Flags().StringVarP(&execJobFlags.TaskName, "task", "", "", "the task of the replica, may be omitted if the job has a single task")
+	cmd.Flags().IntVarP(&execJobFlags.Index, "index", "", 0, "the index of the replica in its task")
+	cmd.Flags().StringVarP(&execJobFlags.Container, "container", "c", "", "the container to execute the command in, the first container of the pod if not set")
+	cmd.Flags().BoolVarP(&execJobFlags.Stdin, "stdin", "i", false, "pass stdin to the command")
+	cmd.Flags().BoolVarP(&execJobFlags.TTY, "tty", "t", false, "allocate a TTY for the command")
+}
+
+// ExecJob executes a command in the pod of a replica of the job, addressed by task and index.
+// The arguments are the job name, optionally, followed by "--" and the command.
+func ExecJob(ctx context.Context, args []string, argsLenAtDash int) error {
+	config, err := util.BuildConfig(execJobFlags.Master, execJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	command := args
+	if argsLenAtDash >= 0 {
+		command = args[argsLenAtDash:]
+		args = args[:argsLenAtDash]
+	} else if len(args) > 0 && execJobFlags.JobName == "" {
+		args, command = args[:1], args[1:]
+	}
+	if len(args) > 0 {
+		execJobFlags.JobName = args[0]
+	}
+	if execJobFlags.JobName == "" {
+		return fmt.Errorf("job name (specified by --name, -N or the argument) is mandatory to execute a command in a particular job")
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("command is mandatory, given after \"--\"")
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	pods, err := listJobPods(ctx, kubeClient, execJobFlags.Namespace, execJobFlags.JobName, "")
+	if err != nil {
+		return err
+	}
+	pod, err := findReplicaPod(pods, execJobFlags.TaskName, execJobFlags.Index)
+	if err != nil {
+		return fmt.Errorf("job %s/%s: %v", execJobFlags.Namespace, execJobFlags.JobName, err)
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		return fmt.Errorf("pod %s of job %s is %s, only running pods can execute commands",
+			pod.Name, execJobFlags.JobName, pod.Status.Phase)
+	}
+
+	container := execJobFlags.Container
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	var stdin io.Reader
+	if execJobFlags.Stdin {
+		stdin = os.Stdin
+	}
+	tty := execJobFlags.TTY
+	var sizeQueue remotecommand.TerminalSizeQueue
+	if tty {
+		fd := int(os.Stdin.Fd())
+		if !execJobFlags.Stdin || !term.IsTerminal(fd) {
+			fmt.Fprintln(os.Stderr, "Unable to use a TTY - input is not a terminal or the right kind of file")
+			tty = false
+		} else {
+			state, err := term.MakeRaw(fd)
+			if err != nil {
+				return err
+			}
+			defer term.Restore(fd, state)
+			queue := newTerminalSizeQueue(ctx, fd)
+			defer queue.stop()
+			sizeQueue = queue
+		}
+	}
+
+	options := &v1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdin:     stdin != nil,
+		Stdout:    true,
+		Stderr:    !tty,
+		TTY:       tty,
+	}
+	return execInPod(ctx, config, kubeClient, pod, options, remotecommand.StreamOptions{
+		Stdin:             stdin,
+		Stdout:            os.Stdout,
+		Stderr:            os.Stderr,
+		Tty:               tty,
+		TerminalSizeQueue: sizeQueue,
+	})
+}
+
+// findReplicaPod returns the pod of the replica of the task with the index.
+func findReplicaPod(pods []v1.Pod, taskName string, index int) (*v1.Pod, error) {
+	tasks := map[string]bool{}
+	for _, pod := range pods {
+		tasks[pod.Labels[v1alpha1.TaskSpecKey]] = true
+	}
+	if taskName == "" {
+		if len(tasks) > 1 {
+			var names []string
+			for name := range tasks {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("job has tasks %v, the task (specified by --task) is mandatory", names)
+		}
+		for name := range tasks {
+			taskName = name
+		}
+	}
+
+	for i := range pods {
+		if pods[i].Labels[v1alpha1.TaskSpecKey] == taskName && api.GetPodIndex(&pods[i]) == index {
+			return &pods[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no pod found for replica %d of task %q", index, taskName)
+}
+
+// terminalSizeQueue sends the size of the terminal to resize the TTY of the command, first its initial
+// size, then its size whenever it changes; the size is polled, as the terminals of all the platforms
+// do not signal their resize.
+type terminalSizeQueue struct {
+	sizes  chan remotecommand.TerminalSize
+	cancel context.CancelFunc
+}
+
+func newTerminalSizeQueue(ctx context.Context, fd int) *terminalSizeQueue {
+	ctx, cancel := context.WithCancel(ctx)
+	queue := &terminalSizeQueue{sizes: make(chan remotecommand.TerminalSize, 1), cancel: cancel}
+	go func() {
+		defer close(queue.sizes)
+		var last remotecommand.TerminalSize
+		ticker := time.NewTicker(resizePeriod)
+		defer ticker.Stop()
+		for {
+			if width, height, err := term.GetSize(fd); err == nil {
+				size := remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+				if size != last {
+					select {
+					case queue.sizes <- size:
+						last = size
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return queue
+}
+
+// Next implements remotecommand.TerminalSizeQueue, it returns nil once the queue is stopped.
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+func (q *terminalSizeQueue) stop() {
+	q.cancel()
+}
+
+// execInPod executes the command in the pod through the exec subresource as kubectl exec does, over
+// websockets, or over SPDY if the API server is not able to upgrade the connection to websockets.
+func execInPod(ctx context.Context, config *rest.Config, kubeClient kubernetes.Interface, pod *v1.Pod,
+	options *v1.PodExecOptions, streamOptions remotecommand.StreamOptions) error {
+	url := kubeClient.CoreV1().RESTClient().Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(options, scheme.ParameterCodec).
+		URL()
+
+	websocketExecutor, err := remotecommand.NewWebSocketExecutor(config, http.MethodGet, url.String())
+	if err != nil {
+		return err
+	}
+	spdyExecutor, err := remotecommand.NewSPDYExecutor(config, http.MethodPost, url)
+	if err != nil {
+		return err
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocketExecutor, spdyExecutor, httpstream.IsUpgradeFailure)
+	if err != nil {
+		return err
+	}
+	return execError(executor.StreamWithContext(ctx, streamOptions))
+}
+
+// execError returns an ExitError for the non-zero exit code of the command, or the error of the execution.
+func execError(err error) error {
+	var exitErr exec.CodeExitError
+	if errors.As(err, &exitErr) {
+		return &util.ExitError{Code: exitErr.ExitStatus()}
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"errors"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/exec"
+
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+func TestFindReplicaPod(t *testing.T) {
+	pods := []v1.Pod{
+		*buildJobPod("job1", "master", "0"),
+		*buildJobPod("job1", "worker", "0"),
+		*buildJobPod("job1", "worker", "1"),
+	}
+
+	testCases := []struct {
+		name     string
+		pods     []v1.Pod
+		task     string
+		index    int
+		expected string
+	}{
+		{
+			name:     "replica of the task",
+			pods:     pods,
+			task:     "worker",
+			index:    1,
+			expected: "job1-worker-1",
+		},
+		{
+			name:  "task is mandatory with several tasks",
+			pods:  pods,
+			index: 0,
+		},
+		{
+			name:     "task may be omitted with a single task",
+			pods:     pods[1:],
+			index:    1,
+			expected: "job1-worker-1",
+		},
+		{
+			name:  "index out of the replicas",
+			pods:  pods,
+			task:  "worker",
+			index: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod, err := findReplicaPod(tc.pods, tc.task, tc.index)
+			if tc.expected == "" {
+				if err == nil {
+					t.Errorf("expected error, got pod %s", pod.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pod.Name != tc.expected {
+				t.Errorf("expected pod %s, got %s", tc.expected, pod.Name)
+			}
+		})
+	}
+}
+
+func TestExecError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		exitCode int
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:     "non-zero exit code",
+			err:      exec.CodeExitError{Err: errors.New("command terminated with exit code 3"), Code: 3},
+			exitCode: 3,
+		},
+		{
+			name: "failure",
+			err:  errors.New("container not found"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := execError(tc.err)
+			if (err != nil) != (tc.err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			var exitErr *util.ExitError
+			if errors.As(err, &exitErr) != (tc.exitCode != 0) {
+				t.Fatalf("unexpected exit error %v", err)
+			}
+			if exitErr != nil && exitErr.Code != tc.exitCode {
+				t.Errorf("expected exit code %d, got %d", tc.exitCode, exitErr.Code)
+			}
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
@@ -40,6 +41,8 @@ type listFlags struct {
 	SchedulerName string
 	allNamespace  bool
 	selector      string
+	Output        string
+	AllClusters   bool
 }
 
 const (
@@ -76,6 +79,8 @@ const (
 	JobType string = "JobType"
 	// Namespace job namespace
 	Namespace string = "Namespace"
+	// Queue job queue
+	Queue string = "Queue"
 )
 
 var listJobFlags = &listFlags{}
@@ -89,17 +94,36 @@ func InitListFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&listJobFlags.SchedulerName, "scheduler", "S", "", "list job with specified scheduler name")
 	cmd.Flags().BoolVarP(&listJobFlags.allNamespace, "all-namespaces", "", false, "list jobs in all namespaces")
 	cmd.Flags().StringVarP(&listJobFlags.selector, "selector", "", "", "fuzzy matching jobName")
+	util.InitOutputFlag(cmd, &listJobFlags.Output, true)
+	util.InitAllClustersFlag(cmd, &listJobFlags.AllClusters)
 }
 
 // ListJobs lists all jobs details.
 func ListJobs(ctx context.Context) error {
-	config, err := util.BuildConfig(listJobFlags.Master, listJobFlags.Kubeconfig)
-	if err != nil {
+	if err := util.ValidateOutput(listJobFlags.Output, true); err != nil {
 		return err
 	}
 	if listJobFlags.allNamespace {
 		listJobFlags.Namespace = ""
 	}
+	if listJobFlags.AllClusters {
+		if err := util.ValidateAllClusters(listJobFlags.Master, listJobFlags.Output); err != nil {
+			return err
+		}
+		return util.ForEachContext(listJobFlags.Kubeconfig, os.Stdout, func(config *rest.Config) error {
+			return listJobs(ctx, config)
+		})
+	}
+
+	config, err := util.BuildConfig(listJobFlags.Master, listJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	return listJobs(ctx, config)
+}
+
+// listJobs lists the jobs of the cluster of the config.
+func listJobs(ctx context.Context, config *rest.Config) error {
 	jobClient := versioned.NewForConfigOrDie(config)
 	jobs, err := jobClient.BatchV1alpha1().Jobs(listJobFlags.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -124,6 +148,16 @@ func ListJobs(ctx context.Context) error {
 	}
 	filteredJobs := filterJobs(jobs, filterFunc)
 
+	if util.IsStructuredOutput(listJobFlags.Output) {
+		filteredJobs.APIVersion = "v1"
+		filteredJobs.Kind = "List"
+		for i := range filteredJobs.Items {
+			filteredJobs.Items[i].ManagedFields = nil
+			filteredJobs.Items[i].APIVersion = v1alpha1.SchemeGroupVersion.String()
+			filteredJobs.Items[i].Kind = "Job"
+		}
+		return util.PrintObject(os.Stdout, listJobFlags.Output, filteredJobs)
+	}
 	if len(filteredJobs.Items) == 0 {
 		fmt.Printf("No resources found\n")
 		return nil
@@ -133,20 +167,30 @@ func ListJobs(ctx context.Context) error {
 	return nil
 }
 
-// PrintJobs prints all jobs details.
+// PrintJobs prints all jobs details, with their queue and scheduler in the wide output format.
 func PrintJobs(jobs *v1alpha1.JobList, writer io.Writer) {
 	maxLenInfo := getMaxLen(jobs)
 
-	titleFormat := "%%-%ds%%-15s%%-12s%%-12s%%-12s%%-6s%%-10s%%-10s%%-12s%%-10s%%-12s%%-10s\n"
-	contentFormat := "%%-%ds%%-15s%%-12s%%-12s%%-12d%%-6d%%-10d%%-10d%%-12d%%-10d%%-12d%%-10d\n"
+	titleFormat := "%%-%ds%%-15s%%-12s%%-12s%%-12s%%-6s%%-10s%%-10s%%-12s%%-10s%%-12s%%-10s"
+	contentFormat := "%%-%ds%%-15s%%-12s%%-12s%%-12d%%-6d%%-10d%%-10d%%-12d%%-10d%%-12d%%-10d"
+	wide := listJobFlags.Output == util.OutputWide
+	if wide {
+		titleFormat += "%%-15s%%s"
+		contentFormat += "%%-15s%%s"
+	}
+	titleFormat += "\n"
+	contentFormat += "\n"
 
 	var err error
+	titles := []interface{}{Name, Creation, Phase, JobType, Replicas, Min, Pending, Running, Succeeded, Failed, Unknown, RetryCount}
+	if wide {
+		titles = append(titles, Queue, Scheduler)
+	}
 	if listJobFlags.allNamespace {
 		_, err = fmt.Fprintf(writer, fmt.Sprintf("%%-%ds"+titleFormat, maxLenInfo[1], maxLenInfo[0]),
-			Namespace, Name, Creation, Phase, JobType, Replicas, Min, Pending, Running, Succeeded, Failed, Unknown, RetryCount)
+			append([]interface{}{Namespace}, titles...)...)
 	} else {
-		_, err = fmt.Fprintf(writer, fmt.Sprintf(titleFormat, maxLenInfo[0]),
-			Name, Creation, Phase, JobType, Replicas, Min, Pending, Running, Succeeded, Failed, Unknown, RetryCount)
+		_, err = fmt.Fprintf(writer, fmt.Sprintf(titleFormat, maxLenInfo[0]), titles...)
 	}
 	if err != nil {
 		fmt.Printf("Failed to print list command result: %s.\n", err)
@@ -162,14 +206,16 @@ func PrintJobs(jobs *v1alpha1.JobList, writer io.Writer) {
 			jobType = "Batch"
 		}
 
+		values := []interface{}{job.Name, job.CreationTimestamp.Format("2006-01-02"), job.Status.State.Phase, jobType, replicas,
+			job.Status.MinAvailable, job.Status.Pending, job.Status.Running, job.Status.Succeeded, job.Status.Failed, job.Status.Unknown, job.Status.RetryCount}
+		if wide {
+			values = append(values, job.Spec.Queue, job.Spec.SchedulerName)
+		}
 		if listJobFlags.allNamespace {
 			_, err = fmt.Fprintf(writer, fmt.Sprintf("%%-%ds"+contentFormat, maxLenInfo[1], maxLenInfo[0]),
-				job.Namespace, job.Name, job.CreationTimestamp.Format("2006-01-02"), job.Status.State.Phase, jobType, replicas,
-				job.Status.MinAvailable, job.Status.Pending, job.Status.Running, job.Status.Succeeded, job.Status.Failed, job.Status.Unknown, job.Status.RetryCount)
+				append([]interface{}{job.Namespace}, values...)...)
 		} else {
-			_, err = fmt.Fprintf(writer, fmt.Sprintf(contentFormat, maxLenInfo[0]),
-				job.Name, job.CreationTimestamp.Format("2006-01-02"), job.Status.State.Phase, jobType, replicas,
-				job.Status.MinAvailable, job.Status.Pending, job.Status.Running, job.Status.Succeeded, job.Status.Failed, job.Status.Unknown, job.Status.RetryCount)
+			_, err = fmt.Fprintf(writer, fmt.Sprintf(contentFormat, maxLenInfo[0]), values...)
 		}
 		if err != nil {
 			fmt.Printf("Failed to print list command result: %s.\n", err)
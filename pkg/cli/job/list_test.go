@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/cli/util"
@@ -47,6 +48,7 @@ func TestListJob(t *testing.T) {
 		Selector       string
 		QueueName      string
 		Namespace      string
+		Output         string
 		ExpectedErr    error
 		ExpectedOutput string
 	}{
@@ -220,6 +222,27 @@ test-queue   0001-01-01                 Batch       0           0     0
 			ExpectedOutput: `Name       Creation       Phase       JobType     Replicas    Min   Pending   Running   Succeeded   Failed    Unknown     RetryCount
 test-job   0001-01-01                 Batch       0           0     0         0         0           0         0           0`,
 		},
+		{
+			Name:   "Normal Case with wide output",
+			Output: util.OutputWide,
+			Response: &v1alpha1.JobList{
+				Items: []v1alpha1.Job{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-job",
+							Namespace: "default",
+						},
+						Spec: v1alpha1.JobSpec{
+							Queue:         "default",
+							SchedulerName: "volcano",
+						},
+					},
+				},
+			},
+			ExpectedErr: nil,
+			ExpectedOutput: `Name       Creation       Phase       JobType     Replicas    Min   Pending   Running   Succeeded   Failed    Unknown     RetryCount  Queue          Scheduler
+test-job   0001-01-01                 Batch       0           0     0         0         0           0         0           0         default        volcano`,
+		},
 	}
 
 	for _, testcase := range testCases {
@@ -237,6 +260,7 @@ test-job   0001-01-01                 Batch       0           0     0         0
 				selector:      testcase.Selector,
 				SchedulerName: testcase.Scheduler,
 				QueueName:     testcase.QueueName,
+				Output:        testcase.Output,
 			}
 			r, oldStdout := util.RedirectStdout()
 			defer r.Close()
@@ -253,6 +277,43 @@ test-job   0001-01-01                 Batch       0           0     0         0
 	}
 }
 
+func TestListJobStructuredOutput(t *testing.T) {
+	server := util.CreateTestServer(&v1alpha1.JobList{
+		Items: []v1alpha1.Job{
+			{ObjectMeta: metav1.ObjectMeta{Name: "test-job", Namespace: "default"}},
+		},
+	})
+	defer server.Close()
+
+	for _, output := range []string{util.OutputJSON, util.OutputYAML} {
+		listJobFlags = &listFlags{
+			CommonFlags: util.CommonFlags{Master: server.URL},
+			Namespace:   "default",
+			Output:      output,
+		}
+		r, oldStdout := util.RedirectStdout()
+		err := ListJobs(context.TODO())
+		gotOutput := util.CaptureOutput(r, oldStdout)
+		r.Close()
+		if err != nil {
+			t.Fatalf("output %s: unexpected error: %v", output, err)
+		}
+
+		jobs := &v1alpha1.JobList{}
+		if err := yaml.Unmarshal([]byte(gotOutput), jobs); err != nil {
+			t.Fatalf("output %s: failed to parse %q: %v", output, gotOutput, err)
+		}
+		if jobs.Kind != "List" || len(jobs.Items) != 1 || jobs.Items[0].Name != "test-job" || jobs.Items[0].Kind != "Job" {
+			t.Errorf("output %s: unexpected jobs %v", output, jobs)
+		}
+	}
+
+	listJobFlags = &listFlags{Output: "table"}
+	if err := ListJobs(context.TODO()); err == nil {
+		t.Errorf("expected error for an unsupported output format")
+	}
+}
+
 func TestInitListFlags(t *testing.T) {
 	var cmd cobra.Command
 	InitListFlags(&cmd)
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+type logsFlags struct {
+	util.CommonFlags
+
+	Namespace string
+	JobName   string
+	TaskName  string
+	Container string
+	Follow    bool
+	TailLines int64
+	NoColor   bool
+}
+
+var logsJobFlags = &logsFlags{}
+
+// logColors are the ANSI colors of the pod prefixes, cycled through the pods of the job.
+var logColors = []string{"\033[36m", "\033[33m", "\033[32m", "\033[35m", "\033[34m", "\033[31m"}
+
+const logColorReset = "\033[0m"
+
+// InitLogsFlags init the logs command flags.
+func InitLogsFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &logsJobFlags.CommonFlags)
+
+	cmd.Flags().StringVarP(&logsJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&logsJobFlags.JobName, "name", "N", "", "the name of job, or given as the argument")
+	cmd.Flags().StringVarP(&logsJobFlags.TaskName, "task", "t", "", "only print the logs of the replicas of the task")
+	cmd.Flags().StringVarP(&logsJobFlags.Container, "container", "c", "", "the container to print the logs of, the first container of the pods if not set")
+	cmd.Flags().BoolVarP(&logsJobFlags.Follow, "follow", "f", false, "stream the logs as they are written")
+	cmd.Flags().Int64VarP(&logsJobFlags.TailLines, "tail", "", -1, "the number of the most recent lines of each replica to print, all if negative")
+	cmd.Flags().BoolVarP(&logsJobFlags.NoColor, "no-color", "", false, "do not color the pod prefixes")
+}
+
+// LogsJob prints the logs of all the replicas of the job, interleaved line by line with the pod name as prefix.
+func LogsJob(ctx context.Context, args []string) error {
+	config, err := util.BuildConfig(logsJobFlags.Master, logsJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		logsJobFlags.JobName = args[0]
+	}
+	if logsJobFlags.JobName == "" {
+		err := fmt.Errorf("job name (specified by --name, -N or the argument) is mandatory to print the logs of a particular job")
+		return err
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	return streamJobLogs(ctx, kubeClient, os.Stdout)
+}
+
+// listJobPods returns the pods of the job, or of one of its tasks, ordered by task name and index.
+func listJobPods(ctx context.Context, kubeClient kubernetes.Interface, namespace, jobName, taskName string) ([]v1.Pod, error) {
+	selector := map[string]string{v1alpha1.JobNameKey: jobName}
+	if taskName != "" {
+		selector[v1alpha1.TaskSpecKey] = taskName
+	}
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := pods.Items
+	sort.Slice(items, func(i, j int) bool {
+		li, ri := items[i].Labels[v1alpha1.TaskSpecKey], items[j].Labels[v1alpha1.TaskSpecKey]
+		if li != ri {
+			return li < ri
+		}
+		return api.GetPodIndex(&items[i]) < api.GetPodIndex(&items[j])
+	})
+	return items, nil
+}
+
+// lineWriter writes whole lines of the replicas, so that the lines of different replicas are not mixed.
+type lineWriter struct {
+	sync.Mutex
+	out io.Writer
+}
+
+func (w *lineWriter) writeLine(prefix, line string) {
+	w.Lock()
+	defer w.Unlock()
+	fmt.Fprintf(w.out, "%s %s\n", prefix, line)
+}
+
+func streamJobLogs(ctx context.Context, kubeClient kubernetes.Interface, out io.Writer) error {
+	pods, err := listJobPods(ctx, kubeClient, logsJobFlags.Namespace, logsJobFlags.JobName, logsJobFlags.TaskName)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods found for job %s/%s", logsJobFlags.Namespace, logsJobFlags.JobName)
+	}
+
+	writer := &lineWriter{out: out}
+	errs := make([]error, len(pods))
+	wg := sync.WaitGroup{}
+	for i := range pods {
+		pod := &pods[i]
+		prefix := "[" + pod.Name + "]"
+		if !logsJobFlags.NoColor {
+			prefix = logColors[i%len(logColors)] + prefix + logColorReset
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = streamPodLogs(ctx, kubeClient, pod, prefix, writer)
+		}(i)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			writer.writeLine("["+pods[i].Name+"]", fmt.Sprintf("failed to get logs: %v", err))
+			failed = append(failed, pods[i].Name)
+		}
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("failed to get logs of pods %v", failed)
+	}
+	return nil
+}
+
+func streamPodLogs(ctx context.Context, kubeClient kubernetes.Interface, pod *v1.Pod, prefix string, writer *lineWriter) error {
+	options := &v1.PodLogOptions{
+		Container: logsJobFlags.Container,
+		Follow:    logsJobFlags.Follow,
+	}
+	if options.Container == "" && len(pod.Spec.Containers) > 0 {
+		options.Container = pod.Spec.Containers[0].Name
+	}
+	if logsJobFlags.TailLines >= 0 {
+		tailLines := logsJobFlags.TailLines
+		options.TailLines = &tailLines
+	}
+
+	stream, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		writer.writeLine(prefix, scanner.Text())
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func buildJobPod(jobName, taskName, index string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName + "-" + taskName + "-" + index,
+			Namespace: "default",
+			Labels: map[string]string{
+				v1alpha1.JobNameKey:  jobName,
+				v1alpha1.TaskSpecKey: taskName,
+			},
+			Annotations: map[string]string{v1alpha1.TaskIndex: index},
+		},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+	}
+}
+
+func TestListJobPods(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		buildJobPod("job1", "worker", "10"),
+		buildJobPod("job1", "worker", "2"),
+		buildJobPod("job1", "master", "0"),
+		buildJobPod("job2", "worker", "0"),
+	)
+
+	testCases := []struct {
+		name     string
+		task     string
+		expected []string
+	}{
+		{
+			name:     "all the replicas ordered by task and index",
+			expected: []string{"job1-master-0", "job1-worker-2", "job1-worker-10"},
+		},
+		{
+			name:     "replicas of the task",
+			task:     "worker",
+			expected: []string{"job1-worker-2", "job1-worker-10"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pods, err := listJobPods(context.TODO(), kubeClient, "default", "job1", tc.task)
+			if err != nil {
+				t.Fatalf("failed to list pods: %v", err)
+			}
+			var names []string
+			for _, pod := range pods {
+				names = append(names, pod.Name)
+			}
+			if !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestStreamJobLogs(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		buildJobPod("job1", "worker", "0"),
+		buildJobPod("job1", "worker", "1"),
+	)
+	logsJobFlags.Namespace = "default"
+	logsJobFlags.JobName = "job1"
+	logsJobFlags.NoColor = true
+	logsJobFlags.TailLines = -1
+	defer func() { logsJobFlags = &logsFlags{} }()
+
+	out := &bytes.Buffer{}
+	if err := streamJobLogs(context.TODO(), kubeClient, out); err != nil {
+		t.Fatalf("failed to stream logs: %v", err)
+	}
+	// the fake clientset returns "fake logs" as the logs of every pod
+	for _, line := range []string{"[job1-worker-0] fake logs", "[job1-worker-1] fake logs"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected line %q, got %q", line, out.String())
+		}
+	}
+
+	logsJobFlags.JobName = "job2"
+	if err := streamJobLogs(context.TODO(), kubeClient, out); err == nil {
+		t.Errorf("expected error for a job without pods")
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+type rerunFlags struct {
+	util.CommonFlags
+
+	Namespace string
+	JobName   string
+	NewName   string
+	Queue     string
+	ImageTag  string
+	Overrides map[string]string
+	DryRun    bool
+}
+
+var rerunJobFlags = &rerunFlags{}
+
+// InitRerunFlags init rerun command flags.
+func InitRerunFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &rerunJobFlags.CommonFlags)
+
+	cmd.Flags().StringVarP(&rerunJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&rerunJobFlags.JobName, "name", "N", "", "the name of the finished job to run again, or given as the argument")
+	cmd.Flags().StringVarP(&rerunJobFlags.NewName, "new-name", "", "", "the name of the new job, generated from the name of job if not set")
+	cmd.Flags().StringVarP(&rerunJobFlags.Queue, "queue", "q", "", "the queue of the new job, the queue of job if not set")
+	cmd.Flags().StringVarP(&rerunJobFlags.ImageTag, "image-tag", "", "", "the image tag of all containers of the new job")
+	cmd.Flags().StringToStringVarP(&rerunJobFlags.Overrides, "set", "", nil,
+		"override a field of the spec of the new job by path, the elements of lists selected by name, e.g. tasks.worker.replicas=16")
+	cmd.Flags().BoolVarP(&rerunJobFlags.DryRun, "dry-run", "", false, "print the new job instead of creating it")
+}
+
+// RerunJob creates a new job from the spec of a finished job, with the overrides of the flags.
+func RerunJob(ctx context.Context, args []string) error {
+	config, err := util.BuildConfig(rerunJobFlags.Master, rerunJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		rerunJobFlags.JobName = args[0]
+	}
+	if rerunJobFlags.JobName == "" {
+		err := fmt.Errorf("job name is mandatory to rerun a particular job")
+		return err
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	job, err := jobClient.BatchV1alpha1().Jobs(rerunJobFlags.Namespace).Get(ctx, rerunJobFlags.JobName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if !isJobFinished(job) {
+		return fmt.Errorf("job %s is %s, only finished jobs can be run again", job.Name, job.Status.State.Phase)
+	}
+
+	rerun, err := newRerunJob(job)
+	if err != nil {
+		return err
+	}
+	if rerunJobFlags.DryRun {
+		rerun.APIVersion = vcbatch.SchemeGroupVersion.String()
+		rerun.Kind = "Job"
+		return util.PrintObject(os.Stdout, util.OutputYAML, rerun)
+	}
+
+	newJob, err := jobClient.BatchV1alpha1().Jobs(rerunJobFlags.Namespace).Create(ctx, rerun, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("rerun job %v as %v successfully\n", job.Name, newJob.Name)
+
+	return nil
+}
+
+// newRerunJob clones the finished job with the overrides of the flags.
+func newRerunJob(job *vcbatch.Job) (*vcbatch.Job, error) {
+	rerun := jobhelpers.NewRerunJob(job, rerunJobFlags.NewName)
+
+	overrides := map[string]string{}
+	for path, value := range rerunJobFlags.Overrides {
+		overrides[path] = value
+	}
+	if rerunJobFlags.Queue != "" {
+		overrides["queue"] = rerunJobFlags.Queue
+	}
+	if err := jobhelpers.SetRerunOverrides(rerun, overrides); err != nil {
+		return nil, err
+	}
+
+	if rerunJobFlags.ImageTag != "" {
+		setImageTag(rerun, rerunJobFlags.ImageTag)
+	}
+	return rerun, nil
+}
+
+func isJobFinished(job *vcbatch.Job) bool {
+	switch job.Status.State.Phase {
+	case vcbatch.Completed, vcbatch.Failed, vcbatch.Terminated, vcbatch.Aborted:
+		return true
+	}
+	return false
+}
+
+// setImageTag replaces the tag or digest of the images of all containers in the job.
+func setImageTag(job *vcbatch.Job, tag string) {
+	for i := range job.Spec.Tasks {
+		spec := &job.Spec.Tasks[i].Template.Spec
+		for j := range spec.InitContainers {
+			spec.InitContainers[j].Image = replaceImageTag(spec.InitContainers[j].Image, tag)
+		}
+		for j := range spec.Containers {
+			spec.Containers[j].Image = replaceImageTag(spec.Containers[j].Image, tag)
+		}
+	}
+}
+
+// replaceImageTag returns the image with its tag or digest replaced by tag, the registry port is kept.
+func replaceImageTag(image, tag string) string {
+	if index := strings.Index(image, "@"); index >= 0 {
+		image = image[:index]
+	}
+	if index := strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		image = image[:index]
+	}
+	return image + ":" + tag
+}
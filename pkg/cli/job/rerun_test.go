@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	v1alpha1batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestRerunJob(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Phase       v1alpha1batch.JobPhase
+		Args        []string
+		Overrides   map[string]string
+		ExpectError bool
+	}{
+		{
+			Name:        "rerun completed job",
+			Phase:       v1alpha1batch.Completed,
+			ExpectError: false,
+		},
+		{
+			Name:        "rerun job given as argument with overrides",
+			Phase:       v1alpha1batch.Failed,
+			Args:        []string{"testjob"},
+			Overrides:   map[string]string{"maxRetry": "5"},
+			ExpectError: false,
+		},
+		{
+			Name:        "rerun with unknown field",
+			Phase:       v1alpha1batch.Completed,
+			Overrides:   map[string]string{"maxRetries": "5"},
+			ExpectError: true,
+		},
+		{
+			Name:        "rerun running job",
+			Phase:       v1alpha1batch.Running,
+			ExpectError: true,
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			responsejob := v1alpha1batch.Job{}
+			responsejob.Name = "testjob"
+			responsejob.Status.State.Phase = testcase.Phase
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				val, err := json.Marshal(responsejob)
+				if err == nil {
+					w.Write(val)
+				}
+			})
+
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			rerunJobFlags.Master = server.URL
+			rerunJobFlags.Namespace = "test"
+			rerunJobFlags.JobName = ""
+			if testcase.Args == nil {
+				rerunJobFlags.JobName = "testjob"
+			}
+			rerunJobFlags.Overrides = testcase.Overrides
+
+			err := RerunJob(context.TODO(), testcase.Args)
+			if (err != nil) != testcase.ExpectError {
+				t.Errorf("expected error: %v, got %v", testcase.ExpectError, err)
+			}
+		})
+	}
+}
+
+func TestReplaceImageTag(t *testing.T) {
+	testCases := []struct {
+		Image       string
+		ExpectImage string
+	}{
+		{Image: "busybox", ExpectImage: "busybox:v2"},
+		{Image: "busybox:1.24", ExpectImage: "busybox:v2"},
+		{Image: "registry:5000/team/busybox", ExpectImage: "registry:5000/team/busybox:v2"},
+		{Image: "registry:5000/team/busybox:1.24", ExpectImage: "registry:5000/team/busybox:v2"},
+		{Image: "busybox@sha256:abcdef", ExpectImage: "busybox:v2"},
+	}
+
+	for _, testcase := range testCases {
+		if image := replaceImageTag(testcase.Image, "v2"); image != testcase.ExpectImage {
+			t.Errorf("expected image %s, got %s", testcase.ExpectImage, image)
+		}
+	}
+}
+
+func TestInitRerunFlags(t *testing.T) {
+	var cmd cobra.Command
+	InitRerunFlags(&cmd)
+
+	for _, name := range []string{"namespace", "name", "new-name", "queue", "image-tag", "set", "dry-run"} {
+		if cmd.Flag(name) == nil {
+			t.Errorf("Could not find the flag %s", name)
+		}
+	}
+}
@@ -56,5 +56,5 @@ func ResumeJob(ctx context.Context) error {
 
 	return createJobCommand(ctx, config,
 		resumeJobFlags.Namespace, resumeJobFlags.JobName,
-		v1alpha1.ResumeJobAction)
+		v1alpha1.ResumeJobAction, "", "")
 }
@@ -22,15 +22,20 @@ import (
 	"os"
 	"strings"
 
+	"github.com/google/shlex"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
 	"volcano.sh/volcano/pkg/cli/util"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/scheduler/api"
 )
 
 type runFlags struct {
@@ -46,6 +51,18 @@ type runFlags struct {
 	Limits        string
 	SchedulerName string
 	FileName      string
+
+	Queue   string
+	GPU     int
+	Plugins []string
+	Command string
+	// Template is the name of the JobTemplate to expand, with the Values of its parameters
+	Template string
+	Values   map[string]string
+	DryRun   bool
+
+	// flags tells which flags are given on the command line, nil in tests
+	flags *pflag.FlagSet
 }
 
 var launchJobFlags = &runFlags{}
@@ -63,6 +80,20 @@ func InitRunFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&launchJobFlags.Limits, "limits", "L", "cpu=1000m,memory=100Mi", "the resource limit of the task")
 	cmd.Flags().StringVarP(&launchJobFlags.SchedulerName, "scheduler", "S", "volcano", "the scheduler for this job")
 	cmd.Flags().StringVarP(&launchJobFlags.FileName, "filename", "f", "", "the yaml file of job")
+	cmd.Flags().StringVarP(&launchJobFlags.Queue, "queue", "q", "", "the queue of job")
+	cmd.Flags().IntVarP(&launchJobFlags.GPU, "gpu", "", 0, "the GPUs of every task replica")
+	cmd.Flags().StringSliceVarP(&launchJobFlags.Plugins, "plugin", "", nil,
+		"the plugins of job, e.g. ssh,svc, with their arguments given as svc=--publish-not-ready-addresses")
+	cmd.Flags().StringVarP(&launchJobFlags.Command, "command", "c", "", "the command of the task, e.g. \"python train.py --epochs 10\"")
+	cmd.Flags().StringVarP(&launchJobFlags.Template, "template", "t", "", "the JobTemplate to expand into the job")
+	cmd.Flags().StringToStringVarP(&launchJobFlags.Values, "set", "", nil, "the values of the parameters of the JobTemplate, e.g. workers=4")
+	cmd.Flags().BoolVarP(&launchJobFlags.DryRun, "dry-run", "", false, "print the job manifest instead of creating the job")
+	launchJobFlags.flags = cmd.Flags()
+}
+
+// changed returns whether the flag is given on the command line.
+func (f *runFlags) changed(name string) bool {
+	return f.flags != nil && f.flags.Changed(name)
 }
 
 var jobName = "job.volcano.sh"
@@ -78,6 +109,9 @@ func RunJob(ctx context.Context) error {
 		err = fmt.Errorf("job name cannot be left blank")
 		return err
 	}
+	if launchJobFlags.Template != "" && launchJobFlags.FileName != "" {
+		return fmt.Errorf("--template and --filename can not be used together")
+	}
 
 	req, err := populateResourceListV1(launchJobFlags.Requests)
 	if err != nil {
@@ -94,11 +128,28 @@ func RunJob(ctx context.Context) error {
 		return err
 	}
 
+	jobClient := versioned.NewForConfigOrDie(config)
+	if launchJobFlags.Template != "" {
+		if job, err = expandJobTemplate(ctx, jobClient, launchJobFlags); err != nil {
+			return err
+		}
+	}
+
 	if job == nil {
-		job = constructLaunchJobFlagsJob(launchJobFlags, req, limit)
+		if job, err = constructLaunchJobFlagsJob(launchJobFlags, req, limit); err != nil {
+			return err
+		}
+	}
+	if err := applyRunOverrides(job, launchJobFlags); err != nil {
+		return err
+	}
+
+	if launchJobFlags.DryRun {
+		job.APIVersion = vcbatch.SchemeGroupVersion.String()
+		job.Kind = "Job"
+		return util.PrintObject(os.Stdout, util.OutputYAML, job)
 	}
 
-	jobClient := versioned.NewForConfigOrDie(config)
 	newJob, err := jobClient.BatchV1alpha1().Jobs(launchJobFlags.Namespace).Create(ctx, job, metav1.CreateOptions{})
 	if err != nil {
 		return err
@@ -135,7 +186,27 @@ func readFile(filename string) (*vcbatch.Job, error) {
 	return &job, nil
 }
 
-func constructLaunchJobFlagsJob(launchJobFlags *runFlags, req, limit v1.ResourceList) *vcbatch.Job {
+func constructLaunchJobFlagsJob(launchJobFlags *runFlags, req, limit v1.ResourceList) (*vcbatch.Job, error) {
+	var command []string
+	if launchJobFlags.Command != "" {
+		var err error
+		if command, err = shlex.Split(launchJobFlags.Command); err != nil {
+			return nil, fmt.Errorf("invalid command %q: %v", launchJobFlags.Command, err)
+		}
+	}
+	if launchJobFlags.GPU > 0 {
+		gpu := *resource.NewQuantity(int64(launchJobFlags.GPU), resource.DecimalSI)
+		req, limit = req.DeepCopy(), limit.DeepCopy()
+		if req == nil {
+			req = v1.ResourceList{}
+		}
+		if limit == nil {
+			limit = v1.ResourceList{}
+		}
+		req[api.GPUResourceName] = gpu
+		limit[api.GPUResourceName] = gpu
+	}
+
 	return &vcbatch.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      launchJobFlags.Name,
@@ -159,6 +230,7 @@ func constructLaunchJobFlagsJob(launchJobFlags *runFlags, req, limit v1.Resource
 								{
 									Image:           launchJobFlags.Image,
 									Name:            launchJobFlags.Name,
+									Command:         command,
 									ImagePullPolicy: v1.PullIfNotPresent,
 									Resources: v1.ResourceRequirements{
 										Limits:   limit,
@@ -171,5 +243,54 @@ func constructLaunchJobFlagsJob(launchJobFlags *runFlags, req, limit v1.Resource
 				},
 			},
 		},
+	}, nil
+}
+
+// expandJobTemplate returns the job expanded from the JobTemplate with the values of its parameters.
+func expandJobTemplate(ctx context.Context, jobClient versioned.Interface, launchJobFlags *runFlags) (*vcbatch.Job, error) {
+	for _, name := range []string{"image", "replicas", "min", "requests", "limits", "gpu", "command"} {
+		if launchJobFlags.changed(name) {
+			return nil, fmt.Errorf("--%s can not be used with --template, set the parameters of the template with --set", name)
+		}
+	}
+
+	template, err := jobClient.FlowV1alpha1().JobTemplates(launchJobFlags.Namespace).Get(ctx, launchJobFlags.Template, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	spec, resolved, err := jobhelpers.ExpandJobTemplate(template, launchJobFlags.Values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand JobTemplate %s: %v", launchJobFlags.Template, err)
+	}
+
+	job := &vcbatch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      launchJobFlags.Name,
+			Namespace: launchJobFlags.Namespace,
+		},
+		Spec: *spec,
+	}
+	jobhelpers.SetTemplateValues(job, launchJobFlags.Template, resolved)
+	return job, nil
+}
+
+// applyRunOverrides sets the queue, the scheduler and the plugins given on the command line on the job.
+func applyRunOverrides(job *vcbatch.Job, launchJobFlags *runFlags) error {
+	if launchJobFlags.Queue != "" {
+		job.Spec.Queue = launchJobFlags.Queue
+	}
+	if launchJobFlags.Template != "" && launchJobFlags.changed("scheduler") {
+		job.Spec.SchedulerName = launchJobFlags.SchedulerName
 	}
+	for _, plugin := range launchJobFlags.Plugins {
+		name, arguments, _ := strings.Cut(plugin, "=")
+		if name == "" {
+			return fmt.Errorf("invalid plugin %q, expected <plugin> or <plugin>=<arguments>", plugin)
+		}
+		if job.Spec.Plugins == nil {
+			job.Spec.Plugins = map[string][]string{}
+		}
+		job.Spec.Plugins[name] = strings.Fields(arguments)
+	}
+	return nil
 }
@@ -22,13 +22,20 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 	"volcano.sh/volcano/pkg/cli/util"
 
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 func TestCreateJob(t *testing.T) {
@@ -118,3 +125,98 @@ func TestInitRunFlags(t *testing.T) {
 	}
 
 }
+
+func TestConstructLaunchJobFlagsJob(t *testing.T) {
+	flags := &runFlags{
+		Name:          "train",
+		Namespace:     "default",
+		Image:         "train:latest",
+		MinAvailable:  8,
+		Replicas:      8,
+		SchedulerName: "volcano",
+		Queue:         "team-a",
+		GPU:           1,
+		Plugins:       []string{"ssh", "svc=--publish-not-ready-addresses"},
+		Command:       `python train.py --name "my run"`,
+	}
+	req := v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")}
+
+	job, err := constructLaunchJobFlagsJob(flags, req, nil)
+	if err != nil {
+		t.Fatalf("failed to construct job: %v", err)
+	}
+	if err := applyRunOverrides(job, flags); err != nil {
+		t.Fatalf("failed to apply overrides: %v", err)
+	}
+
+	if job.Spec.Queue != "team-a" || job.Spec.MinAvailable != 8 || job.Spec.Tasks[0].Replicas != 8 {
+		t.Errorf("unexpected job spec %+v", job.Spec)
+	}
+	expectedPlugins := map[string][]string{"ssh": {}, "svc": {"--publish-not-ready-addresses"}}
+	if !reflect.DeepEqual(job.Spec.Plugins, expectedPlugins) {
+		t.Errorf("expected plugins %v, got %v", expectedPlugins, job.Spec.Plugins)
+	}
+	container := job.Spec.Tasks[0].Template.Spec.Containers[0]
+	if expected := []string{"python", "train.py", "--name", "my run"}; !reflect.DeepEqual(container.Command, expected) {
+		t.Errorf("expected command %v, got %v", expected, container.Command)
+	}
+	gpu := container.Resources.Limits["nvidia.com/gpu"]
+	if gpu.Value() != 1 || container.Resources.Requests.Cpu().Value() != 4 {
+		t.Errorf("unexpected resources %v", container.Resources)
+	}
+	if _, found := req["nvidia.com/gpu"]; found {
+		t.Errorf("the requests of the flags should not be modified")
+	}
+}
+
+func TestExpandJobTemplate(t *testing.T) {
+	template := &flowv1alpha1.JobTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "train",
+			Namespace: "default",
+			Annotations: map[string]string{
+				jobhelpers.TemplateParametersKey: `[{"name":"image","required":true}]`,
+			},
+		},
+	}
+	template.Spec.Tasks = []v1alpha1.TaskSpec{{
+		Name:     "worker",
+		Replicas: 2,
+		Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Image: "${image}"}}}},
+	}}
+	jobClient := fake.NewSimpleClientset(template)
+
+	testCases := []struct {
+		name      string
+		values    map[string]string
+		expectErr bool
+	}{
+		{
+			name:   "parameters given",
+			values: map[string]string{"image": "train:v2"},
+		},
+		{
+			name:      "required parameter missing",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := &runFlags{Name: "run1", Namespace: "default", Template: "train", Values: tc.values}
+			job, err := expandJobTemplate(context.TODO(), jobClient, flags)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if image := job.Spec.Tasks[0].Template.Spec.Containers[0].Image; image != "train:v2" {
+				t.Errorf("expected image train:v2, got %s", image)
+			}
+			if job.Name != "run1" || job.Annotations[jobhelpers.FromTemplateKey] != "train" {
+				t.Errorf("unexpected job metadata %+v", job.ObjectMeta)
+			}
+		})
+	}
+}
@@ -31,6 +31,8 @@ type suspendFlags struct {
 
 	Namespace string
 	JobName   string
+	Reason    string
+	Message   string
 }
 
 var suspendJobFlags = &suspendFlags{}
@@ -41,6 +43,8 @@ func InitSuspendFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringVarP(&suspendJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
 	cmd.Flags().StringVarP(&suspendJobFlags.JobName, "name", "N", "", "the name of job")
+	cmd.Flags().StringVarP(&suspendJobFlags.Reason, "reason", "", "", "the reason of the abort recorded in the job state, a CamelCase word, e.g. Maintenance")
+	cmd.Flags().StringVarP(&suspendJobFlags.Message, "message", "m", "", "the message of the abort recorded in the job state and events")
 }
 
 // SuspendJob suspends the job.
@@ -54,8 +58,11 @@ func SuspendJob(ctx context.Context) error {
 		err := fmt.Errorf("job name is mandatory to suspend a particular job")
 		return err
 	}
+	if err := validateReason(suspendJobFlags.Reason); err != nil {
+		return err
+	}
 
 	return createJobCommand(ctx, config,
 		suspendJobFlags.Namespace, suspendJobFlags.JobName,
-		v1alpha1.AbortJobAction)
+		v1alpha1.AbortJobAction, suspendJobFlags.Reason, suspendJobFlags.Message)
 }
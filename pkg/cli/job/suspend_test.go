@@ -88,5 +88,11 @@ func TestInitSuspendFlags(t *testing.T) {
 	if cmd.Flag("name") == nil {
 		t.Errorf("Could not find the flag name")
 	}
+	if cmd.Flag("reason") == nil {
+		t.Errorf("Could not find the flag reason")
+	}
+	if cmd.Flag("message") == nil {
+		t.Errorf("Could not find the flag message")
+	}
 
 }
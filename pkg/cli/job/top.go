@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+type topFlags struct {
+	util.CommonFlags
+
+	Namespace string
+	JobName   string
+	TaskName  string
+}
+
+var topJobFlags = &topFlags{}
+
+// InitTopFlags init the top command flags.
+func InitTopFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &topJobFlags.CommonFlags)
+
+	cmd.Flags().StringVarP(&topJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&topJobFlags.JobName, "name", "N", "", "the name of job, or given as the argument")
+	cmd.Flags().StringVarP(&topJobFlags.TaskName, "task", "t", "", "only show the replicas of the task")
+}
+
+// topUsage is the resource usage of a replica or of a task, with the resources requested. The requests of
+// a task only count the replicas with metrics, so that its percentages compare the usage to the requests
+// of the same replicas; the GPUs requested count all of them.
+type topUsage struct {
+	Task     string
+	Index    int
+	Pod      string
+	Replicas int
+
+	CPUUsage      int64 // millicores
+	CPURequest    int64 // millicores
+	MemoryUsage   int64 // bytes
+	MemoryRequest int64 // bytes
+	GPURequest    int64 // the metrics server does not report the usage of the GPUs
+	// HasMetrics is false if the metrics of the pod are not available (yet).
+	HasMetrics bool
+}
+
+// TopJob prints the resource usage of the replicas of the job, and of its tasks, reported by the metrics server.
+func TopJob(ctx context.Context, args []string) error {
+	config, err := util.BuildConfig(topJobFlags.Master, topJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		topJobFlags.JobName = args[0]
+	}
+	if topJobFlags.JobName == "" {
+		return fmt.Errorf("job name (specified by --name, -N or the argument) is mandatory to show the usage of a particular job")
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	metricsClient := metricsclientset.NewForConfigOrDie(config)
+	replicas, err := getJobUsage(ctx, kubeClient, metricsClient, topJobFlags.Namespace, topJobFlags.JobName, topJobFlags.TaskName)
+	if err != nil {
+		return err
+	}
+	if len(replicas) == 0 {
+		fmt.Printf("No resources found\n")
+		return nil
+	}
+	PrintJobUsage(replicas, os.Stdout)
+	return nil
+}
+
+// getJobUsage returns the usage of the running replicas of the job, ordered by task and index.
+func getJobUsage(ctx context.Context, kubeClient kubernetes.Interface, metricsClient metricsclientset.Interface,
+	namespace, jobName, taskName string) ([]topUsage, error) {
+	pods, err := listJobPods(ctx, kubeClient, namespace, jobName, taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := map[string]string{v1alpha1.JobNameKey: jobName}
+	if taskName != "" {
+		selector[v1alpha1.TaskSpecKey] = taskName
+	}
+	metrics, err := metricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the metrics of the pods, is the metrics server installed? %v", err)
+	}
+	podMetrics := map[string]*metricsv1beta1.PodMetrics{}
+	for i := range metrics.Items {
+		podMetrics[metrics.Items[i].Name] = &metrics.Items[i]
+	}
+
+	var replicas []topUsage
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		replicas = append(replicas, replicaUsage(pod, podMetrics[pod.Name]))
+	}
+	return replicas, nil
+}
+
+// replicaUsage returns the usage of the pod of a replica, reported by its metrics if any.
+func replicaUsage(pod *v1.Pod, metrics *metricsv1beta1.PodMetrics) topUsage {
+	usage := topUsage{
+		Task:     pod.Labels[v1alpha1.TaskSpecKey],
+		Index:    api.GetPodIndex(pod),
+		Pod:      pod.Name,
+		Replicas: 1,
+	}
+	for _, c := range pod.Spec.Containers {
+		usage.CPURequest += c.Resources.Requests.Cpu().MilliValue()
+		usage.MemoryRequest += c.Resources.Requests.Memory().Value()
+		gpu := c.Resources.Requests[api.GPUResourceName]
+		usage.GPURequest += gpu.Value()
+	}
+	if metrics != nil {
+		usage.HasMetrics = true
+		for _, c := range metrics.Containers {
+			usage.CPUUsage += c.Usage.Cpu().MilliValue()
+			usage.MemoryUsage += c.Usage.Memory().Value()
+		}
+	}
+	return usage
+}
+
+// aggregateTaskUsage sums the usage of the replicas by task, the tasks ordered as the replicas.
+func aggregateTaskUsage(replicas []topUsage) []topUsage {
+	var tasks []topUsage
+	index := map[string]int{}
+	for _, r := range replicas {
+		i, found := index[r.Task]
+		if !found {
+			i = len(tasks)
+			index[r.Task] = i
+			tasks = append(tasks, topUsage{Task: r.Task})
+		}
+		t := &tasks[i]
+		t.Replicas++
+		t.GPURequest += r.GPURequest
+		if !r.HasMetrics {
+			continue
+		}
+		t.HasMetrics = true
+		t.CPUUsage += r.CPUUsage
+		t.CPURequest += r.CPURequest
+		t.MemoryUsage += r.MemoryUsage
+		t.MemoryRequest += r.MemoryRequest
+	}
+	return tasks
+}
+
+// PrintJobUsage prints the usage of the replicas and of the tasks into writer.
+func PrintJobUsage(replicas []topUsage, writer io.Writer) {
+	WriteLine(writer, Level0, "%-30s%-15s%-8s%-12s%-10s%-14s%-10s%s\n",
+		"Pod", "Task", "Index", "CPU", "CPU%", "Memory", "Memory%", "GPU(requests)")
+	for _, r := range replicas {
+		cpu, cpuPercent, memory, memoryPercent := formatUsage(r)
+		WriteLine(writer, Level0, "%-30s%-15s%-8d%-12s%-10s%-14s%-10s%d\n",
+			r.Pod, r.Task, r.Index, cpu, cpuPercent, memory, memoryPercent, r.GPURequest)
+	}
+
+	WriteLine(writer, Level0, "\n%-30s%-15s%-8s%-12s%-10s%-14s%-10s%s\n",
+		"Task", "Replicas", "", "CPU", "CPU%", "Memory", "Memory%", "GPU(requests)")
+	for _, t := range aggregateTaskUsage(replicas) {
+		cpu, cpuPercent, memory, memoryPercent := formatUsage(t)
+		WriteLine(writer, Level0, "%-30s%-15d%-8s%-12s%-10s%-14s%-10s%d\n",
+			t.Task, t.Replicas, "", cpu, cpuPercent, memory, memoryPercent, t.GPURequest)
+	}
+}
+
+// formatUsage formats the usage, and the percentage of the requests it uses.
+func formatUsage(u topUsage) (cpu, cpuPercent, memory, memoryPercent string) {
+	if !u.HasMetrics {
+		return "<unknown>", "<unknown>", "<unknown>", "<unknown>"
+	}
+	percent := func(usage, request int64) string {
+		if request == 0 {
+			return "<none>"
+		}
+		return fmt.Sprintf("%d%%", usage*100/request)
+	}
+	return fmt.Sprintf("%dm", u.CPUUsage), percent(u.CPUUsage, u.CPURequest),
+		fmt.Sprintf("%dMi", u.MemoryUsage/(1024*1024)), percent(u.MemoryUsage, u.MemoryRequest)
+}
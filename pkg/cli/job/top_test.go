@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func buildRunningJobPod(taskName, index, cpu, memory string) *v1.Pod {
+	pod := buildJobPod("job1", taskName, index)
+	pod.Status.Phase = v1.PodRunning
+	pod.Spec.Containers[0].Resources.Requests = v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}
+	return pod
+}
+
+func buildPodMetrics(pod *v1.Pod, cpu, memory string) *metricsv1beta1.PodMetrics {
+	return &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace, Labels: pod.Labels},
+		Containers: []metricsv1beta1.ContainerMetrics{{
+			Name: "main",
+			Usage: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+			},
+		}},
+	}
+}
+
+func TestGetJobUsage(t *testing.T) {
+	master := buildRunningJobPod("master", "0", "1", "1Gi")
+	worker0 := buildRunningJobPod("worker", "0", "2", "4Gi")
+	worker1 := buildRunningJobPod("worker", "1", "2", "4Gi")
+	pending := buildJobPod("job1", "worker", "2")
+
+	kubeClient := fake.NewSimpleClientset(master, worker0, worker1, pending)
+	metricsClient := metricsfake.NewSimpleClientset(
+		buildPodMetrics(master, "500m", "512Mi"),
+		buildPodMetrics(worker0, "1", "1Gi"),
+	)
+
+	replicas, err := getJobUsage(context.TODO(), kubeClient, metricsClient, "default", "job1", "")
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	var pods []string
+	for _, r := range replicas {
+		pods = append(pods, r.Pod)
+	}
+	if expected := []string{"job1-master-0", "job1-worker-0", "job1-worker-1"}; !reflect.DeepEqual(pods, expected) {
+		t.Fatalf("expected replicas %v, got %v", expected, pods)
+	}
+	if replicas[1].CPUUsage != 1000 || replicas[1].CPURequest != 2000 || replicas[2].HasMetrics {
+		t.Errorf("unexpected usage of the workers %+v", replicas[1:])
+	}
+
+	tasks := aggregateTaskUsage(replicas)
+	expected := []topUsage{
+		{Task: "master", Replicas: 1, CPUUsage: 500, CPURequest: 1000, MemoryUsage: 512 << 20, MemoryRequest: 1 << 30, HasMetrics: true},
+		{Task: "worker", Replicas: 2, CPUUsage: 1000, CPURequest: 2000, MemoryUsage: 1 << 30, MemoryRequest: 4 << 30, HasMetrics: true},
+	}
+	if !reflect.DeepEqual(tasks, expected) {
+		t.Errorf("expected tasks %+v, got %+v", expected, tasks)
+	}
+
+	out := &bytes.Buffer{}
+	PrintJobUsage(replicas, out)
+	for _, line := range []string{
+		"job1-master-0                 master         0       500m        50%       512Mi         50%       0",
+		"job1-worker-1                 worker         1       <unknown>   <unknown> <unknown>     <unknown> 0",
+		"worker                        2                      1000m       50%       1024Mi        25%       0",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected line %q in\n%s", line, out.String())
+		}
+	}
+}
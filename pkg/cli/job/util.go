@@ -19,6 +19,7 @@ package job
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -26,8 +27,10 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	vcbus "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/apis/helpers"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
@@ -59,7 +62,20 @@ func populateResourceListV1(spec string) (v1.ResourceList, error) {
 	return result, nil
 }
 
-func createJobCommand(ctx context.Context, config *rest.Config, ns, name string, action vcbus.Action) error {
+// reasonPattern matches the reasons given to the commands, which are recorded as event reasons, e.g. Maintenance.
+var reasonPattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// validateReason checks the reason given to a command.
+func validateReason(reason string) error {
+	if reason != "" && !reasonPattern.MatchString(reason) {
+		return fmt.Errorf("invalid reason %q, expected a CamelCase word, e.g. Maintenance", reason)
+	}
+	return nil
+}
+
+// createJobCommand creates the command of the action on the job, with the reason and the message
+// recorded by the job controller when executing it.
+func createJobCommand(ctx context.Context, config *rest.Config, ns, name string, action vcbus.Action, reason, message string) error {
 	jobClient := versioned.NewForConfigOrDie(config)
 	job, err := jobClient.BatchV1alpha1().Jobs(ns).Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
@@ -78,6 +94,8 @@ func createJobCommand(ctx context.Context, config *rest.Config, ns, name string,
 		},
 		TargetObject: ctrlRef,
 		Action:       string(action),
+		Reason:       reason,
+		Message:      message,
 	}
 
 	if _, err := jobClient.BusV1alpha1().Commands(ns).Create(ctx, cmd, metav1.CreateOptions{}); err != nil {
@@ -87,6 +105,34 @@ func createJobCommand(ctx context.Context, config *rest.Config, ns, name string,
 	return nil
 }
 
+// recordJobEvent records an event of vcctl on the job, e.g. why it was deleted.
+func recordJobEvent(ctx context.Context, kubeClient kubernetes.Interface, job *vcbatch.Job, reason, message string) error {
+	now := metav1.Now()
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", job.Name, now.UnixNano()),
+			Namespace: job.Namespace,
+		},
+		InvolvedObject: v1.ObjectReference{
+			APIVersion:      helpers.JobKind.GroupVersion().String(),
+			Kind:            helpers.JobKind.Kind,
+			Namespace:       job.Namespace,
+			Name:            job.Name,
+			UID:             job.UID,
+			ResourceVersion: job.ResourceVersion,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           v1.EventTypeNormal,
+		Source:         v1.EventSource{Component: "vcctl"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	_, err := kubeClient.CoreV1().Events(job.Namespace).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
+
 func translateTimestampSince(timestamp metav1.Time) string {
 	if timestamp.IsZero() {
 		return "<unknown>"
@@ -108,3 +108,37 @@ func TestJobUtil(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateReason(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Reason    string
+		ExpectErr bool
+	}{
+		{
+			Name:   "EmptyReason",
+			Reason: "",
+		},
+		{
+			Name:   "CamelCaseReason",
+			Reason: "QuotaExceeded",
+		},
+		{
+			Name:      "ReasonWithSpaces",
+			Reason:    "quota exceeded",
+			ExpectErr: true,
+		},
+		{
+			Name:      "LowerCaseReason",
+			Reason:    "maintenance",
+			ExpectErr: true,
+		},
+	}
+
+	for i, testcase := range testCases {
+		err := validateReason(testcase.Reason)
+		if (err != nil) != testcase.ExpectErr {
+			t.Errorf("case %d (%s): expected error: %v, got %v", i, testcase.Name, testcase.ExpectErr, err)
+		}
+	}
+}
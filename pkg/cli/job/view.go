@@ -22,18 +22,22 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	coreV1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
 	"volcano.sh/volcano/pkg/cli/util"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 type viewFlags struct {
@@ -41,6 +45,7 @@ type viewFlags struct {
 
 	Namespace string
 	JobName   string
+	Output    string
 }
 
 // level of print indent.
@@ -58,9 +63,10 @@ func InitViewFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringVarP(&viewJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
 	cmd.Flags().StringVarP(&viewJobFlags.JobName, "name", "N", "", "the name of job")
+	util.InitOutputFlag(cmd, &viewJobFlags.Output, false)
 }
 
-// ViewJob gives full details of the job.
+// ViewJob gives full details of the job, and returns an ExitError if the job did not complete.
 func ViewJob(ctx context.Context) error {
 	config, err := util.BuildConfig(viewJobFlags.Master, viewJobFlags.Kubeconfig)
 	if err != nil {
@@ -70,6 +76,9 @@ func ViewJob(ctx context.Context) error {
 		err := fmt.Errorf("job name (specified by --name or -N) is mandatory to view a particular job")
 		return err
 	}
+	if err := util.ValidateOutput(viewJobFlags.Output, false); err != nil {
+		return err
+	}
 
 	jobClient := versioned.NewForConfigOrDie(config)
 	job, err := jobClient.BatchV1alpha1().Jobs(viewJobFlags.Namespace).Get(ctx, viewJobFlags.JobName, metav1.GetOptions{})
@@ -80,9 +89,44 @@ func ViewJob(ctx context.Context) error {
 		fmt.Printf("No resources found\n")
 		return nil
 	}
+	if util.IsStructuredOutput(viewJobFlags.Output) {
+		job.ManagedFields = nil
+		if job.APIVersion == "" || job.Kind == "" {
+			job.APIVersion = v1alpha1.SchemeGroupVersion.String()
+			job.Kind = "Job"
+		}
+		if err := util.PrintObject(os.Stdout, viewJobFlags.Output, job); err != nil {
+			return err
+		}
+		return util.JobExitError(job)
+	}
 	PrintJobInfo(job, os.Stdout)
-	PrintEvents(GetEvents(ctx, config, job), os.Stdout)
-	return nil
+
+	// the related objects are described at best, the job is described even if they are not available
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	pods, err := listJobPods(ctx, kubeClient, job.Namespace, job.Name, "")
+	if err != nil {
+		fmt.Printf("Failed to list the pods of the job: %v\n", err)
+	}
+	PrintPods(pods, os.Stdout)
+
+	podGroup, err := jobClient.SchedulingV1beta1().PodGroups(job.Namespace).Get(ctx, PodGroupName(job), metav1.GetOptions{})
+	if err != nil {
+		podGroup = nil
+	}
+	PrintPodGroup(podGroup, os.Stdout)
+
+	var queue *v1beta1.Queue
+	if job.Spec.Queue != "" {
+		if queue, err = jobClient.SchedulingV1beta1().Queues().Get(ctx, job.Spec.Queue, metav1.GetOptions{}); err != nil {
+			queue = nil
+		}
+	}
+	PrintQueueShare(queue, pods, os.Stdout)
+
+	PrintPluginResources(GetPluginResources(ctx, kubeClient, job), os.Stdout)
+	PrintEvents(GetJobEvents(ctx, kubeClient, job, pods), os.Stdout)
+	return util.JobExitError(job)
 }
 
 // PrintJobInfo print the job detailed info into writer.
@@ -188,11 +232,61 @@ func PrintJobInfo(job *v1alpha1.Job, writer io.Writer) {
 		WriteLine(writer, Level1, "Version:      \t%d\n", job.Status.Version)
 	}
 
+	if startTime := jobhelpers.GetJobStartTime(job); startTime != nil {
+		WriteLine(writer, Level1, "Start Time:     \t%s\n", startTime.Format(time.RFC3339))
+	}
+	if completionTime := jobhelpers.GetJobCompletionTime(job); completionTime != nil {
+		WriteLine(writer, Level1, "Completion Time:\t%s\n", completionTime.Format(time.RFC3339))
+	}
+	if job.Status.RunningDuration != nil {
+		WriteLine(writer, Level1, "Duration:       \t%s\n", job.Status.RunningDuration.Duration.Round(time.Second))
+	}
+
 	WriteLine(writer, Level1, "State:\n")
 	WriteLine(writer, Level2, "Phase:\t%s\n", job.Status.State.Phase)
+	if job.Status.State.Reason != "" {
+		WriteLine(writer, Level2, "Reason:\t%s\n", job.Status.State.Reason)
+	}
+	if job.Status.State.Message != "" {
+		WriteLine(writer, Level2, "Message:\t%s\n", job.Status.State.Message)
+	}
+	if replicaStatus := jobhelpers.GetReplicaStatus(job); len(replicaStatus) > 0 {
+		WriteLine(writer, Level1, "Replicas:\n    Task\tIndex\tPhase\tNode\tRestarts\tExit Code\n")
+		taskNames := make([]string, 0, len(replicaStatus))
+		for taskName := range replicaStatus {
+			taskNames = append(taskNames, taskName)
+		}
+		sort.Strings(taskNames)
+		for _, taskName := range taskNames {
+			indexes := make([]int, 0, len(replicaStatus[taskName]))
+			for index := range replicaStatus[taskName] {
+				if i, err := strconv.Atoi(index); err == nil {
+					indexes = append(indexes, i)
+				}
+			}
+			sort.Ints(indexes)
+			for _, index := range indexes {
+				status := replicaStatus[taskName][strconv.Itoa(index)]
+				exitCode := "<none>"
+				if status.ExitCode != nil {
+					exitCode = strconv.Itoa(int(*status.ExitCode))
+				}
+				nodeName := status.NodeName
+				if nodeName == "" {
+					nodeName = "<none>"
+				}
+				WriteLine(writer, Level2, "%s \t%d \t%s \t%s \t%d \t%s\n",
+					taskName, index, status.Phase, nodeName, status.Restarts, exitCode)
+			}
+		}
+	}
 	if len(job.Status.ControlledResources) > 0 {
 		WriteLine(writer, Level1, "Controlled Resources:\n")
 		for key, value := range job.Status.ControlledResources {
+			// the replica status is printed above, and the accounted pods are only kept for the accounting
+			if key == jobhelpers.ReplicaStatusKey || key == jobhelpers.AccountedPodsKey {
+				continue
+			}
 			WriteLine(writer, Level2, "%s: \t%s\n", key, value)
 		}
 	}
@@ -209,8 +303,8 @@ func PrintJobInfo(job *v1alpha1.Job, writer io.Writer) {
 // PrintEvents print event info to writer.
 func PrintEvents(events []coreV1.Event, writer io.Writer) {
 	if len(events) > 0 {
-		WriteLine(writer, Level0, "%s:\n%-15s\t%-40s\t%-30s\t%-40s\t%s\n", "Events", "Type", "Reason", "Age", "Form", "Message")
-		WriteLine(writer, Level0, "%-15s\t%-40s\t%-30s\t%-40s\t%s\n", "-------", "-------", "-------", "-------", "-------")
+		WriteLine(writer, Level0, "%s:\n%-15s\t%-40s\t%-30s\t%-40s\t%-40s\t%s\n", "Events", "Type", "Reason", "Age", "Form", "Object", "Message")
+		WriteLine(writer, Level0, "%-15s\t%-40s\t%-30s\t%-40s\t%-40s\t%s\n", "-------", "-------", "-------", "-------", "-------", "-------")
 		for _, e := range events {
 			var interval string
 			if e.Count > 1 {
@@ -222,11 +316,16 @@ func PrintEvents(events []coreV1.Event, writer io.Writer) {
 			if len(e.Source.Host) > 0 {
 				EventSourceString = append(EventSourceString, e.Source.Host)
 			}
-			WriteLine(writer, Level0, "%-15v\t%-40v\t%-30s\t%-40s\t%v\n",
+			object := "<none>"
+			if e.InvolvedObject.Kind != "" {
+				object = strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name
+			}
+			WriteLine(writer, Level0, "%-15v\t%-40v\t%-30s\t%-40s\t%-40s\t%v\n",
 				e.Type,
 				e.Reason,
 				interval,
 				strings.Join(EventSourceString, ", "),
+				object,
 				strings.TrimSpace(e.Message),
 			)
 		}
@@ -235,23 +334,6 @@ func PrintEvents(events []coreV1.Event, writer io.Writer) {
 	}
 }
 
-// GetEvents get the job event by config.
-func GetEvents(ctx context.Context, config *rest.Config, job *v1alpha1.Job) []coreV1.Event {
-	kubernetes, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		fmt.Printf("%v\n", err)
-		return nil
-	}
-	events, _ := kubernetes.CoreV1().Events(viewJobFlags.Namespace).List(ctx, metav1.ListOptions{})
-	var jobEvents []coreV1.Event
-	for _, v := range events.Items {
-		if strings.HasPrefix(v.ObjectMeta.Name, job.Name+".") {
-			jobEvents = append(jobEvents, v)
-		}
-	}
-	return jobEvents
-}
-
 // WriteLine write lines with specified indent.
 func WriteLine(writer io.Writer, spaces int, content string, params ...interface{}) {
 	prefix := ""
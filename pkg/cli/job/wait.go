@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+type waitFlags struct {
+	util.CommonFlags
+
+	Namespace string
+	JobName   string
+	For       string
+	Timeout   time.Duration
+}
+
+var waitJobFlags = &waitFlags{}
+
+// the phases a job waited for can reach.
+var waitPhases = []v1alpha1.JobPhase{
+	v1alpha1.Pending, v1alpha1.Running, v1alpha1.Restarting, v1alpha1.Completing, v1alpha1.Completed,
+	v1alpha1.Terminating, v1alpha1.Terminated, v1alpha1.Aborting, v1alpha1.Aborted, v1alpha1.Failed,
+}
+
+// InitWaitFlags init the wait command flags.
+func InitWaitFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &waitJobFlags.CommonFlags)
+
+	cmd.Flags().StringVarP(&waitJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&waitJobFlags.JobName, "name", "N", "", "the name of job, or given as the argument")
+	cmd.Flags().StringVarP(&waitJobFlags.For, "for", "", string(v1alpha1.Completed), "the phase of the job to wait for")
+	cmd.Flags().DurationVarP(&waitJobFlags.Timeout, "timeout", "", 0, "the duration to wait for, never times out if 0")
+}
+
+// WaitJob blocks until the job reaches the phase, and returns an ExitError if the job ends in another
+// phase or the wait times out.
+func WaitJob(ctx context.Context, args []string) error {
+	config, err := util.BuildConfig(waitJobFlags.Master, waitJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		waitJobFlags.JobName = args[0]
+	}
+	if waitJobFlags.JobName == "" {
+		return fmt.Errorf("job name (specified by --name, -N or the argument) is mandatory to wait for a particular job")
+	}
+	phase, err := parseWaitPhase(waitJobFlags.For)
+	if err != nil {
+		return err
+	}
+
+	if waitJobFlags.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitJobFlags.Timeout)
+		defer cancel()
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	job, err := waitForJobPhase(ctx, jobClient, waitJobFlags.Namespace, waitJobFlags.JobName, phase)
+	if errors.Is(err, context.DeadlineExceeded) {
+		fmt.Printf("Timed out waiting for job %s/%s to be %s\n", waitJobFlags.Namespace, waitJobFlags.JobName, phase)
+		return &util.ExitError{Code: util.ExitCodeTimeout}
+	}
+	if err != nil {
+		return err
+	}
+
+	if job.Status.State.Phase != phase {
+		fmt.Printf("Job %s/%s is %s\n", job.Namespace, job.Name, job.Status.State.Phase)
+		if err := util.JobExitError(job); err != nil {
+			return err
+		}
+		return &util.ExitError{Code: util.ExitCodeError}
+	}
+	fmt.Printf("Job %s/%s is %s\n", job.Namespace, job.Name, phase)
+	return nil
+}
+
+// parseWaitPhase returns the phase given by the --for flag, case insensitive.
+func parseWaitPhase(value string) (v1alpha1.JobPhase, error) {
+	value = strings.TrimPrefix(value, "phase=")
+	for _, phase := range waitPhases {
+		if strings.EqualFold(value, string(phase)) {
+			return phase, nil
+		}
+	}
+	return "", fmt.Errorf("unknown job phase %q to wait for", value)
+}
+
+// isFinalPhase returns whether the job will not change its phase unless it is resumed or run again.
+func isFinalPhase(phase v1alpha1.JobPhase) bool {
+	switch phase {
+	case v1alpha1.Completed, v1alpha1.Failed, v1alpha1.Terminated, v1alpha1.Aborted:
+		return true
+	}
+	return false
+}
+
+// waitForJobPhase watches the job until it reaches the phase, or a final phase it will not leave.
+// The watch is resumed, relisting the job if its resource version expired, whenever the api server
+// closes it, so that jobs running for hours are waited for.
+func waitForJobPhase(ctx context.Context, jobClient versioned.Interface, namespace, name string, phase v1alpha1.JobPhase) (*v1alpha1.Job, error) {
+	jobs := jobClient.BatchV1alpha1().Jobs(namespace)
+	// get the job first, so that a missing job or a forbidden access fails fast instead of being retried
+	job, err := jobs.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if job.Status.State.Phase == phase || isFinalPhase(job.Status.State.Phase) {
+		return job, nil
+	}
+
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return jobs.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return jobs.Watch(ctx, options)
+		},
+	}
+	event, err := watchtools.UntilWithSync(ctx, lw, &v1alpha1.Job{}, nil, jobPhaseReached(name, phase))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return event.Object.(*v1alpha1.Job), nil
+}
+
+// jobPhaseReached returns the condition of the job reaching the phase, or a final phase it will not leave;
+// the job being deleted is a not found error.
+func jobPhaseReached(name string, phase v1alpha1.JobPhase) watchtools.ConditionFunc {
+	return func(event watch.Event) (bool, error) {
+		switch event.Type {
+		case watch.Deleted:
+			return false, apierrors.NewNotFound(schema.GroupResource{Group: v1alpha1.SchemeGroupVersion.Group, Resource: "jobs"}, name)
+		case watch.Added, watch.Modified:
+			job, ok := event.Object.(*v1alpha1.Job)
+			return ok && (job.Status.State.Phase == phase || isFinalPhase(job.Status.State.Phase)), nil
+		}
+		return false, nil
+	}
+}
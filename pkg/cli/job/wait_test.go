@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/client/clientset/versioned/fake"
+)
+
+func buildPhaseJob(phase v1alpha1.JobPhase) *v1alpha1.Job {
+	return &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default"},
+		Status:     v1alpha1.JobStatus{State: v1alpha1.JobState{Phase: phase}},
+	}
+}
+
+func TestParseWaitPhase(t *testing.T) {
+	for value, expected := range map[string]v1alpha1.JobPhase{
+		"Completed":     v1alpha1.Completed,
+		"running":       v1alpha1.Running,
+		"phase=Failed":  v1alpha1.Failed,
+		"Unschedulable": "",
+	} {
+		phase, err := parseWaitPhase(value)
+		if phase != expected || (err != nil) != (expected == "") {
+			t.Errorf("%s: expected phase %q, got %q and error %v", value, expected, phase, err)
+		}
+	}
+}
+
+func TestWaitForJobPhase(t *testing.T) {
+	testCases := []struct {
+		name      string
+		phase     v1alpha1.JobPhase
+		waitFor   v1alpha1.JobPhase
+		expectErr error
+	}{
+		{
+			name:    "phase reached",
+			phase:   v1alpha1.Completed,
+			waitFor: v1alpha1.Completed,
+		},
+		{
+			name:    "final phase other than the one waited for",
+			phase:   v1alpha1.Failed,
+			waitFor: v1alpha1.Completed,
+		},
+		{
+			name:      "timed out",
+			phase:     v1alpha1.Running,
+			waitFor:   v1alpha1.Completed,
+			expectErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jobClient := fake.NewSimpleClientset(buildPhaseJob(tc.phase))
+			ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+			defer cancel()
+
+			job, err := waitForJobPhase(ctx, jobClient, "default", "job1", tc.waitFor)
+			if !errors.Is(err, tc.expectErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if err == nil && job.Status.State.Phase != tc.phase {
+				t.Errorf("expected job %s, got %s", tc.phase, job.Status.State.Phase)
+			}
+		})
+	}
+}
+
+func TestJobPhaseReached(t *testing.T) {
+	testCases := []struct {
+		name      string
+		event     watch.Event
+		expected  bool
+		expectErr bool
+	}{
+		{
+			name:  "job running",
+			event: watch.Event{Type: watch.Modified, Object: buildPhaseJob(v1alpha1.Running)},
+		},
+		{
+			name:     "phase reached",
+			event:    watch.Event{Type: watch.Modified, Object: buildPhaseJob(v1alpha1.Completed)},
+			expected: true,
+		},
+		{
+			name:     "job aborted",
+			event:    watch.Event{Type: watch.Added, Object: buildPhaseJob(v1alpha1.Aborted)},
+			expected: true,
+		},
+		{
+			name:      "job deleted",
+			event:     watch.Event{Type: watch.Deleted, Object: buildPhaseJob(v1alpha1.Running)},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached, err := jobPhaseReached("job1", v1alpha1.Completed)(tc.event)
+			if tc.expectErr {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected not found error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reached != tc.expected {
+				t.Errorf("expected phase reached %v, got %v", tc.expected, reached)
+			}
+		})
+	}
+}
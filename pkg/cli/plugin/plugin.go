@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+// Prefix is the prefix of the executables which are vcctl plugins: vcctl-foo-bar is run as `vcctl foo bar`.
+const Prefix = "vcctl-"
+
+// Plugin is an executable found in the PATH.
+type Plugin struct {
+	// Name is the subcommand of the plugin, e.g. "foo bar".
+	Name string
+	Path string
+	// Warnings are the reasons why the plugin may not run as expected, e.g. it is overshadowed.
+	Warnings []string
+}
+
+// Lookup finds the plugin run by the arguments of vcctl, preferring the longest name: `vcctl foo bar baz`
+// runs vcctl-foo-bar-baz, else vcctl-foo-bar with the baz argument, else vcctl-foo with bar and baz.
+// It returns the path of the plugin and its arguments.
+func Lookup(args []string, lookPath func(string) (string, error)) (string, []string, bool) {
+	var pieces []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		pieces = append(pieces, strings.ReplaceAll(arg, "-", "_"))
+	}
+
+	for n := len(pieces); n > 0; n-- {
+		path, err := lookPath(Prefix + strings.Join(pieces[:n], "-"))
+		if err != nil {
+			continue
+		}
+		return path, args[n:], true
+	}
+	return "", nil, false
+}
+
+// HandlePluginCommand runs the plugin for the arguments of vcctl when they do not name a builtin
+// command. It returns false when there is no such plugin.
+func HandlePluginCommand(root *cobra.Command, args []string) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return false, nil
+	}
+	// The help and completion commands are only added by cobra on execution.
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+	if _, _, err := root.Find(args); err == nil {
+		return false, nil
+	}
+	if args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd {
+		return false, nil
+	}
+
+	path, pluginArgs, found := Lookup(args, exec.LookPath)
+	if !found {
+		return false, nil
+	}
+	return true, Run(path, pluginArgs, os.Environ(), os.Stdin, os.Stdout, os.Stderr)
+}
+
+// Run runs the plugin with the arguments and the environment, returning an ExitError with the exit code
+// of the plugin when it fails.
+func Run(path string, args, env []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cmd := exec.Command(path, args...)
+	cmd.Env = env
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &util.ExitError{Code: exitErr.ExitCode()}
+	}
+	return err
+}
+
+// List lists the plugins found in the directories of the PATH, warning about the ones overshadowed by
+// an earlier plugin of the same name, or by a builtin command of the root command.
+func List(root *cobra.Command, pathEnv string) []Plugin {
+	var plugins []Plugin
+	seen := map[string]int{}
+	for _, dir := range filepath.SplitList(pathEnv) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), Prefix) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			name := pluginName(entry.Name())
+			plugin := Plugin{Name: name, Path: path}
+			if first, found := seen[name]; found {
+				plugin.Warnings = append(plugin.Warnings, fmt.Sprintf("overshadowed by %s", plugins[first].Path))
+			} else {
+				seen[name] = len(plugins)
+			}
+			if builtin := builtinCommand(root, name); builtin != "" {
+				plugin.Warnings = append(plugin.Warnings, fmt.Sprintf("overshadowed by the builtin command %q", builtin))
+			}
+			plugins = append(plugins, plugin)
+		}
+	}
+	return plugins
+}
+
+// PrintPlugins prints the plugins with their warnings.
+func PrintPlugins(plugins []Plugin, writer io.Writer) {
+	if len(plugins) == 0 {
+		fmt.Fprintf(writer, "No vcctl plugins found in the PATH, plugins are executables named %s<name>\n", Prefix)
+		return
+	}
+	for _, plugin := range plugins {
+		fmt.Fprintf(writer, "%s\t%s\n", plugin.Name, plugin.Path)
+		for _, warning := range plugin.Warnings {
+			fmt.Fprintf(writer, "  - warning: %s\n", warning)
+		}
+	}
+}
+
+// pluginName returns the subcommand of the plugin file, e.g. "foo bar" for vcctl-foo-bar and vcctl-foo-bar.exe.
+func pluginName(file string) string {
+	name := strings.TrimPrefix(file, Prefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	pieces := strings.Split(name, "-")
+	for i := range pieces {
+		pieces[i] = strings.ReplaceAll(pieces[i], "_", "-")
+	}
+	return strings.Join(pieces, " ")
+}
+
+// builtinCommand returns the path of the builtin command run instead of the plugin, if any.
+func builtinCommand(root *cobra.Command, name string) string {
+	cmd, _, err := root.Find(strings.Fields(name))
+	if err != nil || cmd == root {
+		return ""
+	}
+	return cmd.CommandPath()
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(path))
+		return ext == ".exe" || ext == ".bat" || ext == ".cmd" || ext == ".ps1"
+	}
+	return info.Mode()&0111 != 0
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+func TestLookup(t *testing.T) {
+	installed := map[string]string{
+		"vcctl-foo":        "/usr/local/bin/vcctl-foo",
+		"vcctl-foo-bar":    "/usr/local/bin/vcctl-foo-bar",
+		"vcctl-team_queue": "/usr/local/bin/vcctl-team_queue",
+	}
+	lookPath := func(file string) (string, error) {
+		if path, found := installed[file]; found {
+			return path, nil
+		}
+		return "", fmt.Errorf("%s not found", file)
+	}
+
+	testCases := []struct {
+		Name         string
+		Args         []string
+		ExpectedPath string
+		ExpectedArgs []string
+		ExpectFound  bool
+	}{
+		{
+			Name:         "longest name wins",
+			Args:         []string{"foo", "bar", "baz", "--flag"},
+			ExpectedPath: "/usr/local/bin/vcctl-foo-bar",
+			ExpectedArgs: []string{"baz", "--flag"},
+			ExpectFound:  true,
+		},
+		{
+			Name:         "shorter name with arguments",
+			Args:         []string{"foo", "baz"},
+			ExpectedPath: "/usr/local/bin/vcctl-foo",
+			ExpectedArgs: []string{"baz"},
+			ExpectFound:  true,
+		},
+		{
+			Name:         "flags end the name",
+			Args:         []string{"foo", "--bar"},
+			ExpectedPath: "/usr/local/bin/vcctl-foo",
+			ExpectedArgs: []string{"--bar"},
+			ExpectFound:  true,
+		},
+		{
+			Name:         "dashes of a subcommand",
+			Args:         []string{"team-queue"},
+			ExpectedPath: "/usr/local/bin/vcctl-team_queue",
+			ExpectedArgs: []string{},
+			ExpectFound:  true,
+		},
+		{
+			Name:        "no plugin",
+			Args:        []string{"baz", "foo"},
+			ExpectFound: false,
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			path, args, found := Lookup(testcase.Args, lookPath)
+			if found != testcase.ExpectFound {
+				t.Fatalf("expected found %v, got %v", testcase.ExpectFound, found)
+			}
+			if !found {
+				return
+			}
+			if path != testcase.ExpectedPath || !reflect.DeepEqual(args, testcase.ExpectedArgs) {
+				t.Errorf("expected %s %v, got %s %v", testcase.ExpectedPath, testcase.ExpectedArgs, path, args)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	writePlugin(t, dir1, "vcctl-foo", "")
+	writePlugin(t, dir2, "vcctl-foo", "")
+	writePlugin(t, dir2, "vcctl-job", "")
+	writePlugin(t, dir2, "vcctl-team_queue-list", "")
+	if err := os.WriteFile(filepath.Join(dir2, "vcctl-notexecutable"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &cobra.Command{Use: "vcctl"}
+	root.AddCommand(&cobra.Command{Use: "job"})
+
+	plugins := List(root, dir1+string(os.PathListSeparator)+dir2)
+	expected := []Plugin{
+		{Name: "foo", Path: filepath.Join(dir1, "vcctl-foo")},
+		{Name: "foo", Path: filepath.Join(dir2, "vcctl-foo"),
+			Warnings: []string{fmt.Sprintf("overshadowed by %s", filepath.Join(dir1, "vcctl-foo"))}},
+		{Name: "job", Path: filepath.Join(dir2, "vcctl-job"),
+			Warnings: []string{`overshadowed by the builtin command "vcctl job"`}},
+		{Name: "team-queue list", Path: filepath.Join(dir2, "vcctl-team_queue-list")},
+	}
+	if !reflect.DeepEqual(plugins, expected) {
+		t.Errorf("expected plugins %v, got %v", expected, plugins)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	dir := t.TempDir()
+	path := writePlugin(t, dir, "vcctl-echo", `echo "$VCCTL_TEST $@"; exit $1`)
+
+	var stdout bytes.Buffer
+	err := Run(path, []string{"0", "queue"}, []string{"VCCTL_TEST=env"}, nil, &stdout, &stdout)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stdout.String() != "env 0 queue\n" {
+		t.Errorf("expected the plugin to get the arguments and the environment, got %q", stdout.String())
+	}
+
+	err = Run(path, []string{"3"}, nil, nil, &stdout, &stdout)
+	var exitErr *util.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Errorf("expected exit code 3, got %v", err)
+	}
+}
+
+func TestHandlePluginCommand(t *testing.T) {
+	root := &cobra.Command{Use: "vcctl"}
+	root.AddCommand(&cobra.Command{Use: "job", Run: func(cmd *cobra.Command, args []string) {}})
+	t.Setenv("PATH", t.TempDir())
+
+	testCases := []struct {
+		Name string
+		Args []string
+	}{
+		{Name: "builtin command", Args: []string{"job"}},
+		{Name: "help command", Args: []string{"help"}},
+		{Name: "completion command", Args: []string{"completion", "bash"}},
+		{Name: "completion request", Args: []string{cobra.ShellCompRequestCmd, "job", ""}},
+		{Name: "flags", Args: []string{"--help"}},
+		{Name: "missing plugin", Args: []string{"foo"}},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			found, err := HandlePluginCommand(root, testcase.Args)
+			if found || err != nil {
+				t.Errorf("expected no plugin to run, got %v, %v", found, err)
+			}
+		})
+	}
+}
+
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -68,6 +68,10 @@ type listFlags struct {
 	allNamespace bool
 	// QueueName represents queue name
 	QueueName string
+	// Output is the output format, json or yaml
+	Output string
+	// AllClusters represents listing in all the contexts of the kubeconfig
+	AllClusters bool
 }
 
 var listPodFlags = &listFlags{}
@@ -80,18 +84,36 @@ func InitListFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&listPodFlags.JobName, "job", "j", "", "list pod with specified job name")
 	cmd.Flags().StringVarP(&listPodFlags.Namespace, "namespace", "n", "default", "the namespace of job")
 	cmd.Flags().BoolVarP(&listPodFlags.allNamespace, "all-namespaces", "", false, "list jobs in all namespaces")
+	util.InitOutputFlag(cmd, &listPodFlags.Output, false)
+	util.InitAllClustersFlag(cmd, &listPodFlags.AllClusters)
 }
 
 // ListPods lists all pods details created by vcjob
 func ListPods(ctx context.Context) error {
-	config, err := util.BuildConfig(listPodFlags.Master, listPodFlags.Kubeconfig)
-	if err != nil {
+	if err := util.ValidateOutput(listPodFlags.Output, false); err != nil {
 		return err
 	}
 	if listPodFlags.allNamespace {
 		listPodFlags.Namespace = ""
 	}
+	if listPodFlags.AllClusters {
+		if err := util.ValidateAllClusters(listPodFlags.Master, listPodFlags.Output); err != nil {
+			return err
+		}
+		return util.ForEachContext(listPodFlags.Kubeconfig, os.Stdout, func(config *rest.Config) error {
+			return listPods(ctx, config)
+		})
+	}
 
+	config, err := util.BuildConfig(listPodFlags.Master, listPodFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	return listPods(ctx, config)
+}
+
+// listPods lists the pods of the cluster of the config.
+func listPods(ctx context.Context, config *rest.Config) error {
 	var pods corev1.PodList
 
 	// if job name are specified, use job name to filter pods
@@ -146,6 +168,16 @@ func ListPods(ctx context.Context) error {
 		pods.Items = append(pods.Items, listPodsRes.Items...)
 	}
 
+	if util.IsStructuredOutput(listPodFlags.Output) {
+		pods.APIVersion = "v1"
+		pods.Kind = "List"
+		for i := range pods.Items {
+			pods.Items[i].ManagedFields = nil
+			pods.Items[i].APIVersion = "v1"
+			pods.Items[i].Kind = "Pod"
+		}
+		return util.PrintObject(os.Stdout, listPodFlags.Output, &pods)
+	}
 	if len(pods.Items) == 0 {
 		fmt.Printf("No resources found\n")
 		return nil
@@ -17,9 +17,6 @@ limitations under the License.
 package queue
 
 import (
-	"os"
-	"path/filepath"
-
 	"github.com/spf13/cobra"
 
 	"volcano.sh/volcano/pkg/cli/util"
@@ -34,12 +31,5 @@ type commonFlags struct {
 func initFlags(cmd *cobra.Command, cf *commonFlags) {
 	cmd.Flags().StringVarP(&cf.SchedulerName, "scheduler", "", "volcano", "the scheduler for this job")
 	cmd.Flags().StringVarP(&cf.Master, "master", "s", "", "the address of apiserver")
-
-	kubeConfFile := os.Getenv("KUBECONFIG")
-	if kubeConfFile == "" {
-		if home := util.HomeDir(); home != "" {
-			kubeConfFile = filepath.Join(home, ".kube", "config")
-		}
-	}
-	cmd.Flags().StringVarP(&cf.Kubeconfig, "kubeconfig", "k", kubeConfFile, "(optional) absolute path to the kubeconfig file")
+	util.InitKubeconfigFlag(cmd, &cf.Kubeconfig)
 }
@@ -18,6 +18,7 @@ package queue
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -25,6 +26,7 @@ import (
 
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
 )
 
 type createFlags struct {
@@ -34,6 +36,14 @@ type createFlags struct {
 	Weight int32
 	// State is state of Queue
 	State string
+	// Capability is the upper limit of the resources of the queue
+	Capability string
+	// Deserved is the resources the queue deserves when reclaiming
+	Deserved string
+	// Guarantee is the resources reserved for the queue
+	Guarantee string
+	// Reclaimable is whether the resources of the queue can be reclaimed by other queues
+	Reclaimable bool
 }
 
 var createQueueFlags = &createFlags{}
@@ -46,6 +56,14 @@ func InitCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().Int32VarP(&createQueueFlags.Weight, "weight", "w", 1, "the weight of the queue")
 
 	cmd.Flags().StringVarP(&createQueueFlags.State, "state", "S", "Open", "the state of queue")
+	cmd.Flags().StringVarP(&createQueueFlags.Capability, "capability", "", "",
+		"the upper limit of the resources of the queue, e.g. cpu=10,memory=20Gi")
+	cmd.Flags().StringVarP(&createQueueFlags.Deserved, "deserved", "", "",
+		"the resources the queue deserves when reclaiming, e.g. cpu=5,memory=10Gi")
+	cmd.Flags().StringVarP(&createQueueFlags.Guarantee, "guarantee", "", "",
+		"the resources reserved for the queue, e.g. cpu=2,memory=4Gi")
+	cmd.Flags().BoolVarP(&createQueueFlags.Reclaimable, "reclaimable", "", true,
+		"whether the resources of the queue can be reclaimed by other queues")
 }
 
 // CreateQueue create queue.
@@ -55,12 +73,30 @@ func CreateQueue(ctx context.Context) error {
 		return err
 	}
 
+	capability, err := util.PopulateResourceListV1(createQueueFlags.Capability)
+	if err != nil {
+		return fmt.Errorf("invalid capability: %v", err)
+	}
+	deserved, err := util.PopulateResourceListV1(createQueueFlags.Deserved)
+	if err != nil {
+		return fmt.Errorf("invalid deserved: %v", err)
+	}
+	guarantee, err := util.PopulateResourceListV1(createQueueFlags.Guarantee)
+	if err != nil {
+		return fmt.Errorf("invalid guarantee: %v", err)
+	}
+	reclaimable := createQueueFlags.Reclaimable
+
 	queue := &schedulingv1beta1.Queue{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: createQueueFlags.Name,
 		},
 		Spec: schedulingv1beta1.QueueSpec{
-			Weight: createQueueFlags.Weight,
+			Weight:      createQueueFlags.Weight,
+			Capability:  capability,
+			Deserved:    deserved,
+			Guarantee:   schedulingv1beta1.Guarantee{Resource: guarantee},
+			Reclaimable: &reclaimable,
 		},
 		Status: schedulingv1beta1.QueueStatus{
 			State: schedulingv1beta1.QueueState(createQueueFlags.State),
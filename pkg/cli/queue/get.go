@@ -28,12 +28,14 @@ import (
 
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
 )
 
 type getFlags struct {
 	commonFlags
 
-	Name string
+	Name   string
+	Output string
 }
 
 var getQueueFlags = &getFlags{}
@@ -43,10 +45,14 @@ func InitGetFlags(cmd *cobra.Command) {
 	initFlags(cmd, &getQueueFlags.commonFlags)
 
 	cmd.Flags().StringVarP(&getQueueFlags.Name, "name", "n", "", "the name of queue")
+	util.InitOutputFlag(cmd, &getQueueFlags.Output, false)
 }
 
 // GetQueue gets a queue.
 func GetQueue(ctx context.Context) error {
+	if err := util.ValidateOutput(getQueueFlags.Output, false); err != nil {
+		return err
+	}
 	config, err := buildConfig(getQueueFlags.Master, getQueueFlags.Kubeconfig)
 	if err != nil {
 		return err
@@ -63,6 +69,12 @@ func GetQueue(ctx context.Context) error {
 		return err
 	}
 
+	if util.IsStructuredOutput(getQueueFlags.Output) {
+		queue.ManagedFields = nil
+		queue.APIVersion = v1beta1.SchemeGroupVersion.String()
+		queue.Kind = "Queue"
+		return util.PrintObject(os.Stdout, getQueueFlags.Output, queue)
+	}
 	PrintQueue(queue, os.Stdout)
 
 	return nil
@@ -70,14 +82,15 @@ func GetQueue(ctx context.Context) error {
 
 // PrintQueue prints queue information.
 func PrintQueue(queue *v1beta1.Queue, writer io.Writer) {
-	_, err := fmt.Fprintf(writer, "%-25s%-8s%-8s%-8s%-8s%-8s%-8s\n",
-		Name, Weight, State, Inqueue, Pending, Running, Unknown)
+	_, err := fmt.Fprintf(writer, "%-25s%-8s%-8s%-8s%-8s%-8s%-8s%-30s%s\n",
+		Name, Weight, State, Inqueue, Pending, Running, Unknown, Capability, Allocated)
 	if err != nil {
 		fmt.Printf("Failed to print queue command result: %s.\n", err)
 	}
-	_, err = fmt.Fprintf(writer, "%-25s%-8d%-8s%-8d%-8d%-8d%-8d\n",
+	_, err = fmt.Fprintf(writer, "%-25s%-8d%-8s%-8d%-8d%-8d%-8d%-30s%s\n",
 		queue.Name, queue.Spec.Weight, queue.Status.State, queue.Status.Inqueue,
-		queue.Status.Pending, queue.Status.Running, queue.Status.Unknown)
+		queue.Status.Pending, queue.Status.Running, queue.Status.Unknown,
+		formatResources(queue.Spec.Capability), formatResources(queue.Status.Allocated))
 	if err != nil {
 		fmt.Printf("Failed to print queue command result: %s.\n", err)
 	}
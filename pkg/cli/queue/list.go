@@ -25,13 +25,18 @@ import (
 	"github.com/spf13/cobra"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
 )
 
 type listFlags struct {
 	commonFlags
+
+	Output      string
+	AllClusters bool
 }
 
 const (
@@ -55,6 +60,12 @@ const (
 
 	// State is state of queue
 	State string = "State"
+
+	// Capability is the upper limit of the resources of the queue
+	Capability string = "Capability"
+
+	// Allocated is the resources allocated to the queue
+	Allocated string = "Allocated"
 )
 
 var listQueueFlags = &listFlags{}
@@ -62,21 +73,49 @@ var listQueueFlags = &listFlags{}
 // InitListFlags inits all flags.
 func InitListFlags(cmd *cobra.Command) {
 	initFlags(cmd, &listQueueFlags.commonFlags)
+	util.InitOutputFlag(cmd, &listQueueFlags.Output, false)
+	util.InitAllClustersFlag(cmd, &listQueueFlags.AllClusters)
 }
 
 // ListQueue lists all the queue.
 func ListQueue(ctx context.Context) error {
+	if err := util.ValidateOutput(listQueueFlags.Output, false); err != nil {
+		return err
+	}
+	if listQueueFlags.AllClusters {
+		if err := util.ValidateAllClusters(listQueueFlags.Master, listQueueFlags.Output); err != nil {
+			return err
+		}
+		return util.ForEachContext(listQueueFlags.Kubeconfig, os.Stdout, func(config *rest.Config) error {
+			return listQueues(ctx, config)
+		})
+	}
+
 	config, err := buildConfig(listQueueFlags.Master, listQueueFlags.Kubeconfig)
 	if err != nil {
 		return err
 	}
+	return listQueues(ctx, config)
+}
 
+// listQueues lists the queues of the cluster of the config.
+func listQueues(ctx context.Context, config *rest.Config) error {
 	jobClient := versioned.NewForConfigOrDie(config)
 	queues, err := jobClient.SchedulingV1beta1().Queues().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return err
 	}
 
+	if util.IsStructuredOutput(listQueueFlags.Output) {
+		queues.APIVersion = "v1"
+		queues.Kind = "List"
+		for i := range queues.Items {
+			queues.Items[i].ManagedFields = nil
+			queues.Items[i].APIVersion = v1beta1.SchemeGroupVersion.String()
+			queues.Items[i].Kind = "Queue"
+		}
+		return util.PrintObject(os.Stdout, listQueueFlags.Output, queues)
+	}
 	if len(queues.Items) == 0 {
 		fmt.Printf("No resources found\n")
 		return nil
@@ -88,15 +127,16 @@ func ListQueue(ctx context.Context) error {
 
 // PrintQueues prints queue information.
 func PrintQueues(queues *v1beta1.QueueList, writer io.Writer) {
-	_, err := fmt.Fprintf(writer, "%-25s%-8s%-8s%-8s%-8s%-8s%-8s\n",
-		Name, Weight, State, Inqueue, Pending, Running, Unknown)
+	_, err := fmt.Fprintf(writer, "%-25s%-8s%-8s%-8s%-8s%-8s%-8s%-30s%s\n",
+		Name, Weight, State, Inqueue, Pending, Running, Unknown, Capability, Allocated)
 	if err != nil {
 		fmt.Printf("Failed to print queue command result: %s.\n", err)
 	}
 	for _, queue := range queues.Items {
-		_, err = fmt.Fprintf(writer, "%-25s%-8d%-8s%-8d%-8d%-8d%-8d\n",
+		_, err = fmt.Fprintf(writer, "%-25s%-8d%-8s%-8d%-8d%-8d%-8d%-30s%s\n",
 			queue.Name, queue.Spec.Weight, queue.Status.State, queue.Status.Inqueue,
-			queue.Status.Pending, queue.Status.Running, queue.Status.Unknown)
+			queue.Status.Pending, queue.Status.Running, queue.Status.Unknown,
+			formatResources(queue.Spec.Capability), formatResources(queue.Status.Allocated))
 		if err != nil {
 			fmt.Printf("Failed to print queue command result: %s.\n", err)
 		}
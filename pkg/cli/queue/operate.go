@@ -18,15 +18,19 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/controllers/apis"
 )
 
 const (
@@ -34,6 +38,8 @@ const (
 	ActionOpen = "open"
 	// ActionClose is `close` action
 	ActionClose = "close"
+	// ActionDrain is `drain` action
+	ActionDrain = "drain"
 	// ActionUpdate is `update` action
 	ActionUpdate = "update"
 )
@@ -47,6 +53,10 @@ type operateFlags struct {
 	Weight int32
 	// Action is operation action of queue
 	Action string
+	// TargetQueue is the queue the pending podgroups of a draining queue are moved to
+	TargetQueue string
+	// EvictAfter is the duration after which the running pods of a draining queue are evicted
+	EvictAfter time.Duration
 }
 
 var operateQueueFlags = &operateFlags{}
@@ -58,7 +68,11 @@ func InitOperateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&operateQueueFlags.Name, "name", "n", "", "the name of queue")
 	cmd.Flags().Int32VarP(&operateQueueFlags.Weight, "weight", "w", 0, "the weight of the queue")
 	cmd.Flags().StringVarP(&operateQueueFlags.Action, "action", "a", "",
-		"operate action to queue, valid actions are open, close, update")
+		"operate action to queue, valid actions are open, close, drain, update")
+	cmd.Flags().StringVarP(&operateQueueFlags.TargetQueue, "target-queue", "t", "",
+		"the queue the pending jobs are moved to when draining the queue")
+	cmd.Flags().DurationVarP(&operateQueueFlags.EvictAfter, "evict-after", "e", 0,
+		"the duration after which the running jobs are evicted when draining the queue, never if 0")
 }
 
 // OperateQueue operates queue
@@ -79,6 +93,11 @@ func OperateQueue(ctx context.Context) error {
 		action = v1alpha1.OpenQueueAction
 	case ActionClose:
 		action = v1alpha1.CloseQueueAction
+	case ActionDrain:
+		action = apis.DrainQueueAction
+		if err := setDrainPolicy(ctx, config, operateQueueFlags.Name, operateQueueFlags.TargetQueue, operateQueueFlags.EvictAfter); err != nil {
+			return err
+		}
 	case ActionUpdate:
 		if operateQueueFlags.Weight == 0 {
 			return fmt.Errorf("when %s queue %s, weight must be specified, "+
@@ -94,9 +113,35 @@ func OperateQueue(ctx context.Context) error {
 	case "":
 		return fmt.Errorf("action can not be null")
 	default:
-		return fmt.Errorf("action %s invalid, valid actions are %s, %s and %s",
-			operateQueueFlags.Action, ActionOpen, ActionClose, ActionUpdate)
+		return fmt.Errorf("action %s invalid, valid actions are %s, %s, %s and %s",
+			operateQueueFlags.Action, ActionOpen, ActionClose, ActionDrain, ActionUpdate)
 	}
 
 	return createQueueCommand(ctx, config, action)
 }
+
+// setDrainPolicy sets the target queue and the eviction deadline of the drain on the queue annotations.
+func setDrainPolicy(ctx context.Context, config *rest.Config, name, targetQueue string, evictAfter time.Duration) error {
+	if targetQueue == "" && evictAfter == 0 {
+		return nil
+	}
+	if evictAfter < 0 {
+		return fmt.Errorf("evict-after must not be negative")
+	}
+
+	annotations := map[string]string{}
+	if targetQueue != "" {
+		annotations[apis.QueueDrainTargetKey] = targetQueue
+	}
+	if evictAfter != 0 {
+		annotations[apis.QueueDrainEvictAfterKey] = evictAfter.String()
+	}
+	patchBytes, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+
+	queueClient := versioned.NewForConfigOrDie(config)
+	_, err = queueClient.SchedulingV1beta1().Queues().Patch(ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
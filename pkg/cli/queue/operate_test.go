@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 
@@ -56,6 +57,8 @@ func TestOperateQueue(t *testing.T) {
 		QueueName   string
 		Weight      int32
 		Action      string
+		TargetQueue string
+		EvictAfter  time.Duration
 		ExpectValue error
 	}{
 		{
@@ -70,6 +73,27 @@ func TestOperateQueue(t *testing.T) {
 			Action:      ActionOpen,
 			ExpectValue: nil,
 		},
+		{
+			Name:        "Normal Case Operate Queue Succeed, Action drain",
+			QueueName:   "normal-case-action-drain",
+			Action:      ActionDrain,
+			ExpectValue: nil,
+		},
+		{
+			Name:        "Normal Case Operate Queue Succeed, Action drain to target queue",
+			QueueName:   "normal-case-action-drain-target",
+			Action:      ActionDrain,
+			TargetQueue: "default",
+			EvictAfter:  time.Hour,
+			ExpectValue: nil,
+		},
+		{
+			Name:        "Abnormal Case Drain Queue Failed For Negative Eviction Deadline",
+			QueueName:   "abnormal-case-negative-evict-after",
+			Action:      ActionDrain,
+			EvictAfter:  -time.Hour,
+			ExpectValue: fmt.Errorf("evict-after must not be negative"),
+		},
 		{
 			Name:        "Normal Case Operate Queue Succeed, Update Weight",
 			QueueName:   "normal-case-update-weight",
@@ -99,8 +123,8 @@ func TestOperateQueue(t *testing.T) {
 			Name:      "Abnormal Case Operate Queue Failed For Action Invalid",
 			QueueName: "abnormal-case-invalid-action",
 			Action:    "invalid",
-			ExpectValue: fmt.Errorf("action %s invalid, valid actions are %s, %s and %s",
-				"invalid", ActionOpen, ActionClose, ActionUpdate),
+			ExpectValue: fmt.Errorf("action %s invalid, valid actions are %s, %s, %s and %s",
+				"invalid", ActionOpen, ActionClose, ActionDrain, ActionUpdate),
 		},
 	}
 
@@ -108,6 +132,8 @@ func TestOperateQueue(t *testing.T) {
 		operateQueueFlags.Name = testCase.QueueName
 		operateQueueFlags.Action = testCase.Action
 		operateQueueFlags.Weight = testCase.Weight
+		operateQueueFlags.TargetQueue = testCase.TargetQueue
+		operateQueueFlags.EvictAfter = testCase.EvictAfter
 
 		err := OperateQueue(context.TODO())
 		if false == reflect.DeepEqual(err, testCase.ExpectValue) {
@@ -129,4 +155,10 @@ func TestInitOperateFlags(t *testing.T) {
 	if cmd.Flag("action") == nil {
 		t.Errorf("Could not find the flag action")
 	}
+	if cmd.Flag("target-queue") == nil {
+		t.Errorf("Could not find the flag target-queue")
+	}
+	if cmd.Flag("evict-after") == nil {
+		t.Errorf("Could not find the flag evict-after")
+	}
 }
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+type updateFlags struct {
+	commonFlags
+
+	// Name is name of queue
+	Name string
+	// Weight is weight of queue, unchanged if 0
+	Weight int32
+	// Capability is the upper limit of the resources of the queue, unchanged if empty
+	Capability string
+	// Deserved is the resources the queue deserves when reclaiming, unchanged if empty
+	Deserved string
+	// Guarantee is the resources reserved for the queue, unchanged if empty
+	Guarantee string
+	// Reclaimable is whether the resources of the queue can be reclaimed, unchanged if empty
+	Reclaimable string
+}
+
+var updateQueueFlags = &updateFlags{}
+
+// InitUpdateFlags is used to init all flags during queue updating.
+func InitUpdateFlags(cmd *cobra.Command) {
+	initFlags(cmd, &updateQueueFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&updateQueueFlags.Name, "name", "n", "", "the name of queue")
+	cmd.Flags().Int32VarP(&updateQueueFlags.Weight, "weight", "w", 0, "the weight of the queue")
+	cmd.Flags().StringVarP(&updateQueueFlags.Capability, "capability", "", "",
+		"the upper limit of the resources of the queue, e.g. cpu=10,memory=20Gi")
+	cmd.Flags().StringVarP(&updateQueueFlags.Deserved, "deserved", "", "",
+		"the resources the queue deserves when reclaiming, e.g. cpu=5,memory=10Gi")
+	cmd.Flags().StringVarP(&updateQueueFlags.Guarantee, "guarantee", "", "",
+		"the resources reserved for the queue, e.g. cpu=2,memory=4Gi")
+	cmd.Flags().StringVarP(&updateQueueFlags.Reclaimable, "reclaimable", "", "",
+		"whether the resources of the queue can be reclaimed by other queues, true or false")
+}
+
+// UpdateQueue updates the spec of a queue, leaving the fields which are not given unchanged.
+func UpdateQueue(ctx context.Context) error {
+	config, err := buildConfig(updateQueueFlags.Master, updateQueueFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	if len(updateQueueFlags.Name) == 0 {
+		return fmt.Errorf("queue name must be specified")
+	}
+
+	patchBytes, err := buildQueueUpdatePatch(updateQueueFlags)
+	if err != nil {
+		return err
+	}
+
+	queueClient := versioned.NewForConfigOrDie(config)
+	_, err = queueClient.SchedulingV1beta1().Queues().Patch(ctx,
+		updateQueueFlags.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
+// buildQueueUpdatePatch returns the merge patch of the spec fields given by the flags.
+func buildQueueUpdatePatch(flags *updateFlags) ([]byte, error) {
+	spec := map[string]interface{}{}
+
+	if flags.Weight < 0 {
+		return nil, fmt.Errorf("weight must be greater than 0")
+	}
+	if flags.Weight > 0 {
+		spec["weight"] = flags.Weight
+	}
+	for field, value := range map[string]string{
+		"capability": flags.Capability,
+		"deserved":   flags.Deserved,
+	} {
+		resources, err := util.PopulateResourceListV1(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", field, err)
+		}
+		if resources != nil {
+			spec[field] = resources
+		}
+	}
+	guarantee, err := util.PopulateResourceListV1(flags.Guarantee)
+	if err != nil {
+		return nil, fmt.Errorf("invalid guarantee: %v", err)
+	}
+	if guarantee != nil {
+		spec["guarantee"] = map[string]interface{}{"resource": guarantee}
+	}
+	if flags.Reclaimable != "" {
+		reclaimable, err := strconv.ParseBool(flags.Reclaimable)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reclaimable %q, expected true or false", flags.Reclaimable)
+		}
+		spec["reclaimable"] = reclaimable
+	}
+
+	if len(spec) == 0 {
+		return nil, fmt.Errorf("nothing to update, one of weight, capability, deserved, guarantee or reclaimable must be specified")
+	}
+	return json.Marshal(map[string]interface{}{"spec": spec})
+}
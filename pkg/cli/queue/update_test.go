@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestBuildQueueUpdatePatch(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Flags       updateFlags
+		ExpectPatch string
+		ExpectErr   bool
+	}{
+		{
+			Name:        "weight only",
+			Flags:       updateFlags{Weight: 3},
+			ExpectPatch: `{"spec":{"weight":3}}`,
+		},
+		{
+			Name: "resources and reclaimable",
+			Flags: updateFlags{
+				Capability:  "cpu=10,memory=20Gi",
+				Guarantee:   "cpu=2",
+				Reclaimable: "false",
+			},
+			ExpectPatch: `{"spec":{"capability":{"cpu":"10","memory":"20Gi"},"guarantee":{"resource":{"cpu":"2"}},"reclaimable":false}}`,
+		},
+		{
+			Name:      "invalid resources",
+			Flags:     updateFlags{Deserved: "cpu"},
+			ExpectErr: true,
+		},
+		{
+			Name:      "invalid reclaimable",
+			Flags:     updateFlags{Reclaimable: "maybe"},
+			ExpectErr: true,
+		},
+		{
+			Name:      "nothing to update",
+			Flags:     updateFlags{},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			patch, err := buildQueueUpdatePatch(&testCase.Flags)
+			if (err != nil) != testCase.ExpectErr {
+				t.Fatalf("expected error %v, got %v", testCase.ExpectErr, err)
+			}
+			if string(patch) != testCase.ExpectPatch {
+				t.Errorf("expected patch %s, got %s", testCase.ExpectPatch, string(patch))
+			}
+		})
+	}
+}
+
+func TestUpdateQueue(t *testing.T) {
+	var method, body string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"metadata":{"name":"test-queue"}}`))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	updateQueueFlags.Master = server.URL
+	updateQueueFlags.Name = "test-queue"
+	updateQueueFlags.Weight = 2
+	defer func() { updateQueueFlags = &updateFlags{} }()
+
+	if err := UpdateQueue(context.TODO()); err != nil {
+		t.Fatalf("failed to update queue: %v", err)
+	}
+	if method != http.MethodPatch || body != `{"spec":{"weight":2}}` {
+		t.Errorf("expected weight patch, got %s %s", method, body)
+	}
+}
+
+func TestInitUpdateFlags(t *testing.T) {
+	var cmd cobra.Command
+	InitUpdateFlags(&cmd)
+
+	for _, flag := range []string{"name", "weight", "capability", "deserved", "guarantee", "reclaimable"} {
+		if cmd.Flag(flag) == nil {
+			t.Errorf("Could not find the flag %s", flag)
+		}
+	}
+}
@@ -19,21 +19,23 @@ package queue
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	// Initialize client auth plugin.
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/apis/helpers"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
 )
 
 func buildConfig(master, kubeconfig string) (*rest.Config, error) {
-	return clientcmd.BuildConfigFromFlags(master, kubeconfig)
+	return util.BuildConfig(master, kubeconfig)
 }
 
 func createQueueCommand(ctx context.Context, config *rest.Config, action busv1alpha1.Action) error {
@@ -62,3 +64,21 @@ func createQueueCommand(ctx context.Context, config *rest.Config, action busv1al
 
 	return nil
 }
+
+// formatResources formats the resources in the syntax of the resource flags, e.g. cpu=10,memory=20Gi.
+func formatResources(resources v1.ResourceList) string {
+	if len(resources) == 0 {
+		return "<none>"
+	}
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := resources[v1.ResourceName(name)]
+		parts = append(parts, name+"="+quantity.String())
+	}
+	return strings.Join(parts, ",")
+}
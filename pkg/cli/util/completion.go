@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+)
+
+// completionTimeout bounds the requests made to complete the names, so that the shell does not hang
+// on an unreachable cluster.
+const completionTimeout = 5 * time.Second
+
+// CompletionFunc completes the value of a flag or an argument of a command.
+type CompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// RegisterFlagCompletions registers the completions of the flags of the command, by flag name.
+// The flags the command does not have are skipped.
+func RegisterFlagCompletions(cmd *cobra.Command, completions map[string]CompletionFunc) {
+	for name, fn := range completions {
+		if cmd.Flags().Lookup(name) == nil {
+			continue
+		}
+		// The error is only returned for missing flags or completions registered twice.
+		_ = cmd.RegisterFlagCompletionFunc(name, fn)
+	}
+}
+
+// CompleteJobNames completes the names of the jobs in the namespace given to the command.
+func CompleteJobNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config, err := completionConfig(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	jobs, err := versioned.NewForConfigOrDie(config).BatchV1alpha1().Jobs(completionNamespace(cmd)).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, job := range jobs.Items {
+		names = append(names, job.Name)
+	}
+	return filterNames(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteJobNameArg completes the job name given as the first argument of the command.
+func CompleteJobNameArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return CompleteJobNames(cmd, args, toComplete)
+}
+
+// CompleteQueueNames completes the names of the queues.
+func CompleteQueueNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config, err := completionConfig(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	queues, err := versioned.NewForConfigOrDie(config).SchedulingV1beta1().Queues().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, queue := range queues.Items {
+		names = append(names, queue.Name)
+	}
+	return filterNames(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteNamespaces completes the names of the namespaces.
+func CompleteNamespaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config, err := completionConfig(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	namespaces, err := kubernetes.NewForConfigOrDie(config).CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, namespace := range namespaces.Items {
+		names = append(names, namespace.Name)
+	}
+	return filterNames(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionConfig builds the config from the master and kubeconfig flags of the command, if any.
+func completionConfig(cmd *cobra.Command) (*rest.Config, error) {
+	config, err := BuildConfig(flagValue(cmd, "master"), flagValue(cmd, "kubeconfig"))
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = completionTimeout
+	return config, nil
+}
+
+// completionNamespace returns the namespace given to the command, "default" if none.
+func completionNamespace(cmd *cobra.Command) string {
+	if namespace := flagValue(cmd, "namespace"); namespace != "" {
+		return namespace
+	}
+	return "default"
+}
+
+func flagValue(cmd *cobra.Command, name string) string {
+	if flag := cmd.Flags().Lookup(name); flag != nil {
+		return flag.Value.String()
+	}
+	return ""
+}
+
+// filterNames returns the sorted names starting with the prefix.
+func filterNames(names []string, prefix string) []string {
+	var filtered []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			filtered = append(filtered, name)
+		}
+	}
+	sort.Strings(filtered)
+	return filtered
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestCompleteJobNames(t *testing.T) {
+	response := &v1alpha1.JobList{
+		Items: []v1alpha1.Job{
+			{ObjectMeta: metav1.ObjectMeta{Name: "train-b"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "serve"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "train-a"}},
+		},
+	}
+	server := CreateTestServer(response)
+	defer server.Close()
+
+	testCases := []struct {
+		Name       string
+		Args       []string
+		ToComplete string
+		Complete   CompletionFunc
+		Expected   []string
+	}{
+		{
+			Name:       "all jobs",
+			ToComplete: "",
+			Complete:   CompleteJobNames,
+			Expected:   []string{"serve", "train-a", "train-b"},
+		},
+		{
+			Name:       "jobs with the prefix",
+			ToComplete: "tr",
+			Complete:   CompleteJobNames,
+			Expected:   []string{"train-a", "train-b"},
+		},
+		{
+			Name:       "first argument",
+			ToComplete: "s",
+			Complete:   CompleteJobNameArg,
+			Expected:   []string{"serve"},
+		},
+		{
+			Name:       "argument after the job name",
+			Args:       []string{"serve"},
+			ToComplete: "",
+			Complete:   CompleteJobNameArg,
+			Expected:   nil,
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cf := &CommonFlags{}
+			InitFlags(cmd, cf)
+			cmd.Flags().String("namespace", "default", "")
+			cf.Master = server.URL
+			cf.Kubeconfig = ""
+
+			names, directive := testcase.Complete(cmd, testcase.Args, testcase.ToComplete)
+			if !reflect.DeepEqual(names, testcase.Expected) {
+				t.Errorf("expected names %v, got %v", testcase.Expected, names)
+			}
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("expected directive %v, got %v", cobra.ShellCompDirectiveNoFileComp, directive)
+			}
+		})
+	}
+}
+
+func TestRegisterFlagCompletions(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("queue", "", "")
+
+	RegisterFlagCompletions(cmd, map[string]CompletionFunc{
+		"queue": CompleteQueueNames,
+		"job":   CompleteJobNames,
+	})
+
+	// Registering the completion of a flag twice fails.
+	if err := cmd.RegisterFlagCompletionFunc("queue", CompleteQueueNames); err == nil {
+		t.Errorf("expected the completion of the queue flag to be registered")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// GlobalFlags are the flags of the root command, which apply to all the command lines.
+type GlobalFlags struct {
+	// Context is the name of the kubeconfig context to use, the current context if not set.
+	Context string
+	// Cluster is the name of the kubeconfig cluster to use, the cluster of the context if not set.
+	Cluster string
+}
+
+var globalFlags = &GlobalFlags{}
+
+// InitGlobalFlags initializes the global flags on the root command.
+func InitGlobalFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&globalFlags.Context, "context", "", "the name of the kubeconfig context to use")
+	cmd.PersistentFlags().StringVar(&globalFlags.Cluster, "cluster", "", "the name of the kubeconfig cluster to use")
+}
+
+// clientConfig returns the client config with the precedence of kubectl: the kubeconfig given by the flag, else
+// the files listed by $KUBECONFIG, else ~/.kube/config, with the context and the cluster of the global flags,
+// and the apiserver given by the master flag.
+func clientConfig(master, kubeconfig string) clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: globalFlags.Context,
+		ClusterInfo:    clientcmdapi.Cluster{Server: master},
+	}
+	overrides.Context.Cluster = globalFlags.Cluster
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// InitAllClustersFlag initializes the flag of the list commands to list in all the contexts of the kubeconfig.
+func InitAllClustersFlag(cmd *cobra.Command, allClusters *bool) {
+	cmd.Flags().BoolVarP(allClusters, "all-clusters", "", false,
+		"list in every context of the kubeconfig, e.g. to see several training clusters at once")
+}
+
+// ValidateAllClusters checks the flags given with the all-clusters flag: the apiserver is given by each
+// context, and the outputs of the contexts are printed one after the other, as tables.
+func ValidateAllClusters(master, output string) error {
+	if master != "" {
+		return fmt.Errorf("--master can not be used with --all-clusters")
+	}
+	if IsStructuredOutput(output) {
+		return fmt.Errorf("--all-clusters does not support the %s output", output)
+	}
+	if globalFlags.Context != "" || globalFlags.Cluster != "" {
+		return fmt.Errorf("--context and --cluster can not be used with --all-clusters")
+	}
+	return nil
+}
+
+// ForEachContext runs fn with the config of every context of the kubeconfig, in the order of their names,
+// printing the name of the context before its output. The error of a context is printed in its output
+// and does not stop the others; an ExitError is returned if any context failed.
+func ForEachContext(kubeconfig string, writer io.Writer, fn func(config *rest.Config) error) error {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	rawConfig, err := rules.Load()
+	if err != nil {
+		return err
+	}
+
+	var contexts []string
+	for name := range rawConfig.Contexts {
+		contexts = append(contexts, name)
+	}
+	if len(contexts) == 0 {
+		return fmt.Errorf("no contexts found in the kubeconfig")
+	}
+	sort.Strings(contexts)
+
+	failed := false
+	for i, name := range contexts {
+		if i > 0 {
+			fmt.Fprintln(writer)
+		}
+		fmt.Fprintf(writer, "Context: %s\n", name)
+
+		config, err := clientcmd.NewNonInteractiveClientConfig(*rawConfig, name, &clientcmd.ConfigOverrides{}, rules).ClientConfig()
+		if err == nil {
+			err = fn(config)
+		}
+		if err != nil {
+			fmt.Fprintf(writer, "Failed in context %s: %v\n", name, err)
+			failed = true
+		}
+	}
+	if failed {
+		return &ExitError{Code: ExitCodeError}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: train
+  cluster:
+    server: https://train.example.com
+- name: serve
+  cluster:
+    server: https://serve.example.com
+contexts:
+- name: train
+  context:
+    cluster: train
+    user: admin
+- name: serve
+  context:
+    cluster: serve
+    user: admin
+current-context: train
+users:
+- name: admin
+  user:
+    token: token
+`
+
+func writeKubeconfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuildConfig(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, testKubeconfig)
+	defer func() { *globalFlags = GlobalFlags{} }()
+
+	testCases := []struct {
+		Name           string
+		Master         string
+		Kubeconfig     string
+		Env            string
+		Flags          GlobalFlags
+		ExpectedServer string
+	}{
+		{
+			Name:           "current context",
+			Kubeconfig:     kubeconfig,
+			ExpectedServer: "https://train.example.com",
+		},
+		{
+			Name:           "context flag",
+			Kubeconfig:     kubeconfig,
+			Flags:          GlobalFlags{Context: "serve"},
+			ExpectedServer: "https://serve.example.com",
+		},
+		{
+			Name:           "cluster flag",
+			Kubeconfig:     kubeconfig,
+			Flags:          GlobalFlags{Context: "train", Cluster: "serve"},
+			ExpectedServer: "https://serve.example.com",
+		},
+		{
+			Name:           "master flag",
+			Master:         "https://master.example.com",
+			Kubeconfig:     kubeconfig,
+			Flags:          GlobalFlags{Context: "serve"},
+			ExpectedServer: "https://master.example.com",
+		},
+		{
+			Name:           "KUBECONFIG files",
+			Env:            writeKubeconfig(t, "apiVersion: v1\nkind: Config\n") + string(os.PathListSeparator) + kubeconfig,
+			Flags:          GlobalFlags{Context: "serve"},
+			ExpectedServer: "https://serve.example.com",
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", testcase.Env)
+			*globalFlags = testcase.Flags
+
+			config, err := BuildConfig(testcase.Master, testcase.Kubeconfig)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if config.Host != testcase.ExpectedServer {
+				t.Errorf("expected server %s, got %s", testcase.ExpectedServer, config.Host)
+			}
+		})
+	}
+
+	*globalFlags = GlobalFlags{Context: "missing"}
+	if _, err := BuildConfig("", kubeconfig); err == nil {
+		t.Errorf("expected an error for a missing context")
+	}
+}
+
+func TestForEachContext(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, testKubeconfig)
+
+	var buf bytes.Buffer
+	err := ForEachContext(kubeconfig, &buf, func(config *rest.Config) error {
+		if config.Host == "https://serve.example.com" {
+			return fmt.Errorf("unreachable")
+		}
+		fmt.Fprintf(&buf, "%s\n", config.Host)
+		return nil
+	})
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeError {
+		t.Errorf("expected exit code %d, got %v", ExitCodeError, err)
+	}
+	expected := "Context: serve\nFailed in context serve: unreachable\n\nContext: train\nhttps://train.example.com\n"
+	if buf.String() != expected {
+		t.Errorf("expected output %q, got %q", expected, buf.String())
+	}
+}
+
+func TestValidateAllClusters(t *testing.T) {
+	defer func() { *globalFlags = GlobalFlags{} }()
+
+	testCases := []struct {
+		Name      string
+		Master    string
+		Output    string
+		Flags     GlobalFlags
+		ExpectErr bool
+	}{
+		{Name: "table output"},
+		{Name: "wide output", Output: OutputWide},
+		{Name: "json output", Output: OutputJSON, ExpectErr: true},
+		{Name: "master", Master: "https://master.example.com", ExpectErr: true},
+		{Name: "context", Flags: GlobalFlags{Context: "train"}, ExpectErr: true},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			*globalFlags = testcase.Flags
+			err := ValidateAllClusters(testcase.Master, testcase.Output)
+			if (err != nil) != testcase.ExpectErr {
+				t.Errorf("expected error %v, got %v", testcase.ExpectErr, err)
+			}
+		})
+	}
+}
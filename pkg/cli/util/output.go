@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// The machine-readable output formats of the commands.
+const (
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+	// OutputWide prints the table of a list command with additional columns.
+	OutputWide = "wide"
+)
+
+// The exit codes of the commands, for scripts to build on.
+const (
+	// ExitCodeError is the exit code of a command which failed.
+	ExitCodeError = 1
+	// ExitCodeJobFailed is the exit code of a command whose job is Failed.
+	ExitCodeJobFailed = 2
+	// ExitCodeJobAborted is the exit code of a command whose job is Aborted or Terminated.
+	ExitCodeJobAborted = 3
+	// ExitCodeNotFound is the exit code of a command whose object does not exist.
+	ExitCodeNotFound = 4
+	// ExitCodeTimeout is the exit code of a command which timed out waiting for a job.
+	ExitCodeTimeout = 5
+)
+
+// InitOutputFlag initializes the output flag of the commands printing objects; wide is only
+// supported by the commands printing tables with additional columns.
+func InitOutputFlag(cmd *cobra.Command, output *string, wide bool) {
+	formats := "json or yaml"
+	if wide {
+		formats = "json, yaml or wide"
+	}
+	cmd.Flags().StringVarP(output, "output", "o", "", "the output format, one of "+formats)
+}
+
+// ValidateOutput checks the output format given by the output flag.
+func ValidateOutput(output string, wide bool) error {
+	switch output {
+	case "", OutputJSON, OutputYAML:
+		return nil
+	case OutputWide:
+		if wide {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format %q", output)
+}
+
+// IsStructuredOutput returns whether the output format prints the objects rather than a table.
+func IsStructuredOutput(output string) bool {
+	return output == OutputJSON || output == OutputYAML
+}
+
+// PrintObject prints the object into writer in the json or yaml output format.
+func PrintObject(writer io.Writer, output string, obj interface{}) error {
+	var data []byte
+	var err error
+	switch output {
+	case OutputJSON:
+		if data, err = json.MarshalIndent(obj, "", "    "); err == nil {
+			data = append(data, '\n')
+		}
+	case OutputYAML:
+		data, err = yaml.Marshal(obj)
+	default:
+		return fmt.Errorf("unsupported output format %q", output)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+// JobExitError returns the ExitError of a job which did not complete, nil otherwise.
+func JobExitError(job *v1alpha1.Job) error {
+	switch job.Status.State.Phase {
+	case v1alpha1.Failed:
+		return &ExitError{Code: ExitCodeJobFailed}
+	case v1alpha1.Aborted, v1alpha1.Terminated:
+		return &ExitError{Code: ExitCodeJobAborted}
+	}
+	return nil
+}
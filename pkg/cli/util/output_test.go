@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestPrintObject(t *testing.T) {
+	obj := map[string]interface{}{"name": "job1", "replicas": 2}
+
+	testCases := []struct {
+		name      string
+		output    string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "json",
+			output:   OutputJSON,
+			expected: "{\n    \"name\": \"job1\",\n    \"replicas\": 2\n}\n",
+		},
+		{
+			name:     "yaml",
+			output:   OutputYAML,
+			expected: "name: job1\nreplicas: 2\n",
+		},
+		{
+			name:      "wide is not structured",
+			output:    OutputWide,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := PrintObject(out, tc.output, obj)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	if err := ValidateOutput(OutputWide, false); err == nil {
+		t.Errorf("expected error for wide output of a command without table")
+	}
+	for _, output := range []string{"", OutputJSON, OutputYAML, OutputWide} {
+		if err := ValidateOutput(output, true); err != nil {
+			t.Errorf("unexpected error for output %q: %v", output, err)
+		}
+	}
+	if err := ValidateOutput("xml", true); err == nil {
+		t.Errorf("expected error for unsupported output")
+	}
+}
+
+func TestJobExitError(t *testing.T) {
+	testCases := []struct {
+		phase    v1alpha1.JobPhase
+		exitCode int
+	}{
+		{phase: v1alpha1.Running},
+		{phase: v1alpha1.Completed},
+		{phase: v1alpha1.Failed, exitCode: ExitCodeJobFailed},
+		{phase: v1alpha1.Aborted, exitCode: ExitCodeJobAborted},
+		{phase: v1alpha1.Terminated, exitCode: ExitCodeJobAborted},
+	}
+
+	for _, tc := range testCases {
+		job := &v1alpha1.Job{Status: v1alpha1.JobStatus{State: v1alpha1.JobState{Phase: tc.phase}}}
+		err := JobExitError(job)
+		var exitErr *ExitError
+		switch {
+		case tc.exitCode == 0 && err != nil:
+			t.Errorf("phase %s: unexpected error %v", tc.phase, err)
+		case tc.exitCode != 0 && (!errors.As(err, &exitErr) || exitErr.Code != tc.exitCode):
+			t.Errorf("phase %s: expected exit code %d, got %v", tc.phase, tc.exitCode, err)
+		}
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -34,7 +33,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 
 	vcbus "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/apis/helpers"
@@ -50,14 +48,24 @@ type CommonFlags struct {
 // InitFlags initializes the common flags for most command lines.
 func InitFlags(cmd *cobra.Command, cf *CommonFlags) {
 	cmd.Flags().StringVarP(&cf.Master, "master", "s", "", "the address of apiserver")
+	InitKubeconfigFlag(cmd, &cf.Kubeconfig)
+}
 
-	kubeConfFile := os.Getenv("KUBECONFIG")
-	if kubeConfFile == "" {
-		if home := HomeDir(); home != "" {
-			kubeConfFile = filepath.Join(home, ".kube", "config")
-		}
-	}
-	cmd.Flags().StringVarP(&cf.Kubeconfig, "kubeconfig", "k", kubeConfFile, "(optional) absolute path to the kubeconfig file")
+// InitKubeconfigFlag initializes the kubeconfig flag. When it is not set, the files listed by $KUBECONFIG
+// are merged, as by kubectl, else ~/.kube/config is used.
+func InitKubeconfigFlag(cmd *cobra.Command, kubeconfig *string) {
+	cmd.Flags().StringVarP(kubeconfig, "kubeconfig", "k", "",
+		"(optional) absolute path to the kubeconfig file, $KUBECONFIG or ~/.kube/config if not set")
+}
+
+// ExitError is returned by a command which should exit with the given code, e.g. the exit
+// code of a command executed in a pod, without printing an error.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit code %d", e.Code)
 }
 
 // HomeDir gets the env $HOME.
@@ -68,9 +76,9 @@ func HomeDir() string {
 	return os.Getenv("USERPROFILE") // windows
 }
 
-// BuildConfig builds the configure file for command lines.
+// BuildConfig builds the configure file for command lines, with the context and the cluster given by the global flags.
 func BuildConfig(master, kubeconfig string) (*rest.Config, error) {
-	return clientcmd.BuildConfigFromFlags(master, kubeconfig)
+	return clientConfig(master, kubeconfig).ClientConfig()
 }
 
 // PopulateResourceListV1 takes strings of form <resourceName1>=<value1>,<resourceName2>=<value2> and returns ResourceList.
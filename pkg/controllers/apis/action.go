@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis
+
+import (
+	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+const (
+	// RestartPodAction recreates only the failed pods of a job with the same name and index,
+	// while the other replicas keep running. It is meant for frameworks supporting worker rejoin.
+	RestartPodAction v1alpha1.Action = "RestartPod"
+	// RequeueJobAction moves a pending job to the queue named by its
+	// volcano.sh/pending-timeout-queue annotation.
+	RequeueJobAction v1alpha1.Action = "RequeueJob"
+	// DrainQueueAction moves a queue to the Draining state.
+	DrainQueueAction v1alpha1.Action = "DrainQueue"
+)
+
+const (
+	// QueueStateDraining is the state of a queue which accepts no new podgroups and schedules
+	// nothing new, while the running jobs of the queue keep running until they finish. The queue
+	// becomes Closed when all its podgroups are gone.
+	QueueStateDraining v1beta1.QueueState = "Draining"
+)
+
+const (
+	// PodPendingTimeoutEvent is triggered when pods of a task have not been scheduled
+	// within the timeout of the policy handling this event.
+	PodPendingTimeoutEvent v1alpha1.Event = "PodPendingTimeout"
+)
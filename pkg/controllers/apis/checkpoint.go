@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CheckpointGracePeriodKey enables the checkpoint protocol of a pod, e.g. "2m". Before
+	// evicting the pod, the scheduler requests a checkpoint and waits up to the grace period
+	// for the pod to report it, so that the framework can persist its state.
+	CheckpointGracePeriodKey = "volcano.sh/checkpoint-grace-period"
+	// CheckpointRequestedKey is the pod annotation set by the scheduler, in RFC3339, when it
+	// requests a checkpoint before evicting the pod.
+	CheckpointRequestedKey = "volcano.sh/checkpoint-requested"
+	// CheckpointStatusKey is the pod annotation set by the application to report the
+	// requested checkpoint, either Completed or Failed.
+	CheckpointStatusKey = "volcano.sh/checkpoint-status"
+	// CheckpointPathKey is the pod annotation set by the application to report where the
+	// completed checkpoint is stored.
+	CheckpointPathKey = "volcano.sh/checkpoint-path"
+)
+
+const (
+	// CheckpointCompleted means the application persisted its state.
+	CheckpointCompleted = "Completed"
+	// CheckpointFailed means the application failed to persist its state.
+	CheckpointFailed = "Failed"
+)
+
+// GetCheckpointGracePeriod returns how long to wait for the checkpoint of the pod before
+// evicting it, zero if the pod does not take part in the checkpoint protocol.
+func GetCheckpointGracePeriod(pod *v1.Pod) time.Duration {
+	value, found := pod.Annotations[CheckpointGracePeriodKey]
+	if !found {
+		return 0
+	}
+	grace, err := time.ParseDuration(value)
+	if err != nil || grace < 0 {
+		return 0
+	}
+	return grace
+}
+
+// GetCheckpointRequested returns when the checkpoint of the pod was requested.
+func GetCheckpointRequested(pod *v1.Pod) (time.Time, bool) {
+	value, found := pod.Annotations[CheckpointRequestedKey]
+	if !found {
+		return time.Time{}, false
+	}
+	requested, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return requested, true
+}
+
+// CheckpointReported returns whether the pod reported the requested checkpoint.
+func CheckpointReported(pod *v1.Pod) bool {
+	switch pod.Annotations[CheckpointStatusKey] {
+	case CheckpointCompleted, CheckpointFailed:
+		return true
+	default:
+		return false
+	}
+}
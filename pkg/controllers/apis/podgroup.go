@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// GangNameKey is the pod annotation naming the gang of a pod not managed by a volcano job. The
+	// podgroup controller creates a PodGroup with that name for the pods of the namespace sharing it,
+	// so that operators get gang scheduling by annotating their pods only.
+	GangNameKey = "volcano.sh/gang-name"
+	// GangMinMemberKey is the pod annotation with the minimal number of pods of the gang which must
+	// be scheduled together, 1 if not set.
+	GangMinMemberKey = "volcano.sh/gang-min-member"
+	// PodGroupFinishedTimeKey is the podgroup annotation recording, in RFC3339, the time the garbage
+	// collector first found the podgroup finished, from which the TTL of finished podgroups counts.
+	PodGroupFinishedTimeKey = "volcano.sh/finished-time"
+)
+
+// ParseGang returns the name and the minimal member of the gang of the pod set by its annotations,
+// an empty name if the pod is not in a gang.
+func ParseGang(annotations map[string]string) (string, int32, error) {
+	name, found := annotations[GangNameKey]
+	if !found {
+		return "", 0, nil
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return "", 0, fmt.Errorf("invalid annotation %s <%s>: %s", GangNameKey, name, strings.Join(errs, ", "))
+	}
+
+	value, found := annotations[GangMinMemberKey]
+	if !found {
+		return name, 1, nil
+	}
+	minMember, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || minMember <= 0 {
+		return "", 0, fmt.Errorf("invalid annotation %s <%s>: must be a positive integer", GangMinMemberKey, value)
+	}
+	return name, int32(minMember), nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// QueueRequestedKey is the queue annotation published by the queue controller with the sum of
+	// the minimal resources of the podgroups in the queue which are not completed, as a JSON
+	// resource list.
+	QueueRequestedKey = "volcano.sh/queue-requested"
+	// QueueOldestPendingKey is the queue annotation published by the queue controller with the
+	// creation time of the oldest pending podgroup in the queue, in RFC3339 format.
+	QueueOldestPendingKey = "volcano.sh/queue-oldest-pending"
+)
+
+const (
+	// QueueDrainTargetKey is the queue annotation naming the queue the podgroups of the queue are
+	// moved to while it is draining. Only the podgroups which are not running yet are moved.
+	QueueDrainTargetKey = "volcano.sh/drain-target-queue"
+	// QueueDrainEvictAfterKey is the queue annotation with the duration, e.g. 2h, after which the
+	// pods of the podgroups still running in the draining queue are evicted.
+	QueueDrainEvictAfterKey = "volcano.sh/drain-evict-after"
+	// QueueDrainStartedKey is the queue annotation set by the queue controller with the time the
+	// queue started draining, in RFC3339 format.
+	QueueDrainStartedKey = "volcano.sh/drain-started"
+)
+
+// ParseDrainEvictAfter returns the duration of the volcano.sh/drain-evict-after annotation,
+// zero if it is not set.
+func ParseDrainEvictAfter(annotations map[string]string) (time.Duration, error) {
+	value, found := annotations[QueueDrainEvictAfterKey]
+	if !found {
+		return 0, nil
+	}
+	evictAfter, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid annotation %s <%s>: %v", QueueDrainEvictAfterKey, value, err)
+	}
+	if evictAfter < 0 {
+		return 0, fmt.Errorf("invalid annotation %s <%s>: must not be negative", QueueDrainEvictAfterKey, value)
+	}
+	return evictAfter, nil
+}
+
+// DrainEvictionDeadline returns the time after which the running pods of the draining queue are
+// evicted, false if they are never evicted.
+func DrainEvictionDeadline(annotations map[string]string) (time.Time, bool, error) {
+	evictAfter, err := ParseDrainEvictAfter(annotations)
+	if err != nil || evictAfter == 0 {
+		return time.Time{}, false, err
+	}
+	value, found := annotations[QueueDrainStartedKey]
+	if !found {
+		return time.Time{}, false, nil
+	}
+	started, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid annotation %s <%s>: %v", QueueDrainStartedKey, value, err)
+	}
+	return started.Add(evictAfter), true, nil
+}
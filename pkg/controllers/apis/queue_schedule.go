@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// QueueCapabilityScheduleKey is the queue annotation changing the capability of the queue by
+	// time window, as a JSON CapabilitySchedule, e.g. the research queue gets more GPUs at night:
+	// {"timeZone":"Europe/Paris","windows":[{"start":"20:00","end":"08:00","capability":{"nvidia.com/gpu":"24"}}]}
+	QueueCapabilityScheduleKey = "volcano.sh/capability-schedule"
+	// QueueBaseCapabilityKey is the queue annotation set by the queue controller with the capability
+	// of the queue spec, as a JSON resource list, while a window of the schedule applies. The
+	// capability is restored when no window applies.
+	QueueBaseCapabilityKey = "volcano.sh/base-capability"
+	// QueueCapabilityWindowKey is the queue annotation set by the queue controller with the index of
+	// the window of the schedule whose capability it set in the queue spec, so that the capability
+	// is only changed when a window starts or ends.
+	QueueCapabilityWindowKey = "volcano.sh/capability-window"
+)
+
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday, "Mon": time.Monday, "Tue": time.Tuesday, "Wed": time.Wednesday,
+	"Thu": time.Thursday, "Fri": time.Friday, "Sat": time.Saturday,
+}
+
+// CapabilitySchedule is the capability of a queue by time window.
+type CapabilitySchedule struct {
+	// TimeZone is the IANA time zone of the windows, UTC by default.
+	TimeZone string `json:"timeZone,omitempty"`
+	// Windows are the time windows, the first window covering the time applies.
+	Windows []CapabilityWindow `json:"windows"`
+
+	location *time.Location
+}
+
+// CapabilityWindow is the capability of a queue in a time window.
+type CapabilityWindow struct {
+	// Days are the days the window starts, e.g. ["Sat","Sun"], every day by default.
+	Days []string `json:"days,omitempty"`
+	// Start and End are the times of the window as HH:MM, 00:00 and 24:00 by default.
+	// A window ending before it starts ends the next day.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+	// Capability is the capability of the queue in the window.
+	Capability v1.ResourceList `json:"capability"`
+
+	days       map[time.Weekday]bool
+	start, end int
+}
+
+// ParseCapabilitySchedule parses the capability schedule annotation of a queue, nil if it is not set.
+func ParseCapabilitySchedule(annotations map[string]string) (*CapabilitySchedule, error) {
+	value, found := annotations[QueueCapabilityScheduleKey]
+	if !found {
+		return nil, nil
+	}
+	schedule := &CapabilitySchedule{}
+	if err := json.Unmarshal([]byte(value), schedule); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %v", QueueCapabilityScheduleKey, err)
+	}
+
+	location, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone <%s> of annotation %s: %v", schedule.TimeZone, QueueCapabilityScheduleKey, err)
+	}
+	schedule.location = location
+
+	for i := range schedule.Windows {
+		window := &schedule.Windows[i]
+		if window.days, err = parseWeekdays(window.Days); err != nil {
+			return nil, fmt.Errorf("invalid window %d of annotation %s: %v", i, QueueCapabilityScheduleKey, err)
+		}
+		if window.start, err = parseClock(window.Start, 0); err != nil {
+			return nil, fmt.Errorf("invalid window %d of annotation %s: %v", i, QueueCapabilityScheduleKey, err)
+		}
+		if window.end, err = parseClock(window.End, 24*60); err != nil {
+			return nil, fmt.Errorf("invalid window %d of annotation %s: %v", i, QueueCapabilityScheduleKey, err)
+		}
+		for name, quantity := range window.Capability {
+			if quantity.Sign() < 0 {
+				return nil, fmt.Errorf("invalid window %d of annotation %s: negative capability of %s", i, QueueCapabilityScheduleKey, name)
+			}
+		}
+	}
+	return schedule, nil
+}
+
+func parseWeekdays(days []string) (map[time.Weekday]bool, error) {
+	if len(days) == 0 {
+		return nil, nil
+	}
+	result := map[time.Weekday]bool{}
+	for _, day := range days {
+		weekday, found := weekdays[day]
+		if !found {
+			return nil, fmt.Errorf("unknown day <%s>, expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", day)
+		}
+		result[weekday] = true
+	}
+	return result, nil
+}
+
+// parseClock parses HH:MM into the minutes since midnight.
+func parseClock(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time <%s>, expected HH:MM", value)
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+// covers returns whether the window covers the time.
+func (w *CapabilityWindow) covers(now time.Time) bool {
+	minute := now.Hour()*60 + now.Minute()
+	onDay := func(t time.Time) bool {
+		return w.days == nil || w.days[t.Weekday()]
+	}
+	if w.start < w.end {
+		return onDay(now) && minute >= w.start && minute < w.end
+	}
+	if w.start == w.end {
+		return onDay(now)
+	}
+	// the window ends the day after it starts
+	return (onDay(now) && minute >= w.start) || (onDay(now.AddDate(0, 0, -1)) && minute < w.end)
+}
+
+// Window returns the index of the first window covering the time, -1 if no window covers it.
+func (s *CapabilitySchedule) Window(now time.Time) int {
+	now = now.In(s.location)
+	for i := range s.Windows {
+		if s.Windows[i].covers(now) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Capability returns the capability of the first window covering the time, and whether a window covers it.
+func (s *CapabilitySchedule) Capability(now time.Time) (v1.ResourceList, bool) {
+	if window := s.Window(now); window >= 0 {
+		return s.Windows[window].Capability, true
+	}
+	return nil, false
+}
+
+// BaseCapability returns the capability of the queue out of the windows of its schedule, i.e.
+// the capability recorded by the queue controller while a window applies, or the capability of
+// the queue spec.
+func BaseCapability(annotations map[string]string, capability v1.ResourceList) v1.ResourceList {
+	value, found := annotations[QueueBaseCapabilityKey]
+	if !found {
+		return capability
+	}
+	base := v1.ResourceList{}
+	if err := json.Unmarshal([]byte(value), &base); err != nil {
+		return capability
+	}
+	return base
+}
+
+// ScheduledCapability returns the capability of the queue at the time, by its capability schedule.
+// The capability of the queue spec applies to the queues without a valid schedule.
+func ScheduledCapability(annotations map[string]string, capability v1.ResourceList, now time.Time) v1.ResourceList {
+	schedule, err := ParseCapabilitySchedule(annotations)
+	if err != nil || schedule == nil {
+		return capability
+	}
+	if scheduled, found := schedule.Capability(now); found {
+		return scheduled
+	}
+	return BaseCapability(annotations, capability)
+}
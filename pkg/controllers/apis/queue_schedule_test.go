@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestScheduledCapability(t *testing.T) {
+	base := v1.ResourceList{v1.ResourceCPU: resource.MustParse("8")}
+	night := v1.ResourceList{v1.ResourceCPU: resource.MustParse("64")}
+	weekend := v1.ResourceList{v1.ResourceCPU: resource.MustParse("32")}
+	schedule := `{"timeZone":"Europe/Paris","windows":[` +
+		`{"days":["Fri"],"start":"20:00","end":"08:00","capability":{"cpu":"64"}},` +
+		`{"days":["Sat","Sun"],"capability":{"cpu":"32"}}]}`
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		now         time.Time
+		expected    v1.ResourceList
+	}{
+		{
+			name:     "no schedule",
+			now:      time.Date(2024, 1, 5, 21, 0, 0, 0, paris),
+			expected: base,
+		},
+		{
+			name:        "out of the windows",
+			annotations: map[string]string{QueueCapabilityScheduleKey: schedule},
+			now:         time.Date(2024, 1, 5, 12, 0, 0, 0, paris),
+			expected:    base,
+		},
+		{
+			name:        "window of the night",
+			annotations: map[string]string{QueueCapabilityScheduleKey: schedule},
+			now:         time.Date(2024, 1, 5, 21, 0, 0, 0, paris),
+			expected:    night,
+		},
+		{
+			name:        "window of the night ends the next day",
+			annotations: map[string]string{QueueCapabilityScheduleKey: schedule},
+			now:         time.Date(2024, 1, 6, 7, 59, 0, 0, paris),
+			expected:    night,
+		},
+		{
+			name:        "window of the weekend",
+			annotations: map[string]string{QueueCapabilityScheduleKey: schedule},
+			now:         time.Date(2024, 1, 6, 8, 0, 0, 0, paris),
+			expected:    weekend,
+		},
+		{
+			name:        "windows are in the time zone of the schedule",
+			annotations: map[string]string{QueueCapabilityScheduleKey: schedule},
+			now:         time.Date(2024, 1, 5, 19, 30, 0, 0, time.UTC),
+			expected:    night,
+		},
+		{
+			name: "base capability out of the windows",
+			annotations: map[string]string{
+				QueueCapabilityScheduleKey: schedule,
+				QueueBaseCapabilityKey:     `{"cpu":"4"}`,
+			},
+			now:      time.Date(2024, 1, 8, 12, 0, 0, 0, paris),
+			expected: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+		},
+		{
+			name:        "invalid schedule",
+			annotations: map[string]string{QueueCapabilityScheduleKey: `{"windows":[{"days":["Someday"]}]}`},
+			now:         time.Date(2024, 1, 6, 12, 0, 0, 0, paris),
+			expected:    base,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			capability := ScheduledCapability(tc.annotations, base, tc.now)
+			if !equality.Semantic.DeepEqual(capability, tc.expected) {
+				t.Errorf("expected capability %v, got %v", tc.expected, capability)
+			}
+		})
+	}
+}
@@ -34,6 +34,10 @@ type Request struct {
 	ExitCode   int32
 	Action     v1alpha1.Action
 	JobVersion int32
+
+	// Reason and Message are given by the user issuing the command, e.g. why an admin aborted the job.
+	Reason  string
+	Message string
 }
 
 // String function returns the request in string format.
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis
+
+import (
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ShrinkOnReclaimKey makes the scheduler ask the job controller to scale the job down, rather
+	// than evicting its pods, when their resources are reclaimed. The job keeps running at a reduced
+	// parallelism, and its victims are chosen by victim cost and the highest index first.
+	ShrinkOnReclaimKey = "volcano.sh/shrink-on-reclaim"
+	// ShrinkRequestedKey is the pod annotation set by the scheduler, in RFC3339, when it asks the
+	// job controller to remove the replica of the pod by scaling its task down.
+	ShrinkRequestedKey = "volcano.sh/shrink-requested"
+)
+
+// IsShrinkOnReclaim returns whether the job of the pod is scaled down when its resources are reclaimed.
+func IsShrinkOnReclaim(pod *v1.Pod) bool {
+	shrink, err := strconv.ParseBool(pod.Annotations[ShrinkOnReclaimKey])
+	return err == nil && shrink
+}
+
+// IsShrinkRequested returns whether the scheduler asked to remove the replica of the pod.
+func IsShrinkRequested(pod *v1.Pod) bool {
+	_, found := pod.Annotations[ShrinkRequestedKey]
+	return found
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events is the catalog of the reasons of the events the controllers record. The type of an event
+// follows from its reason, but for the reasons of the actions, e.g. OpenQueue, which are recorded as warnings
+// when the action fails.
+package events
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// Reason is the reason of an event recorded by the controllers.
+type Reason string
+
+// Reasons of the events of the jobs.
+const (
+	// JobStarted is recorded when a job is running.
+	JobStarted Reason = "JobStarted"
+	// JobRestarting is recorded when a job is restarting.
+	JobRestarting Reason = "JobRestarting"
+	// JobCompleted is recorded when a job is completed.
+	JobCompleted Reason = "JobCompleted"
+	// JobFailed is recorded when a job is failed.
+	JobFailed Reason = "JobFailed"
+	// JobAborted is recorded when a job is aborted.
+	JobAborted Reason = "JobAborted"
+	// JobTerminated is recorded when a job is terminated.
+	JobTerminated Reason = "JobTerminated"
+	// ExecuteAction is recorded when the controller starts to execute an action on a job, e.g. RestartJob,
+	// and as a warning when a job or a pod fails after its retries.
+	ExecuteAction Reason = "ExecuteAction"
+	// CommandIssued is recorded when a command on a job is received.
+	CommandIssued Reason = "CommandIssued"
+	// TaskRestarted is recorded when the failed pods of a task are restarted.
+	TaskRestarted Reason = "TaskRestarted"
+	// PreemptedByQueueReclaim is recorded when the scheduler evicts a pod of a job to give its resources
+	// back to another queue.
+	PreemptedByQueueReclaim Reason = "PreemptedByQueueReclaim"
+	// Preempted is recorded when the scheduler evicts a pod of a job for a job of a higher priority.
+	Preempted Reason = "Preempted"
+	// EvictedByScheduler is recorded when the scheduler evicts a pod of a job for another reason, e.g. shuffle.
+	EvictedByScheduler Reason = "EvictedByScheduler"
+	// PluginError is recorded when a job plugin fails, e.g. on the creation of a job.
+	PluginError Reason = "PluginError"
+	// JobStatusError is recorded when the status of a new job can not be initialized.
+	JobStatusError Reason = "JobStatusError"
+	// PVCError is recorded when the volumes of a job can not be created.
+	PVCError Reason = "PVCError"
+	// FailedDeletePVC is recorded when the volumes of a finished job can not be deleted.
+	FailedDeletePVC Reason = "FailedDeletePVC"
+	// PodGroupError is recorded when the podgroup of a job can not be created or updated.
+	PodGroupError Reason = "PodGroupError"
+	// PodGroupPending is recorded when the podgroup of a job is not scheduled.
+	PodGroupPending Reason = "PodGroupPending"
+	// FailedCreatePDB is recorded when the PodDisruptionBudget of a job can not be created or updated.
+	FailedCreatePDB Reason = "FailedCreatePDB"
+	// FailedCreatePod is recorded when pods of a job can not be created.
+	FailedCreatePod Reason = "FailedCreate"
+	// FailedDeletePod is recorded when pods of a job can not be deleted.
+	FailedDeletePod Reason = "FailedDelete"
+	// PodPendingTimeout is recorded when pods of a job are pending longer than the timeout of its
+	// PodPendingTimeout policy.
+	PodPendingTimeout Reason = "PodPendingTimeout"
+	// Requeued is recorded when a pending job is moved to another queue.
+	Requeued Reason = "Requeued"
+	// FailedRequeue is recorded when a pending job can not be moved to another queue.
+	FailedRequeue Reason = "FailedRequeue"
+	// Rerun is recorded when a job cloned from a finished job is initiated.
+	Rerun Reason = "Rerun"
+	// ScaledDown is recorded when the pods beyond the replicas of a task are deleted.
+	ScaledDown Reason = "ScaledDown"
+	// ParallelismChanged is recorded when the number of running replicas of a job which grows after
+	// start is changed.
+	ParallelismChanged Reason = "ParallelismChanged"
+	// ShrunkOnReclaim is recorded when a task is scaled down to remove the replicas whose pods the
+	// scheduler reclaims.
+	ShrunkOnReclaim Reason = "ShrunkOnReclaim"
+	// Autoscaled is recorded when the tasks of a job are scaled by the job autoscaler.
+	Autoscaled Reason = "Autoscaled"
+	// FailedAutoscale is recorded when the tasks of a job can not be scaled by the job autoscaler.
+	FailedAutoscale Reason = "FailedAutoscale"
+)
+
+// Reasons of the events of the queues.
+const (
+	// QueueStateChanged is recorded when the state of a queue changes.
+	QueueStateChanged Reason = "StateChanged"
+	// OpenQueue is recorded when a queue is opened, and as a warning when it can not be.
+	OpenQueue Reason = "OpenQueue"
+	// CloseQueue is recorded when a queue is closed, and as a warning when it can not be.
+	CloseQueue Reason = "CloseQueue"
+	// DrainQueue is recorded when a queue is drained, and as a warning when it can not be.
+	DrainQueue Reason = "DrainQueue"
+	// InvalidDrainPolicy is recorded when the drain policy of a queue is invalid.
+	InvalidDrainPolicy Reason = "InvalidDrainPolicy"
+	// PodGroupMoved is recorded when a podgroup of a draining queue is moved to another queue.
+	PodGroupMoved Reason = "PodGroupMoved"
+	// PodGroupEvicted is recorded when a podgroup of a draining queue is evicted.
+	PodGroupEvicted Reason = "PodGroupEvicted"
+	// InvalidCapabilitySchedule is recorded when the capability schedule of a queue is invalid.
+	InvalidCapabilitySchedule Reason = "InvalidCapabilitySchedule"
+	// CapabilityChanged is recorded when the capability of a queue is changed by its schedule.
+	CapabilityChanged Reason = "CapabilityChanged"
+	// Provisioned is recorded when a queue is provisioned for a namespace.
+	Provisioned Reason = "Provisioned"
+)
+
+// Reasons of the events of the jobflows. A request on a queue, a jobflow or a jobtemplate failing after its
+// retries is recorded as a warning with its action as reason.
+const (
+	// CreatedJob is recorded when a jobflow creates a job.
+	CreatedJob Reason = "Created"
+)
+
+// eventTypes is the catalog of the reasons, with the type of their events.
+var eventTypes = map[Reason]string{
+	JobStarted:              v1.EventTypeNormal,
+	JobRestarting:           v1.EventTypeWarning,
+	JobCompleted:            v1.EventTypeNormal,
+	JobFailed:               v1.EventTypeWarning,
+	JobAborted:              v1.EventTypeNormal,
+	JobTerminated:           v1.EventTypeWarning,
+	ExecuteAction:           v1.EventTypeNormal,
+	CommandIssued:           v1.EventTypeNormal,
+	TaskRestarted:           v1.EventTypeWarning,
+	PreemptedByQueueReclaim: v1.EventTypeWarning,
+	Preempted:               v1.EventTypeWarning,
+	EvictedByScheduler:      v1.EventTypeWarning,
+	PluginError:             v1.EventTypeWarning,
+	JobStatusError:          v1.EventTypeWarning,
+	PVCError:                v1.EventTypeWarning,
+	FailedDeletePVC:         v1.EventTypeWarning,
+	PodGroupError:           v1.EventTypeWarning,
+	PodGroupPending:         v1.EventTypeWarning,
+	FailedCreatePDB:         v1.EventTypeWarning,
+	FailedCreatePod:         v1.EventTypeWarning,
+	FailedDeletePod:         v1.EventTypeWarning,
+	PodPendingTimeout:       v1.EventTypeWarning,
+	Requeued:                v1.EventTypeNormal,
+	FailedRequeue:           v1.EventTypeWarning,
+	Rerun:                   v1.EventTypeNormal,
+	ScaledDown:              v1.EventTypeNormal,
+	ParallelismChanged:      v1.EventTypeNormal,
+	ShrunkOnReclaim:         v1.EventTypeNormal,
+	Autoscaled:              v1.EventTypeNormal,
+	FailedAutoscale:         v1.EventTypeWarning,
+
+	QueueStateChanged:         v1.EventTypeNormal,
+	OpenQueue:                 v1.EventTypeNormal,
+	CloseQueue:                v1.EventTypeNormal,
+	DrainQueue:                v1.EventTypeNormal,
+	InvalidDrainPolicy:        v1.EventTypeWarning,
+	PodGroupMoved:             v1.EventTypeNormal,
+	PodGroupEvicted:           v1.EventTypeNormal,
+	InvalidCapabilitySchedule: v1.EventTypeWarning,
+	CapabilityChanged:         v1.EventTypeNormal,
+	Provisioned:               v1.EventTypeNormal,
+
+	CreatedJob: v1.EventTypeNormal,
+}
+
+// EventType returns the type of the events of the reason, Normal or Warning; a reason out of the catalog
+// is a Warning.
+func (r Reason) EventType() string {
+	if eventType, found := eventTypes[r]; found {
+		return eventType
+	}
+	return v1.EventTypeWarning
+}
+
+// Reasons returns the reasons of the catalog, sorted.
+func Reasons() []Reason {
+	reasons := make([]Reason, 0, len(eventTypes))
+	for reason := range eventTypes {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		return reasons[i] < reasons[j]
+	})
+	return reasons
+}
+
+// Record records an event with the reason on the object.
+func Record(recorder record.EventRecorder, object runtime.Object, reason Reason, message string) {
+	recorder.Event(object, reason.EventType(), string(reason), message)
+}
+
+// RecordWarning records a warning event with the reason on the object, whatever the type of the reason: the failure
+// of an action, or the warnings of the pods of a job.
+func RecordWarning(recorder record.EventRecorder, object runtime.Object, reason Reason, message string) {
+	recorder.Event(object, v1.EventTypeWarning, string(reason), message)
+}
+
+// Recordf records an event with the reason on the object, its message formatted as fmt.Sprintf.
+func Recordf(recorder record.EventRecorder, object runtime.Object, reason Reason, format string, args ...interface{}) {
+	Record(recorder, object, reason, fmt.Sprintf(format, args...))
+}
+
+// JobPhaseReason returns the reason of the event recorded when a job enters the phase, and whether the
+// phase has one.
+func JobPhaseReason(phase batch.JobPhase) (Reason, bool) {
+	reason, found := jobPhaseReasons[phase]
+	return reason, found
+}
+
+// jobPhaseReasons are the reasons of the job phases recorded, which are the ones alerting rules match on.
+var jobPhaseReasons = map[batch.JobPhase]Reason{
+	batch.Running:    JobStarted,
+	batch.Restarting: JobRestarting,
+	batch.Completed:  JobCompleted,
+	batch.Failed:     JobFailed,
+	batch.Aborted:    JobAborted,
+	batch.Terminated: JobTerminated,
+}
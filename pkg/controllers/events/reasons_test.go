@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestReasonEventType(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Reason Reason
+		Expect string
+	}{
+		{Name: "job started", Reason: JobStarted, Expect: v1.EventTypeNormal},
+		{Name: "task restarted", Reason: TaskRestarted, Expect: v1.EventTypeWarning},
+		{Name: "plugin error", Reason: PluginError, Expect: v1.EventTypeWarning},
+		{Name: "preempted by queue reclaim", Reason: PreemptedByQueueReclaim, Expect: v1.EventTypeWarning},
+		{Name: "queue opened", Reason: OpenQueue, Expect: v1.EventTypeNormal},
+		{Name: "out of the catalog", Reason: Reason("Unknown"), Expect: v1.EventTypeWarning},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if eventType := testCase.Reason.EventType(); eventType != testCase.Expect {
+				t.Errorf("Test case failed: %s, expect: %v, got: %v", testCase.Name, testCase.Expect, eventType)
+			}
+		})
+	}
+}
+
+func TestReasons(t *testing.T) {
+	reasons := Reasons()
+	if len(reasons) != len(eventTypes) {
+		t.Fatalf("expect %d reasons, got %d", len(eventTypes), len(reasons))
+	}
+	if !sort.SliceIsSorted(reasons, func(i, j int) bool { return reasons[i] < reasons[j] }) {
+		t.Errorf("expect the reasons sorted, got %v", reasons)
+	}
+	for _, reason := range jobPhaseReasons {
+		if _, found := eventTypes[reason]; !found {
+			t.Errorf("expect the reason %s of a job phase in the catalog", reason)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	recorder := record.NewFakeRecorder(2)
+	job := &batch.Job{}
+
+	Record(recorder, job, JobStarted, "job started")
+	Recordf(recorder, job, FailedCreatePod, "failed to create %d pods", 2)
+
+	for _, expect := range []string{"Normal JobStarted job started", "Warning FailedCreate failed to create 2 pods"} {
+		if event := <-recorder.Events; event != expect {
+			t.Errorf("expect event %q, got %q", expect, event)
+		}
+	}
+}
+
+func TestJobPhaseReason(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Phase  batch.JobPhase
+		Expect Reason
+		Found  bool
+	}{
+		{Name: "running", Phase: batch.Running, Expect: JobStarted, Found: true},
+		{Name: "failed", Phase: batch.Failed, Expect: JobFailed, Found: true},
+		{Name: "pending", Phase: batch.Pending, Found: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			reason, found := JobPhaseReason(testCase.Phase)
+			if reason != testCase.Expect || found != testCase.Found {
+				t.Errorf("Test case failed: %s, expect: %v %v, got: %v %v", testCase.Name, testCase.Expect, testCase.Found, reason, found)
+			}
+		})
+	}
+}
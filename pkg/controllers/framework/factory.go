@@ -18,16 +18,22 @@ package framework
 
 import (
 	"fmt"
+	"sort"
 
 	"k8s.io/klog/v2"
 )
 
 var controllers = map[string]Controller{}
 
-// ForeachController is helper function to operator all controllers.
+// ForeachController is helper function to operator all controllers, in the order of their names.
 func ForeachController(fn func(controller Controller)) {
-	for _, ctrl := range controllers {
-		fn(ctrl)
+	names := make([]string, 0, len(controllers))
+	for name := range controllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fn(controllers[name])
 	}
 }
 
@@ -17,6 +17,9 @@ limitations under the License.
 package framework
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -25,6 +28,16 @@ import (
 	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
 )
 
+const (
+	// DefaultGCDeleteQPS and DefaultGCDeleteBurst bound by default the rate of the deletes of the garbage
+	// collector, well below the QPS of the client shared with the other controllers.
+	DefaultGCDeleteQPS   = 10
+	DefaultGCDeleteBurst = 20
+	// DefaultGCDeleteBatchSize is by default the maximal number of resources the garbage collector
+	// deletes in a batch.
+	DefaultGCDeleteBatchSize = 100
+)
+
 // ControllerOption is the main context object for the controllers.
 type ControllerOption struct {
 	KubeClient              kubernetes.Interface
@@ -39,15 +52,92 @@ type ControllerOption struct {
 	WorkerThreadsForPG      uint32
 	WorkerThreadsForGC      uint32
 
+	// JobNotificationURLs are the HTTP endpoints the job controller posts job events to.
+	JobNotificationURLs    []string
+	JobNotificationTimeout time.Duration
+
+	// PropagatedJobLabels and PropagatedJobAnnotations are the allowlists of job metadata
+	// stamped onto the pods and resources the job controller creates.
+	PropagatedJobLabels      []string
+	PropagatedJobAnnotations []string
+
+	// JobEventAggregationPeriod is how often the warning events of the job pods are collapsed
+	// into per-task events on the job; zero disables the aggregation.
+	JobEventAggregationPeriod time.Duration
+	// JobAccountingPeriod is how often the resource-hours of the finished pods are recorded
+	// on their jobs; zero disables the accounting.
+	JobAccountingPeriod time.Duration
+
+	// QueueProvisionConfig is the path of the template of the queues the queue controller
+	// provisions for the namespaces; queues are not provisioned if empty.
+	QueueProvisionConfig string
+
+	// GCScanInterval and GCPluginResourceSweepInterval are how often the garbage collector checks
+	// the finished resources and the orphaned plugin resources; zero means the default.
+	GCScanInterval                time.Duration
+	GCPluginResourceSweepInterval time.Duration
+	// GCDeleteQPS, GCDeleteBurst and GCDeleteBatchSize bound the rate and the batches of the deletes
+	// of the garbage collector; zero means the default.
+	GCDeleteQPS       float32
+	GCDeleteBurst     int
+	GCDeleteBatchSize int
+
+	// AutoscalerPrometheusAddress is the address of the Prometheus queried for the metrics of the
+	// autoscaling policies of the tasks, AutoscalerMetricsConfig is the path of the queries of the
+	// metrics and AutoscalerSyncPeriod is how often they are queried.
+	AutoscalerPrometheusAddress string
+	AutoscalerMetricsConfig     string
+	AutoscalerSyncPeriod        time.Duration
+
+	// Namespaces are the namespaces whose resources the controllers manage; the informers are restricted to
+	// them when a single namespace is included, the events of the other namespaces are filtered otherwise.
+	Namespaces NamespaceScope
+
+	// ReloadableConfig holds the settings reloaded from the config file of the controller manager, e.g.
+	// the TTLs of the garbage collector; the controllers read it each time they use them.
+	ReloadableConfig *ReloadableConfigStore
+
 	// Config holds the common attributes that can be passed to a Kubernetes client
 	// and controllers registered by the users can use it.
 	Config *rest.Config
+
+	// Workers is the number of workers of the controller being initialized, from its ControllerConfig;
+	// zero means the flags or the default of the controller.
+	Workers uint32
+	// ResyncPeriod is how often the event handlers of the controller being initialized are sent all the objects
+	// of their informers again, from its ControllerConfig; zero disables the resync.
+	ResyncPeriod time.Duration
+}
+
+// ControllerConfig is the tuning of a controller, set by name in the controller config file.
+type ControllerConfig struct {
+	// Workers is the number of workers of the controller; zero means the flags or the default of the controller.
+	Workers uint32 `json:"workers,omitempty"`
+	// ResyncPeriod is how often the event handlers of the controller are sent all the objects of their informers
+	// again, so that the changes they missed are handled; zero disables the resync.
+	ResyncPeriod metav1.Duration `json:"resyncPeriod,omitempty"`
+	// QPS and Burst limit the requests of the clients of the controller, apart from the other controllers;
+	// zero means the --kube-api-qps and --kube-api-burst flags.
+	QPS   float32 `json:"qps,omitempty"`
+	Burst int     `json:"burst,omitempty"`
+}
+
+// GetWorkers returns the number of workers of the controller, the Workers of the option if set, or else the
+// workers given.
+func (opt *ControllerOption) GetWorkers(workers uint32) uint32 {
+	if opt.Workers > 0 {
+		return opt.Workers
+	}
+	return workers
 }
 
-// Controller is the interface of all controllers.
+// Controller is the interface of all controllers, which register themselves with RegisterController.
 type Controller interface {
+	// Name is the name of the controller, used by the controllers option and as the user agent of its clients.
 	Name() string
+	// Initialize sets the controller up with the clients and informers of the option; an error fails the
+	// controller manager.
 	Initialize(opt *ControllerOption) error
-	// Run run the controller
+	// Run runs the controller until stopCh is closed.
 	Run(stopCh <-chan struct{})
 }
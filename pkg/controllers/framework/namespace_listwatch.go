@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/pager"
+)
+
+// namespaceListWatch is the list and watch of a resource in a namespace.
+type namespaceListWatch struct {
+	namespace string
+	cache.ListerWatcher
+}
+
+// multiNamespaceListWatch lists and watches a resource in several namespaces as a single one. The resource versions
+// of the namespaces are tracked separately, so the watches resume each namespace from its last list or event, rather
+// than from the resource version of the reflector, which may be ahead of the events of the other namespaces.
+type multiNamespaceListWatch struct {
+	listWatches []namespaceListWatch
+
+	mutex    sync.Mutex
+	versions map[string]string
+}
+
+func newMultiNamespaceListWatch(listWatches []namespaceListWatch) *multiNamespaceListWatch {
+	return &multiNamespaceListWatch{
+		listWatches: listWatches,
+		versions:    map[string]string{},
+	}
+}
+
+// List lists the resources of all the namespaces, each of them in pages. The list has the resource version of the
+// namespace listed last, which is not older than the ones of the other namespaces.
+func (lw *multiNamespaceListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	// the continue tokens of the namespaces can not be merged, the namespaces are listed whole
+	options.Limit = 0
+	options.Continue = ""
+
+	var result runtime.Object
+	var items []runtime.Object
+	versions := make(map[string]string, len(lw.listWatches))
+	for _, nlw := range lw.listWatches {
+		listPager := pager.New(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return nlw.List(opts)
+		})
+		list, _, err := listPager.List(context.TODO(), options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespace %s: %v", nlw.namespace, err)
+		}
+		listMeta, err := meta.ListAccessor(list)
+		if err != nil {
+			return nil, err
+		}
+		nsItems, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		versions[nlw.namespace] = listMeta.GetResourceVersion()
+		items = append(items, nsItems...)
+		result = list
+	}
+	if result == nil {
+		return nil, fmt.Errorf("no namespace to list")
+	}
+	if err := meta.SetList(result, items); err != nil {
+		return nil, err
+	}
+
+	lw.mutex.Lock()
+	lw.versions = versions
+	lw.mutex.Unlock()
+	return result, nil
+}
+
+// Watch watches the resources of all the namespaces, each of them from its last resource version. The watch stops
+// when the one of any namespace does, to be restarted by the reflector.
+func (lw *multiNamespaceListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	w := &multiNamespaceWatch{
+		result: make(chan watch.Event),
+		stopCh: make(chan struct{}),
+	}
+	watchers := make([]watch.Interface, 0, len(lw.listWatches))
+	for _, nlw := range lw.listWatches {
+		opts := options
+		if version := lw.version(nlw.namespace); version != "" {
+			opts.ResourceVersion = version
+		}
+		watcher, err := nlw.Watch(opts)
+		if err != nil {
+			for _, watcher := range watchers {
+				watcher.Stop()
+			}
+			return nil, fmt.Errorf("failed to watch namespace %s: %v", nlw.namespace, err)
+		}
+		watchers = append(watchers, watcher)
+	}
+
+	var wg sync.WaitGroup
+	for i, watcher := range watchers {
+		wg.Add(1)
+		go func(namespace string, watcher watch.Interface) {
+			defer wg.Done()
+			defer watcher.Stop()
+			defer w.Stop()
+			lw.forward(namespace, watcher, w)
+		}(lw.listWatches[i].namespace, watcher)
+	}
+	go func() {
+		wg.Wait()
+		close(w.result)
+	}()
+	return w, nil
+}
+
+// forward forwards the events of the watch of a namespace until either watch stops, and tracks the resource version
+// of the events delivered.
+func (lw *multiNamespaceListWatch) forward(namespace string, watcher watch.Interface, w *multiNamespaceWatch) {
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			select {
+			case <-w.stopCh:
+				return
+			case w.result <- event:
+			}
+			if event.Type == watch.Error {
+				continue
+			}
+			if accessor, err := meta.Accessor(event.Object); err == nil {
+				lw.mutex.Lock()
+				lw.versions[namespace] = accessor.GetResourceVersion()
+				lw.mutex.Unlock()
+			}
+		}
+	}
+}
+
+func (lw *multiNamespaceListWatch) version(namespace string) string {
+	lw.mutex.Lock()
+	defer lw.mutex.Unlock()
+	return lw.versions[namespace]
+}
+
+// multiNamespaceWatch merges the watches of several namespaces.
+type multiNamespaceWatch struct {
+	result   chan watch.Event
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *multiNamespaceWatch) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+	})
+}
+
+func (w *multiNamespaceWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
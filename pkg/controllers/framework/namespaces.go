@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
+)
+
+// NamespaceScope is the set of the namespaces whose resources the controllers manage: the namespaces included,
+// or else all the namespaces but the excluded ones. The zero value manages all the namespaces.
+type NamespaceScope struct {
+	Included []string
+	Excluded []string
+}
+
+// Manages returns whether the resources of the namespace are managed.
+func (s NamespaceScope) Manages(namespace string) bool {
+	if len(s.Included) > 0 {
+		return slices.Contains(s.Included, namespace)
+	}
+	return !slices.Contains(s.Excluded, namespace)
+}
+
+// InformerNamespace returns the namespace the informer factories are restricted to: the namespace included if it is
+// the only one, or else all the namespaces. The informers of the namespaced resources are restricted to several
+// namespaces by RegisterInformers.
+func (s NamespaceScope) InformerNamespace() string {
+	if len(s.Included) == 1 {
+		return s.Included[0]
+	}
+	return metav1.NamespaceAll
+}
+
+// Namespaces returns the namespaces the lists of the namespaced resources are done in, whose resources are filtered
+// with Manages.
+func (s NamespaceScope) Namespaces() []string {
+	if len(s.Included) > 1 {
+		return s.Included
+	}
+	return []string{s.InformerNamespace()}
+}
+
+// NeedsListWatch returns whether the namespaced resources can not be restricted to the namespace of the informer
+// factories, but need the list and watch of NewListWatch.
+func (s NamespaceScope) NeedsListWatch() bool {
+	return len(s.Included) > 1 || (len(s.Included) == 0 && len(s.Excluded) > 0)
+}
+
+// NewListWatch returns the list and watch of a namespaced resource restricted to the managed namespaces: the namespaces
+// included are listed and watched together, and the namespaces excluded are filtered out by the API server.
+func (s NamespaceScope) NewListWatch(c cache.Getter, resource string, optionsModifier func(*metav1.ListOptions)) cache.ListerWatcher {
+	modifier := func(options *metav1.ListOptions) {
+		if optionsModifier != nil {
+			optionsModifier(options)
+		}
+		s.excludeNamespaces(options)
+	}
+	if len(s.Included) <= 1 {
+		return cache.NewFilteredListWatchFromClient(c, resource, s.InformerNamespace(), modifier)
+	}
+	listWatches := make([]namespaceListWatch, 0, len(s.Included))
+	for _, namespace := range s.Included {
+		listWatches = append(listWatches, namespaceListWatch{
+			namespace:     namespace,
+			ListerWatcher: cache.NewFilteredListWatchFromClient(c, resource, namespace, modifier),
+		})
+	}
+	return newMultiNamespaceListWatch(listWatches)
+}
+
+// excludeNamespaces adds the namespaces excluded to the field selector of the options.
+func (s NamespaceScope) excludeNamespaces(options *metav1.ListOptions) {
+	if len(s.Included) > 0 || len(s.Excluded) == 0 {
+		return
+	}
+	terms := make([]string, 0, len(s.Excluded)+1)
+	if options.FieldSelector != "" {
+		terms = append(terms, options.FieldSelector)
+	}
+	for _, namespace := range s.Excluded {
+		terms = append(terms, fields.OneTermNotEqualSelector("metadata.namespace", namespace).String())
+	}
+	options.FieldSelector = strings.Join(terms, ",")
+}
+
+// NewInformer returns the informer of a namespaced resource restricted to the managed namespaces, see NewListWatch.
+func (s NamespaceScope) NewInformer(c cache.Getter, resource string, obj runtime.Object, resync time.Duration,
+	optionsModifier func(*metav1.ListOptions)) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(s.NewListWatch(c, resource, optionsModifier), obj, resync,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+}
+
+// RegisterInformers registers in the factories the informers of the namespaced resources of the controllers restricted
+// to the managed namespaces, when the factories can not restrict them. It must be called before the informers are
+// got from the factories.
+func (s NamespaceScope) RegisterInformers(factory informers.SharedInformerFactory, vcFactory vcinformer.SharedInformerFactory) {
+	if !s.NeedsListWatch() {
+		return
+	}
+
+	kubeResources := []struct {
+		obj      runtime.Object
+		resource string
+		client   func(kubernetes.Interface) cache.Getter
+	}{
+		{&v1.Pod{}, "pods", func(c kubernetes.Interface) cache.Getter { return c.CoreV1().RESTClient() }},
+		{&v1.PersistentVolumeClaim{}, "persistentvolumeclaims", func(c kubernetes.Interface) cache.Getter { return c.CoreV1().RESTClient() }},
+		{&v1.Service{}, "services", func(c kubernetes.Interface) cache.Getter { return c.CoreV1().RESTClient() }},
+		{&appsv1.ReplicaSet{}, "replicasets", func(c kubernetes.Interface) cache.Getter { return c.AppsV1().RESTClient() }},
+		{&appsv1.StatefulSet{}, "statefulsets", func(c kubernetes.Interface) cache.Getter { return c.AppsV1().RESTClient() }},
+		{&appsv1.DaemonSet{}, "daemonsets", func(c kubernetes.Interface) cache.Getter { return c.AppsV1().RESTClient() }},
+		{&batchv1.Job{}, "jobs", func(c kubernetes.Interface) cache.Getter { return c.BatchV1().RESTClient() }},
+		{&policyv1.PodDisruptionBudget{}, "poddisruptionbudgets", func(c kubernetes.Interface) cache.Getter { return c.PolicyV1().RESTClient() }},
+	}
+	for _, r := range kubeResources {
+		r := r
+		factory.InformerFor(r.obj, func(c kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+			return s.NewInformer(r.client(c), r.resource, r.obj, resync, nil)
+		})
+	}
+
+	vcResources := []struct {
+		obj      runtime.Object
+		resource string
+		client   func(vcclientset.Interface) cache.Getter
+	}{
+		{&batchv1alpha1.Job{}, "jobs", func(c vcclientset.Interface) cache.Getter { return c.BatchV1alpha1().RESTClient() }},
+		{&busv1alpha1.Command{}, "commands", func(c vcclientset.Interface) cache.Getter { return c.BusV1alpha1().RESTClient() }},
+		{&flowv1alpha1.JobFlow{}, "jobflows", func(c vcclientset.Interface) cache.Getter { return c.FlowV1alpha1().RESTClient() }},
+		{&flowv1alpha1.JobTemplate{}, "jobtemplates", func(c vcclientset.Interface) cache.Getter { return c.FlowV1alpha1().RESTClient() }},
+		{&schedulingv1beta1.PodGroup{}, "podgroups", func(c vcclientset.Interface) cache.Getter { return c.SchedulingV1beta1().RESTClient() }},
+	}
+	for _, r := range vcResources {
+		r := r
+		vcFactory.InformerFor(r.obj, func(c vcclientset.Interface, resync time.Duration) cache.SharedIndexInformer {
+			return s.NewInformer(r.client(c), r.resource, r.obj, resync, nil)
+		})
+	}
+}
+
+// FilterHandler returns the handler of the events of the resources of the managed namespaces only.
+func (s NamespaceScope) FilterHandler(handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+	if len(s.Included) == 0 && len(s.Excluded) == 0 {
+		return handler
+	}
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return false
+			}
+			return s.Manages(accessor.GetNamespace())
+		},
+		Handler: handler,
+	}
+}
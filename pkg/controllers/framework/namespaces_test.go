@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNamespaceScope(t *testing.T) {
+	testCases := []struct {
+		name             string
+		scope            NamespaceScope
+		expectManaged    map[string]bool
+		expectInformerNs string
+	}{
+		{
+			name:             "all namespaces",
+			scope:            NamespaceScope{},
+			expectManaged:    map[string]bool{"team-a": true, "team-b": true, "kube-system": true},
+			expectInformerNs: metav1.NamespaceAll,
+		},
+		{
+			name:             "single namespace",
+			scope:            NamespaceScope{Included: []string{"team-a"}},
+			expectManaged:    map[string]bool{"team-a": true, "team-b": false, "kube-system": false},
+			expectInformerNs: "team-a",
+		},
+		{
+			name:             "several namespaces",
+			scope:            NamespaceScope{Included: []string{"team-a", "team-b"}},
+			expectManaged:    map[string]bool{"team-a": true, "team-b": true, "kube-system": false},
+			expectInformerNs: metav1.NamespaceAll,
+		},
+		{
+			name:             "excluded namespaces",
+			scope:            NamespaceScope{Excluded: []string{"kube-system"}},
+			expectManaged:    map[string]bool{"team-a": true, "team-b": true, "kube-system": false},
+			expectInformerNs: metav1.NamespaceAll,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if ns := tc.scope.InformerNamespace(); ns != tc.expectInformerNs {
+				t.Errorf("expected informer namespace %q, got %q", tc.expectInformerNs, ns)
+			}
+
+			handled := map[string]bool{}
+			handler := tc.scope.FilterHandler(cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					handled[obj.(*v1.Pod).Namespace] = true
+				},
+				DeleteFunc: func(obj interface{}) {
+					pod := obj.(cache.DeletedFinalStateUnknown).Obj.(*v1.Pod)
+					handled[pod.Namespace+"/deleted"] = true
+				},
+			})
+			for ns, managed := range tc.expectManaged {
+				if tc.scope.Manages(ns) != managed {
+					t.Errorf("expected namespace %s managed: %v", ns, managed)
+				}
+				pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: ns}}
+				handler.OnAdd(pod, false)
+				handler.OnDelete(cache.DeletedFinalStateUnknown{Key: ns + "/pod1", Obj: pod})
+				if handled[ns] != managed || handled[ns+"/deleted"] != managed {
+					t.Errorf("expected the events of namespace %s handled: %v", ns, managed)
+				}
+			}
+		})
+	}
+}
+
+func TestNamespaceScopeExcludeNamespaces(t *testing.T) {
+	scope := NamespaceScope{Excluded: []string{"team-a", "team-b"}}
+	options := metav1.ListOptions{FieldSelector: "type=Warning"}
+	scope.excludeNamespaces(&options)
+	expected := "type=Warning,metadata.namespace!=team-a,metadata.namespace!=team-b"
+	if options.FieldSelector != expected {
+		t.Errorf("expected field selector %q, got %q", expected, options.FieldSelector)
+	}
+	if !scope.NeedsListWatch() || (NamespaceScope{Included: []string{"team-a"}}).NeedsListWatch() {
+		t.Errorf("expected the list and watch needed for excluded namespaces only")
+	}
+}
+
+func TestMultiNamespaceListWatch(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "team-a"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "team-b"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod3", Namespace: "kube-system"}},
+	)
+	var listWatches []namespaceListWatch
+	for _, ns := range []string{"team-a", "team-b"} {
+		namespace := ns
+		listWatches = append(listWatches, namespaceListWatch{
+			namespace: namespace,
+			ListerWatcher: &cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return client.CoreV1().Pods(namespace).List(context.TODO(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return client.CoreV1().Pods(namespace).Watch(context.TODO(), options)
+				},
+			},
+		})
+	}
+	lw := newMultiNamespaceListWatch(listWatches)
+
+	list, err := lw.List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	pods := list.(*v1.PodList).Items
+	if len(pods) != 2 || pods[0].Name != "pod1" || pods[1].Name != "pod2" {
+		t.Errorf("expected pod1 and pod2 listed, got %v", pods)
+	}
+
+	w, err := lw.Watch(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	defer w.Stop()
+	for _, pod := range []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod4", Namespace: "kube-system"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod5", Namespace: "team-b", ResourceVersion: "5"}},
+	} {
+		if _, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create pod %s: %v", pod.Name, err)
+		}
+	}
+	select {
+	case event := <-w.ResultChan():
+		if pod := event.Object.(*v1.Pod); event.Type != watch.Added || pod.Name != "pod5" {
+			t.Errorf("expected pod5 added, got %s of %s", event.Type, pod.Name)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatalf("expected the event of pod5")
+	}
+	if err := wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, wait.ForeverTestTimeout, true,
+		func(context.Context) (bool, error) {
+			return lw.version("team-b") == "5", nil
+		}); err != nil {
+		t.Errorf("expected the resource version 5 of team-b, got %q", lw.version("team-b"))
+	}
+
+	w.Stop()
+	if _, ok := <-w.ResultChan(); ok {
+		t.Errorf("expected the watch closed when stopped")
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ReloadableConfig is the part of the configuration of the controllers which is reloaded from the
+// config file of the controller manager while the controllers run.
+type ReloadableConfig struct {
+	// GCPodGroupTTL and GCCommandTTL are how long the garbage collector keeps the finished podgroups
+	// and the commands not handled by any controller; zero means the default and a negative TTL
+	// disables the cleanup.
+	GCPodGroupTTL time.Duration
+	GCCommandTTL  time.Duration
+	// GCDryRun makes the garbage collector only log the resources it would delete.
+	GCDryRun bool
+}
+
+// ReloadableConfigStore holds the latest ReloadableConfig, which the controllers read each time
+// they use it; it is safe for concurrent use.
+type ReloadableConfigStore struct {
+	config atomic.Pointer[ReloadableConfig]
+}
+
+// NewReloadableConfigStore creates a ReloadableConfigStore holding the config.
+func NewReloadableConfigStore(config ReloadableConfig) *ReloadableConfigStore {
+	s := &ReloadableConfigStore{}
+	s.Set(config)
+	return s
+}
+
+// Get returns the latest config.
+func (s *ReloadableConfigStore) Get() ReloadableConfig {
+	return *s.config.Load()
+}
+
+// Set replaces the config, which the controllers use from their next read on.
+func (s *ReloadableConfigStore) Set(config ReloadableConfig) {
+	s.config.Store(&config)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// deleteRequest is a resource the garbage collector deletes.
+type deleteRequest struct {
+	kind      string
+	namespace string
+	name      string
+	// delete deletes the resource, checking first it is still to be deleted if needed.
+	delete func() error
+}
+
+func (r *deleteRequest) key() string {
+	return r.kind + "/" + r.namespace + "/" + r.name
+}
+
+// deleteQueue holds the resources to delete in a FIFO per namespace, and hands them out in batches
+// taking the namespaces in turn, so that a namespace with a burst of expired resources does not
+// delay the cleanup of the others. A resource already waiting is not added twice.
+type deleteQueue struct {
+	lock sync.Mutex
+	cond *sync.Cond
+
+	// namespaces are the namespaces with pending requests, in the order they are served.
+	namespaces []string
+	pending    map[string][]*deleteRequest
+	queued     map[string]bool
+	shutdown   bool
+}
+
+func newDeleteQueue() *deleteQueue {
+	q := &deleteQueue{
+		pending: map[string][]*deleteRequest{},
+		queued:  map[string]bool{},
+	}
+	q.cond = sync.NewCond(&q.lock)
+	return q
+}
+
+// Add queues the request, unless the resource is already waiting.
+func (q *deleteQueue) Add(req *deleteRequest) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.shutdown || q.queued[req.key()] {
+		return
+	}
+	q.queued[req.key()] = true
+	if len(q.pending[req.namespace]) == 0 {
+		q.namespaces = append(q.namespaces, req.namespace)
+	}
+	q.pending[req.namespace] = append(q.pending[req.namespace], req)
+	pendingDeletes.Inc()
+	q.cond.Signal()
+}
+
+// Len returns the number of requests waiting.
+func (q *deleteQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.queued)
+}
+
+// Get blocks until requests are waiting, and returns up to max of them, one per namespace in turn.
+// It returns false once the queue is shut down.
+func (q *deleteQueue) Get(max int) ([]*deleteRequest, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for len(q.namespaces) == 0 && !q.shutdown {
+		q.cond.Wait()
+	}
+	if q.shutdown {
+		return nil, false
+	}
+
+	var batch []*deleteRequest
+	for len(batch) < max && len(q.namespaces) != 0 {
+		namespace := q.namespaces[0]
+		q.namespaces = q.namespaces[1:]
+
+		req := q.pending[namespace][0]
+		q.pending[namespace] = q.pending[namespace][1:]
+		if len(q.pending[namespace]) == 0 {
+			delete(q.pending, namespace)
+		} else {
+			// the namespace waits for its next turn behind the others
+			q.namespaces = append(q.namespaces, namespace)
+		}
+		delete(q.queued, req.key())
+		batch = append(batch, req)
+	}
+	pendingDeletes.Sub(float64(len(batch)))
+	return batch, true
+}
+
+// ShutDown drops the waiting requests and wakes up the callers of Get.
+func (q *deleteQueue) ShutDown() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.shutdown = true
+	pendingDeletes.Sub(float64(len(q.queued)))
+	q.namespaces = nil
+	q.pending = map[string][]*deleteRequest{}
+	q.queued = map[string]bool{}
+	q.cond.Broadcast()
+}
+
+// deleter deletes the queued resources batch by batch, with the workers of the garbage collector in
+// parallel, within the delete rate limit.
+func (gc *gccontroller) deleter(stopCh <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+		gc.deletes.ShutDown()
+	}()
+
+	for gc.processDeleteBatch(ctx) {
+	}
+}
+
+// processDeleteBatch deletes the next batch of resources, it returns false once the queue is shut down.
+func (gc *gccontroller) processDeleteBatch(ctx context.Context) bool {
+	batch, ok := gc.deletes.Get(gc.deleteBatchSize)
+	if !ok {
+		return false
+	}
+
+	workers := int(gc.workers)
+	if workers < 1 {
+		workers = 1
+	}
+	workqueue.ParallelizeUntil(ctx, workers, len(batch), func(i int) {
+		req := batch[i]
+		if err := gc.deleteLimiter.Wait(ctx); err != nil {
+			return
+		}
+		if err := req.delete(); err != nil {
+			klog.Errorf("Failed to delete %s %s/%s: %v", req.kind, req.namespace, req.name, err)
+		}
+	})
+	return true
+}
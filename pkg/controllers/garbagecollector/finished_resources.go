@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+const (
+	// finishedResourceCleanupPeriod is by default how often the finished podgroups and the stale commands are checked.
+	finishedResourceCleanupPeriod = time.Minute
+	// defaultPodGroupTTL disables by default the cleanup of the finished podgroups, which must be opted in.
+	defaultPodGroupTTL = -time.Second
+	// defaultCommandTTL is by default how long a command not handled by any controller is kept.
+	defaultCommandTTL = time.Hour
+)
+
+// cleanupFinishedResources queues to delete the podgroups finished for longer than their TTL and the commands
+// never handled within their TTL, which would otherwise pile up in the listings and in the cache of
+// the scheduler on busy clusters.
+func (gc *gccontroller) cleanupFinishedResources() {
+	now := time.Now()
+	settings := gc.settings()
+
+	if settings.GCPodGroupTTL >= 0 {
+		podGroups, err := gc.pgLister.List(labels.Everything())
+		if err != nil {
+			klog.Errorf("Failed to list PodGroups: %v", err)
+		}
+		for _, pg := range podGroups {
+			if !gc.namespaces.Manages(pg.Namespace) {
+				continue
+			}
+			if err := gc.cleanupPodGroup(pg, now); err != nil {
+				klog.Errorf("Failed to clean up PodGroup %s/%s: %v", pg.Namespace, pg.Name, err)
+			}
+		}
+	}
+
+	if settings.GCCommandTTL < 0 {
+		return
+	}
+	commands, err := gc.cmdLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Commands: %v", err)
+	}
+	for _, cmd := range commands {
+		if cmd.DeletionTimestamp != nil || !gc.namespaces.Manages(cmd.Namespace) || now.Before(cmd.CreationTimestamp.Add(settings.GCCommandTTL)) {
+			continue
+		}
+		if settings.GCDryRun {
+			klog.Infof("Dry run: would delete Command %s/%s not handled since %v", cmd.Namespace, cmd.Name, cmd.CreationTimestamp)
+			continue
+		}
+		uid := cmd.UID
+		namespace, name, created := cmd.Namespace, cmd.Name, cmd.CreationTimestamp
+		gc.deletes.Add(&deleteRequest{
+			kind:      "Command",
+			namespace: namespace,
+			name:      name,
+			delete: func() error {
+				err := gc.vcClient.BusV1alpha1().Commands(namespace).Delete(context.TODO(), name,
+					metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}})
+				if err != nil && !apierrors.IsNotFound(err) {
+					return err
+				}
+				klog.V(3).Infof("Deleted Command %s/%s not handled since %v", namespace, name, created)
+				expiredResources.WithLabelValues("Command").Inc()
+				return nil
+			},
+		})
+	}
+}
+
+// cleanupPodGroup queues to delete the podgroup once it is finished for longer than the TTL. The time the
+// podgroup is first found finished is recorded on it, so that the TTL survives controller restarts;
+// it is recorded in dry-run mode too, so that the TTL holds once the dry-run mode is turned off.
+// The podgroups with a controller are left to it: those of volcano jobs are deleted with the job and its TTL,
+// and the others, e.g. of a batch Job or of a bare pod, are deleted with their owner by the kube garbage collector.
+func (gc *gccontroller) cleanupPodGroup(pg *scheduling.PodGroup, now time.Time) error {
+	if pg.DeletionTimestamp != nil || metav1.GetControllerOf(pg) != nil {
+		return nil
+	}
+
+	value, stamped := pg.Annotations[apis.PodGroupFinishedTimeKey]
+	if !isPodGroupFinished(pg) {
+		if !stamped {
+			return nil
+		}
+		// the podgroup is running again, e.g. its pods are recreated
+		newPG := pg.DeepCopy()
+		delete(newPG.Annotations, apis.PodGroupFinishedTimeKey)
+		_, err := gc.vcClient.SchedulingV1beta1().PodGroups(pg.Namespace).Update(context.TODO(), newPG, metav1.UpdateOptions{})
+		return err
+	}
+
+	finishedAt, err := time.Parse(time.RFC3339, value)
+	if !stamped || err != nil {
+		newPG := pg.DeepCopy()
+		if newPG.Annotations == nil {
+			newPG.Annotations = map[string]string{}
+		}
+		newPG.Annotations[apis.PodGroupFinishedTimeKey] = now.UTC().Format(time.RFC3339)
+		_, err := gc.vcClient.SchedulingV1beta1().PodGroups(pg.Namespace).Update(context.TODO(), newPG, metav1.UpdateOptions{})
+		return err
+	}
+	settings := gc.settings()
+	if now.Before(finishedAt.Add(settings.GCPodGroupTTL)) {
+		return nil
+	}
+
+	if settings.GCDryRun {
+		klog.Infof("Dry run: would delete PodGroup %s/%s finished at %v", pg.Namespace, pg.Name, finishedAt)
+		return nil
+	}
+	uid := pg.UID
+	namespace, name := pg.Namespace, pg.Name
+	gc.deletes.Add(&deleteRequest{
+		kind:      "PodGroup",
+		namespace: namespace,
+		name:      name,
+		delete: func() error {
+			err := gc.vcClient.SchedulingV1beta1().PodGroups(namespace).Delete(context.TODO(), name,
+				metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			klog.V(3).Infof("Deleted PodGroup %s/%s finished at %v", namespace, name, finishedAt)
+			expiredResources.WithLabelValues("PodGroup").Inc()
+			return nil
+		},
+	})
+	return nil
+}
+
+// isPodGroupFinished returns whether the podgroup is completed, or failed: pending with all its
+// minimal members terminated and some of them failed.
+func isPodGroupFinished(pg *scheduling.PodGroup) bool {
+	if pg.Status.Phase == scheduling.PodGroupCompleted {
+		return true
+	}
+	return pg.Status.Phase == scheduling.PodGroupPending && pg.Status.Running == 0 && pg.Status.Failed > 0 &&
+		pg.Status.Succeeded+pg.Status.Failed >= pg.Spec.MinMember
+}
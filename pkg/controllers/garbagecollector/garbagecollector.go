@@ -19,13 +19,16 @@ package garbagecollector
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
@@ -34,6 +37,8 @@ import (
 	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
 	batchinformers "volcano.sh/apis/pkg/client/informers/externalversions/batch/v1alpha1"
 	batchlisters "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
+	buslisters "volcano.sh/apis/pkg/client/listers/bus/v1alpha1"
+	schedulinglisters "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/controllers/framework"
 )
 
@@ -50,8 +55,14 @@ func init() {
 // worker will send requests to the API server to delete the Jobs accordingly.
 // This is implemented outside of Job controller for separation of concerns, and
 // because it will be extended to handle other finishable resource types.
+// It also sweeps periodically the resources of the job plugins left behind by
+// Jobs which no longer exist, the finished PodGroups and the stale Commands.
+// The resources to delete are queued per namespace and deleted in rate limited
+// batches, so that a burst of expired resources neither starves the API server
+// nor the other controllers sharing the client.
 type gccontroller struct {
-	vcClient vcclientset.Interface
+	kubeClient kubernetes.Interface
+	vcClient   vcclientset.Interface
 
 	jobInformer batchinformers.JobInformer
 
@@ -61,6 +72,28 @@ type gccontroller struct {
 	jobLister batchlisters.JobLister
 	jobSynced func() bool
 
+	// A store of podgroups
+	pgLister schedulinglisters.PodGroupLister
+
+	// A store of commands
+	cmdLister buslisters.CommandLister
+
+	// how often the finished resources and the orphaned plugin resources are checked
+	scanInterval  time.Duration
+	sweepInterval time.Duration
+
+	// the namespaces whose resources are collected
+	namespaces framework.NamespaceScope
+
+	// how long the finished podgroups and the commands not handled are kept, and the dry-run mode,
+	// reloaded from the config file of the controller manager
+	config *framework.ReloadableConfigStore
+
+	// resources to delete, and the limit of the rate and of the batches they are deleted in
+	deletes         *deleteQueue
+	deleteLimiter   flowcontrol.RateLimiter
+	deleteBatchSize int
+
 	// queues that need to be updated.
 	queue workqueue.RateLimitingInterface
 
@@ -73,6 +106,7 @@ func (gc *gccontroller) Name() string {
 
 // Initialize creates an instance of gccontroller.
 func (gc *gccontroller) Initialize(opt *framework.ControllerOption) error {
+	gc.kubeClient = opt.KubeClient
 	gc.vcClient = opt.VolcanoClient
 
 	factory := opt.VCSharedInformerFactory
@@ -82,13 +116,40 @@ func (gc *gccontroller) Initialize(opt *framework.ControllerOption) error {
 	gc.jobInformer = jobInformer
 	gc.jobLister = jobInformer.Lister()
 	gc.jobSynced = jobInformer.Informer().HasSynced
-	gc.queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
-	gc.workers = opt.WorkerThreadsForGC
+	gc.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "gc")
+	gc.workers = opt.GetWorkers(opt.WorkerThreadsForGC)
+
+	gc.pgLister = factory.Scheduling().V1beta1().PodGroups().Lister()
+	gc.cmdLister = factory.Bus().V1alpha1().Commands().Lister()
+	gc.scanInterval = durationOrDefault(opt.GCScanInterval, finishedResourceCleanupPeriod)
+	gc.sweepInterval = durationOrDefault(opt.GCPluginResourceSweepInterval, pluginResourceSweepPeriod)
+	gc.namespaces = opt.Namespaces
+	gc.config = opt.ReloadableConfig
+	if gc.config == nil {
+		gc.config = framework.NewReloadableConfigStore(framework.ReloadableConfig{})
+	}
 
-	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	deleteQPS, deleteBurst := opt.GCDeleteQPS, opt.GCDeleteBurst
+	if deleteQPS <= 0 {
+		deleteQPS = framework.DefaultGCDeleteQPS
+	}
+	if deleteBurst <= 0 {
+		deleteBurst = framework.DefaultGCDeleteBurst
+	}
+	gc.deletes = newDeleteQueue()
+	gc.deleteLimiter = flowcontrol.NewTokenBucketRateLimiter(deleteQPS, deleteBurst)
+	gc.deleteBatchSize = opt.GCDeleteBatchSize
+	if gc.deleteBatchSize <= 0 {
+		gc.deleteBatchSize = framework.DefaultGCDeleteBatchSize
+	}
+	if gc.settings().GCDryRun {
+		klog.Infof("Garbage collector runs in dry-run mode, resources are not deleted")
+	}
+
+	jobInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    gc.addJob,
 		UpdateFunc: gc.updateJob,
-	})
+	}), opt.ResyncPeriod)
 
 	return nil
 }
@@ -108,11 +169,34 @@ func (gc *gccontroller) Run(stopCh <-chan struct{}) {
 		}
 	}
 
+	var workers sync.WaitGroup
 	for i := 0; i < int(gc.workers); i++ {
-		go wait.Until(gc.worker, time.Second, stopCh)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			wait.Until(gc.worker, time.Second, stopCh)
+		}()
 	}
+	workers.Add(3)
+	go func() {
+		defer workers.Done()
+		gc.deleter(stopCh)
+	}()
+	go func() {
+		defer workers.Done()
+		wait.Until(gc.sweepPluginResources, gc.sweepInterval, stopCh)
+	}()
+	go func() {
+		defer workers.Done()
+		wait.Until(gc.cleanupFinishedResources, gc.scanInterval, stopCh)
+	}()
 
 	<-stopCh
+
+	// the workers handle the jobs left in the queue and the deleter completes its batch in flight before the
+	// collector stops; the resources left to delete are found again by the next scan
+	gc.queue.ShutDown()
+	workers.Wait()
 }
 
 func (gc *gccontroller) addJob(obj interface{}) {
@@ -182,8 +266,8 @@ func (gc *gccontroller) handleErr(err error, key interface{}) {
 	gc.queue.AddRateLimited(key)
 }
 
-// processJob will check the Job's state and TTL and delete the Job when it
-// finishes and its TTL after finished has expired. If the Job hasn't finished or
+// processJob will check the Job's state and TTL and queue the Job to delete when
+// it finishes and its TTL after finished has expired. If the Job hasn't finished or
 // its TTL hasn't expired, it will be added to the queue after the TTL is expected
 // to expire.
 // This function is not meant to be invoked concurrently with the same key.
@@ -209,6 +293,23 @@ func (gc *gccontroller) processJob(key string) error {
 		return nil
 	}
 
+	gc.deletes.Add(&deleteRequest{
+		kind:      "Job",
+		namespace: namespace,
+		name:      name,
+		delete: func() error {
+			err := gc.deleteExpiredJob(namespace, name)
+			if err != nil {
+				gc.queue.AddRateLimited(key)
+			}
+			return err
+		},
+	})
+	return nil
+}
+
+// deleteExpiredJob deletes the Job if its TTL truly expires.
+func (gc *gccontroller) deleteExpiredJob(namespace, name string) error {
 	// The Job's TTL is assumed to have expired, but the Job TTL might be stale.
 	// Before deleting the Job, do a final sanity check.
 	// If TTL is modified before we do this check, we cannot be sure if the TTL truly expires.
@@ -226,6 +327,10 @@ func (gc *gccontroller) processJob(key string) error {
 	} else if !expired {
 		return nil
 	}
+	if gc.settings().GCDryRun {
+		klog.Infof("Dry run: would delete Job %s/%s, its TTL after finished has expired", namespace, name)
+		return nil
+	}
 	// Cascade deletes the Jobs if TTL truly expires.
 	policy := metav1.DeletePropagationForeground
 	options := metav1.DeleteOptions{
@@ -264,6 +369,23 @@ func (gc *gccontroller) processTTL(job *v1alpha1.Job) (expired bool, err error)
 	return false, nil
 }
 
+// durationOrDefault returns the duration, or the default one if it is not set.
+// settings returns the TTLs, with their defaults, and the dry-run mode of the garbage collector, which may be
+// reloaded between two calls.
+func (gc *gccontroller) settings() framework.ReloadableConfig {
+	config := gc.config.Get()
+	config.GCPodGroupTTL = durationOrDefault(config.GCPodGroupTTL, defaultPodGroupTTL)
+	config.GCCommandTTL = durationOrDefault(config.GCCommandTTL, defaultCommandTTL)
+	return config
+}
+
+func durationOrDefault(d, defaultDuration time.Duration) time.Duration {
+	if d == 0 {
+		return defaultDuration
+	}
+	return d
+}
+
 // needsCleanup checks whether a Job has finished and has a TTL set.
 func needsCleanup(j *v1alpha1.Job) bool {
 	return j.Spec.TTLSecondsAfterFinished != nil && isJobFinished(j)
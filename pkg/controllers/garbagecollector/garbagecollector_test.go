@@ -17,15 +17,22 @@ limitations under the License.
 package garbagecollector
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	volcanoclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
 	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/controllers/apis"
 	"volcano.sh/volcano/pkg/controllers/framework"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 func newFakeController() *gccontroller {
@@ -34,6 +41,7 @@ func newFakeController() *gccontroller {
 
 	controller := &gccontroller{}
 	opt := &framework.ControllerOption{
+		KubeClient:              kubeclient.NewSimpleClientset(),
 		VolcanoClient:           volcanoClientSet,
 		VCSharedInformerFactory: vcSharedInformers,
 	}
@@ -43,6 +51,13 @@ func newFakeController() *gccontroller {
 	return controller
 }
 
+// drainDeletes deletes all the queued resources.
+func drainDeletes(gc *gccontroller) {
+	for gc.deletes.Len() > 0 {
+		gc.processDeleteBatch(context.TODO())
+	}
+}
+
 func TestGarbageCollector_ProcessJob(t *testing.T) {
 
 }
@@ -395,3 +410,229 @@ func TestGarbageCollector_JobFinishTime(t *testing.T) {
 		}
 	}
 }
+
+func TestGarbageCollector_SweepPluginResources(t *testing.T) {
+	namespace := "test"
+	pluginLabels := func(jobName string) map[string]string {
+		return map[string]string{jobhelpers.PluginLabelKey: "ssh", v1alpha1.JobNameKey: jobName}
+	}
+	job := &v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace}}
+
+	gc := newFakeController()
+	if _, err := gc.vcClient.BatchV1alpha1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+	gc.jobInformer.Informer().GetIndexer().Add(job)
+
+	secrets := []*v1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: "job1-ssh", Namespace: namespace, Labels: pluginLabels("job1")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "job2-ssh", Namespace: namespace, Labels: pluginLabels("job2")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: namespace}},
+	}
+	for _, secret := range secrets {
+		if _, err := gc.kubeClient.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create secret: %v", err)
+		}
+	}
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "job2", Namespace: namespace, Labels: pluginLabels("job2")}}
+	if _, err := gc.kubeClient.CoreV1().Services(namespace).Create(context.TODO(), svc, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	gc.sweepPluginResources()
+	drainDeletes(gc)
+
+	expected := map[string]bool{"job1-ssh": true, "job2-ssh": false, "other": true}
+	for name, exists := range expected {
+		_, err := gc.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if exists != (err == nil) {
+			t.Errorf("expected secret %s to exist: %v, got error %v", name, exists, err)
+		}
+	}
+	if _, err := gc.kubeClient.CoreV1().Services(namespace).Get(context.TODO(), "job2", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected orphaned service job2 to be deleted")
+	}
+}
+
+func TestGarbageCollector_CleanupPodGroup(t *testing.T) {
+	namespace := "test"
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	isController := true
+
+	testcases := []struct {
+		Name           string
+		Status         scheduling.PodGroupStatus
+		FinishedTime   string
+		Owner          *metav1.OwnerReference
+		DryRun         bool
+		ExpectDeleted  bool
+		ExpectFinished string
+	}{
+		{
+			Name:           "completed podgroup is stamped",
+			Status:         scheduling.PodGroupStatus{Phase: scheduling.PodGroupCompleted, Succeeded: 1},
+			ExpectFinished: "2024-01-02T00:00:00Z",
+		},
+		{
+			Name:           "failed podgroup is stamped",
+			Status:         scheduling.PodGroupStatus{Phase: scheduling.PodGroupPending, Failed: 1},
+			ExpectFinished: "2024-01-02T00:00:00Z",
+		},
+		{
+			Name:           "podgroup finished within the TTL is kept",
+			Status:         scheduling.PodGroupStatus{Phase: scheduling.PodGroupCompleted, Succeeded: 1},
+			FinishedTime:   "2024-01-01T12:00:00Z",
+			ExpectFinished: "2024-01-01T12:00:00Z",
+		},
+		{
+			Name:          "podgroup finished past the TTL is deleted",
+			Status:        scheduling.PodGroupStatus{Phase: scheduling.PodGroupCompleted, Succeeded: 1},
+			FinishedTime:  "2024-01-01T00:00:00Z",
+			ExpectDeleted: true,
+		},
+		{
+			Name:           "podgroup finished past the TTL is kept in dry-run mode",
+			Status:         scheduling.PodGroupStatus{Phase: scheduling.PodGroupCompleted, Succeeded: 1},
+			FinishedTime:   "2024-01-01T00:00:00Z",
+			DryRun:         true,
+			ExpectFinished: "2024-01-01T00:00:00Z",
+		},
+		{
+			Name:         "running podgroup is unstamped",
+			Status:       scheduling.PodGroupStatus{Phase: scheduling.PodGroupRunning, Running: 1},
+			FinishedTime: "2024-01-01T00:00:00Z",
+		},
+		{
+			Name:   "podgroup of a volcano job is left to the job",
+			Status: scheduling.PodGroupStatus{Phase: scheduling.PodGroupCompleted, Succeeded: 1},
+			Owner: &metav1.OwnerReference{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Job",
+				Name: "job1", Controller: &isController},
+			FinishedTime:   "2024-01-01T00:00:00Z",
+			ExpectFinished: "2024-01-01T00:00:00Z",
+		},
+		{
+			Name:   "podgroup of another controller is left to its owner",
+			Status: scheduling.PodGroupStatus{Phase: scheduling.PodGroupCompleted, Succeeded: 1},
+			Owner: &metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job",
+				Name: "job1", Controller: &isController},
+			FinishedTime:   "2024-01-01T00:00:00Z",
+			ExpectFinished: "2024-01-01T00:00:00Z",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			gc := newFakeController()
+			gc.config.Set(framework.ReloadableConfig{GCPodGroupTTL: 24 * time.Hour, GCDryRun: testcase.DryRun})
+			pg := &scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: namespace, Annotations: map[string]string{}},
+				Spec:       scheduling.PodGroupSpec{MinMember: 1},
+				Status:     testcase.Status,
+			}
+			if testcase.FinishedTime != "" {
+				pg.Annotations[apis.PodGroupFinishedTimeKey] = testcase.FinishedTime
+			}
+			if testcase.Owner != nil {
+				pg.OwnerReferences = []metav1.OwnerReference{*testcase.Owner}
+			}
+			if _, err := gc.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create podgroup: %v", err)
+			}
+
+			if err := gc.cleanupPodGroup(pg, now); err != nil {
+				t.Fatalf("failed to clean up podgroup: %v", err)
+			}
+			drainDeletes(gc)
+
+			got, err := gc.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "pg1", metav1.GetOptions{})
+			if testcase.ExpectDeleted {
+				if err == nil {
+					t.Errorf("expected podgroup to be deleted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected podgroup to be kept, got %v", err)
+			}
+			if finished := got.Annotations[apis.PodGroupFinishedTimeKey]; finished != testcase.ExpectFinished {
+				t.Errorf("expected finished time %q, got %q", testcase.ExpectFinished, finished)
+			}
+		})
+	}
+}
+
+func TestGarbageCollector_CleanupStaleCommands(t *testing.T) {
+	namespace := "test"
+	gc := newFakeController()
+	commands := []*busv1alpha1.Command{
+		{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * defaultCommandTTL))}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "fresh", Namespace: namespace,
+			CreationTimestamp: metav1.NewTime(time.Now())}},
+	}
+	for _, cmd := range commands {
+		if _, err := gc.vcClient.BusV1alpha1().Commands(namespace).Create(context.TODO(), cmd, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create command: %v", err)
+		}
+		gc.vcInformerFactory.Bus().V1alpha1().Commands().Informer().GetIndexer().Add(cmd)
+	}
+
+	gc.cleanupFinishedResources()
+	drainDeletes(gc)
+
+	if _, err := gc.vcClient.BusV1alpha1().Commands(namespace).Get(context.TODO(), "stale", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected stale command to be deleted")
+	}
+	if _, err := gc.vcClient.BusV1alpha1().Commands(namespace).Get(context.TODO(), "fresh", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected fresh command to be kept, got %v", err)
+	}
+}
+
+func TestDeleteQueue(t *testing.T) {
+	q := newDeleteQueue()
+	add := func(namespace, name string) {
+		q.Add(&deleteRequest{kind: "Job", namespace: namespace, name: name, delete: func() error { return nil }})
+	}
+	for i := 0; i < 4; i++ {
+		add("busy", fmt.Sprintf("job%d", i))
+	}
+	add("quiet", "job0")
+	add("other", "job0")
+	// already waiting
+	add("busy", "job0")
+
+	if q.Len() != 6 {
+		t.Fatalf("expected 6 requests waiting, got %d", q.Len())
+	}
+
+	// the namespaces are taken in turn, so that the busy one does not delay the others
+	expected := [][]string{
+		{"busy/job0", "quiet/job0"},
+		{"other/job0", "busy/job1"},
+		{"busy/job2", "busy/job3"},
+	}
+	for i, keys := range expected {
+		batch, ok := q.Get(2)
+		if !ok {
+			t.Fatalf("batch %d: expected the queue not to be shut down", i)
+		}
+		var got []string
+		for _, req := range batch {
+			got = append(got, req.namespace+"/"+req.name)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(keys) {
+			t.Errorf("batch %d: expected %v, got %v", i, keys, got)
+		}
+	}
+
+	// a request taken can be added again
+	add("busy", "job0")
+	if q.Len() != 1 {
+		t.Errorf("expected 1 request waiting, got %d", q.Len())
+	}
+
+	q.ShutDown()
+	if _, ok := q.Get(2); ok {
+		t.Errorf("expected the queue to be shut down")
+	}
+}
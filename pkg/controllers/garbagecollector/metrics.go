@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
+)
+
+var (
+	sweptPluginResources = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "volcano",
+			Name:      "gc_swept_plugin_resources_total",
+			Help:      "Number of orphaned resources of job plugins deleted by the garbage collector",
+		}, []string{"resource"},
+	)
+
+	expiredResources = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "volcano",
+			Name:      "gc_expired_resources_total",
+			Help:      "Number of finished podgroups and stale commands deleted by the garbage collector",
+		}, []string{"resource"},
+	)
+
+	pendingDeletes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "volcano",
+			Name:      "gc_pending_deletes",
+			Help:      "Number of resources waiting to be deleted by the garbage collector",
+		},
+	)
+)
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+// pluginResourceSweepPeriod is by default how often the resources of the job plugins are checked for orphans.
+const pluginResourceSweepPeriod = 10 * time.Minute
+
+// sweepPluginResources deletes the Secrets, ConfigMaps and Services created by the job plugins whose
+// job no longer exists. They are normally deleted by the plugins or garbage collected with their
+// owner, but are left behind when the job is force deleted or lost in an etcd restore.
+func (gc *gccontroller) sweepPluginResources() {
+	ctx := context.TODO()
+	for _, namespace := range gc.namespaces.Namespaces() {
+		gc.sweepNamespacePluginResources(ctx, namespace)
+	}
+}
+
+// sweepNamespacePluginResources checks the resources of the job plugins of a namespace for orphans.
+func (gc *gccontroller) sweepNamespacePluginResources(ctx context.Context, namespace string) {
+	options := metav1.ListOptions{LabelSelector: jobhelpers.PluginLabelKey}
+
+	secrets, err := gc.kubeClient.CoreV1().Secrets(namespace).List(ctx, options)
+	if err != nil {
+		klog.Errorf("Failed to list Secrets of job plugins: %v", err)
+	} else {
+		for i := range secrets.Items {
+			secret := &secrets.Items[i]
+			gc.sweepPluginResource("Secret", secret, func(opts metav1.DeleteOptions) error {
+				return gc.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, opts)
+			})
+		}
+	}
+
+	cms, err := gc.kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, options)
+	if err != nil {
+		klog.Errorf("Failed to list ConfigMaps of job plugins: %v", err)
+	} else {
+		for i := range cms.Items {
+			cm := &cms.Items[i]
+			gc.sweepPluginResource("ConfigMap", cm, func(opts metav1.DeleteOptions) error {
+				return gc.kubeClient.CoreV1().ConfigMaps(cm.Namespace).Delete(ctx, cm.Name, opts)
+			})
+		}
+	}
+
+	services, err := gc.kubeClient.CoreV1().Services(namespace).List(ctx, options)
+	if err != nil {
+		klog.Errorf("Failed to list Services of job plugins: %v", err)
+	} else {
+		for i := range services.Items {
+			svc := &services.Items[i]
+			gc.sweepPluginResource("Service", svc, func(opts metav1.DeleteOptions) error {
+				return gc.kubeClient.CoreV1().Services(svc.Namespace).Delete(ctx, svc.Name, opts)
+			})
+		}
+	}
+}
+
+// sweepPluginResource queues the resource of a job plugin if its job no longer exists.
+func (gc *gccontroller) sweepPluginResource(kind string, obj metav1.Object, deleteFn func(metav1.DeleteOptions) error) {
+	if !gc.namespaces.Manages(obj.GetNamespace()) {
+		return
+	}
+	orphaned, err := gc.isOrphaned(obj)
+	if err != nil {
+		klog.Errorf("Failed to check the job of %s %s/%s: %v", kind, obj.GetNamespace(), obj.GetName(), err)
+		return
+	}
+	if !orphaned {
+		return
+	}
+
+	if gc.settings().GCDryRun {
+		klog.Infof("Dry run: would delete %s %s/%s of plugin %s, its Job %s no longer exists", kind, obj.GetNamespace(), obj.GetName(),
+			obj.GetLabels()[jobhelpers.PluginLabelKey], obj.GetLabels()[v1alpha1.JobNameKey])
+		return
+	}
+
+	uid := obj.GetUID()
+	gc.deletes.Add(&deleteRequest{
+		kind:      kind,
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+		delete: func() error {
+			err := deleteFn(metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			klog.V(3).Infof("Deleted %s %s/%s of plugin %s, its Job %s no longer exists", kind, obj.GetNamespace(), obj.GetName(),
+				obj.GetLabels()[jobhelpers.PluginLabelKey], obj.GetLabels()[v1alpha1.JobNameKey])
+			sweptPluginResources.WithLabelValues(kind).Inc()
+			return nil
+		},
+	})
+}
+
+// isOrphaned returns whether the job of the plugin resource no longer exists. A job recreated with the
+// same name reuses the resources of the plugins, so they are only orphaned once no job has that name.
+func (gc *gccontroller) isOrphaned(obj metav1.Object) (bool, error) {
+	if obj.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+	jobName := obj.GetLabels()[v1alpha1.JobNameKey]
+	if jobName == "" {
+		return false, nil
+	}
+
+	if _, err := gc.jobLister.Jobs(obj.GetNamespace()).Get(jobName); !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	// the cache may not have seen a new job yet, check the job on the API server before deleting
+	if _, err := gc.vcClient.BatchV1alpha1().Jobs(obj.GetNamespace()).Get(context.TODO(), jobName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,475 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+const (
+	// TerminationOrderKey is the job annotation listing task names, comma separated, in the order
+	// their pods are terminated when the job is restarted or killed, e.g. "worker,launcher".
+	// Pods of a task are only deleted after all pods of the tasks listed before it are gone.
+	TerminationOrderKey = "volcano.sh/termination-order"
+	// TerminationGracePeriodKey overrides the grace period, in seconds, used when the job controller
+	// deletes a pod. It can be set on the job for all tasks, or on a task template for that task only.
+	TerminationGracePeriodKey = "volcano.sh/termination-grace-period-seconds"
+	// ReplicaRestartsKey is the key of the controlled resources of the job status recording how many
+	// times each replica was recreated by the RestartPod action, as a JSON object keyed by pod name.
+	ReplicaRestartsKey = "replica-restarts"
+	// RestartCountKey is the pod annotation holding the restart count of the replica.
+	RestartCountKey = "volcano.sh/restart-count"
+	// ReplicaStatusKey is the key of the controlled resources of the job status recording phase,
+	// node, restarts and exit code of every replica, so that a failed replica can be located without
+	// listing pods. The task status of the job only counts the pods of each phase.
+	ReplicaStatusKey = "replica-status"
+	// RollingUpdateMaxUnavailableKey enables updating task templates of a running job. Pods
+	// created from an outdated template are recreated in index order, keeping at most the given
+	// number or percentage of the task's replicas unavailable at a time.
+	RollingUpdateMaxUnavailableKey = "volcano.sh/rolling-update-max-unavailable"
+	// TemplateHashKey is the pod annotation holding the hash of the task template it was created from.
+	TemplateHashKey = "volcano.sh/template-hash"
+	// PDBPolicyKey asks the job controller to manage PodDisruptionBudgets derived from
+	// minAvailable, one for the whole job ("Job") or one for each task ("Task").
+	PDBPolicyKey = "volcano.sh/pdb-policy"
+	// PendingTimeoutQueueKey is the job annotation naming the queue a job is moved to
+	// by the RequeueJob action, typically on PodPendingTimeout.
+	PendingTimeoutQueueKey = "volcano.sh/pending-timeout-queue"
+	// RerunOfKey is the job annotation naming the job it was cloned from to run again.
+	RerunOfKey = "volcano.sh/rerun-of"
+	// RerunOverridesKey is the job annotation recording the fields of the spec overridden when the job
+	// was cloned to run again, as a JSON object keyed by spec path, e.g. {"tasks.worker.replicas":"16"}.
+	RerunOverridesKey = "volcano.sh/rerun-overrides"
+	// PodRetainPolicyKey is the job annotation controlling which finished pods are kept
+	// when the job finishes, one of RetainAll (default), RetainFailed or DeleteAll.
+	PodRetainPolicyKey = "volcano.sh/pod-retain-policy"
+	// PerReplicaVolumesKey is the job annotation listing mount paths, comma separated, of the volumes
+	// whose volumeClaim is instantiated as one PVC for each replica instead of one shared by the job.
+	PerReplicaVolumesKey = "volcano.sh/per-replica-volumes"
+	// VolumeRetentionPolicyKey is the job annotation controlling whether the PVCs created from the
+	// volumeClaim of its volumes are kept until the job is deleted (Retain, default) or deleted when
+	// the job finishes (Delete).
+	VolumeRetentionPolicyKey = "volcano.sh/volume-retention-policy"
+	// ElasticMinMemberPolicyKey is the job annotation controlling the minMember of the podgroup of
+	// a job whose task replicas are scaled: the minAvailable of the job (MinAvailable, default), or
+	// all the replicas of the job (Replicas), so that the pods added by a scale up are gang scheduled
+	// with the running ones.
+	ElasticMinMemberPolicyKey = "volcano.sh/elastic-min-member"
+	// ResourceHoursKey is the key of the controlled resources of the job status recording the resources
+	// consumed by the finished pods of the job, as a JSON object of resource-hours keyed by resource name:
+	// core-hours of cpu, GiB-hours of memory and unit-hours of the extended resources,
+	// e.g. {"cpu":12.5,"memory":48,"nvidia.com/gpu":4}.
+	ResourceHoursKey = "resource-hours"
+	// AccountedPodsKey is the key of the controlled resources of the job status listing the UIDs of the
+	// existing pods whose resource-hours are already recorded, so that they are not accounted twice when
+	// the controller sees them again, e.g. after a restart.
+	AccountedPodsKey = "resource-hours-pods"
+)
+
+const (
+	// VolumeRetentionPolicyRetain keeps the PVCs of the job until the job is deleted.
+	VolumeRetentionPolicyRetain = "Retain"
+	// VolumeRetentionPolicyDelete deletes the PVCs of the job when the job finishes.
+	VolumeRetentionPolicyDelete = "Delete"
+)
+
+const (
+	// PodRetainPolicyRetainAll keeps succeeded and failed pods of a finished job.
+	PodRetainPolicyRetainAll = "RetainAll"
+	// PodRetainPolicyRetainFailed keeps only the failed pods of a finished job for inspection.
+	PodRetainPolicyRetainFailed = "RetainFailed"
+	// PodRetainPolicyDeleteAll deletes all pods once the job is finished.
+	PodRetainPolicyDeleteAll = "DeleteAll"
+)
+
+const (
+	// ElasticMinMemberPolicyMinAvailable keeps the minMember of the podgroup at the minAvailable of the job.
+	ElasticMinMemberPolicyMinAvailable = "MinAvailable"
+	// ElasticMinMemberPolicyReplicas sets the minMember of the podgroup to all the replicas of the job.
+	ElasticMinMemberPolicyReplicas = "Replicas"
+)
+
+// SchedulerEvictReason is the reason of the pod condition set by the scheduler when it evicts
+// a pod in preempt or reclaim actions.
+const SchedulerEvictReason = "Evict"
+
+// schedulerEvictMessagePrefix prefixes the action the scheduler evicts a pod for in the message of
+// the pod condition.
+const schedulerEvictMessagePrefix = "Pod is evicted, because of "
+
+const (
+	// PDBPolicyJob creates a PodDisruptionBudget with the job's minAvailable.
+	PDBPolicyJob = "Job"
+	// PDBPolicyTask creates a PodDisruptionBudget for each task with the task's minAvailable.
+	PDBPolicyTask = "Task"
+)
+
+// GetTerminationOrder returns the task termination order declared on the job, or nil if not set.
+func GetTerminationOrder(job *batch.Job) []string {
+	value, found := job.Annotations[TerminationOrderKey]
+	if !found {
+		return nil
+	}
+
+	var order []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// GetTerminationGracePeriod returns the grace period override of the pod, if any.
+func GetTerminationGracePeriod(pod *v1.Pod) (int64, bool) {
+	value, found := pod.Annotations[TerminationGracePeriodKey]
+	if !found {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return seconds, true
+}
+
+// GetReplicaRestarts returns the restart count of each replica recorded in the job status, keyed by pod name.
+func GetReplicaRestarts(job *batch.Job) map[string]int32 {
+	restarts := map[string]int32{}
+	value, found := job.Status.ControlledResources[ReplicaRestartsKey]
+	if !found {
+		return restarts
+	}
+
+	if err := json.Unmarshal([]byte(value), &restarts); err != nil {
+		klog.Warningf("Failed to parse status %s of job <%s/%s>: %v", ReplicaRestartsKey, job.Namespace, job.Name, err)
+		return map[string]int32{}
+	}
+	return restarts
+}
+
+// SetReplicaRestarts records the restart count of each replica in the job status, it returns whether the status
+// is changed. The controlled resources are copied on change, as they may be shared with the status of the cached job.
+func SetReplicaRestarts(job *batch.Job, restarts map[string]int32) bool {
+	current, found := job.Status.ControlledResources[ReplicaRestartsKey]
+	if !found && len(restarts) == 0 {
+		return false
+	}
+	value, _ := json.Marshal(restarts)
+	if current == string(value) {
+		return false
+	}
+	controlledResources := make(map[string]string, len(job.Status.ControlledResources)+1)
+	for key, resource := range job.Status.ControlledResources {
+		controlledResources[key] = resource
+	}
+	controlledResources[ReplicaRestartsKey] = string(value)
+	job.Status.ControlledResources = controlledResources
+	return true
+}
+
+// ReplicaStatus is the observed status of a single replica of a task.
+type ReplicaStatus struct {
+	Phase    v1.PodPhase `json:"phase"`
+	NodeName string      `json:"nodeName,omitempty"`
+	Restarts int32       `json:"restarts,omitempty"`
+	ExitCode *int32      `json:"exitCode,omitempty"`
+}
+
+// GetReplicaStatus returns the status of each replica recorded in the job status, keyed by task name and index.
+func GetReplicaStatus(job *batch.Job) map[string]map[string]ReplicaStatus {
+	replicaStatus := map[string]map[string]ReplicaStatus{}
+	value, found := job.Status.ControlledResources[ReplicaStatusKey]
+	if !found {
+		return replicaStatus
+	}
+
+	if err := json.Unmarshal([]byte(value), &replicaStatus); err != nil {
+		klog.Warningf("Failed to parse status %s of job <%s/%s>: %v", ReplicaStatusKey, job.Namespace, job.Name, err)
+		return map[string]map[string]ReplicaStatus{}
+	}
+	return replicaStatus
+}
+
+// SetReplicaStatus records the status of each replica in the job status, it returns whether the status is changed.
+// The controlled resources are copied on change, as they may be shared with the status of the cached job.
+func SetReplicaStatus(job *batch.Job, replicaStatus map[string]map[string]ReplicaStatus) bool {
+	current, found := job.Status.ControlledResources[ReplicaStatusKey]
+	if !found && len(replicaStatus) == 0 {
+		return false
+	}
+	value, _ := json.Marshal(replicaStatus)
+	if current == string(value) {
+		return false
+	}
+	controlledResources := make(map[string]string, len(job.Status.ControlledResources)+1)
+	for key, resource := range job.Status.ControlledResources {
+		controlledResources[key] = resource
+	}
+	controlledResources[ReplicaStatusKey] = string(value)
+	job.Status.ControlledResources = controlledResources
+	return true
+}
+
+// ResourceHours are the resource-hours consumed, keyed by resource name.
+type ResourceHours map[v1.ResourceName]float64
+
+// Add adds the resource-hours of other.
+func (r ResourceHours) Add(other ResourceHours) {
+	for name, hours := range other {
+		r[name] += hours
+	}
+}
+
+// GetResourceHours returns the resource-hours of the finished pods recorded in the job status.
+func GetResourceHours(job *batch.Job) ResourceHours {
+	hours := ResourceHours{}
+	value, found := job.Status.ControlledResources[ResourceHoursKey]
+	if !found {
+		return hours
+	}
+
+	if err := json.Unmarshal([]byte(value), &hours); err != nil {
+		klog.Warningf("Failed to parse status %s of job <%s/%s>: %v", ResourceHoursKey, job.Namespace, job.Name, err)
+		return ResourceHours{}
+	}
+	return hours
+}
+
+// GetAccountedPods returns the UIDs of the pods whose resource-hours are recorded in the job status.
+func GetAccountedPods(job *batch.Job) sets.Set[string] {
+	value, found := job.Status.ControlledResources[AccountedPodsKey]
+	if !found || value == "" {
+		return sets.New[string]()
+	}
+	return sets.New(strings.Split(value, ",")...)
+}
+
+// SetResourceHours records the resource-hours of the finished pods and the UIDs of the accounted pods in
+// the job status, it returns whether the status is changed. The controlled resources are copied on change,
+// as they may be shared with the status of the cached job.
+func SetResourceHours(job *batch.Job, hours ResourceHours, accounted sets.Set[string]) bool {
+	value, _ := json.Marshal(hours)
+	pods := strings.Join(sets.List(accounted), ",")
+	if job.Status.ControlledResources[ResourceHoursKey] == string(value) &&
+		job.Status.ControlledResources[AccountedPodsKey] == pods {
+		return false
+	}
+	controlledResources := make(map[string]string, len(job.Status.ControlledResources)+2)
+	for key, resource := range job.Status.ControlledResources {
+		controlledResources[key] = resource
+	}
+	controlledResources[ResourceHoursKey] = string(value)
+	controlledResources[AccountedPodsKey] = pods
+	job.Status.ControlledResources = controlledResources
+	return true
+}
+
+// PodResourceHours returns the resource-hours the requests of the pod consumed from its start to end;
+// the cpu, the memory and the extended resources, e.g. nvidia.com/gpu, are accounted.
+func PodResourceHours(pod *v1.Pod, end time.Time) ResourceHours {
+	if pod.Status.StartTime == nil || !end.After(pod.Status.StartTime.Time) {
+		return nil
+	}
+	duration := end.Sub(pod.Status.StartTime.Time).Hours()
+
+	hours := ResourceHours{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			switch {
+			case name == v1.ResourceCPU:
+				hours[name] += float64(quantity.MilliValue()) / 1000 * duration
+			case name == v1.ResourceMemory:
+				hours[name] += float64(quantity.Value()) / (1 << 30) * duration
+			case strings.Contains(string(name), "/"):
+				hours[name] += quantity.AsApproximateFloat64() * duration
+			}
+		}
+	}
+	return hours
+}
+
+// PodFinishTime returns when the last container of a finished pod terminated, or now if unknown.
+func PodFinishTime(pod *v1.Pod, now time.Time) time.Time {
+	var finish time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(finish) {
+			finish = terminated.FinishedAt.Time
+		}
+	}
+	if finish.IsZero() {
+		return now
+	}
+	return finish
+}
+
+// ComputeTemplateHash returns the hash of a task template, used to find pods created from an outdated one.
+func ComputeTemplateHash(template *v1.PodTemplateSpec) string {
+	data, _ := json.Marshal(template)
+	hasher := fnv.New32a()
+	hasher.Write(data)
+	return fmt.Sprintf("%x", hasher.Sum32())
+}
+
+// GetRollingUpdateMaxUnavailable returns the number of replicas of the task which may be unavailable
+// during a template update, and whether the rolling update is enabled for the job.
+func GetRollingUpdateMaxUnavailable(job *batch.Job, replicas int32) (int32, bool) {
+	value, found := job.Annotations[RollingUpdateMaxUnavailableKey]
+	if !found {
+		return 0, false
+	}
+
+	maxUnavailable := intstr.Parse(value)
+	count, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(replicas), false)
+	if err != nil {
+		klog.Warningf("Invalid annotation %s of job <%s/%s>: %v", RollingUpdateMaxUnavailableKey, job.Namespace, job.Name, err)
+		return 0, false
+	}
+	// always make progress, otherwise a template update is never rolled out.
+	if count < 1 {
+		count = 1
+	}
+	return int32(count), true
+}
+
+// GetPDBPolicy returns the PodDisruptionBudget policy of the job, empty if the job does not ask for one.
+func GetPDBPolicy(job *batch.Job) string {
+	switch policy := job.Annotations[PDBPolicyKey]; policy {
+	case PDBPolicyJob, PDBPolicyTask:
+		return policy
+	case "":
+		return ""
+	default:
+		klog.Warningf("Unknown %s <%s> of job <%s/%s>", PDBPolicyKey, policy, job.Namespace, job.Name)
+		return ""
+	}
+}
+
+// GetPodRetainPolicy returns the pod retain policy of the job, RetainAll if not set or unknown.
+func GetPodRetainPolicy(job *batch.Job) string {
+	switch policy := job.Annotations[PodRetainPolicyKey]; policy {
+	case PodRetainPolicyRetainAll, PodRetainPolicyRetainFailed, PodRetainPolicyDeleteAll:
+		return policy
+	case "":
+		return PodRetainPolicyRetainAll
+	default:
+		klog.Warningf("Unknown %s <%s> of job <%s/%s>", PodRetainPolicyKey, policy, job.Namespace, job.Name)
+		return PodRetainPolicyRetainAll
+	}
+}
+
+// GetPerReplicaVolumes returns the mount paths of the volumes instantiated for each replica.
+func GetPerReplicaVolumes(job *batch.Job) map[string]bool {
+	value, found := job.Annotations[PerReplicaVolumesKey]
+	if !found {
+		return nil
+	}
+
+	mountPaths := map[string]bool{}
+	for _, mountPath := range strings.Split(value, ",") {
+		if mountPath = strings.TrimSpace(mountPath); mountPath != "" {
+			mountPaths[mountPath] = true
+		}
+	}
+	return mountPaths
+}
+
+// IsPerReplicaVolume returns whether a PVC is created from the volumeClaim of the volume for each replica.
+func IsPerReplicaVolume(job *batch.Job, volume batch.VolumeSpec) bool {
+	return volume.VolumeClaim != nil && GetPerReplicaVolumes(job)[volume.MountPath]
+}
+
+// MakeReplicaPVCName returns the name of the PVC of a replica instantiated from the volume claim.
+func MakeReplicaPVCName(claimName string, taskName string, index int) string {
+	return fmt.Sprintf("%s-%s-%d", claimName, taskName, index)
+}
+
+// GetVolumeRetentionPolicy returns the volume retention policy of the job, Retain if not set or unknown.
+func GetVolumeRetentionPolicy(job *batch.Job) string {
+	switch policy := job.Annotations[VolumeRetentionPolicyKey]; policy {
+	case VolumeRetentionPolicyRetain, VolumeRetentionPolicyDelete:
+		return policy
+	case "":
+		return VolumeRetentionPolicyRetain
+	default:
+		klog.Warningf("Unknown %s <%s> of job <%s/%s>", VolumeRetentionPolicyKey, policy, job.Namespace, job.Name)
+		return VolumeRetentionPolicyRetain
+	}
+}
+
+// GetElasticMinMemberPolicy returns the minMember policy of the job, MinAvailable if not set or unknown.
+func GetElasticMinMemberPolicy(job *batch.Job) string {
+	switch policy := job.Annotations[ElasticMinMemberPolicyKey]; policy {
+	case ElasticMinMemberPolicyMinAvailable, ElasticMinMemberPolicyReplicas:
+		return policy
+	case "":
+		return ElasticMinMemberPolicyMinAvailable
+	default:
+		klog.Warningf("Unknown %s <%s> of job <%s/%s>", ElasticMinMemberPolicyKey, policy, job.Namespace, job.Name)
+		return ElasticMinMemberPolicyMinAvailable
+	}
+}
+
+// SchedulerEvictAction returns the action the scheduler evicted the pod for, e.g. reclaim or preempt,
+// or "" if the scheduler did not evict the pod.
+func SchedulerEvictAction(pod *v1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady && condition.Reason == SchedulerEvictReason {
+			return strings.TrimPrefix(condition.Message, schedulerEvictMessagePrefix)
+		}
+	}
+	return ""
+}
+
+// IsPreemptedPod returns whether the pod is preemptable and was evicted by the scheduler,
+// so that its eviction is not handled as a failure of the job.
+func IsPreemptedPod(pod *v1.Pod) bool {
+	if !isPreemptablePod(pod) {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady && condition.Status == v1.ConditionFalse && condition.Reason == SchedulerEvictReason {
+			return true
+		}
+	}
+	return false
+}
+
+// isPreemptablePod returns whether the pod is marked preemptable by its annotation, or else by its label,
+// as the scheduler reads it.
+func isPreemptablePod(pod *v1.Pod) bool {
+	value, found := pod.Annotations[schedulingv1beta1.PodPreemptable]
+	if !found {
+		value = pod.Labels[schedulingv1beta1.PodPreemptable]
+	}
+	preemptable, err := strconv.ParseBool(value)
+	return err == nil && preemptable
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+func TestGetTerminationOrder(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Annotations map[string]string
+		Expect      []string
+	}{
+		{
+			Name:   "no annotation",
+			Expect: nil,
+		},
+		{
+			Name:        "ordered tasks with spaces",
+			Annotations: map[string]string{TerminationOrderKey: "worker, ps ,launcher,"},
+			Expect:      []string{"worker", "ps", "launcher"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			job := &batch.Job{ObjectMeta: metav1.ObjectMeta{Annotations: testCase.Annotations}}
+			if order := GetTerminationOrder(job); !reflect.DeepEqual(order, testCase.Expect) {
+				t.Errorf("Test case failed: %s, expect: %v, got: %v", testCase.Name, testCase.Expect, order)
+			}
+		})
+	}
+}
+
+func TestReplicaRestarts(t *testing.T) {
+	job := &batch.Job{}
+	if restarts := GetReplicaRestarts(job); len(restarts) != 0 {
+		t.Errorf("expect no restarts, got: %v", restarts)
+	}
+
+	if SetReplicaRestarts(job, map[string]int32{}) {
+		t.Errorf("expect no change for empty restarts")
+	}
+	if !SetReplicaRestarts(job, map[string]int32{"job1-worker-1": 2}) {
+		t.Errorf("expect the status to be changed")
+	}
+	if SetReplicaRestarts(job, map[string]int32{"job1-worker-1": 2}) {
+		t.Errorf("expect no change for the same restarts")
+	}
+	if restarts := GetReplicaRestarts(job); restarts["job1-worker-1"] != 2 {
+		t.Errorf("expect 2 restarts of job1-worker-1, got: %v", restarts)
+	}
+
+	job.Status.ControlledResources[ReplicaRestartsKey] = "invalid"
+	if restarts := GetReplicaRestarts(job); len(restarts) != 0 {
+		t.Errorf("expect no restarts for invalid status, got: %v", restarts)
+	}
+}
+
+func TestIsPreemptedPod(t *testing.T) {
+	evicted := []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: SchedulerEvictReason}}
+	testCases := []struct {
+		Name        string
+		Annotations map[string]string
+		Labels      map[string]string
+		Conditions  []v1.PodCondition
+		Expect      bool
+	}{
+		{
+			Name:        "preemptable pod evicted by scheduler",
+			Annotations: map[string]string{schedulingv1beta1.PodPreemptable: "true"},
+			Conditions:  evicted,
+			Expect:      true,
+		},
+		{
+			Name:       "pod labeled preemptable evicted by scheduler",
+			Labels:     map[string]string{schedulingv1beta1.PodPreemptable: "true"},
+			Conditions: evicted,
+			Expect:     true,
+		},
+		{
+			Name:        "annotation overriding the label",
+			Annotations: map[string]string{schedulingv1beta1.PodPreemptable: "false"},
+			Labels:      map[string]string{schedulingv1beta1.PodPreemptable: "true"},
+			Conditions:  evicted,
+			Expect:      false,
+		},
+		{
+			Name:       "pod not marked preemptable",
+			Conditions: evicted,
+			Expect:     false,
+		},
+		{
+			Name:        "preemptable pod ready again",
+			Annotations: map[string]string{schedulingv1beta1.PodPreemptable: "true"},
+			Conditions:  []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue, Reason: SchedulerEvictReason}},
+			Expect:      false,
+		},
+		{
+			Name:        "preemptable pod deleted by others",
+			Annotations: map[string]string{schedulingv1beta1.PodPreemptable: "true"},
+			Expect:      false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: testCase.Annotations, Labels: testCase.Labels},
+				Status:     v1.PodStatus{Conditions: testCase.Conditions},
+			}
+			if got := IsPreemptedPod(pod); got != testCase.Expect {
+				t.Errorf("expected %v, got %v", testCase.Expect, got)
+			}
+		})
+	}
+}
+
+func TestResourceHours(t *testing.T) {
+	job := &batch.Job{}
+	if hours := GetResourceHours(job); len(hours) != 0 {
+		t.Errorf("expect no resource-hours, got: %v", hours)
+	}
+	if pods := GetAccountedPods(job); pods.Len() != 0 {
+		t.Errorf("expect no accounted pods, got: %v", pods)
+	}
+
+	hours := ResourceHours{v1.ResourceCPU: 2}
+	hours.Add(ResourceHours{v1.ResourceCPU: 1.5, "nvidia.com/gpu": 4})
+	if !SetResourceHours(job, hours, sets.New("uid-2", "uid-1")) {
+		t.Errorf("expect the status to be changed")
+	}
+	expected := ResourceHours{v1.ResourceCPU: 3.5, "nvidia.com/gpu": 4}
+	if hours := GetResourceHours(job); !reflect.DeepEqual(hours, expected) {
+		t.Errorf("expect resource-hours %v, got: %v", expected, hours)
+	}
+	if pods := GetAccountedPods(job); !pods.Equal(sets.New("uid-1", "uid-2")) {
+		t.Errorf("expect accounted pods uid-1 and uid-2, got: %v", pods)
+	}
+	if SetResourceHours(job, expected, sets.New("uid-1", "uid-2")) {
+		t.Errorf("expect the status not to be changed")
+	}
+
+	job.Status.ControlledResources[ResourceHoursKey] = "invalid"
+	if hours := GetResourceHours(job); len(hours) != 0 {
+		t.Errorf("expect no resource-hours for invalid status, got: %v", hours)
+	}
+}
+
+func TestPodResourceHours(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newPod := func(startTime *metav1.Time, requests ...v1.ResourceList) *v1.Pod {
+		pod := &v1.Pod{Status: v1.PodStatus{StartTime: startTime}}
+		for _, request := range requests {
+			pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Resources: v1.ResourceRequirements{Requests: request}})
+		}
+		return pod
+	}
+
+	testCases := []struct {
+		Name   string
+		Pod    *v1.Pod
+		End    time.Time
+		Expect ResourceHours
+	}{
+		{
+			Name:   "pod not started",
+			Pod:    newPod(nil, v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}),
+			End:    start.Add(time.Hour),
+			Expect: nil,
+		},
+		{
+			Name: "containers requests",
+			Pod: newPod(&metav1.Time{Time: start},
+				v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("2Gi"), "nvidia.com/gpu": resource.MustParse("1")},
+				v1.ResourceList{v1.ResourceCPU: resource.MustParse("1500m"), v1.ResourceEphemeralStorage: resource.MustParse("10Gi")}),
+			End:    start.Add(90 * time.Minute),
+			Expect: ResourceHours{v1.ResourceCPU: 3, v1.ResourceMemory: 3, "nvidia.com/gpu": 1.5},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if hours := PodResourceHours(testCase.Pod, testCase.End); !reflect.DeepEqual(hours, testCase.Expect) {
+				t.Errorf("expect resource-hours %v, got: %v", testCase.Expect, hours)
+			}
+		})
+	}
+}
+
+func TestPodFinishTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	finished := now.Add(-time.Hour)
+	pod := &v1.Pod{}
+	if finish := PodFinishTime(pod, now); !finish.Equal(now) {
+		t.Errorf("expect finish time %v, got: %v", now, finish)
+	}
+
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{
+		{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.Time{Time: finished.Add(-time.Minute)}}}},
+		{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.Time{Time: finished}}}},
+	}
+	if finish := PodFinishTime(pod, now); !finish.Equal(finished) {
+		t.Errorf("expect finish time %v, got: %v", finished, finish)
+	}
+}
+
+func TestSchedulerEvictAction(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Conditions []v1.PodCondition
+		Expect     string
+	}{
+		{
+			Name:   "not evicted",
+			Expect: "",
+		},
+		{
+			Name: "evicted by reclaim",
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue},
+				{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: SchedulerEvictReason, Message: "Pod is evicted, because of reclaim"},
+			},
+			Expect: "reclaim",
+		},
+		{
+			Name: "not ready for another reason",
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: "ContainersNotReady"},
+			},
+			Expect: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			pod := &v1.Pod{Status: v1.PodStatus{Conditions: testCase.Conditions}}
+			if action := SchedulerEvictAction(pod); action != testCase.Expect {
+				t.Errorf("Test case failed: %s, expect: %q, got: %q", testCase.Name, testCase.Expect, action)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+const (
+	// TaskAutoscalingKey is the job annotation declaring the autoscaling policies of its tasks as a
+	// JSON object keyed by task name, e.g.
+	// {"worker":{"minReplicas":2,"maxReplicas":8,"metric":"queue-depth","targetAverageValue":100}}.
+	TaskAutoscalingKey = "volcano.sh/task-autoscaling"
+	// LastShrinkTimeKey is the job annotation set by the job controller, in RFC3339, when it scales
+	// the job down on reclaim; the autoscaler does not scale the job up within the scale down
+	// stabilization window of its tasks after that.
+	LastShrinkTimeKey = "volcano.sh/last-shrink-time"
+)
+
+const (
+	// autoscalingTolerance is the ratio of the metric to its target within which the replicas are kept.
+	autoscalingTolerance = 0.1
+	// defaultScaleDownStabilization is how long the highest recommendation is kept before scaling down.
+	defaultScaleDownStabilization = 5 * time.Minute
+)
+
+// TaskAutoscaling is the autoscaling policy of a task, scaling its replicas between MinReplicas and
+// MaxReplicas so that the metric returned by the Prometheus query meets its target.
+type TaskAutoscaling struct {
+	MinReplicas int32 `json:"minReplicas"`
+	MaxReplicas int32 `json:"maxReplicas"`
+	// Metric is the name of the metric, whose Prometheus query is configured for the autoscaler by
+	// the cluster administrator.
+	Metric string `json:"metric"`
+	// TargetValue is the target of the metric, the replicas are scaled in proportion to it.
+	TargetValue *float64 `json:"targetValue,omitempty"`
+	// TargetAverageValue is the target of the metric divided by the replicas, e.g. the queue depth per worker.
+	TargetAverageValue *float64 `json:"targetAverageValue,omitempty"`
+	// ScaleDownStabilizationSeconds is how long the highest recommendation is kept before the task is
+	// scaled down, 300 by default.
+	ScaleDownStabilizationSeconds *int32 `json:"scaleDownStabilizationSeconds,omitempty"`
+}
+
+// ScaleDownStabilization returns how long the highest recommendation is kept before scaling down.
+func (a *TaskAutoscaling) ScaleDownStabilization() time.Duration {
+	if a.ScaleDownStabilizationSeconds == nil {
+		return defaultScaleDownStabilization
+	}
+	return time.Duration(*a.ScaleDownStabilizationSeconds) * time.Second
+}
+
+// GetTaskAutoscaling returns the autoscaling policies of the tasks of the job, keyed by task name.
+func GetTaskAutoscaling(job *batch.Job) (map[string]*TaskAutoscaling, error) {
+	value, found := job.Annotations[TaskAutoscalingKey]
+	if !found {
+		return nil, nil
+	}
+
+	policies := map[string]*TaskAutoscaling{}
+	if err := json.Unmarshal([]byte(value), &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s of job <%s/%s>: %v", TaskAutoscalingKey, job.Namespace, job.Name, err)
+	}
+	return policies, nil
+}
+
+// ValidateTaskAutoscaling checks the autoscaling policies of the job against its tasks.
+func ValidateTaskAutoscaling(job *batch.Job) error {
+	policies, err := GetTaskAutoscaling(job)
+	if err != nil {
+		return err
+	}
+
+	tasks := map[string]*batch.TaskSpec{}
+	for i := range job.Spec.Tasks {
+		tasks[job.Spec.Tasks[i].Name] = &job.Spec.Tasks[i]
+	}
+	for name, policy := range policies {
+		task, found := tasks[name]
+		if !found {
+			return fmt.Errorf("autoscaling policy of unknown task %s", name)
+		}
+		if policy == nil {
+			return fmt.Errorf("empty autoscaling policy of task %s", name)
+		}
+		if policy.MinReplicas < 0 || policy.MaxReplicas < policy.MinReplicas {
+			return fmt.Errorf("autoscaling policy of task %s must have 0 <= minReplicas <= maxReplicas", name)
+		}
+		if task.MinAvailable != nil && policy.MinReplicas < *task.MinAvailable {
+			return fmt.Errorf("minReplicas of the autoscaling policy of task %s must be >= the minAvailable of the task", name)
+		}
+		if policy.Metric == "" {
+			return fmt.Errorf("autoscaling policy of task %s must have a metric", name)
+		}
+		if (policy.TargetValue == nil) == (policy.TargetAverageValue == nil) {
+			return fmt.Errorf("autoscaling policy of task %s must have exactly one of targetValue and targetAverageValue", name)
+		}
+		if (policy.TargetValue != nil && *policy.TargetValue <= 0) || (policy.TargetAverageValue != nil && *policy.TargetAverageValue <= 0) {
+			return fmt.Errorf("the target of the autoscaling policy of task %s must be positive", name)
+		}
+		if policy.ScaleDownStabilizationSeconds != nil && *policy.ScaleDownStabilizationSeconds < 0 {
+			return fmt.Errorf("scaleDownStabilizationSeconds of the autoscaling policy of task %s must be >= 0", name)
+		}
+	}
+	return nil
+}
+
+// RecommendReplicas returns the replicas of the task for the metric value, between the minReplicas and
+// maxReplicas of the policy. The replicas are kept while the metric is within 10% of its target.
+func (a *TaskAutoscaling) RecommendReplicas(current int32, value float64) int32 {
+	var replicas float64
+	switch {
+	case a.TargetAverageValue != nil:
+		replicas = value / *a.TargetAverageValue
+	case a.TargetValue != nil:
+		// a task without replicas is scaled as if it had one
+		replicas = math.Max(float64(current), 1) * value / *a.TargetValue
+	default:
+		return current
+	}
+
+	desired := int32(math.Ceil(replicas))
+	if current > 0 && math.Abs(replicas/float64(current)-1) <= autoscalingTolerance {
+		desired = current
+	}
+	if desired < a.MinReplicas {
+		desired = a.MinReplicas
+	}
+	if desired > a.MaxReplicas {
+		desired = a.MaxReplicas
+	}
+	return desired
+}
+
+// GetLastShrinkTime returns when the job was last scaled down on reclaim.
+func GetLastShrinkTime(job *batch.Job) (time.Time, bool) {
+	value, found := job.Annotations[LastShrinkTimeKey]
+	if !found {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestRecommendReplicas(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+
+	testCases := []struct {
+		Name     string
+		Policy   TaskAutoscaling
+		Current  int32
+		Metric   float64
+		Expected int32
+	}{
+		{
+			Name:     "average value scales to the metric divided by the target",
+			Policy:   TaskAutoscaling{MinReplicas: 1, MaxReplicas: 10, TargetAverageValue: value(100)},
+			Current:  2,
+			Metric:   450,
+			Expected: 5,
+		},
+		{
+			Name:     "value scales in proportion to the target",
+			Policy:   TaskAutoscaling{MinReplicas: 1, MaxReplicas: 10, TargetValue: value(0.5)},
+			Current:  4,
+			Metric:   0.25,
+			Expected: 2,
+		},
+		{
+			Name:     "replicas are kept within the tolerance",
+			Policy:   TaskAutoscaling{MinReplicas: 1, MaxReplicas: 10, TargetAverageValue: value(100)},
+			Current:  4,
+			Metric:   430,
+			Expected: 4,
+		},
+		{
+			Name:     "replicas are bounded by maxReplicas",
+			Policy:   TaskAutoscaling{MinReplicas: 1, MaxReplicas: 10, TargetAverageValue: value(100)},
+			Current:  4,
+			Metric:   5000,
+			Expected: 10,
+		},
+		{
+			Name:     "replicas are bounded by minReplicas",
+			Policy:   TaskAutoscaling{MinReplicas: 2, MaxReplicas: 10, TargetAverageValue: value(100)},
+			Current:  4,
+			Metric:   0,
+			Expected: 2,
+		},
+		{
+			Name:     "task without replicas is scaled up",
+			Policy:   TaskAutoscaling{MinReplicas: 0, MaxReplicas: 10, TargetValue: value(10)},
+			Current:  0,
+			Metric:   30,
+			Expected: 3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if replicas := testCase.Policy.RecommendReplicas(testCase.Current, testCase.Metric); replicas != testCase.Expected {
+				t.Errorf("expected %d replicas, got %d", testCase.Expected, replicas)
+			}
+		})
+	}
+}
+
+func TestValidateTaskAutoscaling(t *testing.T) {
+	minAvailable := int32(2)
+	testCases := []struct {
+		Name      string
+		Policy    string
+		ExpectErr bool
+	}{
+		{
+			Name:   "valid policy",
+			Policy: `{"worker":{"minReplicas":2,"maxReplicas":8,"metric":"queue-depth","targetAverageValue":100}}`,
+		},
+		{
+			Name:      "unknown task",
+			Policy:    `{"ps":{"minReplicas":2,"maxReplicas":8,"metric":"up","targetValue":1}}`,
+			ExpectErr: true,
+		},
+		{
+			Name:      "minReplicas below the minAvailable of the task",
+			Policy:    `{"worker":{"minReplicas":1,"maxReplicas":8,"metric":"up","targetValue":1}}`,
+			ExpectErr: true,
+		},
+		{
+			Name:      "maxReplicas below minReplicas",
+			Policy:    `{"worker":{"minReplicas":4,"maxReplicas":2,"metric":"up","targetValue":1}}`,
+			ExpectErr: true,
+		},
+		{
+			Name:      "both targets",
+			Policy:    `{"worker":{"minReplicas":2,"maxReplicas":8,"metric":"up","targetValue":1,"targetAverageValue":1}}`,
+			ExpectErr: true,
+		},
+		{
+			Name:      "no metric",
+			Policy:    `{"worker":{"minReplicas":2,"maxReplicas":8,"targetValue":1}}`,
+			ExpectErr: true,
+		},
+		{
+			Name:      "malformed policy",
+			Policy:    `{"worker":[]}`,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{TaskAutoscalingKey: testCase.Policy}},
+				Spec: batch.JobSpec{
+					Tasks: []batch.TaskSpec{{Name: "worker", Replicas: 4, MinAvailable: &minAvailable}},
+				},
+			}
+			if err := ValidateTaskAutoscaling(job); (err != nil) != testCase.ExpectErr {
+				t.Errorf("expected error: %v, got %v", testCase.ExpectErr, err)
+			}
+		})
+	}
+}
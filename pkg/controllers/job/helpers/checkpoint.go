@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+const (
+	// CheckpointsKey is the job annotation recording the last completed checkpoint of every
+	// replica, as a JSON object keyed by task name and index. It is kept when the job is run
+	// again, so that the replicas of the new run resume from the checkpoints.
+	CheckpointsKey = "volcano.sh/checkpoints"
+	// ResumeFromKey is the pod annotation holding the path of the checkpoint the replica resumes from.
+	ResumeFromKey = "volcano.sh/resume-from"
+	// CheckpointPathEnv is the environment variable holding the path of the checkpoint the replica resumes from.
+	CheckpointPathEnv = "VC_CHECKPOINT_PATH"
+)
+
+// Checkpoint is a completed checkpoint of a replica.
+type Checkpoint struct {
+	Path string `json:"path"`
+	// Time is when the checkpoint was requested, in RFC3339.
+	Time string `json:"time,omitempty"`
+}
+
+// GetPodCheckpoint returns the checkpoint reported by the pod, if it is completed.
+func GetPodCheckpoint(pod *v1.Pod) (Checkpoint, bool) {
+	if pod.Annotations[apis.CheckpointStatusKey] != apis.CheckpointCompleted {
+		return Checkpoint{}, false
+	}
+	path := pod.Annotations[apis.CheckpointPathKey]
+	if path == "" {
+		return Checkpoint{}, false
+	}
+	return Checkpoint{Path: path, Time: pod.Annotations[apis.CheckpointRequestedKey]}, true
+}
+
+// GetCheckpoints returns the checkpoint of each replica recorded on the job, keyed by task name and index.
+func GetCheckpoints(job *batch.Job) map[string]map[string]Checkpoint {
+	checkpoints := map[string]map[string]Checkpoint{}
+	value, found := job.Annotations[CheckpointsKey]
+	if !found {
+		return checkpoints
+	}
+
+	if err := json.Unmarshal([]byte(value), &checkpoints); err != nil {
+		klog.Warningf("Failed to parse annotation %s of job <%s/%s>: %v", CheckpointsKey, job.Namespace, job.Name, err)
+		return map[string]map[string]Checkpoint{}
+	}
+	return checkpoints
+}
+
+// SetCheckpoints records the checkpoint of each replica on the job, it returns whether the annotation is changed.
+func SetCheckpoints(job *batch.Job, checkpoints map[string]map[string]Checkpoint) bool {
+	current, found := job.Annotations[CheckpointsKey]
+	if !found && len(checkpoints) == 0 {
+		return false
+	}
+	value, _ := json.Marshal(checkpoints)
+	if current == string(value) {
+		return false
+	}
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations[CheckpointsKey] = string(value)
+	return true
+}
+
+// SetResumeFrom makes the pod resume from the checkpoint, by the annotation and the
+// environment variable of its containers. A variable set by the template is kept.
+func SetResumeFrom(pod *v1.Pod, checkpoint Checkpoint) {
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[ResumeFromKey] = checkpoint.Path
+	setEnv := func(containers []v1.Container) {
+		for i := range containers {
+			found := false
+			for _, env := range containers[i].Env {
+				if env.Name == CheckpointPathEnv {
+					found = true
+					break
+				}
+			}
+			if !found {
+				containers[i].Env = append(containers[i].Env, v1.EnvVar{Name: CheckpointPathEnv, Value: checkpoint.Path})
+			}
+		}
+	}
+	setEnv(pod.Spec.InitContainers)
+	setEnv(pod.Spec.Containers)
+}
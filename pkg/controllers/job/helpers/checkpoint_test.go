@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+func TestGetPodCheckpoint(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Annotations map[string]string
+		Expected    Checkpoint
+		Found       bool
+	}{
+		{
+			Name: "completed checkpoint",
+			Annotations: map[string]string{
+				apis.CheckpointRequestedKey: "2024-01-01T00:00:00Z",
+				apis.CheckpointStatusKey:    apis.CheckpointCompleted,
+				apis.CheckpointPathKey:      "s3://ckpt/worker-0",
+			},
+			Expected: Checkpoint{Path: "s3://ckpt/worker-0", Time: "2024-01-01T00:00:00Z"},
+			Found:    true,
+		},
+		{
+			Name: "failed checkpoint",
+			Annotations: map[string]string{
+				apis.CheckpointStatusKey: apis.CheckpointFailed,
+				apis.CheckpointPathKey:   "s3://ckpt/worker-0",
+			},
+		},
+		{
+			Name: "completed checkpoint without path",
+			Annotations: map[string]string{
+				apis.CheckpointStatusKey: apis.CheckpointCompleted,
+			},
+		},
+		{
+			Name: "no checkpoint",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: testCase.Annotations}}
+			checkpoint, found := GetPodCheckpoint(pod)
+			if found != testCase.Found || checkpoint != testCase.Expected {
+				t.Errorf("expected %v %v, got %v %v", testCase.Expected, testCase.Found, checkpoint, found)
+			}
+		})
+	}
+}
+
+func TestSetCheckpoints(t *testing.T) {
+	job := &batch.Job{}
+	if SetCheckpoints(job, map[string]map[string]Checkpoint{}) {
+		t.Errorf("expected no annotation for no checkpoints")
+	}
+
+	checkpoints := map[string]map[string]Checkpoint{"worker": {"0": {Path: "/ckpt/0"}}}
+	if !SetCheckpoints(job, checkpoints) {
+		t.Errorf("expected the annotation to be changed")
+	}
+	if SetCheckpoints(job, checkpoints) {
+		t.Errorf("expected the annotation not to be changed again")
+	}
+	if got := GetCheckpoints(job); !reflect.DeepEqual(got, checkpoints) {
+		t.Errorf("expected %v, got %v", checkpoints, got)
+	}
+}
+
+func TestSetResumeFrom(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init"}},
+			Containers: []v1.Container{
+				{Name: "main"},
+				{Name: "custom", Env: []v1.EnvVar{{Name: CheckpointPathEnv, Value: "/custom"}}},
+			},
+		},
+	}
+	SetResumeFrom(pod, Checkpoint{Path: "/ckpt/0"})
+
+	if pod.Annotations[ResumeFromKey] != "/ckpt/0" {
+		t.Errorf("expected annotation %s to be /ckpt/0, got %s", ResumeFromKey, pod.Annotations[ResumeFromKey])
+	}
+	expected := map[string]string{"init": "/ckpt/0", "main": "/ckpt/0", "custom": "/custom"}
+	for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if len(c.Env) != 1 || c.Env[0].Value != expected[c.Name] {
+			t.Errorf("container %s: expected %s=%s, got %v", c.Name, CheckpointPathEnv, expected[c.Name], c.Env)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
@@ -37,6 +38,15 @@ const (
 	persistentVolumeClaimFmt = "%s-pvc-%s"
 )
 
+// rerunDroppedAnnotations are the annotations recording the state of a job run, which
+// are not copied to the job running it again.
+var rerunDroppedAnnotations = []string{
+	ParallelismKey,
+	RerunOverridesKey,
+	batch.JobForwardingKey,
+	v1.LastAppliedConfigAnnotation,
+}
+
 // GetPodIndexUnderTask returns task Index.
 func GetPodIndexUnderTask(pod *v1.Pod) string {
 	num := strings.Split(pod.Name, "-")
@@ -146,3 +156,65 @@ func GetPodsNameUnderTask(taskName string, job *batch.Job) []string {
 	}
 	return res
 }
+
+// NewRerunJob returns a copy of the job to run it again. The spec is kept while the status,
+// the run-time annotations and the generated volume claim names are cleared, so that the
+// controller creates the pods, services, secrets and volumes of the new job from scratch.
+// If name is empty, the name is generated from the name of the job.
+func NewRerunJob(job *batch.Job, name string) *batch.Job {
+	rerun := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   job.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *job.Spec.DeepCopy(),
+	}
+	if name == "" {
+		rerun.GenerateName = job.Name + "-"
+	}
+
+	for key, value := range job.Labels {
+		rerun.Labels[key] = value
+	}
+	for key, value := range job.Annotations {
+		rerun.Annotations[key] = value
+	}
+	for _, key := range rerunDroppedAnnotations {
+		delete(rerun.Annotations, key)
+	}
+	rerun.Annotations[RerunOfKey] = job.Name
+
+	for i := range rerun.Spec.Volumes {
+		if rerun.Spec.Volumes[i].VolumeClaim != nil {
+			rerun.Spec.Volumes[i].VolumeClaimName = ""
+		}
+	}
+
+	return rerun
+}
+
+// GetJobStartTime returns when the job started running the last time, nil if it has not run.
+func GetJobStartTime(job *batch.Job) *metav1.Time {
+	for i := len(job.Status.Conditions) - 1; i >= 0; i-- {
+		if job.Status.Conditions[i].Status == batch.Running {
+			return job.Status.Conditions[i].LastTransitionTime
+		}
+	}
+	return nil
+}
+
+// GetJobCompletionTime returns when the job finished, nil if it is not finished.
+func GetJobCompletionTime(job *batch.Job) *metav1.Time {
+	switch job.Status.State.Phase {
+	case batch.Completed, batch.Failed, batch.Terminated, batch.Aborted:
+	default:
+		return nil
+	}
+
+	if n := len(job.Status.Conditions); n > 0 && job.Status.Conditions[n-1].Status == job.Status.State.Phase {
+		return job.Status.Conditions[n-1].LastTransitionTime
+	}
+	return &job.Status.State.LastTransitionTime
+}
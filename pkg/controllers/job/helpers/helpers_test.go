@@ -17,6 +17,7 @@ limitations under the License.
 package helpers
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -333,3 +334,127 @@ func contains(s []string, e string) bool {
 	}
 	return false
 }
+
+func TestNewRerunJob(t *testing.T) {
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: "test",
+			UID:       "job1-uid",
+			Labels:    map[string]string{"app": "test"},
+			Annotations: map[string]string{
+				"owner":        "team-a",
+				ParallelismKey: "4",
+			},
+		},
+		Spec: batch.JobSpec{
+			Queue: "default",
+			Volumes: []batch.VolumeSpec{
+				{MountPath: "/data", VolumeClaimName: "job1-pvc-abc", VolumeClaim: &v1.PersistentVolumeClaimSpec{}},
+				{MountPath: "/shared", VolumeClaimName: "shared-pvc"},
+			},
+		},
+		Status: batch.JobStatus{State: batch.JobState{Phase: batch.Completed}},
+	}
+
+	testCases := []struct {
+		Name               string
+		NewName            string
+		ExpectName         string
+		ExpectGenerateName string
+	}{
+		{
+			Name:               "generate name",
+			ExpectGenerateName: "job1-",
+		},
+		{
+			Name:       "given name",
+			NewName:    "job2",
+			ExpectName: "job2",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			rerun := NewRerunJob(job, testCase.NewName)
+			if rerun.Name != testCase.ExpectName || rerun.GenerateName != testCase.ExpectGenerateName {
+				t.Errorf("expected name %q and generate name %q, got %q and %q",
+					testCase.ExpectName, testCase.ExpectGenerateName, rerun.Name, rerun.GenerateName)
+			}
+			if rerun.UID != "" || rerun.Status.State.Phase != "" {
+				t.Errorf("expected identity and status of the job to be cleared, got %v", rerun)
+			}
+			expectAnnotations := map[string]string{"owner": "team-a", RerunOfKey: "job1"}
+			if !reflect.DeepEqual(rerun.Annotations, expectAnnotations) {
+				t.Errorf("expected annotations %v, got %v", expectAnnotations, rerun.Annotations)
+			}
+			if rerun.Spec.Volumes[0].VolumeClaimName != "" || rerun.Spec.Volumes[1].VolumeClaimName != "shared-pvc" {
+				t.Errorf("expected only generated volume claim names to be cleared, got %v", rerun.Spec.Volumes)
+			}
+		})
+	}
+	if job.Spec.Volumes[0].VolumeClaimName != "job1-pvc-abc" {
+		t.Errorf("expected the job to be unchanged")
+	}
+}
+
+func TestGetJobStartAndCompletionTime(t *testing.T) {
+	started := metav1.NewTime(time.Now().Add(-time.Hour))
+	restarted := metav1.NewTime(time.Now().Add(-time.Minute))
+	finished := metav1.Now()
+	condition := func(phase batch.JobPhase, at metav1.Time) batch.JobCondition {
+		return batch.JobCondition{Status: phase, LastTransitionTime: &at}
+	}
+
+	testCases := []struct {
+		Name                 string
+		Phase                batch.JobPhase
+		Conditions           []batch.JobCondition
+		ExpectStartTime      *metav1.Time
+		ExpectCompletionTime *metav1.Time
+	}{
+		{
+			Name:       "pending job",
+			Phase:      batch.Pending,
+			Conditions: []batch.JobCondition{condition(batch.Pending, started)},
+		},
+		{
+			Name:  "running job restarted",
+			Phase: batch.Running,
+			Conditions: []batch.JobCondition{
+				condition(batch.Pending, started),
+				condition(batch.Running, started),
+				condition(batch.Restarting, restarted),
+				condition(batch.Pending, restarted),
+				condition(batch.Running, restarted),
+			},
+			ExpectStartTime: &restarted,
+		},
+		{
+			Name:  "completed job",
+			Phase: batch.Completed,
+			Conditions: []batch.JobCondition{
+				condition(batch.Pending, started),
+				condition(batch.Running, started),
+				condition(batch.Completed, finished),
+			},
+			ExpectStartTime:      &started,
+			ExpectCompletionTime: &finished,
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job := &batch.Job{}
+			job.Status.State.Phase = testcase.Phase
+			job.Status.Conditions = testcase.Conditions
+
+			if startTime := GetJobStartTime(job); !reflect.DeepEqual(startTime, testcase.ExpectStartTime) {
+				t.Errorf("expected start time %v, got %v", testcase.ExpectStartTime, startTime)
+			}
+			if completionTime := GetJobCompletionTime(job); !reflect.DeepEqual(completionTime, testcase.ExpectCompletionTime) {
+				t.Errorf("expected completion time %v, got %v", testcase.ExpectCompletionTime, completionTime)
+			}
+		})
+	}
+}
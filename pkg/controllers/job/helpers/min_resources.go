@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/util"
+)
+
+// IsGangTask returns whether the pods of the task are scheduled by the scheduler of the job,
+// so the task takes part in the gang scheduling of the job's PodGroup. A task with its own
+// schedulerName, e.g. default-scheduler, is scheduled pod by pod.
+func IsGangTask(job *batch.Job, task *batch.TaskSpec) bool {
+	schedulerName := task.Template.Spec.SchedulerName
+	return schedulerName == "" || schedulerName == job.Spec.SchedulerName
+}
+
+// GetPodGroupMinMember returns the minMember of the job's PodGroup, which only counts the
+// replicas of the gang tasks.
+func GetPodGroupMinMember(job *batch.Job) int32 {
+	replicas := int32(0)
+	for i := range job.Spec.Tasks {
+		if IsGangTask(job, &job.Spec.Tasks[i]) {
+			replicas += job.Spec.Tasks[i].Replicas
+		}
+	}
+	// the pods added by a scale up are gang scheduled with the running ones if asked for
+	if job.Spec.MinAvailable < replicas && GetElasticMinMemberPolicy(job) != ElasticMinMemberPolicyReplicas {
+		replicas = job.Spec.MinAvailable
+	}
+	// a podgroup gangs at least one pod, which is also enforced by the podgroup admission
+	if replicas < 1 {
+		return 1
+	}
+	return replicas
+}
+
+// CalcMinResources returns the minResources of the PodGroup of the job: the requests of its minMember
+// pods, taken from the gang tasks given in priority order, higher priority first.
+func CalcMinResources(job *batch.Job, tasks []batch.TaskSpec) v1.ResourceList {
+	totalMinAvailable := int32(0)
+	for _, task := range tasks {
+		if task.MinAvailable != nil { // actually, it can not be nil, because nil value will be patched in webhook
+			totalMinAvailable += *task.MinAvailable
+		} else {
+			totalMinAvailable += task.Replicas
+		}
+	}
+
+	// see docs https://github.com/volcano-sh/volcano/pull/2945
+	// 1. job.MinAvailable < sum(task.MinAvailable), regard podgroup's min resource as sum of the first minAvailable,
+	// according to https://github.com/volcano-sh/volcano/blob/c91eb07f2c300e4d5c826ff11a63b91781b3ac11/pkg/scheduler/api/job_info.go#L738-L740
+	minMember := GetPodGroupMinMember(job)
+	if minMember < totalMinAvailable {
+		return CalcFirstCountResources(tasks, minMember)
+	}
+
+	// 2. job.MinAvailable >= sum(task.MinAvailable)
+	return CalcTasksMinResources(tasks, minMember)
+}
+
+// CalcFirstCountResources return the first count tasks resource, the tasks are given in priority order
+func CalcFirstCountResources(tasks []batch.TaskSpec, count int32) v1.ResourceList {
+	minReq := v1.ResourceList{}
+
+	for _, task := range tasks {
+		if count <= task.Replicas {
+			minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, count))
+			break
+		} else {
+			minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, task.Replicas))
+			count -= task.Replicas
+		}
+	}
+	return minReq
+}
+
+// CalcTasksMinResources sums up all task's min available; if not enough, then fill up to jobMinAvailable via task's replicas,
+// the tasks are given in priority order
+func CalcTasksMinResources(tasks []batch.TaskSpec, jobMinAvailable int32) v1.ResourceList {
+	minReq := v1.ResourceList{}
+	podCnt := int32(0)
+
+	// 1. first sum up those tasks whose MinAvailable is set
+	for _, task := range tasks {
+		if task.MinAvailable == nil { // actually, all task's min available is set by webhook
+			continue
+		}
+
+		validReplics := *task.MinAvailable
+		if left := jobMinAvailable - podCnt; left < validReplics {
+			validReplics = left
+		}
+		minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, validReplics))
+		podCnt += validReplics
+		if podCnt >= jobMinAvailable {
+			break
+		}
+	}
+
+	if podCnt >= jobMinAvailable {
+		return minReq
+	}
+
+	// 2. fill up the count of pod to jobMinAvailable with tasks whose replicas is not used up, higher priority first
+	leftCnt := jobMinAvailable - podCnt
+	for _, task := range tasks {
+		left := task.Replicas
+		if task.MinAvailable != nil {
+			if *task.MinAvailable == task.Replicas {
+				continue
+			} else {
+				left = task.Replicas - *task.MinAvailable
+			}
+		}
+
+		if leftCnt >= left {
+			minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, left))
+			leftCnt -= left
+		} else {
+			minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, leftCnt))
+			leftCnt = 0
+		}
+		if leftCnt <= 0 {
+			break
+		}
+	}
+	return minReq
+}
+
+// calTaskRequests returns requests resource with validReplica replicas
+func calTaskRequests(pod *v1.Pod, validReplica int32) v1.ResourceList {
+	minReq := v1.ResourceList{}
+	usage := *util.GetPodQuotaUsage(pod)
+	for i := int32(0); i < validReplica; i++ {
+		minReq = quotav1.Add(minReq, usage)
+	}
+	return minReq
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// SetRerunOverrides overrides the fields of the spec of the job cloned to run again, by spec path, see
+// SetJobSpecValue, and records the overrides in its annotations.
+func SetRerunOverrides(job *batch.Job, overrides map[string]string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(overrides))
+	for path := range overrides {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := SetJobSpecValue(&job.Spec, path, overrides[path]); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[RerunOverridesKey] = string(data)
+	return nil
+}
+
+// SetJobSpecValue sets the field of the job spec at the path, e.g. "tasks.worker.replicas", to the value
+// parsed as YAML, e.g. 16, true or 2Gi; null clears the field. The elements of lists are selected by name,
+// e.g. "tasks.worker.template.spec.containers.main.image", or by index. The path may start with "spec.".
+func SetJobSpecValue(spec *batch.JobSpec, path, value string) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		parsed = value
+	}
+	obj, err = setPathValue(obj, strings.Split(strings.TrimPrefix(path, "spec."), "."), parsed)
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %v", path, err)
+	}
+
+	if data, err = json.Marshal(obj); err != nil {
+		return err
+	}
+	// unknown fields are rejected, so that a misspelled path is not ignored
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	newSpec := batch.JobSpec{}
+	if err := decoder.Decode(&newSpec); err != nil {
+		return fmt.Errorf("invalid value %s of %s: %v", value, path, err)
+	}
+	*spec = newSpec
+	return nil
+}
+
+// setPathValue sets the value at the path of keys under the node, creating the missing objects,
+// and returns the updated node.
+func setPathValue(node interface{}, keys []string, value interface{}) (interface{}, error) {
+	if len(keys) == 0 {
+		return value, nil
+	}
+	key := keys[0]
+	if key == "" {
+		return nil, fmt.Errorf("empty field name")
+	}
+
+	switch n := node.(type) {
+	case nil:
+		child, err := setPathValue(nil, keys[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{key: child}, nil
+	case map[string]interface{}:
+		child, err := setPathValue(n[key], keys[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[key] = child
+		return n, nil
+	case []interface{}:
+		index, err := listIndex(n, key)
+		if err != nil {
+			return nil, err
+		}
+		child, err := setPathValue(n[index], keys[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = child
+		return n, nil
+	default:
+		return nil, fmt.Errorf("can not set field %s of a %T value", key, node)
+	}
+}
+
+// listIndex returns the index of the element of the list named key, or at the index key.
+func listIndex(list []interface{}, key string) (int, error) {
+	for i, item := range list {
+		if element, ok := item.(map[string]interface{}); ok && element["name"] == key {
+			return i, nil
+		}
+	}
+	if index, err := strconv.Atoi(key); err == nil && index >= 0 && index < len(list) {
+		return index, nil
+	}
+	return -1, fmt.Errorf("no element named %s", key)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func newOverridesJobSpec() batch.JobSpec {
+	return batch.JobSpec{
+		Queue:        "default",
+		MinAvailable: 2,
+		Tasks: []batch.TaskSpec{
+			{
+				Name:     "ps",
+				Replicas: 1,
+				Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "ps", Image: "train:v1"}},
+				}},
+			},
+			{
+				Name:     "worker",
+				Replicas: 4,
+				Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "worker", Image: "train:v1"}},
+				}},
+			},
+		},
+	}
+}
+
+func TestSetJobSpecValue(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Path        string
+		Value       string
+		ExpectError bool
+		Check       func(spec batch.JobSpec) bool
+	}{
+		{
+			Name:  "replicas of task by name",
+			Path:  "tasks.worker.replicas",
+			Value: "16",
+			Check: func(spec batch.JobSpec) bool {
+				return spec.Tasks[1].Replicas == 16 && spec.Tasks[0].Replicas == 1
+			},
+		},
+		{
+			Name:  "image of container by name",
+			Path:  "tasks.ps.template.spec.containers.ps.image",
+			Value: "train:v2",
+			Check: func(spec batch.JobSpec) bool {
+				return spec.Tasks[0].Template.Spec.Containers[0].Image == "train:v2" &&
+					spec.Tasks[1].Template.Spec.Containers[0].Image == "train:v1"
+			},
+		},
+		{
+			Name:  "task by index",
+			Path:  "tasks.1.replicas",
+			Value: "8",
+			Check: func(spec batch.JobSpec) bool {
+				return spec.Tasks[1].Replicas == 8
+			},
+		},
+		{
+			Name:  "spec prefix",
+			Path:  "spec.queue",
+			Value: "big",
+			Check: func(spec batch.JobSpec) bool {
+				return spec.Queue == "big" && spec.MinAvailable == 2
+			},
+		},
+		{
+			Name:  "missing object is created",
+			Path:  "tasks.worker.template.spec.nodeSelector.zone",
+			Value: "a",
+			Check: func(spec batch.JobSpec) bool {
+				return spec.Tasks[1].Template.Spec.NodeSelector["zone"] == "a"
+			},
+		},
+		{
+			Name:        "unknown field",
+			Path:        "tasks.worker.replica",
+			Value:       "16",
+			ExpectError: true,
+		},
+		{
+			Name:        "invalid value",
+			Path:        "tasks.worker.replicas",
+			Value:       "many",
+			ExpectError: true,
+		},
+		{
+			Name:        "missing task",
+			Path:        "tasks.chief.replicas",
+			Value:       "1",
+			ExpectError: true,
+		},
+		{
+			Name:        "field of a scalar",
+			Path:        "queue.name",
+			Value:       "big",
+			ExpectError: true,
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			spec := newOverridesJobSpec()
+			err := SetJobSpecValue(&spec, testcase.Path, testcase.Value)
+			if (err != nil) != testcase.ExpectError {
+				t.Fatalf("expected error: %v, got %v", testcase.ExpectError, err)
+			}
+			if err != nil {
+				if spec.Tasks[1].Replicas != 4 || spec.Queue != "default" {
+					t.Errorf("expected spec unchanged on error, got %v", spec)
+				}
+				return
+			}
+			if !testcase.Check(spec) {
+				t.Errorf("unexpected spec %v", spec)
+			}
+		})
+	}
+}
+
+func TestSetRerunOverrides(t *testing.T) {
+	job := &batch.Job{Spec: newOverridesJobSpec()}
+	overrides := map[string]string{
+		"tasks.worker.replicas": "16",
+		"queue":                 "big",
+	}
+	if err := SetRerunOverrides(job, overrides); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Spec.Tasks[1].Replicas != 16 || job.Spec.Queue != "big" {
+		t.Errorf("overrides not applied: %v", job.Spec)
+	}
+	expected := `{"queue":"big","tasks.worker.replicas":"16"}`
+	if job.Annotations[RerunOverridesKey] != expected {
+		t.Errorf("expected annotation %s, got %s", expected, job.Annotations[RerunOverridesKey])
+	}
+
+	job = &batch.Job{Spec: newOverridesJobSpec()}
+	if err := SetRerunOverrides(job, nil); err != nil || job.Annotations[RerunOverridesKey] != "" {
+		t.Errorf("expected no annotation without overrides, got %v, %v", job.Annotations, err)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/features"
+)
+
+const (
+	// GrowAfterStartKey is the job annotation making the job start once minAvailable pods run
+	// and grow to its full replicas as the remaining pods are scheduled. The peer lists of the
+	// plugins only list the replicas which joined, i.e. whose pods are running, and are
+	// regenerated as the replicas join.
+	GrowAfterStartKey = "volcano.sh/grow-after-start"
+	// ParallelismKey is the job annotation recording the current and the desired parallelism
+	// of a job which grows after start, as a JSON object.
+	ParallelismKey = "volcano.sh/parallelism"
+)
+
+// Parallelism is the number of running replicas of a job and of each of its tasks,
+// against the number of replicas they should grow to.
+type Parallelism struct {
+	Current int32                      `json:"current"`
+	Desired int32                      `json:"desired"`
+	Tasks   map[string]TaskParallelism `json:"tasks,omitempty"`
+}
+
+// TaskParallelism is the number of running replicas of a task against its replicas.
+type TaskParallelism struct {
+	Current int32 `json:"current"`
+	Desired int32 `json:"desired"`
+}
+
+// IsGrowAfterStart returns whether the job grows to its full replicas after it starts, always false
+// with the GrowAfterStart feature disabled.
+func IsGrowAfterStart(job *batch.Job) bool {
+	value, found := job.Annotations[GrowAfterStartKey]
+	if !found || !utilfeature.DefaultFeatureGate.Enabled(features.GrowAfterStart) {
+		return false
+	}
+	grow, err := strconv.ParseBool(value)
+	if err != nil {
+		klog.Warningf("Invalid %s <%s> of job <%s/%s>", GrowAfterStartKey, value, job.Namespace, job.Name)
+		return false
+	}
+	return grow
+}
+
+// GetPeerIndexes returns the indexes of the replicas of the task listed in the peer lists of the
+// job: all of them, or only the ones whose pods are running if the job grows after start.
+func GetPeerIndexes(job *batch.Job, ts batch.TaskSpec) []int {
+	var replicaStatus map[string]ReplicaStatus
+	grow := IsGrowAfterStart(job)
+	if grow {
+		replicaStatus = GetReplicaStatus(job)[ts.Name]
+	}
+
+	indexes := make([]int, 0, ts.Replicas)
+	for i := 0; i < int(ts.Replicas); i++ {
+		if grow && replicaStatus[strconv.Itoa(i)].Phase != v1.PodRunning {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// ComputeParallelism counts the running replicas of the job from its replica status.
+func ComputeParallelism(job *batch.Job) Parallelism {
+	replicaStatus := GetReplicaStatus(job)
+	parallelism := Parallelism{Tasks: map[string]TaskParallelism{}}
+	for _, ts := range job.Spec.Tasks {
+		task := TaskParallelism{Desired: ts.Replicas}
+		for index, status := range replicaStatus[ts.Name] {
+			if i, err := strconv.Atoi(index); err == nil && i < int(ts.Replicas) && status.Phase == v1.PodRunning {
+				task.Current++
+			}
+		}
+		parallelism.Tasks[ts.Name] = task
+		parallelism.Current += task.Current
+		parallelism.Desired += task.Desired
+	}
+	return parallelism
+}
+
+// GetParallelism returns the parallelism recorded on the job.
+func GetParallelism(job *batch.Job) (Parallelism, bool) {
+	value, found := job.Annotations[ParallelismKey]
+	if !found {
+		return Parallelism{}, false
+	}
+
+	parallelism := Parallelism{}
+	if err := json.Unmarshal([]byte(value), &parallelism); err != nil {
+		klog.Warningf("Failed to parse annotation %s of job <%s/%s>: %v", ParallelismKey, job.Namespace, job.Name, err)
+		return Parallelism{}, false
+	}
+	return parallelism, true
+}
+
+// SetParallelism records the parallelism on the job, it returns whether the annotation is changed.
+func SetParallelism(job *batch.Job, parallelism Parallelism) bool {
+	value, _ := json.Marshal(parallelism)
+	if job.Annotations[ParallelismKey] == string(value) {
+		return false
+	}
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations[ParallelismKey] = string(value)
+	return true
+}
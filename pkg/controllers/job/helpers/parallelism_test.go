@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/features"
+)
+
+func newGrowingJob(grow string) *batch.Job {
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default", Annotations: map[string]string{}},
+		Spec: batch.JobSpec{
+			Tasks: []batch.TaskSpec{
+				{Name: "master", Replicas: 1},
+				{Name: "worker", Replicas: 4},
+			},
+		},
+	}
+	if grow != "" {
+		job.Annotations[GrowAfterStartKey] = grow
+	}
+	SetReplicaStatus(job, map[string]map[string]ReplicaStatus{
+		"master": {"0": {Phase: v1.PodRunning}},
+		"worker": {"0": {Phase: v1.PodRunning}, "1": {Phase: v1.PodPending}, "3": {Phase: v1.PodRunning}},
+	})
+	return job
+}
+
+func TestIsGrowAfterStart(t *testing.T) {
+	testCases := []struct {
+		name     string
+		grow     string
+		disabled bool
+		expected bool
+	}{
+		{name: "not annotated", expected: false},
+		{name: "annotated", grow: "true", expected: true},
+		{name: "annotated with false", grow: "false", expected: false},
+		{name: "invalid annotation", grow: "yes", expected: false},
+		{name: "feature disabled", grow: "true", disabled: true, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.disabled {
+				defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.GrowAfterStart, false)()
+			}
+			if got := IsGrowAfterStart(newGrowingJob(tc.grow)); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetPeerIndexes(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Grow     string
+		Expected []int
+	}{
+		{
+			Name:     "all the replicas are peers",
+			Expected: []int{0, 1, 2, 3},
+		},
+		{
+			Name:     "only the running replicas are peers of a growing job",
+			Grow:     "true",
+			Expected: []int{0, 3},
+		},
+		{
+			Name:     "invalid annotation is ignored",
+			Grow:     "yes",
+			Expected: []int{0, 1, 2, 3},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			job := newGrowingJob(testCase.Grow)
+			if got := GetPeerIndexes(job, job.Spec.Tasks[1]); !reflect.DeepEqual(got, testCase.Expected) {
+				t.Errorf("expected %v, got %v", testCase.Expected, got)
+			}
+		})
+	}
+}
+
+func TestComputeParallelism(t *testing.T) {
+	job := newGrowingJob("true")
+	expected := Parallelism{
+		Current: 3,
+		Desired: 5,
+		Tasks: map[string]TaskParallelism{
+			"master": {Current: 1, Desired: 1},
+			"worker": {Current: 2, Desired: 4},
+		},
+	}
+	parallelism := ComputeParallelism(job)
+	if !reflect.DeepEqual(parallelism, expected) {
+		t.Errorf("expected %v, got %v", expected, parallelism)
+	}
+
+	if !SetParallelism(job, parallelism) {
+		t.Errorf("expected the annotation to be changed")
+	}
+	if SetParallelism(job, parallelism) {
+		t.Errorf("expected the annotation not to be changed again")
+	}
+	if got, found := GetParallelism(job); !found || !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
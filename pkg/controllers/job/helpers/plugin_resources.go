@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+)
+
+// PluginLabelKey is the label of the resources created by the job plugins, e.g. the ssh Secret or
+// the svc ConfigMap and Service, naming the plugin. Together with the job-name label it lets the
+// garbage collector sweep the resources whose job is gone.
+const PluginLabelKey = "volcano.sh/job-plugin"
+
+// sshConfigKey is the entry of the ssh config in the secret of the ssh plugin.
+const sshConfigKey = "config"
+
+// PluginResourceLabels returns the labels of a resource created by the plugin for the job.
+func PluginResourceLabels(job *batch.Job, plugin string) map[string]string {
+	return map[string]string{
+		PluginLabelKey:   plugin,
+		batch.JobNameKey: job.Name,
+	}
+}
+
+// labelPluginResource adds the plugin labels to the object, it returns whether the object is changed.
+func labelPluginResource(job *batch.Job, plugin string, obj metav1.Object) bool {
+	labels := obj.GetLabels()
+	changed := false
+	for key, value := range PluginResourceLabels(job, plugin) {
+		if labels[key] == value {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+		changed = true
+	}
+	if changed {
+		obj.SetLabels(labels)
+	}
+	return changed
+}
+
+// CreateOrUpdatePluginSecret creates the secret of the plugin for the job, labeled with PluginResourceLabels,
+// or updates its data and its labels, e.g. of a secret created before the labels, in a single update. As the
+// generated keys differ on each call, the data is only updated when its ssh config changes, so that the keys
+// of the running pods are kept.
+func CreateOrUpdatePluginSecret(job *batch.Job, kubeClients kubernetes.Interface, plugin string, data map[string][]byte, secretName string) error {
+	secret, err := kubeClients.CoreV1().Secrets(job.Namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.V(3).Infof("Failed to get Secret <%s/%s> of Job %s: %v",
+				job.Namespace, secretName, job.Name, err)
+			return err
+		}
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: job.Namespace,
+				Name:      secretName,
+				Labels:    PluginResourceLabels(job, plugin),
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(job, helpers.JobKind),
+				},
+			},
+			Data: data,
+		}
+		if _, err := kubeClients.CoreV1().Secrets(job.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			klog.V(3).Infof("Failed to create Secret <%s/%s> of Job %s: %v",
+				job.Namespace, secretName, job.Name, err)
+			return err
+		}
+		return nil
+	}
+
+	changed := labelPluginResource(job, plugin, secret)
+	if !reflect.DeepEqual(secret.Data[sshConfigKey], data[sshConfigKey]) {
+		secret.Data = data
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if _, err := kubeClients.CoreV1().Secrets(job.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		klog.V(3).Infof("Failed to update Secret <%s/%s> of Job %s: %v",
+			job.Namespace, secretName, job.Name, err)
+		return err
+	}
+	return nil
+}
+
+// CreateOrUpdatePluginConfigMap creates the configmap of the plugin for the job, labeled with PluginResourceLabels,
+// or updates its data and its labels, e.g. of a configmap created before the labels, in a single update.
+func CreateOrUpdatePluginConfigMap(job *batch.Job, kubeClients kubernetes.Interface, plugin string, data map[string]string, cmName string) error {
+	cm, err := kubeClients.CoreV1().ConfigMaps(job.Namespace).Get(context.TODO(), cmName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.V(3).Infof("Failed to get ConfigMap <%s/%s> of Job %s: %v",
+				job.Namespace, cmName, job.Name, err)
+			return err
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: job.Namespace,
+				Name:      cmName,
+				Labels:    PluginResourceLabels(job, plugin),
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(job, helpers.JobKind),
+				},
+			},
+			Data: data,
+		}
+		if _, err := kubeClients.CoreV1().ConfigMaps(job.Namespace).Create(context.TODO(), cm, metav1.CreateOptions{}); err != nil {
+			klog.V(3).Infof("Failed to create ConfigMap <%s/%s> of Job %s: %v",
+				job.Namespace, cmName, job.Name, err)
+			return err
+		}
+		return nil
+	}
+
+	changed := labelPluginResource(job, plugin, cm)
+	if !reflect.DeepEqual(cm.Data, data) {
+		cm.Data = data
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if _, err := kubeClients.CoreV1().ConfigMaps(job.Namespace).Update(context.TODO(), cm, metav1.UpdateOptions{}); err != nil {
+		klog.V(3).Infof("Failed to update ConfigMap <%s/%s> of Job %s: %v",
+			job.Namespace, cmName, job.Name, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// MetadataPropagation is the allowlist of job labels and annotations stamped onto
+// the pods and the resources the controller creates for the job, e.g. a cost center
+// or an experiment ID used for chargeback and log correlation. An entry ending with
+// "*" matches all the keys with that prefix.
+type MetadataPropagation struct {
+	Labels      []string
+	Annotations []string
+}
+
+// IsEmpty returns whether nothing is propagated.
+func (p MetadataPropagation) IsEmpty() bool {
+	return len(p.Labels) == 0 && len(p.Annotations) == 0
+}
+
+// Apply copies the allowlisted labels and annotations of the job onto the object,
+// keys already set on the object are kept. It returns whether the object is changed.
+func (p MetadataPropagation) Apply(job *batch.Job, obj metav1.Object) bool {
+	labels, labelsChanged := propagate(p.Labels, job.Labels, obj.GetLabels())
+	if labelsChanged {
+		obj.SetLabels(labels)
+	}
+	annotations, annotationsChanged := propagate(p.Annotations, job.Annotations, obj.GetAnnotations())
+	if annotationsChanged {
+		obj.SetAnnotations(annotations)
+	}
+	return labelsChanged || annotationsChanged
+}
+
+func propagate(allowlist []string, from, to map[string]string) (map[string]string, bool) {
+	changed := false
+	for key, value := range from {
+		if !matchAllowlist(allowlist, key) {
+			continue
+		}
+		if _, found := to[key]; found {
+			continue
+		}
+		if to == nil {
+			to = make(map[string]string)
+		}
+		to[key] = value
+		changed = true
+	}
+	return to, changed
+}
+
+func matchAllowlist(allowlist []string, key string) bool {
+	for _, entry := range allowlist {
+		if prefix, found := strings.CutSuffix(entry, "*"); found {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if entry == key {
+			return true
+		}
+	}
+	return false
+}
+
+// PropagateToSecret stamps the allowlisted job metadata onto the secret created for the job.
+func PropagateToSecret(job *batch.Job, kubeClients kubernetes.Interface, p MetadataPropagation, secretName string) error {
+	if p.IsEmpty() {
+		return nil
+	}
+	secret, err := kubeClients.CoreV1().Secrets(job.Namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !p.Apply(job, secret) {
+		return nil
+	}
+	if _, err := kubeClients.CoreV1().Secrets(job.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		klog.V(3).Infof("Failed to propagate metadata to Secret <%s/%s> of Job %s: %v",
+			job.Namespace, secretName, job.Name, err)
+		return err
+	}
+	return nil
+}
+
+// PropagateToConfigMap stamps the allowlisted job metadata onto the configmap created for the job.
+func PropagateToConfigMap(job *batch.Job, kubeClients kubernetes.Interface, p MetadataPropagation, cmName string) error {
+	if p.IsEmpty() {
+		return nil
+	}
+	cm, err := kubeClients.CoreV1().ConfigMaps(job.Namespace).Get(context.TODO(), cmName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !p.Apply(job, cm) {
+		return nil
+	}
+	if _, err := kubeClients.CoreV1().ConfigMaps(job.Namespace).Update(context.TODO(), cm, metav1.UpdateOptions{}); err != nil {
+		klog.V(3).Infof("Failed to propagate metadata to ConfigMap <%s/%s> of Job %s: %v",
+			job.Namespace, cmName, job.Name, err)
+		return err
+	}
+	return nil
+}
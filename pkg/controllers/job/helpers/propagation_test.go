@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestMetadataPropagationApply(t *testing.T) {
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job1",
+			Labels:      map[string]string{"cost-center": "ml", "example.com/team": "vision", "app": "train"},
+			Annotations: map[string]string{"experiment-id": "exp-42", "note": "internal"},
+		},
+	}
+
+	testcases := []struct {
+		Name                string
+		Propagation         MetadataPropagation
+		Pod                 *v1.Pod
+		ExpectChanged       bool
+		ExpectedLabels      map[string]string
+		ExpectedAnnotations map[string]string
+	}{
+		{
+			Name:        "nothing is propagated by default",
+			Propagation: MetadataPropagation{},
+			Pod:         &v1.Pod{},
+		},
+		{
+			Name: "allowlisted keys and prefixes are propagated",
+			Propagation: MetadataPropagation{
+				Labels:      []string{"cost-center", "example.com/*"},
+				Annotations: []string{"experiment-id"},
+			},
+			Pod:                 &v1.Pod{},
+			ExpectChanged:       true,
+			ExpectedLabels:      map[string]string{"cost-center": "ml", "example.com/team": "vision"},
+			ExpectedAnnotations: map[string]string{"experiment-id": "exp-42"},
+		},
+		{
+			Name:        "keys of the object are kept",
+			Propagation: MetadataPropagation{Labels: []string{"cost-center", "example.com/*"}},
+			Pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"cost-center": "infra"},
+			}},
+			ExpectChanged:  true,
+			ExpectedLabels: map[string]string{"cost-center": "infra", "example.com/team": "vision"},
+		},
+		{
+			Name:        "no change if already propagated",
+			Propagation: MetadataPropagation{Labels: []string{"cost-center"}},
+			Pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"cost-center": "ml"},
+			}},
+			ExpectedLabels: map[string]string{"cost-center": "ml"},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			changed := testcase.Propagation.Apply(job, testcase.Pod)
+			if changed != testcase.ExpectChanged {
+				t.Errorf("expected changed %v, but got %v", testcase.ExpectChanged, changed)
+			}
+			if !reflect.DeepEqual(testcase.Pod.Labels, testcase.ExpectedLabels) {
+				t.Errorf("expected labels %v, but got %v", testcase.ExpectedLabels, testcase.Pod.Labels)
+			}
+			if !reflect.DeepEqual(testcase.Pod.Annotations, testcase.ExpectedAnnotations) {
+				t.Errorf("expected annotations %v, but got %v", testcase.ExpectedAnnotations, testcase.Pod.Annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+)
+
+const (
+	// TemplateParametersKey is the JobTemplate annotation declaring the parameters of the template
+	// as a JSON list, e.g. [{"name":"image","required":true},{"name":"epochs","default":"10"}].
+	// A parameter is referenced as ${name} in any string field of the template spec.
+	TemplateParametersKey = "volcano.sh/template-parameters"
+	// FromTemplateKey is the job annotation naming the JobTemplate the job was expanded from.
+	FromTemplateKey = "volcano.sh/from-template"
+	// TemplateValuesKey is the job annotation recording the parameter values, as a JSON object,
+	// used to expand the job from its template.
+	TemplateValuesKey = "volcano.sh/template-values"
+	// FlowTemplateValuesKey is the JobFlow annotation holding the parameter values of each flow
+	// as a JSON object keyed by flow name, e.g. {"train":{"epochs":"20"}}.
+	FlowTemplateValuesKey = "volcano.sh/flow-template-values"
+)
+
+var (
+	templateParameterName        = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	templateParameterPlaceholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// TemplateParameter is a parameter declared by a JobTemplate.
+type TemplateParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"`
+}
+
+// GetTemplateParameters returns the parameters declared by the JobTemplate.
+func GetTemplateParameters(template *flow.JobTemplate) ([]TemplateParameter, error) {
+	value, found := template.Annotations[TemplateParametersKey]
+	if !found {
+		return nil, nil
+	}
+
+	var params []TemplateParameter
+	if err := json.Unmarshal([]byte(value), &params); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s of jobTemplate <%s/%s>: %v",
+			TemplateParametersKey, template.Namespace, template.Name, err)
+	}
+
+	names := map[string]bool{}
+	for _, param := range params {
+		if !templateParameterName.MatchString(param.Name) {
+			return nil, fmt.Errorf("invalid parameter name <%s> of jobTemplate <%s/%s>",
+				param.Name, template.Namespace, template.Name)
+		}
+		if names[param.Name] {
+			return nil, fmt.Errorf("duplicated parameter <%s> of jobTemplate <%s/%s>",
+				param.Name, template.Namespace, template.Name)
+		}
+		names[param.Name] = true
+	}
+	return params, nil
+}
+
+// ResolveTemplateParameters checks the values against the declared parameters, filling in
+// the defaults of optional parameters. Unknown parameters and missing required ones are errors.
+func ResolveTemplateParameters(params []TemplateParameter, values map[string]string) (map[string]string, error) {
+	declared := map[string]bool{}
+	resolved := map[string]string{}
+	var missing []string
+	for _, param := range params {
+		declared[param.Name] = true
+		if value, found := values[param.Name]; found {
+			resolved[param.Name] = value
+			continue
+		}
+		if param.Required {
+			missing = append(missing, param.Name)
+			continue
+		}
+		resolved[param.Name] = param.Default
+	}
+
+	var unknown []string
+	for name := range values {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("missing required parameters %v", missing)
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown parameters %v", unknown)
+	}
+	return resolved, nil
+}
+
+// ExpandJobTemplate returns the spec of the JobTemplate with its parameters substituted by
+// the given values, together with the resolved values of all the parameters.
+func ExpandJobTemplate(template *flow.JobTemplate, values map[string]string) (*batch.JobSpec, map[string]string, error) {
+	params, err := GetTemplateParameters(template)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(params) == 0 && len(values) == 0 {
+		return template.Spec.DeepCopy(), nil, nil
+	}
+	resolved, err := ResolveTemplateParameters(params, values)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jobTemplate <%s/%s>: %v", template.Namespace, template.Name, err)
+	}
+
+	data, err := json.Marshal(template.Spec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// placeholders of undeclared parameters are kept, e.g. shell variables in container commands
+	expanded := templateParameterPlaceholder.ReplaceAllFunc(data, func(placeholder []byte) []byte {
+		value, found := resolved[string(placeholder[2:len(placeholder)-1])]
+		if !found {
+			return placeholder
+		}
+		// the placeholder is within a JSON string, so the value is escaped without its quotes
+		quoted, _ := json.Marshal(value)
+		return quoted[1 : len(quoted)-1]
+	})
+
+	spec := &batch.JobSpec{}
+	if err := json.Unmarshal(expanded, spec); err != nil {
+		return nil, nil, err
+	}
+	return spec, resolved, nil
+}
+
+// GetTemplatePlaceholders returns the declared parameters which are still referenced by the job spec.
+func GetTemplatePlaceholders(spec *batch.JobSpec, params []TemplateParameter) []string {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil
+	}
+
+	declared := map[string]bool{}
+	for _, param := range params {
+		declared[param.Name] = true
+	}
+	names := map[string]bool{}
+	for _, match := range templateParameterPlaceholder.FindAllSubmatch(data, -1) {
+		if name := string(match[1]); declared[name] {
+			names[name] = true
+		}
+	}
+	var result []string
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GetTemplateValues returns the parameter values recorded on the job annotations.
+func GetTemplateValues(annotations map[string]string) (map[string]string, error) {
+	values := map[string]string{}
+	value, found := annotations[TemplateValuesKey]
+	if !found {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %v", TemplateValuesKey, err)
+	}
+	return values, nil
+}
+
+// SetTemplateValues records the template the job was expanded from and the parameter values on the job.
+func SetTemplateValues(job *batch.Job, templateName string, values map[string]string) {
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	if values == nil {
+		values = map[string]string{}
+	}
+	data, _ := json.Marshal(values)
+	job.Annotations[FromTemplateKey] = templateName
+	job.Annotations[TemplateValuesKey] = string(data)
+}
+
+// GetFlowTemplateValues returns the parameter values of the flow declared on the JobFlow.
+func GetFlowTemplateValues(jobFlow *flow.JobFlow, flowName string) (map[string]string, error) {
+	values, err := GetAllFlowTemplateValues(jobFlow)
+	if err != nil {
+		return nil, err
+	}
+	return values[flowName], nil
+}
+
+// GetAllFlowTemplateValues returns the parameter values of all the flows declared on the JobFlow, keyed by flow name.
+func GetAllFlowTemplateValues(jobFlow *flow.JobFlow) (map[string]map[string]string, error) {
+	value, found := jobFlow.Annotations[FlowTemplateValuesKey]
+	if !found {
+		return nil, nil
+	}
+
+	values := map[string]map[string]string{}
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s of jobFlow <%s/%s>: %v",
+			FlowTemplateValuesKey, jobFlow.Namespace, jobFlow.Name, err)
+	}
+	return values, nil
+}
+
+// ParseTemplateValues parses parameter values given as name=value pairs.
+func ParseTemplateValues(pairs []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid parameter <%s>, expected name=value", pair)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
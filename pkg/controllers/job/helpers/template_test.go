@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+)
+
+func TestExpandJobTemplate(t *testing.T) {
+	newTemplate := func(params string) *flow.JobTemplate {
+		template := &flow.JobTemplate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "train"},
+			Spec: batch.JobSpec{
+				Queue: "${queue}",
+				Tasks: []batch.TaskSpec{{
+					Name:     "worker",
+					Replicas: 2,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{{
+								Name:    "worker",
+								Image:   "${image}",
+								Command: []string{"sh", "-c", "train --epochs=${epochs} --home=${HOME}"},
+							}},
+						},
+					},
+				}},
+			},
+		}
+		if params != "" {
+			template.Annotations = map[string]string{TemplateParametersKey: params}
+		}
+		return template
+	}
+	params := `[{"name":"image","required":true},{"name":"queue","default":"default"},{"name":"epochs","default":"10"}]`
+
+	testCases := []struct {
+		Name           string
+		Template       *flow.JobTemplate
+		Values         map[string]string
+		ExpectQueue    string
+		ExpectImage    string
+		ExpectCommand  string
+		ExpectResolved map[string]string
+		ExpectErr      bool
+	}{
+		{
+			Name:           "defaults of optional parameters",
+			Template:       newTemplate(params),
+			Values:         map[string]string{"image": "busybox"},
+			ExpectQueue:    "default",
+			ExpectImage:    "busybox",
+			ExpectCommand:  "train --epochs=10 --home=${HOME}",
+			ExpectResolved: map[string]string{"image": "busybox", "queue": "default", "epochs": "10"},
+		},
+		{
+			Name:           "values with quotes are escaped",
+			Template:       newTemplate(params),
+			Values:         map[string]string{"image": "busybox", "queue": "q1", "epochs": `"20"`},
+			ExpectQueue:    "q1",
+			ExpectImage:    "busybox",
+			ExpectCommand:  `train --epochs="20" --home=${HOME}`,
+			ExpectResolved: map[string]string{"image": "busybox", "queue": "q1", "epochs": `"20"`},
+		},
+		{
+			Name:          "template without parameters is kept",
+			Template:      newTemplate(""),
+			ExpectQueue:   "${queue}",
+			ExpectImage:   "${image}",
+			ExpectCommand: "train --epochs=${epochs} --home=${HOME}",
+		},
+		{
+			Name:      "missing required parameter",
+			Template:  newTemplate(params),
+			Values:    map[string]string{"queue": "q1"},
+			ExpectErr: true,
+		},
+		{
+			Name:      "unknown parameter",
+			Template:  newTemplate(params),
+			Values:    map[string]string{"image": "busybox", "replicas": "3"},
+			ExpectErr: true,
+		},
+		{
+			Name:      "duplicated parameter",
+			Template:  newTemplate(`[{"name":"image"},{"name":"image"}]`),
+			ExpectErr: true,
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			spec, resolved, err := ExpandJobTemplate(testcase.Template, testcase.Values)
+			if (err != nil) != testcase.ExpectErr {
+				t.Fatalf("expected error: %v, got %v", testcase.ExpectErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if spec.Queue != testcase.ExpectQueue {
+				t.Errorf("expected queue %s, got %s", testcase.ExpectQueue, spec.Queue)
+			}
+			container := spec.Tasks[0].Template.Spec.Containers[0]
+			if container.Image != testcase.ExpectImage {
+				t.Errorf("expected image %s, got %s", testcase.ExpectImage, container.Image)
+			}
+			if container.Command[2] != testcase.ExpectCommand {
+				t.Errorf("expected command %s, got %s", testcase.ExpectCommand, container.Command[2])
+			}
+			if spec.Tasks[0].Replicas != 2 {
+				t.Errorf("expected 2 replicas, got %d", spec.Tasks[0].Replicas)
+			}
+			if !reflect.DeepEqual(resolved, testcase.ExpectResolved) {
+				t.Errorf("expected resolved values %v, got %v", testcase.ExpectResolved, resolved)
+			}
+			if placeholders := GetTemplatePlaceholders(spec, []TemplateParameter{{Name: "image"}}); testcase.Values != nil && len(placeholders) != 0 {
+				t.Errorf("expected no placeholders left, got %v", placeholders)
+			}
+		})
+	}
+}
+
+func TestParseTemplateValues(t *testing.T) {
+	values, err := ParseTemplateValues([]string{"image=busybox", "args=--lr=0.1", "empty="})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{"image": "busybox", "args": "--lr=0.1", "empty": ""}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	if _, err := ParseTemplateValues([]string{"image"}); err == nil {
+		t.Errorf("expected error for a parameter without value")
+	}
+}
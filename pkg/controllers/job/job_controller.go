@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -27,10 +28,12 @@ import (
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	policyinformers "k8s.io/client-go/informers/policy/v1"
 	kubeschedulinginformers "k8s.io/client-go/informers/scheduling/v1"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
 	kubeschedulinglisters "k8s.io/client-go/listers/scheduling/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -51,11 +54,17 @@ import (
 
 	"volcano.sh/volcano/pkg/controllers/apis"
 	jobcache "volcano.sh/volcano/pkg/controllers/cache"
+	"volcano.sh/volcano/pkg/controllers/events"
 	"volcano.sh/volcano/pkg/controllers/framework"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/notification"
 	"volcano.sh/volcano/pkg/controllers/job/state"
 	"volcano.sh/volcano/pkg/features"
+	"volcano.sh/volcano/pkg/util/logging"
 )
 
+var logger = logging.Logger("job-controller")
+
 func init() {
 	framework.RegisterController(&jobcontroller{})
 }
@@ -73,6 +82,7 @@ type jobcontroller struct {
 	cmdInformer   businformer.CommandInformer
 	pcInformer    kubeschedulinginformers.PriorityClassInformer
 	queueInformer schedulinginformers.QueueInformer
+	pdbInformer   policyinformers.PodDisruptionBudgetInformer
 
 	informerFactory   informers.SharedInformerFactory
 	vcInformerFactory vcinformer.SharedInformerFactory
@@ -105,12 +115,34 @@ type jobcontroller struct {
 	queueLister schedulinglisters.QueueLister
 	queueSynced func() bool
 
+	// A store of pod disruption budgets
+	pdbLister policylisters.PodDisruptionBudgetLister
+
+	// notifier sends job events to external systems, nil if not configured
+	notifier notification.Notifier
+
+	// propagation is the allowlist of job labels and annotations stamped onto the pods and resources of the job
+	propagation jobhelpers.MetadataPropagation
+
+	// eventInformerFactory watches the warning events of the pods, which eventAggregator collapses into
+	// per-task events of the jobs every eventAggregationPeriod; it is nil if the aggregation is disabled
+	eventInformerFactory   informers.SharedInformerFactory
+	eventAggregator        *podEventAggregator
+	eventAggregationPeriod time.Duration
+
+	// accounting holds the resource-hours of the finished pods, recorded in the status of their jobs every
+	// accountingPeriod; it is nil if the accounting is disabled
+	accounting       *jobAccounting
+	accountingPeriod time.Duration
+
 	// queue that need to sync up
 	queueList    []workqueue.RateLimitingInterface
 	commandQueue workqueue.RateLimitingInterface
 	cache        jobcache.Cache
 	// Job Event recorder
 	recorder record.EventRecorder
+	// eventBroadcaster sends the events of the recorder, it is flushed when the controller stops
+	eventBroadcaster record.EventBroadcaster
 
 	errTasks      workqueue.RateLimitingInterface
 	workers       uint32
@@ -127,7 +159,7 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 	cc.vcClient = opt.VolcanoClient
 
 	sharedInformers := opt.SharedInformerFactory
-	workers := opt.WorkerNum
+	workers := opt.GetWorkers(opt.WorkerNum)
 	// Initialize event client
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(klog.Infof)
@@ -136,38 +168,45 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 
 	cc.informerFactory = sharedInformers
 	cc.queueList = make([]workqueue.RateLimitingInterface, workers)
-	cc.commandQueue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	cc.commandQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "job-command")
 	cc.cache = jobcache.New()
 	cc.errTasks = newRateLimitingQueue()
 	cc.recorder = recorder
+	cc.eventBroadcaster = eventBroadcaster
 	cc.workers = workers
 	cc.maxRequeueNum = opt.MaxRequeueNum
 	if cc.maxRequeueNum < 0 {
 		cc.maxRequeueNum = -1
 	}
+	cc.notifier = notification.NewWebhookNotifier(opt.JobNotificationURLs, opt.JobNotificationTimeout)
+	cc.propagation = jobhelpers.MetadataPropagation{
+		Labels:      opt.PropagatedJobLabels,
+		Annotations: opt.PropagatedJobAnnotations,
+	}
 
 	var i uint32
 	for i = 0; i < workers; i++ {
-		cc.queueList[i] = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		cc.queueList[i] = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), fmt.Sprintf("job-%d", i))
 	}
 
 	factory := opt.VCSharedInformerFactory
 	cc.vcInformerFactory = factory
 	if utilfeature.DefaultFeatureGate.Enabled(features.WorkLoadSupport) {
 		cc.jobInformer = factory.Batch().V1alpha1().Jobs()
-		cc.jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		cc.jobInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc:    cc.addJob,
 			UpdateFunc: cc.updateJob,
 			DeleteFunc: cc.deleteJob,
-		})
+		}), opt.ResyncPeriod)
 		cc.jobLister = cc.jobInformer.Lister()
 		cc.jobSynced = cc.jobInformer.Informer().HasSynced
+		registerJobCollector(cc.jobLister)
 	}
 
 	if utilfeature.DefaultFeatureGate.Enabled(features.QueueCommandSync) {
 		cc.cmdInformer = factory.Bus().V1alpha1().Commands()
-		cc.cmdInformer.Informer().AddEventHandler(
-			cache.FilteringResourceEventHandler{
+		cc.cmdInformer.Informer().AddEventHandlerWithResyncPeriod(
+			opt.Namespaces.FilterHandler(cache.FilteringResourceEventHandler{
 				FilterFunc: func(obj interface{}) bool {
 					switch v := obj.(type) {
 					case *busv1alpha1.Command:
@@ -185,18 +224,19 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 				Handler: cache.ResourceEventHandlerFuncs{
 					AddFunc: cc.addCommand,
 				},
-			},
+			}),
+			opt.ResyncPeriod,
 		)
 		cc.cmdLister = cc.cmdInformer.Lister()
 		cc.cmdSynced = cc.cmdInformer.Informer().HasSynced
 	}
 
 	cc.podInformer = sharedInformers.Core().V1().Pods()
-	cc.podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	cc.podInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    cc.addPod,
 		UpdateFunc: cc.updatePod,
 		DeleteFunc: cc.deletePod,
-	})
+	}), opt.ResyncPeriod)
 
 	cc.podLister = cc.podInformer.Lister()
 	cc.podSynced = cc.podInformer.Informer().HasSynced
@@ -210,9 +250,9 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 	cc.svcSynced = cc.svcInformer.Informer().HasSynced
 
 	cc.pgInformer = factory.Scheduling().V1beta1().PodGroups()
-	cc.pgInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	cc.pgInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 		UpdateFunc: cc.updatePodGroup,
-	})
+	}), opt.ResyncPeriod)
 	cc.pgLister = cc.pgInformer.Lister()
 	cc.pgSynced = cc.pgInformer.Informer().HasSynced
 
@@ -226,15 +266,30 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 	cc.queueLister = cc.queueInformer.Lister()
 	cc.queueSynced = cc.queueInformer.Informer().HasSynced
 
+	cc.pdbInformer = sharedInformers.Policy().V1().PodDisruptionBudgets()
+	// the pdb cache is synced with the other informers of the factory before the workers start
+	cc.pdbLister = cc.pdbInformer.Lister()
+
+	if opt.JobEventAggregationPeriod > 0 && cc.jobLister != nil {
+		cc.initPodEventInformer(opt)
+	}
+	if opt.JobAccountingPeriod > 0 && cc.jobLister != nil {
+		cc.initAccounting(opt)
+	}
+
 	// Register actions
 	state.SyncJob = cc.syncJob
 	state.KillJob = cc.killJob
+	state.RestartPod = cc.restartPod
+	state.RequeueJob = cc.requeueJob
 
 	return nil
 }
 
 // Run start JobController.
 func (cc *jobcontroller) Run(stopCh <-chan struct{}) {
+	defer cc.shutDownQueues()
+
 	cc.informerFactory.Start(stopCh)
 	cc.vcInformerFactory.Start(stopCh)
 
@@ -252,10 +307,17 @@ func (cc *jobcontroller) Run(stopCh <-chan struct{}) {
 		}
 	}
 
-	go wait.Until(cc.handleCommands, 0, stopCh)
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		wait.Until(cc.handleCommands, 0, stopCh)
+	}()
 	var i uint32
 	for i = 0; i < cc.workers; i++ {
+		workers.Add(1)
 		go func(num uint32) {
+			defer workers.Done()
 			wait.Until(
 				func() {
 					cc.worker(num)
@@ -267,10 +329,47 @@ func (cc *jobcontroller) Run(stopCh <-chan struct{}) {
 
 	go cc.cache.Run(stopCh)
 
+	if cc.notifier != nil {
+		go cc.notifier.Run(stopCh)
+	}
+
 	// Re-sync error tasks.
 	go wait.Until(cc.processResyncTask, 0, stopCh)
 
+	if cc.eventInformerFactory != nil {
+		cc.eventInformerFactory.Start(stopCh)
+		go wait.Until(cc.flushPodEvents, cc.eventAggregationPeriod, stopCh)
+	}
+	if cc.accounting != nil {
+		go wait.Until(cc.flushAccounting, cc.accountingPeriod, stopCh)
+	}
+
 	klog.Infof("JobController is running ...... ")
+
+	<-stopCh
+
+	// the workers handle the requests left in the queues until they are empty, so that the job transitions
+	// in flight are not dropped, and the events they recorded are sent before the controller stops
+	klog.Infof("JobController is stopping, draining the queues ......")
+	cc.shutDownQueues()
+	workers.Wait()
+	if cc.eventAggregator != nil {
+		cc.flushPodEvents()
+	}
+	if cc.accounting != nil {
+		cc.flushAccounting()
+	}
+	cc.eventBroadcaster.Shutdown()
+	klog.Infof("JobController is stopped")
+}
+
+// shutDownQueues shuts the queues of the controller down; the requests left are still handed to the workers.
+func (cc *jobcontroller) shutDownQueues() {
+	cc.commandQueue.ShutDown()
+	cc.errTasks.ShutDown()
+	for _, queue := range cc.queueList {
+		queue.ShutDown()
+	}
 }
 
 func (cc *jobcontroller) worker(i uint32) {
@@ -310,7 +409,7 @@ func (cc *jobcontroller) processNextReq(count uint32) bool {
 	queue := cc.queueList[count]
 	obj, shutdown := queue.Get()
 	if shutdown {
-		klog.Errorf("Fail to pop item from queue")
+		logger.Error(nil, "Failed to pop item from queue")
 		return false
 	}
 
@@ -319,52 +418,60 @@ func (cc *jobcontroller) processNextReq(count uint32) bool {
 
 	key := jobcache.JobKeyByReq(&req)
 	if !cc.belongsToThisRoutine(key, count) {
-		klog.Errorf("should not occur The job does not belongs to this routine key:%s, worker:%d...... ", key, count)
+		logger.Error(nil, "Should not occur, the job does not belong to this worker", logging.JobKey, key, "worker", count)
 		queueLocal := cc.getWorkerQueue(key)
 		queueLocal.Add(req)
 		return true
 	}
 
-	klog.V(3).Infof("Try to handle request <%v>", req)
+	jobLogger := logger.WithValues(logging.JobKey, klog.KRef(req.Namespace, req.JobName))
+	jobLogger.V(3).Info("Try to handle request", logging.TaskKey, req.TaskName, "event", req.Event, "action", req.Action)
 
 	jobInfo, err := cc.cache.Get(key)
 	if err != nil {
 		// TODO(k82cn): ignore not-ready error.
-		klog.Errorf("Failed to get job by <%v> from cache: %v", req, err)
+		jobLogger.Error(err, "Failed to get job from cache")
 		return true
 	}
 
 	st := state.NewState(jobInfo)
 	if st == nil {
-		klog.Errorf("Invalid state <%s> of Job <%v/%v>",
-			jobInfo.Job.Status.State, jobInfo.Job.Namespace, jobInfo.Job.Name)
+		jobLogger.Error(nil, "Invalid state of job", "state", jobInfo.Job.Status.State)
 		return true
 	}
 
-	action := applyPolicies(jobInfo.Job, &req)
-	klog.V(3).Infof("Execute <%v> on Job <%s/%s> in <%s> by <%T>.",
-		action, req.Namespace, req.JobName, jobInfo.Job.Status.State.Phase, st)
+	var action busv1alpha1.Action
+	if req.Event == apis.PodPendingTimeoutEvent && !cc.isPodPendingTimeout(jobInfo, req.TaskName) {
+		// pods have been scheduled or recreated since the request was delayed
+		action = busv1alpha1.SyncJobAction
+	} else {
+		action = applyPolicies(jobInfo.Job, &req)
+	}
+	jobLogger.V(3).Info("Execute action on job", "action", action, "phase", jobInfo.Job.Status.State.Phase, "state", fmt.Sprintf("%T", st))
 
 	if action != busv1alpha1.SyncJobAction {
-		cc.recordJobEvent(jobInfo.Job.Namespace, jobInfo.Job.Name, batchv1alpha1.ExecuteAction, fmt.Sprintf(
+		cc.recordJobEvent(jobInfo.Job.Namespace, jobInfo.Job.Name, events.ExecuteAction, fmt.Sprintf(
 			"Start to execute action %s ", action))
 	}
 
 	if err := st.Execute(action); err != nil {
 		if cc.maxRequeueNum == -1 || queue.NumRequeues(req) < cc.maxRequeueNum {
-			klog.V(2).Infof("Failed to handle Job <%s/%s>: %v",
-				jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+			jobLogger.V(2).Info("Failed to handle job", "err", err)
 			// If any error, requeue it.
 			queue.AddRateLimited(req)
 			return true
 		}
-		cc.recordJobEvent(jobInfo.Job.Namespace, jobInfo.Job.Name, batchv1alpha1.ExecuteAction, fmt.Sprintf(
+		cc.recordJobEvent(jobInfo.Job.Namespace, jobInfo.Job.Name, events.ExecuteAction, fmt.Sprintf(
 			"Job failed on action %s for retry limit reached", action))
-		klog.Warningf("Terminating Job <%s/%s> and releasing resources", jobInfo.Job.Namespace, jobInfo.Job.Name)
+		jobLogger.Info("Terminating job and releasing resources")
 		if err = st.Execute(busv1alpha1.TerminateJobAction); err != nil {
-			klog.Errorf("Failed to terminate Job<%s/%s>: %v", jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+			jobLogger.Error(err, "Failed to terminate job")
+		}
+		jobLogger.Info("Dropping job out of the queue because max retries has reached", "err", err)
+	} else if req.Event == busv1alpha1.CommandIssuedEvent {
+		if err := cc.setCommandReason(&req); err != nil {
+			jobLogger.Error(err, "Failed to record the reason of command on job", "action", req.Action)
 		}
-		klog.Warningf("Dropping job<%s/%s> out of the queue: %v because max retries has reached", jobInfo.Job.Namespace, jobInfo.Job.Name, err)
 	}
 
 	// If no error, forget it.
@@ -372,3 +479,39 @@ func (cc *jobcontroller) processNextReq(count uint32) bool {
 
 	return true
 }
+
+// notifyJobUpdate records the event of the phase change of the job, and sends the phase change and the
+// retry of the job to the notifier, if any.
+func (cc *jobcontroller) notifyJobUpdate(oldJob, newJob *batchv1alpha1.Job) {
+	if newJob.Status.State.Phase != oldJob.Status.State.Phase {
+		cc.recordJobPhaseEvent(oldJob, newJob)
+	}
+	if cc.notifier == nil {
+		return
+	}
+
+	if newJob.Status.RetryCount > oldJob.Status.RetryCount {
+		cc.notifier.Notify(notification.NewEvent(notification.Retried, newJob))
+	}
+	if newJob.Status.State.Phase != oldJob.Status.State.Phase {
+		event := notification.NewEvent(notification.PhaseChanged, newJob)
+		event.PreviousPhase = oldJob.Status.State.Phase
+		cc.notifier.Notify(event)
+	}
+}
+
+// recordJobPhaseEvent records the event of the phase the job enters, if the phase has a reason in the catalog.
+func (cc *jobcontroller) recordJobPhaseEvent(oldJob, newJob *batchv1alpha1.Job) {
+	reason, found := events.JobPhaseReason(newJob.Status.State.Phase)
+	if !found {
+		return
+	}
+	message := fmt.Sprintf("Job phase changed from %s to %s", oldJob.Status.State.Phase, newJob.Status.State.Phase)
+	if newJob.Status.State.Reason != "" {
+		message += fmt.Sprintf(", reason: %s", newJob.Status.State.Reason)
+	}
+	if newJob.Status.State.Message != "" {
+		message += fmt.Sprintf(", message: %s", newJob.Status.State.Message)
+	}
+	events.Record(cc.recorder, newJob, reason, message)
+}
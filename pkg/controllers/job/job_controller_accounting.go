@@ -0,0 +1,282 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+
+	jobcache "volcano.sh/volcano/pkg/controllers/cache"
+	"volcano.sh/volcano/pkg/controllers/framework"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+var jobResourceHoursDesc = prometheus.NewDesc(
+	"volcano_job_resource_hours",
+	"Resource-hours consumed by the pods of the job: core-hours of cpu, GiB-hours of memory and unit-hours of the extended resources",
+	[]string{"namespace", "job", "queue", "resource"}, nil,
+)
+
+// jobAccounting holds the resource-hours of the pods which finished since they were last
+// recorded on their jobs, keyed by job and by pod UID, so that a pod seen twice is accounted once.
+type jobAccounting struct {
+	sync.Mutex
+	pending map[string]map[types.UID]jobhelpers.ResourceHours
+}
+
+func newJobAccounting() *jobAccounting {
+	return &jobAccounting{pending: map[string]map[types.UID]jobhelpers.ResourceHours{}}
+}
+
+func (a *jobAccounting) add(key string, pods map[types.UID]jobhelpers.ResourceHours) {
+	a.Lock()
+	defer a.Unlock()
+
+	for uid, hours := range pods {
+		if len(hours) == 0 {
+			continue
+		}
+		if a.pending[key] == nil {
+			a.pending[key] = map[types.UID]jobhelpers.ResourceHours{}
+		}
+		a.pending[key][uid] = hours
+	}
+}
+
+// pendingOf returns the resource-hours of the job not recorded yet, but for the accounted pods.
+func (a *jobAccounting) pendingOf(key string, accounted sets.Set[string]) jobhelpers.ResourceHours {
+	a.Lock()
+	defer a.Unlock()
+
+	hours := jobhelpers.ResourceHours{}
+	for uid, podHours := range a.pending[key] {
+		if !accounted.Has(string(uid)) {
+			hours.Add(podHours)
+		}
+	}
+	return hours
+}
+
+// take returns the resource-hours not recorded yet and forgets them.
+func (a *jobAccounting) take() map[string]map[types.UID]jobhelpers.ResourceHours {
+	a.Lock()
+	defer a.Unlock()
+
+	pending := a.pending
+	a.pending = map[string]map[types.UID]jobhelpers.ResourceHours{}
+	return pending
+}
+
+// initAccounting accounts the resource-hours of the job pods when they finish or are deleted, which
+// are recorded in the status of the jobs every opt.JobAccountingPeriod.
+func (cc *jobcontroller) initAccounting(opt *framework.ControllerOption) {
+	cc.accounting = newJobAccounting()
+	cc.accountingPeriod = opt.JobAccountingPeriod
+	cc.podInformer.Informer().AddEventHandler(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    cc.accountPodAdd,
+		UpdateFunc: cc.accountPodUpdate,
+		DeleteFunc: cc.accountPodDelete,
+	}))
+
+	if err := prometheus.Register(&jobResourceHoursCollector{cc: cc}); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			klog.Errorf("Failed to register the job resource-hours metrics: %v", err)
+		}
+	}
+}
+
+func isPodFinished(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+// accountPodAdd accounts the pod already finished when it is listed, e.g. the pods which finished while
+// the controller was down; the pods already recorded on their job are skipped when they are flushed.
+func (cc *jobcontroller) accountPodAdd(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	if !isControlledBy(pod, helpers.JobKind) || !isPodFinished(pod) {
+		return
+	}
+	cc.accountPod(pod, jobhelpers.PodFinishTime(pod, time.Now()))
+}
+
+// accountPodUpdate accounts the pod once it finishes, from its start to the termination of its last container.
+func (cc *jobcontroller) accountPodUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	newPod, ok := newObj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	if !isControlledBy(newPod, helpers.JobKind) || isPodFinished(oldPod) || !isPodFinished(newPod) {
+		return
+	}
+	cc.accountPod(newPod, jobhelpers.PodFinishTime(newPod, time.Now()))
+}
+
+// accountPodDelete accounts the pod deleted before it finished, until its deletion.
+func (cc *jobcontroller) accountPodDelete(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if pod, ok = tombstone.Obj.(*v1.Pod); !ok {
+			return
+		}
+	}
+	if !isControlledBy(pod, helpers.JobKind) || isPodFinished(pod) {
+		return
+	}
+	cc.accountPod(pod, time.Now())
+}
+
+func (cc *jobcontroller) accountPod(pod *v1.Pod, end time.Time) {
+	jobName, found := pod.Annotations[batch.JobNameKey]
+	if !found {
+		return
+	}
+	cc.accounting.add(jobcache.JobKeyByName(pod.Namespace, jobName),
+		map[types.UID]jobhelpers.ResourceHours{pod.UID: jobhelpers.PodResourceHours(pod, end)})
+}
+
+// jobPodUIDs returns the UIDs of the existing pods of the job.
+func (cc *jobcontroller) jobPodUIDs(namespace, name string) (sets.Set[string], error) {
+	pods, err := cc.podLister.Pods(namespace).List(labels.SelectorFromSet(labels.Set{batch.JobNameKey: name}))
+	if err != nil {
+		return nil, err
+	}
+	uids := sets.New[string]()
+	for _, pod := range pods {
+		uids.Insert(string(pod.UID))
+	}
+	return uids, nil
+}
+
+// flushAccounting adds the resource-hours of the pods finished since the last flush to the status of
+// their jobs, but for the pods already accounted; they are retried in the next flush if the job status
+// is not updated. The accounted pods which no longer exist are forgotten, as they are not seen again.
+func (cc *jobcontroller) flushAccounting() {
+	for key, pods := range cc.accounting.take() {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			continue
+		}
+		job, err := cc.jobLister.Jobs(namespace).Get(name)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("Failed to get Job %s to record its resource-hours: %v", key, err)
+				cc.accounting.add(key, pods)
+			}
+			continue
+		}
+		existing, err := cc.jobPodUIDs(namespace, name)
+		if err != nil {
+			klog.Errorf("Failed to list the pods of Job %s to record its resource-hours: %v", key, err)
+			cc.accounting.add(key, pods)
+			continue
+		}
+
+		job = job.DeepCopy()
+		hours := jobhelpers.GetResourceHours(job)
+		accounted := jobhelpers.GetAccountedPods(job)
+		for uid, podHours := range pods {
+			if accounted.Has(string(uid)) {
+				continue
+			}
+			hours.Add(podHours)
+			accounted.Insert(string(uid))
+		}
+		if !jobhelpers.SetResourceHours(job, hours, accounted.Intersection(existing)) {
+			continue
+		}
+		if _, err := cc.vcClient.BatchV1alpha1().Jobs(namespace).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("Failed to record the resource-hours of Job %s: %v", key, err)
+				cc.accounting.add(key, pods)
+			}
+		}
+	}
+}
+
+// jobResourceHours returns the resource-hours consumed by the pods of the job until now: the ones
+// recorded on the job, the ones of the pods finished since, and the ones of the running pods.
+func (cc *jobcontroller) jobResourceHours(job *batch.Job, now time.Time) jobhelpers.ResourceHours {
+	hours := jobhelpers.GetResourceHours(job)
+	hours.Add(cc.accounting.pendingOf(jobcache.JobKeyByName(job.Namespace, job.Name), jobhelpers.GetAccountedPods(job)))
+
+	pods, err := cc.podLister.Pods(job.Namespace).List(labels.SelectorFromSet(labels.Set{batch.JobNameKey: job.Name}))
+	if err != nil {
+		klog.Errorf("Failed to list the pods of Job %s/%s for the metrics: %v", job.Namespace, job.Name, err)
+		return hours
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning && isControlledBy(pod, helpers.JobKind) {
+			hours.Add(jobhelpers.PodResourceHours(pod, now))
+		}
+	}
+	return hours
+}
+
+// jobResourceHoursCollector exports the resource-hours of the jobs of the informer cache when
+// the metrics are scraped.
+type jobResourceHoursCollector struct {
+	cc *jobcontroller
+}
+
+// Describe implements prometheus.Collector.
+func (c *jobResourceHoursCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- jobResourceHoursDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *jobResourceHoursCollector) Collect(ch chan<- prometheus.Metric) {
+	jobs, err := c.cc.jobLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list jobs for the metrics: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		for name, hours := range c.cc.jobResourceHours(job, now) {
+			ch <- prometheus.MustNewConstMetric(jobResourceHoursDesc, prometheus.CounterValue, hours,
+				job.Namespace, job.Name, job.Spec.Queue, string(name))
+		}
+	}
+}
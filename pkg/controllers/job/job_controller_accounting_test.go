@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+func buildAccountedPod(name string, phase v1.PodPhase, start time.Time) *v1.Pod {
+	pod := addPodAnnotation(buildPod("ns", name, phase, nil), map[string]string{batch.JobNameKey: "job1"})
+	pod.Status.StartTime = &metav1.Time{Time: start}
+	pod.Spec.Containers[0].Resources.Requests = v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")}
+	return pod
+}
+
+func TestAccountPod(t *testing.T) {
+	start := time.Now().Add(-2 * time.Hour)
+	finished := buildAccountedPod("job1-worker-0", v1.PodSucceeded, start)
+	finished.Status.ContainerStatuses = []v1.ContainerStatus{{
+		State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.Time{Time: start.Add(time.Hour)}}},
+	}}
+
+	testCases := []struct {
+		Name   string
+		Add    *v1.Pod
+		Update [2]*v1.Pod
+		Delete interface{}
+		Expect jobhelpers.ResourceHours
+	}{
+		{
+			Name:   "finished pod listed",
+			Add:    finished,
+			Expect: jobhelpers.ResourceHours{v1.ResourceCPU: 2},
+		},
+		{
+			Name:   "running pod listed",
+			Add:    buildAccountedPod("job1-worker-0", v1.PodRunning, start),
+			Expect: nil,
+		},
+		{
+			Name:   "pod finished",
+			Update: [2]*v1.Pod{buildAccountedPod("job1-worker-0", v1.PodRunning, start), finished},
+			Expect: jobhelpers.ResourceHours{v1.ResourceCPU: 2},
+		},
+		{
+			Name:   "finished pod updated",
+			Update: [2]*v1.Pod{finished, finished},
+			Expect: nil,
+		},
+		{
+			Name:   "finished pod deleted",
+			Delete: finished,
+			Expect: nil,
+		},
+		{
+			Name:   "running pod deleted",
+			Delete: cache.DeletedFinalStateUnknown{Obj: buildAccountedPod("job1-worker-0", v1.PodRunning, start)},
+			Expect: jobhelpers.ResourceHours{v1.ResourceCPU: 4},
+		},
+		{
+			Name:   "pod not started",
+			Delete: buildPod("ns", "job1-worker-0", v1.PodPending, nil),
+			Expect: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			cc := &jobcontroller{accounting: newJobAccounting()}
+			if testCase.Add != nil {
+				cc.accountPodAdd(testCase.Add)
+			}
+			if testCase.Update[0] != nil {
+				cc.accountPodUpdate(testCase.Update[0], testCase.Update[1])
+			}
+			if testCase.Delete != nil {
+				cc.accountPodDelete(testCase.Delete)
+			}
+
+			pending := cc.accounting.pendingOf("ns/job1", sets.New[string]())
+			if testCase.Expect == nil {
+				if len(pending) != 0 {
+					t.Errorf("expect no resource-hours, got: %v", pending)
+				}
+				return
+			}
+			// the pods deleted while running are accounted until now
+			if !reflect.DeepEqual(roundResourceHours(pending), testCase.Expect) {
+				t.Errorf("expect resource-hours %v, got: %v", testCase.Expect, pending)
+			}
+		})
+	}
+}
+
+func TestFlushAccounting(t *testing.T) {
+	start := time.Now().Add(-2 * time.Hour)
+	accounted := buildAccountedPod("job1-worker-0", v1.PodSucceeded, start)
+	existing := buildAccountedPod("job1-worker-1", v1.PodSucceeded, start)
+	job := &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "ns"}}
+	jobhelpers.SetResourceHours(job, jobhelpers.ResourceHours{v1.ResourceCPU: 2},
+		sets.New(string(accounted.UID), "deleted-pod"))
+
+	cc := newFakeController()
+	cc.accounting = newJobAccounting()
+	if _, err := cc.vcClient.BatchV1alpha1().Jobs("ns").Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+	cc.jobInformer.Informer().GetIndexer().Add(job)
+	for _, pod := range []*v1.Pod{accounted, existing} {
+		pod.Labels = map[string]string{batch.JobNameKey: "job1"}
+		cc.podInformer.Informer().GetIndexer().Add(pod)
+	}
+
+	// the pod already recorded is listed again, e.g. after a restart, along with a pod finished since
+	cc.accounting.add("ns/job1", map[types.UID]jobhelpers.ResourceHours{
+		accounted.UID: {v1.ResourceCPU: 2},
+		existing.UID:  {v1.ResourceCPU: 3},
+	})
+	cc.flushAccounting()
+
+	updated, err := cc.vcClient.BatchV1alpha1().Jobs("ns").Get(context.TODO(), "job1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get job: %v", err)
+	}
+	expected := jobhelpers.ResourceHours{v1.ResourceCPU: 5}
+	if hours := jobhelpers.GetResourceHours(updated); !reflect.DeepEqual(hours, expected) {
+		t.Errorf("expect resource-hours %v, got: %v", expected, hours)
+	}
+	expectedPods := sets.New(string(accounted.UID), string(existing.UID))
+	if pods := jobhelpers.GetAccountedPods(updated); !pods.Equal(expectedPods) {
+		t.Errorf("expect accounted pods %v, got: %v", sets.List(expectedPods), sets.List(pods))
+	}
+}
+
+func roundResourceHours(hours jobhelpers.ResourceHours) jobhelpers.ResourceHours {
+	rounded := jobhelpers.ResourceHours{}
+	for name, value := range hours {
+		rounded[name] = float64(int(value*100+0.5)) / 100
+	}
+	return rounded
+}
@@ -20,15 +20,23 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -36,8 +44,10 @@ import (
 	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 
 	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/events"
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/state"
+	"volcano.sh/volcano/pkg/util/tracing"
 )
 
 var calMutex sync.Mutex
@@ -59,7 +69,25 @@ func (cc *jobcontroller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.Pha
 	var errs []error
 	var total int
 
-	for _, pods := range jobInfo.Pods {
+	maxRetry := job.Spec.MaxRetry
+	lastRetry := false
+	if job.Status.RetryCount >= maxRetry-1 {
+		lastRetry = true
+	}
+
+	// A finishing job keeps its finished pods according to its pod retain policy.
+	finishing := isFinishingPhase(job.Status.State.Phase)
+
+	// Only retain the Failed and Succeeded pods at the last retry.
+	// If it is not the last retry, kill pod as defined in `podRetainPhase`.
+	retainPhase := podRetainPhase
+	if lastRetry && !finishing {
+		retainPhase = state.PodRetainPhaseSoft
+	}
+
+	deferredTasks := getDeferredTasks(jobInfo, jobhelpers.GetTerminationOrder(job), retainPhase)
+
+	for taskName, pods := range jobInfo.Pods {
 		for _, pod := range pods {
 			total++
 
@@ -69,24 +97,26 @@ func (cc *jobcontroller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.Pha
 				continue
 			}
 
-			maxRetry := job.Spec.MaxRetry
-			lastRetry := false
-			if job.Status.RetryCount >= maxRetry-1 {
-				lastRetry = true
-			}
+			_, retain := retainPhase[pod.Status.Phase]
 
-			// Only retain the Failed and Succeeded pods at the last retry.
-			// If it is not the last retry, kill pod as defined in `podRetainPhase`.
-			retainPhase := podRetainPhase
-			if lastRetry {
-				retainPhase = state.PodRetainPhaseSoft
+			if !retain && deferredTasks[taskName] {
+				// The pod will be deleted once the tasks ordered before it are gone,
+				// count it as terminating so that the job does not move on without it.
+				klog.V(3).Infof("Deferring termination of Pod <%s/%s> by termination order", pod.Namespace, pod.Name)
+				terminating++
+				continue
 			}
-			_, retain := retainPhase[pod.Status.Phase]
 
 			if !retain {
 				err := cc.deleteJobPod(job.Name, pod)
 				if err == nil {
-					terminating++
+					if !finishing || (pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed) {
+						terminating++
+						continue
+					}
+					// finished pods deleted by the pod retain policy are still counted in the job status
+					classifyAndAddUpPodBaseOnPhase(pod, &pending, &running, &succeeded, &failed, &unknown)
+					calcPodStatus(pod, taskStatusCount)
 					continue
 				}
 				// record the err, and then collect the pod info like retained pod
@@ -101,7 +131,7 @@ func (cc *jobcontroller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.Pha
 
 	if len(errs) != 0 {
 		klog.Errorf("failed to kill pods for job %s/%s, with err %+v", job.Namespace, job.Name, errs)
-		cc.recorder.Event(job, v1.EventTypeWarning, FailedDeletePodReason,
+		events.Record(cc.recorder, job, events.FailedDeletePod,
 			fmt.Sprintf("Error deleting pods: %+v", errs))
 		return fmt.Errorf("failed to kill %d pods of %d", len(errs), total)
 	}
@@ -111,25 +141,23 @@ func (cc *jobcontroller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.Pha
 	job.Status.Version++
 	job.Status.Pending = pending
 	job.Status.Running = running
-	job.Status.Succeeded = succeeded
-	job.Status.Failed = failed
 	job.Status.Terminating = terminating
 	job.Status.Unknown = unknown
-	job.Status.TaskStatusCount = taskStatusCount
+	// The finished pods of a finished job may have been deleted by its pod retain policy,
+	// keep the counts recorded when the job finished.
+	if !isFinishedPhase(job.Status.State.Phase) || succeeded+failed >= job.Status.Succeeded+job.Status.Failed {
+		job.Status.Succeeded = succeeded
+		job.Status.Failed = failed
+		job.Status.TaskStatusCount = taskStatusCount
+	}
 
 	if updateStatus != nil {
 		if updateStatus(&job.Status) {
-			job.Status.State.LastTransitionTime = metav1.Now()
-			jobCondition := newCondition(job.Status.State.Phase, &job.Status.State.LastTransitionTime)
-			job.Status.Conditions = append(job.Status.Conditions, jobCondition)
+			setPhaseTransition(job)
+			klog.V(3).Infof("Running duration is %s", job.Status.RunningDuration.ToUnstructured())
 		}
 	}
 
-	// Update running duration
-	runningDuration := metav1.Duration{Duration: job.Status.State.LastTransitionTime.Sub(jobInfo.Job.CreationTimestamp.Time)}
-	klog.V(3).Infof("Running duration is %s", runningDuration.ToUnstructured())
-	job.Status.RunningDuration = &runningDuration
-
 	// must be called before update job status
 	if err := cc.pluginOnJobDelete(job); err != nil {
 		return err
@@ -151,6 +179,7 @@ func (cc *jobcontroller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.Pha
 			newJob.Namespace, newJob.Name, e)
 		return e
 	}
+	cc.notifyJobUpdate(jobInfo.Job, newJob)
 
 	// Delete PodGroup
 	pgName := job.Name + "-" + string(job.UID)
@@ -163,38 +192,236 @@ func (cc *jobcontroller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.Pha
 	}
 
 	// NOTE(k82cn): DO NOT delete input/output until job is deleted.
+	// Unless the job asks to delete its volumes once it is finished.
+	if isFinishedPhase(newJob.Status.State.Phase) && jobhelpers.GetVolumeRetentionPolicy(newJob) == jobhelpers.VolumeRetentionPolicyDelete {
+		if err := cc.deleteJobPVCs(newJob); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cc *jobcontroller) restartPod(jobInfo *apis.JobInfo, updateStatus state.UpdateStatusFn) error {
+	job := jobInfo.Job
+	klog.V(3).Infof("Restarting failed pods of Job <%s/%s>, current version %d", job.Namespace, job.Name, job.Status.Version)
+
+	if job.DeletionTimestamp != nil {
+		klog.Infof("Job <%s/%s> is terminating, skip management process.",
+			job.Namespace, job.Name)
+		return nil
+	}
+
+	restarts := jobhelpers.GetReplicaRestarts(job)
+	var podToRestart []*v1.Pod
+	for taskName, pods := range jobInfo.Pods {
+		maxRetry := job.Spec.MaxRetry
+		if ts, found := jobhelpers.GetTaskSpec(job, taskName); found && ts.MaxRetry > 0 {
+			maxRetry = ts.MaxRetry
+		}
+
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodFailed {
+				continue
+			}
+
+			if restarts[pod.Name] >= maxRetry {
+				message := fmt.Sprintf("Pod %s failed after %d restarts, retry limit reached", pod.Name, restarts[pod.Name])
+				events.RecordWarning(cc.recorder, job, events.ExecuteAction, message)
+				return cc.killJob(jobInfo, state.PodRetainPhaseSoft, func(status *batch.JobStatus) bool {
+					status.State.Phase = batch.Failed
+					status.State.Reason = state.PodMaxRetryReachedReason
+					status.State.Message = message
+					return true
+				})
+			}
+			podToRestart = append(podToRestart, pod)
+		}
+	}
+
+	if len(podToRestart) == 0 {
+		return cc.syncJob(jobInfo, updateStatus)
+	}
+
+	var errs []error
+	restarted := map[string][]string{}
+	for _, pod := range podToRestart {
+		if err := cc.deleteJobPod(job.Name, pod); err != nil {
+			errs = append(errs, err)
+			cc.resyncTask(pod)
+			continue
+		}
+		restarts[pod.Name]++
+		taskName := pod.Annotations[batch.TaskSpecKey]
+		restarted[taskName] = append(restarted[taskName], pod.Name)
+		klog.V(3).Infof("Deleted failed Pod <%s/%s> of Job %s to recreate it", pod.Namespace, pod.Name, job.Name)
+	}
+
+	// The deleted pods are recreated by the next sync of the job, which finds them missing.
+	newJob := job.DeepCopy()
+	if jobhelpers.SetReplicaRestarts(newJob, restarts) {
+		updatedJob, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).UpdateStatus(context.TODO(), newJob, metav1.UpdateOptions{})
+		if err != nil {
+			klog.Errorf("Failed to update replica restarts of Job %v/%v: %v",
+				job.Namespace, job.Name, err)
+			return err
+		}
+		if e := cc.cache.Update(updatedJob); e != nil {
+			klog.Errorf("RestartPod - Failed to update Job %v/%v in cache:  %v",
+				updatedJob.Namespace, updatedJob.Name, e)
+			return e
+		}
+		newJob = updatedJob
+	}
+	for _, taskName := range sets.List(sets.KeySet(restarted)) {
+		events.Recordf(cc.recorder, newJob, events.TaskRestarted, "Restarted the failed pods %s of task %s",
+			strings.Join(sets.List(sets.New(restarted[taskName]...)), ", "), taskName)
+	}
+
+	if len(errs) != 0 {
+		events.Record(cc.recorder, job, events.FailedDeletePod,
+			fmt.Sprintf("Error deleting pods: %+v", errs))
+		return fmt.Errorf("failed to restart %d pods of %d", len(errs), len(podToRestart))
+	}
+
+	return nil
+}
+
+// requeueJob moves the pending job to the queue named by its pending-timeout-queue annotation.
+// The PodGroup and the unscheduled pods follow the job on the next sync, so that the scheduler
+// re-evaluates the job in the new queue.
+func (cc *jobcontroller) requeueJob(jobInfo *apis.JobInfo, updateStatus state.UpdateStatusFn) error {
+	job := jobInfo.Job
+	klog.V(3).Infof("Requeueing Job <%s/%s>, current queue %s", job.Namespace, job.Name, job.Spec.Queue)
+
+	if job.DeletionTimestamp != nil {
+		klog.Infof("Job <%s/%s> is terminating, skip management process.",
+			job.Namespace, job.Name)
+		return nil
+	}
+
+	target := job.Annotations[jobhelpers.PendingTimeoutQueueKey]
+	if target == "" || target == job.Spec.Queue {
+		events.Record(cc.recorder, job, events.FailedRequeue,
+			fmt.Sprintf("No queue other than %s to move the job to, annotation %s is not set", job.Spec.Queue, jobhelpers.PendingTimeoutQueueKey))
+		return cc.syncJob(jobInfo, updateStatus)
+	}
+	if _, err := cc.GetQueueInfo(target); err != nil {
+		events.Record(cc.recorder, job, events.FailedRequeue,
+			fmt.Sprintf("Failed to move the job to queue %s, err: %v", target, err))
+		return cc.syncJob(jobInfo, updateStatus)
+	}
+
+	job = job.DeepCopy()
+	job.Spec.Queue = target
+	newJob, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).Update(context.TODO(), job, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to update queue of Job %v/%v: %v",
+			job.Namespace, job.Name, err)
+		return err
+	}
+	if e := cc.cache.Update(newJob); e != nil {
+		klog.Errorf("RequeueJob - Failed to update Job %v/%v in cache:  %v",
+			newJob.Namespace, newJob.Name, e)
+		return e
+	}
+	return nil
+}
 
+// deleteQueueMigratedPods deletes the unscheduled pods created in the previous queue of the job,
+// they are recreated in the current queue of the job.
+func (cc *jobcontroller) deleteQueueMigratedPods(jobInfo *apis.JobInfo, job *batch.Job) error {
+	var errs []error
+	for _, pods := range jobInfo.Pods {
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil || !isQueueMigratedPod(job, pod) {
+				continue
+			}
+			if err := cc.deleteJobPod(job.Name, pod); err != nil {
+				errs = append(errs, err)
+				cc.resyncTask(pod)
+				continue
+			}
+			klog.V(3).Infof("Deleted Pod <%s/%s> of Job %s moved to queue %s", pod.Namespace, pod.Name, job.Name, job.Spec.Queue)
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("failed to delete %d pods moved to queue %s", len(errs), job.Spec.Queue)
+	}
 	return nil
 }
 
+// enqueuePodPendingTimeouts schedules a PodPendingTimeout request for each task with
+// unscheduled pods, to be handled when the timeout of its policy expires.
+func (cc *jobcontroller) enqueuePodPendingTimeouts(job *batch.Job, pods map[string]map[string]*v1.Pod) {
+	for taskName, timeout := range getPodPendingTimeouts(job, pods, time.Now()) {
+		req := apis.Request{
+			Namespace:  job.Namespace,
+			JobName:    job.Name,
+			TaskName:   taskName,
+			Event:      apis.PodPendingTimeoutEvent,
+			JobVersion: job.Status.Version,
+		}
+		queue := cc.getWorkerQueue(jobhelpers.GetJobKeyByReq(&req))
+		queue.AddAfter(req, timeout.next())
+	}
+}
+
+// isPodPendingTimeout checks whether pods of the task are still pending beyond the timeout
+// when a delayed PodPendingTimeout request is handled.
+func (cc *jobcontroller) isPodPendingTimeout(jobInfo *apis.JobInfo, taskName string) bool {
+	timeout, found := getPodPendingTimeouts(jobInfo.Job, jobInfo.Pods, time.Now())[taskName]
+	if !found || !timeout.expired() {
+		return false
+	}
+
+	events.Record(cc.recorder, jobInfo.Job, events.PodPendingTimeout,
+		fmt.Sprintf("Pods of task %s have been pending for %v, longer than %v", taskName, timeout.pending.Round(time.Second), timeout.timeout))
+	return true
+}
+
 func (cc *jobcontroller) initiateJob(job *batch.Job) (*batch.Job, error) {
 	klog.V(3).Infof("Starting to initiate Job <%s/%s>", job.Namespace, job.Name)
 	jobInstance, err := cc.initJobStatus(job)
 	if err != nil {
-		cc.recorder.Event(job, v1.EventTypeWarning, string(batch.JobStatusError),
+		events.Record(cc.recorder, job, events.JobStatusError,
 			fmt.Sprintf("Failed to initialize job status, err: %v", err))
 		return nil, err
 	}
 
 	if err := cc.pluginOnJobAdd(jobInstance); err != nil {
-		cc.recorder.Event(job, v1.EventTypeWarning, string(batch.PluginError),
+		events.Record(cc.recorder, job, events.PluginError,
 			fmt.Sprintf("Execute plugin when job add failed, err: %v", err))
 		return nil, err
 	}
 
 	newJob, err := cc.createJobIOIfNotExist(jobInstance)
 	if err != nil {
-		cc.recorder.Event(job, v1.EventTypeWarning, string(batch.PVCError),
+		events.Record(cc.recorder, job, events.PVCError,
 			fmt.Sprintf("Failed to create PVC, err: %v", err))
 		return nil, err
 	}
 
 	if err := cc.createOrUpdatePodGroup(newJob); err != nil {
-		cc.recorder.Event(job, v1.EventTypeWarning, string(batch.PodGroupError),
+		events.Record(cc.recorder, job, events.PodGroupError,
 			fmt.Sprintf("Failed to create PodGroup, err: %v", err))
 		return nil, err
 	}
 
+	if err := cc.createOrUpdatePDBs(newJob); err != nil {
+		events.Record(cc.recorder, job, events.FailedCreatePDB,
+			fmt.Sprintf("Failed to create PodDisruptionBudget, err: %v", err))
+		return nil, err
+	}
+
+	if source, found := newJob.Annotations[jobhelpers.RerunOfKey]; found {
+		message := fmt.Sprintf("Job is a rerun of job %s", source)
+		if overrides, found := newJob.Annotations[jobhelpers.RerunOverridesKey]; found {
+			message += fmt.Sprintf(" with overrides %s", overrides)
+		}
+		events.Record(cc.recorder, newJob, events.Rerun, message)
+	}
+
 	return newJob, nil
 }
 
@@ -202,17 +429,23 @@ func (cc *jobcontroller) initOnJobUpdate(job *batch.Job) error {
 	klog.V(3).Infof("Starting to initiate Job <%s/%s> on update", job.Namespace, job.Name)
 
 	if err := cc.pluginOnJobUpdate(job); err != nil {
-		cc.recorder.Event(job, v1.EventTypeWarning, string(batch.PluginError),
+		events.Record(cc.recorder, job, events.PluginError,
 			fmt.Sprintf("Execute plugin when job add failed, err: %v", err))
 		return err
 	}
 
 	if err := cc.createOrUpdatePodGroup(job); err != nil {
-		cc.recorder.Event(job, v1.EventTypeWarning, string(batch.PodGroupError),
+		events.Record(cc.recorder, job, events.PodGroupError,
 			fmt.Sprintf("Failed to create PodGroup, err: %v", err))
 		return err
 	}
 
+	if err := cc.createOrUpdatePDBs(job); err != nil {
+		events.Record(cc.recorder, job, events.FailedCreatePDB,
+			fmt.Sprintf("Failed to update PodDisruptionBudget, err: %v", err))
+		return err
+	}
+
 	return nil
 }
 
@@ -264,6 +497,9 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 		if job, err = cc.initiateJob(job); err != nil {
 			return err
 		}
+		if err = cc.deleteQueueMigratedPods(jobInfo, job); err != nil {
+			return err
+		}
 	} else {
 		// TODO: optimize this call it only when scale up/down
 		if err = cc.initOnJobUpdate(job); err != nil {
@@ -271,6 +507,13 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 		}
 	}
 
+	cc.enqueuePodPendingTimeouts(job, jobInfo.Pods)
+
+	// the job is synced again on the update of its replicas
+	if shrunk, err := cc.shrinkOnReclaim(job, jobInfo.Pods); err != nil || shrunk {
+		return err
+	}
+
 	if len(queueInfo.Spec.ExtendClusters) != 0 {
 		jobForwarding = true
 		job.Annotations[batch.JobForwardingKey] = "true"
@@ -290,7 +533,6 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 		cc.recordPodGroupEvent(job, pg)
 	}
 
-	var jobCondition batch.JobCondition
 	oldStatus := job.Status
 	if !syncTask {
 		if updateStatus != nil {
@@ -301,9 +543,9 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 			klog.V(4).Infof("Job <%s/%s> has not updated for no changing", job.Namespace, job.Name)
 			return nil
 		}
-		job.Status.State.LastTransitionTime = metav1.Now()
-		jobCondition = newCondition(job.Status.State.Phase, &job.Status.State.LastTransitionTime)
-		job.Status.Conditions = append(job.Status.Conditions, jobCondition)
+		if job.Status.State.Phase != oldStatus.State.Phase {
+			setPhaseTransition(job)
+		}
 		newJob, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
 		if err != nil {
 			klog.Errorf("Failed to update status of Job %v/%v: %v",
@@ -320,9 +562,12 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 
 	var running, pending, terminating, succeeded, failed, unknown int32
 	taskStatusCount := make(map[string]batch.TaskState)
+	replicaStatus := make(map[string]map[string]jobhelpers.ReplicaStatus)
+	restarts := jobhelpers.GetReplicaRestarts(job)
+	checkpoints := jobhelpers.GetCheckpoints(job)
 
 	podToCreate := make(map[string][]*v1.Pod)
-	var podToDelete []*v1.Pod
+	var podToDelete, podToScaleDown []*v1.Pod
 	var creationErrs []error
 	var deletionErrs []error
 	appendMutex := sync.Mutex{}
@@ -345,11 +590,14 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 			pods = map[string]*v1.Pod{}
 		}
 
+		outdatedPods := getOutdatedPods(job, &ts, pods)
+
 		var podToCreateEachTask []*v1.Pod
 		for i := 0; i < int(ts.Replicas); i++ {
 			podName := fmt.Sprintf(jobhelpers.PodNameFmt, job.Name, name, i)
 			if pod, found := pods[podName]; !found {
 				newPod := createJobPod(job, tc, ts.TopologyPolicy, i, jobForwarding)
+				cc.propagation.Apply(job, newPod)
 				if err := cc.pluginOnPodCreate(job, newPod); err != nil {
 					return err
 				}
@@ -357,19 +605,41 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 				waitCreationGroup.Add(1)
 			} else {
 				delete(pods, podName)
+				// a preempted pod reports its checkpoint before it is deleted
+				recordCheckpoint(pod, checkpoints)
 				if pod.DeletionTimestamp != nil {
 					klog.Infof("Pod <%s/%s> is terminating", pod.Namespace, pod.Name)
 					atomic.AddInt32(&terminating, 1)
 					continue
 				}
 
+				if outdatedPods[podName] {
+					klog.V(3).Infof("Pod <%s/%s> is created from an outdated template, recreate it", pod.Namespace, pod.Name)
+					podToDelete = append(podToDelete, pod)
+					continue
+				}
+
 				classifyAndAddUpPodBaseOnPhase(pod, &pending, &running, &succeeded, &failed, &unknown)
 				calcPodStatus(pod, taskStatusCount)
+				calcReplicaStatus(pod, restarts, replicaStatus)
 			}
 		}
 		podToCreate[ts.Name] = podToCreateEachTask
+
+		// the pods left are beyond the replicas of the task, which is scaled down
+		var podToScaleDownEachTask []*v1.Pod
 		for _, pod := range pods {
-			podToDelete = append(podToDelete, pod)
+			if pod.DeletionTimestamp != nil {
+				atomic.AddInt32(&terminating, 1)
+				continue
+			}
+			podToScaleDownEachTask = append(podToScaleDownEachTask, pod)
+		}
+		if len(podToScaleDownEachTask) != 0 {
+			sortPodsByIndexDesc(podToScaleDownEachTask)
+			podToScaleDown = append(podToScaleDown, podToScaleDownEachTask...)
+			events.Record(cc.recorder, job, events.ScaledDown,
+				fmt.Sprintf("Scaled down task %s to %d replicas, deleting %d pods", name, ts.Replicas, len(podToScaleDownEachTask)))
 		}
 	}
 
@@ -395,6 +665,13 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 			for _, pod := range podToCreateEachTask {
 				go func(pod *v1.Pod) {
 					defer waitCreationGroup.Done()
+					index, _ := strconv.Atoi(pod.Annotations[batch.TaskIndex])
+					if err := cc.createReplicaPVCs(job, taskName, index); err != nil {
+						klog.Errorf("Failed to create PVCs of pod %s for Job %s, err %#v",
+							pod.Name, job.Name, err)
+						appendError(&creationErrs, fmt.Errorf("failed to create PVCs of pod %s, err: %#v", pod.Name, err))
+						return
+					}
 					newPod, err := cc.kubeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
 					if err != nil && !apierrors.IsAlreadyExists(err) {
 						// Failed to create Pod, waitCreationGroup a moment and then create it again
@@ -406,6 +683,7 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 					} else {
 						classifyAndAddUpPodBaseOnPhase(newPod, &pending, &running, &succeeded, &failed, &unknown)
 						calcPodStatus(newPod, taskStatusCount)
+						calcReplicaStatus(newPod, restarts, replicaStatus)
 						klog.V(5).Infof("Created Task <%s> of Job <%s/%s>",
 							pod.Name, job.Namespace, job.Name)
 					}
@@ -417,7 +695,7 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 	waitCreationGroup.Wait()
 
 	if len(creationErrs) != 0 {
-		cc.recorder.Event(job, v1.EventTypeWarning, FailedCreatePodReason,
+		events.Record(cc.recorder, job, events.FailedCreatePod,
 			fmt.Sprintf("Error creating pods: %+v", creationErrs))
 		return fmt.Errorf("failed to create %d pods of %d", len(creationErrs), len(podToCreate))
 	}
@@ -447,11 +725,31 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 	waitDeletionGroup.Wait()
 
 	if len(deletionErrs) != 0 {
-		cc.recorder.Event(job, v1.EventTypeWarning, FailedDeletePodReason,
+		events.Record(cc.recorder, job, events.FailedDeletePod,
 			fmt.Sprintf("Error deleting pods: %+v", deletionErrs))
 		return fmt.Errorf("failed to delete %d pods of %d", len(deletionErrs), len(podToDelete))
 	}
 
+	// Delete pods when scale down, highest index first, so that the peers with the lowest
+	// indexes, e.g. the rank 0 of the workers, are kept if the deletion fails halfway.
+	for _, pod := range podToScaleDown {
+		if err := cc.deleteJobPod(job.Name, pod); err != nil {
+			events.Record(cc.recorder, job, events.FailedDeletePod,
+				fmt.Sprintf("Error deleting pod %s when scaling down: %v", pod.Name, err))
+			cc.resyncTask(pod)
+			return err
+		}
+		klog.V(3).Infof("Deleted Task <%s> of Job <%s/%s> when scaling down",
+			pod.Name, job.Namespace, job.Name)
+		terminating++
+	}
+
+	// the replica status is written with the status of the job
+	replicaStatusChanged := jobhelpers.SetReplicaStatus(job, replicaStatus)
+	if job, err = cc.updateReplicaStatus(job, replicaStatusChanged, checkpoints); err != nil {
+		return err
+	}
+
 	newStatus := batch.JobStatus{
 		State: job.Status.State,
 
@@ -473,14 +771,15 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 		updateStatus(&newStatus)
 	}
 
-	if reflect.DeepEqual(job.Status, newStatus) {
+	if !replicaStatusChanged && reflect.DeepEqual(job.Status, newStatus) {
 		klog.V(3).Infof("Job <%s/%s> has not updated for no changing", job.Namespace, job.Name)
 		return nil
 	}
+	oldPhase := job.Status.State.Phase
 	job.Status = newStatus
-	job.Status.State.LastTransitionTime = metav1.Now()
-	jobCondition = newCondition(job.Status.State.Phase, &job.Status.State.LastTransitionTime)
-	job.Status.Conditions = append(job.Status.Conditions, jobCondition)
+	if job.Status.State.Phase != oldPhase {
+		setPhaseTransition(job)
+	}
 	newJob, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
 	if err != nil {
 		klog.Errorf("Failed to update status of Job %v/%v: %v",
@@ -492,10 +791,58 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 			newJob.Namespace, newJob.Name, e)
 		return e
 	}
+	cc.notifyJobUpdate(jobInfo.Job, newJob)
 
 	return nil
 }
 
+// updateReplicaStatus records the per-replica checkpoints, and the parallelism of a job which grows
+// after start, on the job if they have changed, and regenerates the peer lists of a growing job when
+// the status of its replicas has changed.
+func (cc *jobcontroller) updateReplicaStatus(job *batch.Job, statusChanged bool,
+	checkpoints map[string]map[string]jobhelpers.Checkpoint) (*batch.Job, error) {
+	checkpointsChanged := jobhelpers.SetCheckpoints(job, checkpoints)
+	grow := jobhelpers.IsGrowAfterStart(job)
+	var parallelism jobhelpers.Parallelism
+	parallelismChanged, currentChanged := false, false
+	if grow {
+		old, _ := jobhelpers.GetParallelism(job)
+		parallelism = jobhelpers.ComputeParallelism(job)
+		parallelismChanged = jobhelpers.SetParallelism(job, parallelism)
+		currentChanged = parallelism.Current != old.Current
+	}
+	newJob := job
+	if checkpointsChanged || parallelismChanged {
+		updated, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).Update(context.TODO(), job, metav1.UpdateOptions{})
+		if err != nil {
+			klog.Errorf("Failed to update replica status of Job %v/%v: %v",
+				job.Namespace, job.Name, err)
+			return nil, err
+		}
+		if e := cc.cache.Update(updated); e != nil {
+			klog.Errorf("SyncJob - Failed to update Job %v/%v in cache:  %v",
+				updated.Namespace, updated.Name, e)
+			return nil, e
+		}
+		newJob = updated.DeepCopy()
+		newJob.Status = job.Status
+	}
+
+	if currentChanged {
+		events.Recordf(cc.recorder, newJob, events.ParallelismChanged,
+			"Job runs %d of %d desired replicas", parallelism.Current, parallelism.Desired)
+	}
+	// the peer lists of a growing job only list the running replicas, regenerate them as replicas join
+	if grow && statusChanged {
+		if err := cc.pluginOnJobUpdate(newJob); err != nil {
+			events.Record(cc.recorder, newJob, events.PluginError,
+				fmt.Sprintf("Execute plugin when job replicas join failed, err: %v", err))
+			return nil, err
+		}
+	}
+	return newJob, nil
+}
+
 func (cc *jobcontroller) waitDependsOnTaskMeetCondition(taskIndex int, job *batch.Job) bool {
 	if job.Spec.Tasks[taskIndex].DependsOn == nil {
 		return true
@@ -575,6 +922,8 @@ func (cc *jobcontroller) createJobIOIfNotExist(job *batch.Job) (*batch.Job, erro
 	}
 	for index, volume := range job.Spec.Volumes {
 		vcName := volume.VolumeClaimName
+		// the PVCs of a per replica volume are created with the pods, named after the volume claim name
+		perReplica := jobhelpers.IsPerReplicaVolume(job, volume)
 		if len(vcName) == 0 {
 			// NOTE(k82cn): Ensure never have duplicated generated names.
 			for {
@@ -591,12 +940,12 @@ func (cc *jobcontroller) createJobIOIfNotExist(job *batch.Job) (*batch.Job, erro
 				break
 			}
 			// TODO: check VolumeClaim must be set if VolumeClaimName is empty
-			if volume.VolumeClaim != nil {
+			if volume.VolumeClaim != nil && !perReplica {
 				if err := cc.createPVC(job, vcName, volume.VolumeClaim); err != nil {
 					return job, err
 				}
 			}
-		} else {
+		} else if !perReplica {
 			exist, err := cc.checkPVCExist(job, vcName)
 			if err != nil {
 				return job, err
@@ -638,12 +987,16 @@ func (cc *jobcontroller) createPVC(job *batch.Job, vcName string, volumeClaim *v
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: job.Namespace,
 			Name:      vcName,
+			Labels: map[string]string{
+				batch.JobNameKey: job.Name,
+			},
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(job, helpers.JobKind),
 			},
 		},
 		Spec: *volumeClaim,
 	}
+	cc.propagation.Apply(job, pvc)
 
 	klog.V(3).Infof("Try to create PVC: %v", pvc)
 
@@ -655,6 +1008,55 @@ func (cc *jobcontroller) createPVC(job *batch.Job, vcName string, volumeClaim *v
 	return nil
 }
 
+// createReplicaPVCs creates the PVCs of the per replica volumes of the job for the pod, if not exist.
+func (cc *jobcontroller) createReplicaPVCs(job *batch.Job, taskName string, index int) error {
+	for _, volume := range job.Spec.Volumes {
+		if !jobhelpers.IsPerReplicaVolume(job, volume) {
+			continue
+		}
+
+		pvcName := jobhelpers.MakeReplicaPVCName(volume.VolumeClaimName, taskName, index)
+		exist, err := cc.checkPVCExist(job, pvcName)
+		if err != nil {
+			return err
+		}
+		if exist {
+			continue
+		}
+		if err := cc.createPVC(job, pvcName, volume.VolumeClaim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteJobPVCs deletes the PVCs created by the job controller for the volumes of the job.
+func (cc *jobcontroller) deleteJobPVCs(job *batch.Job) error {
+	pvcs, err := cc.pvcLister.PersistentVolumeClaims(job.Namespace).List(labels.SelectorFromSet(labels.Set{batch.JobNameKey: job.Name}))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, pvc := range pvcs {
+		if pvc.DeletionTimestamp != nil || !metav1.IsControlledBy(pvc, job) {
+			continue
+		}
+		if err := cc.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(context.TODO(), pvc.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to delete PVC <%s/%s> of Job %s: %v", pvc.Namespace, pvc.Name, job.Name, err)
+			errs = append(errs, err)
+			continue
+		}
+		klog.V(3).Infof("Deleted PVC <%s/%s> of finished Job %s", pvc.Namespace, pvc.Name, job.Name)
+	}
+	if len(errs) != 0 {
+		events.Record(cc.recorder, job, events.FailedDeletePVC,
+			fmt.Sprintf("Error deleting PVCs: %+v", errs))
+		return fmt.Errorf("failed to delete %d PVCs of %d", len(errs), len(pvcs))
+	}
+	return nil
+}
+
 func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 	// If PodGroup does not exist, create one for Job.
 	pgName := job.Name + "-" + string(job.UID)
@@ -678,6 +1080,9 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 
 			minTaskMember := map[string]int32{}
 			for _, task := range job.Spec.Tasks {
+				if !jobhelpers.IsGangTask(job, &task) {
+					continue
+				}
 				if task.MinAvailable != nil {
 					minTaskMember[task.Name] = *task.MinAvailable
 				} else {
@@ -685,19 +1090,24 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 				}
 			}
 
+			ctx, span := tracing.Start(tracing.Extract(context.TODO(), job.Annotations), "CreatePodGroup",
+				trace.WithAttributes(attribute.String("namespace", job.Namespace), attribute.String("job", job.Name)))
+			defer span.End()
+
 			pg := &scheduling.PodGroup{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: job.Namespace,
 					// add job.UID into its name when create new PodGroup
-					Name:        pgName,
-					Annotations: job.Annotations,
+					Name: pgName,
+					// the spans of the scheduler on the podgroup are children of its creation
+					Annotations: tracing.Inject(ctx, job.Annotations),
 					Labels:      job.Labels,
 					OwnerReferences: []metav1.OwnerReference{
 						*metav1.NewControllerRef(job, helpers.JobKind),
 					},
 				},
 				Spec: scheduling.PodGroupSpec{
-					MinMember:         job.Spec.MinAvailable,
+					MinMember:         jobhelpers.GetPodGroupMinMember(job),
 					MinTaskMember:     minTaskMember,
 					Queue:             job.Spec.Queue,
 					MinResources:      cc.calcPGMinResources(job),
@@ -705,10 +1115,11 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 				},
 			}
 
-			if _, err = cc.vcClient.SchedulingV1beta1().PodGroups(job.Namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+			if _, err = cc.vcClient.SchedulingV1beta1().PodGroups(job.Namespace).Create(ctx, pg, metav1.CreateOptions{}); err != nil {
 				if !apierrors.IsAlreadyExists(err) {
 					klog.Errorf("Failed to create PodGroup for Job <%s/%s>: %v",
 						job.Namespace, job.Name, err)
+					span.SetStatus(codes.Error, err.Error())
 					return err
 				}
 			}
@@ -717,14 +1128,27 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 	}
 
 	pgShouldUpdate := false
+	// a job is only moved to another queue before it starts running
+	var sourceQueue string
+	if pg.Spec.Queue != job.Spec.Queue {
+		if pg.Status.Phase == "" || pg.Status.Phase == scheduling.PodGroupPending || pg.Status.Phase == scheduling.PodGroupInqueue {
+			sourceQueue = pg.Spec.Queue
+			pg.Spec.Queue = job.Spec.Queue
+			pgShouldUpdate = true
+		} else {
+			events.Record(cc.recorder, job, events.FailedRequeue,
+				fmt.Sprintf("Job can not be moved to queue %s, its PodGroup is %s", job.Spec.Queue, pg.Status.Phase))
+		}
+	}
 	if pg.Spec.PriorityClassName != job.Spec.PriorityClassName {
 		pg.Spec.PriorityClassName = job.Spec.PriorityClassName
 		pgShouldUpdate = true
 	}
 
+	minMember := jobhelpers.GetPodGroupMinMember(job)
 	minResources := cc.calcPGMinResources(job)
-	if pg.Spec.MinMember != job.Spec.MinAvailable || !equality.Semantic.DeepEqual(pg.Spec.MinResources, minResources) {
-		pg.Spec.MinMember = job.Spec.MinAvailable
+	if pg.Spec.MinMember != minMember || !equality.Semantic.DeepEqual(pg.Spec.MinResources, minResources) {
+		pg.Spec.MinMember = minMember
 		pg.Spec.MinResources = minResources
 		pgShouldUpdate = true
 	}
@@ -735,6 +1159,14 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 	}
 
 	for _, task := range job.Spec.Tasks {
+		if !jobhelpers.IsGangTask(job, &task) {
+			if _, ok := pg.Spec.MinTaskMember[task.Name]; ok {
+				pgShouldUpdate = true
+				delete(pg.Spec.MinTaskMember, task.Name)
+			}
+			continue
+		}
+
 		cnt := task.Replicas
 		if task.MinAvailable != nil {
 			cnt = *task.MinAvailable
@@ -761,12 +1193,21 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 	if err != nil {
 		klog.V(3).Infof("Failed to update PodGroup for Job <%s/%s>: %v",
 			job.Namespace, job.Name, err)
+		return err
 	}
-	return err
+	if sourceQueue != "" {
+		events.Record(cc.recorder, job, events.Requeued,
+			fmt.Sprintf("Job is moved from queue %s to queue %s", sourceQueue, job.Spec.Queue))
+	}
+	return nil
 }
 
 func (cc *jobcontroller) deleteJobPod(jobName string, pod *v1.Pod) error {
-	err := cc.kubeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+	opts := metav1.DeleteOptions{}
+	if gracePeriod, found := jobhelpers.GetTerminationGracePeriod(pod); found {
+		opts.GracePeriodSeconds = &gracePeriod
+	}
+	err := cc.kubeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, opts)
 	if err != nil && !apierrors.IsNotFound(err) {
 		klog.Errorf("Failed to delete pod %s/%s for Job %s, err %#v",
 			pod.Namespace, pod.Name, jobName, err)
@@ -780,10 +1221,16 @@ func (cc *jobcontroller) deleteJobPod(jobName string, pod *v1.Pod) error {
 func (cc *jobcontroller) calcPGMinResources(job *batch.Job) *v1.ResourceList {
 	// sort task by priorityClasses
 	var tasksPriority TasksPriority
-	totalMinAvailable := int32(0)
 	for _, task := range job.Spec.Tasks {
+		// tasks of other schedulers are not gang scheduled with the job
+		if !jobhelpers.IsGangTask(job, &task) {
+			continue
+		}
 		tp := TaskPriority{0, task}
 		pc := task.Template.Spec.PriorityClassName
+		if pc == "" {
+			pc = job.Spec.PriorityClassName
+		}
 
 		if pc != "" {
 			priorityClass, err := cc.pcLister.Get(pc)
@@ -794,24 +1241,10 @@ func (cc *jobcontroller) calcPGMinResources(job *batch.Job) *v1.ResourceList {
 			}
 		}
 		tasksPriority = append(tasksPriority, tp)
-		if task.MinAvailable != nil { // actually, it can not be nil, because nil value will be patched in webhook
-			totalMinAvailable += *task.MinAvailable
-		} else {
-			totalMinAvailable += task.Replicas
-		}
 	}
+	sort.Sort(tasksPriority)
 
-	// see docs https://github.com/volcano-sh/volcano/pull/2945
-	// 1. job.MinAvailable < sum(task.MinAvailable), regard podgroup's min resource as sum of the first minAvailable,
-	// according to https://github.com/volcano-sh/volcano/blob/c91eb07f2c300e4d5c826ff11a63b91781b3ac11/pkg/scheduler/api/job_info.go#L738-L740
-	if job.Spec.MinAvailable < totalMinAvailable {
-		minReq := tasksPriority.CalcFirstCountResources(job.Spec.MinAvailable)
-		return &minReq
-	}
-
-	// 2. job.MinAvailable >= sum(task.MinAvailable)
-	minReq := tasksPriority.CalcPGMinResources(job.Spec.MinAvailable)
-
+	minReq := jobhelpers.CalcMinResources(job, tasksPriority.tasks())
 	return &minReq
 }
 
@@ -821,10 +1254,8 @@ func (cc *jobcontroller) initJobStatus(job *batch.Job) (*batch.Job, error) {
 	}
 
 	job.Status.State.Phase = batch.Pending
-	job.Status.State.LastTransitionTime = metav1.Now()
 	job.Status.MinAvailable = job.Spec.MinAvailable
-	jobCondition := newCondition(job.Status.State.Phase, &job.Status.State.LastTransitionTime)
-	job.Status.Conditions = append(job.Status.Conditions, jobCondition)
+	setPhaseTransition(job)
 	newJob, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
 	if err != nil {
 		klog.Errorf("Failed to update status of Job %v/%v: %v",
@@ -855,9 +1286,8 @@ func (cc *jobcontroller) recordPodGroupEvent(job *batch.Job, podGroup *schedulin
 
 	// If the latest condition is not scheduled, then a warning event is recorded
 	if latestCondition != nil && latestCondition.Type != scheduling.PodGroupScheduled {
-		cc.recorder.Eventf(job, v1.EventTypeWarning, string(batch.PodGroupPending),
-			fmt.Sprintf("PodGroup %s:%s %s, reason: %s", job.Namespace, job.Name,
-				strings.ToLower(string(latestCondition.Type)), latestCondition.Message))
+		events.Recordf(cc.recorder, job, events.PodGroupPending, "PodGroup %s:%s %s, reason: %s", job.Namespace, job.Name,
+			strings.ToLower(string(latestCondition.Type)), latestCondition.Message)
 	}
 }
 
@@ -904,6 +1334,63 @@ func calcPodStatus(pod *v1.Pod, taskStatusCount map[string]batch.TaskState) {
 	}
 }
 
+// recordCheckpoint records the checkpoint completed by the pod as the one its replica resumes from.
+func recordCheckpoint(pod *v1.Pod, checkpoints map[string]map[string]jobhelpers.Checkpoint) {
+	checkpoint, found := jobhelpers.GetPodCheckpoint(pod)
+	if !found {
+		return
+	}
+	taskName, found := pod.Annotations[batch.TaskSpecKey]
+	if !found {
+		return
+	}
+	index, found := pod.Annotations[batch.TaskIndex]
+	if !found {
+		return
+	}
+	if _, ok := checkpoints[taskName]; !ok {
+		checkpoints[taskName] = make(map[string]jobhelpers.Checkpoint)
+	}
+	if checkpoints[taskName][index] != checkpoint {
+		klog.V(3).Infof("Recorded checkpoint %s of Pod <%s/%s>", checkpoint.Path, pod.Namespace, pod.Name)
+	}
+	checkpoints[taskName][index] = checkpoint
+}
+
+func calcReplicaStatus(pod *v1.Pod, restarts map[string]int32, replicaStatus map[string]map[string]jobhelpers.ReplicaStatus) {
+	taskName, found := pod.Annotations[batch.TaskSpecKey]
+	if !found {
+		return
+	}
+	index, found := pod.Annotations[batch.TaskIndex]
+	if !found {
+		return
+	}
+
+	status := jobhelpers.ReplicaStatus{
+		Phase:    pod.Status.Phase,
+		NodeName: pod.Spec.NodeName,
+		Restarts: restarts[pod.Name],
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Terminated == nil {
+			continue
+		}
+		exitCode := containerStatus.State.Terminated.ExitCode
+		status.ExitCode = &exitCode
+		if exitCode != 0 {
+			break
+		}
+	}
+
+	calMutex.Lock()
+	defer calMutex.Unlock()
+	if _, ok := replicaStatus[taskName]; !ok {
+		replicaStatus[taskName] = make(map[string]jobhelpers.ReplicaStatus)
+	}
+	replicaStatus[taskName][index] = status
+}
+
 func isInitiated(job *batch.Job) bool {
 	if job.Status.State.Phase == "" || job.Status.State.Phase == batch.Pending {
 		return false
@@ -912,6 +1399,51 @@ func isInitiated(job *batch.Job) bool {
 	return true
 }
 
+// setPhaseTransition records the transition of the job to its current phase: the transition time,
+// the condition of the phase unless it is already the latest one, and the running duration.
+func setPhaseTransition(job *batch.Job) {
+	now := metav1.Now()
+	since := job.Status.State.LastTransitionTime
+	job.Status.State.LastTransitionTime = now
+	if n := len(job.Status.Conditions); n == 0 || job.Status.Conditions[n-1].Status != job.Status.State.Phase {
+		if n > 0 {
+			updateJobPhaseTransition(job.Status.Conditions[n-1].Status, job.Status.State.Phase, since.Time, now.Time)
+		}
+		job.Status.Conditions = append(job.Status.Conditions, newCondition(job.Status.State.Phase, &now))
+	}
+	job.Status.RunningDuration = &metav1.Duration{Duration: now.Sub(job.CreationTimestamp.Time)}
+}
+
+// setCommandReason records the reason and the message given with the command of the request in the state of the job,
+// if the action of the command moved the job to another phase, so that users can see why e.g. an admin aborted it.
+func (cc *jobcontroller) setCommandReason(req *apis.Request) error {
+	if req.Reason == "" && req.Message == "" {
+		return nil
+	}
+
+	jobInfo, err := cc.cache.Get(jobhelpers.GetJobKeyByReq(req))
+	if err != nil {
+		return err
+	}
+	if jobInfo.Job.Status.State.Reason != string(req.Action) {
+		return nil
+	}
+
+	job := jobInfo.Job.DeepCopy()
+	if req.Reason != "" {
+		job.Status.State.Reason = req.Reason
+	}
+	if req.Message != "" {
+		job.Status.State.Message = req.Message
+	}
+
+	newJob, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	return cc.cache.Update(newJob)
+}
+
 func newCondition(status batch.JobPhase, lastTransitionTime *metav1.Time) batch.JobCondition {
 	return batch.JobCondition{
 		Status:             status,
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	schedulingapi "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/state"
 )
 
@@ -179,6 +181,106 @@ func TestKillJobFunc(t *testing.T) {
 	}
 }
 
+func TestKillFinishedJobWithPodRetainPolicy(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name          string
+		Policy        string
+		ExpectPods    []string
+		ExpectSucceed int32
+		ExpectFailed  int32
+	}{
+		{
+			Name:          "retain all finished pods by default",
+			ExpectPods:    []string{"job1-task1-0", "job1-task1-1"},
+			ExpectSucceed: 1,
+			ExpectFailed:  1,
+		},
+		{
+			Name:          "retain failed pods",
+			Policy:        jobhelpers.PodRetainPolicyRetainFailed,
+			ExpectPods:    []string{"job1-task1-1"},
+			ExpectSucceed: 1,
+			ExpectFailed:  1,
+		},
+		{
+			Name:          "delete all finished pods",
+			Policy:        jobhelpers.PodRetainPolicyDeleteAll,
+			ExpectPods:    []string{},
+			ExpectSucceed: 1,
+			ExpectFailed:  1,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			fakeController := newFakeController()
+
+			job := &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job1",
+					Namespace: namespace,
+					UID:       "job1-uid",
+				},
+				Status: v1alpha1.JobStatus{
+					State: v1alpha1.JobState{Phase: v1alpha1.Completed},
+				},
+			}
+			if testcase.Policy != "" {
+				job.Annotations = map[string]string{jobhelpers.PodRetainPolicyKey: testcase.Policy}
+			}
+			pods := map[string]*v1.Pod{
+				"job1-task1-0": buildPod(namespace, "job1-task1-0", v1.PodSucceeded, nil),
+				"job1-task1-1": buildPod(namespace, "job1-task1-1", v1.PodFailed, nil),
+			}
+			for _, pod := range pods {
+				if _, err := fakeController.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("Error While Creating Pod: %v", err)
+				}
+			}
+			if _, err := fakeController.vcClient.BatchV1alpha1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("Error While Creating Job: %v", err)
+			}
+			if err := fakeController.cache.Add(job); err != nil {
+				t.Fatalf("Error While Adding Job in cache: %v", err)
+			}
+
+			jobInfo := &apis.JobInfo{
+				Namespace: namespace,
+				Name:      job.Name,
+				Job:       job,
+				Pods:      map[string]map[string]*v1.Pod{"task1": pods},
+			}
+			if err := fakeController.killJob(jobInfo, state.FinishedPodRetainPhase(job), nil); err != nil {
+				t.Fatalf("expected no error, but got %v", err)
+			}
+
+			podList, err := fakeController.kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("Error While Listing Pods: %v", err)
+			}
+			remaining := []string{}
+			for _, pod := range podList.Items {
+				remaining = append(remaining, pod.Name)
+			}
+			sort.Strings(remaining)
+			if !reflect.DeepEqual(remaining, testcase.ExpectPods) {
+				t.Errorf("expected remaining pods %v, but got %v", testcase.ExpectPods, remaining)
+			}
+
+			newJob, err := fakeController.vcClient.BatchV1alpha1().Jobs(namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Error While Getting Job: %v", err)
+			}
+			if newJob.Status.Succeeded != testcase.ExpectSucceed || newJob.Status.Failed != testcase.ExpectFailed {
+				t.Errorf("expected %d succeeded and %d failed, but got %d and %d", testcase.ExpectSucceed,
+					testcase.ExpectFailed, newJob.Status.Succeeded, newJob.Status.Failed)
+			}
+		})
+	}
+}
+
 func TestSyncJobFunc(t *testing.T) {
 	namespace := "test"
 
@@ -787,3 +889,229 @@ func TestRecordPodGroupEvent(t *testing.T) {
 	}
 
 }
+
+func TestCalcReplicaStatus(t *testing.T) {
+	namespace := "test"
+	pod := buildPod(namespace, "job1-worker-1", v1.PodFailed, nil)
+	pod.Annotations = map[string]string{
+		v1alpha1.TaskSpecKey: "worker",
+		v1alpha1.TaskIndex:   "1",
+	}
+	pod.Spec.NodeName = "node1"
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{
+		{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137}}},
+	}
+
+	replicaStatus := map[string]map[string]jobhelpers.ReplicaStatus{}
+	calcReplicaStatus(pod, map[string]int32{"job1-worker-1": 2}, replicaStatus)
+
+	status, found := replicaStatus["worker"]["1"]
+	if !found {
+		t.Fatalf("expected replica status of worker 1 to be recorded, got %v", replicaStatus)
+	}
+	if status.Phase != v1.PodFailed || status.NodeName != "node1" || status.Restarts != 2 {
+		t.Errorf("unexpected replica status %+v", status)
+	}
+	if status.ExitCode == nil || *status.ExitCode != 137 {
+		t.Errorf("expected exit code 137, got %v", status.ExitCode)
+	}
+}
+
+func TestSetPhaseTransition(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "test",
+			Name:              "job1",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+		},
+	}
+
+	for _, phase := range []v1alpha1.JobPhase{v1alpha1.Pending, v1alpha1.Running, v1alpha1.Running, v1alpha1.Completed} {
+		job.Status.State.Phase = phase
+		setPhaseTransition(job)
+	}
+
+	var phases []v1alpha1.JobPhase
+	for _, condition := range job.Status.Conditions {
+		phases = append(phases, condition.Status)
+	}
+	expected := []v1alpha1.JobPhase{v1alpha1.Pending, v1alpha1.Running, v1alpha1.Completed}
+	if !reflect.DeepEqual(phases, expected) {
+		t.Errorf("expected conditions %v, got %v", expected, phases)
+	}
+	if job.Status.RunningDuration == nil || job.Status.RunningDuration.Duration < time.Minute {
+		t.Errorf("expected running duration of at least one minute, got %v", job.Status.RunningDuration)
+	}
+	if completionTime := jobhelpers.GetJobCompletionTime(job); completionTime == nil || !completionTime.Equal(&job.Status.State.LastTransitionTime) {
+		t.Errorf("expected completion time %v, got %v", job.Status.State.LastTransitionTime, completionTime)
+	}
+}
+
+func TestReplicaPVCs(t *testing.T) {
+	namespace := "test"
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+			UID:       "job1-uid",
+			Annotations: map[string]string{
+				jobhelpers.PerReplicaVolumesKey:     "/data",
+				jobhelpers.VolumeRetentionPolicyKey: jobhelpers.VolumeRetentionPolicyDelete,
+			},
+		},
+		Spec: v1alpha1.JobSpec{
+			Volumes: []v1alpha1.VolumeSpec{
+				{MountPath: "/data", VolumeClaimName: "job1-pvc-data", VolumeClaim: &v1.PersistentVolumeClaimSpec{}},
+			},
+		},
+	}
+	fakeController := newFakeController()
+
+	for index := 0; index < 2; index++ {
+		if err := fakeController.createReplicaPVCs(job, "worker", index); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	}
+	// a PVC of the same job name not created by the job controller is kept
+	fakeController.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Create(context.TODO(), &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-pvc", Namespace: namespace, Labels: map[string]string{v1alpha1.JobNameKey: "job1"}},
+	}, metav1.CreateOptions{})
+
+	pvcs, _ := fakeController.kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	var names []string
+	for i := range pvcs.Items {
+		names = append(names, pvcs.Items[i].Name)
+		fakeController.pvcInformer.Informer().GetIndexer().Add(&pvcs.Items[i])
+	}
+	sort.Strings(names)
+	expected := []string{"job1-pvc-data-worker-0", "job1-pvc-data-worker-1", "user-pvc"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected PVCs %v, but got %v", expected, names)
+	}
+
+	if err := fakeController.deleteJobPVCs(job); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	pvcs, _ = fakeController.kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	if len(pvcs.Items) != 1 || pvcs.Items[0].Name != "user-pvc" {
+		t.Errorf("Expected only user-pvc to be kept, but got %v", pvcs.Items)
+	}
+}
+
+func TestDeleteQueueMigratedPods(t *testing.T) {
+	namespace := "test"
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+		Spec:       v1alpha1.JobSpec{Queue: "q2"},
+	}
+
+	newPod := func(name, queue, nodeName string) *v1.Pod {
+		pod := buildPod(namespace, name, v1.PodPending, nil)
+		pod.Annotations = map[string]string{v1alpha1.QueueNameKey: queue}
+		pod.Spec.NodeName = nodeName
+		return pod
+	}
+	pods := []*v1.Pod{
+		newPod("job1-worker-0", "q1", ""),
+		newPod("job1-worker-1", "q1", "node1"),
+		newPod("job1-worker-2", "q2", ""),
+	}
+
+	fakeController := newFakeController()
+	jobInfo := &apis.JobInfo{Job: job, Pods: map[string]map[string]*v1.Pod{"worker": {}}}
+	for _, pod := range pods {
+		fakeController.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		jobInfo.Pods["worker"][pod.Name] = pod
+	}
+
+	if err := fakeController.deleteQueueMigratedPods(jobInfo, job); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	podList, _ := fakeController.kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	var names []string
+	for _, pod := range podList.Items {
+		names = append(names, pod.Name)
+	}
+	sort.Strings(names)
+	expected := []string{"job1-worker-1", "job1-worker-2"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected pods %v to be kept, but got %v", expected, names)
+	}
+}
+
+func TestSetCommandReason(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name            string
+		State           v1alpha1.JobState
+		Request         apis.Request
+		ExpectedReason  string
+		ExpectedMessage string
+	}{
+		{
+			Name:  "reason and message of the abort are recorded",
+			State: v1alpha1.JobState{Phase: v1alpha1.Aborting, Reason: "AbortJob", Message: "Job is Aborting by action AbortJob"},
+			Request: apis.Request{
+				Action:  "AbortJob",
+				Reason:  "Maintenance",
+				Message: "node pool is drained for the upgrade",
+			},
+			ExpectedReason:  "Maintenance",
+			ExpectedMessage: "node pool is drained for the upgrade",
+		},
+		{
+			Name:  "message only keeps the reason of the action",
+			State: v1alpha1.JobState{Phase: v1alpha1.Aborting, Reason: "AbortJob", Message: "Job is Aborting by action AbortJob"},
+			Request: apis.Request{
+				Action:  "AbortJob",
+				Message: "quota exceeded",
+			},
+			ExpectedReason:  "AbortJob",
+			ExpectedMessage: "quota exceeded",
+		},
+		{
+			Name:  "state not set by the action is kept",
+			State: v1alpha1.JobState{Phase: v1alpha1.Completed, Reason: state.MinSuccessReachedReason, Message: "done"},
+			Request: apis.Request{
+				Action: "AbortJob",
+				Reason: "Maintenance",
+			},
+			ExpectedReason:  state.MinSuccessReachedReason,
+			ExpectedMessage: "done",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job := &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Status:     v1alpha1.JobStatus{State: testcase.State},
+			}
+			fakeController := newFakeController()
+			if _, err := fakeController.vcClient.BatchV1alpha1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("Expected no error when creating job, but got: %v", err)
+			}
+			if err := fakeController.cache.Add(job); err != nil {
+				t.Fatalf("Expected no error when adding job to cache, but got: %v", err)
+			}
+
+			req := testcase.Request
+			req.Namespace = namespace
+			req.JobName = job.Name
+			if err := fakeController.setCommandReason(&req); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			newJob, err := fakeController.vcClient.BatchV1alpha1().Jobs(namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Expected no error when getting job, but got: %v", err)
+			}
+			if newJob.Status.State.Reason != testcase.ExpectedReason || newJob.Status.State.Message != testcase.ExpectedMessage {
+				t.Errorf("Expected state reason %q and message %q, but got %q and %q", testcase.ExpectedReason,
+					testcase.ExpectedMessage, newJob.Status.State.Reason, newJob.Status.State.Message)
+			}
+		})
+	}
+}
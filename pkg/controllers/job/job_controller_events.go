@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+
+	"volcano.sh/volcano/pkg/controllers/events"
+	"volcano.sh/volcano/pkg/controllers/framework"
+)
+
+// podEventKey groups the pod events which are collapsed into one event of the job.
+type podEventKey struct {
+	namespace string
+	job       string
+	task      string
+	reason    string
+	message   string
+}
+
+// podEventSummary is the number of pods of a task which got the same event in a period.
+type podEventSummary struct {
+	podEventKey
+	pods int
+}
+
+// podEventAggregator collects the warning events of the job pods, so that thousands of
+// near-identical pod events of a large job are reported as one event per task.
+type podEventAggregator struct {
+	sync.Mutex
+	events map[podEventKey]sets.Set[string]
+}
+
+func newPodEventAggregator() *podEventAggregator {
+	return &podEventAggregator{events: map[podEventKey]sets.Set[string]{}}
+}
+
+// add records that the pod got the event; a pod is counted once per event however often it is repeated.
+func (a *podEventAggregator) add(key podEventKey, pod string) {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.events[key] == nil {
+		a.events[key] = sets.New[string]()
+	}
+	a.events[key].Insert(pod)
+}
+
+// flush returns the events collected since the last flush, ordered by the job, task and reason.
+func (a *podEventAggregator) flush() []podEventSummary {
+	a.Lock()
+	events := a.events
+	a.events = map[podEventKey]sets.Set[string]{}
+	a.Unlock()
+
+	summaries := make([]podEventSummary, 0, len(events))
+	for key, pods := range events {
+		summaries = append(summaries, podEventSummary{podEventKey: key, pods: pods.Len()})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		l, r := summaries[i], summaries[j]
+		if l.namespace != r.namespace {
+			return l.namespace < r.namespace
+		}
+		if l.job != r.job {
+			return l.job < r.job
+		}
+		if l.task != r.task {
+			return l.task < r.task
+		}
+		if l.reason != r.reason {
+			return l.reason < r.reason
+		}
+		return l.message < r.message
+	})
+	return summaries
+}
+
+// normalizePodEventMessage removes the name and the UID of the pod from the event message, so that
+// the same event of different pods, e.g. a container back-off, is grouped together.
+func normalizePodEventMessage(pod *v1.Pod, message string) string {
+	message = strings.ReplaceAll(message, string(pod.UID), "<uid>")
+	return strings.ReplaceAll(message, pod.Name, "<pod>")
+}
+
+// initPodEventInformer watches the warning events of the pods, which are aggregated
+// into events of the jobs every opt.JobEventAggregationPeriod.
+func (cc *jobcontroller) initPodEventInformer(opt *framework.ControllerOption) {
+	selector := fields.SelectorFromSet(fields.Set{
+		"involvedObject.kind": "Pod",
+		"type":                v1.EventTypeWarning,
+	}).String()
+	tweakListOptions := func(options *metav1.ListOptions) {
+		options.FieldSelector = selector
+	}
+	cc.eventInformerFactory = informers.NewSharedInformerFactoryWithOptions(cc.kubeClient, 0,
+		informers.WithNamespace(opt.Namespaces.InformerNamespace()),
+		informers.WithTweakListOptions(tweakListOptions))
+	if opt.Namespaces.NeedsListWatch() {
+		cc.eventInformerFactory.InformerFor(&v1.Event{}, func(client kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+			return opt.Namespaces.NewInformer(client.CoreV1().RESTClient(), "events", &v1.Event{}, resync, tweakListOptions)
+		})
+	}
+	cc.eventInformerFactory.Core().V1().Events().Informer().AddEventHandler(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: cc.addPodEvent,
+		UpdateFunc: func(_, newObj interface{}) {
+			cc.addPodEvent(newObj)
+		},
+	}))
+	cc.eventAggregator = newPodEventAggregator()
+	cc.eventAggregationPeriod = opt.JobEventAggregationPeriod
+}
+
+func (cc *jobcontroller) addPodEvent(obj interface{}) {
+	event, ok := obj.(*v1.Event)
+	if !ok {
+		klog.Errorf("Failed to convert %v to v1.Event", obj)
+		return
+	}
+
+	pod, err := cc.podLister.Pods(event.InvolvedObject.Namespace).Get(event.InvolvedObject.Name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to get pod %s/%s of event %s: %v",
+				event.InvolvedObject.Namespace, event.InvolvedObject.Name, event.Name, err)
+		}
+		return
+	}
+	if !isControlledBy(pod, helpers.JobKind) {
+		return
+	}
+
+	jobName, found := pod.Annotations[batch.JobNameKey]
+	if !found {
+		return
+	}
+	cc.eventAggregator.add(podEventKey{
+		namespace: pod.Namespace,
+		job:       jobName,
+		task:      pod.Annotations[batch.TaskSpecKey],
+		reason:    event.Reason,
+		message:   normalizePodEventMessage(pod, event.Message),
+	}, pod.Name)
+}
+
+// flushPodEvents records the pod events collected in the last period as events of the jobs.
+func (cc *jobcontroller) flushPodEvents() {
+	for _, summary := range cc.eventAggregator.flush() {
+		job, err := cc.jobLister.Jobs(summary.namespace).Get(summary.job)
+		if err != nil {
+			klog.V(4).Infof("Skipped the %s events of job %s/%s: %v", summary.reason, summary.namespace, summary.job, err)
+			continue
+		}
+		events.RecordWarning(cc.recorder, job, events.Reason(summary.reason), podEventSummaryMessage(job, summary))
+	}
+}
+
+// podEventSummaryMessage is the message of the job event, e.g.
+// "37/500 worker pods FailedScheduling: 0/100 nodes are available: insufficient nvidia.com/gpu".
+func podEventSummaryMessage(job *batch.Job, summary podEventSummary) string {
+	total := 0
+	for _, task := range job.Spec.Tasks {
+		if task.Name == summary.task {
+			total = int(task.Replicas)
+			break
+		}
+	}
+	if total < summary.pods {
+		total = summary.pods
+	}
+	return fmt.Sprintf("%d/%d %s pods %s: %s", summary.pods, total, summary.task, summary.reason, summary.message)
+}
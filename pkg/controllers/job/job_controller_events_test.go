@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestPodEventAggregator(t *testing.T) {
+	scheduling := podEventKey{namespace: "ns", job: "job", task: "worker", reason: "FailedScheduling", message: "insufficient nvidia.com/gpu"}
+	backOff := podEventKey{namespace: "ns", job: "job", task: "ps", reason: "BackOff", message: "Back-off restarting failed container"}
+
+	testcases := []struct {
+		Name     string
+		Events   map[podEventKey][]string
+		Expected []podEventSummary
+	}{
+		{
+			Name:     "no events",
+			Expected: []podEventSummary{},
+		},
+		{
+			Name: "repeated events of a pod are counted once",
+			Events: map[podEventKey][]string{
+				scheduling: {"job-worker-0", "job-worker-0", "job-worker-1"},
+			},
+			Expected: []podEventSummary{{podEventKey: scheduling, pods: 2}},
+		},
+		{
+			Name: "events are summarized per task",
+			Events: map[podEventKey][]string{
+				scheduling: {"job-worker-0", "job-worker-1", "job-worker-2"},
+				backOff:    {"job-ps-0"},
+			},
+			Expected: []podEventSummary{
+				{podEventKey: backOff, pods: 1},
+				{podEventKey: scheduling, pods: 3},
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			aggregator := newPodEventAggregator()
+			for key, pods := range testcase.Events {
+				for _, pod := range pods {
+					aggregator.add(key, pod)
+				}
+			}
+			if summaries := aggregator.flush(); !reflect.DeepEqual(summaries, testcase.Expected) {
+				t.Errorf("expected summaries %v, got %v", testcase.Expected, summaries)
+			}
+			if summaries := aggregator.flush(); len(summaries) != 0 {
+				t.Errorf("expected no summaries after the flush, got %v", summaries)
+			}
+		})
+	}
+}
+
+func TestPodEventSummaryMessage(t *testing.T) {
+	job := &batch.Job{
+		Spec: batch.JobSpec{
+			Tasks: []batch.TaskSpec{
+				{Name: "ps", Replicas: 1},
+				{Name: "worker", Replicas: 500},
+			},
+		},
+	}
+
+	testcases := []struct {
+		Name     string
+		Summary  podEventSummary
+		Expected string
+	}{
+		{
+			Name: "task of the job",
+			Summary: podEventSummary{
+				podEventKey: podEventKey{task: "worker", reason: "FailedScheduling", message: "insufficient nvidia.com/gpu"},
+				pods:        37,
+			},
+			Expected: "37/500 worker pods FailedScheduling: insufficient nvidia.com/gpu",
+		},
+		{
+			Name: "task removed from the job",
+			Summary: podEventSummary{
+				podEventKey: podEventKey{task: "chief", reason: "BackOff", message: "Back-off pulling image"},
+				pods:        2,
+			},
+			Expected: "2/2 chief pods BackOff: Back-off pulling image",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			if message := podEventSummaryMessage(job, testcase.Summary); message != testcase.Expected {
+				t.Errorf("expected message %q, got %q", testcase.Expected, message)
+			}
+		})
+	}
+}
+
+func TestNormalizePodEventMessage(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-worker-3", UID: "6d8d1d1e-1c1f-4d5e-9f55-8c1a8f0b3f2a"}}
+	message := "Back-off restarting failed container worker in pod job-worker-3_ns(6d8d1d1e-1c1f-4d5e-9f55-8c1a8f0b3f2a)"
+	expected := "Back-off restarting failed container worker in pod <pod>_ns(<uid>)"
+
+	if normalized := normalizePodEventMessage(pod, message); normalized != expected {
+		t.Errorf("expected message %q, got %q", expected, normalized)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -34,7 +35,9 @@ import (
 	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/controllers/apis"
 	jobcache "volcano.sh/volcano/pkg/controllers/cache"
+	"volcano.sh/volcano/pkg/controllers/events"
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/notification"
 )
 
 func (cc *jobcontroller) addCommand(obj interface{}) {
@@ -248,6 +251,11 @@ func (cc *jobcontroller) updatePod(oldObj, newObj interface{}) {
 			newPod.Namespace, newPod.Name, err)
 	}
 
+	if action := jobhelpers.SchedulerEvictAction(newPod); action != "" && jobhelpers.SchedulerEvictAction(oldPod) == "" {
+		cc.recordJobEvent(newPod.Namespace, jobName, podEvictionReason(action),
+			fmt.Sprintf("Pod %s of task %s is evicted by the scheduler, because of %s", newPod.Name, taskName, action))
+	}
+
 	event := bus.OutOfSyncEvent
 	var exitCode int32
 
@@ -267,6 +275,9 @@ func (cc *jobcontroller) updatePod(oldObj, newObj interface{}) {
 			event = bus.TaskCompletedEvent
 		}
 	case v1.PodPending, v1.PodRunning:
+		if newPod.Status.Phase == v1.PodRunning && oldPod.Status.Phase != v1.PodRunning {
+			recordPodStartSpan(newPod, time.Now())
+		}
 		if cc.cache.TaskFailed(jobcache.JobKeyByName(newPod.Namespace, jobName), taskName) {
 			event = bus.TaskFailedEvent
 		}
@@ -345,6 +356,28 @@ func (cc *jobcontroller) deletePod(obj interface{}) {
 		JobVersion: int32(dVersion),
 	}
 
+	// A preemptable pod evicted by the scheduler is recreated instead of triggering the policies of PodEvicted.
+	if jobhelpers.IsPreemptedPod(pod) {
+		klog.V(3).Infof("Pod <%s/%s> of Job %s is preempted by scheduler", pod.Namespace, pod.Name, jobName)
+		req.Event = bus.OutOfSyncEvent
+	}
+
+	if req.Event == bus.PodEvictedEvent && (cc.notifier != nil || pod.Spec.NodeName == "") {
+		if jobInfo, err := cc.cache.Get(jobcache.JobKeyByName(pod.Namespace, jobName)); err == nil && jobInfo.Job != nil {
+			if isQueueMigratedPod(jobInfo.Job, pod) {
+				// An unscheduled pod of a job moved to another queue is recreated in the new queue.
+				klog.V(3).Infof("Pod <%s/%s> of Job %s is moved to queue %s", pod.Namespace, pod.Name, jobName, jobInfo.Job.Spec.Queue)
+				req.Event = bus.OutOfSyncEvent
+			} else if cc.notifier != nil && jobInfo.Job.Status.State.Phase == batch.Running {
+				// Pods deleted by the controller itself are removed while the job is restarting or finishing,
+				// only deletions of a running job are reported as evictions.
+				event := notification.NewEvent(notification.PodEvicted, jobInfo.Job)
+				event.Pod = pod.Name
+				cc.notifier.Notify(event)
+			}
+		}
+	}
+
 	if err := cc.cache.DeletePod(pod); err != nil {
 		klog.Errorf("Failed to delete Pod <%s/%s>: %v in cache",
 			pod.Namespace, pod.Name, err)
@@ -355,14 +388,26 @@ func (cc *jobcontroller) deletePod(obj interface{}) {
 	queue.Add(req)
 }
 
-func (cc *jobcontroller) recordJobEvent(namespace, name string, event batch.JobEvent, message string) {
+// podEvictionReason returns the reason of the event of the eviction of a pod by the scheduler for the action.
+func podEvictionReason(action string) events.Reason {
+	switch action {
+	case "reclaim":
+		return events.PreemptedByQueueReclaim
+	case "preempt":
+		return events.Preempted
+	default:
+		return events.EvictedByScheduler
+	}
+}
+
+func (cc *jobcontroller) recordJobEvent(namespace, name string, reason events.Reason, message string) {
 	job, err := cc.cache.Get(jobcache.JobKeyByName(namespace, name))
 	if err != nil {
 		klog.Warningf("Failed to find job in cache when reporting job event <%s/%s>: %v",
 			namespace, name, err)
 		return
 	}
-	cc.recorder.Event(job.Job, v1.EventTypeNormal, string(event), message)
+	events.Record(cc.recorder, job.Job, reason, message)
 }
 
 func (cc *jobcontroller) handleCommands() {
@@ -386,14 +431,17 @@ func (cc *jobcontroller) processNextCommand() bool {
 		return true
 	}
 	cc.recordJobEvent(cmd.Namespace, cmd.TargetObject.Name,
-		batch.CommandIssued,
+		events.CommandIssued,
 		fmt.Sprintf(
-			"Start to execute command %s, and clean it up to make sure executed not more than once.", cmd.Action))
+			"Start to execute command %s, and clean it up to make sure executed not more than once.%s",
+			cmd.Action, commandReasonMessage(cmd)))
 	req := apis.Request{
 		Namespace: cmd.Namespace,
 		JobName:   cmd.TargetObject.Name,
 		Event:     bus.CommandIssuedEvent,
 		Action:    bus.Action(cmd.Action),
+		Reason:    cmd.Reason,
+		Message:   cmd.Message,
 	}
 
 	key := jobhelpers.GetJobKeyByReq(&req)
@@ -403,6 +451,18 @@ func (cc *jobcontroller) processNextCommand() bool {
 	return true
 }
 
+// commandReasonMessage returns the reason and the message given with the command, for its event.
+func commandReasonMessage(cmd *bus.Command) string {
+	var s string
+	if cmd.Reason != "" {
+		s += fmt.Sprintf(" Reason: %s.", cmd.Reason)
+	}
+	if cmd.Message != "" {
+		s += fmt.Sprintf(" Message: %s", cmd.Message)
+	}
+	return s
+}
+
 func (cc *jobcontroller) updatePodGroup(oldObj, newObj interface{}) {
 	oldPG, ok := oldObj.(*scheduling.PodGroup)
 	if !ok {
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+// buildPDBs returns the PodDisruptionBudgets expected by the job according to its pdb policy.
+func buildPDBs(job *batch.Job) []*policyv1.PodDisruptionBudget {
+	var pdbs []*policyv1.PodDisruptionBudget
+	switch jobhelpers.GetPDBPolicy(job) {
+	case jobhelpers.PDBPolicyJob:
+		pdbs = append(pdbs, newPDB(job, job.Name, job.Spec.MinAvailable, map[string]string{
+			batch.JobNameKey:      job.Name,
+			batch.JobNamespaceKey: job.Namespace,
+		}))
+	case jobhelpers.PDBPolicyTask:
+		for _, task := range job.Spec.Tasks {
+			minAvailable := task.Replicas
+			if task.MinAvailable != nil {
+				minAvailable = *task.MinAvailable
+			}
+			pdbs = append(pdbs, newPDB(job, fmt.Sprintf("%s-%s", job.Name, task.Name), minAvailable, map[string]string{
+				batch.JobNameKey:      job.Name,
+				batch.JobNamespaceKey: job.Namespace,
+				batch.TaskSpecKey:     task.Name,
+			}))
+		}
+	}
+	return pdbs
+}
+
+func newPDB(job *batch.Job, name string, minAvailable int32, selector map[string]string) *policyv1.PodDisruptionBudget {
+	min := intstr.FromInt32(minAvailable)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: job.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				batch.JobNameKey:      job.Name,
+				batch.JobNamespaceKey: job.Namespace,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, helpers.JobKind),
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &min,
+			Selector:     &metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+}
+
+// createOrUpdatePDBs keeps the PodDisruptionBudgets of the job in line with its minAvailable,
+// so that node drains do not evict more pods than the gang can tolerate.
+func (cc *jobcontroller) createOrUpdatePDBs(job *batch.Job) error {
+	for _, pdb := range buildPDBs(job) {
+		current, err := cc.pdbLister.PodDisruptionBudgets(pdb.Namespace).Get(pdb.Name)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("Failed to get PodDisruptionBudget %s for Job <%s/%s>: %v",
+					pdb.Name, job.Namespace, job.Name, err)
+				return err
+			}
+			cc.propagation.Apply(job, pdb)
+			if _, err := cc.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Create(context.TODO(), pdb, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+				klog.Errorf("Failed to create PodDisruptionBudget %s for Job <%s/%s>: %v",
+					pdb.Name, job.Namespace, job.Name, err)
+				return err
+			}
+			continue
+		}
+
+		if !metav1.IsControlledBy(current, job) {
+			klog.Warningf("PodDisruptionBudget <%s/%s> is not controlled by Job %s, skip it",
+				current.Namespace, current.Name, job.Name)
+			continue
+		}
+		if equality.Semantic.DeepEqual(current.Spec, pdb.Spec) {
+			continue
+		}
+
+		current = current.DeepCopy()
+		current.Spec = pdb.Spec
+		if _, err := cc.kubeClient.PolicyV1().PodDisruptionBudgets(current.Namespace).Update(context.TODO(), current, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("Failed to update PodDisruptionBudget %s for Job <%s/%s>: %v",
+				current.Name, job.Namespace, job.Name, err)
+			return err
+		}
+	}
+
+	return nil
+}
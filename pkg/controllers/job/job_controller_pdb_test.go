@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"testing"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+func TestCreateOrUpdatePDBs(t *testing.T) {
+	namespace := "test"
+	two := int32(2)
+
+	newJob := func(policy string) *batch.Job {
+		job := &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job1",
+				Namespace: namespace,
+				UID:       "job1-uid",
+			},
+			Spec: batch.JobSpec{
+				MinAvailable: 3,
+				Tasks: []batch.TaskSpec{
+					{Name: "ps", Replicas: 1},
+					{Name: "worker", Replicas: 4, MinAvailable: &two},
+				},
+			},
+		}
+		if policy != "" {
+			job.Annotations = map[string]string{jobhelpers.PDBPolicyKey: policy}
+		}
+		return job
+	}
+
+	testcases := []struct {
+		Name         string
+		Job          *batch.Job
+		ExistingPDB  *policyv1.PodDisruptionBudget
+		ExpectedPDBs map[string]int
+	}{
+		{
+			Name:         "no pdb policy",
+			Job:          newJob(""),
+			ExpectedPDBs: map[string]int{},
+		},
+		{
+			Name:         "job pdb policy",
+			Job:          newJob(jobhelpers.PDBPolicyJob),
+			ExpectedPDBs: map[string]int{"job1": 3},
+		},
+		{
+			Name:         "task pdb policy",
+			Job:          newJob(jobhelpers.PDBPolicyTask),
+			ExpectedPDBs: map[string]int{"job1-ps": 1, "job1-worker": 2},
+		},
+		{
+			Name:         "update minAvailable of existing pdb",
+			Job:          newJob(jobhelpers.PDBPolicyJob),
+			ExistingPDB:  newPDB(newJob(jobhelpers.PDBPolicyJob), "job1", 1, map[string]string{batch.JobNameKey: "job1"}),
+			ExpectedPDBs: map[string]int{"job1": 3},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			fakeController := newFakeController()
+
+			if testcase.ExistingPDB != nil {
+				if _, err := fakeController.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Create(context.TODO(), testcase.ExistingPDB, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("Failed to create PodDisruptionBudget: %v", err)
+				}
+				fakeController.pdbInformer.Informer().GetIndexer().Add(testcase.ExistingPDB)
+			}
+
+			if err := fakeController.createOrUpdatePDBs(testcase.Job); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			pdbs, err := fakeController.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("Failed to list PodDisruptionBudgets: %v", err)
+			}
+			if len(pdbs.Items) != len(testcase.ExpectedPDBs) {
+				t.Fatalf("Expected %d PodDisruptionBudgets, but got %d", len(testcase.ExpectedPDBs), len(pdbs.Items))
+			}
+			for _, pdb := range pdbs.Items {
+				minAvailable, found := testcase.ExpectedPDBs[pdb.Name]
+				if !found {
+					t.Errorf("Unexpected PodDisruptionBudget %s", pdb.Name)
+					continue
+				}
+				if pdb.Spec.MinAvailable == nil || *pdb.Spec.MinAvailable != intstr.FromInt(minAvailable) {
+					t.Errorf("Expected minAvailable %d of PodDisruptionBudget %s, but got %v", minAvailable, pdb.Name, pdb.Spec.MinAvailable)
+				}
+			}
+		})
+	}
+}
@@ -28,16 +28,18 @@ import (
 )
 
 func (cc *jobcontroller) pluginOnPodCreate(job *batch.Job, pod *v1.Pod) error {
-	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient}
+	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient, Propagation: cc.propagation}
 	for name, args := range job.Spec.Plugins {
 		pb, found := plugins.GetPluginBuilder(name)
 		if !found {
 			err := fmt.Errorf("failed to get plugin %s", name)
 			klog.Error(err)
+			updateJobPluginError(name, "NotFound")
 			return err
 		}
 		klog.Infof("Starting to execute plugin at <pluginOnPodCreate>: %s on job: <%s/%s>", name, job.Namespace, job.Name)
 		if err := pb(client, args).OnPodCreate(pod, job); err != nil {
+			updateJobPluginError(name, "OnPodCreate")
 			klog.Errorf("Failed to process on pod create plugin %s, err %v.", name, err)
 			return err
 		}
@@ -46,7 +48,7 @@ func (cc *jobcontroller) pluginOnPodCreate(job *batch.Job, pod *v1.Pod) error {
 }
 
 func (cc *jobcontroller) pluginOnJobAdd(job *batch.Job) error {
-	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient}
+	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient, Propagation: cc.propagation}
 	if job.Status.ControlledResources == nil {
 		job.Status.ControlledResources = make(map[string]string)
 	}
@@ -55,10 +57,12 @@ func (cc *jobcontroller) pluginOnJobAdd(job *batch.Job) error {
 		if !found {
 			err := fmt.Errorf("failed to get plugin %s", name)
 			klog.Error(err)
+			updateJobPluginError(name, "NotFound")
 			return err
 		}
 		klog.Infof("Starting to execute plugin at <pluginOnJobAdd>: %s on job: <%s/%s>", name, job.Namespace, job.Name)
 		if err := pb(client, args).OnJobAdd(job); err != nil {
+			updateJobPluginError(name, "OnJobAdd")
 			klog.Errorf("Failed to process on job add plugin %s, err %v.", name, err)
 			return err
 		}
@@ -71,16 +75,18 @@ func (cc *jobcontroller) pluginOnJobDelete(job *batch.Job) error {
 	if job.Status.ControlledResources == nil {
 		job.Status.ControlledResources = make(map[string]string)
 	}
-	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient}
+	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient, Propagation: cc.propagation}
 	for name, args := range job.Spec.Plugins {
 		pb, found := plugins.GetPluginBuilder(name)
 		if !found {
 			err := fmt.Errorf("failed to get plugin %s", name)
 			klog.Error(err)
+			updateJobPluginError(name, "NotFound")
 			return err
 		}
 		klog.Infof("Starting to execute plugin at <pluginOnJobDelete>: %s on job: <%s/%s>", name, job.Namespace, job.Name)
 		if err := pb(client, args).OnJobDelete(job); err != nil {
+			updateJobPluginError(name, "OnJobDelete")
 			klog.Errorf("failed to process on job delete plugin %s, err %v.", name, err)
 			return err
 		}
@@ -90,7 +96,7 @@ func (cc *jobcontroller) pluginOnJobDelete(job *batch.Job) error {
 }
 
 func (cc *jobcontroller) pluginOnJobUpdate(job *batch.Job) error {
-	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient}
+	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient, Propagation: cc.propagation}
 	if job.Status.ControlledResources == nil {
 		job.Status.ControlledResources = make(map[string]string)
 	}
@@ -99,10 +105,12 @@ func (cc *jobcontroller) pluginOnJobUpdate(job *batch.Job) error {
 		if !found {
 			err := fmt.Errorf("failed to get plugin %s", name)
 			klog.Error(err)
+			updateJobPluginError(name, "NotFound")
 			return err
 		}
 		klog.Infof("Starting to execute plugin at <pluginOnJobUpdate>: %s on job: <%s/%s>", name, job.Namespace, job.Name)
 		if err := pb(client, args).OnJobUpdate(job); err != nil {
+			updateJobPluginError(name, "OnJobUpdate")
 			klog.Errorf("Failed to process on job update plugin %s, err %v.", name, err)
 			return err
 		}
@@ -30,11 +30,11 @@ import (
 )
 
 func newRateLimitingQueue() workqueue.RateLimitingInterface {
-	return workqueue.NewRateLimitingQueue(workqueue.NewMaxOfRateLimiter(
+	return workqueue.NewNamedRateLimitingQueue(workqueue.NewMaxOfRateLimiter(
 		workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 180*time.Second),
 		// 10 qps, 100 bucket size.  This is only for retry speed and its only the overall factor (not per item)
 		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
-	))
+	), "job-resync")
 }
 
 func (cc *jobcontroller) processResyncTask() {
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/events"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/features"
+)
+
+// computeShrink returns the replicas each task is scaled down to, so that the replicas whose pods
+// the scheduler reclaims are removed, and the pods to delete instead because the task can not be
+// scaled down to remove them. As the pods of a task are indexed from 0, a scale down only removes
+// the requested pods at the tail of the task; the others are deleted and recreated, rather than
+// renumbered. The minAvailable of the tasks and of the job are kept.
+func computeShrink(job *batch.Job, pods map[string]map[string]*v1.Pod) (map[string]int32, []*v1.Pod) {
+	var total int32
+	for _, ts := range job.Spec.Tasks {
+		total += ts.Replicas
+	}
+
+	replicas := map[string]int32{}
+	var podToDelete []*v1.Pod
+	for _, ts := range job.Spec.Tasks {
+		requested := map[int32]*v1.Pod{}
+		for _, pod := range pods[ts.Name] {
+			if pod.DeletionTimestamp != nil || !apis.IsShrinkRequested(pod) {
+				continue
+			}
+			index := int32(getPodIndex(pod))
+			if index < 0 {
+				podToDelete = append(podToDelete, pod)
+				continue
+			}
+			// the pods beyond the replicas are deleted by the scale down of the task
+			if index < ts.Replicas {
+				requested[index] = pod
+			}
+		}
+		if len(requested) == 0 {
+			continue
+		}
+
+		newReplicas := ts.Replicas
+		for newReplicas > 0 {
+			if _, found := requested[newReplicas-1]; !found {
+				break
+			}
+			newReplicas--
+		}
+		if ts.MinAvailable != nil && newReplicas < *ts.MinAvailable {
+			newReplicas = *ts.MinAvailable
+		}
+		if reducible := total - job.Spec.MinAvailable; ts.Replicas-newReplicas > reducible {
+			newReplicas = ts.Replicas - max(reducible, 0)
+		}
+		if newReplicas < ts.Replicas {
+			replicas[ts.Name] = newReplicas
+			total -= ts.Replicas - newReplicas
+		}
+
+		// the pods kept by the scale down are evicted, and recreated by the controller
+		for index, pod := range requested {
+			if index < newReplicas {
+				podToDelete = append(podToDelete, pod)
+			}
+		}
+	}
+
+	sort.Slice(podToDelete, func(i, j int) bool {
+		return podToDelete[i].Name < podToDelete[j].Name
+	})
+	return replicas, podToDelete
+}
+
+// shrinkRequestedPods returns the pods the scheduler requested to shrink, which are evicted
+// rather than shrunk with the ElasticJobs feature disabled.
+func shrinkRequestedPods(pods map[string]map[string]*v1.Pod) []*v1.Pod {
+	var requested []*v1.Pod
+	for _, taskPods := range pods {
+		for _, pod := range taskPods {
+			if pod.DeletionTimestamp == nil && apis.IsShrinkRequested(pod) {
+				requested = append(requested, pod)
+			}
+		}
+	}
+	sort.Slice(requested, func(i, j int) bool {
+		return requested[i].Name < requested[j].Name
+	})
+	return requested
+}
+
+// shrinkOnReclaim scales the job down to remove the replicas whose pods the scheduler reclaims,
+// keeping the job running at a reduced parallelism. It returns whether the job is updated.
+func (cc *jobcontroller) shrinkOnReclaim(job *batch.Job, pods map[string]map[string]*v1.Pod) (bool, error) {
+	var replicas map[string]int32
+	var podToDelete []*v1.Pod
+	if utilfeature.DefaultFeatureGate.Enabled(features.ElasticJobs) {
+		replicas, podToDelete = computeShrink(job, pods)
+	} else {
+		podToDelete = shrinkRequestedPods(pods)
+	}
+
+	for _, pod := range podToDelete {
+		if err := cc.deleteJobPod(job.Name, pod); err != nil {
+			cc.resyncTask(pod)
+			return false, err
+		}
+		klog.V(3).Infof("Evicted Pod <%s/%s> of Job %s, its task can not be scaled down to remove it",
+			pod.Namespace, pod.Name, job.Name)
+	}
+	if len(replicas) == 0 {
+		return false, nil
+	}
+
+	newJob := job.DeepCopy()
+	if newJob.Annotations == nil {
+		newJob.Annotations = map[string]string{}
+	}
+	// the autoscaler does not add the replicas back right away
+	newJob.Annotations[jobhelpers.LastShrinkTimeKey] = time.Now().UTC().Format(time.RFC3339)
+	for i, ts := range newJob.Spec.Tasks {
+		if r, found := replicas[ts.Name]; found {
+			newJob.Spec.Tasks[i].Replicas = r
+			events.Record(cc.recorder, job, events.ShrunkOnReclaim,
+				fmt.Sprintf("Task %s is scaled down from %d to %d replicas to release the resources reclaimed by the scheduler",
+					ts.Name, ts.Replicas, r))
+		}
+	}
+	updated, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).Update(context.TODO(), newJob, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to scale down Job %v/%v on reclaim: %v", job.Namespace, job.Name, err)
+		return false, err
+	}
+	if e := cc.cache.Update(updated); e != nil {
+		klog.Errorf("ShrinkOnReclaim - Failed to update Job %v/%v in cache:  %v",
+			updated.Namespace, updated.Name, e)
+		return false, e
+	}
+	return true, nil
+}
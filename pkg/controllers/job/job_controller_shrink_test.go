@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+func TestComputeShrink(t *testing.T) {
+	taskMinAvailable := int32(2)
+	newJob := func(minAvailable int32) *batch.Job {
+		return &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default"},
+			Spec: batch.JobSpec{
+				MinAvailable: minAvailable,
+				Tasks: []batch.TaskSpec{
+					{Name: "master", Replicas: 1},
+					{Name: "worker", Replicas: 6, MinAvailable: &taskMinAvailable},
+				},
+			},
+		}
+	}
+	newPods := func(requested ...int) map[string]map[string]*v1.Pod {
+		pods := map[string]map[string]*v1.Pod{"master": {}, "worker": {}}
+		pods["master"]["job1-master-0"] = &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job1-master-0",
+			Annotations: map[string]string{batch.TaskIndex: "0"}}}
+		for i := 0; i < 6; i++ {
+			name := fmt.Sprintf("job1-worker-%d", i)
+			pods["worker"][name] = &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name,
+				Annotations: map[string]string{batch.TaskIndex: fmt.Sprint(i)}}}
+		}
+		for _, i := range requested {
+			pods["worker"][fmt.Sprintf("job1-worker-%d", i)].Annotations[apis.ShrinkRequestedKey] = "2024-01-01T00:00:00Z"
+		}
+		return pods
+	}
+
+	testCases := []struct {
+		name             string
+		job              *batch.Job
+		pods             map[string]map[string]*v1.Pod
+		expectedReplicas map[string]int32
+		expectedDeleted  []string
+	}{
+		{
+			name:             "no shrink requested",
+			job:              newJob(3),
+			pods:             newPods(),
+			expectedReplicas: map[string]int32{},
+		},
+		{
+			name:             "task is scaled down to remove the requested tail",
+			job:              newJob(3),
+			pods:             newPods(4, 5),
+			expectedReplicas: map[string]int32{"worker": 4},
+		},
+		{
+			name:             "pods below the requested tail are deleted instead",
+			job:              newJob(3),
+			pods:             newPods(1, 3, 5),
+			expectedReplicas: map[string]int32{"worker": 5},
+			expectedDeleted:  []string{"job1-worker-1", "job1-worker-3"},
+		},
+		{
+			name:             "task is not scaled down when its last pod is not requested",
+			job:              newJob(3),
+			pods:             newPods(2),
+			expectedReplicas: map[string]int32{},
+			expectedDeleted:  []string{"job1-worker-2"},
+		},
+		{
+			name:             "task is not scaled down below its minAvailable",
+			job:              newJob(3),
+			pods:             newPods(0, 1, 2, 3, 4, 5),
+			expectedReplicas: map[string]int32{"worker": 2},
+			expectedDeleted:  []string{"job1-worker-0", "job1-worker-1"},
+		},
+		{
+			name:             "job is not scaled down below its minAvailable",
+			job:              newJob(6),
+			pods:             newPods(3, 4, 5),
+			expectedReplicas: map[string]int32{"worker": 5},
+			expectedDeleted:  []string{"job1-worker-3", "job1-worker-4"},
+		},
+		{
+			name:             "job at its minAvailable is not scaled down",
+			job:              newJob(7),
+			pods:             newPods(5),
+			expectedReplicas: map[string]int32{},
+			expectedDeleted:  []string{"job1-worker-5"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replicas, podToDelete := computeShrink(tc.job, tc.pods)
+			if !reflect.DeepEqual(replicas, tc.expectedReplicas) {
+				t.Errorf("expected replicas %v, got %v", tc.expectedReplicas, replicas)
+			}
+			var deleted []string
+			for _, pod := range podToDelete {
+				deleted = append(deleted, pod.Name)
+			}
+			if !reflect.DeepEqual(deleted, tc.expectedDeleted) {
+				t.Errorf("expected deleted pods %v, got %v", tc.expectedDeleted, deleted)
+			}
+		})
+	}
+}
+
+func TestShrinkRequestedPods(t *testing.T) {
+	deleting := metav1.Now()
+	pods := map[string]map[string]*v1.Pod{
+		"master": {
+			"job1-master-0": {ObjectMeta: metav1.ObjectMeta{Name: "job1-master-0"}},
+		},
+		"worker": {
+			"job1-worker-0": {ObjectMeta: metav1.ObjectMeta{Name: "job1-worker-0"}},
+			"job1-worker-1": {ObjectMeta: metav1.ObjectMeta{Name: "job1-worker-1",
+				Annotations: map[string]string{apis.ShrinkRequestedKey: "2024-01-01T00:00:00Z"}}},
+			"job1-worker-2": {ObjectMeta: metav1.ObjectMeta{Name: "job1-worker-2", DeletionTimestamp: &deleting,
+				Annotations: map[string]string{apis.ShrinkRequestedKey: "2024-01-01T00:00:00Z"}}},
+			"job1-worker-3": {ObjectMeta: metav1.ObjectMeta{Name: "job1-worker-3",
+				Annotations: map[string]string{apis.ShrinkRequestedKey: "2024-01-01T00:00:00Z"}}},
+		},
+	}
+
+	var requested []string
+	for _, pod := range shrinkRequestedPods(pods) {
+		requested = append(requested, pod.Name)
+	}
+	expected := []string{"job1-worker-1", "job1-worker-3"}
+	if !reflect.DeepEqual(requested, expected) {
+		t.Errorf("expected requested pods %v, got %v", expected, requested)
+	}
+}
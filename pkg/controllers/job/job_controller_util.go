@@ -17,14 +17,18 @@ limitations under the License.
 package job
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -33,7 +37,9 @@ import (
 	schedulingv2 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/controllers/apis"
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
-	"volcano.sh/volcano/pkg/controllers/util"
+	"volcano.sh/volcano/pkg/controllers/job/state"
+	"volcano.sh/volcano/pkg/features"
+	"volcano.sh/volcano/pkg/util/tracing"
 )
 
 // MakePodName append podname,jobname,taskName and index and returns the string.
@@ -62,9 +68,17 @@ func createJobPod(job *batch.Job, template *v1.PodTemplateSpec, topologyPolicy b
 		pod.Spec.SchedulerName = job.Spec.SchedulerName
 	}
 
+	// If no priority class in Pod, inherit the priority class of Job.
+	if len(pod.Spec.PriorityClassName) == 0 {
+		pod.Spec.PriorityClassName = job.Spec.PriorityClassName
+	}
+
 	volumeMap := make(map[string]string)
 	for _, volume := range job.Spec.Volumes {
 		vcName := volume.VolumeClaimName
+		if jobhelpers.IsPerReplicaVolume(job, volume) {
+			vcName = jobhelpers.MakeReplicaPVCName(vcName, template.Name, ix)
+		}
 		name := fmt.Sprintf("%s-%s", job.Name, jobhelpers.GenRandomStr(12))
 		if _, ok := volumeMap[vcName]; !ok {
 			volume := v1.Volume{
@@ -111,6 +125,16 @@ func createJobPod(job *batch.Job, template *v1.PodTemplateSpec, topologyPolicy b
 	pod.Annotations[batch.PodTemplateKey] = fmt.Sprintf("%s-%s", job.Name, template.Name)
 	pod.Annotations[batch.JobRetryCountKey] = strconv.Itoa(int(job.Status.RetryCount))
 
+	pod.Annotations[jobhelpers.TemplateHashKey] = jobhelpers.ComputeTemplateHash(template)
+
+	if restarts := jobhelpers.GetReplicaRestarts(job)[pod.Name]; restarts > 0 {
+		pod.Annotations[jobhelpers.RestartCountKey] = strconv.Itoa(int(restarts))
+	}
+
+	if checkpoint, found := jobhelpers.GetCheckpoints(job)[tsKey][index]; found {
+		jobhelpers.SetResumeFrom(pod, checkpoint)
+	}
+
 	if topologyPolicy != "" {
 		pod.Annotations[schedulingv2.NumaPolicyKey] = string(topologyPolicy)
 	}
@@ -126,6 +150,28 @@ func createJobPod(job *batch.Job, template *v1.PodTemplateSpec, topologyPolicy b
 			pod.Annotations[schedulingv2.RevocableZone] = value
 		}
 
+		if value, found := job.Annotations[jobhelpers.TerminationGracePeriodKey]; found {
+			if _, exist := pod.Annotations[jobhelpers.TerminationGracePeriodKey]; !exist {
+				pod.Annotations[jobhelpers.TerminationGracePeriodKey] = value
+			}
+		}
+
+		// the scheduler evicts the pods as usual when the job can not be shrunk
+		if value, found := job.Annotations[apis.ShrinkOnReclaimKey]; found && utilfeature.DefaultFeatureGate.Enabled(features.ElasticJobs) {
+			pod.Annotations[apis.ShrinkOnReclaimKey] = value
+		}
+
+		if value, found := job.Annotations[apis.CheckpointGracePeriodKey]; found {
+			if _, exist := pod.Annotations[apis.CheckpointGracePeriodKey]; !exist {
+				pod.Annotations[apis.CheckpointGracePeriodKey] = value
+			}
+		}
+
+		// the spans of the scheduler on the pod are in the trace of the job
+		if value, found := job.Annotations[tracing.TraceParentKey]; found {
+			pod.Annotations[tracing.TraceParentKey] = value
+		}
+
 		if value, found := job.Annotations[schedulingv2.JDBMinAvailable]; found {
 			pod.Annotations[schedulingv2.JDBMinAvailable] = value
 		} else if value, found := job.Annotations[schedulingv2.JDBMaxUnavailable]; found {
@@ -218,6 +264,189 @@ func applyPolicies(job *batch.Job, req *apis.Request) v1alpha1.Action {
 	return v1alpha1.SyncJobAction
 }
 
+// getPodIndex returns the index of the pod in its task, -1 if it is unknown.
+func getPodIndex(pod *v1.Pod) int {
+	value, found := pod.Annotations[batch.TaskIndex]
+	if !found {
+		value = jobhelpers.GetPodIndexUnderTask(pod)
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
+// sortPodsByIndexDesc sorts the pods of a task by their index, highest first.
+func sortPodsByIndexDesc(pods []*v1.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return getPodIndex(pods[i]) > getPodIndex(pods[j])
+	})
+}
+
+// getOutdatedPods returns the pods of the task which are created from an outdated template and
+// can be recreated now, lowest index first, without exceeding the task's maxUnavailable.
+func getOutdatedPods(job *batch.Job, ts *batch.TaskSpec, pods map[string]*v1.Pod) map[string]bool {
+	outdated := map[string]bool{}
+	maxUnavailable, enabled := jobhelpers.GetRollingUpdateMaxUnavailable(job, ts.Replicas)
+	if !enabled {
+		return outdated
+	}
+
+	templateHash := jobhelpers.ComputeTemplateHash(&ts.Template)
+	available := int32(0)
+	var candidates []*v1.Pod
+	for i := 0; i < int(ts.Replicas); i++ {
+		pod, found := pods[jobhelpers.MakePodName(job.Name, ts.Name, i)]
+		if !found || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		// pods created before template hash is recorded are regarded as up to date.
+		hash, found := pod.Annotations[jobhelpers.TemplateHashKey]
+		isOutdated := found && hash != templateHash
+
+		switch pod.Status.Phase {
+		case v1.PodRunning:
+			available++
+			if isOutdated {
+				candidates = append(candidates, pod)
+			}
+		case v1.PodPending:
+			// pending pods are unavailable anyway, recreate them without consuming the budget.
+			if isOutdated {
+				outdated[pod.Name] = true
+			}
+		}
+	}
+
+	budget := maxUnavailable - (ts.Replicas - available)
+	for _, pod := range candidates {
+		if budget <= 0 {
+			break
+		}
+		outdated[pod.Name] = true
+		budget--
+	}
+	return outdated
+}
+
+// getDeferredTasks returns the tasks whose pods must be kept for now, because tasks ordered
+// before them in the termination order still have pods which are alive or being deleted.
+func getDeferredTasks(jobInfo *apis.JobInfo, order []string, retainPhase state.PhaseMap) map[string]bool {
+	deferred := map[string]bool{}
+	blocked := false
+	for _, taskName := range order {
+		if blocked {
+			deferred[taskName] = true
+			continue
+		}
+		for _, pod := range jobInfo.Pods[taskName] {
+			if _, retain := retainPhase[pod.Status.Phase]; !retain || pod.DeletionTimestamp != nil {
+				blocked = true
+				break
+			}
+		}
+	}
+	return deferred
+}
+
+// podPendingTimeout is how long the pods of a task have been pending and
+// the timeout of the PodPendingTimeout policy applied to the task.
+type podPendingTimeout struct {
+	pending time.Duration
+	timeout time.Duration
+}
+
+// expired returns whether the pods have been pending longer than the timeout.
+func (p podPendingTimeout) expired() bool {
+	return p.pending >= p.timeout
+}
+
+// next returns the duration until the timeout expires, or until it expires again
+// if it has already expired, so a policy which can not fix the job is raised once per timeout.
+func (p podPendingTimeout) next() time.Duration {
+	if !p.expired() {
+		return p.timeout - p.pending
+	}
+	return p.timeout - p.pending%p.timeout
+}
+
+// getPodPendingTimeout returns the timeout of the PodPendingTimeout policy applied to the task,
+// task level policies take precedence over job level ones.
+func getPodPendingTimeout(job *batch.Job, taskName string) (time.Duration, bool) {
+	for _, task := range job.Spec.Tasks {
+		if task.Name != taskName {
+			continue
+		}
+		for _, policy := range task.Policies {
+			if policy.Timeout != nil && checkEventExist(getEventlist(policy), apis.PodPendingTimeoutEvent) {
+				return policy.Timeout.Duration, true
+			}
+		}
+		break
+	}
+
+	for _, policy := range job.Spec.Policies {
+		if policy.Timeout != nil && checkEventExist(getEventlist(policy), apis.PodPendingTimeoutEvent) {
+			return policy.Timeout.Duration, true
+		}
+	}
+
+	return 0, false
+}
+
+// getPodPendingTimeouts returns the tasks which have unscheduled pods and a PodPendingTimeout policy.
+// A task whose pods are not created yet is pending since the job became Pending.
+func getPodPendingTimeouts(job *batch.Job, pods map[string]map[string]*v1.Pod, now time.Time) map[string]podPendingTimeout {
+	timeouts := map[string]podPendingTimeout{}
+	for _, task := range job.Spec.Tasks {
+		timeout, found := getPodPendingTimeout(job, task.Name)
+		if !found || timeout <= 0 {
+			continue
+		}
+
+		var pendingSince time.Time
+		for _, pod := range pods[task.Name] {
+			if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodPending || pod.Spec.NodeName != "" {
+				continue
+			}
+			if pendingSince.IsZero() || pod.CreationTimestamp.Time.Before(pendingSince) {
+				pendingSince = pod.CreationTimestamp.Time
+			}
+		}
+		if pendingSince.IsZero() && len(pods[task.Name]) == 0 && job.Status.State.Phase == batch.Pending {
+			pendingSince = job.Status.State.LastTransitionTime.Time
+			if pendingSince.IsZero() {
+				pendingSince = job.CreationTimestamp.Time
+			}
+		}
+		if pendingSince.IsZero() {
+			continue
+		}
+
+		timeouts[task.Name] = podPendingTimeout{
+			pending: now.Sub(pendingSince),
+			timeout: timeout,
+		}
+	}
+	return timeouts
+}
+
+// isFinishedPhase returns whether the job is finished.
+func isFinishedPhase(phase batch.JobPhase) bool {
+	switch phase {
+	case batch.Completed, batch.Failed, batch.Terminated:
+		return true
+	}
+	return false
+}
+
+// isFinishingPhase returns whether the job is finished or being finished.
+func isFinishingPhase(phase batch.JobPhase) bool {
+	return isFinishedPhase(phase) || phase == batch.Completing || phase == batch.Terminating
+}
+
 func getEventlist(policy batch.LifecyclePolicy) []v1alpha1.Event {
 	policyEventsList := policy.Events
 	if len(policy.Event) > 0 {
@@ -267,79 +496,43 @@ func isControlledBy(obj metav1.Object, gvk schema.GroupVersionKind) bool {
 // CalcFirstCountResources return the first count tasks resource, sorted by priority
 func (p TasksPriority) CalcFirstCountResources(count int32) v1.ResourceList {
 	sort.Sort(p)
-	minReq := v1.ResourceList{}
-
-	for _, task := range p {
-		if count <= task.Replicas {
-			minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, count))
-			break
-		} else {
-			minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, task.Replicas))
-			count -= task.Replicas
-		}
-	}
-	return minReq
+	return jobhelpers.CalcFirstCountResources(p.tasks(), count)
 }
 
 // CalcPGMinResources sums up all task's min available; if not enough, then fill up to jobMinAvailable via task's replicas
 func (p TasksPriority) CalcPGMinResources(jobMinAvailable int32) v1.ResourceList {
 	sort.Sort(p)
-	minReq := v1.ResourceList{}
-	podCnt := int32(0)
+	return jobhelpers.CalcTasksMinResources(p.tasks(), jobMinAvailable)
+}
 
-	// 1. first sum up those tasks whose MinAvailable is set
+func (p TasksPriority) tasks() []batch.TaskSpec {
+	tasks := make([]batch.TaskSpec, 0, len(p))
 	for _, task := range p {
-		if task.MinAvailable == nil { // actually, all task's min available is set by webhook
-			continue
-		}
-
-		validReplics := *task.MinAvailable
-		if left := jobMinAvailable - podCnt; left < validReplics {
-			validReplics = left
-		}
-		minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, validReplics))
-		podCnt += validReplics
-		if podCnt >= jobMinAvailable {
-			break
-		}
-	}
-
-	if podCnt >= jobMinAvailable {
-		return minReq
+		tasks = append(tasks, task.TaskSpec)
 	}
+	return tasks
+}
 
-	// 2. fill up the count of pod to jobMinAvailable with tasks whose replicas is not used up, higher priority first
-	leftCnt := jobMinAvailable - podCnt
-	for _, task := range p {
-		left := task.Replicas
-		if task.MinAvailable != nil {
-			if *task.MinAvailable == task.Replicas {
-				continue
-			} else {
-				left = task.Replicas - *task.MinAvailable
-			}
-		}
+// isQueueMigratedPod returns whether the pod is not scheduled yet and was created in a queue
+// other than the current queue of the job, i.e. the job was moved to another queue.
+func isQueueMigratedPod(job *batch.Job, pod *v1.Pod) bool {
+	queue, found := pod.Annotations[batch.QueueNameKey]
+	return found && pod.Spec.NodeName == "" && queue != job.Spec.Queue
+}
 
-		if leftCnt >= left {
-			minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, left))
-			leftCnt -= left
-		} else {
-			minReq = quotav1.Add(minReq, calTaskRequests(&v1.Pod{Spec: task.Template.Spec}, leftCnt))
-			leftCnt = 0
-		}
-		if leftCnt <= 0 {
-			break
+// podStartTime returns the time the pod starts to be started, i.e. since it is scheduled.
+func podStartTime(pod *v1.Pod) time.Time {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodScheduled && cond.Status == v1.ConditionTrue {
+			return cond.LastTransitionTime.Time
 		}
 	}
-	return minReq
+	return pod.CreationTimestamp.Time
 }
 
-// calTaskRequests returns requests resource with validReplica replicas
-func calTaskRequests(pod *v1.Pod, validReplica int32) v1.ResourceList {
-	minReq := v1.ResourceList{}
-	usage := *util.GetPodQuotaUsage(pod)
-	for i := int32(0); i < validReplica; i++ {
-		minReq = quotav1.Add(minReq, usage)
-	}
-	return minReq
+// recordPodStartSpan records the span from the scheduling of the pod to its running, in the trace of its job.
+func recordPodStartSpan(pod *v1.Pod, now time.Time) {
+	tracing.RecordSpan(tracing.Extract(context.TODO(), pod.Annotations), "StartPod", podStartTime(pod), now,
+		trace.WithAttributes(attribute.String("namespace", pod.Namespace), attribute.String("pod", pod.Name),
+			attribute.String("node", pod.Spec.NodeName)))
 }
@@ -19,6 +19,7 @@ package job
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -28,6 +29,8 @@ import (
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/state"
 )
 
 func TestMakePodName(t *testing.T) {
@@ -251,6 +254,48 @@ func TestCreateJobPod(t *testing.T) {
 	}
 }
 
+func TestCreateJobPodPriorityClass(t *testing.T) {
+	testcases := []struct {
+		Name              string
+		JobPriorityClass  string
+		TaskPriorityClass string
+		ExpectVal         string
+	}{
+		{
+			Name:             "inherit priority class of job",
+			JobPriorityClass: "high-priority",
+			ExpectVal:        "high-priority",
+		},
+		{
+			Name:              "priority class of task overrides job",
+			JobPriorityClass:  "high-priority",
+			TaskPriorityClass: "low-priority",
+			ExpectVal:         "low-priority",
+		},
+		{
+			Name:      "no priority class",
+			ExpectVal: "",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test"},
+				Spec:       batch.JobSpec{PriorityClassName: testcase.JobPriorityClass},
+			}
+			template := &v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Name: "task1"},
+				Spec:       v1.PodSpec{PriorityClassName: testcase.TaskPriorityClass},
+			}
+			pod := createJobPod(job, template, "", 0, false)
+			if pod.Spec.PriorityClassName != testcase.ExpectVal {
+				t.Errorf("expected priority class %q, but got %q", testcase.ExpectVal, pod.Spec.PriorityClassName)
+			}
+		})
+	}
+}
+
 func TestApplyPolicies(t *testing.T) {
 	namespace := "test"
 	errorCode0 := int32(0)
@@ -1070,3 +1115,410 @@ func TestCalcPGMinResources(t *testing.T) {
 
 	}
 }
+
+func TestGetDeferredTasks(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name        string
+		Pods        map[string]map[string]*v1.Pod
+		Order       []string
+		RetainPhase state.PhaseMap
+		ExpectVal   map[string]bool
+	}{
+		{
+			Name: "no termination order",
+			Pods: map[string]map[string]*v1.Pod{
+				"worker": {"job1-worker-0": buildPod(namespace, "job1-worker-0", v1.PodRunning, nil)},
+			},
+			RetainPhase: state.PodRetainPhaseNone,
+			ExpectVal:   map[string]bool{},
+		},
+		{
+			Name: "launcher waits for running workers",
+			Pods: map[string]map[string]*v1.Pod{
+				"worker":   {"job1-worker-0": buildPod(namespace, "job1-worker-0", v1.PodRunning, nil)},
+				"launcher": {"job1-launcher-0": buildPod(namespace, "job1-launcher-0", v1.PodRunning, nil)},
+			},
+			Order:       []string{"worker", "launcher"},
+			RetainPhase: state.PodRetainPhaseNone,
+			ExpectVal:   map[string]bool{"launcher": true},
+		},
+		{
+			Name: "retained workers do not block launcher",
+			Pods: map[string]map[string]*v1.Pod{
+				"worker":   {"job1-worker-0": buildPod(namespace, "job1-worker-0", v1.PodSucceeded, nil)},
+				"launcher": {"job1-launcher-0": buildPod(namespace, "job1-launcher-0", v1.PodRunning, nil)},
+			},
+			Order:       []string{"worker", "launcher"},
+			RetainPhase: state.PodRetainPhaseSoft,
+			ExpectVal:   map[string]bool{},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			jobInfo := &apis.JobInfo{Namespace: namespace, Name: "job1", Pods: testcase.Pods}
+			deferred := getDeferredTasks(jobInfo, testcase.Order, testcase.RetainPhase)
+			if !reflect.DeepEqual(deferred, testcase.ExpectVal) {
+				t.Errorf("expected %v, but got %v", testcase.ExpectVal, deferred)
+			}
+		})
+	}
+}
+
+func TestGetOutdatedPods(t *testing.T) {
+	namespace := "test"
+	ts := batch.TaskSpec{
+		Name:     "worker",
+		Replicas: 3,
+		Template: v1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "worker", Image: "worker:v2"}},
+			},
+		},
+	}
+	buildOutdatedPod := func(name string, phase v1.PodPhase) *v1.Pod {
+		pod := buildPod(namespace, name, phase, nil)
+		pod.Annotations = map[string]string{jobhelpers.TemplateHashKey: "outdated"}
+		return pod
+	}
+
+	testcases := []struct {
+		Name        string
+		Annotations map[string]string
+		Pods        map[string]*v1.Pod
+		ExpectVal   map[string]bool
+	}{
+		{
+			Name: "rolling update disabled",
+			Pods: map[string]*v1.Pod{
+				"job1-worker-0": buildOutdatedPod("job1-worker-0", v1.PodRunning),
+			},
+			ExpectVal: map[string]bool{},
+		},
+		{
+			Name:        "recreate lowest index first",
+			Annotations: map[string]string{jobhelpers.RollingUpdateMaxUnavailableKey: "1"},
+			Pods: map[string]*v1.Pod{
+				"job1-worker-0": buildOutdatedPod("job1-worker-0", v1.PodRunning),
+				"job1-worker-1": buildOutdatedPod("job1-worker-1", v1.PodRunning),
+				"job1-worker-2": buildOutdatedPod("job1-worker-2", v1.PodRunning),
+			},
+			ExpectVal: map[string]bool{"job1-worker-0": true},
+		},
+		{
+			Name:        "no budget while a replica is missing",
+			Annotations: map[string]string{jobhelpers.RollingUpdateMaxUnavailableKey: "1"},
+			Pods: map[string]*v1.Pod{
+				"job1-worker-1": buildOutdatedPod("job1-worker-1", v1.PodRunning),
+				"job1-worker-2": buildOutdatedPod("job1-worker-2", v1.PodPending),
+			},
+			ExpectVal: map[string]bool{"job1-worker-2": true},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace, Annotations: testcase.Annotations},
+			}
+			outdated := getOutdatedPods(job, &ts, testcase.Pods)
+			if !reflect.DeepEqual(outdated, testcase.ExpectVal) {
+				t.Errorf("expected %v, but got %v", testcase.ExpectVal, outdated)
+			}
+		})
+	}
+}
+
+func TestGetPodPendingTimeouts(t *testing.T) {
+	namespace := "test"
+	now := time.Now()
+	buildPendingPod := func(name string, age time.Duration, nodeName string) *v1.Pod {
+		pod := buildPod(namespace, name, v1.PodPending, nil)
+		pod.CreationTimestamp = metav1.NewTime(now.Add(-age))
+		pod.Spec.NodeName = nodeName
+		return pod
+	}
+	timeoutPolicy := func(timeout time.Duration) []batch.LifecyclePolicy {
+		return []batch.LifecyclePolicy{{
+			Event:   apis.PodPendingTimeoutEvent,
+			Action:  busv1alpha1.AbortJobAction,
+			Timeout: &metav1.Duration{Duration: timeout},
+		}}
+	}
+
+	testcases := []struct {
+		Name        string
+		Phase       batch.JobPhase
+		JobPolicies []batch.LifecyclePolicy
+		PsPolicies  []batch.LifecyclePolicy
+		Pods        map[string]map[string]*v1.Pod
+		ExpectVal   map[string]podPendingTimeout
+	}{
+		{
+			Name:  "no pod pending timeout policy",
+			Phase: batch.Running,
+			Pods: map[string]map[string]*v1.Pod{
+				"worker": {"job1-worker-0": buildPendingPod("job1-worker-0", time.Hour, "")},
+			},
+			ExpectVal: map[string]podPendingTimeout{},
+		},
+		{
+			Name:        "scheduled pods are not pending",
+			Phase:       batch.Running,
+			JobPolicies: timeoutPolicy(time.Minute),
+			Pods: map[string]map[string]*v1.Pod{
+				"ps":     {"job1-ps-0": buildPendingPod("job1-ps-0", time.Hour, "node1")},
+				"worker": {"job1-worker-0": buildPendingPod("job1-worker-0", time.Hour, "node1")},
+			},
+			ExpectVal: map[string]podPendingTimeout{},
+		},
+		{
+			Name:        "task level policy takes precedence",
+			Phase:       batch.Running,
+			JobPolicies: timeoutPolicy(time.Minute),
+			PsPolicies:  timeoutPolicy(time.Hour),
+			Pods: map[string]map[string]*v1.Pod{
+				"ps": {"job1-ps-0": buildPendingPod("job1-ps-0", 10*time.Minute, "")},
+				"worker": {
+					"job1-worker-0": buildPendingPod("job1-worker-0", 10*time.Minute, "node1"),
+					"job1-worker-1": buildPendingPod("job1-worker-1", 5*time.Minute, ""),
+				},
+			},
+			ExpectVal: map[string]podPendingTimeout{
+				"ps":     {pending: 10 * time.Minute, timeout: time.Hour},
+				"worker": {pending: 5 * time.Minute, timeout: time.Minute},
+			},
+		},
+		{
+			Name:        "pending job without pods",
+			Phase:       batch.Pending,
+			JobPolicies: timeoutPolicy(time.Minute),
+			Pods:        map[string]map[string]*v1.Pod{},
+			ExpectVal: map[string]podPendingTimeout{
+				"ps":     {pending: 2 * time.Minute, timeout: time.Minute},
+				"worker": {pending: 2 * time.Minute, timeout: time.Minute},
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Spec: batch.JobSpec{
+					Policies: testcase.JobPolicies,
+					Tasks: []batch.TaskSpec{
+						{Name: "ps", Replicas: 1, Policies: testcase.PsPolicies},
+						{Name: "worker", Replicas: 2},
+					},
+				},
+				Status: batch.JobStatus{
+					State: batch.JobState{
+						Phase:              testcase.Phase,
+						LastTransitionTime: metav1.NewTime(now.Add(-2 * time.Minute)),
+					},
+				},
+			}
+			timeouts := getPodPendingTimeouts(job, testcase.Pods, now)
+			if !reflect.DeepEqual(timeouts, testcase.ExpectVal) {
+				t.Errorf("expected %v, but got %v", testcase.ExpectVal, timeouts)
+			}
+		})
+	}
+}
+
+func TestPodPendingTimeoutNext(t *testing.T) {
+	testcases := []struct {
+		Name      string
+		Timeout   podPendingTimeout
+		ExpectVal time.Duration
+	}{
+		{
+			Name:      "not expired",
+			Timeout:   podPendingTimeout{pending: 20 * time.Second, timeout: time.Minute},
+			ExpectVal: 40 * time.Second,
+		},
+		{
+			Name:      "expired is raised again after another timeout",
+			Timeout:   podPendingTimeout{pending: 80 * time.Second, timeout: time.Minute},
+			ExpectVal: 40 * time.Second,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			if next := testcase.Timeout.next(); next != testcase.ExpectVal {
+				t.Errorf("expected %v, but got %v", testcase.ExpectVal, next)
+			}
+		})
+	}
+}
+
+func TestCreateJobPodPerReplicaVolume(t *testing.T) {
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job1",
+			Namespace:   "test",
+			Annotations: map[string]string{jobhelpers.PerReplicaVolumesKey: "/data"},
+		},
+		Spec: batch.JobSpec{
+			Volumes: []batch.VolumeSpec{
+				{MountPath: "/data", VolumeClaimName: "job1-pvc-data", VolumeClaim: &v1.PersistentVolumeClaimSpec{}},
+				{MountPath: "/shared", VolumeClaimName: "job1-pvc-shared", VolumeClaim: &v1.PersistentVolumeClaimSpec{}},
+			},
+		},
+	}
+	template := &v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "worker"}}},
+	}
+
+	pod := createJobPod(job, template, "", 2, false)
+
+	claims := map[string]string{}
+	for _, volume := range pod.Spec.Volumes {
+		claims[volume.Name] = volume.PersistentVolumeClaim.ClaimName
+	}
+	mounts := map[string]string{}
+	for _, mount := range pod.Spec.Containers[0].VolumeMounts {
+		mounts[mount.MountPath] = claims[mount.Name]
+	}
+	expected := map[string]string{"/data": "job1-pvc-data-worker-2", "/shared": "job1-pvc-shared"}
+	if !reflect.DeepEqual(mounts, expected) {
+		t.Errorf("expected claims %v, got %v", expected, mounts)
+	}
+}
+
+func TestGetPodGroupMinMember(t *testing.T) {
+	task := func(name, schedulerName string, replicas int32) batch.TaskSpec {
+		return batch.TaskSpec{
+			Name:     name,
+			Replicas: replicas,
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{SchedulerName: schedulerName}},
+		}
+	}
+
+	testcases := []struct {
+		Name         string
+		MinAvailable int32
+		Tasks        []batch.TaskSpec
+		Policy       string
+		ExpectVal    int32
+	}{
+		{
+			Name:         "all tasks are gang scheduled",
+			MinAvailable: 3,
+			Tasks:        []batch.TaskSpec{task("ps", "", 1), task("worker", "volcano", 2)},
+			ExpectVal:    3,
+		},
+		{
+			Name:         "tasks of other schedulers are excluded",
+			MinAvailable: 4,
+			Tasks:        []batch.TaskSpec{task("worker", "", 2), task("evaluator", "default-scheduler", 2)},
+			ExpectVal:    2,
+		},
+		{
+			Name:         "minAvailable below the gang replicas is kept",
+			MinAvailable: 1,
+			Tasks:        []batch.TaskSpec{task("worker", "", 2), task("evaluator", "default-scheduler", 2)},
+			ExpectVal:    1,
+		},
+		{
+			Name:         "all the gang replicas with the Replicas policy",
+			MinAvailable: 1,
+			Tasks:        []batch.TaskSpec{task("worker", "", 4), task("evaluator", "default-scheduler", 2)},
+			Policy:       jobhelpers.ElasticMinMemberPolicyReplicas,
+			ExpectVal:    4,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job := &batch.Job{
+				Spec: batch.JobSpec{
+					SchedulerName: "volcano",
+					MinAvailable:  testcase.MinAvailable,
+					Tasks:         testcase.Tasks,
+				},
+			}
+			if testcase.Policy != "" {
+				job.Annotations = map[string]string{jobhelpers.ElasticMinMemberPolicyKey: testcase.Policy}
+			}
+			if minMember := jobhelpers.GetPodGroupMinMember(job); minMember != testcase.ExpectVal {
+				t.Errorf("expected %v, but got %v", testcase.ExpectVal, minMember)
+			}
+		})
+	}
+}
+
+func TestSortPodsByIndexDesc(t *testing.T) {
+	pod := func(name, index string) *v1.Pod {
+		p := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}}}
+		if index != "" {
+			p.Annotations[batch.TaskIndex] = index
+		}
+		return p
+	}
+	pods := []*v1.Pod{
+		pod("job1-worker-2", "2"),
+		pod("job1-worker-10", "10"),
+		pod("job1-worker-3", ""),
+		pod("job1-worker-9", "9"),
+	}
+
+	sortPodsByIndexDesc(pods)
+
+	var names []string
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	expected := []string{"job1-worker-10", "job1-worker-9", "job1-worker-3", "job1-worker-2"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestPodStartTime(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduled := created.Add(time.Minute)
+	testcases := []struct {
+		Name       string
+		Conditions []v1.PodCondition
+		Expected   time.Time
+	}{
+		{
+			Name:     "not scheduled",
+			Expected: created,
+		},
+		{
+			Name: "unschedulable",
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionFalse, LastTransitionTime: metav1.NewTime(scheduled)},
+			},
+			Expected: created,
+		},
+		{
+			Name: "scheduled",
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodInitialized, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(scheduled.Add(time.Second))},
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(scheduled)},
+			},
+			Expected: scheduled,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+				Status:     v1.PodStatus{Conditions: testcase.Conditions},
+			}
+			if ret := podStartTime(pod); !ret.Equal(testcase.Expected) {
+				t.Errorf("expected %v, got %v", testcase.Expected, ret)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	batchlister "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
+)
+
+var (
+	jobPhaseTransitionLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: "volcano",
+			Name:      "job_phase_transition_duration_seconds",
+			Help:      "Time spent by the jobs in a phase before moving to the next one",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 16),
+		}, []string{"from", "to"},
+	)
+
+	jobPluginErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "volcano",
+			Name:      "job_plugin_errors_total",
+			Help:      "Number of failed executions of the job plugins",
+		}, []string{"plugin", "event"},
+	)
+
+	jobsDesc = prometheus.NewDesc(
+		"volcano_jobs",
+		"Number of jobs by phase and queue",
+		[]string{"phase", "queue"}, nil,
+	)
+)
+
+// updateJobPhaseTransition records the time the job spent in its previous phase, since its last transition.
+func updateJobPhaseTransition(from, to batch.JobPhase, since, now time.Time) {
+	if from == "" || since.IsZero() {
+		return
+	}
+	jobPhaseTransitionLatency.WithLabelValues(string(from), string(to)).Observe(now.Sub(since).Seconds())
+}
+
+// updateJobPluginError records a failed execution of the plugin at the event, e.g. OnJobAdd.
+func updateJobPluginError(plugin, event string) {
+	jobPluginErrors.WithLabelValues(plugin, event).Inc()
+}
+
+// jobCollector counts the jobs of the informer cache by phase and queue when the metrics are scraped,
+// so that the counts stay right however the jobs were added or deleted.
+type jobCollector struct {
+	lister batchlister.JobLister
+}
+
+// registerJobCollector registers the collector of the job counts; only the first job controller registers it.
+func registerJobCollector(lister batchlister.JobLister) {
+	if err := prometheus.Register(&jobCollector{lister: lister}); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			klog.Errorf("Failed to register the job metrics: %v", err)
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *jobCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- jobsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *jobCollector) Collect(ch chan<- prometheus.Metric) {
+	jobs, err := c.lister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list jobs for the metrics: %v", err)
+		return
+	}
+
+	type key struct {
+		phase batch.JobPhase
+		queue string
+	}
+	counts := map[key]int{}
+	for _, job := range jobs {
+		phase := job.Status.State.Phase
+		if phase == "" {
+			phase = batch.Pending
+		}
+		counts[key{phase: phase, queue: job.Spec.Queue}]++
+	}
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(jobsDesc, prometheus.GaugeValue, float64(count), string(k.phase), k.queue)
+	}
+}
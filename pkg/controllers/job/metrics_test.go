@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	batchlister "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
+)
+
+func TestJobCollector(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, job := range []*batch.Job{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}, Spec: batch.JobSpec{Queue: "default"},
+			Status: batch.JobStatus{State: batch.JobState{Phase: batch.Running}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns"}, Spec: batch.JobSpec{Queue: "default"},
+			Status: batch.JobStatus{State: batch.JobState{Phase: batch.Running}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "ns"}, Spec: batch.JobSpec{Queue: "big"}},
+	} {
+		indexer.Add(job)
+	}
+
+	collector := &jobCollector{lister: batchlister.NewJobLister(indexer)}
+	expected := `
+# HELP volcano_jobs Number of jobs by phase and queue
+# TYPE volcano_jobs gauge
+volcano_jobs{phase="Pending",queue="big"} 1
+volcano_jobs{phase="Running",queue="default"} 2
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdateJobPhaseTransition(t *testing.T) {
+	now := time.Now()
+	from, to := batch.JobPhase("TestFrom"), batch.JobPhase("TestTo")
+	series := testutil.CollectAndCount(jobPhaseTransitionLatency)
+
+	updateJobPhaseTransition("", to, now, now)
+	updateJobPhaseTransition(from, to, time.Time{}, now)
+	if count := testutil.CollectAndCount(jobPhaseTransitionLatency); count != series {
+		t.Errorf("expected no transition without a previous phase, got %d series instead of %d", count, series)
+	}
+
+	updateJobPhaseTransition(from, to, now.Add(-time.Minute), now)
+	if count := testutil.CollectAndCount(jobPhaseTransitionLatency); count != series+1 {
+		t.Errorf("expected the transition from %s to %s, got %d series instead of %d", from, to, count, series+1)
+	}
+}
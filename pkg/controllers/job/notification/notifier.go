@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// EventType is the type of a job notification.
+type EventType string
+
+const (
+	// PhaseChanged is sent when the phase of a job changes.
+	PhaseChanged EventType = "PhaseChanged"
+	// Retried is sent when a job is restarted, with the new retry count.
+	Retried EventType = "Retried"
+	// PodEvicted is sent when a pod of a job is evicted.
+	PodEvicted EventType = "PodEvicted"
+)
+
+const (
+	// defaultQueueSize is the number of events waiting for delivery before new ones are dropped.
+	defaultQueueSize = 1024
+	// defaultRetries is the number of attempts to deliver an event to an endpoint.
+	defaultRetries = 3
+)
+
+// Event is the payload posted to the notification endpoints.
+type Event struct {
+	Type          EventType      `json:"type"`
+	Namespace     string         `json:"namespace"`
+	Name          string         `json:"name"`
+	UID           string         `json:"uid"`
+	Queue         string         `json:"queue,omitempty"`
+	Phase         batch.JobPhase `json:"phase"`
+	PreviousPhase batch.JobPhase `json:"previousPhase,omitempty"`
+	RetryCount    int32          `json:"retryCount"`
+	Pod           string         `json:"pod,omitempty"`
+	Message       string         `json:"message,omitempty"`
+	Time          metav1.Time    `json:"time"`
+}
+
+// NewEvent builds the event of the job.
+func NewEvent(eventType EventType, job *batch.Job) *Event {
+	return &Event{
+		Type:       eventType,
+		Namespace:  job.Namespace,
+		Name:       job.Name,
+		UID:        string(job.UID),
+		Queue:      job.Spec.Queue,
+		Phase:      job.Status.State.Phase,
+		RetryCount: job.Status.RetryCount,
+		Message:    job.Status.State.Message,
+		Time:       metav1.Now(),
+	}
+}
+
+// Notifier sends job events to external systems.
+type Notifier interface {
+	// Notify queues the event for delivery, it never blocks the caller.
+	Notify(event *Event)
+	// Run delivers queued events until stopCh is closed.
+	Run(stopCh <-chan struct{})
+}
+
+// webhookNotifier posts events as JSON to HTTP endpoints.
+type webhookNotifier struct {
+	urls   []string
+	client *http.Client
+	events chan *Event
+}
+
+// NewWebhookNotifier creates a notifier posting events to the urls, nil if there is no url.
+func NewWebhookNotifier(urls []string, timeout time.Duration) Notifier {
+	if len(urls) == 0 {
+		return nil
+	}
+	return &webhookNotifier{
+		urls:   urls,
+		client: &http.Client{Timeout: timeout},
+		events: make(chan *Event, defaultQueueSize),
+	}
+}
+
+func (n *webhookNotifier) Notify(event *Event) {
+	select {
+	case n.events <- event:
+	default:
+		klog.Warningf("Notification queue is full, drop %s event of job <%s/%s>", event.Type, event.Namespace, event.Name)
+	}
+}
+
+func (n *webhookNotifier) Run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case event := <-n.events:
+			for _, url := range n.urls {
+				n.send(url, event)
+			}
+		}
+	}
+}
+
+func (n *webhookNotifier) send(url string, event *Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		klog.Errorf("Failed to marshal %s event of job <%s/%s>: %v", event.Type, event.Namespace, event.Name, err)
+		return
+	}
+
+	backoff := wait.Backoff{Duration: 100 * time.Millisecond, Factor: 2, Steps: defaultRetries}
+	err = wait.ExponentialBackoff(backoff, func() (bool, error) {
+		if err := n.post(url, body); err != nil {
+			klog.V(3).Infof("Failed to send %s event of job <%s/%s> to %s: %v", event.Type, event.Namespace, event.Name, url, err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		klog.Errorf("Failed to send %s event of job <%s/%s> to %s after %d attempts",
+			event.Type, event.Namespace, event.Name, url, defaultRetries)
+	}
+}
+
+func (n *webhookNotifier) post(url string, body []byte) error {
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
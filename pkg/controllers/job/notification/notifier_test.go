@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	received := make(chan Event, 1)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		// fail the first attempt to check the event is retried
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode event: %v", err)
+		}
+		received <- event
+	}))
+	defer server.Close()
+
+	if NewWebhookNotifier(nil, time.Second) != nil {
+		t.Errorf("expected no notifier without urls")
+	}
+
+	notifier := NewWebhookNotifier([]string{server.URL}, time.Second)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go notifier.Run(stopCh)
+
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test", UID: "job1-uid"},
+		Status:     batch.JobStatus{State: batch.JobState{Phase: batch.Running}},
+	}
+	event := NewEvent(PhaseChanged, job)
+	event.PreviousPhase = batch.Pending
+	notifier.Notify(event)
+
+	select {
+	case got := <-received:
+		if got.Type != PhaseChanged || got.Name != "job1" || got.Phase != batch.Running || got.PreviousPhase != batch.Pending {
+			t.Errorf("unexpected event %+v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for the event")
+	}
+}
@@ -18,6 +18,7 @@ package mpi
 
 import (
 	"flag"
+	"fmt"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -46,6 +47,9 @@ type Plugin struct {
 	masterName   string
 	workerName   string
 	port         int
+
+	// argumentsErr is the error of parsing the plugin arguments
+	argumentsErr error
 }
 
 // New creates mpi plugin.
@@ -68,7 +72,19 @@ func (mp *Plugin) addFlags() {
 	flagSet.IntVar(&mp.port, "port", DefaultPort, "open port for containers")
 	if err := flagSet.Parse(mp.mpiArguments); err != nil {
 		klog.Errorf("plugin %s flagset parse failed, err: %v", mp.Name(), err)
+		mp.argumentsErr = err
+	}
+}
+
+// ValidateArguments returns the error of the malformed plugin arguments.
+func (mp *Plugin) ValidateArguments() error {
+	if mp.argumentsErr != nil {
+		return mp.argumentsErr
+	}
+	if mp.port <= 0 || mp.port > 65535 {
+		return fmt.Errorf("invalid port %d, must be in range 1-65535", mp.port)
 	}
+	return nil
 }
 
 func (mp *Plugin) Name() string {
@@ -39,6 +39,9 @@ type pytorchPlugin struct {
 	masterName       string
 	workerName       string
 	port             int
+
+	// argumentsErr is the error of parsing the plugin arguments
+	argumentsErr error
 }
 
 // New creates pytorch plugin.
@@ -55,7 +58,19 @@ func (pp *pytorchPlugin) addFlags() {
 	flagSet.IntVar(&pp.port, "port", DefaultPort, "open port for containers")
 	if err := flagSet.Parse(pp.pytorchArguments); err != nil {
 		klog.Errorf("plugin %s flagset parse failed, err: %v", pp.Name(), err)
+		pp.argumentsErr = err
+	}
+}
+
+// ValidateArguments returns the error of the malformed plugin arguments.
+func (pp *pytorchPlugin) ValidateArguments() error {
+	if pp.argumentsErr != nil {
+		return pp.argumentsErr
+	}
+	if pp.port <= 0 || pp.port > 65535 {
+		return fmt.Errorf("invalid port %d, must be in range 1-65535", pp.port)
 	}
+	return nil
 }
 
 func (pp *pytorchPlugin) Name() string {
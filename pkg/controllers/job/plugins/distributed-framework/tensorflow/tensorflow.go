@@ -47,6 +47,9 @@ type tensorflowPlugin struct {
 	chiefName     string
 	evaluatorName string
 	port          int
+
+	// argumentsErr is the error of parsing the plugin arguments
+	argumentsErr error
 }
 
 // New creates tensorflow plugin.
@@ -65,7 +68,19 @@ func (tp *tensorflowPlugin) addFlags() {
 	flagSet.IntVar(&tp.port, "port", DefaultPort, "service port")
 	if err := flagSet.Parse(tp.tfArguments); err != nil {
 		klog.Errorf("plugin %s flagset parse failed, err: %v", tp.Name(), err)
+		tp.argumentsErr = err
+	}
+}
+
+// ValidateArguments returns the error of the malformed plugin arguments.
+func (tp *tensorflowPlugin) ValidateArguments() error {
+	if tp.argumentsErr != nil {
+		return tp.argumentsErr
+	}
+	if tp.port <= 0 || tp.port > 65535 {
+		return fmt.Errorf("invalid port %d, must be in range 1-65535", tp.port)
 	}
+	return nil
 }
 
 func (tp *tensorflowPlugin) Name() string {
@@ -43,23 +43,39 @@ func (ep *envPlugin) Name() string {
 }
 
 func (ep *envPlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
-	index := jobhelpers.GetPodIndexUnderTask(pod)
+	// the index annotation is set by the job controller, fall back to the pod name for
+	// the pods which are not created from a task template.
+	index, found := pod.Annotations[batch.TaskIndex]
+	if !found {
+		index = jobhelpers.GetPodIndexUnderTask(pod)
+	}
 
 	// add VK_TASK_INDEX and VC_TASK_INDEX env to each container
 	for i := range pod.Spec.Containers {
-		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, v1.EnvVar{Name: TaskVkIndex, Value: index})
-		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, v1.EnvVar{Name: TaskIndex, Value: index})
+		pod.Spec.Containers[i].Env = setEnv(pod.Spec.Containers[i].Env, TaskVkIndex, index)
+		pod.Spec.Containers[i].Env = setEnv(pod.Spec.Containers[i].Env, TaskIndex, index)
 	}
 
 	// add VK_TASK_INDEX and VC_TASK_INDEX env to each init container
 	for i := range pod.Spec.InitContainers {
-		pod.Spec.InitContainers[i].Env = append(pod.Spec.InitContainers[i].Env, v1.EnvVar{Name: TaskVkIndex, Value: index})
-		pod.Spec.InitContainers[i].Env = append(pod.Spec.InitContainers[i].Env, v1.EnvVar{Name: TaskIndex, Value: index})
+		pod.Spec.InitContainers[i].Env = setEnv(pod.Spec.InitContainers[i].Env, TaskVkIndex, index)
+		pod.Spec.InitContainers[i].Env = setEnv(pod.Spec.InitContainers[i].Env, TaskIndex, index)
 	}
 
 	return nil
 }
 
+// setEnv sets the env to value, overriding the one with the same name from the template.
+func setEnv(envs []v1.EnvVar, name, value string) []v1.EnvVar {
+	for i := range envs {
+		if envs[i].Name == name {
+			envs[i] = v1.EnvVar{Name: name, Value: value}
+			return envs
+		}
+	}
+	return append(envs, v1.EnvVar{Name: name, Value: value})
+}
+
 func (ep *envPlugin) OnJobAdd(job *batch.Job) error {
 	if job.Status.ControlledResources["plugin-"+ep.Name()] == ep.Name() {
 		return nil
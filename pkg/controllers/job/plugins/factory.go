@@ -17,6 +17,7 @@ limitations under the License.
 package plugins
 
 import (
+	"fmt"
 	"sync"
 
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/mpi"
@@ -61,3 +62,19 @@ func GetPluginBuilder(name string) (PluginBuilder, bool) {
 	pb, found := pluginBuilders[name]
 	return pb, found
 }
+
+// ValidatePluginArguments checks the plugin exists and its arguments are well-formed.
+func ValidatePluginArguments(name string, arguments []string) error {
+	pb, found := GetPluginBuilder(name)
+	if !found {
+		return fmt.Errorf("unable to find job plugin: %s", name)
+	}
+	validator, ok := pb(pluginsinterface.PluginClientset{}, arguments).(pluginsinterface.PluginArgumentsValidator)
+	if !ok {
+		return nil
+	}
+	if err := validator.ValidateArguments(); err != nil {
+		return fmt.Errorf("invalid arguments %v of job plugin %s: %v", arguments, name, err)
+	}
+	return nil
+}
@@ -21,11 +21,14 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 // PluginClientset clientset.
 type PluginClientset struct {
 	KubeClients kubernetes.Interface
+	// Propagation is the job metadata stamped onto the resources created by plugins.
+	Propagation jobhelpers.MetadataPropagation
 }
 
 // PluginInterface interface.
@@ -48,3 +51,10 @@ type PluginInterface interface {
 	// Note: it can be called multi times, must be idempotent
 	OnJobUpdate(job *vcbatch.Job) error
 }
+
+// PluginArgumentsValidator is implemented by the plugins accepting arguments,
+// so that malformed arguments are rejected when the job is admitted.
+type PluginArgumentsValidator interface {
+	// ValidateArguments returns the error of the malformed plugin arguments
+	ValidateArguments() error
+}
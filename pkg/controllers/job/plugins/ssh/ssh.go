@@ -17,6 +17,8 @@ limitations under the License.
 package ssh
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -26,6 +28,8 @@ import (
 
 	"golang.org/x/crypto/ssh"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -48,6 +52,9 @@ type sshPlugin struct {
 
 	// public key string
 	sshPublicKey string
+
+	// argumentsErr is the error of parsing the plugin arguments
+	argumentsErr error
 }
 
 // New creates ssh plugin
@@ -89,10 +96,14 @@ func (sp *sshPlugin) OnJobAdd(job *batch.Job) error {
 		return err
 	}
 
-	if err := helpers.CreateOrUpdateSecret(job, sp.client.KubeClients, data, sp.secretName(job)); err != nil {
+	if err := jobhelpers.CreateOrUpdatePluginSecret(job, sp.client.KubeClients, sp.Name(), data, sp.secretName(job)); err != nil {
 		return fmt.Errorf("create secret for job <%s/%s> with ssh plugin failed for %v",
 			job.Namespace, job.Name, err)
 	}
+	if err := jobhelpers.PropagateToSecret(job, sp.client.KubeClients, sp.client.Propagation, sp.secretName(job)); err != nil {
+		return fmt.Errorf("propagate metadata to secret for job <%s/%s> with ssh plugin failed for %v",
+			job.Namespace, job.Name, err)
+	}
 
 	job.Status.ControlledResources["plugin-"+sp.Name()] = sp.Name()
 
@@ -111,19 +122,34 @@ func (sp *sshPlugin) OnJobDelete(job *batch.Job) error {
 	return nil
 }
 
-// TODO: currently a container using a Secret as a subPath volume mount will not receive Secret updates.
-// we may not update the job secret due to the above reason now.
+// OnJobUpdate regenerates the ssh config of the secret when the replicas of the job are scaled,
+// keeping the keys the running pods trust. The secret is mounted by subPath, which the kubelet
+// never refreshes, so only the pods created afterwards receive the updated config; the running
+// pods keep theirs until they are restarted.
 // related issue: https://github.com/volcano-sh/volcano/issues/1420
 func (sp *sshPlugin) OnJobUpdate(job *batch.Job) error {
-	//data, err := generateRsaKey(job)
-	//if err != nil {
-	//	return err
-	//}
-	//
-	//if err := helpers.CreateOrUpdateSecret(job, sp.client.KubeClients, data, sp.secretName(job)); err != nil {
-	//	return fmt.Errorf("update secret for job <%s/%s> with ssh plugin failed for %v",
-	//		job.Namespace, job.Name, err)
-	//}
+	secret, err := sp.client.KubeClients.CoreV1().Secrets(job.Namespace).Get(context.TODO(), sp.secretName(job), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	config := []byte(generateSSHConfig(job))
+	if bytes.Equal(secret.Data[SSHConfig], config) {
+		return nil
+	}
+	secret = secret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[SSHConfig] = config
+	if _, err := sp.client.KubeClients.CoreV1().Secrets(job.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("update ssh config of secret for job <%s/%s> with ssh plugin failed for %v",
+			job.Namespace, job.Name, err)
+	}
+	klog.V(3).Infof("Updated the ssh config of Job <%s/%s>", job.Namespace, job.Name)
 
 	return nil
 }
@@ -242,14 +268,20 @@ func (sp *sshPlugin) addFlags() {
 
 	if err := flagSet.Parse(sp.pluginArguments); err != nil {
 		klog.Errorf("plugin %s flagset parse failed, err: %v", sp.Name(), err)
+		sp.argumentsErr = err
 	}
 }
 
+// ValidateArguments returns the error of the malformed plugin arguments.
+func (sp *sshPlugin) ValidateArguments() error {
+	return sp.argumentsErr
+}
+
 func generateSSHConfig(job *batch.Job) string {
 	config := "StrictHostKeyChecking no\nUserKnownHostsFile /dev/null\n"
 
 	for _, ts := range job.Spec.Tasks {
-		for i := 0; i < int(ts.Replicas); i++ {
+		for _, i := range jobhelpers.GetPeerIndexes(job, ts) {
 			hostName := ts.Template.Spec.Hostname
 			subdomain := ts.Template.Spec.Subdomain
 			if len(hostName) == 0 {
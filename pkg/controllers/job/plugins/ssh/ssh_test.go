@@ -17,8 +17,15 @@ limitations under the License.
 package ssh
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
 )
 
@@ -71,3 +78,37 @@ func TestSSHPlugin(t *testing.T) {
 		})
 	}
 }
+
+func TestSSHPluginOnJobUpdate(t *testing.T) {
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "default"},
+		Spec: batch.JobSpec{
+			Tasks: []batch.TaskSpec{{Name: "worker", Replicas: 2}},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1-ssh", Namespace: "default"},
+		Data: map[string][]byte{
+			SSHPrivateKey: []byte("private"),
+			SSHConfig:     []byte(generateSSHConfig(job)),
+		},
+	})
+	plugin := New(pluginsinterface.PluginClientset{KubeClients: kubeClient}, nil)
+
+	// the job is scaled up
+	job.Spec.Tasks[0].Replicas = 3
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("failed to update the job: %v", err)
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets("default").Get(context.TODO(), "job1-ssh", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the secret: %v", err)
+	}
+	if !strings.Contains(string(secret.Data[SSHConfig]), "Host job1-worker-2\n") {
+		t.Errorf("expected the ssh config to have the new replica, got %s", secret.Data[SSHConfig])
+	}
+	if string(secret.Data[SSHPrivateKey]) != "private" {
+		t.Errorf("expected the keys to be kept, got %s", secret.Data[SSHPrivateKey])
+	}
+}
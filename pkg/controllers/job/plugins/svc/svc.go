@@ -44,6 +44,9 @@ type servicePlugin struct {
 	// flag parse args
 	publishNotReadyAddresses bool
 	disableNetworkPolicy     bool
+
+	// argumentsErr is the error of parsing the plugin arguments
+	argumentsErr error
 }
 
 // New creates service plugin.
@@ -68,9 +71,15 @@ func (sp *servicePlugin) addFlags() {
 
 	if err := flagSet.Parse(sp.pluginArguments); err != nil {
 		klog.Errorf("plugin %s flagset parse failed, err: %v", sp.Name(), err)
+		sp.argumentsErr = err
 	}
 }
 
+// ValidateArguments returns the error of the malformed plugin arguments.
+func (sp *servicePlugin) ValidateArguments() error {
+	return sp.argumentsErr
+}
+
 func (sp *servicePlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
 	// Add `hostname` and `subdomain` for pod, mount service config for pod.
 	// A pod with `hostname` and `subdomain` will have the fully qualified domain name(FQDN)
@@ -90,6 +99,15 @@ func (sp *servicePlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
 		pod.Spec.Subdomain = job.Name
 	}
 
+	sp.mountConfigmap(pod, job)
+
+	// The environment variables are only set when a container starts, while the peer lists of the
+	// jobs which grow after start change as the replicas join, so their pods read the host files
+	// of the mounted ConfigMap, which the kubelet keeps up to date.
+	if jobhelpers.IsGrowAfterStart(job) {
+		return nil
+	}
+
 	var hostEnv []v1.EnvVar
 	var envNames []string
 
@@ -119,8 +137,6 @@ func (sp *servicePlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
 		pod.Spec.InitContainers[i].Env = append(pod.Spec.InitContainers[i].Env, hostEnv...)
 	}
 
-	sp.mountConfigmap(pod, job)
-
 	return nil
 }
 
@@ -132,7 +148,10 @@ func (sp *servicePlugin) OnJobAdd(job *batch.Job) error {
 	hostFile := GenerateHosts(job)
 
 	// Create ConfigMap of hosts for Pods to mount.
-	if err := helpers.CreateOrUpdateConfigMap(job, sp.Clientset.KubeClients, hostFile, sp.cmName(job)); err != nil {
+	if err := jobhelpers.CreateOrUpdatePluginConfigMap(job, sp.Clientset.KubeClients, sp.Name(), hostFile, sp.cmName(job)); err != nil {
+		return err
+	}
+	if err := jobhelpers.PropagateToConfigMap(job, sp.Clientset.KubeClients, sp.Clientset.Propagation, sp.cmName(job)); err != nil {
 		return err
 	}
 
@@ -182,7 +201,7 @@ func (sp *servicePlugin) OnJobUpdate(job *batch.Job) error {
 	hostFile := GenerateHosts(job)
 
 	// updates ConfigMap of hosts for Pods to mount.
-	return helpers.CreateOrUpdateConfigMap(job, sp.Clientset.KubeClients, hostFile, sp.cmName(job))
+	return jobhelpers.CreateOrUpdatePluginConfigMap(job, sp.Clientset.KubeClients, sp.Name(), hostFile, sp.cmName(job))
 }
 
 func (sp *servicePlugin) mountConfigmap(pod *v1.Pod, job *batch.Job) {
@@ -223,6 +242,7 @@ func (sp *servicePlugin) createServiceIfNotExist(job *batch.Job) error {
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: job.Namespace,
 				Name:      job.Name,
+				Labels:    jobhelpers.PluginResourceLabels(job, sp.Name()),
 				OwnerReferences: []metav1.OwnerReference{
 					*metav1.NewControllerRef(job, helpers.JobKind),
 				},
@@ -237,6 +257,8 @@ func (sp *servicePlugin) createServiceIfNotExist(job *batch.Job) error {
 			},
 		}
 
+		sp.Clientset.Propagation.Apply(job, svc)
+
 		if _, e := sp.Clientset.KubeClients.CoreV1().Services(job.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{}); e != nil {
 			klog.V(3).Infof("Failed to create Service for Job <%s/%s>: %v", job.Namespace, job.Name, e)
 			return e
@@ -286,6 +308,8 @@ func (sp *servicePlugin) createNetworkPolicyIfNotExist(job *batch.Job) error {
 			},
 		}
 
+		sp.Clientset.Propagation.Apply(job, networkpolicy)
+
 		if _, e := sp.Clientset.KubeClients.NetworkingV1().NetworkPolicies(job.Namespace).Create(context.TODO(), networkpolicy, metav1.CreateOptions{}); e != nil {
 			klog.V(3).Infof("Failed to create Service for Job <%s/%s>: %v", job.Namespace, job.Name, e)
 			return e
@@ -307,7 +331,7 @@ func GenerateHosts(job *batch.Job) map[string]string {
 	for _, ts := range job.Spec.Tasks {
 		hosts := make([]string, 0, ts.Replicas)
 
-		for i := 0; i < int(ts.Replicas); i++ {
+		for _, i := range jobhelpers.GetPeerIndexes(job, ts) {
 			hostName := ts.Template.Spec.Hostname
 			subdomain := ts.Template.Spec.Subdomain
 			if len(hostName) == 0 {
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/features"
+)
+
+func TestOnPodCreate(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.GrowAfterStart, true)()
+
+	newJob := func(annotations map[string]string) *batch.Job {
+		return &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "horovod", Annotations: annotations},
+			Spec: batch.JobSpec{Tasks: []batch.TaskSpec{
+				{Name: "master", Replicas: 1},
+				{Name: "worker", Replicas: 2},
+			}},
+		}
+	}
+	testCases := []struct {
+		name        string
+		job         *batch.Job
+		expectedEnv []string
+	}{
+		{
+			name:        "host lists in the environment",
+			job:         newJob(nil),
+			expectedEnv: []string{"VC_MASTER_HOSTS", "VC_MASTER_NUM", "VC_WORKER_HOSTS", "VC_WORKER_NUM"},
+		},
+		{
+			name: "host lists only in the files of a job growing after start",
+			job:  newJob(map[string]string{jobhelpers.GrowAfterStartKey: "true"}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "horovod-worker-0"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "worker"}}},
+			}
+			sp := &servicePlugin{}
+			if err := sp.OnPodCreate(pod, tc.job); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			container := pod.Spec.Containers[0]
+			var env []string
+			for _, e := range container.Env {
+				env = append(env, e.Name)
+			}
+			if len(env) != len(tc.expectedEnv) {
+				t.Fatalf("expected env %v, got %v", tc.expectedEnv, env)
+			}
+			for i := range env {
+				if env[i] != tc.expectedEnv[i] {
+					t.Errorf("expected env %v, got %v", tc.expectedEnv, env)
+				}
+			}
+			if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != ConfigMapMountPath || container.VolumeMounts[0].SubPath != "" {
+				t.Errorf("expected the host files mounted at %s, got %v", ConfigMapMountPath, container.VolumeMounts)
+			}
+		})
+	}
+}
@@ -30,7 +30,7 @@ func (as *abortedState) Execute(action v1alpha1.Action) error {
 	switch action {
 	case v1alpha1.ResumeJobAction:
 		return KillJob(as.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Restarting
+			setPhaseByAction(status, vcbatch.Restarting, v1alpha1.ResumeJobAction)
 			status.RetryCount++
 			return true
 		})
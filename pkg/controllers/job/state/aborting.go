@@ -30,7 +30,7 @@ func (ps *abortingState) Execute(action v1alpha1.Action) error {
 	switch action {
 	case v1alpha1.ResumeJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Restarting
+			setPhaseByAction(status, vcbatch.Restarting, v1alpha1.ResumeJobAction)
 			status.RetryCount++
 			return true
 		})
@@ -40,7 +40,7 @@ func (ps *abortingState) Execute(action v1alpha1.Action) error {
 			if status.Terminating != 0 || status.Pending != 0 || status.Running != 0 {
 				return false
 			}
-			status.State.Phase = vcbatch.Aborted
+			setKilledPhase(status, vcbatch.Aborted, v1alpha1.AbortJobAction)
 			return true
 		})
 	}
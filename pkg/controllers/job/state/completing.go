@@ -27,12 +27,12 @@ type completingState struct {
 }
 
 func (ps *completingState) Execute(action v1alpha1.Action) error {
-	return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
+	return KillJob(ps.job, FinishedPodRetainPhase(ps.job.Job), func(status *vcbatch.JobStatus) bool {
 		// If any "alive" pods, still in Completing phase
 		if status.Terminating != 0 || status.Pending != 0 || status.Running != 0 {
 			return false
 		}
-		status.State.Phase = vcbatch.Completed
+		setKilledPhase(status, vcbatch.Completed, v1alpha1.CompleteJobAction)
 		return true
 	})
 }
@@ -45,11 +45,20 @@ var PodRetainPhaseSoft = PhaseMap{
 	v1.PodFailed:    {},
 }
 
+// PodRetainPhaseFailed stores PodFailed Phase.
+var PodRetainPhaseFailed = PhaseMap{
+	v1.PodFailed: {},
+}
+
 var (
 	// SyncJob will create or delete Pods according to Job's spec.
 	SyncJob ActionFn
 	// KillJob kill all Pods of Job with phase not in podRetainPhase.
 	KillJob KillActionFn
+	// RestartPod recreates the failed Pods of Job and keeps the others running.
+	RestartPod ActionFn
+	// RequeueJob moves the pending Job to another queue.
+	RequeueJob ActionFn
 )
 
 // State interface.
@@ -26,6 +26,7 @@ type finishedState struct {
 }
 
 func (ps *finishedState) Execute(action v1alpha1.Action) error {
-	// In finished state, e.g. Completed, always kill the whole job.
-	return KillJob(ps.job, PodRetainPhaseSoft, nil)
+	// In finished state, e.g. Completed, always kill the whole job,
+	// finished pods are kept according to the pod retain policy of the job.
+	return KillJob(ps.job, FinishedPodRetainPhase(ps.job.Job), nil)
 }
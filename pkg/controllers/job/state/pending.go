@@ -17,6 +17,8 @@ limitations under the License.
 package state
 
 import (
+	"fmt"
+
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
@@ -31,29 +33,35 @@ func (ps *pendingState) Execute(action v1alpha1.Action) error {
 	case v1alpha1.RestartJobAction:
 		return KillJob(ps.job, PodRetainPhaseNone, func(status *vcbatch.JobStatus) bool {
 			status.RetryCount++
-			status.State.Phase = vcbatch.Restarting
+			setPhaseByAction(status, vcbatch.Restarting, v1alpha1.RestartJobAction)
 			return true
 		})
 
+	case apis.RestartPodAction:
+		return RestartPod(ps.job, nil)
+	case apis.RequeueJobAction:
+		return RequeueJob(ps.job, nil)
 	case v1alpha1.AbortJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Aborting
+			setPhaseByAction(status, vcbatch.Aborting, v1alpha1.AbortJobAction)
 			return true
 		})
 	case v1alpha1.CompleteJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Completing
+			setPhaseByAction(status, vcbatch.Completing, v1alpha1.CompleteJobAction)
 			return true
 		})
 	case v1alpha1.TerminateJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Terminating
+			setPhaseByAction(status, vcbatch.Terminating, v1alpha1.TerminateJobAction)
 			return true
 		})
 	default:
 		return SyncJob(ps.job, func(status *vcbatch.JobStatus) bool {
 			if ps.job.Job.Spec.MinAvailable <= status.Running+status.Succeeded+status.Failed {
-				status.State.Phase = vcbatch.Running
+				setPhase(status, vcbatch.Running, MinAvailableReadyReason,
+					fmt.Sprintf("%d pods of minAvailable %d are running or finished",
+						status.Running+status.Succeeded+status.Failed, ps.job.Job.Spec.MinAvailable))
 				return true
 			}
 			return false
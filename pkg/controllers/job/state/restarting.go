@@ -17,6 +17,8 @@ limitations under the License.
 package state
 
 import (
+	"fmt"
+
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
@@ -33,7 +35,8 @@ func (ps *restartingState) Execute(action v1alpha1.Action) error {
 
 		if status.RetryCount >= maxRetry {
 			// Failed is the phase that the job is restarted failed reached the maximum number of retries.
-			status.State.Phase = vcbatch.Failed
+			setPhase(status, vcbatch.Failed, MaxRetryReachedReason,
+				fmt.Sprintf("Job is restarted %d times, reached maxRetry %d", status.RetryCount, maxRetry))
 			return true
 		}
 		total := int32(0)
@@ -42,7 +45,7 @@ func (ps *restartingState) Execute(action v1alpha1.Action) error {
 		}
 
 		if total-status.Terminating >= status.MinAvailable {
-			status.State.Phase = vcbatch.Pending
+			setPhase(status, vcbatch.Pending, RestartedReason, "Pods of the job are killed for restarting")
 			return true
 		}
 
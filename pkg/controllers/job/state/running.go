@@ -17,6 +17,8 @@ limitations under the License.
 package state
 
 import (
+	"fmt"
+
 	v1 "k8s.io/api/core/v1"
 
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -32,23 +34,25 @@ func (ps *runningState) Execute(action v1alpha1.Action) error {
 	switch action {
 	case v1alpha1.RestartJobAction:
 		return KillJob(ps.job, PodRetainPhaseNone, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Restarting
+			setPhaseByAction(status, vcbatch.Restarting, v1alpha1.RestartJobAction)
 			status.RetryCount++
 			return true
 		})
+	case apis.RestartPodAction:
+		return RestartPod(ps.job, nil)
 	case v1alpha1.AbortJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Aborting
+			setPhaseByAction(status, vcbatch.Aborting, v1alpha1.AbortJobAction)
 			return true
 		})
 	case v1alpha1.TerminateJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Terminating
+			setPhaseByAction(status, vcbatch.Terminating, v1alpha1.TerminateJobAction)
 			return true
 		})
 	case v1alpha1.CompleteJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
-			status.State.Phase = vcbatch.Completing
+			setPhaseByAction(status, vcbatch.Completing, v1alpha1.CompleteJobAction)
 			return true
 		})
 	default:
@@ -61,7 +65,8 @@ func (ps *runningState) Execute(action v1alpha1.Action) error {
 
 			minSuccess := ps.job.Job.Spec.MinSuccess
 			if minSuccess != nil && status.Succeeded >= *minSuccess {
-				status.State.Phase = vcbatch.Completed
+				setPhase(status, vcbatch.Completed, MinSuccessReachedReason,
+					fmt.Sprintf("%d pods succeeded, reached minSuccess %d", status.Succeeded, *minSuccess))
 				return true
 			}
 
@@ -75,7 +80,9 @@ func (ps *runningState) Execute(action v1alpha1.Action) error {
 
 						if taskStatus, ok := status.TaskStatusCount[task.Name]; ok {
 							if taskStatus.Phase[v1.PodSucceeded] < *task.MinAvailable {
-								status.State.Phase = vcbatch.Failed
+								setPhase(status, vcbatch.Failed, TaskMinAvailableNotSucceededReason,
+									fmt.Sprintf("%d pods of task %s succeeded, less than its minAvailable %d",
+										taskStatus.Phase[v1.PodSucceeded], task.Name, *task.MinAvailable))
 								return true
 							}
 						}
@@ -83,16 +90,20 @@ func (ps *runningState) Execute(action v1alpha1.Action) error {
 				}
 
 				if minSuccess != nil && status.Succeeded < *minSuccess {
-					status.State.Phase = vcbatch.Failed
+					setPhase(status, vcbatch.Failed, MinSuccessNotReachedReason,
+						fmt.Sprintf("%d pods succeeded, less than minSuccess %d", status.Succeeded, *minSuccess))
 				} else if status.Succeeded >= ps.job.Job.Spec.MinAvailable {
-					status.State.Phase = vcbatch.Completed
+					setPhase(status, vcbatch.Completed, MinAvailableSucceededReason,
+						fmt.Sprintf("%d pods succeeded, minAvailable is %d", status.Succeeded, ps.job.Job.Spec.MinAvailable))
 				} else {
-					status.State.Phase = vcbatch.Failed
+					setPhase(status, vcbatch.Failed, MinAvailableNotSucceededReason,
+						fmt.Sprintf("%d pods succeeded, less than minAvailable %d", status.Succeeded, ps.job.Job.Spec.MinAvailable))
 				}
 				return true
 			}
 			if status.Pending > jobReplicas-ps.job.Job.Spec.MinAvailable {
-				status.State.Phase = vcbatch.Pending
+				setPhase(status, vcbatch.Pending, PodsPendingReason,
+					fmt.Sprintf("%d pods are pending, minAvailable %d is not met", status.Pending, ps.job.Job.Spec.MinAvailable))
 				return true
 			}
 			return false
@@ -27,12 +27,12 @@ type terminatingState struct {
 }
 
 func (ps *terminatingState) Execute(action v1alpha1.Action) error {
-	return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
+	return KillJob(ps.job, FinishedPodRetainPhase(ps.job.Job), func(status *vcbatch.JobStatus) bool {
 		// If any "alive" pods, still in Terminating phase
 		if status.Terminating != 0 || status.Pending != 0 || status.Running != 0 {
 			return false
 		}
-		status.State.Phase = vcbatch.Terminated
+		setKilledPhase(status, vcbatch.Terminated, v1alpha1.TerminateJobAction)
 		return true
 	})
 }
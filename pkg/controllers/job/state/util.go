@@ -17,7 +17,36 @@ limitations under the License.
 package state
 
 import (
+	"fmt"
+
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+// The reasons of the phase transitions made by the state machine, recorded in the job state.
+// Transitions caused by an action, e.g. AbortJob, use the name of the action as reason.
+const (
+	// MinAvailableReadyReason is set when the job is running with minAvailable pods.
+	MinAvailableReadyReason = "MinAvailableReady"
+	// PodsPendingReason is set when the running job lost its minAvailable pods.
+	PodsPendingReason = "PodsPending"
+	// MinSuccessReachedReason is set when minSuccess pods of the job succeeded.
+	MinSuccessReachedReason = "MinSuccessReached"
+	// MinSuccessNotReachedReason is set when all pods of the job finished before minSuccess pods succeeded.
+	MinSuccessNotReachedReason = "MinSuccessNotReached"
+	// TaskMinAvailableNotSucceededReason is set when less than minAvailable pods of a task succeeded.
+	TaskMinAvailableNotSucceededReason = "TaskMinAvailableNotSucceeded"
+	// MinAvailableSucceededReason is set when all pods finished with minAvailable pods succeeded.
+	MinAvailableSucceededReason = "MinAvailableSucceeded"
+	// MinAvailableNotSucceededReason is set when all pods finished with less than minAvailable pods succeeded.
+	MinAvailableNotSucceededReason = "MinAvailableNotSucceeded"
+	// MaxRetryReachedReason is set when the restarting job reached its maxRetry.
+	MaxRetryReachedReason = "MaxRetryReached"
+	// RestartedReason is set when the pods of the restarting job are killed.
+	RestartedReason = "Restarted"
+	// PodMaxRetryReachedReason is set when a pod of the job failed more times than the maxRetry of its task.
+	PodMaxRetryReachedReason = "PodMaxRetryReached"
 )
 
 // TotalTasks returns number of tasks in a given volcano job.
@@ -45,3 +74,37 @@ func TotalTaskMinAvailable(job *vcbatch.Job) int32 {
 
 	return rep
 }
+
+// FinishedPodRetainPhase returns the phases of pods kept when the job finishes, according to its pod retain policy.
+func FinishedPodRetainPhase(job *vcbatch.Job) PhaseMap {
+	switch jobhelpers.GetPodRetainPolicy(job) {
+	case jobhelpers.PodRetainPolicyRetainFailed:
+		return PodRetainPhaseFailed
+	case jobhelpers.PodRetainPolicyDeleteAll:
+		return PodRetainPhaseNone
+	default:
+		return PodRetainPhaseSoft
+	}
+}
+
+// setPhase moves the job to the phase, recording the reason and message of the transition.
+func setPhase(status *vcbatch.JobStatus, phase vcbatch.JobPhase, reason, message string) {
+	status.State.Phase = phase
+	status.State.Reason = reason
+	status.State.Message = message
+}
+
+// setPhaseByAction moves the job to the phase on the action.
+func setPhaseByAction(status *vcbatch.JobStatus, phase vcbatch.JobPhase, action v1alpha1.Action) {
+	setPhase(status, phase, string(action), fmt.Sprintf("Job is %s by action %s", phase, action))
+}
+
+// setKilledPhase moves the job whose pods are killed by the action to its final phase. The reason and the message
+// given with the command of the action, if any, are kept, so that users still see why e.g. an admin aborted the job.
+func setKilledPhase(status *vcbatch.JobStatus, phase vcbatch.JobPhase, action v1alpha1.Action) {
+	if status.State.Reason != "" && status.State.Reason != string(action) {
+		status.State.Phase = phase
+		return
+	}
+	setPhaseByAction(status, phase, action)
+}
@@ -0,0 +1,393 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobautoscaler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
+	versionedscheme "volcano.sh/apis/pkg/client/clientset/versioned/scheme"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
+	batchlister "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/events"
+	"volcano.sh/volcano/pkg/controllers/framework"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/features"
+)
+
+const (
+	// defaultSyncPeriod is by default how often the metrics of the autoscaled tasks are queried.
+	defaultSyncPeriod = 30 * time.Second
+	// metricsQueryWorkers is the number of the metrics queries run in parallel.
+	metricsQueryWorkers = 8
+)
+
+func init() {
+	framework.RegisterController(&jobautoscalercontroller{})
+}
+
+// jobautoscalercontroller scales the replicas of the tasks of running jobs between the bounds of
+// their autoscaling policies, so that the metrics of the policies meet their targets. The job
+// controller then creates or deletes the pods as for a scale by hand.
+type jobautoscalercontroller struct {
+	vcClient vcclientset.Interface
+
+	vcInformerFactory vcinformer.SharedInformerFactory
+
+	jobLister batchlister.JobLister
+	jobSynced cache.InformerSynced
+	// the namespaces whose jobs are autoscaled
+	namespaces framework.NamespaceScope
+
+	recorder record.EventRecorder
+	// eventBroadcaster sends the events of the recorder, it is flushed when the controller stops
+	eventBroadcaster record.EventBroadcaster
+
+	// metrics returns the values of the queries of the metrics, nil if no metrics source is configured
+	metrics metricsClient
+	// queries are the Prometheus queries of the metrics the policies refer to, keyed by metric name
+	queries    map[string]string
+	syncPeriod time.Duration
+
+	mutex sync.Mutex
+	// recommendations of the tasks within their scale down stabilization window, keyed by task
+	recommendations map[string][]recommendation
+	// samples are the last values of the metrics of the tasks, keyed by task
+	samples map[string]sample
+
+	now func() time.Time
+}
+
+type recommendation struct {
+	replicas  int32
+	timestamp time.Time
+}
+
+type sample struct {
+	value     float64
+	timestamp time.Time
+}
+
+func (ac *jobautoscalercontroller) Name() string {
+	return "jobautoscaler-controller"
+}
+
+func (ac *jobautoscalercontroller) Initialize(opt *framework.ControllerOption) error {
+	ac.vcClient = opt.VolcanoClient
+
+	factory := opt.VCSharedInformerFactory
+	ac.vcInformerFactory = factory
+	jobInformer := factory.Batch().V1alpha1().Jobs()
+	ac.jobLister = jobInformer.Lister()
+	ac.jobSynced = jobInformer.Informer().HasSynced
+	ac.namespaces = opt.Namespaces
+
+	eventBroadcaster := record.NewBroadcaster()
+	ac.eventBroadcaster = eventBroadcaster
+	eventBroadcaster.StartLogging(klog.Infof)
+	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: opt.KubeClient.CoreV1().Events("")})
+	ac.recorder = eventBroadcaster.NewRecorder(versionedscheme.Scheme, v1.EventSource{Component: "vc-controller-manager"})
+
+	if opt.AutoscalerPrometheusAddress != "" && opt.AutoscalerMetricsConfig != "" {
+		queries, err := loadMetricQueries(opt.AutoscalerMetricsConfig)
+		if err != nil {
+			return err
+		}
+		ac.metrics = newPrometheusClient(opt.AutoscalerPrometheusAddress)
+		ac.queries = queries
+	}
+	ac.syncPeriod = opt.AutoscalerSyncPeriod
+	if ac.syncPeriod <= 0 {
+		ac.syncPeriod = defaultSyncPeriod
+	}
+	ac.recommendations = map[string][]recommendation{}
+	ac.samples = map[string]sample{}
+	ac.now = time.Now
+
+	return nil
+}
+
+func (ac *jobautoscalercontroller) Run(stopCh <-chan struct{}) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ElasticJobs) {
+		klog.Infof("The %s feature is disabled, job autoscaler is disabled", features.ElasticJobs)
+		return
+	}
+	if ac.metrics == nil {
+		klog.Infof("No metrics source or metrics queries are configured, job autoscaler is disabled")
+		return
+	}
+
+	ac.vcInformerFactory.Start(stopCh)
+	for informerType, ok := range ac.vcInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
+
+	klog.Infof("Job autoscaler is running, syncing every %v", ac.syncPeriod)
+	// the metrics are queried apart from the scaling of the jobs, so that slow queries do not delay it
+	var workers sync.WaitGroup
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		wait.Until(ac.refreshMetrics, ac.syncPeriod, stopCh)
+	}()
+	go func() {
+		defer workers.Done()
+		wait.Until(ac.sync, ac.syncPeriod, stopCh)
+	}()
+
+	<-stopCh
+
+	// the scaling in flight completes, and the events it recorded are sent before the controller stops
+	workers.Wait()
+	ac.eventBroadcaster.Shutdown()
+}
+
+// isAutoscaled returns whether the job has autoscaling policies and is managed by the controller.
+func (ac *jobautoscalercontroller) isAutoscaled(job *batch.Job) bool {
+	_, found := job.Annotations[jobhelpers.TaskAutoscalingKey]
+	return found && ac.namespaces.Manages(job.Namespace)
+}
+
+// metricQuery is the query of the metric of an autoscaled task.
+type metricQuery struct {
+	// task is the key of the task, namespace/job/task
+	task   string
+	metric string
+	query  string
+}
+
+// refreshMetrics queries the metrics of the tasks of the running autoscaled jobs in parallel, and
+// records their values for the next syncs.
+func (ac *jobautoscalercontroller) refreshMetrics() {
+	jobs, err := ac.jobLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list jobs: %v", err)
+		return
+	}
+
+	var queries []metricQuery
+	for _, job := range jobs {
+		if !ac.isAutoscaled(job) || job.DeletionTimestamp != nil || job.Status.State.Phase != batch.Running {
+			continue
+		}
+		// the invalid policies are reported when the job is synced
+		policies, err := jobhelpers.GetTaskAutoscaling(job)
+		if err != nil {
+			continue
+		}
+		for taskName, policy := range policies {
+			if policy == nil {
+				continue
+			}
+			template, found := ac.queries[policy.Metric]
+			if !found {
+				continue
+			}
+			queries = append(queries, metricQuery{
+				task:   taskKey(job, taskName),
+				metric: policy.Metric,
+				query:  strings.NewReplacer("$namespace", job.Namespace, "$job", job.Name, "$task", taskName).Replace(template),
+			})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ac.syncPeriod)
+	defer cancel()
+	workqueue.ParallelizeUntil(ctx, metricsQueryWorkers, len(queries), func(i int) {
+		q := queries[i]
+		value, err := ac.metrics.Query(ctx, q.query)
+		if err != nil {
+			klog.Warningf("Failed to query metric %s of task %s: %v", q.metric, q.task, err)
+			return
+		}
+		ac.mutex.Lock()
+		defer ac.mutex.Unlock()
+		ac.samples[q.task] = sample{value: value, timestamp: ac.now()}
+	})
+}
+
+// getSample returns the last value of the metric of the task, unless it is older than two sync periods.
+func (ac *jobautoscalercontroller) getSample(key string) (float64, bool) {
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
+	s, found := ac.samples[key]
+	if !found || ac.now().Sub(s.timestamp) > 2*ac.syncPeriod {
+		return 0, false
+	}
+	return s.value, true
+}
+
+func taskKey(job *batch.Job, taskName string) string {
+	return fmt.Sprintf("%s/%s/%s", job.Namespace, job.Name, taskName)
+}
+
+// sync scales the tasks of all the running jobs with autoscaling policies.
+func (ac *jobautoscalercontroller) sync() {
+	jobs, err := ac.jobLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list jobs: %v", err)
+		return
+	}
+
+	autoscaled := map[string]bool{}
+	for _, job := range jobs {
+		if !ac.isAutoscaled(job) {
+			continue
+		}
+		autoscaled[job.Namespace+"/"+job.Name] = true
+		if err := ac.autoscaleJob(job); err != nil {
+			klog.Errorf("Failed to autoscale job <%s/%s>: %v", job.Namespace, job.Name, err)
+		}
+	}
+
+	// forget the recommendations and the metrics of the jobs which are gone or no longer autoscaled
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
+	for key := range ac.recommendations {
+		if !autoscaled[key[:strings.LastIndex(key, "/")]] {
+			delete(ac.recommendations, key)
+		}
+	}
+	for key := range ac.samples {
+		if !autoscaled[key[:strings.LastIndex(key, "/")]] {
+			delete(ac.samples, key)
+		}
+	}
+}
+
+// autoscaleJob updates the replicas of the tasks of the job recommended by their autoscaling policies
+// from the last values of their metrics.
+func (ac *jobautoscalercontroller) autoscaleJob(job *batch.Job) error {
+	if job.DeletionTimestamp != nil || job.Status.State.Phase != batch.Running {
+		return nil
+	}
+	policies, err := jobhelpers.GetTaskAutoscaling(job)
+	if err != nil {
+		return err
+	}
+	lastShrink, shrunk := jobhelpers.GetLastShrinkTime(job)
+
+	newJob := job.DeepCopy()
+	total := int32(0)
+	for _, task := range newJob.Spec.Tasks {
+		total += task.Replicas
+	}
+
+	var scaled []string
+	for i := range newJob.Spec.Tasks {
+		task := &newJob.Spec.Tasks[i]
+		policy := policies[task.Name]
+		if policy == nil {
+			continue
+		}
+		if _, found := ac.queries[policy.Metric]; !found {
+			events.Record(ac.recorder, job, events.FailedAutoscale,
+				fmt.Sprintf("Unknown metric %s of the autoscaling policy of task %s", policy.Metric, task.Name))
+			continue
+		}
+
+		key := taskKey(job, task.Name)
+		value, found := ac.getSample(key)
+		if !found {
+			continue
+		}
+
+		desired := ac.stabilize(key, policy.RecommendReplicas(task.Replicas, value), task.Replicas, policy.ScaleDownStabilization())
+		// the job keeps at least its minAvailable replicas
+		if lower := job.Spec.MinAvailable - (total - task.Replicas); desired < lower {
+			desired = lower
+		}
+		if task.MinAvailable != nil && desired < *task.MinAvailable {
+			desired = *task.MinAvailable
+		}
+		// the replicas removed on reclaim are not added back within the stabilization window, as
+		// the resources of the queue are claimed by other queues
+		if shrunk && desired > task.Replicas && ac.now().Sub(lastShrink) < policy.ScaleDownStabilization() {
+			klog.V(4).Infof("Keeping %d replicas of task %s of job <%s/%s> scaled down on reclaim at %v",
+				task.Replicas, task.Name, job.Namespace, job.Name, lastShrink)
+			continue
+		}
+		if desired == task.Replicas {
+			continue
+		}
+
+		klog.V(3).Infof("Scaling task %s of job <%s/%s> from %d to %d replicas, metric %s %v", task.Name,
+			job.Namespace, job.Name, task.Replicas, desired, policy.Metric, value)
+		scaled = append(scaled, fmt.Sprintf("task %s from %d to %d replicas (%s %v)", task.Name, task.Replicas, desired, policy.Metric, value))
+		total += desired - task.Replicas
+		task.Replicas = desired
+	}
+	if len(scaled) == 0 {
+		return nil
+	}
+
+	if _, err := ac.vcClient.BatchV1alpha1().Jobs(job.Namespace).Update(context.TODO(), newJob, metav1.UpdateOptions{}); err != nil {
+		events.Record(ac.recorder, job, events.FailedAutoscale, fmt.Sprintf("Failed to scale %s: %v", strings.Join(scaled, ", "), err))
+		return err
+	}
+	events.Record(ac.recorder, job, events.Autoscaled, fmt.Sprintf("Scaled %s", strings.Join(scaled, ", ")))
+	return nil
+}
+
+// stabilize records the recommendation of the task and returns its replicas. The task is scaled up at
+// once, but only scaled down to the highest recommendation within the stabilization window, so that
+// a metric going up and down does not scale the task back and forth.
+func (ac *jobautoscalercontroller) stabilize(key string, recommended, current int32, window time.Duration) int32 {
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
+
+	now := ac.now()
+	recommendations := []recommendation{{replicas: recommended, timestamp: now}}
+	highest := recommended
+	for _, r := range ac.recommendations[key] {
+		if now.Sub(r.timestamp) >= window {
+			continue
+		}
+		recommendations = append(recommendations, r)
+		if r.replicas > highest {
+			highest = r.replicas
+		}
+	}
+	ac.recommendations[key] = recommendations
+
+	if recommended >= current {
+		return recommended
+	}
+	if highest < current {
+		return highest
+	}
+	return current
+}
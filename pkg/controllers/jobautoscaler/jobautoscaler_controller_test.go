@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobautoscaler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	volcanoclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/controllers/framework"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+type fakeMetricsClient map[string]float64
+
+func (f fakeMetricsClient) Query(_ context.Context, query string) (float64, error) {
+	value, found := f[query]
+	if !found {
+		return 0, fmt.Errorf("the query returned no samples")
+	}
+	return value, nil
+}
+
+func newFakeController(metrics fakeMetricsClient) *jobautoscalercontroller {
+	volcanoClientSet := volcanoclient.NewSimpleClientset()
+	controller := &jobautoscalercontroller{}
+	controller.Initialize(&framework.ControllerOption{
+		KubeClient:              kubeclient.NewSimpleClientset(),
+		VolcanoClient:           volcanoClientSet,
+		VCSharedInformerFactory: informerfactory.NewSharedInformerFactory(volcanoClientSet, 0),
+	})
+	controller.metrics = metrics
+	controller.queries = map[string]string{"queue-depth": `sum(queue_depth{job="$job"})`}
+	return controller
+}
+
+func newAutoscaledJob(replicas int32, phase batch.JobPhase) *batch.Job {
+	return &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: "default",
+			Annotations: map[string]string{
+				jobhelpers.TaskAutoscalingKey: `{"worker":{"minReplicas":1,"maxReplicas":8,` +
+					`"metric":"queue-depth","targetAverageValue":100,"scaleDownStabilizationSeconds":60}}`,
+			},
+		},
+		Spec: batch.JobSpec{
+			MinAvailable: 3,
+			Tasks: []batch.TaskSpec{
+				{Name: "master", Replicas: 1},
+				{Name: "worker", Replicas: replicas},
+			},
+		},
+		Status: batch.JobStatus{State: batch.JobState{Phase: phase}},
+	}
+}
+
+func TestAutoscaleJob(t *testing.T) {
+	query := `sum(queue_depth{job="job1"})`
+	testCases := []struct {
+		Name     string
+		Phase    batch.JobPhase
+		Replicas int32
+		Metric   float64
+		// ShrunkAgo is how long ago the job was scaled down on reclaim, never if zero
+		ShrunkAgo time.Duration
+		Expected  int32
+	}{
+		{
+			Name:     "scale up",
+			Phase:    batch.Running,
+			Replicas: 2,
+			Metric:   500,
+			Expected: 5,
+		},
+		{
+			Name:     "scale down",
+			Phase:    batch.Running,
+			Replicas: 4,
+			Metric:   200,
+			Expected: 2,
+		},
+		{
+			Name:     "the job keeps its minAvailable",
+			Phase:    batch.Running,
+			Replicas: 4,
+			Metric:   0,
+			Expected: 2,
+		},
+		{
+			Name:      "the job scaled down on reclaim is not scaled up within the window",
+			Phase:     batch.Running,
+			Replicas:  2,
+			Metric:    500,
+			ShrunkAgo: 30 * time.Second,
+			Expected:  2,
+		},
+		{
+			Name:      "the job scaled down on reclaim is scaled up after the window",
+			Phase:     batch.Running,
+			Replicas:  2,
+			Metric:    500,
+			ShrunkAgo: 90 * time.Second,
+			Expected:  5,
+		},
+		{
+			Name:     "pending job is not scaled",
+			Phase:    batch.Pending,
+			Replicas: 2,
+			Metric:   500,
+			Expected: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			ac := newFakeController(fakeMetricsClient{query: testCase.Metric})
+			job := newAutoscaledJob(testCase.Replicas, testCase.Phase)
+			if testCase.ShrunkAgo != 0 {
+				job.Annotations[jobhelpers.LastShrinkTimeKey] = time.Now().Add(-testCase.ShrunkAgo).UTC().Format(time.RFC3339)
+			}
+			if _, err := ac.vcClient.BatchV1alpha1().Jobs(job.Namespace).Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create job: %v", err)
+			}
+			ac.vcInformerFactory.Batch().V1alpha1().Jobs().Informer().GetIndexer().Add(job)
+
+			ac.refreshMetrics()
+			if err := ac.autoscaleJob(job); err != nil {
+				t.Fatalf("failed to autoscale job: %v", err)
+			}
+
+			got, err := ac.vcClient.BatchV1alpha1().Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get job: %v", err)
+			}
+			if replicas := got.Spec.Tasks[1].Replicas; replicas != testCase.Expected {
+				t.Errorf("expected %d replicas, got %d", testCase.Expected, replicas)
+			}
+		})
+	}
+}
+
+func TestStabilizeScaleDown(t *testing.T) {
+	ac := newFakeController(nil)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ac.now = func() time.Time { return now }
+	window := time.Minute
+
+	if replicas := ac.stabilize("default/job1/worker", 6, 4, window); replicas != 6 {
+		t.Errorf("expected to scale up to 6 at once, got %d", replicas)
+	}
+	now = now.Add(30 * time.Second)
+	if replicas := ac.stabilize("default/job1/worker", 3, 6, window); replicas != 6 {
+		t.Errorf("expected to keep 6 replicas within the window, got %d", replicas)
+	}
+	now = now.Add(45 * time.Second)
+	if replicas := ac.stabilize("default/job1/worker", 2, 6, window); replicas != 3 {
+		t.Errorf("expected to scale down to the highest recommendation within the window, got %d", replicas)
+	}
+}
+
+func TestPrometheusQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("query") {
+		case "queue_depth":
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[`+
+				`{"metric":{"queue":"a"},"value":[1700000000,"120"]},{"metric":{"queue":"b"},"value":[1700000000,"30.5"]}]}}`)
+		case "empty":
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":"error","error":"parse error"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := newPrometheusClient(server.URL + "/")
+	if value, err := client.Query(context.TODO(), "queue_depth"); err != nil || value != 150.5 {
+		t.Errorf("expected the sum of the samples 150.5, got %v, %v", value, err)
+	}
+	if _, err := client.Query(context.TODO(), "empty"); err == nil {
+		t.Errorf("expected an error for a query without samples")
+	}
+	if _, err := client.Query(context.TODO(), "invalid("); err == nil {
+		t.Errorf("expected an error for an invalid query")
+	}
+}
+
+func TestLoadMetricQueries(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	os.WriteFile(valid, []byte("queue-depth: sum(queue_depth{job=\"$job\"})\n"), 0o644)
+	empty := filepath.Join(dir, "empty.yaml")
+	os.WriteFile(empty, []byte("queue-depth: \"\"\n"), 0o644)
+
+	queries, err := loadMetricQueries(valid)
+	if err != nil || queries["queue-depth"] != `sum(queue_depth{job="$job"})` {
+		t.Errorf("expected the query of metric queue-depth, got %v, %v", queries, err)
+	}
+	if _, err := loadMetricQueries(empty); err == nil {
+		t.Errorf("expected an error for an empty query")
+	}
+	if _, err := loadMetricQueries(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
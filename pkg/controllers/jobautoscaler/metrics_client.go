@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobautoscaler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const metricsQueryTimeout = 10 * time.Second
+
+// loadMetricQueries loads the Prometheus queries of the metrics, keyed by metric name, from the
+// YAML file. The policies of the tasks only refer to the metrics by name, so that the users do
+// not run their own queries with the credentials of the controller.
+func loadMetricQueries(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read autoscaler metrics config %s: %v", path, err)
+	}
+	queries := map[string]string{}
+	if err := yaml.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("failed to parse autoscaler metrics config %s: %v", path, err)
+	}
+	for name, query := range queries {
+		if query == "" {
+			return nil, fmt.Errorf("invalid autoscaler metrics config %s: empty query of metric %s", path, name)
+		}
+	}
+	return queries, nil
+}
+
+// metricsClient returns the value of a metrics query.
+type metricsClient interface {
+	Query(ctx context.Context, query string) (float64, error)
+}
+
+// prometheusClient runs instant queries against the HTTP API of Prometheus.
+type prometheusClient struct {
+	address string
+	client  *http.Client
+}
+
+func newPrometheusClient(address string) *prometheusClient {
+	return &prometheusClient{
+		address: strings.TrimSuffix(address, "/"),
+		client:  &http.Client{Timeout: metricsQueryTimeout},
+	}
+}
+
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// Query returns the value of the query, the sum of the samples if it returns a vector.
+func (c *prometheusClient) Query(ctx context.Context, query string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.address+"/api/v1/query?"+url.Values{"query": []string{query}}.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var body prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to decode the response of the query, status %s: %v", resp.Status, err)
+	}
+	if body.Status != "success" {
+		return 0, fmt.Errorf("the query failed: %s", body.Error)
+	}
+
+	switch body.Data.ResultType {
+	case "scalar":
+		var sample [2]interface{}
+		if err := json.Unmarshal(body.Data.Result, &sample); err != nil {
+			return 0, err
+		}
+		return parseSampleValue(sample)
+	case "vector":
+		var samples []struct {
+			Value [2]interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &samples); err != nil {
+			return 0, err
+		}
+		if len(samples) == 0 {
+			return 0, fmt.Errorf("the query returned no samples")
+		}
+		sum := 0.0
+		for _, sample := range samples {
+			value, err := parseSampleValue(sample.Value)
+			if err != nil {
+				return 0, err
+			}
+			sum += value
+		}
+		return sum, nil
+	default:
+		return 0, fmt.Errorf("the query returned unsupported result type %s", body.Data.ResultType)
+	}
+}
+
+// parseSampleValue parses the value of a [timestamp, "value"] sample.
+func parseSampleValue(sample [2]interface{}) (float64, error) {
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid sample value %v", sample[1])
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid sample value %s", value)
+	}
+	return f, nil
+}
@@ -18,6 +18,7 @@ package jobflow
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -38,6 +39,7 @@ import (
 	batchlister "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
 	flowlister "volcano.sh/apis/pkg/client/listers/flow/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/events"
 	"volcano.sh/volcano/pkg/controllers/framework"
 	"volcano.sh/volcano/pkg/controllers/jobflow/state"
 	jobflowstate "volcano.sh/volcano/pkg/controllers/jobflow/state"
@@ -74,6 +76,8 @@ type jobflowcontroller struct {
 
 	// JobFlow Event recorder
 	recorder record.EventRecorder
+	// eventBroadcaster sends the events of the recorder, it is flushed when the controller stops
+	eventBroadcaster record.EventBroadcaster
 
 	queue          workqueue.RateLimitingInterface
 	enqueueJobFlow func(req apis.FlowRequest)
@@ -81,6 +85,8 @@ type jobflowcontroller struct {
 	syncHandler func(req *apis.FlowRequest) error
 
 	maxRequeueNum int
+	// workers is the number of workers of the queue
+	workers uint32
 }
 
 func (jf *jobflowcontroller) Name() string {
@@ -97,10 +103,10 @@ func (jf *jobflowcontroller) Initialize(opt *framework.ControllerOption) error {
 	jf.jobFlowInformer = factory.Flow().V1alpha1().JobFlows()
 	jf.jobFlowSynced = jf.jobFlowInformer.Informer().HasSynced
 	jf.jobFlowLister = jf.jobFlowInformer.Lister()
-	jf.jobFlowInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	jf.jobFlowInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    jf.addJobFlow,
 		UpdateFunc: jf.updateJobFlow,
-	})
+	}), opt.ResyncPeriod)
 
 	jf.jobTemplateInformer = factory.Flow().V1alpha1().JobTemplates()
 	jf.jobTemplateSynced = jf.jobTemplateInformer.Informer().HasSynced
@@ -109,21 +115,23 @@ func (jf *jobflowcontroller) Initialize(opt *framework.ControllerOption) error {
 	jf.jobInformer = factory.Batch().V1alpha1().Jobs()
 	jf.jobSynced = jf.jobInformer.Informer().HasSynced
 	jf.jobLister = jf.jobInformer.Lister()
-	jf.jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	jf.jobInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 		UpdateFunc: jf.updateJob,
-	})
+	}), opt.ResyncPeriod)
 
+	jf.workers = opt.GetWorkers(1)
 	jf.maxRequeueNum = opt.MaxRequeueNum
 	if jf.maxRequeueNum < 0 {
 		jf.maxRequeueNum = -1
 	}
 
 	eventBroadcaster := record.NewBroadcaster()
+	jf.eventBroadcaster = eventBroadcaster
 	eventBroadcaster.StartLogging(klog.Infof)
 	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: jf.kubeClient.CoreV1().Events("")})
 
 	jf.recorder = eventBroadcaster.NewRecorder(versionedscheme.Scheme, v1.EventSource{Component: "vc-controller-manager"})
-	jf.queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	jf.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "jobflow")
 
 	jf.enqueueJobFlow = jf.enqueue
 
@@ -144,11 +152,23 @@ func (jf *jobflowcontroller) Run(stopCh <-chan struct{}) {
 		}
 	}
 
-	go wait.Until(jf.worker, time.Second, stopCh)
+	var workers sync.WaitGroup
+	for i := 0; i < int(jf.workers); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			wait.Until(jf.worker, time.Second, stopCh)
+		}()
+	}
 
 	klog.Infof("JobFlowController is running ...... ")
 
 	<-stopCh
+
+	// the workers handle the requests left in the queue, and the events they recorded are sent before the controller stops
+	jf.queue.ShutDown()
+	workers.Wait()
+	jf.eventBroadcaster.Shutdown()
 }
 
 func (jf *jobflowcontroller) worker() {
@@ -226,18 +246,18 @@ func (jf *jobflowcontroller) handleJobFlowErr(err error, obj interface{}) {
 	}
 
 	req, _ := obj.(apis.FlowRequest)
-	jf.recordEventsForJobFlow(req.Namespace, req.JobFlowName, v1.EventTypeWarning, string(req.Action),
+	jf.recordEventsForJobFlow(req.Namespace, req.JobFlowName, events.Reason(req.Action),
 		fmt.Sprintf("%v JobFlow failed for %v", req.Action, err))
 	klog.V(4).Infof("Dropping JobFlow request %v out of the queue for %v.", obj, err)
 	jf.queue.Forget(obj)
 }
 
-func (jf *jobflowcontroller) recordEventsForJobFlow(namespace, name, eventType, reason, message string) {
+func (jf *jobflowcontroller) recordEventsForJobFlow(namespace, name string, reason events.Reason, message string) {
 	jobFlow, err := jf.jobFlowLister.JobFlows(namespace).Get(name)
 	if err != nil {
 		klog.Errorf("Get JobFlow %s failed for %v.", name, err)
 		return
 	}
 
-	jf.recorder.Event(jobFlow, eventType, reason, message)
+	events.RecordWarning(jf.recorder, jobFlow, reason, message)
 }
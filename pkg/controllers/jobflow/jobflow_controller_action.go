@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -32,6 +31,8 @@ import (
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
 	"volcano.sh/apis/pkg/client/clientset/versioned/scheme"
+	"volcano.sh/volcano/pkg/controllers/events"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/jobflow/state"
 )
 
@@ -135,7 +136,7 @@ func (jf *jobflowcontroller) createJob(jobFlow *v1alpha1flow.JobFlow, flow v1alp
 		}
 		return err
 	}
-	jf.recorder.Eventf(jobFlow, corev1.EventTypeNormal, "Created", fmt.Sprintf("create a job named %v!", job.Name))
+	events.Recordf(jf.recorder, jobFlow, events.CreatedJob, "create a job named %v!", job.Name)
 	return nil
 }
 
@@ -258,6 +259,15 @@ func (jf *jobflowcontroller) loadJobTemplateAndSetJob(jobFlow *v1alpha1flow.JobF
 		return err
 	}
 
+	values, err := jobhelpers.GetFlowTemplateValues(jobFlow, flowName)
+	if err != nil {
+		return err
+	}
+	spec, resolved, err := jobhelpers.ExpandJobTemplate(jobTemplate, values)
+	if err != nil {
+		return err
+	}
+
 	*job = v1alpha1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jobName,
@@ -271,9 +281,12 @@ func (jf *jobflowcontroller) loadJobTemplateAndSetJob(jobFlow *v1alpha1flow.JobF
 				CreatedByJobFlow:     GenerateObjectString(jobFlow.Namespace, jobFlow.Name),
 			},
 		},
-		Spec:   jobTemplate.Spec,
+		Spec:   *spec,
 		Status: v1alpha1.JobStatus{},
 	}
+	if len(resolved) != 0 {
+		jobhelpers.SetTemplateValues(job, flowName, resolved)
+	}
 
 	return controllerutil.SetControllerReference(jobFlow, job, scheme.Scheme)
 }
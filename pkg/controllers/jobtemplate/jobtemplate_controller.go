@@ -18,6 +18,7 @@ package jobtemplate
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -38,6 +39,7 @@ import (
 	batchlister "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
 	flowlister "volcano.sh/apis/pkg/client/listers/flow/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/events"
 	"volcano.sh/volcano/pkg/controllers/framework"
 )
 
@@ -67,6 +69,8 @@ type jobtemplatecontroller struct {
 
 	// JobTemplate Event recorder
 	recorder record.EventRecorder
+	// eventBroadcaster sends the events of the recorder, it is flushed when the controller stops
+	eventBroadcaster record.EventBroadcaster
 
 	queue              workqueue.RateLimitingInterface
 	enqueueJobTemplate func(req apis.FlowRequest)
@@ -74,6 +78,8 @@ type jobtemplatecontroller struct {
 	syncHandler func(req *apis.FlowRequest) error
 
 	maxRequeueNum int
+	// workers is the number of workers of the queue
+	workers uint32
 }
 
 func (jt *jobtemplatecontroller) Name() string {
@@ -90,28 +96,30 @@ func (jt *jobtemplatecontroller) Initialize(opt *framework.ControllerOption) err
 	jt.jobTemplateInformer = factory.Flow().V1alpha1().JobTemplates()
 	jt.jobTemplateSynced = jt.jobTemplateInformer.Informer().HasSynced
 	jt.jobTemplateLister = jt.jobTemplateInformer.Lister()
-	jt.jobTemplateInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	jt.jobTemplateInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: jt.addJobTemplate,
-	})
+	}), opt.ResyncPeriod)
 
 	jt.jobInformer = factory.Batch().V1alpha1().Jobs()
 	jt.jobSynced = jt.jobInformer.Informer().HasSynced
 	jt.jobLister = jt.jobInformer.Lister()
-	jt.jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	jt.jobInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: jt.addJob,
-	})
+	}), opt.ResyncPeriod)
 
+	jt.workers = opt.GetWorkers(1)
 	jt.maxRequeueNum = opt.MaxRequeueNum
 	if jt.maxRequeueNum < 0 {
 		jt.maxRequeueNum = -1
 	}
 
 	eventBroadcaster := record.NewBroadcaster()
+	jt.eventBroadcaster = eventBroadcaster
 	eventBroadcaster.StartLogging(klog.Infof)
 	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: jt.kubeClient.CoreV1().Events("")})
 
 	jt.recorder = eventBroadcaster.NewRecorder(versionedscheme.Scheme, v1.EventSource{Component: "vc-controller-manager"})
-	jt.queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	jt.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "jobtemplate")
 
 	jt.enqueueJobTemplate = jt.enqueue
 
@@ -131,11 +139,23 @@ func (jt *jobtemplatecontroller) Run(stopCh <-chan struct{}) {
 		}
 	}
 
-	go wait.Until(jt.worker, time.Second, stopCh)
+	var workers sync.WaitGroup
+	for i := 0; i < int(jt.workers); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			wait.Until(jt.worker, time.Second, stopCh)
+		}()
+	}
 
 	klog.Infof("JobTemplateController is running ...... ")
 
 	<-stopCh
+
+	// the workers handle the requests left in the queue, and the events they recorded are sent before the controller stops
+	jt.queue.ShutDown()
+	workers.Wait()
+	jt.eventBroadcaster.Shutdown()
 }
 
 func (jt *jobtemplatecontroller) worker() {
@@ -208,18 +228,18 @@ func (jt *jobtemplatecontroller) handleJobTemplateErr(err error, obj interface{}
 	}
 
 	req, _ := obj.(*apis.FlowRequest)
-	jt.recordEventsForJobTemplate(req.Namespace, req.JobTemplateName, v1.EventTypeWarning, string(req.Action),
+	jt.recordEventsForJobTemplate(req.Namespace, req.JobTemplateName, events.Reason(req.Action),
 		fmt.Sprintf("%v JobTemplate failed for %v", req.Action, err))
 	klog.V(2).Infof("Dropping JobTemplate request %v out of the queue for %v.", obj, err)
 	jt.queue.Forget(obj)
 }
 
-func (jt *jobtemplatecontroller) recordEventsForJobTemplate(namespace, name, eventType, reason, message string) {
+func (jt *jobtemplatecontroller) recordEventsForJobTemplate(namespace, name string, reason events.Reason, message string) {
 	jobTemplate, err := jt.jobTemplateLister.JobTemplates(namespace).Get(name)
 	if err != nil {
 		klog.Errorf("Get JobTemplate %s failed for %v.", name, err)
 		return
 	}
 
-	jt.recorder.Event(jobTemplate, eventType, reason, message)
+	events.RecordWarning(jt.recorder, jobTemplate, reason, message)
 }
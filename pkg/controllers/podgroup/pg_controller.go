@@ -18,6 +18,7 @@ package podgroup
 
 import (
 	"slices"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/util/wait"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
@@ -25,6 +26,8 @@ import (
 	appinformers "k8s.io/client-go/informers/apps/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	applisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -35,6 +38,7 @@ import (
 	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
 	schedulinginformer "volcano.sh/apis/pkg/client/informers/externalversions/scheduling/v1beta1"
 	schedulinglister "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
 	"volcano.sh/volcano/pkg/controllers/framework"
 	"volcano.sh/volcano/pkg/features"
 )
@@ -66,7 +70,19 @@ type pgcontroller struct {
 	// A store of replicaset
 	rsSynced func() bool
 
+	// A store of namespaces
+	nsLister corelisters.NamespaceLister
+	nsSynced func() bool
+
+	// The stores of the workloads whose pod templates give the minResources of their podgroups
+	rsLister  applisters.ReplicaSetLister
+	ssLister  applisters.StatefulSetLister
+	dsLister  applisters.DaemonSetLister
+	jobLister batchlisters.JobLister
+
 	queue workqueue.RateLimitingInterface
+	// pgQueue holds the podgroups whose minResources are to be computed
+	pgQueue workqueue.RateLimitingInterface
 
 	schedulerNames []string
 	workers        uint32
@@ -83,9 +99,10 @@ func (pg *pgcontroller) Name() string {
 func (pg *pgcontroller) Initialize(opt *framework.ControllerOption) error {
 	pg.kubeClient = opt.KubeClient
 	pg.vcClient = opt.VolcanoClient
-	pg.workers = opt.WorkerThreadsForPG
+	pg.workers = opt.GetWorkers(opt.WorkerThreadsForPG)
 
-	pg.queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	pg.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "podgroup-pod")
+	pg.pgQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "podgroup")
 
 	pg.schedulerNames = make([]string, len(opt.SchedulerNames))
 	copy(pg.schedulerNames, opt.SchedulerNames)
@@ -95,29 +112,45 @@ func (pg *pgcontroller) Initialize(opt *framework.ControllerOption) error {
 	pg.podInformer = opt.SharedInformerFactory.Core().V1().Pods()
 	pg.podLister = pg.podInformer.Lister()
 	pg.podSynced = pg.podInformer.Informer().HasSynced
-	pg.podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	pg.podInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: pg.addPod,
-	})
+	}), opt.ResyncPeriod)
+
+	nsInformer := opt.SharedInformerFactory.Core().V1().Namespaces()
+	pg.nsLister = nsInformer.Lister()
+	pg.nsSynced = nsInformer.Informer().HasSynced
+
+	pg.rsLister = opt.SharedInformerFactory.Apps().V1().ReplicaSets().Lister()
+	pg.ssLister = opt.SharedInformerFactory.Apps().V1().StatefulSets().Lister()
+	pg.dsLister = opt.SharedInformerFactory.Apps().V1().DaemonSets().Lister()
+	pg.jobLister = opt.SharedInformerFactory.Batch().V1().Jobs().Lister()
 
 	factory := opt.VCSharedInformerFactory
 	pg.vcInformerFactory = factory
 	pg.pgInformer = factory.Scheduling().V1beta1().PodGroups()
 	pg.pgLister = pg.pgInformer.Lister()
 	pg.pgSynced = pg.pgInformer.Informer().HasSynced
+	pg.pgInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    pg.addPodGroup,
+		UpdateFunc: pg.updatePodGroup,
+	}), opt.ResyncPeriod)
 
 	if utilfeature.DefaultFeatureGate.Enabled(features.WorkLoadSupport) {
 		pg.rsInformer = pg.informerFactory.Apps().V1().ReplicaSets()
 		pg.rsSynced = pg.rsInformer.Informer().HasSynced
-		pg.rsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		pg.rsInformer.Informer().AddEventHandlerWithResyncPeriod(opt.Namespaces.FilterHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc:    pg.addReplicaSet,
 			UpdateFunc: pg.updateReplicaSet,
-		})
+		}), opt.ResyncPeriod)
 	}
 	return nil
 }
 
 // Run start NewPodgroupController.
 func (pg *pgcontroller) Run(stopCh <-chan struct{}) {
+	defer pg.queue.ShutDown()
+	defer pg.pgQueue.ShutDown()
+
 	pg.informerFactory.Start(stopCh)
 	pg.vcInformerFactory.Start(stopCh)
 
@@ -133,11 +166,28 @@ func (pg *pgcontroller) Run(stopCh <-chan struct{}) {
 		}
 	}
 
+	var workers sync.WaitGroup
 	for i := 0; i < int(pg.workers); i++ {
-		go wait.Until(pg.worker, 0, stopCh)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			wait.Until(pg.worker, 0, stopCh)
+		}()
 	}
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		wait.Until(pg.pgWorker, 0, stopCh)
+	}()
 
 	klog.Infof("PodgroupController is running ...... ")
+
+	<-stopCh
+
+	// the workers handle the requests left in the queues before the controller stops
+	pg.queue.ShutDown()
+	pg.pgQueue.ShutDown()
+	workers.Wait()
 }
 
 func (pg *pgcontroller) worker() {
@@ -171,9 +221,20 @@ func (pg *pgcontroller) processNextReq() bool {
 		return true
 	}
 
-	// normal pod use volcano
-	klog.V(4).Infof("Try to create podgroup for pod %s/%s", pod.Namespace, pod.Name)
-	if err := pg.createNormalPodPGIfNotExist(pod); err != nil {
+	gangName, minMember, err := apis.ParseGang(pod.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to get gang of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	if gangName != "" {
+		// pods annotated with the same gang share a podgroup
+		klog.V(4).Infof("Try to create podgroup %s for gang pod %s/%s", gangName, pod.Namespace, pod.Name)
+		err = pg.createPodPGIfNotExist(pod, gangName, minMember, true)
+	} else {
+		// normal pod use volcano
+		klog.V(4).Infof("Try to create podgroup for pod %s/%s", pod.Namespace, pod.Name)
+		err = pg.createNormalPodPGIfNotExist(pod)
+	}
+	if err != nil {
 		klog.Errorf("Failed to handle Pod <%s/%s>: %v", pod.Namespace, pod.Name, err)
 		pg.queue.AddRateLimited(req)
 		return true
@@ -28,11 +28,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/klog/v2"
 
 	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/apis/helpers"
 	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
 	"volcano.sh/volcano/pkg/controllers/util"
 )
 
@@ -62,6 +64,11 @@ func (pg *pgcontroller) addPod(obj interface{}) {
 	}
 
 	pg.queue.Add(req)
+
+	// the minResources of the podgroup may be computed from its pods
+	if pgName := pod.Annotations[scheduling.KubeGroupNameAnnotationKey]; pgName != "" {
+		pg.pgQueue.Add(pod.Namespace + "/" + pgName)
+	}
 }
 
 func (pg *pgcontroller) addReplicaSet(obj interface{}) {
@@ -193,16 +200,53 @@ func (pg *pgcontroller) inheritUpperAnnotations(pod *v1.Pod, obj *scheduling.Pod
 	}
 }
 
+// namespaceDefaultQueue returns the default queue of the namespace set by its
+// scheduling.volcano.sh/queue-name annotation, or "" to let the admission decide.
+func (pg *pgcontroller) namespaceDefaultQueue(namespace string) string {
+	ns, err := pg.nsLister.Get(namespace)
+	if err != nil {
+		klog.V(4).Infof("Failed to get namespace %s for its default queue: %v", namespace, err)
+		return ""
+	}
+	return ns.Annotations[scheduling.QueueNameAnnotationKey]
+}
+
 func (pg *pgcontroller) createNormalPodPGIfNotExist(pod *v1.Pod) error {
-	pgName := helpers.GeneratePodgroupName(pod)
+	return pg.createPodPGIfNotExist(pod, helpers.GeneratePodgroupName(pod), 1, false)
+}
 
-	if _, err := pg.pgLister.PodGroups(pod.Namespace).Get(pgName); err != nil {
+// createPodPGIfNotExist creates the PodGroup of the pod if it does not exist and annotates the pod
+// with it. The PodGroup of a gang is shared by all the pods of the namespace annotated with the same
+// gang name and is created from the first pod of the gang, its minimal resources are those of
+// minMember such pods. It is owned by the owners of all the pods of the gang. A PodGroup named after
+// the gang which is not annotated with the gang, e.g. the PodGroup of a Volcano Job, is not joined.
+func (pg *pgcontroller) createPodPGIfNotExist(pod *v1.Pod, pgName string, minMember int32, gang bool) error {
+	if podGroup, err := pg.pgLister.PodGroups(pod.Namespace).Get(pgName); err == nil {
+		if gang && podGroup.Annotations[apis.GangNameKey] != pgName {
+			klog.Warningf("Pod <%s/%s> is not added to PodGroup %s of gang %s, the PodGroup was not created for the gang",
+				pod.Namespace, pod.Name, pgName, pgName)
+			return nil
+		}
+		if err := pg.addPGOwner(podGroup, pod); err != nil {
+			klog.Errorf("Failed to add owner of Pod <%s/%s> to PodGroup %s: %v",
+				pod.Namespace, pod.Name, pgName, err)
+			return err
+		}
+	} else {
 		if !apierrors.IsNotFound(err) {
-			klog.Errorf("Failed to get normal PodGroup for Pod <%s/%s>: %v",
+			klog.Errorf("Failed to get PodGroup for Pod <%s/%s>: %v",
 				pod.Namespace, pod.Name, err)
 			return err
 		}
 
+		minResources := util.GetPodQuotaUsage(pod)
+		if minResources != nil {
+			podResources := *minResources
+			for i := int32(1); i < minMember; i++ {
+				*minResources = quotav1.Add(*minResources, podResources)
+			}
+		}
+
 		obj := &scheduling.PodGroup{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace:       pod.Namespace,
@@ -212,9 +256,9 @@ func (pg *pgcontroller) createNormalPodPGIfNotExist(pod *v1.Pod) error {
 				Labels:          map[string]string{},
 			},
 			Spec: scheduling.PodGroupSpec{
-				MinMember:         1,
+				MinMember:         minMember,
 				PriorityClassName: pod.Spec.PriorityClassName,
-				MinResources:      util.GetPodQuotaUsage(pod),
+				MinResources:      minResources,
 			},
 			Status: scheduling.PodGroupStatus{
 				Phase: scheduling.PodGroupPending,
@@ -222,10 +266,16 @@ func (pg *pgcontroller) createNormalPodPGIfNotExist(pod *v1.Pod) error {
 		}
 
 		pg.inheritUpperAnnotations(pod, obj)
+		if gang {
+			obj.Annotations[apis.GangNameKey] = pgName
+		}
 		// Individual annotations on pods would overwrite annotations inherited from upper resources.
 		if queueName, ok := pod.Annotations[scheduling.QueueNameAnnotationKey]; ok {
 			obj.Spec.Queue = queueName
 		}
+		if obj.Spec.Queue == "" {
+			obj.Spec.Queue = pg.namespaceDefaultQueue(pod.Namespace)
+		}
 
 		if value, ok := pod.Annotations[scheduling.PodPreemptable]; ok {
 			obj.Annotations[scheduling.PodPreemptable] = value
@@ -250,14 +300,14 @@ func (pg *pgcontroller) createNormalPodPGIfNotExist(pod *v1.Pod) error {
 		}
 
 		if _, err := pg.vcClient.SchedulingV1beta1().PodGroups(pod.Namespace).Create(context.TODO(), obj, metav1.CreateOptions{}); err != nil {
-			if !apierrors.IsAlreadyExists(err) {
-				klog.Errorf("Failed to create normal PodGroup for Pod <%s/%s>: %v",
+			if !apierrors.IsAlreadyExists(err) || gang {
+				// the pod of a gang is retried to check the PodGroup created meanwhile once it is cached
+				klog.Errorf("Failed to create PodGroup for Pod <%s/%s>: %v",
 					pod.Namespace, pod.Name, err)
 				return err
-			} else {
-				klog.V(4).Infof("PodGroup <%s/%s> already exists for Pod <%s/%s>",
-					pod.Namespace, pgName, pod.Namespace, pod.Name)
 			}
+			klog.V(4).Infof("PodGroup <%s/%s> already exists for Pod <%s/%s>",
+				pod.Namespace, pgName, pod.Namespace, pod.Name)
 		} else {
 			klog.V(4).Infof("PodGroup <%s/%s> created for Pod <%s/%s>",
 				pod.Namespace, pgName, pod.Namespace, pod.Name)
@@ -267,6 +317,39 @@ func (pg *pgcontroller) createNormalPodPGIfNotExist(pod *v1.Pod) error {
 	return pg.updatePodAnnotations(pod, pgName)
 }
 
+// addPGOwner adds the owner of the pod to the owners of the PodGroup shared by several workloads,
+// e.g. the Deployment and the StatefulSet of a gang, so that the PodGroup is garbage collected once
+// all its owners are gone. The first owner stays the controller of the PodGroup. A PodGroup without
+// owners is managed by its creator and is kept as it is.
+func (pg *pgcontroller) addPGOwner(podGroup *scheduling.PodGroup, pod *v1.Pod) error {
+	if len(podGroup.OwnerReferences) == 0 {
+		return nil
+	}
+
+	var owner metav1.OwnerReference
+	for _, ref := range newPGOwnerReferences(pod) {
+		if ref.Controller != nil && *ref.Controller {
+			owner = ref
+			break
+		}
+	}
+	for _, ref := range podGroup.OwnerReferences {
+		if ref.UID == owner.UID {
+			return nil
+		}
+	}
+
+	isController := false
+	owner.Controller = &isController
+	newPodGroup := podGroup.DeepCopy()
+	newPodGroup.OwnerReferences = append(newPodGroup.OwnerReferences, owner)
+	if _, err := pg.vcClient.SchedulingV1beta1().PodGroups(pod.Namespace).Update(context.TODO(), newPodGroup, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.V(4).Infof("Added %s %s as owner of PodGroup <%s/%s>", owner.Kind, owner.Name, pod.Namespace, podGroup.Name)
+	return nil
+}
+
 func newPGOwnerReferences(pod *v1.Pod) []metav1.OwnerReference {
 	if len(pod.OwnerReferences) != 0 {
 		for _, ownerReference := range pod.OwnerReferences {
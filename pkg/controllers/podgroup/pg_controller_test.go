@@ -20,16 +20,20 @@ import (
 	"context"
 	"testing"
 
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
 	kubeclient "k8s.io/client-go/kubernetes/fake"
 
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
 	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/controllers/apis"
 	"volcano.sh/volcano/pkg/controllers/framework"
 )
 
@@ -190,3 +194,396 @@ func TestAddPodGroup(t *testing.T) {
 		}
 	}
 }
+
+func TestNamespaceDefaultQueue(t *testing.T) {
+	testCases := []struct {
+		name          string
+		namespace     *v1.Namespace
+		podQueue      string
+		expectedQueue string
+	}{
+		{
+			name:          "namespace without default queue",
+			namespace:     &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			expectedQueue: "",
+		},
+		{
+			name: "default queue of the namespace",
+			namespace: &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "test",
+				Annotations: map[string]string{scheduling.QueueNameAnnotationKey: "team-a"},
+			}},
+			expectedQueue: "team-a",
+		},
+		{
+			name: "queue of the pod overrides the namespace",
+			namespace: &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "test",
+				Annotations: map[string]string{scheduling.QueueNameAnnotationKey: "team-a"},
+			}},
+			podQueue:      "research",
+			expectedQueue: "research",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := newFakeController()
+			c.informerFactory.Core().V1().Namespaces().Informer().GetIndexer().Add(testCase.namespace)
+
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: testCase.namespace.Name,
+					UID:       types.UID("7a09885b-b753-4924-9fba-77c0836bac20"),
+				},
+			}
+			if testCase.podQueue != "" {
+				pod.Annotations = map[string]string{scheduling.QueueNameAnnotationKey: testCase.podQueue}
+			}
+			if _, err := c.kubeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create pod: %v", err)
+			}
+			if err := c.createNormalPodPGIfNotExist(pod); err != nil {
+				t.Fatalf("failed to create podgroup: %v", err)
+			}
+
+			pg, err := c.vcClient.SchedulingV1beta1().PodGroups(pod.Namespace).Get(context.TODO(),
+				"podgroup-7a09885b-b753-4924-9fba-77c0836bac20", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get podgroup: %v", err)
+			}
+			if pg.Spec.Queue != testCase.expectedQueue {
+				t.Errorf("expected queue %q, got %q", testCase.expectedQueue, pg.Spec.Queue)
+			}
+		})
+	}
+}
+
+func TestGangPodGroup(t *testing.T) {
+	namespace := "test"
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				UID:       types.UID(name),
+				Annotations: map[string]string{
+					apis.GangNameKey:      "spark-app",
+					apis.GangMinMemberKey: "2",
+				},
+			},
+			Spec: v1.PodSpec{
+				SchedulerName: "volcano",
+				Containers: []v1.Container{{
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					},
+				}},
+			},
+		}
+	}
+
+	c := newFakeController()
+	for _, pod := range []*v1.Pod{newPod("driver"), newPod("executor-1")} {
+		if _, err := c.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+		c.podInformer.Informer().GetIndexer().Add(pod)
+		c.addPod(pod)
+		c.processNextReq()
+		// the informers are not running, keep the podgroup cache up to date
+		if pg, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "spark-app", metav1.GetOptions{}); err == nil {
+			c.pgInformer.Informer().GetIndexer().Update(pg)
+		}
+	}
+
+	pg, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "spark-app", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get podgroup of the gang: %v", err)
+	}
+	if pg.Spec.MinMember != 2 {
+		t.Errorf("expected min member 2, got %d", pg.Spec.MinMember)
+	}
+	if cpu := (*pg.Spec.MinResources)[v1.ResourceCPU]; cpu.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("expected min cpu 2, got %s", cpu.String())
+	}
+	for _, name := range []string{"driver", "executor-1"} {
+		pod, _ := c.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if pgName := pod.Annotations[scheduling.KubeGroupNameAnnotationKey]; pgName != "spark-app" {
+			t.Errorf("expected pod %s in podgroup spark-app, got %q", name, pgName)
+		}
+	}
+}
+
+func TestSyncMinResources(t *testing.T) {
+	namespace := "test"
+	isController := true
+	podSpec := func(cpu string) v1.PodSpec {
+		return v1.PodSpec{
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+				},
+			}},
+		}
+	}
+	newPod := func(name, cpu string, created int64) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: metav1.Unix(created, 0),
+				Annotations:       map[string]string{scheduling.KubeGroupNameAnnotationKey: "pg1"},
+			},
+			Spec: podSpec(cpu),
+		}
+	}
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs1", Namespace: namespace},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: v1.PodTemplateSpec{Spec: podSpec("2")},
+		},
+	}
+	ownedPod := newPod("p1", "1", 1)
+	ownedPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs1", Controller: &isController}}
+
+	testCases := []struct {
+		name        string
+		owner       *metav1.OwnerReference
+		minMember   int32
+		minCPU      string
+		pods        []*v1.Pod
+		expectedCPU string
+	}{
+		{
+			name:        "computed from the pod template of the replicaset",
+			owner:       &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs1", Controller: &isController},
+			minMember:   3,
+			expectedCPU: "6",
+		},
+		{
+			name:        "computed from the first created pods of the podgroup",
+			minMember:   2,
+			pods:        []*v1.Pod{newPod("p3", "4", 3), newPod("p1", "1", 1), newPod("p2", "2", 2)},
+			expectedCPU: "3",
+		},
+		{
+			name:        "computed from the first pod before minMember pods are created",
+			minMember:   3,
+			pods:        []*v1.Pod{newPod("p2", "2", 2), newPod("p1", "1", 1)},
+			expectedCPU: "4",
+		},
+		{
+			name:        "computed from the pod template of the owner of the pods",
+			minMember:   2,
+			pods:        []*v1.Pod{ownedPod},
+			expectedCPU: "4",
+		},
+		{
+			name:      "not computed before a pod is created",
+			minMember: 2,
+		},
+		{
+			name:        "set minResources are kept",
+			minMember:   2,
+			minCPU:      "10",
+			pods:        []*v1.Pod{newPod("p1", "1", 1), newPod("p2", "2", 2)},
+			expectedCPU: "10",
+		},
+		{
+			name:      "podgroups of vcjobs are skipped",
+			owner:     &metav1.OwnerReference{APIVersion: batchv1alpha1.SchemeGroupVersion.String(), Kind: "Job", Name: "job1", Controller: &isController},
+			minMember: 2,
+			pods:      []*v1.Pod{newPod("p1", "1", 1), newPod("p2", "2", 2)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := newFakeController()
+			c.informerFactory.Apps().V1().ReplicaSets().Informer().GetIndexer().Add(rs)
+			for _, pod := range testCase.pods {
+				c.podInformer.Informer().GetIndexer().Add(pod)
+			}
+			pg := &scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: namespace},
+				Spec:       scheduling.PodGroupSpec{MinMember: testCase.minMember},
+			}
+			if testCase.owner != nil {
+				pg.OwnerReferences = []metav1.OwnerReference{*testCase.owner}
+			}
+			if testCase.minCPU != "" {
+				pg.Spec.MinResources = &v1.ResourceList{v1.ResourceCPU: resource.MustParse(testCase.minCPU)}
+			}
+			if _, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create podgroup: %v", err)
+			}
+			c.pgInformer.Informer().GetIndexer().Add(pg)
+
+			if err := c.syncMinResources(namespace + "/pg1"); err != nil {
+				t.Fatalf("failed to sync minResources: %v", err)
+			}
+
+			pg, _ = c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "pg1", metav1.GetOptions{})
+			if testCase.expectedCPU == "" {
+				if pg.Spec.MinResources != nil {
+					t.Errorf("expected no minResources, got %v", *pg.Spec.MinResources)
+				}
+				return
+			}
+			if pg.Spec.MinResources == nil {
+				t.Fatalf("expected min cpu %s, got no minResources", testCase.expectedCPU)
+			}
+			if cpu := (*pg.Spec.MinResources)[v1.ResourceCPU]; cpu.Cmp(resource.MustParse(testCase.expectedCPU)) != 0 {
+				t.Errorf("expected min cpu %s, got %s", testCase.expectedCPU, cpu.String())
+			}
+		})
+	}
+}
+
+func TestGangPodGroupOwners(t *testing.T) {
+	namespace := "test"
+	isController := true
+	newPod := func(name, ownerKind, ownerName string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				UID:       types.UID(name),
+				Annotations: map[string]string{
+					apis.GangNameKey:      "flink",
+					apis.GangMinMemberKey: "3",
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       ownerKind,
+					Name:       ownerName,
+					UID:        types.UID(ownerName),
+					Controller: &isController,
+				}},
+			},
+			Spec: v1.PodSpec{SchedulerName: "volcano"},
+		}
+	}
+
+	c := newFakeController()
+	pods := []*v1.Pod{
+		newPod("jobmanager-0", "ReplicaSet", "jobmanager"),
+		newPod("taskmanager-0", "StatefulSet", "taskmanager"),
+		newPod("taskmanager-1", "StatefulSet", "taskmanager"),
+	}
+	for _, pod := range pods {
+		if _, err := c.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+		c.podInformer.Informer().GetIndexer().Add(pod)
+		c.addPod(pod)
+		c.processNextReq()
+		// the informers are not running, keep the podgroup cache up to date
+		if pg, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "flink", metav1.GetOptions{}); err == nil {
+			c.pgInformer.Informer().GetIndexer().Update(pg)
+		}
+	}
+
+	pg, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "flink", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get podgroup of the gang: %v", err)
+	}
+	if len(pg.OwnerReferences) != 2 {
+		t.Fatalf("expected 2 owners, got %v", pg.OwnerReferences)
+	}
+	if owner := metav1.GetControllerOf(pg); owner == nil || owner.Name != "jobmanager" {
+		t.Errorf("expected jobmanager as controller, got %v", owner)
+	}
+	if owner := pg.OwnerReferences[1]; owner.Name != "taskmanager" || *owner.Controller {
+		t.Errorf("expected taskmanager as non controller owner, got %v", owner)
+	}
+}
+
+func TestGangPodGroupWithoutOwners(t *testing.T) {
+	namespace := "test"
+	c := newFakeController()
+	pg := &scheduling.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "flink", Namespace: namespace, Annotations: map[string]string{apis.GangNameKey: "flink"}},
+		Spec:       scheduling.PodGroupSpec{MinMember: 2},
+	}
+	if _, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create podgroup: %v", err)
+	}
+	c.pgInformer.Informer().GetIndexer().Add(pg)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "jobmanager-0",
+			Namespace:   namespace,
+			UID:         types.UID("jobmanager-0"),
+			Annotations: map[string]string{apis.GangNameKey: "flink"},
+		},
+		Spec: v1.PodSpec{SchedulerName: "volcano"},
+	}
+	if _, err := c.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	c.podInformer.Informer().GetIndexer().Add(pod)
+	c.addPod(pod)
+	c.processNextReq()
+
+	pg, _ = c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "flink", metav1.GetOptions{})
+	if len(pg.OwnerReferences) != 0 {
+		t.Errorf("expected podgroup created by the user to be kept without owners, got %v", pg.OwnerReferences)
+	}
+	pod, _ = c.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), "jobmanager-0", metav1.GetOptions{})
+	if pgName := pod.Annotations[scheduling.KubeGroupNameAnnotationKey]; pgName != "flink" {
+		t.Errorf("expected pod in podgroup flink, got %q", pgName)
+	}
+}
+
+func TestGangPodGroupNotCreatedForGang(t *testing.T) {
+	namespace := "test"
+	isController := true
+	c := newFakeController()
+	pg := &scheduling.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "flink",
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "batch.volcano.sh/v1alpha1",
+				Kind:       "Job",
+				Name:       "flink",
+				UID:        types.UID("flink"),
+				Controller: &isController,
+			}},
+		},
+		Spec: scheduling.PodGroupSpec{MinMember: 2},
+	}
+	if _, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create podgroup: %v", err)
+	}
+	c.pgInformer.Informer().GetIndexer().Add(pg)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "jobmanager-0",
+			Namespace:   namespace,
+			UID:         types.UID("jobmanager-0"),
+			Annotations: map[string]string{apis.GangNameKey: "flink"},
+		},
+		Spec: v1.PodSpec{SchedulerName: "volcano"},
+	}
+	if _, err := c.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	c.podInformer.Informer().GetIndexer().Add(pod)
+	c.addPod(pod)
+	c.processNextReq()
+
+	pg, _ = c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "flink", metav1.GetOptions{})
+	if len(pg.OwnerReferences) != 1 {
+		t.Errorf("expected the owners of the podgroup to be kept, got %v", pg.OwnerReferences)
+	}
+	pod, _ = c.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), "jobmanager-0", metav1.GetOptions{})
+	if pgName, found := pod.Annotations[scheduling.KubeGroupNameAnnotationKey]; found {
+		t.Errorf("expected pod not to join the podgroup, got %q", pgName)
+	}
+}
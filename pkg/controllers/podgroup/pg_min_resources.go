@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"context"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/util"
+)
+
+func (pg *pgcontroller) addPodGroup(obj interface{}) {
+	podGroup, ok := obj.(*scheduling.PodGroup)
+	if !ok {
+		klog.Errorf("Failed to convert %v to PodGroup", obj)
+		return
+	}
+	if podGroup.Spec.MinResources != nil {
+		return
+	}
+	key, _ := cache.MetaNamespaceKeyFunc(podGroup)
+	pg.pgQueue.Add(key)
+}
+
+func (pg *pgcontroller) updatePodGroup(_, newObj interface{}) {
+	pg.addPodGroup(newObj)
+}
+
+func (pg *pgcontroller) pgWorker() {
+	for pg.processNextPodGroup() {
+	}
+}
+
+func (pg *pgcontroller) processNextPodGroup() bool {
+	obj, shutdown := pg.pgQueue.Get()
+	if shutdown {
+		return false
+	}
+	defer pg.pgQueue.Done(obj)
+
+	key := obj.(string)
+	if err := pg.syncMinResources(key); err != nil {
+		klog.Errorf("Failed to compute minResources of PodGroup %s: %v", key, err)
+		pg.pgQueue.AddRateLimited(key)
+		return true
+	}
+	pg.pgQueue.Forget(key)
+	return true
+}
+
+// syncMinResources sets the minResources of the podgroup if it is unset, so that the enqueue of the
+// podgroup checks the quota of its queue. They are computed from the pod template of the workload
+// controlling the podgroup, or from the pods of the podgroup if the workload has no known template.
+func (pg *pgcontroller) syncMinResources(key string) error {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+	podGroup, err := pg.pgLister.PodGroups(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if podGroup.Spec.MinResources != nil || podGroup.Spec.MinMember <= 0 {
+		return nil
+	}
+	// the minResources of the podgroups of vcjobs are computed by the job controller
+	if owner := metav1.GetControllerOf(podGroup); owner != nil && owner.APIVersion == batchv1alpha1.SchemeGroupVersion.String() {
+		return nil
+	}
+
+	minResources, err := pg.calcMinResources(podGroup)
+	if err != nil || minResources == nil {
+		return err
+	}
+
+	newPodGroup := podGroup.DeepCopy()
+	newPodGroup.Spec.MinResources = &minResources
+	if _, err := pg.vcClient.SchedulingV1beta1().PodGroups(namespace).Update(context.TODO(), newPodGroup, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.V(3).Infof("Set minResources of PodGroup %s to %v", key, minResources)
+	return nil
+}
+
+// calcMinResources returns the requests of minMember pods of the podgroup, nil if they are unknown yet.
+func (pg *pgcontroller) calcMinResources(podGroup *scheduling.PodGroup) (v1.ResourceList, error) {
+	template, err := pg.getPodTemplateFromOwner(metav1.GetControllerOf(podGroup), podGroup.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if template != nil {
+		return templateMinResources(template, podGroup.Spec.MinMember), nil
+	}
+
+	pods, err := pg.podLister.Pods(podGroup.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var members []*v1.Pod
+	for _, pod := range pods {
+		if pod.Annotations[scheduling.KubeGroupNameAnnotationKey] == podGroup.Name {
+			members = append(members, pod)
+		}
+	}
+	// wait for the first member to be created, the podgroup is synced again on pod creation
+	if len(members) == 0 {
+		return nil, nil
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].CreationTimestamp.Before(&members[j].CreationTimestamp)
+	})
+
+	// the pods of the podgroup are created by the same workload
+	template, err = pg.getPodTemplateFromOwner(metav1.GetControllerOf(members[0]), podGroup.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if template != nil {
+		return templateMinResources(template, podGroup.Spec.MinMember), nil
+	}
+
+	// the members which are not created yet are assumed to request as much as the first one
+	minResources := v1.ResourceList{}
+	for i := int32(0); i < podGroup.Spec.MinMember; i++ {
+		member := members[0]
+		if int(i) < len(members) {
+			member = members[i]
+		}
+		minResources = quotav1.Add(minResources, *util.GetPodQuotaUsage(member))
+	}
+	return minResources, nil
+}
+
+// templateMinResources returns the requests of minMember pods created from the pod template.
+func templateMinResources(template *v1.PodTemplateSpec, minMember int32) v1.ResourceList {
+	usage := *util.GetPodQuotaUsage(&v1.Pod{Spec: template.Spec})
+	minResources := v1.ResourceList{}
+	for i := int32(0); i < minMember; i++ {
+		minResources = quotav1.Add(minResources, usage)
+	}
+	return minResources
+}
+
+// getPodTemplateFromOwner returns the pod template of the workload from the informer caches, nil if
+// there is no owner, the kind of the workload is not known or the workload is not found.
+func (pg *pgcontroller) getPodTemplateFromOwner(owner *metav1.OwnerReference, namespace string) (*v1.PodTemplateSpec, error) {
+	if owner == nil {
+		return nil, nil
+	}
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return nil, nil
+	}
+	var template *v1.PodTemplateSpec
+	switch gv.WithKind(owner.Kind).GroupKind() {
+	case appsv1.SchemeGroupVersion.WithKind("ReplicaSet").GroupKind():
+		var rs *appsv1.ReplicaSet
+		if rs, err = pg.rsLister.ReplicaSets(namespace).Get(owner.Name); err == nil {
+			template = &rs.Spec.Template
+		}
+	case appsv1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind():
+		var ss *appsv1.StatefulSet
+		if ss, err = pg.ssLister.StatefulSets(namespace).Get(owner.Name); err == nil {
+			template = &ss.Spec.Template
+		}
+	case appsv1.SchemeGroupVersion.WithKind("DaemonSet").GroupKind():
+		var ds *appsv1.DaemonSet
+		if ds, err = pg.dsLister.DaemonSets(namespace).Get(owner.Name); err == nil {
+			template = &ds.Spec.Template
+		}
+	case batchv1.SchemeGroupVersion.WithKind("Job").GroupKind():
+		var job *batchv1.Job
+		if job, err = pg.jobLister.Jobs(namespace).Get(owner.Name); err == nil {
+			template = &job.Spec.Template
+		}
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	return template, nil
+}
@@ -28,8 +28,10 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -44,6 +46,7 @@ import (
 	busv1alpha1lister "volcano.sh/apis/pkg/client/listers/bus/v1alpha1"
 	schedulinglister "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/events"
 	"volcano.sh/volcano/pkg/controllers/framework"
 	queuestate "volcano.sh/volcano/pkg/controllers/queue/state"
 	"volcano.sh/volcano/pkg/features"
@@ -76,9 +79,22 @@ type queuecontroller struct {
 
 	vcInformerFactory vcinformer.SharedInformerFactory
 
+	// provisionConfig is the template of the queues provisioned for the namespaces,
+	// nil if queues are not provisioned.
+	provisionConfig *ProvisionConfig
+	informerFactory informers.SharedInformerFactory
+	// podIndexer indexes the pods by their podgroup, the pods of the running podgroups of
+	// a draining queue are evicted
+	podIndexer cache.Indexer
+	podSynced  cache.InformerSynced
+	nsLister   corelisters.NamespaceLister
+	nsSynced   cache.InformerSynced
+
 	// queues that need to be updated.
 	queue        workqueue.RateLimitingInterface
 	commandQueue workqueue.RateLimitingInterface
+	// namespaces whose queues need to be provisioned or deleted.
+	namespaceQueue workqueue.RateLimitingInterface
 
 	pgMutex sync.RWMutex
 	// queue name -> podgroup namespace/name
@@ -89,8 +105,12 @@ type queuecontroller struct {
 
 	enqueueQueue func(req *apis.Request)
 
-	recorder      record.EventRecorder
-	maxRequeueNum int
+	recorder record.EventRecorder
+	// eventBroadcaster sends the events of the recorder, it is flushed when the controller stops
+	eventBroadcaster record.EventBroadcaster
+	maxRequeueNum    int
+	// workers is the number of workers of the queue
+	workers uint32
 }
 
 func (c *queuecontroller) Name() string {
@@ -107,6 +127,7 @@ func (c *queuecontroller) Initialize(opt *framework.ControllerOption) error {
 	pgInformer := factory.Scheduling().V1beta1().PodGroups()
 
 	eventBroadcaster := record.NewBroadcaster()
+	c.eventBroadcaster = eventBroadcaster
 	eventBroadcaster.StartLogging(klog.Infof)
 	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: c.kubeClient.CoreV1().Events("")})
 
@@ -117,30 +138,39 @@ func (c *queuecontroller) Initialize(opt *framework.ControllerOption) error {
 	c.queueSynced = queueInformer.Informer().HasSynced
 	c.pgLister = pgInformer.Lister()
 	c.pgSynced = pgInformer.Informer().HasSynced
-	c.queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
-	c.commandQueue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	c.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "queue")
+	c.commandQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "queue-command")
 	c.podGroups = make(map[string]map[string]struct{})
 	c.recorder = eventBroadcaster.NewRecorder(versionedscheme.Scheme, v1.EventSource{Component: "vc-controller-manager"})
+	c.workers = opt.GetWorkers(1)
 	c.maxRequeueNum = opt.MaxRequeueNum
 	if c.maxRequeueNum < 0 {
 		c.maxRequeueNum = -1
 	}
 
-	queueInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	queueInformer.Informer().AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.addQueue,
 		UpdateFunc: c.updateQueue,
 		DeleteFunc: c.deleteQueue,
-	})
+	}, opt.ResyncPeriod)
 
-	pgInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	pgInformer.Informer().AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.addPodGroup,
 		UpdateFunc: c.updatePodGroup,
 		DeleteFunc: c.deletePodGroup,
-	})
+	}, opt.ResyncPeriod)
+
+	c.informerFactory = opt.SharedInformerFactory
+	podInformer := opt.SharedInformerFactory.Core().V1().Pods().Informer()
+	if err := addPodGroupIndex(podInformer); err != nil {
+		return err
+	}
+	c.podIndexer = podInformer.GetIndexer()
+	c.podSynced = podInformer.HasSynced
 
 	if utilfeature.DefaultFeatureGate.Enabled(features.QueueCommandSync) {
 		c.cmdInformer = factory.Bus().V1alpha1().Commands()
-		c.cmdInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		c.cmdInformer.Informer().AddEventHandlerWithResyncPeriod(cache.FilteringResourceEventHandler{
 			FilterFunc: func(obj interface{}) bool {
 				switch v := obj.(type) {
 				case *busv1alpha1.Command:
@@ -152,14 +182,32 @@ func (c *queuecontroller) Initialize(opt *framework.ControllerOption) error {
 			Handler: cache.ResourceEventHandlerFuncs{
 				AddFunc: c.addCommand,
 			},
-		})
+		}, opt.ResyncPeriod)
 		c.cmdLister = c.cmdInformer.Lister()
 		c.cmdSynced = c.cmdInformer.Informer().HasSynced
 	}
 
+	if opt.QueueProvisionConfig != "" {
+		provisionConfig, err := LoadProvisionConfig(opt.QueueProvisionConfig)
+		if err != nil {
+			return err
+		}
+		c.provisionConfig = provisionConfig
+		c.namespaceQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "queue-namespace")
+		nsInformer := opt.SharedInformerFactory.Core().V1().Namespaces()
+		c.nsLister = nsInformer.Lister()
+		c.nsSynced = nsInformer.Informer().HasSynced
+		nsInformer.Informer().AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addNamespace,
+			UpdateFunc: c.updateNamespace,
+			DeleteFunc: c.deleteNamespace,
+		}, opt.ResyncPeriod)
+	}
+
 	queuestate.SyncQueue = c.syncQueue
 	queuestate.OpenQueue = c.openQueue
 	queuestate.CloseQueue = c.closeQueue
+	queuestate.DrainQueue = c.drainQueue
 
 	c.syncHandler = c.handleQueue
 	c.syncCommandHandler = c.handleCommand
@@ -186,11 +234,49 @@ func (c *queuecontroller) Run(stopCh <-chan struct{}) {
 			return
 		}
 	}
+	c.informerFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, c.podSynced) {
+		klog.Errorf("Failed to sync pods for queue drain.")
+		return
+	}
 
-	go wait.Until(c.worker, 0, stopCh)
-	go wait.Until(c.commandWorker, 0, stopCh)
+	if c.provisionConfig != nil {
+		defer c.namespaceQueue.ShutDown()
+		if !cache.WaitForCacheSync(stopCh, c.nsSynced) {
+			klog.Errorf("Failed to sync namespaces for queue provisioning.")
+			return
+		}
+	}
+
+	var workers sync.WaitGroup
+	runUntil := func(f func(), period time.Duration) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			wait.Until(f, period, stopCh)
+		}()
+	}
+	for i := 0; i < int(c.workers); i++ {
+		runUntil(c.worker, 0)
+	}
+	runUntil(c.commandWorker, 0)
+	// the windows of the capability schedules are in minutes
+	runUntil(c.applyCapabilitySchedules, time.Minute)
+	if c.provisionConfig != nil {
+		runUntil(c.namespaceWorker, 0)
+		runUntil(c.enqueueOrphanedQueues, orphanedQueuesPeriod)
+	}
 
 	<-stopCh
+
+	// the workers handle the requests left in the queues, and the events they recorded are sent before the controller stops
+	c.queue.ShutDown()
+	c.commandQueue.ShutDown()
+	if c.provisionConfig != nil {
+		c.namespaceQueue.ShutDown()
+	}
+	workers.Wait()
+	c.eventBroadcaster.Shutdown()
 }
 
 // worker runs a worker thread that just dequeues items, processes them, and
@@ -264,7 +350,7 @@ func (c *queuecontroller) handleQueueErr(err error, obj interface{}) {
 	}
 
 	req, _ := obj.(*apis.Request)
-	c.recordEventsForQueue(req.QueueName, v1.EventTypeWarning, string(req.Action),
+	c.recordEventsForQueue(req.QueueName, events.Reason(req.Action),
 		fmt.Sprintf("%v queue failed for %v", req.Action, err))
 	klog.V(2).Infof("Dropping queue request %v out of the queue for %v.", obj, err)
 	c.queue.Forget(obj)
@@ -18,17 +18,24 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
-	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/events"
 	"volcano.sh/volcano/pkg/controllers/queue/state"
 )
 
@@ -38,6 +45,8 @@ func (c *queuecontroller) syncQueue(queue *schedulingv1beta1.Queue, updateStateF
 
 	podGroups := c.getPodGroups(queue.Name)
 	queueStatus := schedulingv1beta1.QueueStatus{}
+	requested := v1.ResourceList{}
+	var oldestPending *metav1.Time
 
 	for _, pgKey := range podGroups {
 		// Ignore error here, tt can not occur.
@@ -56,15 +65,25 @@ func (c *queuecontroller) syncQueue(queue *schedulingv1beta1.Queue, updateStateF
 			continue
 		}
 
+		if pg.Status.Phase != schedulingv1beta1.PodGroupCompleted && pg.Spec.MinResources != nil {
+			requested = quotav1.Add(requested, *pg.Spec.MinResources)
+		}
+
 		switch pg.Status.Phase {
-		case schedulingv1beta1.PodGroupPending:
+		// the phase set on the creation of a podgroup is dropped by its status subresource
+		case schedulingv1beta1.PodGroupPending, "":
 			queueStatus.Pending++
+			if oldestPending == nil || pg.CreationTimestamp.Before(oldestPending) {
+				oldestPending = pg.CreationTimestamp.DeepCopy()
+			}
 		case schedulingv1beta1.PodGroupRunning:
 			queueStatus.Running++
 		case schedulingv1beta1.PodGroupUnknown:
 			queueStatus.Unknown++
 		case schedulingv1beta1.PodGroupInqueue:
 			queueStatus.Inqueue++
+		case schedulingv1beta1.PodGroupCompleted:
+			queueStatus.Completed++
 		}
 	}
 
@@ -83,34 +102,112 @@ func (c *queuecontroller) syncQueue(queue *schedulingv1beta1.Queue, updateStateF
 	}
 
 	// ignore update when status does not change
-	if equality.Semantic.DeepEqual(queueStatus, queue.Status) {
+	if !equality.Semantic.DeepEqual(queueStatus, queue.Status) {
+		newQueue := queue.DeepCopy()
+		newQueue.Status = queueStatus
+		if _, err := c.vcClient.SchedulingV1beta1().Queues().UpdateStatus(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("Failed to update status of Queue %s: %v.", newQueue.Name, err)
+			return err
+		}
+
+		if queue.Status.State != "" && queue.Status.State != queueStatus.State {
+			events.Record(c.recorder, newQueue, events.QueueStateChanged,
+				fmt.Sprintf("Queue state changed from %s to %s", queue.Status.State, queueStatus.State))
+		}
+	}
+
+	if queueStatus.State == apis.QueueStateDraining {
+		if err := c.drainPodGroups(queue, podGroups); err != nil {
+			return err
+		}
+	}
+
+	return c.updateQueueUsage(queue, requested, oldestPending)
+}
+
+// updateQueueUsage publishes the resources requested by the podgroups of the queue and the creation
+// time of its oldest pending podgroup on the queue annotations, so that the backlog of the queue
+// is visible with kubectl. The podgroup counts are published in the queue status; the status of the
+// queue has no field for the requested resources nor the oldest pending podgroup.
+func (c *queuecontroller) updateQueueUsage(queue *schedulingv1beta1.Queue, requested v1.ResourceList, oldestPending *metav1.Time) error {
+	annotations := map[string]interface{}{}
+
+	requestedValue := ""
+	if len(requested) != 0 {
+		data, err := json.Marshal(requested)
+		if err != nil {
+			return err
+		}
+		requestedValue = string(data)
+	}
+	setQueueAnnotation(annotations, queue, apis.QueueRequestedKey, requestedValue)
+
+	oldestPendingValue := ""
+	if oldestPending != nil {
+		oldestPendingValue = oldestPending.UTC().Format(time.RFC3339)
+	}
+	setQueueAnnotation(annotations, queue, apis.QueueOldestPendingKey, oldestPendingValue)
+
+	if len(annotations) == 0 {
 		return nil
 	}
 
-	newQueue := queue.DeepCopy()
-	newQueue.Status = queueStatus
-	if _, err := c.vcClient.SchedulingV1beta1().Queues().UpdateStatus(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
-		klog.Errorf("Failed to update status of Queue %s: %v.", newQueue.Name, err)
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+	if _, err := c.vcClient.SchedulingV1beta1().Queues().Patch(context.TODO(), queue.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		klog.Errorf("Failed to update usage of Queue %s: %v.", queue.Name, err)
 		return err
 	}
-
 	return nil
 }
 
+// setQueueAnnotation adds the annotation to the merge patch if it changes, an empty value removes it.
+func setQueueAnnotation(patch map[string]interface{}, queue *schedulingv1beta1.Queue, key, value string) {
+	current, found := queue.Annotations[key]
+	if value == "" {
+		if found {
+			patch[key] = nil
+		}
+		return
+	}
+	if current != value {
+		patch[key] = value
+	}
+}
+
+// updateQueueState writes the state of the queue through its status subresource, after its annotations
+// if they changed, as an update of the queue does not change its status.
+func (c *queuecontroller) updateQueueState(queue, newQueue *schedulingv1beta1.Queue) error {
+	if !equality.Semantic.DeepEqual(queue.Annotations, newQueue.Annotations) {
+		updated, err := c.vcClient.SchedulingV1beta1().Queues().Update(context.TODO(), newQueue, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		state := newQueue.Status.State
+		newQueue = updated.DeepCopy()
+		newQueue.Status.State = state
+	}
+	_, err := c.vcClient.SchedulingV1beta1().Queues().UpdateStatus(context.TODO(), newQueue, metav1.UpdateOptions{})
+	return err
+}
+
 func (c *queuecontroller) openQueue(queue *schedulingv1beta1.Queue, updateStateFn state.UpdateQueueStatusFn) error {
 	klog.V(4).Infof("Begin to open queue %s.", queue.Name)
 
 	newQueue := queue.DeepCopy()
 	newQueue.Status.State = schedulingv1beta1.QueueStateOpen
+	delete(newQueue.Annotations, apis.QueueDrainStartedKey)
 
 	if queue.Status.State != newQueue.Status.State {
-		if _, err := c.vcClient.SchedulingV1beta1().Queues().Update(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
-			c.recorder.Event(newQueue, v1.EventTypeWarning, string(v1alpha1.OpenQueueAction),
+		if err := c.updateQueueState(queue, newQueue); err != nil {
+			events.RecordWarning(c.recorder, newQueue, events.OpenQueue,
 				fmt.Sprintf("Open queue failed for %v", err))
 			return err
 		}
 
-		c.recorder.Event(newQueue, v1.EventTypeNormal, string(v1alpha1.OpenQueueAction), "Open queue succeed")
+		events.Record(c.recorder, newQueue, events.OpenQueue, "Open queue succeed")
 	} else {
 		return nil
 	}
@@ -129,7 +226,7 @@ func (c *queuecontroller) openQueue(queue *schedulingv1beta1.Queue, updateStateF
 
 	if queue.Status.State != newQueue.Status.State {
 		if _, err := c.vcClient.SchedulingV1beta1().Queues().UpdateStatus(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
-			c.recorder.Event(newQueue, v1.EventTypeWarning, string(v1alpha1.OpenQueueAction),
+			events.RecordWarning(c.recorder, newQueue, events.OpenQueue,
 				fmt.Sprintf("Update queue status from %s to %s failed for %v",
 					queue.Status.State, newQueue.Status.State, err))
 			return err
@@ -146,13 +243,13 @@ func (c *queuecontroller) closeQueue(queue *schedulingv1beta1.Queue, updateState
 	newQueue.Status.State = schedulingv1beta1.QueueStateClosed
 
 	if queue.Status.State != newQueue.Status.State {
-		if _, err := c.vcClient.SchedulingV1beta1().Queues().Update(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
-			c.recorder.Event(newQueue, v1.EventTypeWarning, string(v1alpha1.CloseQueueAction),
+		if err := c.updateQueueState(queue, newQueue); err != nil {
+			events.RecordWarning(c.recorder, newQueue, events.CloseQueue,
 				fmt.Sprintf("Close queue failed for %v", err))
 			return err
 		}
 
-		c.recorder.Event(newQueue, v1.EventTypeNormal, string(v1alpha1.CloseQueueAction), "Close queue succeed")
+		events.Record(c.recorder, newQueue, events.CloseQueue, "Close queue succeed")
 	} else {
 		return nil
 	}
@@ -172,7 +269,7 @@ func (c *queuecontroller) closeQueue(queue *schedulingv1beta1.Queue, updateState
 
 	if queue.Status.State != newQueue.Status.State {
 		if _, err := c.vcClient.SchedulingV1beta1().Queues().UpdateStatus(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
-			c.recorder.Event(newQueue, v1.EventTypeWarning, string(v1alpha1.CloseQueueAction),
+			events.RecordWarning(c.recorder, newQueue, events.CloseQueue,
 				fmt.Sprintf("Update queue status from %s to %s failed for %v",
 					queue.Status.State, newQueue.Status.State, err))
 			return err
@@ -181,3 +278,127 @@ func (c *queuecontroller) closeQueue(queue *schedulingv1beta1.Queue, updateState
 
 	return nil
 }
+
+func (c *queuecontroller) drainQueue(queue *schedulingv1beta1.Queue, updateStateFn state.UpdateQueueStatusFn) error {
+	klog.V(4).Infof("Begin to drain queue %s.", queue.Name)
+
+	newQueue := queue.DeepCopy()
+	newQueue.Status.State = apis.QueueStateDraining
+	if newQueue.Annotations == nil {
+		newQueue.Annotations = map[string]string{}
+	}
+	// the running pods are evicted relative to the start of the drain
+	newQueue.Annotations[apis.QueueDrainStartedKey] = time.Now().UTC().Format(time.RFC3339)
+
+	if queue.Status.State != newQueue.Status.State {
+		if err := c.updateQueueState(queue, newQueue); err != nil {
+			events.RecordWarning(c.recorder, newQueue, events.DrainQueue,
+				fmt.Sprintf("Drain queue failed for %v", err))
+			return err
+		}
+
+		events.Record(c.recorder, newQueue, events.DrainQueue, "Drain queue succeed")
+	} else {
+		return nil
+	}
+
+	q, err := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), newQueue.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	newQueue = q.DeepCopy()
+	podGroups := c.getPodGroups(newQueue.Name)
+	if updateStateFn != nil {
+		updateStateFn(&newQueue.Status, podGroups)
+	} else {
+		return fmt.Errorf("internal error, update state function should be provided")
+	}
+
+	if queue.Status.State != newQueue.Status.State {
+		if _, err := c.vcClient.SchedulingV1beta1().Queues().UpdateStatus(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
+			events.RecordWarning(c.recorder, newQueue, events.DrainQueue,
+				fmt.Sprintf("Update queue status from %s to %s failed for %v",
+					queue.Status.State, newQueue.Status.State, err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyCapabilitySchedules applies the capability schedules of all the queues.
+func (c *queuecontroller) applyCapabilitySchedules() {
+	queues, err := c.queueLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list queues: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, queue := range queues {
+		if err := c.applyCapabilitySchedule(queue, now); err != nil {
+			klog.Errorf("Failed to apply capability schedule of Queue %s: %v.", queue.Name, err)
+		}
+	}
+}
+
+// applyCapabilitySchedule sets the capability of the queue to the capability of the window of its
+// schedule covering the time when the window starts. The capability of the queue spec is kept in an
+// annotation while a window applies, and restored when the window ends or the schedule is removed.
+// The capability is only changed when a window starts or ends, so that it can be changed in the spec
+// meanwhile.
+func (c *queuecontroller) applyCapabilitySchedule(queue *schedulingv1beta1.Queue, now time.Time) error {
+	_, hasBase := queue.Annotations[apis.QueueBaseCapabilityKey]
+	schedule, err := apis.ParseCapabilitySchedule(queue.Annotations)
+	if err != nil {
+		events.Record(c.recorder, queue, events.InvalidCapabilitySchedule, err.Error())
+	}
+	if schedule == nil && !hasBase {
+		return nil
+	}
+
+	window := -1
+	if schedule != nil {
+		window = schedule.Window(now)
+	}
+	applied := -1
+	if value, found := queue.Annotations[apis.QueueCapabilityWindowKey]; found {
+		if index, err := strconv.Atoi(value); err == nil {
+			applied = index
+		}
+	}
+	if window == applied && (window >= 0 || !hasBase) {
+		return nil
+	}
+
+	newQueue := queue.DeepCopy()
+	if newQueue.Annotations == nil {
+		newQueue.Annotations = map[string]string{}
+	}
+	var capability v1.ResourceList
+	if window >= 0 {
+		if !hasBase {
+			data, err := json.Marshal(queue.Spec.Capability)
+			if err != nil {
+				return err
+			}
+			newQueue.Annotations[apis.QueueBaseCapabilityKey] = string(data)
+		}
+		newQueue.Annotations[apis.QueueCapabilityWindowKey] = strconv.Itoa(window)
+		capability = schedule.Windows[window].Capability
+	} else {
+		capability = apis.BaseCapability(queue.Annotations, queue.Spec.Capability)
+		delete(newQueue.Annotations, apis.QueueBaseCapabilityKey)
+		delete(newQueue.Annotations, apis.QueueCapabilityWindowKey)
+	}
+	newQueue.Spec.Capability = capability.DeepCopy()
+
+	if _, err := c.vcClient.SchedulingV1beta1().Queues().Update(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(capability, queue.Spec.Capability) {
+		events.Record(c.recorder, newQueue, events.CapabilityChanged,
+			fmt.Sprintf("Queue capability changed by schedule to %v", capability))
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/events"
 )
 
 func (c *queuecontroller) enqueue(req *apis.Request) {
@@ -62,8 +63,16 @@ func (c *queuecontroller) deleteQueue(obj interface{}) {
 	delete(c.podGroups, queue.Name)
 }
 
-func (c *queuecontroller) updateQueue(_, _ interface{}) {
-	// currently do not care about queue update
+func (c *queuecontroller) updateQueue(old, new interface{}) {
+	oldQueue := old.(*schedulingv1beta1.Queue)
+	newQueue := new.(*schedulingv1beta1.Queue)
+
+	// only the drain policy of the queue is acted on by the sync
+	if oldQueue.Annotations[apis.QueueDrainTargetKey] == newQueue.Annotations[apis.QueueDrainTargetKey] &&
+		oldQueue.Annotations[apis.QueueDrainEvictAfterKey] == newQueue.Annotations[apis.QueueDrainEvictAfterKey] {
+		return
+	}
+	c.addQueue(newQueue)
 }
 
 func (c *queuecontroller) addPodGroup(obj interface{}) {
@@ -92,8 +101,12 @@ func (c *queuecontroller) updatePodGroup(old, new interface{}) {
 	oldPG := old.(*schedulingv1beta1.PodGroup)
 	newPG := new.(*schedulingv1beta1.PodGroup)
 
-	// Note: we have no use case update PodGroup.Spec.Queue
-	// So do not consider it here.
+	// the podgroup is moved to another queue, e.g. out of a draining queue
+	if oldPG.Spec.Queue != newPG.Spec.Queue {
+		c.deletePodGroup(oldPG)
+		c.addPodGroup(newPG)
+		return
+	}
 	if oldPG.Status.Phase != newPG.Status.Phase {
 		c.addPodGroup(newPG)
 	}
@@ -156,12 +169,12 @@ func (c *queuecontroller) getPodGroups(key string) []string {
 	return podGroups
 }
 
-func (c *queuecontroller) recordEventsForQueue(name, eventType, reason, message string) {
+func (c *queuecontroller) recordEventsForQueue(name string, reason events.Reason, message string) {
 	queue, err := c.queueLister.Get(name)
 	if err != nil {
 		klog.Errorf("Get queue %s failed for %v.", name, err)
 		return
 	}
 
-	c.recorder.Event(queue, eventType, reason, message)
+	events.RecordWarning(c.recorder, queue, reason, message)
 }
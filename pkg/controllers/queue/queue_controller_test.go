@@ -21,16 +21,23 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
 	kubeclient "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
 
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
 	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/controllers/apis"
 	"volcano.sh/volcano/pkg/controllers/framework"
+	queuestate "volcano.sh/volcano/pkg/controllers/queue/state"
 )
 
 func newFakeController() *queuecontroller {
@@ -38,11 +45,13 @@ func newFakeController() *queuecontroller {
 	KubeClientSet := kubeclient.NewSimpleClientset()
 
 	vcSharedInformers := informerfactory.NewSharedInformerFactory(KubeBatchClientSet, 0)
+	sharedInformers := informers.NewSharedInformerFactory(KubeClientSet, 0)
 
 	controller := &queuecontroller{}
 	opt := framework.ControllerOption{
 		VolcanoClient:           KubeBatchClientSet,
 		KubeClient:              KubeClientSet,
+		SharedInformerFactory:   sharedInformers,
 		VCSharedInformerFactory: vcSharedInformers,
 	}
 
@@ -400,3 +409,218 @@ func TestProcessNextWorkItem(t *testing.T) {
 		}
 	}
 }
+
+func TestDrainQueue(t *testing.T) {
+	c := newFakeController()
+
+	queue := &schedulingv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "drain"},
+		Spec:       schedulingv1beta1.QueueSpec{Weight: 1},
+		Status:     schedulingv1beta1.QueueStatus{State: schedulingv1beta1.QueueStateOpen},
+	}
+	pg := &schedulingv1beta1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "c1"},
+		Spec:       schedulingv1beta1.PodGroupSpec{Queue: queue.Name},
+		Status:     schedulingv1beta1.PodGroupStatus{Phase: schedulingv1beta1.PodGroupRunning},
+	}
+	c.addPodGroup(pg)
+	c.pgInformer.Informer().GetIndexer().Add(pg)
+	c.vcClient.SchedulingV1beta1().Queues().Create(context.TODO(), queue, metav1.CreateOptions{})
+
+	if err := queuestate.NewState(queue).Execute(apis.DrainQueueAction); err != nil {
+		t.Fatalf("failed to drain queue: %v", err)
+	}
+	item, _ := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), queue.Name, metav1.GetOptions{})
+	if item.Status.State != apis.QueueStateDraining {
+		t.Errorf("expected queue state %s while podgroups remain, got %s", apis.QueueStateDraining, item.Status.State)
+	}
+
+	c.deletePodGroup(pg)
+	c.pgInformer.Informer().GetIndexer().Delete(pg)
+	if err := queuestate.NewState(item).Execute(busv1alpha1.SyncQueueAction); err != nil {
+		t.Fatalf("failed to sync queue: %v", err)
+	}
+	item, _ = c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), queue.Name, metav1.GetOptions{})
+	if item.Status.State != schedulingv1beta1.QueueStateClosed {
+		t.Errorf("expected queue state %s once drained, got %s", schedulingv1beta1.QueueStateClosed, item.Status.State)
+	}
+}
+
+func TestSyncQueueUsage(t *testing.T) {
+	created := metav1.NewTime(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC))
+	newPodGroup := func(name string, phase schedulingv1beta1.PodGroupPhase, cpu string, creation metav1.Time) *schedulingv1beta1.PodGroup {
+		return &schedulingv1beta1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "c1", CreationTimestamp: creation},
+			Spec: schedulingv1beta1.PodGroupSpec{
+				Queue:        "usage",
+				MinResources: &v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+			},
+			Status: schedulingv1beta1.PodGroupStatus{Phase: phase},
+		}
+	}
+
+	testCases := []struct {
+		Name                string
+		annotations         map[string]string
+		podGroups           []*schedulingv1beta1.PodGroup
+		expectRequested     string
+		expectOldestPending string
+		expectCompleted     int32
+	}{
+		{
+			Name: "publish requested resources and oldest pending podgroup",
+			podGroups: []*schedulingv1beta1.PodGroup{
+				newPodGroup("pending-old", schedulingv1beta1.PodGroupPending, "1", created),
+				newPodGroup("pending-new", schedulingv1beta1.PodGroupPending, "1", metav1.NewTime(created.Add(time.Hour))),
+				newPodGroup("running", schedulingv1beta1.PodGroupRunning, "2", created),
+				newPodGroup("completed", schedulingv1beta1.PodGroupCompleted, "8", created),
+			},
+			expectRequested:     `{"cpu":"4"}`,
+			expectOldestPending: "2024-01-01T08:00:00Z",
+			expectCompleted:     1,
+		},
+		{
+			Name: "count the podgroup without phase as pending",
+			podGroups: []*schedulingv1beta1.PodGroup{
+				newPodGroup("created", "", "1", created),
+				newPodGroup("running", schedulingv1beta1.PodGroupRunning, "2", created),
+			},
+			expectRequested:     `{"cpu":"3"}`,
+			expectOldestPending: "2024-01-01T08:00:00Z",
+		},
+		{
+			Name: "remove oldest pending once no podgroup is pending",
+			annotations: map[string]string{
+				apis.QueueRequestedKey:     `{"cpu":"1"}`,
+				apis.QueueOldestPendingKey: "2024-01-01T08:00:00Z",
+			},
+			podGroups: []*schedulingv1beta1.PodGroup{
+				newPodGroup("running", schedulingv1beta1.PodGroupRunning, "2", created),
+			},
+			expectRequested: `{"cpu":"2"}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			c := newFakeController()
+			queue := &schedulingv1beta1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "usage", Annotations: testCase.annotations},
+				Spec:       schedulingv1beta1.QueueSpec{Weight: 1},
+			}
+			c.vcClient.SchedulingV1beta1().Queues().Create(context.TODO(), queue, metav1.CreateOptions{})
+			for _, pg := range testCase.podGroups {
+				c.addPodGroup(pg)
+				c.pgInformer.Informer().GetIndexer().Add(pg)
+			}
+
+			if err := c.syncQueue(queue, nil); err != nil {
+				t.Fatalf("failed to sync queue: %v", err)
+			}
+
+			item, _ := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), queue.Name, metav1.GetOptions{})
+			if requested := item.Annotations[apis.QueueRequestedKey]; requested != testCase.expectRequested {
+				t.Errorf("expected requested %s, got %s", testCase.expectRequested, requested)
+			}
+			if oldestPending := item.Annotations[apis.QueueOldestPendingKey]; oldestPending != testCase.expectOldestPending {
+				t.Errorf("expected oldest pending %s, got %s", testCase.expectOldestPending, oldestPending)
+			}
+			if item.Status.Completed != testCase.expectCompleted {
+				t.Errorf("expected %d completed podgroups, got %d", testCase.expectCompleted, item.Status.Completed)
+			}
+		})
+	}
+}
+
+func TestApplyCapabilitySchedule(t *testing.T) {
+	// Saturday 2024-01-06
+	weekend := time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)
+	weekday := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	schedule := `{"windows":[{"days":["Sat","Sun"],"capability":{"nvidia.com/gpu":"32"}}]}`
+	base := v1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")}
+	weekendCapability := v1.ResourceList{"nvidia.com/gpu": resource.MustParse("32")}
+
+	testCases := []struct {
+		Name             string
+		annotations      map[string]string
+		capability       v1.ResourceList
+		now              time.Time
+		expectCapability v1.ResourceList
+		expectBase       string
+		expectWindow     string
+	}{
+		{
+			Name:             "window starts",
+			annotations:      map[string]string{apis.QueueCapabilityScheduleKey: schedule},
+			capability:       base,
+			now:              weekend,
+			expectCapability: weekendCapability,
+			expectBase:       `{"nvidia.com/gpu":"8"}`,
+			expectWindow:     "0",
+		},
+		{
+			Name: "capability changed in the spec while the window applies",
+			annotations: map[string]string{
+				apis.QueueCapabilityScheduleKey: schedule,
+				apis.QueueBaseCapabilityKey:     `{"nvidia.com/gpu":"8"}`,
+				apis.QueueCapabilityWindowKey:   "0",
+			},
+			capability:       v1.ResourceList{"nvidia.com/gpu": resource.MustParse("16")},
+			now:              weekend,
+			expectCapability: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("16")},
+			expectBase:       `{"nvidia.com/gpu":"8"}`,
+			expectWindow:     "0",
+		},
+		{
+			Name: "window ends",
+			annotations: map[string]string{
+				apis.QueueCapabilityScheduleKey: schedule,
+				apis.QueueBaseCapabilityKey:     `{"nvidia.com/gpu":"8"}`,
+				apis.QueueCapabilityWindowKey:   "0",
+			},
+			capability:       weekendCapability,
+			now:              weekday,
+			expectCapability: base,
+		},
+		{
+			Name:             "no window applies",
+			annotations:      map[string]string{apis.QueueCapabilityScheduleKey: schedule},
+			capability:       base,
+			now:              weekday,
+			expectCapability: base,
+		},
+		{
+			Name:             "schedule removed",
+			annotations:      map[string]string{apis.QueueBaseCapabilityKey: `{"nvidia.com/gpu":"8"}`},
+			capability:       weekendCapability,
+			now:              weekend,
+			expectCapability: base,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			c := newFakeController()
+			queue := &schedulingv1beta1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "schedule", Annotations: testCase.annotations},
+				Spec:       schedulingv1beta1.QueueSpec{Weight: 1, Capability: testCase.capability},
+			}
+			c.vcClient.SchedulingV1beta1().Queues().Create(context.TODO(), queue, metav1.CreateOptions{})
+
+			if err := c.applyCapabilitySchedule(queue, testCase.now); err != nil {
+				t.Fatalf("failed to apply capability schedule: %v", err)
+			}
+
+			item, _ := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), queue.Name, metav1.GetOptions{})
+			if !equality.Semantic.DeepEqual(item.Spec.Capability, testCase.expectCapability) {
+				t.Errorf("expected capability %v, got %v", testCase.expectCapability, item.Spec.Capability)
+			}
+			if value := item.Annotations[apis.QueueBaseCapabilityKey]; value != testCase.expectBase {
+				t.Errorf("expected base capability %s, got %s", testCase.expectBase, value)
+			}
+			if value := item.Annotations[apis.QueueCapabilityWindowKey]; value != testCase.expectWindow {
+				t.Errorf("expected window %s, got %s", testCase.expectWindow, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/events"
+)
+
+// podGroupIndex is the index of the pod informer by the namespaced name of the podgroup of the pods.
+const podGroupIndex = "podgroup"
+
+// addPodGroupIndex indexes the pods of the informer by their podgroup, unless the index was
+// already added by another controller sharing the informer.
+func addPodGroupIndex(informer cache.SharedIndexInformer) error {
+	if _, ok := informer.GetIndexer().GetIndexers()[podGroupIndex]; ok {
+		return nil
+	}
+	return informer.AddIndexers(cache.Indexers{podGroupIndex: func(obj interface{}) ([]string, error) {
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			return nil, nil
+		}
+		pgName := pod.Annotations[schedulingv1beta1.KubeGroupNameAnnotationKey]
+		if pgName == "" {
+			return nil, nil
+		}
+		return []string{pod.Namespace + "/" + pgName}, nil
+	}})
+}
+
+// drainPodGroups moves the podgroups of the draining queue which are not running yet to the target
+// queue of the drain, and evicts the pods of the running ones once the eviction deadline of the drain
+// has passed, so that they are rescheduled in the target queue by their controllers.
+func (c *queuecontroller) drainPodGroups(queue *schedulingv1beta1.Queue, podGroups []string) error {
+	target := queue.Annotations[apis.QueueDrainTargetKey]
+	if target != "" && !c.canDrainTo(queue, target) {
+		target = ""
+	}
+	deadline, evict, err := apis.DrainEvictionDeadline(queue.Annotations)
+	if err != nil {
+		events.Record(c.recorder, queue, events.InvalidDrainPolicy, err.Error())
+	}
+	now := time.Now()
+	if evict && now.Before(deadline) {
+		c.queue.AddAfter(&apis.Request{
+			QueueName: queue.Name,
+			Event:     busv1alpha1.OutOfSyncEvent,
+			Action:    busv1alpha1.SyncQueueAction,
+		}, deadline.Sub(now))
+		evict = false
+	}
+	if target == "" && !evict {
+		return nil
+	}
+
+	var errs []error
+	for _, pgKey := range podGroups {
+		ns, name, _ := cache.SplitMetaNamespaceKey(pgKey)
+		pg, err := c.pgLister.PodGroups(ns).Get(name)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if pg.Spec.Queue != queue.Name {
+			continue
+		}
+
+		switch pg.Status.Phase {
+		case "", schedulingv1beta1.PodGroupPending, schedulingv1beta1.PodGroupInqueue:
+			if target != "" {
+				if err := c.movePodGroup(pg, target); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				events.Record(c.recorder, queue, events.PodGroupMoved,
+					fmt.Sprintf("PodGroup %s moved to queue %s", pgKey, target))
+			}
+		case schedulingv1beta1.PodGroupRunning, schedulingv1beta1.PodGroupUnknown:
+			if evict {
+				evicted, err := c.evictPodGroup(pg)
+				if err != nil {
+					errs = append(errs, err)
+				}
+				if evicted != 0 {
+					events.Record(c.recorder, queue, events.PodGroupEvicted,
+						fmt.Sprintf("Evicted %d pods of PodGroup %s, the queue drain deadline has passed", evicted, pgKey))
+				}
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// canDrainTo returns whether the podgroups of the queue can be moved to the target queue.
+func (c *queuecontroller) canDrainTo(queue *schedulingv1beta1.Queue, target string) bool {
+	if target == queue.Name {
+		events.Record(c.recorder, queue, events.InvalidDrainPolicy,
+			"The podgroups of the queue can not be moved to the queue itself")
+		return false
+	}
+	targetQueue, err := c.queueLister.Get(target)
+	if err != nil {
+		events.Record(c.recorder, queue, events.InvalidDrainPolicy,
+			fmt.Sprintf("Failed to get drain target queue %s: %v", target, err))
+		return false
+	}
+	if targetQueue.Status.State != schedulingv1beta1.QueueStateOpen {
+		events.Record(c.recorder, queue, events.InvalidDrainPolicy,
+			fmt.Sprintf("Drain target queue %s is %s, podgroups can only be moved to an open queue", target, targetQueue.Status.State))
+		return false
+	}
+	return true
+}
+
+// movePodGroup moves the podgroup to the queue. The podgroup of a volcano job is moved by moving
+// the job, the job controller then moves its podgroup and its unscheduled pods.
+func (c *queuecontroller) movePodGroup(pg *schedulingv1beta1.PodGroup, target string) error {
+	if owner := metav1.GetControllerOf(pg); owner != nil && owner.Kind == "Job" &&
+		owner.APIVersion == batchv1alpha1.SchemeGroupVersion.String() {
+		job, err := c.vcClient.BatchV1alpha1().Jobs(pg.Namespace).Get(context.TODO(), owner.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if job.Spec.Queue == target {
+			return nil
+		}
+		job.Spec.Queue = target
+		if _, err := c.vcClient.BatchV1alpha1().Jobs(job.Namespace).Update(context.TODO(), job, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to move job %s/%s to queue %s: %v", job.Namespace, job.Name, target, err)
+		}
+		klog.V(3).Infof("Moved job %s/%s from draining queue %s to queue %s", job.Namespace, job.Name, pg.Spec.Queue, target)
+		return nil
+	}
+
+	newPG := pg.DeepCopy()
+	newPG.Spec.Queue = target
+	if _, err := c.vcClient.SchedulingV1beta1().PodGroups(pg.Namespace).Update(context.TODO(), newPG, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to move podgroup %s/%s to queue %s: %v", pg.Namespace, pg.Name, target, err)
+	}
+	klog.V(3).Infof("Moved podgroup %s/%s from draining queue %s to queue %s", pg.Namespace, pg.Name, pg.Spec.Queue, target)
+	return nil
+}
+
+// evictPodGroup evicts the active pods of the podgroup through the eviction API, so that the
+// disruption budgets of the pods are respected. It returns the number of evicted pods.
+func (c *queuecontroller) evictPodGroup(pg *schedulingv1beta1.PodGroup) (int, error) {
+	objs, err := c.podIndexer.ByIndex(podGroupIndex, pg.Namespace+"/"+pg.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	evicted := 0
+	var errs []error
+	for _, obj := range objs {
+		pod, ok := obj.(*v1.Pod)
+		if !ok || pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+		if err := c.kubeClient.CoreV1().Pods(pod.Namespace).EvictV1(context.TODO(), eviction); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err))
+			continue
+		}
+		evicted++
+	}
+	return evicted, utilerrors.NewAggregate(errs)
+}
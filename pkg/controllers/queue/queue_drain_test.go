@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+func TestDrainPodGroups(t *testing.T) {
+	started := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	newPodGroup := func(name string, phase schedulingv1beta1.PodGroupPhase, owner *batchv1alpha1.Job) *schedulingv1beta1.PodGroup {
+		pg := &schedulingv1beta1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "c1"},
+			Spec:       schedulingv1beta1.PodGroupSpec{Queue: "source"},
+			Status:     schedulingv1beta1.PodGroupStatus{Phase: phase},
+		}
+		if owner != nil {
+			pg.OwnerReferences = []metav1.OwnerReference{
+				*metav1.NewControllerRef(owner, batchv1alpha1.SchemeGroupVersion.WithKind("Job")),
+			}
+		}
+		return pg
+	}
+	newPod := func(name, pgName string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "c1",
+				Annotations: map[string]string{schedulingv1beta1.KubeGroupNameAnnotationKey: pgName},
+			},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
+	job := &batchv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "c1"},
+		Spec:       batchv1alpha1.JobSpec{Queue: "source"},
+	}
+
+	testCases := []struct {
+		Name           string
+		annotations    map[string]string
+		targetState    schedulingv1beta1.QueueState
+		podGroups      []*schedulingv1beta1.PodGroup
+		pods           []*v1.Pod
+		expectQueues   map[string]string
+		expectJobQueue string
+		expectEvicted  int
+	}{
+		{
+			Name:        "move pending podgroups and jobs to the target queue",
+			annotations: map[string]string{apis.QueueDrainTargetKey: "target"},
+			targetState: schedulingv1beta1.QueueStateOpen,
+			podGroups: []*schedulingv1beta1.PodGroup{
+				newPodGroup("pending", schedulingv1beta1.PodGroupPending, nil),
+				newPodGroup("running", schedulingv1beta1.PodGroupRunning, nil),
+				newPodGroup("job1-pg", schedulingv1beta1.PodGroupInqueue, job),
+			},
+			pods:           []*v1.Pod{newPod("running-0", "running", v1.PodRunning)},
+			expectQueues:   map[string]string{"pending": "target", "running": "source", "job1-pg": "source"},
+			expectJobQueue: "target",
+		},
+		{
+			Name:        "do not move podgroups to a closed queue",
+			annotations: map[string]string{apis.QueueDrainTargetKey: "target"},
+			targetState: schedulingv1beta1.QueueStateClosed,
+			podGroups: []*schedulingv1beta1.PodGroup{
+				newPodGroup("pending", schedulingv1beta1.PodGroupPending, nil),
+			},
+			expectQueues:   map[string]string{"pending": "source"},
+			expectJobQueue: "source",
+		},
+		{
+			Name: "evict running pods after the deadline",
+			annotations: map[string]string{
+				apis.QueueDrainEvictAfterKey: "30m",
+				apis.QueueDrainStartedKey:    started,
+			},
+			podGroups: []*schedulingv1beta1.PodGroup{
+				newPodGroup("running", schedulingv1beta1.PodGroupRunning, nil),
+			},
+			pods: []*v1.Pod{
+				newPod("running-0", "running", v1.PodRunning),
+				newPod("running-1", "running", v1.PodRunning),
+				newPod("running-2", "running", v1.PodSucceeded),
+				newPod("other-0", "other", v1.PodRunning),
+			},
+			expectQueues:   map[string]string{"running": "source"},
+			expectJobQueue: "source",
+			expectEvicted:  2,
+		},
+		{
+			Name: "keep running pods before the deadline",
+			annotations: map[string]string{
+				apis.QueueDrainEvictAfterKey: "2h",
+				apis.QueueDrainStartedKey:    started,
+			},
+			podGroups: []*schedulingv1beta1.PodGroup{
+				newPodGroup("running", schedulingv1beta1.PodGroupRunning, nil),
+			},
+			pods:           []*v1.Pod{newPod("running-0", "running", v1.PodRunning)},
+			expectQueues:   map[string]string{"running": "source"},
+			expectJobQueue: "source",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			c := newFakeController()
+			queue := &schedulingv1beta1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "source", Annotations: testCase.annotations},
+				Spec:       schedulingv1beta1.QueueSpec{Weight: 1},
+				Status:     schedulingv1beta1.QueueStatus{State: apis.QueueStateDraining},
+			}
+			target := &schedulingv1beta1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "target"},
+				Spec:       schedulingv1beta1.QueueSpec{Weight: 1},
+				Status:     schedulingv1beta1.QueueStatus{State: testCase.targetState},
+			}
+			for _, q := range []*schedulingv1beta1.Queue{queue, target} {
+				c.vcClient.SchedulingV1beta1().Queues().Create(context.TODO(), q, metav1.CreateOptions{})
+				c.queueInformer.Informer().GetIndexer().Add(q)
+			}
+			c.vcClient.BatchV1alpha1().Jobs(job.Namespace).Create(context.TODO(), job.DeepCopy(), metav1.CreateOptions{})
+			var podGroups []string
+			for _, pg := range testCase.podGroups {
+				c.vcClient.SchedulingV1beta1().PodGroups(pg.Namespace).Create(context.TODO(), pg, metav1.CreateOptions{})
+				c.pgInformer.Informer().GetIndexer().Add(pg)
+				podGroups = append(podGroups, pg.Namespace+"/"+pg.Name)
+			}
+			for _, pod := range testCase.pods {
+				c.kubeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+				c.podIndexer.Add(pod)
+			}
+
+			if err := c.drainPodGroups(queue, podGroups); err != nil {
+				t.Fatalf("failed to drain podgroups: %v", err)
+			}
+
+			for name, expected := range testCase.expectQueues {
+				pg, _ := c.vcClient.SchedulingV1beta1().PodGroups("c1").Get(context.TODO(), name, metav1.GetOptions{})
+				if pg.Spec.Queue != expected {
+					t.Errorf("expected podgroup %s in queue %s, got %s", name, expected, pg.Spec.Queue)
+				}
+			}
+			item, _ := c.vcClient.BatchV1alpha1().Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if item.Spec.Queue != testCase.expectJobQueue {
+				t.Errorf("expected job in queue %s, got %s", testCase.expectJobQueue, item.Spec.Queue)
+			}
+			evicted := 0
+			for _, action := range c.kubeClient.(*kubeclient.Clientset).Actions() {
+				if action.GetVerb() == "create" && action.GetSubresource() == "eviction" {
+					evicted++
+				}
+			}
+			if evicted != testCase.expectEvicted {
+				t.Errorf("expected %d evicted pods, got %d", testCase.expectEvicted, evicted)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/events"
+)
+
+// ProvisionedForNamespaceKey is the label of the queues provisioned for a namespace, naming the namespace.
+const ProvisionedForNamespaceKey = "volcano.sh/provisioned-for-namespace"
+
+const (
+	// namespacePlaceholder is replaced by the name of the namespace in the annotations of the queue template.
+	namespacePlaceholder = "${namespace}"
+	// orphanedQueuesPeriod is how often the provisioned queues of deleted namespaces are looked for.
+	orphanedQueuesPeriod = 10 * time.Minute
+)
+
+// ProvisionConfig is the template of the queues provisioned for the namespaces.
+type ProvisionConfig struct {
+	// NamespaceSelector selects the namespaces a queue is provisioned for, all the namespaces if empty.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// QueueNamePrefix is prepended to the name of the namespace to name its queue.
+	QueueNamePrefix string `json:"queueNamePrefix,omitempty"`
+	// Weight is the weight of the queues, 1 if not set.
+	Weight      int32           `json:"weight,omitempty"`
+	Capability  v1.ResourceList `json:"capability,omitempty"`
+	Reclaimable *bool           `json:"reclaimable,omitempty"`
+	// Annotations are set on the queues, ${namespace} in the values is replaced by the name of
+	// the namespace, e.g. to restrict the queue to its namespace with a queue ACL.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// SetNamespaceDefaultQueue annotates the namespaces with their queue as their default queue,
+	// unless they already have one.
+	SetNamespaceDefaultQueue bool `json:"setNamespaceDefaultQueue,omitempty"`
+
+	selector labels.Selector
+}
+
+// LoadProvisionConfig loads the queue provision config from the YAML file.
+func LoadProvisionConfig(path string) (*ProvisionConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue provision config %s: %v", path, err)
+	}
+	config := &ProvisionConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse queue provision config %s: %v", path, err)
+	}
+	if err := config.complete(); err != nil {
+		return nil, fmt.Errorf("invalid queue provision config %s: %v", path, err)
+	}
+	return config, nil
+}
+
+func (p *ProvisionConfig) complete() error {
+	if p.Weight < 0 {
+		return fmt.Errorf("weight must not be negative")
+	}
+	if p.Weight == 0 {
+		p.Weight = 1
+	}
+	p.selector = labels.Everything()
+	if p.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(p.NamespaceSelector)
+		if err != nil {
+			return fmt.Errorf("invalid namespaceSelector: %v", err)
+		}
+		p.selector = selector
+	}
+	return nil
+}
+
+// Selects returns whether a queue is provisioned for the namespace.
+func (p *ProvisionConfig) Selects(ns *v1.Namespace) bool {
+	return ns.DeletionTimestamp == nil && p.selector.Matches(labels.Set(ns.Labels))
+}
+
+// QueueName returns the name of the queue provisioned for the namespace.
+func (p *ProvisionConfig) QueueName(namespace string) string {
+	return p.QueueNamePrefix + namespace
+}
+
+// NewQueue returns the queue provisioned for the namespace.
+func (p *ProvisionConfig) NewQueue(namespace string) *schedulingv1beta1.Queue {
+	queue := &schedulingv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   p.QueueName(namespace),
+			Labels: map[string]string{ProvisionedForNamespaceKey: namespace},
+		},
+		Spec: schedulingv1beta1.QueueSpec{
+			Weight:      p.Weight,
+			Capability:  p.Capability.DeepCopy(),
+			Reclaimable: p.Reclaimable,
+		},
+	}
+	if len(p.Annotations) != 0 {
+		queue.Annotations = make(map[string]string, len(p.Annotations))
+		for key, value := range p.Annotations {
+			queue.Annotations[key] = strings.ReplaceAll(value, namespacePlaceholder, namespace)
+		}
+	}
+	return queue
+}
+
+func (c *queuecontroller) addNamespace(obj interface{}) {
+	ns, ok := obj.(*v1.Namespace)
+	if !ok {
+		klog.Errorf("Failed to convert %v to *v1.Namespace.", obj)
+		return
+	}
+	c.namespaceQueue.Add(ns.Name)
+}
+
+func (c *queuecontroller) updateNamespace(_, newObj interface{}) {
+	c.addNamespace(newObj)
+}
+
+func (c *queuecontroller) deleteNamespace(obj interface{}) {
+	ns, ok := obj.(*v1.Namespace)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Failed to get Namespace from tombstone %v.", obj)
+			return
+		}
+		ns, ok = tombstone.Obj.(*v1.Namespace)
+		if !ok {
+			klog.Errorf("Tombstone contained object that is not a Namespace: %#v.", obj)
+			return
+		}
+	}
+	c.namespaceQueue.Add(ns.Name)
+}
+
+func (c *queuecontroller) namespaceWorker() {
+	for c.processNextNamespace() {
+	}
+}
+
+func (c *queuecontroller) processNextNamespace() bool {
+	obj, shutdown := c.namespaceQueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.namespaceQueue.Done(obj)
+
+	name := obj.(string)
+	if err := c.provisionQueue(name); err != nil {
+		// the namespace is never dropped, otherwise it is left without its queue, or its queue
+		// is left behind, until the namespace changes again.
+		klog.V(4).Infof("Error provisioning queue of namespace %s for %v, retrying.", name, err)
+		c.namespaceQueue.AddRateLimited(obj)
+		return true
+	}
+	c.namespaceQueue.Forget(obj)
+	return true
+}
+
+// provisionQueue creates the queue of the namespace if the namespace is selected, and deletes the
+// queue provisioned for the namespace once the namespace is deleted or no longer selected.
+func (c *queuecontroller) provisionQueue(namespace string) error {
+	ns, err := c.nsLister.Get(namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	queueName := c.provisionConfig.QueueName(namespace)
+
+	if ns == nil || !c.provisionConfig.Selects(ns) {
+		queue, err := c.queueLister.Get(queueName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		// only the queues provisioned for the namespace are deleted
+		if queue.Labels[ProvisionedForNamespaceKey] != namespace {
+			return nil
+		}
+		if err := c.vcClient.SchedulingV1beta1().Queues().Delete(context.TODO(), queueName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete queue %s of namespace %s: %v", queueName, namespace, err)
+		}
+		klog.Infof("Deleted queue %s provisioned for namespace %s.", queueName, namespace)
+		return nil
+	}
+
+	queue, err := c.queueLister.Get(queueName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		queue, err = c.vcClient.SchedulingV1beta1().Queues().Create(context.TODO(), c.provisionConfig.NewQueue(namespace), metav1.CreateOptions{})
+		switch {
+		case err == nil:
+			klog.Infof("Provisioned queue %s for namespace %s.", queueName, namespace)
+			events.Record(c.recorder, queue, events.Provisioned, fmt.Sprintf("Queue provisioned for namespace %s", namespace))
+		case apierrors.IsAlreadyExists(err):
+			// created since the cache was synced, by someone else or for another namespace
+			if queue, err = c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), queueName, metav1.GetOptions{}); err != nil {
+				return fmt.Errorf("failed to get queue %s of namespace %s: %v", queueName, namespace, err)
+			}
+		default:
+			return fmt.Errorf("failed to create queue %s of namespace %s: %v", queueName, namespace, err)
+		}
+	}
+	// a queue of the same name which was not provisioned for the namespace, e.g. one created by hand or
+	// provisioned for another namespace with a prefixed name, is never adopted as the queue of the namespace.
+	if queue.Labels[ProvisionedForNamespaceKey] != namespace {
+		klog.Warningf("Queue %s exists but was not provisioned for namespace %s, skip provisioning it.", queueName, namespace)
+		return nil
+	}
+
+	if c.provisionConfig.SetNamespaceDefaultQueue && ns.Annotations[schedulingv1beta1.QueueNameAnnotationKey] == "" {
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, schedulingv1beta1.QueueNameAnnotationKey, queueName)
+		if _, err := c.kubeClient.CoreV1().Namespaces().Patch(context.TODO(), namespace, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to set default queue of namespace %s: %v", namespace, err)
+		}
+	}
+	return nil
+}
+
+// enqueueOrphanedQueues enqueues the namespaces of the provisioned queues which are deleted or no longer
+// selected, so that their queues are deleted too, even if the namespace changed while the controller was
+// not running or the deletion of the queue failed.
+func (c *queuecontroller) enqueueOrphanedQueues() {
+	selector, _ := labels.Parse(ProvisionedForNamespaceKey)
+	queues, err := c.queueLister.List(selector)
+	if err != nil {
+		klog.Errorf("Failed to list provisioned queues: %v", err)
+		return
+	}
+	for _, queue := range queues {
+		namespace := queue.Labels[ProvisionedForNamespaceKey]
+		ns, err := c.nsLister.Get(namespace)
+		if apierrors.IsNotFound(err) || (err == nil && !c.provisionConfig.Selects(ns)) {
+			c.namespaceQueue.Add(namespace)
+		}
+	}
+}
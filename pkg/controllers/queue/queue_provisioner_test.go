@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+func TestLoadProvisionConfig(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		name      string
+		content   string
+		expectErr bool
+	}{
+		{
+			name: "valid config",
+			content: `
+namespaceSelector:
+  matchLabels:
+    tenant: "true"
+queueNamePrefix: tenant-
+capability:
+  cpu: "16"
+annotations:
+  volcano.sh/queue-acl: '{"namespaces":["${namespace}"]}'
+setNamespaceDefaultQueue: true
+`,
+		},
+		{name: "negative weight", content: "weight: -1", expectErr: true},
+		{
+			name:      "invalid selector",
+			content:   "namespaceSelector:\n  matchExpressions:\n  - key: tenant\n    operator: Near",
+			expectErr: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.name+".yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			config, err := LoadProvisionConfig(path)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("case %d: expected error %v, got %v", i, tc.expectErr, err)
+			}
+			if err == nil && config.Weight != 1 {
+				t.Errorf("case %d: expected default weight 1, got %d", i, config.Weight)
+			}
+		})
+	}
+}
+
+func TestProvisionQueue(t *testing.T) {
+	c := newFakeController()
+	config := &ProvisionConfig{
+		NamespaceSelector:        &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+		QueueNamePrefix:          "tenant-",
+		Capability:               v1.ResourceList{v1.ResourceCPU: resource.MustParse("16")},
+		Annotations:              map[string]string{"volcano.sh/queue-acl": `{"namespaces":["${namespace}"]}`},
+		SetNamespaceDefaultQueue: true,
+	}
+	if err := config.complete(); err != nil {
+		t.Fatal(err)
+	}
+	c.provisionConfig = config
+	c.informerFactory = informers.NewSharedInformerFactory(c.kubeClient, 0)
+	nsIndexer := c.informerFactory.Core().V1().Namespaces().Informer().GetIndexer()
+	c.nsLister = c.informerFactory.Core().V1().Namespaces().Lister()
+
+	for _, ns := range []*v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	} {
+		if _, err := c.kubeClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		nsIndexer.Add(ns)
+	}
+
+	// a queue is provisioned for the selected namespace only
+	for _, namespace := range []string{"team-a", "kube-system"} {
+		if err := c.provisionQueue(namespace); err != nil {
+			t.Fatalf("failed to provision queue of namespace %s: %v", namespace, err)
+		}
+	}
+	queue, err := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), "tenant-team-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected queue tenant-team-a to be provisioned: %v", err)
+	}
+	if queue.Labels[ProvisionedForNamespaceKey] != "team-a" || queue.Spec.Weight != 1 ||
+		!equality.Semantic.DeepEqual(queue.Spec.Capability, config.Capability) ||
+		queue.Annotations["volcano.sh/queue-acl"] != `{"namespaces":["team-a"]}` {
+		t.Errorf("unexpected provisioned queue %v", queue)
+	}
+	if _, err := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), "tenant-kube-system", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected no queue for namespace kube-system, got %v", err)
+	}
+	ns, _ := c.kubeClient.CoreV1().Namespaces().Get(context.TODO(), "team-a", metav1.GetOptions{})
+	if ns.Annotations[schedulingv1beta1.QueueNameAnnotationKey] != "tenant-team-a" {
+		t.Errorf("expected namespace team-a to default to queue tenant-team-a, got %v", ns.Annotations)
+	}
+
+	// the queue is deleted with its namespace, the queues not provisioned are kept
+	manual := &schedulingv1beta1.Queue{ObjectMeta: metav1.ObjectMeta{Name: "tenant-team-b"}}
+	if _, err := c.vcClient.SchedulingV1beta1().Queues().Create(context.TODO(), manual, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	c.queueInformer.Informer().GetIndexer().Add(queue)
+	c.queueInformer.Informer().GetIndexer().Add(manual)
+	nsIndexer.Delete(ns)
+	for _, namespace := range []string{"team-a", "team-b"} {
+		if err := c.provisionQueue(namespace); err != nil {
+			t.Fatalf("failed to clean up queue of namespace %s: %v", namespace, err)
+		}
+	}
+	if _, err := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), "tenant-team-a", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected queue tenant-team-a to be deleted, got %v", err)
+	}
+	if _, err := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), "tenant-team-b", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected queue tenant-team-b to be kept, got %v", err)
+	}
+
+	// a queue of the same name not provisioned for the namespace is not adopted
+	teamB := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tenant": "true"}}}
+	if _, err := c.kubeClient.CoreV1().Namespaces().Create(context.TODO(), teamB, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	nsIndexer.Add(teamB)
+	if err := c.provisionQueue("team-b"); err != nil {
+		t.Fatalf("failed to provision queue of namespace team-b: %v", err)
+	}
+	ns, _ = c.kubeClient.CoreV1().Namespaces().Get(context.TODO(), "team-b", metav1.GetOptions{})
+	if queueName, found := ns.Annotations[schedulingv1beta1.QueueNameAnnotationKey]; found {
+		t.Errorf("expected namespace team-b not to default to queue %s", queueName)
+	}
+}
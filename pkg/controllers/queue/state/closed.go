@@ -19,6 +19,7 @@ package state
 import (
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
 )
 
 type closedState struct {
@@ -31,7 +32,7 @@ func (cs *closedState) Execute(action v1alpha1.Action) error {
 		return OpenQueue(cs.queue, func(status *v1beta1.QueueStatus, podGroupList []string) {
 			status.State = v1beta1.QueueStateOpen
 		})
-	case v1alpha1.CloseQueueAction:
+	case v1alpha1.CloseQueueAction, apis.DrainQueueAction:
 		return SyncQueue(cs.queue, func(status *v1beta1.QueueStatus, podGroupList []string) {
 			status.State = v1beta1.QueueStateClosed
 		})
@@ -19,6 +19,7 @@ package state
 import (
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
 )
 
 type closingState struct {
@@ -39,6 +40,8 @@ func (cs *closingState) Execute(action v1alpha1.Action) error {
 			}
 			status.State = v1beta1.QueueStateClosing
 		})
+	case apis.DrainQueueAction:
+		return DrainQueue(cs.queue, drainQueueStatus)
 	default:
 		return SyncQueue(cs.queue, func(status *v1beta1.QueueStatus, podGroupList []string) {
 			specState := cs.queue.Status.State
@@ -19,6 +19,7 @@ package state
 import (
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
 )
 
 // State interface.
@@ -30,7 +31,7 @@ type State interface {
 // UpdateQueueStatusFn updates the queue status.
 type UpdateQueueStatusFn func(status *v1beta1.QueueStatus, podGroupList []string)
 
-// QueueActionFn will open, close or sync queue.
+// QueueActionFn will open, close, drain or sync queue.
 type QueueActionFn func(queue *v1beta1.Queue, fn UpdateQueueStatusFn) error
 
 var (
@@ -40,6 +41,8 @@ var (
 	OpenQueue QueueActionFn
 	// CloseQueue will set state of queue to close
 	CloseQueue QueueActionFn
+	// DrainQueue will set state of queue to draining
+	DrainQueue QueueActionFn
 )
 
 // NewState gets the state from queue status.
@@ -51,9 +54,20 @@ func NewState(queue *v1beta1.Queue) State {
 		return &closedState{queue: queue}
 	case v1beta1.QueueStateClosing:
 		return &closingState{queue: queue}
+	case apis.QueueStateDraining:
+		return &drainingState{queue: queue}
 	case v1beta1.QueueStateUnknown:
 		return &unknownState{queue: queue}
 	}
 
 	return nil
 }
+
+// drainQueueStatus moves a draining queue to closed once all its podgroups are gone.
+func drainQueueStatus(status *v1beta1.QueueStatus, podGroupList []string) {
+	if len(podGroupList) == 0 {
+		status.State = v1beta1.QueueStateClosed
+		return
+	}
+	status.State = apis.QueueStateDraining
+}
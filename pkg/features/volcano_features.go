@@ -43,6 +43,22 @@ const (
 
 	// ResourceTopology supports resources like cpu/memory topology aware.
 	ResourceTopology featuregate.Feature = "ResourceTopology"
+
+	// ElasticJobs supports scaling the tasks of the jobs while they run: shrinking the jobs annotated with
+	// volcano.sh/shrink-on-reclaim rather than evicting their pods, and autoscaling the tasks by their metrics.
+	ElasticJobs featuregate.Feature = "ElasticJobs"
+
+	// GrowAfterStart supports starting the jobs annotated with volcano.sh/grow-after-start once their minAvailable
+	// pods run, i.e. a partial gang, and growing them to their full replicas afterwards.
+	GrowAfterStart featuregate.Feature = "GrowAfterStart"
+
+	// IncrementalSnapshot supports giving a scheduling session the clones of the nodes given to the previous
+	// session again, rather than cloning every node, when neither the nodes nor the clones changed since.
+	IncrementalSnapshot featuregate.Feature = "IncrementalSnapshot"
+
+	// EquivalenceCache supports reusing the predicate results of a task on the nodes for the other replicas
+	// of its task, as long as the nodes did not change since.
+	EquivalenceCache featuregate.Feature = "EquivalenceCache"
 )
 
 func init() {
@@ -58,4 +74,10 @@ var defaultVolcanoFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec
 	// CSIStorage is explicitly set to false by default.
 	CSIStorage:       {Default: false, PreRelease: featuregate.Alpha},
 	ResourceTopology: {Default: true, PreRelease: featuregate.Alpha},
+	ElasticJobs:      {Default: true, PreRelease: featuregate.Beta},
+	GrowAfterStart:   {Default: true, PreRelease: featuregate.Beta},
+	// IncrementalSnapshot is explicitly set to false by default.
+	IncrementalSnapshot: {Default: false, PreRelease: featuregate.Alpha},
+	// EquivalenceCache is explicitly set to false by default.
+	EquivalenceCache: {Default: false, PreRelease: featuregate.Alpha},
 }
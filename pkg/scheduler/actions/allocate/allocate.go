@@ -17,6 +17,7 @@
 package allocate
 
 import (
+	"fmt"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -27,8 +28,11 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/metrics"
 	"volcano.sh/volcano/pkg/scheduler/util"
+	"volcano.sh/volcano/pkg/util/logging"
 )
 
+var logger = logging.Logger("allocate")
+
 type Action struct {
 	session *framework.Session
 	// configured flag for error cache
@@ -53,8 +57,8 @@ func (alloc *Action) parseArguments(ssn *framework.Session) {
 }
 
 func (alloc *Action) Execute(ssn *framework.Session) {
-	klog.V(5).Infof("Enter Allocate ...")
-	defer klog.V(5).Infof("Leaving Allocate ...")
+	logger.V(5).Info("Enter Allocate")
+	defer logger.V(5).Info("Leaving Allocate")
 
 	alloc.parseArguments(ssn)
 
@@ -72,7 +76,7 @@ func (alloc *Action) Execute(ssn *framework.Session) {
 
 	alloc.session = ssn
 	alloc.pickUpQueuesAndJobs(queues, jobsMap)
-	klog.V(3).Infof("Try to allocate resource to %d Queues", len(jobsMap))
+	logger.V(3).Info("Try to allocate resource to the queues", "queues", len(jobsMap))
 	alloc.allocateResources(queues, jobsMap)
 }
 
@@ -82,24 +86,30 @@ func (alloc *Action) pickUpQueuesAndJobs(queues *util.PriorityQueue, jobsMap map
 		// If not config enqueue action, change Pending pg into Inqueue statue to avoid blocking job scheduling.
 		if job.IsPending() {
 			if conf.EnabledActionMap["enqueue"] {
-				klog.V(4).Infof("Job <%s/%s> Queue <%s> skip allocate, reason: job status is pending.",
-					job.Namespace, job.Name, job.Queue)
+				logger.V(4).Info("Skip allocating the job", logging.JobKey, klog.KRef(job.Namespace, job.Name), logging.QueueKey, job.Queue, "reason", "job status is pending")
+				ssn.RecordJobSkipped(job, alloc.Name(), "job status is pending")
 				continue
 			} else {
-				klog.V(4).Infof("Job <%s/%s> Queue <%s> status update from pending to inqueue, reason: no enqueue action is configured.",
-					job.Namespace, job.Name, job.Queue)
+				logger.V(4).Info("Update the status of the job from pending to inqueue", logging.JobKey, klog.KRef(job.Namespace, job.Name), logging.QueueKey, job.Queue, "reason", "no enqueue action is configured")
 				job.PodGroup.Status.Phase = scheduling.PodGroupInqueue
 			}
 		}
 
 		if vr := ssn.JobValid(job); vr != nil && !vr.Pass {
-			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip allocate, reason: %v, message %v", job.Namespace, job.Name, job.Queue, vr.Reason, vr.Message)
+			logger.V(4).Info("Skip allocating the job", logging.JobKey, klog.KRef(job.Namespace, job.Name), logging.QueueKey, job.Queue, "reason", vr.Reason, "message", vr.Message)
+			ssn.RecordJobSkipped(job, alloc.Name(), fmt.Sprintf("%v: %v", vr.Reason, vr.Message))
 			continue
 		}
 
 		if _, found := ssn.Queues[job.Queue]; !found {
-			klog.Warningf("Skip adding Job <%s/%s> because its queue %s is not found",
-				job.Namespace, job.Name, job.Queue)
+			logger.Info("Skip adding the job because its queue is not found", logging.JobKey, klog.KRef(job.Namespace, job.Name), logging.QueueKey, job.Queue)
+			ssn.RecordJobSkipped(job, alloc.Name(), fmt.Sprintf("queue %s is not found", job.Queue))
+			continue
+		}
+
+		if ssn.Queues[job.Queue].IsDraining() && job.PodGroup.Status.Phase != scheduling.PodGroupRunning {
+			logger.V(4).Info("Skip allocating the job", logging.JobKey, klog.KRef(job.Namespace, job.Name), logging.QueueKey, job.Queue, "reason", "queue is draining")
+			ssn.RecordJobSkipped(job, alloc.Name(), "queue is draining")
 			continue
 		}
 
@@ -108,8 +118,9 @@ func (alloc *Action) pickUpQueuesAndJobs(queues *util.PriorityQueue, jobsMap map
 			queues.Push(ssn.Queues[job.Queue])
 		}
 
-		klog.V(4).Infof("Added Job <%s/%s> into Queue <%s>", job.Namespace, job.Name, job.Queue)
+		logger.V(4).Info("Added the job into its queue", logging.JobKey, klog.KRef(job.Namespace, job.Name), logging.QueueKey, job.Queue)
 		jobsMap[job.Queue].Push(job)
+		ssn.RecordJobConsidered(job, alloc.Name())
 	}
 }
 
@@ -133,15 +144,15 @@ func (alloc *Action) allocateResources(queues *util.PriorityQueue, jobsMap map[a
 		queue := queues.Pop().(*api.QueueInfo)
 
 		if ssn.Overused(queue) {
-			klog.V(3).Infof("Queue <%s> is overused, ignore it.", queue.Name)
+			logger.V(3).Info("Queue is overused, ignore it", logging.QueueKey, queue.Name)
 			continue
 		}
 
-		klog.V(3).Infof("Try to allocate resource to Jobs in Queue <%s>", queue.Name)
+		logger.V(3).Info("Try to allocate resource to the jobs of the queue", logging.QueueKey, queue.Name)
 
 		jobs, found := jobsMap[queue.UID]
 		if !found || jobs.Empty() {
-			klog.V(4).Infof("Can not find jobs for queue %s.", queue.Name)
+			logger.V(4).Info("Can not find jobs for the queue", logging.QueueKey, queue.Name)
 			continue
 		}
 
@@ -156,8 +167,7 @@ func (alloc *Action) allocateResources(queues *util.PriorityQueue, jobsMap map[a
 
 				// Skip BestEffort task in 'allocate' action.
 				if task.Resreq.IsEmpty() {
-					klog.V(4).Infof("Task <%v/%v> is BestEffort task, skip it.",
-						task.Namespace, task.Name)
+					logger.V(4).Info("Task is BestEffort, skip it", logging.TaskKey, klog.KRef(task.Namespace, task.Name))
 					continue
 				}
 
@@ -173,10 +183,13 @@ func (alloc *Action) allocateResources(queues *util.PriorityQueue, jobsMap map[a
 			continue
 		}
 
-		klog.V(3).Infof("Try to allocate resource to %d tasks of Job <%v/%v>",
-			tasks.Len(), job.Namespace, job.Name)
+		logger.V(3).Info("Try to allocate resource to the tasks of the job", logging.JobKey, klog.KRef(job.Namespace, job.Name), "tasks", tasks.Len())
 
-		alloc.allocateResourcesForTasks(tasks, job, jobs, queue, allNodes)
+		if job.TopologyConstraint != nil {
+			pendingTasks[job.UID] = alloc.allocateResourcesInTopology(tasks, job, jobs, queue, allNodes)
+		} else {
+			alloc.allocateResourcesForTasks(tasks, job, jobs, queue, allNodes)
+		}
 
 		// Put back the queue to priority queue after job's resource allocating finished,
 		// To ensure that the priority of the queue is calculated based on the latest resource allocation situation.
@@ -184,7 +197,8 @@ func (alloc *Action) allocateResources(queues *util.PriorityQueue, jobsMap map[a
 	}
 }
 
-func (alloc *Action) allocateResourcesForTasks(tasks *util.PriorityQueue, job *api.JobInfo, jobs *util.PriorityQueue, queue *api.QueueInfo, allNodes []*api.NodeInfo) {
+// allocateResourcesForTasks allocates the tasks on the nodes, it returns whether the job is ready or pipelined.
+func (alloc *Action) allocateResourcesForTasks(tasks *util.PriorityQueue, job *api.JobInfo, jobs *util.PriorityQueue, queue *api.QueueInfo, allNodes []*api.NodeInfo) bool {
 	ssn := alloc.session
 	stmt := framework.NewStatement(ssn)
 	ph := util.NewPredicateHelper()
@@ -193,20 +207,20 @@ func (alloc *Action) allocateResourcesForTasks(tasks *util.PriorityQueue, job *a
 		task := tasks.Pop().(*api.TaskInfo)
 
 		if !ssn.Allocatable(queue, task) {
-			klog.V(3).Infof("Queue <%s> is overused when considering task <%s>, ignore it.", queue.Name, task.Name)
+			logger.V(3).Info("Queue is overused when considering the task, ignore it", logging.QueueKey, queue.Name, logging.TaskKey, klog.KRef(task.Namespace, task.Name))
 			continue
 		}
 
 		// check if the task with its spec has already predicates failed
 		if job.TaskHasFitErrors(task) {
-			klog.V(5).Infof("Task %s with role spec %s has already predicated failed, skip", task.Name, task.TaskRole)
+			logger.V(5).Info("Task with the same role has already failed the predicates, skip it", logging.TaskKey, klog.KRef(task.Namespace, task.Name), "role", task.TaskRole)
 			continue
 		}
 
-		klog.V(3).Infof("There are <%d> nodes for Job <%v/%v>", len(ssn.Nodes), job.Namespace, job.Name)
+		logger.V(3).Info("Nodes for the job", logging.JobKey, klog.KRef(job.Namespace, job.Name), "nodes", len(ssn.Nodes))
 
 		if err := ssn.PrePredicateFn(task); err != nil {
-			klog.V(3).Infof("PrePredicate for task %s/%s failed for: %v", task.Namespace, task.Name, err)
+			logger.V(3).Info("PrePredicate failed for the task", logging.TaskKey, klog.KRef(task.Namespace, task.Name), "err", err)
 			fitErrors := api.NewFitErrors()
 			for _, ni := range allNodes {
 				fitErrors.SetNodeError(ni.Name, err)
@@ -242,27 +256,28 @@ func (alloc *Action) allocateResourcesForTasks(tasks *util.PriorityQueue, job *a
 			} else if task.InitResreq.LessEqual(n.FutureIdle(), api.Zero) {
 				futureIdleCandidateNodes = append(futureIdleCandidateNodes, n)
 			} else {
-				klog.V(5).Infof("Predicate filtered node %v, idle: %v and future idle: %v do not meet the requirements of task: %v",
-					n.Name, n.Idle, n.FutureIdle(), task.Name)
+				logger.V(5).Info("Idle and future idle of the predicate filtered node do not meet the requirements of the task",
+					logging.NodeKey, n.Name, "idle", n.Idle, "futureIdle", n.FutureIdle(), logging.TaskKey, klog.KRef(task.Namespace, task.Name))
 			}
 		}
 		candidateNodes = append(candidateNodes, idleCandidateNodes)
 		candidateNodes = append(candidateNodes, futureIdleCandidateNodes)
 
 		var bestNode *api.NodeInfo
+		var nodeScores map[float64][]*api.NodeInfo
 		for index, nodes := range candidateNodes {
-			if klog.V(5).Enabled() {
+			if logger.V(5).Enabled() {
 				for _, node := range nodes {
-					klog.V(5).Infof("node %v, idle: %v, future idle: %v", node.Name, node.Idle, node.FutureIdle())
+					logger.V(5).Info("Candidate node", logging.NodeKey, node.Name, "idle", node.Idle, "futureIdle", node.FutureIdle())
 				}
 			}
 			switch {
 			case len(nodes) == 0:
-				klog.V(5).Infof("Task: %v, no matching node is found in the candidateNodes（index: %d） list.", task.Name, index)
+				logger.V(5).Info("No matching node is found in the candidate nodes", logging.TaskKey, klog.KRef(task.Namespace, task.Name), "index", index)
 			case len(nodes) == 1: // If only one node after predicate, just use it.
 				bestNode = nodes[0]
 			case len(nodes) > 1: // If more than one node after predicate, using "the best" one
-				nodeScores := util.PrioritizeNodes(task, nodes, ssn.BatchNodeOrderFn, ssn.NodeOrderMapFn, ssn.NodeOrderReduceFn)
+				nodeScores = util.PrioritizeNodes(task, nodes, ssn.BatchNodeOrderFn, ssn.NodeOrderMapFn, ssn.NodeOrderReduceFn)
 
 				bestNode = ssn.BestNodeFn(task, nodeScores)
 				if bestNode == nil {
@@ -276,27 +291,26 @@ func (alloc *Action) allocateResourcesForTasks(tasks *util.PriorityQueue, job *a
 			}
 		}
 
+		ssn.RecordTaskCandidates(task, predicateNodes, nodeScores)
+
 		// Allocate idle resource to the task.
 		if task.InitResreq.LessEqual(bestNode.Idle, api.Zero) {
-			klog.V(3).Infof("Binding Task <%v/%v> to node <%v>", task.Namespace, task.Name, bestNode.Name)
+			logger.V(3).Info("Binding the task to the node", logging.TaskKey, klog.KRef(task.Namespace, task.Name), logging.NodeKey, bestNode.Name)
 			if err := stmt.Allocate(task, bestNode); err != nil {
-				klog.Errorf("Failed to bind Task %v on %v in Session %v, err: %v",
-					task.UID, bestNode.Name, ssn.UID, err)
+				logger.Error(err, "Failed to bind the task", logging.TaskKey, klog.KRef(task.Namespace, task.Name), logging.NodeKey, bestNode.Name, "session", ssn.UID)
 			} else {
 				metrics.UpdateE2eSchedulingDurationByJob(job.Name, string(job.Queue), job.Namespace, metrics.Duration(job.CreationTimestamp.Time))
 				metrics.UpdateE2eSchedulingLastTimeByJob(job.Name, string(job.Queue), job.Namespace, time.Now())
 			}
 		} else {
-			klog.V(3).Infof("Predicates failed in allocate for task <%s/%s> on node <%s> with limited resources",
-				task.Namespace, task.Name, bestNode.Name)
+			logger.V(3).Info("Predicates failed for the task on the node with limited resources", logging.TaskKey, klog.KRef(task.Namespace, task.Name), logging.NodeKey, bestNode.Name)
 
 			// Allocate releasing resource to the task if any.
 			if task.InitResreq.LessEqual(bestNode.FutureIdle(), api.Zero) {
-				klog.V(3).Infof("Pipelining Task <%v/%v> to node <%v> for <%v> on <%v>",
-					task.Namespace, task.Name, bestNode.Name, task.InitResreq, bestNode.Releasing)
+				logger.V(3).Info("Pipelining the task to the node", logging.TaskKey, klog.KRef(task.Namespace, task.Name), logging.NodeKey, bestNode.Name,
+					"request", task.InitResreq, "releasing", bestNode.Releasing)
 				if err := stmt.Pipeline(task, bestNode.Name, false); err != nil {
-					klog.Errorf("Failed to pipeline Task %v on %v in Session %v for %v.",
-						task.UID, bestNode.Name, ssn.UID, err)
+					logger.Error(err, "Failed to pipeline the task", logging.TaskKey, klog.KRef(task.Namespace, task.Name), logging.NodeKey, bestNode.Name, "session", ssn.UID)
 				} else {
 					metrics.UpdateE2eSchedulingDurationByJob(job.Name, string(job.Queue), job.Namespace, metrics.Duration(job.CreationTimestamp.Time))
 					metrics.UpdateE2eSchedulingLastTimeByJob(job.Name, string(job.Queue), job.Namespace, time.Now())
@@ -312,11 +326,13 @@ func (alloc *Action) allocateResourcesForTasks(tasks *util.PriorityQueue, job *a
 
 	if ssn.JobReady(job) {
 		stmt.Commit()
-	} else {
-		if !ssn.JobPipelined(job) {
-			stmt.Discard()
-		}
+		return true
+	}
+	if !ssn.JobPipelined(job) {
+		stmt.Discard()
+		return false
 	}
+	return true
 }
 
 func (alloc *Action) predicate(task *api.TaskInfo, node *api.NodeInfo) error {
@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
 
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/volcano/cmd/scheduler/app/options"
 	"volcano.sh/volcano/pkg/scheduler/api"
@@ -219,6 +220,92 @@ func TestAllocate(t *testing.T) {
 			},
 			ExpectBindsNum: 1,
 		},
+		{
+			Name: "gang is allocated in the topology domain which can hold it",
+			PodGroups: []*schedulingv1.PodGroup{
+				withTopology(util.BuildPodGroup("pg1", "c1", "c1", 2, nil, schedulingv1.PodGroupInqueue), "zone", ""),
+			},
+			Pods: []*v1.Pod{
+				util.BuildPod("c1", "p1", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+				util.BuildPod("c1", "p2", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+			},
+			Nodes: []*v1.Node{
+				util.BuildNode("n1", api.BuildResourceList("1", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), map[string]string{"zone": "a"}),
+				util.BuildNode("n2", api.BuildResourceList("2", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), map[string]string{"zone": "b"}),
+			},
+			Queues: []*schedulingv1.Queue{
+				util.BuildQueue("c1", 1, nil),
+			},
+			ExpectBindMap: map[string]string{
+				"c1/p1": "n2",
+				"c1/p2": "n2",
+			},
+			ExpectBindsNum: 2,
+		},
+		{
+			Name: "gang is not allocated across topology domains",
+			PodGroups: []*schedulingv1.PodGroup{
+				withTopology(util.BuildPodGroup("pg1", "c1", "c1", 2, nil, schedulingv1.PodGroupInqueue), "zone", api.TopologyModeRequired),
+			},
+			Pods: []*v1.Pod{
+				util.BuildPod("c1", "p1", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg1", make(map[string]string), map[string]string{"zone": "a"}),
+				util.BuildPod("c1", "p2", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg1", make(map[string]string), map[string]string{"zone": "b"}),
+			},
+			Nodes: []*v1.Node{
+				util.BuildNode("n1", api.BuildResourceList("2", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), map[string]string{"zone": "a"}),
+				util.BuildNode("n2", api.BuildResourceList("2", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), map[string]string{"zone": "b"}),
+			},
+			Queues: []*schedulingv1.Queue{
+				util.BuildQueue("c1", 1, nil),
+			},
+			ExpectBindMap:  map[string]string{},
+			ExpectBindsNum: 0,
+		},
+		{
+			Name: "gang with preferred topology falls back to all the nodes",
+			PodGroups: []*schedulingv1.PodGroup{
+				withTopology(util.BuildPodGroup("pg1", "c1", "c1", 2, nil, schedulingv1.PodGroupInqueue), "zone", api.TopologyModePreferred),
+			},
+			Pods: []*v1.Pod{
+				util.BuildPod("c1", "p1", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg1", make(map[string]string), map[string]string{"zone": "a"}),
+				util.BuildPod("c1", "p2", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg1", make(map[string]string), map[string]string{"zone": "b"}),
+			},
+			Nodes: []*v1.Node{
+				util.BuildNode("n1", api.BuildResourceList("2", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), map[string]string{"zone": "a"}),
+				util.BuildNode("n2", api.BuildResourceList("2", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), map[string]string{"zone": "b"}),
+			},
+			Queues: []*schedulingv1.Queue{
+				util.BuildQueue("c1", 1, nil),
+			},
+			ExpectBindMap: map[string]string{
+				"c1/p1": "n1",
+				"c1/p2": "n2",
+			},
+			ExpectBindsNum: 2,
+		},
+		{
+			Name: "gang whose role fails in a topology domain is allocated in the next one",
+			PodGroups: []*schedulingv1.PodGroup{
+				withTopology(util.BuildPodGroup("pg1", "c1", "c1", 2, nil, schedulingv1.PodGroupInqueue), "zone", api.TopologyModeRequired),
+			},
+			Pods: []*v1.Pod{
+				withTaskRole(util.BuildPod("c1", "p1", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg1", make(map[string]string), map[string]string{"gpu": "true"}), "worker"),
+				withTaskRole(util.BuildPod("c1", "p2", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg1", make(map[string]string), map[string]string{"gpu": "true"}), "worker"),
+			},
+			Nodes: []*v1.Node{
+				// the smaller domain is tried first, its node does not match the selector of the pods
+				util.BuildNode("n1", api.BuildResourceList("2", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), map[string]string{"zone": "a"}),
+				util.BuildNode("n2", api.BuildResourceList("3", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), map[string]string{"zone": "b", "gpu": "true"}),
+			},
+			Queues: []*schedulingv1.Queue{
+				util.BuildQueue("c1", 1, nil),
+			},
+			ExpectBindMap: map[string]string{
+				"c1/p1": "n2",
+				"c1/p2": "n2",
+			},
+			ExpectBindsNum: 2,
+		},
 	}
 
 	trueValue := true
@@ -268,6 +355,25 @@ func TestAllocate(t *testing.T) {
 	}
 }
 
+func withTopology(pg *schedulingv1.PodGroup, key, mode string) *schedulingv1.PodGroup {
+	if pg.Annotations == nil {
+		pg.Annotations = map[string]string{}
+	}
+	pg.Annotations[api.JobTopologyKeyAnnotationKey] = key
+	if mode != "" {
+		pg.Annotations[api.JobTopologyModeAnnotationKey] = mode
+	}
+	return pg
+}
+
+func withTaskRole(pod *v1.Pod, role string) *v1.Pod {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[batch.TaskSpecKey] = role
+	return pod
+}
+
 func TestFareShareAllocate(t *testing.T) {
 	plugins := map[string]framework.PluginBuilder{
 		drf.PluginName:        drf.New,
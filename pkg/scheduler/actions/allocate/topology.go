@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package allocate
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/util"
+	"volcano.sh/volcano/pkg/util/logging"
+)
+
+// allocateResourcesInTopology allocates the tasks of the job with a topology constraint within a
+// single topology domain. The domain is the one of the members of the job already allocated, if any,
+// otherwise the domains whose idle resources can hold the gang are tried in turn, the smallest first
+// to keep the larger domains for larger gangs. A preferred constraint falls back to all the nodes.
+// It returns the tasks left to allocate.
+func (alloc *Action) allocateResourcesInTopology(tasks *util.PriorityQueue, job *api.JobInfo, jobs *util.PriorityQueue, queue *api.QueueInfo, allNodes []*api.NodeInfo) *util.PriorityQueue {
+	ssn := alloc.session
+	constraint := job.TopologyConstraint
+
+	var pendingTasks []*api.TaskInfo
+	for !tasks.Empty() {
+		pendingTasks = append(pendingTasks, tasks.Pop().(*api.TaskInfo))
+	}
+	newTasks := func() *util.PriorityQueue {
+		queued := util.NewPriorityQueue(ssn.TaskOrderFn)
+		for _, task := range pendingTasks {
+			queued.Push(task)
+		}
+		return queued
+	}
+
+	domains := api.GetTopologyDomains(allNodes, constraint.Key)
+	if value, found := api.AllocatedTopologyDomain(job, ssn.Nodes, constraint.Key); found {
+		domains = filterTopologyDomains(domains, func(domain *api.TopologyDomain) bool {
+			return domain.Value == value
+		})
+	} else {
+		request := gangRequest(job, pendingTasks)
+		domains = filterTopologyDomains(domains, func(domain *api.TopologyDomain) bool {
+			return request.LessEqual(domain.Idle, api.Zero)
+		})
+		sort.SliceStable(domains, func(i, j int) bool {
+			if domains[i].Idle.MilliCPU != domains[j].Idle.MilliCPU {
+				return domains[i].Idle.MilliCPU < domains[j].Idle.MilliCPU
+			}
+			return domains[i].Idle.Memory < domains[j].Idle.Memory
+		})
+	}
+
+	for _, domain := range domains {
+		logger.V(3).Info("Try to allocate the job in the topology domain", logging.JobKey, klog.KRef(job.Namespace, job.Name), "domain", constraint.Key+"="+domain.Value)
+		tasks = newTasks()
+		// the fit errors of another domain do not hold in this one, and would skip the roles failed there
+		job.NodesFitErrors = make(map[api.TaskID]*api.FitErrors)
+		if alloc.allocateResourcesForTasks(tasks, job, jobs, queue, domain.Nodes) {
+			return tasks
+		}
+	}
+
+	if constraint.Preferred {
+		logger.V(3).Info("No topology domain can hold the job, try all the nodes", logging.JobKey, klog.KRef(job.Namespace, job.Name), "topologyKey", constraint.Key)
+		tasks = newTasks()
+		job.NodesFitErrors = make(map[api.TaskID]*api.FitErrors)
+		alloc.allocateResourcesForTasks(tasks, job, jobs, queue, allNodes)
+		return tasks
+	}
+
+	if len(domains) == 0 && len(pendingTasks) != 0 {
+		err := fmt.Errorf("no topology domain %s can hold the gang", constraint.Key)
+		fitErrors := api.NewFitErrors()
+		for _, node := range allNodes {
+			fitErrors.SetNodeError(node.Name, err)
+		}
+		job.NodesFitErrors[pendingTasks[0].UID] = fitErrors
+	}
+	return newTasks()
+}
+
+// gangRequest returns the resources requested by the pending tasks the job needs to be ready.
+func gangRequest(job *api.JobInfo, pendingTasks []*api.TaskInfo) *api.Resource {
+	request := api.EmptyResource()
+	need := int(job.MinAvailable - job.ReadyTaskNum() - job.WaitingTaskNum())
+	for i := 0; i < need && i < len(pendingTasks); i++ {
+		request.Add(pendingTasks[i].InitResreq)
+	}
+	return request
+}
+
+func filterTopologyDomains(domains []*api.TopologyDomain, fn func(*api.TopologyDomain) bool) []*api.TopologyDomain {
+	var result []*api.TopologyDomain
+	for _, domain := range domains {
+		if fn(domain) {
+			result = append(result, domain)
+		}
+	}
+	return result
+}
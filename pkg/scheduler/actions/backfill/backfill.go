@@ -55,6 +55,10 @@ func (backfill *Action) Execute(ssn *framework.Session) {
 
 	backfill.parseArguments(ssn)
 
+	// the backfilled jobs give way to the waiting gangs first, then take the idle resources left
+	backfill.reclaimBackfilled(ssn)
+	backfill.backfillReserved(ssn)
+
 	predicateFunc := ssn.PredicateForAllocateAction
 
 	// TODO (k82cn): When backfill, it's also need to balance between Queues.
@@ -74,7 +78,8 @@ func (backfill *Action) Execute(ssn *framework.Session) {
 			break
 		}
 
-		predicateNodes, fitErrors := ph.PredicateNodes(task, ssn.NodeList, predicateFunc, backfill.enablePredicateErrorCache)
+		nodes := api.FilterTopologyNodes(job, ssn.Nodes, ssn.NodeList)
+		predicateNodes, fitErrors := ph.PredicateNodes(task, nodes, predicateFunc, backfill.enablePredicateErrorCache)
 		if len(predicateNodes) == 0 {
 			job.NodesFitErrors[task.UID] = fitErrors
 			break
@@ -109,6 +114,177 @@ func (backfill *Action) Execute(ssn *framework.Session) {
 
 func (backfill *Action) UnInitialize() {}
 
+// isBackfilled returns whether the job is short enough to be backfilled by its queue.
+func isBackfilled(ssn *framework.Session, job *api.JobInfo) bool {
+	queue, found := ssn.Queues[job.Queue]
+	return found && queue.Backfills(job)
+}
+
+// reclaimBackfilled evicts the running tasks of the backfilled jobs for the jobs waiting for
+// resources, as soon as a waiting job fits with the resources of the backfilled tasks, so that
+// the backfilled jobs never delay the jobs they are backfilled for.
+func (backfill *Action) reclaimBackfilled(ssn *framework.Session) {
+	// node name -> running tasks of the backfilled jobs
+	backfilled := map[string][]*api.TaskInfo{}
+	for _, job := range ssn.Jobs {
+		if !isBackfilled(ssn, job) {
+			continue
+		}
+		for _, task := range job.TaskStatusIndex[api.Running] {
+			backfilled[task.NodeName] = append(backfilled[task.NodeName], task)
+		}
+	}
+	if len(backfilled) == 0 {
+		return
+	}
+
+	waiting := util.NewPriorityQueue(ssn.JobOrderFn)
+	for _, job := range ssn.Jobs {
+		if job.IsPending() || isBackfilled(ssn, job) || !job.HasPendingTasks() {
+			continue
+		}
+		if vr := ssn.JobValid(job); vr != nil && !vr.Pass {
+			continue
+		}
+		if ssn.JobReady(job) || ssn.JobPipelined(job) {
+			continue
+		}
+		waiting.Push(job)
+	}
+
+	for !waiting.Empty() {
+		job := waiting.Pop().(*api.JobInfo)
+		queue := ssn.Queues[job.Queue]
+		tasks := util.NewPriorityQueue(ssn.TaskOrderFn)
+		for _, task := range job.TaskStatusIndex[api.Pending] {
+			if !task.SchGated {
+				tasks.Push(task)
+			}
+		}
+
+		stmt := framework.NewStatement(ssn)
+		for !tasks.Empty() {
+			task := tasks.Pop().(*api.TaskInfo)
+			if !ssn.Allocatable(queue, task) {
+				break
+			}
+			if !pipelineOnBackfilled(ssn, stmt, task, backfilled) {
+				break
+			}
+		}
+		if ssn.JobPipelined(job) {
+			klog.V(3).Infof("Evict backfilled tasks for Job <%s/%s>.", job.Namespace, job.Name)
+			stmt.Commit()
+		} else {
+			stmt.Discard()
+		}
+	}
+}
+
+// pipelineOnBackfilled pipelines the task on a node whose future idle resources fit the task,
+// evicting running backfilled tasks of the node if the task does not fit otherwise.
+func pipelineOnBackfilled(ssn *framework.Session, stmt *framework.Statement, task *api.TaskInfo, backfilled map[string][]*api.TaskInfo) bool {
+	if err := ssn.PrePredicateFn(task); err != nil {
+		return false
+	}
+	var nodes []*api.NodeInfo
+	for _, node := range api.FilterTopologyNodes(ssn.Jobs[task.Job], ssn.Nodes, ssn.NodeList) {
+		if err := ssn.PredicateForPreemptAction(task, node); err != nil {
+			continue
+		}
+		if task.InitResreq.LessEqual(node.FutureIdle(), api.Zero) {
+			return stmt.Pipeline(task, node.Name, false) == nil
+		}
+		nodes = append(nodes, node)
+	}
+
+	for _, node := range nodes {
+		var victims []*api.TaskInfo
+		freed := node.FutureIdle()
+		for _, victim := range backfilled[node.Name] {
+			if task.InitResreq.LessEqual(freed, api.Zero) {
+				break
+			}
+			// skip the tasks evicted for the previous tasks
+			if job, found := ssn.Jobs[victim.Job]; !found || job.Tasks[victim.UID] == nil || job.Tasks[victim.UID].Status != api.Running {
+				continue
+			}
+			victims = append(victims, victim)
+			freed.Add(victim.Resreq)
+		}
+		if !task.InitResreq.LessEqual(freed, api.Zero) {
+			continue
+		}
+		for _, victim := range victims {
+			if err := stmt.Evict(victim.Clone(), "backfill"); err != nil {
+				klog.Errorf("Failed to evict backfilled Task <%s/%s> for Task <%s/%s>: %v",
+					victim.Namespace, victim.Name, task.Namespace, task.Name, err)
+				return false
+			}
+		}
+		return stmt.Pipeline(task, node.Name, true) == nil
+	}
+	return false
+}
+
+// backfillReserved allocates the pending tasks of the backfilled jobs to the idle resources of
+// the nodes, including the idle resources reserved for the pipelined jobs, which are reclaimed
+// by reclaimBackfilled once the pipelined jobs need them.
+func (backfill *Action) backfillReserved(ssn *framework.Session) {
+	jobs := util.NewPriorityQueue(ssn.JobOrderFn)
+	for _, job := range ssn.Jobs {
+		if job.IsPending() || !isBackfilled(ssn, job) || !job.HasPendingTasks() {
+			continue
+		}
+		if vr := ssn.JobValid(job); vr != nil && !vr.Pass {
+			continue
+		}
+		jobs.Push(job)
+	}
+
+	for !jobs.Empty() {
+		job := jobs.Pop().(*api.JobInfo)
+		queue := ssn.Queues[job.Queue]
+		tasks := util.NewPriorityQueue(ssn.TaskOrderFn)
+		for _, task := range job.TaskStatusIndex[api.Pending] {
+			// the best effort tasks are backfilled anyway
+			if !task.SchGated && !task.BestEffort {
+				tasks.Push(task)
+			}
+		}
+
+		stmt := framework.NewStatement(ssn)
+		for !tasks.Empty() {
+			task := tasks.Pop().(*api.TaskInfo)
+			if !ssn.Allocatable(queue, task) {
+				break
+			}
+			if err := ssn.PrePredicateFn(task); err != nil {
+				break
+			}
+			for _, node := range api.FilterTopologyNodes(job, ssn.Nodes, ssn.NodeList) {
+				if !task.InitResreq.LessEqual(node.Idle, api.Zero) {
+					continue
+				}
+				if err := ssn.PredicateForAllocateAction(task, node); err != nil {
+					continue
+				}
+				if err := stmt.Allocate(task, node); err != nil {
+					klog.Errorf("Failed to backfill Task <%s/%s> on Node <%s>: %v", task.Namespace, task.Name, node.Name, err)
+					continue
+				}
+				klog.V(3).Infof("Backfilled Task <%s/%s> on Node <%s>", task.Namespace, task.Name, node.Name)
+				break
+			}
+		}
+		if ssn.JobReady(job) {
+			stmt.Commit()
+		} else {
+			stmt.Discard()
+		}
+	}
+}
+
 func (backfill *Action) pickUpPendingTasks(ssn *framework.Session) []*api.TaskInfo {
 	queues := util.NewPriorityQueue(ssn.QueueOrderFn)
 	jobs := map[api.QueueID]*util.PriorityQueue{}
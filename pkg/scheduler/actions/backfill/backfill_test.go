@@ -16,7 +16,9 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/conf"
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/plugins/drf"
+	"volcano.sh/volcano/pkg/scheduler/plugins/gang"
 	"volcano.sh/volcano/pkg/scheduler/plugins/priority"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
 	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
@@ -160,3 +162,83 @@ func TestPickUpPendingTasks(t *testing.T) {
 		}
 	}
 }
+
+func TestReclaimBackfilled(t *testing.T) {
+	shortPodGroup := func() *schedulingv1beta1.PodGroup {
+		pg := util.BuildPodGroup("pg-short", "c1", "q1", 1, nil, schedulingv1beta1.PodGroupRunning)
+		pg.Annotations = map[string]string{api.JobEstimatedDurationKey: "10m"}
+		return pg
+	}
+	pods := func() []*v1.Pod {
+		return []*v1.Pod{
+			util.BuildPod("c1", "short-1", "n1", v1.PodRunning, api.BuildResourceList("1", "1G"), "pg-short", make(map[string]string), make(map[string]string)),
+			util.BuildPod("c1", "short-2", "n1", v1.PodRunning, api.BuildResourceList("1", "1G"), "pg-short", make(map[string]string), make(map[string]string)),
+			util.BuildPod("c1", "normal-1", "n1", v1.PodRunning, api.BuildResourceList("2", "2G"), "pg-normal", make(map[string]string), make(map[string]string)),
+			util.BuildPod("c1", "gang-1", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg-gang", make(map[string]string), make(map[string]string)),
+			util.BuildPod("c1", "gang-2", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pg-gang", make(map[string]string), make(map[string]string)),
+		}
+	}
+	podGroups := func() []*schedulingv1beta1.PodGroup {
+		return []*schedulingv1beta1.PodGroup{
+			shortPodGroup(),
+			util.BuildPodGroup("pg-normal", "c1", "q1", 1, nil, schedulingv1beta1.PodGroupRunning),
+			util.BuildPodGroup("pg-gang", "c1", "q1", 2, nil, schedulingv1beta1.PodGroupInqueue),
+		}
+	}
+	nodes := func() []*v1.Node {
+		return []*v1.Node{
+			util.BuildNode("n1", api.BuildResourceList("4", "4Gi", []api.ScalarResource{{Name: "pods", Value: "10"}}...), make(map[string]string)),
+		}
+	}
+
+	tests := []uthelper.TestCommonStruct{
+		{
+			Name:      "the backfilled tasks are evicted for the waiting gang",
+			Plugins:   map[string]framework.PluginBuilder{gang.PluginName: gang.New},
+			PodGroups: podGroups(),
+			Pods:      pods(),
+			Nodes:     nodes(),
+			Queues: []*schedulingv1beta1.Queue{
+				util.BuildQueueWithAnnos("q1", 1, nil, map[string]string{api.QueueBackfillMaxDurationAnnotationKey: "1h"}),
+			},
+			ExpectPipeLined: map[string][]string{"c1/pg-gang": {"n1"}},
+			ExpectEvictNum:  2,
+			ExpectEvicted:   []string{"c1/short-1", "c1/short-2"},
+		},
+		{
+			Name:      "the jobs are not evicted if their queue does not backfill",
+			Plugins:   map[string]framework.PluginBuilder{gang.PluginName: gang.New},
+			PodGroups: podGroups(),
+			Pods:      pods(),
+			Nodes:     nodes(),
+			Queues: []*schedulingv1beta1.Queue{
+				util.BuildQueue("q1", 1, nil),
+			},
+			ExpectEvictNum: 0,
+		},
+	}
+
+	trueValue := true
+	tiers := []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{
+					Name:                gang.PluginName,
+					EnabledJobOrder:     &trueValue,
+					EnabledJobReady:     &trueValue,
+					EnabledJobPipelined: &trueValue,
+				},
+			},
+		},
+	}
+	for i, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.RegisterSession(tiers, nil)
+			defer test.Close()
+			test.Run([]framework.Action{New()})
+			if err := test.CheckAll(i); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -48,6 +48,9 @@ func (enqueue *Action) Execute(ssn *framework.Session) {
 	queues := util.NewPriorityQueue(ssn.QueueOrderFn)
 	queueSet := sets.NewString()
 	jobsMap := map[api.QueueID]*util.PriorityQueue{}
+	// the number of enqueued or running jobs, and of the pending jobs considered in this cycle, of each queue
+	runningJobs := map[api.QueueID]int32{}
+	pendingJobs := map[api.QueueID]int32{}
 
 	for _, job := range ssn.Jobs {
 		if job.ScheduleStartTimestamp.IsZero() {
@@ -68,11 +71,18 @@ func (enqueue *Action) Execute(ssn *framework.Session) {
 		}
 
 		if job.IsPending() {
+			if ssn.Queues[job.Queue].IsDraining() {
+				klog.V(4).Infof("Job <%s/%s> Queue <%s> skip enqueue, reason: queue is draining.",
+					job.Namespace, job.Name, job.Queue)
+				continue
+			}
 			if _, found := jobsMap[job.Queue]; !found {
 				jobsMap[job.Queue] = util.NewPriorityQueue(ssn.JobOrderFn)
 			}
 			klog.V(5).Infof("Added Job <%s/%s> into Queue <%s>", job.Namespace, job.Name, job.Queue)
 			jobsMap[job.Queue].Push(job)
+		} else if job.PodGroup.Status.Phase != scheduling.PodGroupCompleted {
+			runningJobs[job.Queue]++
 		}
 	}
 
@@ -90,12 +100,24 @@ func (enqueue *Action) Execute(ssn *framework.Session) {
 		if !found || jobs.Empty() {
 			continue
 		}
+		if queue.MaxRunningJobs > 0 && runningJobs[queue.UID] >= queue.MaxRunningJobs {
+			klog.V(4).Infof("Queue <%s> skip enqueue, reason: %d jobs reach the max running jobs %d.",
+				queue.Name, runningJobs[queue.UID], queue.MaxRunningJobs)
+			continue
+		}
+		if queue.MaxPendingJobs > 0 && pendingJobs[queue.UID] >= queue.MaxPendingJobs {
+			klog.V(4).Infof("Queue <%s> skip enqueue of %d pending jobs, reason: reach the max pending jobs %d.",
+				queue.Name, jobs.Len(), queue.MaxPendingJobs)
+			continue
+		}
 		job := jobs.Pop().(*api.JobInfo)
+		pendingJobs[queue.UID]++
 
 		if job.PodGroup.Spec.MinResources == nil || ssn.JobEnqueueable(job) {
 			ssn.JobEnqueued(job)
 			job.PodGroup.Status.Phase = scheduling.PodGroupInqueue
 			ssn.Jobs[job.UID] = job
+			runningJobs[queue.UID]++
 		}
 
 		// Added Queue back until no job in Queue.
@@ -105,6 +105,39 @@ func TestEnqueue(t *testing.T) {
 				"c1/pg1": scheduling.PodGroupPending,
 			},
 		},
+		{
+			Name: "pggroup cannot enqueue because the queue reaches its max running jobs",
+			PodGroups: []*schedulingv1.PodGroup{
+				util.BuildPodGroup("pg1", "c1", "c1", 0, nil, schedulingv1.PodGroupRunning),
+				util.BuildPodGroup("pg2", "c1", "c1", 0, nil, schedulingv1.PodGroupPending),
+			},
+			Queues: []*schedulingv1.Queue{
+				util.BuildQueueWithAnnos("c1", 1, api.BuildResourceList("4", "4G"),
+					map[string]string{api.QueueMaxRunningJobsAnnotationKey: "1"}),
+			},
+			ExpectStatus: map[api.JobID]scheduling.PodGroupPhase{
+				"c1/pg1": scheduling.PodGroupRunning,
+				"c1/pg2": scheduling.PodGroupPending,
+			},
+		},
+		{
+			Name: "only the max pending jobs of the queue are considered for enqueue",
+			PodGroups: []*schedulingv1.PodGroup{
+				util.BuildPodGroup("pg1", "c1", "c1", 0, nil, schedulingv1.PodGroupPending),
+				util.BuildPodGroup("pg2", "c1", "c1", 0, nil, schedulingv1.PodGroupPending),
+				util.BuildPodGroup("pg3", "c1", "c2", 0, nil, schedulingv1.PodGroupPending),
+			},
+			Queues: []*schedulingv1.Queue{
+				util.BuildQueueWithAnnos("c1", 1, api.BuildResourceList("4", "4G"),
+					map[string]string{api.QueueMaxPendingJobsAnnotationKey: "1"}),
+				util.BuildQueue("c2", 1, api.BuildResourceList("4", "4G")),
+			},
+			ExpectStatus: map[api.JobID]scheduling.PodGroupPhase{
+				"c1/pg1": scheduling.PodGroupInqueue,
+				"c1/pg2": scheduling.PodGroupPending,
+				"c1/pg3": scheduling.PodGroupInqueue,
+			},
+		},
 	}
 
 	trueValue := true
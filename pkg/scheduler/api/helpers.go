@@ -21,6 +21,8 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	clientcache "k8s.io/client-go/tools/cache"
+
+	"volcano.sh/volcano/pkg/controllers/apis"
 )
 
 // PodKey returns the string key of a pod.
@@ -38,6 +40,10 @@ func getTaskStatus(pod *v1.Pod) TaskStatus {
 		if pod.DeletionTimestamp != nil {
 			return Releasing
 		}
+		// the pod is checkpointing before it is evicted, or is removed by scaling its job down
+		if _, found := apis.GetCheckpointRequested(pod); found || apis.IsShrinkRequested(pod) {
+			return Releasing
+		}
 
 		return Running
 	case v1.PodPending:
@@ -112,3 +118,36 @@ func MergeErrors(errs ...error) error {
 func JobTerminated(job *JobInfo) bool {
 	return job.PodGroup == nil && len(job.Tasks) == 0
 }
+
+// CompareVictimCost orders victims of the same job by their victim cost, lower cost first,
+// and by index for the same cost, highest index first. It is only decided when either task has a
+// victim cost, or both belong to a job which is scaled down when its resources are reclaimed.
+func CompareVictimCost(l, r *TaskInfo) (less bool, decided bool) {
+	if l.VictimCost == nil && r.VictimCost == nil && !shrinkOnReclaim(l, r) {
+		return false, false
+	}
+
+	var lCost, rCost int32
+	if l.VictimCost != nil {
+		lCost = *l.VictimCost
+	}
+	if r.VictimCost != nil {
+		rCost = *r.VictimCost
+	}
+	if lCost != rCost {
+		return lCost < rCost, true
+	}
+
+	if l.Pod == nil || r.Pod == nil {
+		return false, false
+	}
+	lIndex, rIndex := GetPodIndex(l.Pod), GetPodIndex(r.Pod)
+	if lIndex != rIndex {
+		return lIndex > rIndex, true
+	}
+	return false, false
+}
+
+func shrinkOnReclaim(l, r *TaskInfo) bool {
+	return l.Pod != nil && r.Pod != nil && apis.IsShrinkOnReclaim(l.Pod) && apis.IsShrinkOnReclaim(r.Pod)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Reasons of the Unschedulable condition of a podgroup, telling why the podgroup is not scheduled.
+const (
+	// QueueQuotaInsufficientReason means the queue of the job has not enough quota left for the
+	// minimal resources of the job.
+	QueueQuotaInsufficientReason = "QueueQuotaInsufficient"
+	// NodeResourcesInsufficientReason means the nodes have not enough idle resources for the
+	// pending tasks of the job.
+	NodeResourcesInsufficientReason = "NodeResourcesInsufficient"
+	// NodeTaintsUntoleratedReason means the pending tasks of the job do not tolerate the taints
+	// of the nodes.
+	NodeTaintsUntoleratedReason = "NodeTaintsUntolerated"
+	// NodeAffinityMismatchReason means the nodes do not match the node affinity or the node
+	// selector of the pending tasks of the job.
+	NodeAffinityMismatchReason = "NodeAffinityMismatch"
+)
+
+const insufficientPrefix = "Insufficient "
+
+// DiagnoseJob returns the reason and a message explaining why the job is not ready, from the
+// enqueue decision of the job or from the fit errors of its pending tasks on the nodes. The reason
+// is empty if the fit errors have no known cause.
+func DiagnoseJob(job *JobInfo, nodes map[string]*NodeInfo) (string, string) {
+	if job.JobFitReason != "" {
+		return job.JobFitReason, job.JobFitErrors
+	}
+
+	resourceNodes := map[string]bool{}
+	taintNodes := map[string]bool{}
+	affinityNodes := map[string]bool{}
+	insufficient := map[v1.ResourceName]bool{}
+	taints := map[string]bool{}
+	for uid := range job.TaskStatusIndex[Pending] {
+		fitErrors := job.NodesFitErrors[uid]
+		if fitErrors == nil {
+			continue
+		}
+		for nodeName, fitError := range fitErrors.nodes {
+			for _, reason := range fitError.Reasons() {
+				switch {
+				case strings.HasPrefix(reason, insufficientPrefix):
+					insufficient[v1.ResourceName(strings.TrimPrefix(reason, insufficientPrefix))] = true
+					resourceNodes[nodeName] = true
+				case strings.Contains(reason, "untolerated taint"):
+					taints[reason] = true
+					taintNodes[nodeName] = true
+				case strings.Contains(reason, "node affinity/selector"):
+					affinityNodes[nodeName] = true
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(resourceNodes) == 0 && len(taintNodes) == 0 && len(affinityNodes) == 0:
+		return "", ""
+	case len(resourceNodes) >= len(taintNodes) && len(resourceNodes) >= len(affinityNodes):
+		return NodeResourcesInsufficientReason, diagnoseResources(job, nodes, insufficient, len(resourceNodes))
+	case len(taintNodes) >= len(affinityNodes):
+		return NodeTaintsUntoleratedReason, fmt.Sprintf("pending tasks do not tolerate the taints of %d node(s): %s",
+			len(taintNodes), strings.Join(sortedKeys(taints), ", "))
+	default:
+		return NodeAffinityMismatchReason, fmt.Sprintf("%d node(s) do not match the node affinity or selector of pending tasks",
+			len(affinityNodes))
+	}
+}
+
+// diagnoseResources tells how many of the insufficient resources the nodes are short of for the
+// pending tasks of the job, or that the idle resources are too fragmented across the nodes.
+func diagnoseResources(job *JobInfo, nodes map[string]*NodeInfo, insufficient map[v1.ResourceName]bool, nodeNum int) string {
+	requested := EmptyResource()
+	for _, task := range job.TaskStatusIndex[Pending] {
+		requested.Add(task.Resreq)
+	}
+	idle := EmptyResource()
+	for _, node := range nodes {
+		idle.Add(node.Idle)
+	}
+
+	var names []string
+	for name := range insufficient {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var short []string
+	for _, name := range names {
+		rn := v1.ResourceName(name)
+		if delta := requested.Get(rn) - idle.Get(rn); delta > 0 {
+			short = append(short, fmt.Sprintf("%s %s", formatResource(rn, delta), name))
+		}
+	}
+	pending := len(job.TaskStatusIndex[Pending])
+	if len(short) == 0 {
+		return fmt.Sprintf("%d node(s) have not enough idle %s for %d pending tasks, the idle resources are fragmented",
+			nodeNum, strings.Join(names, ", "), pending)
+	}
+	return fmt.Sprintf("nodes are short of %s for %d pending tasks", strings.Join(short, ", "), pending)
+}
+
+// formatResource formats the quantity of the resource as in a resource list.
+func formatResource(name v1.ResourceName, value float64) string {
+	switch name {
+	case v1.ResourceCPU:
+		return resource.NewMilliQuantity(int64(value), resource.DecimalSI).String()
+	case v1.ResourceMemory:
+		return resource.NewQuantity(int64(value), resource.BinarySI).String()
+	case v1.ResourcePods:
+		return resource.NewQuantity(int64(value), resource.DecimalSI).String()
+	default:
+		// scalar resources are in milli units
+		return resource.NewMilliQuantity(int64(value), resource.DecimalSI).String()
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestDiagnoseJob(t *testing.T) {
+	gpu := ScalarResource{Name: "nvidia.com/gpu", Value: "4"}
+	taint := "node(s) had untolerated taint {dedicated: inference}"
+
+	testCases := []struct {
+		name          string
+		fitReason     string
+		fitErrors     string
+		nodeReasons   map[string]string
+		expectReason  string
+		expectMessage string
+	}{
+		{
+			name:          "queue quota insufficient",
+			fitReason:     QueueQuotaInsufficientReason,
+			fitErrors:     "queue q1 resource quota insufficient",
+			expectReason:  QueueQuotaInsufficientReason,
+			expectMessage: "queue q1 resource quota insufficient",
+		},
+		{
+			name:          "nodes short of gpus",
+			nodeReasons:   map[string]string{"n1": "Insufficient nvidia.com/gpu", "n2": "Insufficient nvidia.com/gpu"},
+			expectReason:  NodeResourcesInsufficientReason,
+			expectMessage: "nodes are short of 4 nvidia.com/gpu for 2 pending tasks",
+		},
+		{
+			name:          "taints not tolerated by most nodes",
+			nodeReasons:   map[string]string{"n1": "Insufficient nvidia.com/gpu", "n2": taint, "n3": taint},
+			expectReason:  NodeTaintsUntoleratedReason,
+			expectMessage: "pending tasks do not tolerate the taints of 2 node(s): " + taint,
+		},
+		{
+			name:          "node affinity mismatch",
+			nodeReasons:   map[string]string{"n1": "node(s) didn't match Pod's node affinity/selector"},
+			expectReason:  NodeAffinityMismatchReason,
+			expectMessage: "1 node(s) do not match the node affinity or selector of pending tasks",
+		},
+		{
+			name:        "unknown cause",
+			nodeReasons: map[string]string{"n1": "node(s) had volume node affinity conflict"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tasks := []*TaskInfo{
+				NewTaskInfo(buildPod("c1", "p1", "", v1.PodPending, BuildResourceList("1", "1Gi", gpu), nil, nil)),
+				NewTaskInfo(buildPod("c1", "p2", "", v1.PodPending, BuildResourceList("1", "1Gi", gpu), nil, nil)),
+			}
+			job := NewJobInfo("c1/job", tasks...)
+			job.JobFitReason = testCase.fitReason
+			job.JobFitErrors = testCase.fitErrors
+
+			nodes := map[string]*NodeInfo{}
+			for _, name := range []string{"n1", "n2"} {
+				nodes[name] = NewNodeInfo(buildNode(name, BuildResourceList("8", "16Gi", ScalarResource{Name: "nvidia.com/gpu", Value: "2"})))
+			}
+			for _, task := range tasks {
+				fitErrors := NewFitErrors()
+				for nodeName, reason := range testCase.nodeReasons {
+					fitErrors.SetNodeError(nodeName, NewFitError(task, &NodeInfo{Name: nodeName}, reason))
+				}
+				job.NodesFitErrors[task.UID] = fitErrors
+			}
+
+			reason, message := DiagnoseJob(job, nodes)
+			if reason != testCase.expectReason {
+				t.Errorf("expected reason %q, got %q", testCase.expectReason, reason)
+			}
+			if message != testCase.expectMessage {
+				t.Errorf("expected message %q, got %q", testCase.expectMessage, message)
+			}
+		})
+	}
+}
@@ -66,6 +66,10 @@ func (db *DisruptionBudget) Clone() *DisruptionBudget {
 // when job waits longer than waiting time, it should enqueue at once, and cluster should reserve resources for it
 const JobWaitingTime = "sla-waiting-time"
 
+// JobEstimatedDurationKey is the podgroup annotation estimating how long the job runs, e.g. 2h,
+// used by the shortest-job-first order policy of queues.
+const JobEstimatedDurationKey = "volcano.sh/estimated-duration"
+
 // TaskID is UID type for Task
 type TaskID types.UID
 
@@ -124,6 +128,7 @@ type TaskInfo struct {
 	Priority                    int32
 	VolumeReady                 bool
 	Preemptable                 bool
+	VictimCost                  *int32
 	BestEffort                  bool
 	HasRestartableInitContainer bool
 	SchGated                    bool
@@ -171,6 +176,10 @@ func getTaskRole(pod *v1.Pod) string {
 
 const TaskPriorityAnnotation = "volcano.sh/task-priority"
 
+// VictimCostAnnotation is the cost of evicting a pod. Among the pods of a job chosen as victims,
+// the ones with lower cost are evicted first, and pods of the same cost by the highest index first.
+const VictimCostAnnotation = "volcano.sh/victim-cost"
+
 // NewTaskInfo creates new taskInfo object for a Pod
 func NewTaskInfo(pod *v1.Pod) *TaskInfo {
 	initResReq := GetPodResourceRequest(pod)
@@ -196,6 +205,7 @@ func NewTaskInfo(pod *v1.Pod) *TaskInfo {
 		Resreq:                      resReq,
 		InitResreq:                  initResReq,
 		Preemptable:                 preemptable,
+		VictimCost:                  GetPodVictimCost(pod),
 		BestEffort:                  bestEffort,
 		HasRestartableInitContainer: hasRestartableInitContainer,
 		RevocableZone:               revocableZone,
@@ -284,6 +294,7 @@ func (ti *TaskInfo) Clone() *TaskInfo {
 		InitResreq:                  ti.InitResreq.Clone(),
 		VolumeReady:                 ti.VolumeReady,
 		Preemptable:                 ti.Preemptable,
+		VictimCost:                  ti.VictimCost,
 		BestEffort:                  ti.BestEffort,
 		HasRestartableInitContainer: ti.HasRestartableInitContainer,
 		RevocableZone:               ti.RevocableZone,
@@ -344,8 +355,14 @@ type JobInfo struct {
 	MinAvailable int32
 
 	WaitingTime *time.Duration
-
-	JobFitErrors   string
+	// EstimatedDuration is how long the job is estimated to run, nil if unknown
+	EstimatedDuration *time.Duration
+	// TopologyConstraint is the topology domain the gang is allocated in, nil if none
+	TopologyConstraint *TopologyConstraint
+
+	JobFitErrors string
+	// JobFitReason is the reason of JobFitErrors, e.g. QueueQuotaInsufficient
+	JobFitReason   string
 	NodesFitErrors map[TaskID]*FitErrors
 
 	// All tasks of the Job.
@@ -421,6 +438,13 @@ func (ji *JobInfo) SetPodGroup(pg *PodGroup) {
 		}
 	}
 
+	ji.EstimatedDuration = ji.extractEstimatedDuration(pg)
+	ji.TopologyConstraint, err = ParseTopologyConstraint(pg.Annotations)
+	if err != nil {
+		klog.Warningf("Error occurs in parsing topology constraint for job <%s/%s>, err: %s.",
+			pg.Namespace, pg.Name, err.Error())
+	}
+
 	ji.Preemptable = ji.extractPreemptable(pg)
 	ji.RevocableZone = ji.extractRevocableZone(pg)
 	ji.Budget = ji.extractBudget(pg)
@@ -450,6 +474,20 @@ func (ji *JobInfo) extractWaitingTime(pg *PodGroup, waitingTimeKey string) (*tim
 	return &jobWaitingTime, nil
 }
 
+// extractEstimatedDuration reads the estimated duration of the job from podgroup annotations
+func (ji *JobInfo) extractEstimatedDuration(pg *PodGroup) *time.Duration {
+	value, found := pg.Annotations[JobEstimatedDurationKey]
+	if !found {
+		return nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		klog.Warningf("invalid %s=%s of job <%s/%s>", JobEstimatedDurationKey, value, pg.Namespace, pg.Name)
+		return nil
+	}
+	return &duration
+}
+
 // extractPreemptable return volcano.sh/preemptable value for job
 func (ji *JobInfo) extractPreemptable(pg *PodGroup) bool {
 	// check annotaion first
@@ -654,12 +692,15 @@ func (ji *JobInfo) Clone() *JobInfo {
 		Queue:     ji.Queue,
 		Priority:  ji.Priority,
 
-		MinAvailable:   ji.MinAvailable,
-		WaitingTime:    ji.WaitingTime,
-		JobFitErrors:   ji.JobFitErrors,
-		NodesFitErrors: make(map[TaskID]*FitErrors),
-		Allocated:      EmptyResource(),
-		TotalRequest:   EmptyResource(),
+		MinAvailable:       ji.MinAvailable,
+		WaitingTime:        ji.WaitingTime,
+		EstimatedDuration:  ji.EstimatedDuration,
+		TopologyConstraint: ji.TopologyConstraint,
+		JobFitErrors:       ji.JobFitErrors,
+		JobFitReason:       ji.JobFitReason,
+		NodesFitErrors:     make(map[TaskID]*FitErrors),
+		Allocated:          EmptyResource(),
+		TotalRequest:       EmptyResource(),
 
 		PodGroup: ji.PodGroup.Clone(),
 
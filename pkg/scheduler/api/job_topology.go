@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// JobTopologyKeyAnnotationKey is the podgroup annotation naming the node label of the topology
+	// domain all the members of the gang are allocated in, e.g. topology.kubernetes.io/zone or a rack label.
+	JobTopologyKeyAnnotationKey = "volcano.sh/topology-key"
+	// JobTopologyModeAnnotationKey is the podgroup annotation telling whether the topology domain is
+	// required, the default, or only preferred: a preferred domain falls back to all the nodes when no
+	// domain can hold the gang.
+	JobTopologyModeAnnotationKey = "volcano.sh/topology-mode"
+
+	// TopologyModeRequired requires the gang to be allocated in a single topology domain.
+	TopologyModeRequired = "required"
+	// TopologyModePreferred prefers the gang to be allocated in a single topology domain.
+	TopologyModePreferred = "preferred"
+)
+
+// TopologyConstraint is the topology domain the members of a gang are allocated in.
+type TopologyConstraint struct {
+	// Key is the node label of the topology domains.
+	Key string
+	// Preferred tells whether the gang may be allocated across domains when no domain can hold it.
+	Preferred bool
+}
+
+// ParseTopologyConstraint returns the topology constraint declared by the annotations, nil if none.
+func ParseTopologyConstraint(annotations map[string]string) (*TopologyConstraint, error) {
+	key, found := annotations[JobTopologyKeyAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	if key == "" {
+		return nil, fmt.Errorf("%s must not be empty", JobTopologyKeyAnnotationKey)
+	}
+
+	constraint := &TopologyConstraint{Key: key}
+	switch mode := annotations[JobTopologyModeAnnotationKey]; mode {
+	case "", TopologyModeRequired:
+	case TopologyModePreferred:
+		constraint.Preferred = true
+	default:
+		return nil, fmt.Errorf("invalid %s <%s>, must be %s or %s",
+			JobTopologyModeAnnotationKey, mode, TopologyModeRequired, TopologyModePreferred)
+	}
+	return constraint, nil
+}
+
+// TopologyDomain is the nodes sharing the same value of the topology key.
+type TopologyDomain struct {
+	Value string
+	Nodes []*NodeInfo
+	// Idle is the future idle resources of the nodes, which include the resources being released
+	// the tasks may be pipelined on
+	Idle *Resource
+}
+
+// GetTopologyDomains groups the nodes labelled with the topology key by the value of the label,
+// the nodes without the label are in no domain. The domains are sorted by value.
+func GetTopologyDomains(nodes []*NodeInfo, key string) []*TopologyDomain {
+	domains := map[string]*TopologyDomain{}
+	for _, node := range nodes {
+		if node.Node == nil {
+			continue
+		}
+		value, found := node.Node.Labels[key]
+		if !found {
+			continue
+		}
+		domain, found := domains[value]
+		if !found {
+			domain = &TopologyDomain{Value: value, Idle: EmptyResource()}
+			domains[value] = domain
+		}
+		domain.Nodes = append(domain.Nodes, node)
+		domain.Idle.Add(node.FutureIdle())
+	}
+
+	result := make([]*TopologyDomain, 0, len(domains))
+	for _, domain := range domains {
+		result = append(result, domain)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+// AllocatedTopologyDomain returns the topology domain of the members of the job already allocated
+// or pipelined.
+func AllocatedTopologyDomain(job *JobInfo, nodes map[string]*NodeInfo, key string) (string, bool) {
+	for status, tasks := range job.TaskStatusIndex {
+		if !AllocatedStatus(status) && status != Pipelined {
+			continue
+		}
+		for _, task := range tasks {
+			node, found := nodes[task.NodeName]
+			if !found || node.Node == nil {
+				continue
+			}
+			if value, found := node.Node.Labels[key]; found {
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// FilterTopologyNodes returns the candidate nodes the tasks of the job may be placed on under its
+// required topology constraint: the nodes of the topology domain of its members already allocated
+// or pipelined, or the nodes in any domain if there is none. The candidates are returned as is for
+// the jobs without a required constraint.
+func FilterTopologyNodes(job *JobInfo, nodes map[string]*NodeInfo, candidates []*NodeInfo) []*NodeInfo {
+	constraint := job.TopologyConstraint
+	if constraint == nil || constraint.Preferred {
+		return candidates
+	}
+	value, allocated := AllocatedTopologyDomain(job, nodes, constraint.Key)
+	result := make([]*NodeInfo, 0, len(candidates))
+	for _, node := range candidates {
+		if node.Node == nil {
+			continue
+		}
+		if v, found := node.Node.Labels[constraint.Key]; found && (!allocated || v == value) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestFilterTopologyNodes(t *testing.T) {
+	resources := v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("4Gi")}
+	newNode := func(name, zone string) *NodeInfo {
+		node := buildNode(name, resources)
+		if zone != "" {
+			node.Labels = map[string]string{"zone": zone}
+		}
+		return NewNodeInfo(node)
+	}
+	nodes := map[string]*NodeInfo{}
+	var nodeList []*NodeInfo
+	for _, node := range []*NodeInfo{newNode("n1", "a"), newNode("n2", "b"), newNode("n3", "")} {
+		nodes[node.Name] = node
+		nodeList = append(nodeList, node)
+	}
+	newJob := func(constraint *TopologyConstraint, nodeName string) *JobInfo {
+		phase := v1.PodPending
+		if nodeName != "" {
+			phase = v1.PodRunning
+		}
+		job := NewJobInfo("job", NewTaskInfo(buildPod("ns", "p1", nodeName, phase, resources, nil, nil)))
+		job.TopologyConstraint = constraint
+		return job
+	}
+
+	testCases := []struct {
+		name     string
+		job      *JobInfo
+		expected []string
+	}{
+		{
+			name:     "no constraint",
+			job:      newJob(nil, ""),
+			expected: []string{"n1", "n2", "n3"},
+		},
+		{
+			name:     "preferred constraint",
+			job:      newJob(&TopologyConstraint{Key: "zone", Preferred: true}, "n1"),
+			expected: []string{"n1", "n2", "n3"},
+		},
+		{
+			name:     "required constraint without allocated members",
+			job:      newJob(&TopologyConstraint{Key: "zone"}, ""),
+			expected: []string{"n1", "n2"},
+		},
+		{
+			name:     "required constraint with allocated members",
+			job:      newJob(&TopologyConstraint{Key: "zone"}, "n2"),
+			expected: []string{"n2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := FilterTopologyNodes(tc.job, nodes, nodeList)
+			var names []string
+			for _, node := range result {
+				names = append(names, node.Name)
+			}
+			if len(names) != len(tc.expected) {
+				t.Fatalf("expected nodes %v, got %v", tc.expected, names)
+			}
+			for i := range names {
+				if names[i] != tc.expected[i] {
+					t.Fatalf("expected nodes %v, got %v", tc.expected, names)
+				}
+			}
+		})
+	}
+}
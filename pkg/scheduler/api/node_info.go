@@ -88,6 +88,20 @@ type NodeInfo struct {
 	// checking an image's existence and advanced usage (e.g., image locality scheduling policy) based on the image
 	// state information.
 	ImageStates map[string]*k8sframework.ImageStateSummary
+
+	// generation is increased whenever the node info is changed, so that an unchanged clone can be reused
+	generation uint64
+}
+
+// Generation returns the generation of the node info, which is increased whenever the node info is changed.
+func (ni *NodeInfo) Generation() uint64 {
+	return ni.generation
+}
+
+// MarkChanged increases the generation of the node info, it must be called after changing the fields of the
+// node info directly rather than by its methods.
+func (ni *NodeInfo) MarkChanged() {
+	ni.generation++
 }
 
 // FutureIdle returns resources that will be idle in the future:
@@ -168,10 +182,17 @@ func NewNodeInfo(node *v1.Node) *NodeInfo {
 // RefreshNumaSchedulerInfoByCrd used to update scheduler numa information based the CRD numatopo
 func (ni *NodeInfo) RefreshNumaSchedulerInfoByCrd() {
 	if ni.NumaInfo == nil {
-		ni.NumaSchedulerInfo = nil
+		if ni.NumaSchedulerInfo != nil {
+			ni.NumaSchedulerInfo = nil
+			ni.MarkChanged()
+		}
+		return
+	}
+	if ni.NumaChgFlag == NumaInfoResetFlag {
 		return
 	}
 
+	ni.MarkChanged()
 	tmp := ni.NumaInfo.DeepCopy()
 	if ni.NumaChgFlag == NumaInfoMoreFlag {
 		ni.NumaSchedulerInfo = tmp
@@ -308,6 +329,7 @@ func (ni *NodeInfo) setNodeState(node *v1.Node) {
 
 // SetNode sets kubernetes node object to nodeInfo object
 func (ni *NodeInfo) SetNode(node *v1.Node) {
+	ni.MarkChanged()
 	ni.setNodeState(node)
 	if !ni.Ready() {
 		klog.Warningf("Failed to set node info for %s, phase: %s, reason: %s",
@@ -441,6 +463,7 @@ func (ni *NodeInfo) AddTask(task *TaskInfo) error {
 	task.NodeName = ni.Name
 	ti.NodeName = ni.Name
 	ni.Tasks[key] = ti
+	ni.MarkChanged()
 
 	return nil
 }
@@ -479,6 +502,7 @@ func (ni *NodeInfo) RemoveTask(ti *TaskInfo) error {
 	}
 
 	delete(ni.Tasks, key)
+	ni.MarkChanged()
 
 	return nil
 }
@@ -73,6 +73,7 @@ func TestNodeInfo_AddPod(t *testing.T) {
 					vgpu.DeviceName:  vgpu.NewGPUDevices("n1", case01Node),
 				},
 				ImageStates: make(map[string]*k8sframework.ImageStateSummary),
+				generation:  2,
 			},
 		},
 		{
@@ -99,6 +100,7 @@ func TestNodeInfo_AddPod(t *testing.T) {
 					vgpu.DeviceName:  vgpu.NewGPUDevices("n2", case01Node),
 				},
 				ImageStates: make(map[string]*k8sframework.ImageStateSummary),
+				generation:  1,
 			},
 			expectedFailure: false,
 		},
@@ -164,6 +166,7 @@ func TestNodeInfo_RemovePod(t *testing.T) {
 					vgpu.DeviceName:  vgpu.NewGPUDevices("n1", case01Node),
 				},
 				ImageStates: make(map[string]*k8sframework.ImageStateSummary),
+				generation:  4,
 			},
 		},
 	}
@@ -231,6 +234,7 @@ func TestNodeInfo_SetNode(t *testing.T) {
 					vgpu.DeviceName:  vgpu.NewGPUDevices("n1", case01Node1),
 				},
 				ImageStates: make(map[string]*k8sframework.ImageStateSummary),
+				generation:  4,
 			},
 			expected2: &NodeInfo{
 				Name:                     "n1",
@@ -254,6 +258,7 @@ func TestNodeInfo_SetNode(t *testing.T) {
 					vgpu.DeviceName:  vgpu.NewGPUDevices("n1", case01Node1),
 				},
 				ImageStates: make(map[string]*k8sframework.ImageStateSummary),
+				generation:  5,
 			},
 		},
 	}
@@ -19,10 +19,12 @@ package api
 import (
 	"encoding/json"
 	"strconv"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 )
 
@@ -115,6 +117,34 @@ func GetPodPreemptable(pod *v1.Pod) bool {
 	return true
 }
 
+// GetPodVictimCost return volcano.sh/victim-cost value for pod, nil if not set or invalid
+func GetPodVictimCost(pod *v1.Pod) *int32 {
+	value, found := pod.Annotations[VictimCostAnnotation]
+	if !found {
+		return nil
+	}
+	cost, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		klog.Warningf("invalid %s=%s", VictimCostAnnotation, value)
+		return nil
+	}
+	victimCost := int32(cost)
+	return &victimCost
+}
+
+// GetPodIndex return the index of pod in its task, from volcano.sh/task-index or the suffix of its name
+func GetPodIndex(pod *v1.Pod) int {
+	value, found := pod.Annotations[batch.TaskIndex]
+	if !found {
+		value = pod.Name[strings.LastIndex(pod.Name, "-")+1:]
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
 // GetPodRevocableZone return volcano.sh/revocable-zone value for pod/podgroup
 func GetPodRevocableZone(pod *v1.Pod) string {
 	if len(pod.Annotations) > 0 {
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"volcano.sh/volcano/pkg/controllers/apis"
 	"volcano.sh/volcano/pkg/scheduler/api/devices/nvidia/gpushare"
 )
 
@@ -435,3 +436,61 @@ func TestGetGPUIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareVictimCost(t *testing.T) {
+	buildTask := func(name string, cost string) *TaskInfo {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if cost != "" {
+			pod.Annotations = map[string]string{VictimCostAnnotation: cost}
+		}
+		return NewTaskInfo(pod)
+	}
+	buildShrinkTask := func(name string) *TaskInfo {
+		return NewTaskInfo(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name,
+			Annotations: map[string]string{apis.ShrinkOnReclaimKey: "true"}}})
+	}
+
+	tests := []struct {
+		name          string
+		l, r          *TaskInfo
+		expectLess    bool
+		expectDecided bool
+	}{
+		{
+			name:          "no victim cost",
+			l:             buildTask("job1-worker-0", ""),
+			r:             buildTask("job1-worker-1", ""),
+			expectDecided: false,
+		},
+		{
+			name:          "lower cost first",
+			l:             buildTask("job1-ps-0", "10"),
+			r:             buildTask("job1-worker-0", ""),
+			expectLess:    false,
+			expectDecided: true,
+		},
+		{
+			name:          "highest index first for the same cost",
+			l:             buildTask("job1-worker-2", "1"),
+			r:             buildTask("job1-worker-1", "1"),
+			expectLess:    true,
+			expectDecided: true,
+		},
+		{
+			name:          "highest index first for a job shrinking on reclaim",
+			l:             buildShrinkTask("job1-worker-2"),
+			r:             buildShrinkTask("job1-worker-1"),
+			expectLess:    true,
+			expectDecided: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			less, decided := CompareVictimCost(test.l, test.r)
+			if less != test.expectLess || decided != test.expectDecided {
+				t.Errorf("expected (%v, %v), but got (%v, %v)", test.expectLess, test.expectDecided, less, decided)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+)
+
+// Annotations recording the phase timeline of a podgroup, the times are in RFC3339 and the
+// latencies are durations, e.g. 1m30s.
+const (
+	// PodGroupPendingTimeKey is the time the podgroup is pending since, i.e. its creation.
+	PodGroupPendingTimeKey = "volcano.sh/pending-time"
+	// PodGroupInqueueTimeKey is the time the podgroup is first admitted into its queue.
+	PodGroupInqueueTimeKey = "volcano.sh/inqueue-time"
+	// PodGroupRunningTimeKey is the time the podgroup first has its minimal members allocated.
+	PodGroupRunningTimeKey = "volcano.sh/running-time"
+	// PodGroupQueueingLatencyKey is the latency from Pending to Inqueue, i.e. waiting for the quota of the queue.
+	PodGroupQueueingLatencyKey = "volcano.sh/queueing-latency"
+	// PodGroupSchedulingLatencyKey is the latency from Inqueue to Running, i.e. waiting for the nodes.
+	PodGroupSchedulingLatencyKey = "volcano.sh/scheduling-latency"
+)
+
+// phaseTimeline is the order of the phases of the timeline, with the annotation of their time.
+var phaseTimeline = []struct {
+	phase scheduling.PodGroupPhase
+	key   string
+}{
+	{scheduling.PodGroupPending, PodGroupPendingTimeKey},
+	{scheduling.PodGroupInqueue, PodGroupInqueueTimeKey},
+	{scheduling.PodGroupRunning, PodGroupRunningTimeKey},
+}
+
+func phaseIndex(phase scheduling.PodGroupPhase) int {
+	for i, p := range phaseTimeline {
+		if p.phase == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+// RecordPhaseTimeline stamps the time the podgroup enters its current phase from oldPhase on its
+// annotations, together with the latency since the previous phase. A podgroup allocated in the session
+// it is enqueued enters Inqueue and Running at the same time. Only the phases the podgroup is seen
+// entering are stamped, e.g. a podgroup running before the scheduler starts only gets its pending time,
+// and a stamped phase is never changed. It returns the latencies recorded, keyed by their annotation,
+// and whether the annotations are changed.
+func RecordPhaseTimeline(pg *PodGroup, oldPhase scheduling.PodGroupPhase, now time.Time) (map[string]time.Duration, bool) {
+	if pg == nil {
+		return nil, false
+	}
+	current := phaseIndex(pg.Status.Phase)
+	if current < 0 {
+		return nil, false
+	}
+	// a new podgroup is pending, any other phase is left as it is
+	from := phaseIndex(oldPhase)
+	if oldPhase == "" {
+		from = 0
+	} else if from < 0 {
+		from = current
+	}
+
+	now = now.Truncate(time.Second)
+	if pg.Annotations == nil {
+		pg.Annotations = map[string]string{}
+	}
+	latencies := map[string]time.Duration{}
+	changed := false
+	if _, found := pg.Annotations[PodGroupPendingTimeKey]; !found {
+		pending := now
+		if !pg.CreationTimestamp.IsZero() {
+			pending = pg.CreationTimestamp.Time
+		}
+		pg.Annotations[PodGroupPendingTimeKey] = pending.UTC().Format(time.RFC3339)
+		changed = true
+	}
+	for i := from + 1; i <= current; i++ {
+		key := phaseTimeline[i].key
+		if _, found := pg.Annotations[key]; found {
+			continue
+		}
+		pg.Annotations[key] = now.UTC().Format(time.RFC3339)
+		changed = true
+
+		previous, err := time.Parse(time.RFC3339, pg.Annotations[phaseTimeline[i-1].key])
+		if err != nil || previous.After(now) {
+			continue
+		}
+		latencyKey := PodGroupQueueingLatencyKey
+		if phaseTimeline[i].phase == scheduling.PodGroupRunning {
+			latencyKey = PodGroupSchedulingLatencyKey
+		}
+		latencies[latencyKey] = now.Sub(previous)
+		pg.Annotations[latencyKey] = now.Sub(previous).String()
+	}
+	return latencies, changed
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+)
+
+func TestRecordPhaseTimeline(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(90 * time.Second)
+
+	tests := []struct {
+		name                string
+		oldPhase            scheduling.PodGroupPhase
+		phase               scheduling.PodGroupPhase
+		annotations         map[string]string
+		expectedAnnotations map[string]string
+		expectedLatencies   map[string]time.Duration
+		expectedChanged     bool
+	}{
+		{
+			name:  "new pending podgroup is pending since its creation",
+			phase: scheduling.PodGroupPending,
+			expectedAnnotations: map[string]string{
+				PodGroupPendingTimeKey: "2024-01-01T00:00:00Z",
+			},
+			expectedLatencies: map[string]time.Duration{},
+			expectedChanged:   true,
+		},
+		{
+			name:     "enqueued podgroup records its queueing latency",
+			oldPhase: scheduling.PodGroupPending,
+			phase:    scheduling.PodGroupInqueue,
+			annotations: map[string]string{
+				PodGroupPendingTimeKey: "2024-01-01T00:00:00Z",
+			},
+			expectedAnnotations: map[string]string{
+				PodGroupPendingTimeKey:     "2024-01-01T00:00:00Z",
+				PodGroupInqueueTimeKey:     "2024-01-01T00:01:30Z",
+				PodGroupQueueingLatencyKey: "1m30s",
+			},
+			expectedLatencies: map[string]time.Duration{PodGroupQueueingLatencyKey: 90 * time.Second},
+			expectedChanged:   true,
+		},
+		{
+			name:     "running podgroup records its scheduling latency",
+			oldPhase: scheduling.PodGroupInqueue,
+			phase:    scheduling.PodGroupRunning,
+			annotations: map[string]string{
+				PodGroupPendingTimeKey: "2024-01-01T00:00:00Z",
+				PodGroupInqueueTimeKey: "2024-01-01T00:01:00Z",
+			},
+			expectedAnnotations: map[string]string{
+				PodGroupPendingTimeKey:       "2024-01-01T00:00:00Z",
+				PodGroupInqueueTimeKey:       "2024-01-01T00:01:00Z",
+				PodGroupRunningTimeKey:       "2024-01-01T00:01:30Z",
+				PodGroupSchedulingLatencyKey: "30s",
+			},
+			expectedLatencies: map[string]time.Duration{PodGroupSchedulingLatencyKey: 30 * time.Second},
+			expectedChanged:   true,
+		},
+		{
+			name:     "podgroup enqueued and allocated in the same session",
+			oldPhase: scheduling.PodGroupPending,
+			phase:    scheduling.PodGroupRunning,
+			expectedAnnotations: map[string]string{
+				PodGroupPendingTimeKey:       "2024-01-01T00:00:00Z",
+				PodGroupInqueueTimeKey:       "2024-01-01T00:01:30Z",
+				PodGroupRunningTimeKey:       "2024-01-01T00:01:30Z",
+				PodGroupQueueingLatencyKey:   "1m30s",
+				PodGroupSchedulingLatencyKey: "0s",
+			},
+			expectedLatencies: map[string]time.Duration{
+				PodGroupQueueingLatencyKey:   90 * time.Second,
+				PodGroupSchedulingLatencyKey: 0,
+			},
+			expectedChanged: true,
+		},
+		{
+			name:     "podgroup running without transition only gets its pending time",
+			oldPhase: scheduling.PodGroupRunning,
+			phase:    scheduling.PodGroupRunning,
+			expectedAnnotations: map[string]string{
+				PodGroupPendingTimeKey: "2024-01-01T00:00:00Z",
+			},
+			expectedLatencies: map[string]time.Duration{},
+			expectedChanged:   true,
+		},
+		{
+			name:     "podgroup enqueued before the timeline has no scheduling latency",
+			oldPhase: scheduling.PodGroupInqueue,
+			phase:    scheduling.PodGroupRunning,
+			annotations: map[string]string{
+				PodGroupPendingTimeKey: "2024-01-01T00:00:00Z",
+			},
+			expectedAnnotations: map[string]string{
+				PodGroupPendingTimeKey: "2024-01-01T00:00:00Z",
+				PodGroupRunningTimeKey: "2024-01-01T00:01:30Z",
+			},
+			expectedLatencies: map[string]time.Duration{},
+			expectedChanged:   true,
+		},
+		{
+			name:     "stamped phases are kept",
+			oldPhase: scheduling.PodGroupPending,
+			phase:    scheduling.PodGroupInqueue,
+			annotations: map[string]string{
+				PodGroupPendingTimeKey: "2024-01-01T00:00:00Z",
+				PodGroupInqueueTimeKey: "2024-01-01T00:00:10Z",
+			},
+			expectedAnnotations: map[string]string{
+				PodGroupPendingTimeKey: "2024-01-01T00:00:00Z",
+				PodGroupInqueueTimeKey: "2024-01-01T00:00:10Z",
+			},
+			expectedLatencies: map[string]time.Duration{},
+		},
+		{
+			name:     "completed podgroup is not recorded",
+			oldPhase: scheduling.PodGroupRunning,
+			phase:    scheduling.PodGroupCompleted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pg := &PodGroup{PodGroup: scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(created),
+					Annotations:       test.annotations,
+				},
+				Status: scheduling.PodGroupStatus{Phase: test.phase},
+			}}
+
+			latencies, changed := RecordPhaseTimeline(pg, test.oldPhase, now)
+			if changed != test.expectedChanged {
+				t.Errorf("expected changed %v, got %v", test.expectedChanged, changed)
+			}
+			if !reflect.DeepEqual(latencies, test.expectedLatencies) {
+				t.Errorf("expected latencies %v, got %v", test.expectedLatencies, latencies)
+			}
+			if !reflect.DeepEqual(pg.Annotations, test.expectedAnnotations) {
+				t.Errorf("expected annotations %v, got %v", test.expectedAnnotations, pg.Annotations)
+			}
+		})
+	}
+}
@@ -17,12 +17,67 @@ limitations under the License.
 package api
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
 
 	"volcano.sh/apis/pkg/apis/scheduling"
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+// QueueBorrowLimitAnnotationKey is the queue annotation limiting how much the queue may borrow
+// beyond its deserved resource, as a JSON resource list, e.g. {"cpu":"4","memory":"8Gi"}.
+// The resources which are not listed are not limited beyond the capability of the queue.
+const QueueBorrowLimitAnnotationKey = "volcano.sh/borrow-limit"
+
+const (
+	// QueueMaxRunningJobsAnnotationKey is the queue annotation limiting the number of jobs of the
+	// queue which are enqueued or running at the same time.
+	QueueMaxRunningJobsAnnotationKey = "volcano.sh/max-running-jobs"
+	// QueueMaxPendingJobsAnnotationKey is the queue annotation limiting the number of pending jobs
+	// of the queue which are considered for enqueue in a scheduling cycle; the other pending jobs
+	// wait for the next cycles. The admission webhook rejects the new jobs of a queue reaching it.
+	QueueMaxPendingJobsAnnotationKey = "volcano.sh/max-pending-jobs"
+)
+
+// QueueJobOrderPolicyAnnotationKey is the queue annotation setting how the jobs of the queue are
+// ordered, one of the JobOrderPolicy values. The job order of the scheduler plugins applies to the
+// queues without the annotation, and to the jobs which are equal by the policy.
+const QueueJobOrderPolicyAnnotationKey = "volcano.sh/job-order-policy"
+
+// JobOrderPolicy is how the jobs of a queue are ordered.
+type JobOrderPolicy string
+
+const (
+	// JobOrderFIFO orders the jobs by their creation time.
+	JobOrderFIFO JobOrderPolicy = "fifo"
+	// JobOrderPriority orders the jobs by their priority class.
+	JobOrderPriority JobOrderPolicy = "priority"
+	// JobOrderSJF orders the jobs by their estimated duration, the shortest job first. The jobs
+	// without estimated duration go last.
+	JobOrderSJF JobOrderPolicy = "sjf"
 )
 
+const (
+	// QueueMaxPreemptionVictimsAnnotationKey is the queue annotation limiting the number of tasks of
+	// the queue which preempt and reclaim may evict in a scheduling cycle.
+	QueueMaxPreemptionVictimsAnnotationKey = "volcano.sh/max-preemption-victims"
+	// QueuePreemptionMinRuntimeAnnotationKey is the queue annotation protecting the tasks of the queue
+	// from preempt and reclaim until they have been running for the duration, e.g. 30m.
+	QueuePreemptionMinRuntimeAnnotationKey = "volcano.sh/preemption-min-runtime"
+)
+
+// QueueBackfillMaxDurationAnnotationKey is the queue annotation allowing the jobs of the queue
+// estimated to run no longer than the duration, e.g. 30m, to be backfilled onto the resources
+// reserved for the pending gangs; the backfilled jobs are evicted once a gang needs their resources.
+const QueueBackfillMaxDurationAnnotationKey = "volcano.sh/backfill-max-duration"
+
 // QueueID is UID type, serves as unique ID for each queue
 type QueueID types.UID
 
@@ -41,6 +96,24 @@ type QueueInfo struct {
 	// path from the root to the node itself.
 	Hierarchy string
 
+	// MaxRunningJobs and MaxPendingJobs are the job limits of the queue, 0 means unlimited.
+	MaxRunningJobs int32
+	MaxPendingJobs int32
+
+	// JobOrderPolicy is how the jobs of the queue are ordered, "" to use the job order of the plugins.
+	JobOrderPolicy JobOrderPolicy
+
+	// MaxPreemptionVictims is the number of tasks of the queue which may be evicted by preempt and
+	// reclaim in a scheduling cycle, 0 means unlimited.
+	MaxPreemptionVictims int32
+	// PreemptionMinRuntime is how long the tasks of the queue run before they may be evicted by
+	// preempt and reclaim.
+	PreemptionMinRuntime time.Duration
+
+	// BackfillMaxDuration is the longest estimated duration of the jobs of the queue which are
+	// backfilled, 0 if the queue does not backfill.
+	BackfillMaxDuration time.Duration
+
 	Queue *scheduling.Queue
 }
 
@@ -54,10 +127,138 @@ func NewQueueInfo(queue *scheduling.Queue) *QueueInfo {
 		Hierarchy: queue.Annotations[v1beta1.KubeHierarchyAnnotationKey],
 		Weights:   queue.Annotations[v1beta1.KubeHierarchyWeightAnnotationKey],
 
+		MaxRunningJobs: queueJobLimit(queue, QueueMaxRunningJobsAnnotationKey),
+		MaxPendingJobs: queueJobLimit(queue, QueueMaxPendingJobsAnnotationKey),
+
+		JobOrderPolicy: queueJobOrderPolicy(queue),
+
+		MaxPreemptionVictims: queueJobLimit(queue, QueueMaxPreemptionVictimsAnnotationKey),
+		PreemptionMinRuntime: queueDuration(queue, QueuePreemptionMinRuntimeAnnotationKey),
+
+		BackfillMaxDuration: queueDuration(queue, QueueBackfillMaxDurationAnnotationKey),
+
 		Queue: queue,
 	}
 }
 
+func queueJobLimit(queue *scheduling.Queue, key string) int32 {
+	limit, err := ParseQueueJobLimit(queue.Annotations, key)
+	if err != nil {
+		klog.Errorf("Ignore the limit of queue <%s>: %v", queue.Name, err)
+		return 0
+	}
+	return limit
+}
+
+func queueJobOrderPolicy(queue *scheduling.Queue) JobOrderPolicy {
+	policy, err := ParseQueueJobOrderPolicy(queue.Annotations)
+	if err != nil {
+		klog.Errorf("Ignore the job order policy of queue <%s>: %v", queue.Name, err)
+		return ""
+	}
+	return policy
+}
+
+func queueDuration(queue *scheduling.Queue, key string) time.Duration {
+	duration, err := ParseQueueDuration(queue.Annotations, key)
+	if err != nil {
+		klog.Errorf("Ignore the duration of queue <%s>: %v", queue.Name, err)
+		return 0
+	}
+	return duration
+}
+
+// ParseQueueDuration parses a duration annotation of a queue, e.g. the preemption min runtime, 0 if it is not set.
+func ParseQueueDuration(annotations map[string]string, key string) (time.Duration, error) {
+	value, found := annotations[key]
+	if !found {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid annotation %s <%s>: must be a non-negative duration", key, value)
+	}
+	return duration, nil
+}
+
+// Backfills returns whether the job of the queue is short enough to be backfilled.
+func (q *QueueInfo) Backfills(job *JobInfo) bool {
+	return q.BackfillMaxDuration > 0 && job.EstimatedDuration != nil && *job.EstimatedDuration <= q.BackfillMaxDuration
+}
+
+// ProtectedFromPreemption returns whether the task of the queue has not been running long enough
+// to be evicted by preempt or reclaim.
+func (q *QueueInfo) ProtectedFromPreemption(task *TaskInfo, now time.Time) bool {
+	if q.PreemptionMinRuntime <= 0 || task.Pod == nil || task.Pod.Status.StartTime == nil {
+		return false
+	}
+	return now.Sub(task.Pod.Status.StartTime.Time) < q.PreemptionMinRuntime
+}
+
+// ParseQueueJobOrderPolicy parses the job order policy annotation of a queue, "" if it is not set.
+func ParseQueueJobOrderPolicy(annotations map[string]string) (JobOrderPolicy, error) {
+	value, found := annotations[QueueJobOrderPolicyAnnotationKey]
+	if !found {
+		return "", nil
+	}
+	switch policy := JobOrderPolicy(value); policy {
+	case JobOrderFIFO, JobOrderPriority, JobOrderSJF:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid annotation %s <%s>: must be one of %s, %s or %s",
+		QueueJobOrderPolicyAnnotationKey, value, JobOrderFIFO, JobOrderPriority, JobOrderSJF)
+}
+
+// CompareJobs compares the jobs of the queue by its job order policy: negative if l goes first,
+// positive if r goes first, 0 if they are equal by the policy.
+func (q *QueueInfo) CompareJobs(l, r *JobInfo) int {
+	switch q.JobOrderPolicy {
+	case JobOrderFIFO:
+		if l.CreationTimestamp.Equal(&r.CreationTimestamp) {
+			return 0
+		}
+		if l.CreationTimestamp.Before(&r.CreationTimestamp) {
+			return -1
+		}
+		return 1
+	case JobOrderPriority:
+		if l.Priority == r.Priority {
+			return 0
+		}
+		if l.Priority > r.Priority {
+			return -1
+		}
+		return 1
+	case JobOrderSJF:
+		switch {
+		case l.EstimatedDuration == nil && r.EstimatedDuration == nil:
+			return 0
+		case r.EstimatedDuration == nil:
+			return -1
+		case l.EstimatedDuration == nil:
+			return 1
+		case *l.EstimatedDuration < *r.EstimatedDuration:
+			return -1
+		case *l.EstimatedDuration > *r.EstimatedDuration:
+			return 1
+		}
+	}
+	return 0
+}
+
+// ParseQueueJobLimit parses a count limit annotation of a queue, e.g. a job limit, 0 if it is not set.
+func ParseQueueJobLimit(annotations map[string]string, key string) (int32, error) {
+	value, found := annotations[key]
+	if !found {
+		return 0, nil
+	}
+	limit, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid annotation %s <%s>: must be a positive integer", key, value)
+	}
+	return int32(limit), nil
+}
+
 // Clone is used to clone queueInfo object
 func (q *QueueInfo) Clone() *QueueInfo {
 	return &QueueInfo{
@@ -66,7 +267,18 @@ func (q *QueueInfo) Clone() *QueueInfo {
 		Weight:    q.Weight,
 		Hierarchy: q.Hierarchy,
 		Weights:   q.Weights,
-		Queue:     q.Queue,
+
+		MaxRunningJobs: q.MaxRunningJobs,
+		MaxPendingJobs: q.MaxPendingJobs,
+
+		JobOrderPolicy: q.JobOrderPolicy,
+
+		MaxPreemptionVictims: q.MaxPreemptionVictims,
+		PreemptionMinRuntime: q.PreemptionMinRuntime,
+
+		BackfillMaxDuration: q.BackfillMaxDuration,
+
+		Queue: q.Queue,
 	}
 }
 
@@ -86,3 +298,32 @@ func (q *QueueInfo) Reclaimable() bool {
 
 	return *q.Queue.Spec.Reclaimable
 }
+
+// IsDraining returns whether the queue is draining, i.e. nothing new is scheduled in the queue
+// while its running jobs keep running.
+func (q *QueueInfo) IsDraining() bool {
+	return q.Queue != nil && q.Queue.Status.State == apis.QueueStateDraining
+}
+
+// BorrowLimit returns the borrow limit of the queue, nil if it is not set.
+func (q *QueueInfo) BorrowLimit() (v1.ResourceList, error) {
+	return ParseQueueBorrowLimit(q.Queue.Annotations)
+}
+
+// ParseQueueBorrowLimit parses the borrow limit annotation of a queue.
+func ParseQueueBorrowLimit(annotations map[string]string) (v1.ResourceList, error) {
+	value, found := annotations[QueueBorrowLimitAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	limit := v1.ResourceList{}
+	if err := json.Unmarshal([]byte(value), &limit); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %v", QueueBorrowLimitAnnotationKey, err)
+	}
+	for name, quantity := range limit {
+		if quantity.Sign() < 0 {
+			return nil, fmt.Errorf("invalid annotation %s: negative limit of %s", QueueBorrowLimitAnnotationKey, name)
+		}
+	}
+	return limit, nil
+}
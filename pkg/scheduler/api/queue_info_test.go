@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompareJobs(t *testing.T) {
+	created := metav1.NewTime(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC))
+	short, long := time.Minute, time.Hour
+	newJob := func(creation metav1.Time, priority int32, duration *time.Duration) *JobInfo {
+		return &JobInfo{CreationTimestamp: creation, Priority: priority, EstimatedDuration: duration}
+	}
+	early := newJob(created, 1, &long)
+	late := newJob(metav1.NewTime(created.Add(time.Hour)), 10, &short)
+	unknown := newJob(created, 1, nil)
+
+	testCases := []struct {
+		name     string
+		policy   JobOrderPolicy
+		l, r     *JobInfo
+		expected int
+	}{
+		{name: "no policy", l: late, r: early, expected: 0},
+		{name: "fifo", policy: JobOrderFIFO, l: late, r: early, expected: 1},
+		{name: "fifo of jobs created together", policy: JobOrderFIFO, l: early, r: unknown, expected: 0},
+		{name: "priority", policy: JobOrderPriority, l: late, r: early, expected: -1},
+		{name: "shortest job first", policy: JobOrderSJF, l: early, r: late, expected: 1},
+		{name: "jobs without estimated duration go last", policy: JobOrderSJF, l: unknown, r: early, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queue := &QueueInfo{JobOrderPolicy: tc.policy}
+			if got := queue.CompareJobs(tc.l, tc.r); got != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseQueueJobOrderPolicy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    JobOrderPolicy
+		expectErr   bool
+	}{
+		{name: "not set"},
+		{name: "sjf", annotations: map[string]string{QueueJobOrderPolicyAnnotationKey: "sjf"}, expected: JobOrderSJF},
+		{name: "unknown", annotations: map[string]string{QueueJobOrderPolicyAnnotationKey: "lifo"}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := ParseQueueJobOrderPolicy(tc.annotations)
+			if (err != nil) != tc.expectErr || policy != tc.expected {
+				t.Errorf("expected %q (error %v), got %q (%v)", tc.expected, tc.expectErr, policy, err)
+			}
+		})
+	}
+}
+
+func TestProtectedFromPreemption(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	newTask := func(started *time.Time) *TaskInfo {
+		pod := &v1.Pod{}
+		if started != nil {
+			pod.Status.StartTime = &metav1.Time{Time: *started}
+		}
+		return &TaskInfo{Pod: pod}
+	}
+	recent, old := now.Add(-time.Minute), now.Add(-time.Hour)
+
+	testCases := []struct {
+		name       string
+		minRuntime time.Duration
+		task       *TaskInfo
+		expected   bool
+	}{
+		{name: "no min runtime", task: newTask(&recent)},
+		{name: "running less than min runtime", minRuntime: 30 * time.Minute, task: newTask(&recent), expected: true},
+		{name: "running longer than min runtime", minRuntime: 30 * time.Minute, task: newTask(&old)},
+		{name: "not started", minRuntime: 30 * time.Minute, task: newTask(nil)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queue := &QueueInfo{PreemptionMinRuntime: tc.minRuntime}
+			if got := queue.ProtectedFromPreemption(tc.task, now); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
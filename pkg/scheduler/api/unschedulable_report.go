@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// UnschedulableReportConfigMap is the name of the ConfigMap the scheduler publishes the report of the
+	// unschedulable podgroups into.
+	UnschedulableReportConfigMap = "volcano-unschedulable-podgroups"
+	// UnschedulableReportKey is the key of the report in the data of the ConfigMap.
+	UnschedulableReportKey = "report.json"
+)
+
+// UnschedulableReport lists the podgroups unschedulable for longer than a threshold, with their blocking reason.
+type UnschedulableReport struct {
+	// Time is the time the report is published at.
+	Time time.Time `json:"time"`
+	// Threshold is how long a podgroup is unschedulable for before it is reported.
+	Threshold string `json:"threshold"`
+	// Total is the number of podgroups unschedulable for longer than the threshold, which may be more than
+	// the podgroups listed.
+	Total int `json:"total"`
+	// Reasons is the number of podgroups unschedulable for longer than the threshold by reason.
+	Reasons map[string]int `json:"reasons,omitempty"`
+	// PodGroups are the podgroups unschedulable for the longest first.
+	PodGroups []UnschedulablePodGroup `json:"podGroups,omitempty"`
+}
+
+// UnschedulablePodGroup is a podgroup of the report of the unschedulable podgroups.
+type UnschedulablePodGroup struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Queue     string `json:"queue"`
+	// Since is the time the podgroup is unschedulable since.
+	Since time.Time `json:"since"`
+	// Reason is the reason of the Unschedulable condition of the podgroup, e.g. NodeResourcesInsufficient.
+	Reason string `json:"reason"`
+	// Message is the diagnosis of the reason, truncated.
+	Message      string `json:"message,omitempty"`
+	MinAvailable int32  `json:"minAvailable"`
+	PendingTasks int    `json:"pendingTasks"`
+}
+
+// ParseUnschedulableReport parses the report of the unschedulable podgroups from the data of its ConfigMap.
+func ParseUnschedulableReport(data map[string]string) (*UnschedulableReport, error) {
+	report := &UnschedulableReport{}
+	if err := json.Unmarshal([]byte(data[UnschedulableReportKey]), report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -80,6 +80,15 @@ func (f *FitErrors) GetUnschedulableAndUnresolvableNodes() map[string]sets.Empty
 	return ret
 }
 
+// NodeReasons returns the reasons the task does not fit each node
+func (f *FitErrors) NodeReasons() map[string][]string {
+	ret := make(map[string][]string, len(f.nodes))
+	for name, node := range f.nodes {
+		ret[name] = node.Reasons()
+	}
+	return ret
+}
+
 // Error returns the final error message
 func (f *FitErrors) Error() string {
 	if f.err == "" {
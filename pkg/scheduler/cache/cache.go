@@ -25,15 +25,22 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	infov1 "k8s.io/client-go/informers/core/v1"
@@ -45,6 +52,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	watchtools "k8s.io/client-go/tools/watch"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -63,12 +71,14 @@ import (
 	vcinformerv1 "volcano.sh/apis/pkg/client/informers/externalversions/scheduling/v1beta1"
 
 	"volcano.sh/volcano/cmd/scheduler/app/options"
+	controllerapis "volcano.sh/volcano/pkg/controllers/apis"
 	"volcano.sh/volcano/pkg/features"
 	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 	volumescheduling "volcano.sh/volcano/pkg/scheduler/capabilities/volumebinding"
 	"volcano.sh/volcano/pkg/scheduler/metrics"
 	"volcano.sh/volcano/pkg/scheduler/metrics/source"
 	commonutil "volcano.sh/volcano/pkg/util"
+	"volcano.sh/volcano/pkg/util/tracing"
 )
 
 const (
@@ -152,6 +162,10 @@ type SchedulerCache struct {
 	// A map from image name to its imageState.
 	imageStates map[string]*imageState
 
+	// nodeSnapshots are the clones of the nodes given to the last session, by node name, when the
+	// snapshots are incremental.
+	nodeSnapshots map[string]*nodeSnapshot
+
 	nodeWorkers uint32
 
 	// IgnoredCSIProvisioners contains a list of provisioners, and pod request pvc with these provisioners will
@@ -169,6 +183,15 @@ type multiSchedulerInfo struct {
 	c                *consistent.Consistent
 }
 
+// nodeSnapshot is the clone of a node given to a session, with the generations of the node and of
+// the clone when it was given.
+type nodeSnapshot struct {
+	node            *schedulingapi.NodeInfo
+	nodeGeneration  uint64
+	clone           *schedulingapi.NodeInfo
+	cloneGeneration uint64
+}
+
 type imageState struct {
 	// Size of the image
 	size int64
@@ -187,7 +210,9 @@ func (db *DefaultBinder) Bind(kubeClient kubernetes.Interface, tasks []*scheduli
 	errMsg := make(map[schedulingapi.TaskID]string)
 	for _, task := range tasks {
 		p := task.Pod
-		if err := db.kubeclient.CoreV1().Pods(p.Namespace).Bind(context.TODO(),
+		ctx, span := tracing.Start(tracing.Extract(context.TODO(), p.Annotations), "BindPod",
+			trace.WithAttributes(attribute.String("namespace", p.Namespace), attribute.String("pod", p.Name), attribute.String("node", task.NodeName)))
+		if err := db.kubeclient.CoreV1().Pods(p.Namespace).Bind(ctx,
 			&v1.Binding{
 				ObjectMeta: metav1.ObjectMeta{Namespace: p.Namespace, Name: p.Name, UID: p.UID, Annotations: p.Annotations},
 				Target: v1.ObjectReference{
@@ -198,9 +223,11 @@ func (db *DefaultBinder) Bind(kubeClient kubernetes.Interface, tasks []*scheduli
 			metav1.CreateOptions{}); err != nil {
 			klog.Errorf("Failed to bind pod <%v/%v> to node %s : %#v", p.Namespace, p.Name, task.NodeName, err)
 			errMsg[task.UID] = err.Error()
+			span.SetStatus(codes.Error, err.Error())
 		} else {
 			metrics.UpdateTaskScheduleDuration(metrics.Duration(p.CreationTimestamp.Time)) // update metrics as soon as pod is bind
 		}
+		span.End()
 	}
 
 	return errMsg
@@ -223,11 +250,28 @@ type defaultEvictor struct {
 func (de *defaultEvictor) Evict(p *v1.Pod, reason string) error {
 	klog.V(3).Infof("Evicting pod %v/%v, because of %v", p.Namespace, p.Name, reason)
 
+	// the job controller removes the replica of the pod by scaling its job down
+	if reason == "reclaim" && controllerapis.IsShrinkOnReclaim(p) {
+		return de.requestShrink(p)
+	}
+
 	evictMsg := fmt.Sprintf("Pod is evicted, because of %v", reason)
 	annotations := map[string]string{}
 	// record that we are evicting the pod
 	de.recorder.AnnotatedEventf(p, annotations, v1.EventTypeWarning, "Evict", evictMsg)
 
+	if grace := controllerapis.GetCheckpointGracePeriod(p); grace > 0 {
+		latest, err := de.checkpoint(p, grace)
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			klog.V(3).Infof("Pod <%v/%v> is gone while checkpointing", p.Namespace, p.Name)
+			return nil
+		}
+		p = latest
+	}
+
 	pod := p.DeepCopy()
 	condition := &v1.PodCondition{
 		Type:    v1.PodReady,
@@ -252,6 +296,88 @@ func (de *defaultEvictor) Evict(p *v1.Pod, reason string) error {
 	return nil
 }
 
+// checkpoint requests the pod to checkpoint and waits until it reports the checkpoint or the
+// grace period elapses. It returns the latest pod, nil if the pod is gone.
+func (de *defaultEvictor) checkpoint(p *v1.Pod, grace time.Duration) (*v1.Pod, error) {
+	requested, found := controllerapis.GetCheckpointRequested(p)
+	if !found {
+		// the checkpoint may be requested already, by the evictor before the scheduler restarted
+		requested = time.Now()
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`,
+			controllerapis.CheckpointRequestedKey, requested.Format(time.RFC3339))
+		if _, err := de.kubeclient.CoreV1().Pods(p.Namespace).Patch(context.TODO(), p.Name,
+			types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			klog.Errorf("Failed to request checkpoint of pod <%v/%v>: %v", p.Namespace, p.Name, err)
+			return nil, err
+		}
+		de.recorder.Eventf(p, v1.EventTypeNormal, "CheckpointRequested",
+			"Checkpoint is requested before evicting the pod, grace period %v", grace)
+	}
+
+	// watch the pod until it reports the checkpoint, rather than polling the apiserver
+	ctx, cancel := context.WithDeadline(context.TODO(), requested.Add(grace))
+	defer cancel()
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", p.Name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return de.kubeclient.CoreV1().Pods(p.Namespace).List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return de.kubeclient.CoreV1().Pods(p.Namespace).Watch(ctx, options)
+		},
+	}
+	latest := p
+	_, err := watchtools.UntilWithSync(ctx, lw, &v1.Pod{}, nil, func(event watch.Event) (bool, error) {
+		if event.Type == watch.Deleted {
+			latest = nil
+			return true, nil
+		}
+		pod, ok := event.Object.(*v1.Pod)
+		if !ok {
+			return false, nil
+		}
+		latest = pod
+		return controllerapis.CheckpointReported(pod), nil
+	})
+	if latest == nil {
+		return nil, nil
+	}
+	if err != nil {
+		klog.Warningf("Pod <%v/%v> did not report its checkpoint within %v, evicting it", p.Namespace, p.Name, grace)
+		de.recorder.Eventf(latest, v1.EventTypeWarning, "CheckpointTimeout",
+			"Checkpoint is not reported within the grace period %v", grace)
+		return latest, nil
+	}
+	klog.V(3).Infof("Pod <%v/%v> reported checkpoint %s", p.Namespace, p.Name,
+		latest.Annotations[controllerapis.CheckpointStatusKey])
+	return latest, nil
+}
+
+// requestShrink asks the job controller to remove the replica of the pod by scaling its task down.
+func (de *defaultEvictor) requestShrink(p *v1.Pod) error {
+	if controllerapis.IsShrinkRequested(p) {
+		return nil
+	}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`,
+		controllerapis.ShrinkRequestedKey, time.Now().Format(time.RFC3339))
+	if _, err := de.kubeclient.CoreV1().Pods(p.Namespace).Patch(context.TODO(), p.Name,
+		types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("Failed to request shrink of pod <%v/%v>: %v", p.Namespace, p.Name, err)
+		return err
+	}
+	de.recorder.Eventf(p, v1.EventTypeNormal, "ShrinkRequested",
+		"Job is requested to scale down to release the resources of the pod")
+	return nil
+}
+
 // defaultStatusUpdater is the default implementation of the StatusUpdater interface
 type defaultStatusUpdater struct {
 	kubeclient kubernetes.Interface
@@ -292,7 +418,8 @@ func (su *defaultStatusUpdater) UpdatePodStatus(pod *v1.Pod) (*v1.Pod, error) {
 	return su.kubeclient.CoreV1().Pods(pod.Namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
 }
 
-// UpdatePodGroup will Update PodGroup
+// UpdatePodGroup will Update PodGroup, its status through the status subresource and its
+// labels and annotations, e.g. the phase timeline, if they changed.
 func (su *defaultStatusUpdater) UpdatePodGroup(pg *schedulingapi.PodGroup) (*schedulingapi.PodGroup, error) {
 	podgroup := &vcv1beta1.PodGroup{}
 	if err := schedulingscheme.Scheme.Convert(&pg.PodGroup, podgroup, nil); err != nil {
@@ -300,11 +427,20 @@ func (su *defaultStatusUpdater) UpdatePodGroup(pg *schedulingapi.PodGroup) (*sch
 		return nil, err
 	}
 
-	updated, err := su.vcclient.SchedulingV1beta1().PodGroups(podgroup.Namespace).Update(context.TODO(), podgroup, metav1.UpdateOptions{})
+	updated, err := su.vcclient.SchedulingV1beta1().PodGroups(podgroup.Namespace).UpdateStatus(context.TODO(), podgroup, metav1.UpdateOptions{})
 	if err != nil {
-		klog.Errorf("Error while updating PodGroup with error: %v", err)
+		klog.Errorf("Error while updating PodGroup status with error: %v", err)
 		return nil, err
 	}
+	if !equality.Semantic.DeepEqual(updated.Labels, podgroup.Labels) ||
+		!equality.Semantic.DeepEqual(updated.Annotations, podgroup.Annotations) {
+		updated.Labels = podgroup.Labels
+		updated.Annotations = podgroup.Annotations
+		if updated, err = su.vcclient.SchedulingV1beta1().PodGroups(podgroup.Namespace).Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("Error while updating PodGroup with error: %v", err)
+			return nil, err
+		}
+	}
 
 	podGroupInfo := &schedulingapi.PodGroup{Version: schedulingapi.PodGroupVersionV1Beta1}
 	if err := schedulingscheme.Scheme.Convert(updated, &podGroupInfo.PodGroup, nil); err != nil {
@@ -972,6 +1108,7 @@ func (sc *SchedulerCache) UpdateSchedulerNumaInfo(AllocatedSets map[string]sched
 		}
 
 		numaInfo.Allocate(sets)
+		sc.Nodes[nodeName].MarkChanged()
 	}
 	return nil
 }
@@ -1293,6 +1430,21 @@ func (sc *SchedulerCache) BindTask() {
 
 // Snapshot returns the complete snapshot of the cluster from cache
 func (sc *SchedulerCache) Snapshot() *schedulingapi.ClusterInfo {
+	return sc.snapshot(false)
+}
+
+// SessionSnapshot returns the snapshot of the cache for a scheduling session. With the IncrementalSnapshot
+// feature, the clones of the nodes which did not change since the last session snapshot are shared with it.
+func (sc *SchedulerCache) SessionSnapshot() *schedulingapi.ClusterInfo {
+	return sc.snapshot(utilfeature.DefaultFeatureGate.Enabled(features.IncrementalSnapshot))
+}
+
+func (sc *SchedulerCache) snapshot(incremental bool) *schedulingapi.ClusterInfo {
+	start := time.Now()
+	defer func() {
+		metrics.UpdateSnapshotDuration(metrics.Duration(start))
+	}()
+
 	sc.Mutex.Lock()
 	defer sc.Mutex.Unlock()
 
@@ -1315,18 +1467,30 @@ func (sc *SchedulerCache) Snapshot() *schedulingapi.ClusterInfo {
 		snapshot.CSINodesStatus[value.CSINodeName] = value.Clone()
 	}
 
+	var nodeSnapshots map[string]*nodeSnapshot
+	if incremental {
+		nodeSnapshots = make(map[string]*nodeSnapshot, len(sc.Nodes))
+	}
 	for _, value := range sc.Nodes {
 		if !value.Ready() {
 			continue
 		}
 
-		snapshot.Nodes[value.Name] = value.Clone()
+		if incremental {
+			snapshot.Nodes[value.Name] = sc.cloneNode(value, nodeSnapshots)
+		} else {
+			snapshot.Nodes[value.Name] = value.Clone()
+		}
 
 		if value.RevocableZone != "" {
 			snapshot.RevocableNodes[value.Name] = snapshot.Nodes[value.Name]
 		}
 	}
 
+	if incremental {
+		sc.nodeSnapshots = nodeSnapshots
+	}
+
 	for _, value := range sc.Queues {
 		snapshot.Queues[value.UID] = value.Clone()
 	}
@@ -1386,6 +1550,28 @@ func (sc *SchedulerCache) Snapshot() *schedulingapi.ClusterInfo {
 	return snapshot
 }
 
+// cloneNode returns the clone of the node given to the last session if neither the node nor the clone
+// changed since, e.g. no task was bound to or evicted from the node, or else a new clone. The clone is
+// recorded into nodeSnapshots.
+func (sc *SchedulerCache) cloneNode(node *schedulingapi.NodeInfo, nodeSnapshots map[string]*nodeSnapshot) *schedulingapi.NodeInfo {
+	if last, found := sc.nodeSnapshots[node.Name]; found && last.node == node &&
+		last.nodeGeneration == node.Generation() && last.cloneGeneration == last.clone.Generation() {
+		nodeSnapshots[node.Name] = last
+		metrics.RegisterSnapshotNode(true)
+		return last.clone
+	}
+
+	clone := node.Clone()
+	nodeSnapshots[node.Name] = &nodeSnapshot{
+		node:            node,
+		nodeGeneration:  node.Generation(),
+		clone:           clone,
+		cloneGeneration: clone.Generation(),
+	}
+	metrics.RegisterSnapshotNode(false)
+	return clone
+}
+
 // String returns information about the cache in a string format
 func (sc *SchedulerCache) String() string {
 	sc.Mutex.Lock()
@@ -1434,6 +1620,7 @@ func (sc *SchedulerCache) RecordJobStatusEvent(job *schedulingapi.JobInfo, updat
 	pgUnschedulable := job.PodGroup != nil &&
 		(job.PodGroup.Status.Phase == scheduling.PodGroupUnknown ||
 			job.PodGroup.Status.Phase == scheduling.PodGroupPending ||
+			job.PodGroup.Status.Phase == "" ||
 			job.PodGroup.Status.Phase == scheduling.PodGroupInqueue)
 
 	fitErrStr := job.FitError()
@@ -1563,6 +1750,7 @@ func (sc *SchedulerCache) setMetricsData(usageInfo map[string]*source.NodeMetric
 		}
 		klog.V(5).Infof("node: %s, ResourceUsage: %+v => %+v", nodeName, *nodeInfo.ResourceUsage, nodeUsage)
 		nodeInfo.ResourceUsage = nodeUsage
+		nodeInfo.MarkChanged()
 	}
 }
 
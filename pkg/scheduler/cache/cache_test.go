@@ -20,14 +20,22 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	controllerapis "volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/features"
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/util"
 )
@@ -304,6 +312,72 @@ func TestNodeOperation(t *testing.T) {
 	}
 }
 
+func TestSessionSnapshot(t *testing.T) {
+	n1 := buildNode("n1", api.BuildResourceList("2000m", "10G", []api.ScalarResource{{Name: "pods", Value: "10"}}...))
+	n2 := buildNode("n2", api.BuildResourceList("4000m", "16G", []api.ScalarResource{{Name: "pods", Value: "10"}}...))
+	newCache := func() *SchedulerCache {
+		cache := &SchedulerCache{
+			Nodes:    make(map[string]*api.NodeInfo),
+			NodeList: []string{},
+		}
+		cache.AddOrUpdateNode(n1)
+		cache.AddOrUpdateNode(n2)
+		return cache
+	}
+
+	t.Run("incremental snapshot disabled", func(t *testing.T) {
+		cache := newCache()
+		first, second := cache.SessionSnapshot(), cache.SessionSnapshot()
+		if first.Nodes["n1"] == second.Nodes["n1"] {
+			t.Errorf("expected node n1 cloned for every session")
+		}
+	})
+
+	t.Run("incremental snapshot enabled", func(t *testing.T) {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.IncrementalSnapshot, true)()
+		cache := newCache()
+
+		first := cache.SessionSnapshot()
+		second := cache.SessionSnapshot()
+		if first.Nodes["n1"] != second.Nodes["n1"] || first.Nodes["n2"] != second.Nodes["n2"] {
+			t.Errorf("expected the unchanged nodes reused by the next session")
+		}
+
+		// the session allocates a task on n1
+		pod := buildPod("c1", "p1", "", v1.PodPending, api.BuildResourceList("1000m", "1G"), nil, nil)
+		task := api.NewTaskInfo(pod)
+		task.NodeName = "n1"
+		if err := second.Nodes["n1"].AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+		third := cache.SessionSnapshot()
+		if third.Nodes["n1"] == second.Nodes["n1"] || len(third.Nodes["n1"].Tasks) != 0 {
+			t.Errorf("expected node n1 changed by the session cloned from the cache again")
+		}
+		if third.Nodes["n2"] != second.Nodes["n2"] {
+			t.Errorf("expected node n2 unchanged reused")
+		}
+
+		// the cache updates n2
+		cache.AddOrUpdateNode(buildNode("n2", api.BuildResourceList("8000m", "16G", []api.ScalarResource{{Name: "pods", Value: "10"}}...)))
+		fourth := cache.SessionSnapshot()
+		if fourth.Nodes["n2"] == third.Nodes["n2"] || fourth.Nodes["n2"].Allocatable.MilliCPU != 8000 {
+			t.Errorf("expected node n2 changed in the cache cloned again")
+		}
+
+		// the full snapshot never shares the nodes of the sessions
+		if full := cache.Snapshot(); full.Nodes["n1"] == fourth.Nodes["n1"] {
+			t.Errorf("expected the full snapshot to clone every node")
+		}
+
+		// a removed node is not given anymore
+		cache.RemoveNode("n1")
+		if _, found := cache.SessionSnapshot().Nodes["n1"]; found {
+			t.Errorf("expected removed node n1 not in the snapshot")
+		}
+	})
+}
+
 func TestBindTasks(t *testing.T) {
 	owner := buildOwnerReference("j1")
 	scheduler := "fake-scheduler"
@@ -339,3 +413,88 @@ func TestBindTasks(t *testing.T) {
 		t.Fatalf("succesfully binding task should have 1 event")
 	}
 }
+
+func TestDefaultEvictorCheckpoint(t *testing.T) {
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedPatch  bool
+		expectedEvents []string
+	}{
+		{
+			name:           "pod without checkpoint protocol is evicted at once",
+			expectedEvents: []string{"Evict"},
+		},
+		{
+			name: "pod is evicted once it reports the checkpoint",
+			annotations: map[string]string{
+				controllerapis.CheckpointGracePeriodKey: "1h",
+				controllerapis.CheckpointRequestedKey:   time.Now().Format(time.RFC3339),
+				controllerapis.CheckpointStatusKey:      controllerapis.CheckpointCompleted,
+			},
+			expectedEvents: []string{"Evict"},
+		},
+		{
+			name: "pod is evicted when the grace period elapses",
+			annotations: map[string]string{
+				controllerapis.CheckpointGracePeriodKey: "50ms",
+			},
+			expectedPatch:  true,
+			expectedEvents: []string{"Evict", "CheckpointRequested", "CheckpointTimeout"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := buildPod("c1", "p1", "n1", v1.PodRunning, api.BuildResourceList("1000m", "1G"), nil, nil)
+			pod.Annotations = tc.annotations
+			kubeClient := fake.NewSimpleClientset(pod)
+			recorder := record.NewFakeRecorder(10)
+			evictor := &defaultEvictor{kubeclient: kubeClient, recorder: recorder}
+
+			if err := evictor.Evict(pod, "preempt"); err != nil {
+				t.Fatalf("failed to evict pod: %v", err)
+			}
+
+			if _, err := kubeClient.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+				t.Errorf("expected pod to be deleted, got %v", err)
+			}
+			patched := false
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() == "patch" {
+					patched = true
+				}
+			}
+			if patched != tc.expectedPatch {
+				t.Errorf("expected patch %v, got %v", tc.expectedPatch, patched)
+			}
+			close(recorder.Events)
+			var reasons []string
+			for event := range recorder.Events {
+				reasons = append(reasons, strings.Fields(event)[1])
+			}
+			if !reflect.DeepEqual(reasons, tc.expectedEvents) {
+				t.Errorf("expected events %v, got %v", tc.expectedEvents, reasons)
+			}
+		})
+	}
+}
+
+func TestDefaultEvictorShrink(t *testing.T) {
+	pod := buildPod("c1", "p1", "n1", v1.PodRunning, api.BuildResourceList("1000m", "1G"), nil, nil)
+	pod.Annotations = map[string]string{controllerapis.ShrinkOnReclaimKey: "true"}
+	kubeClient := fake.NewSimpleClientset(pod)
+	evictor := &defaultEvictor{kubeclient: kubeClient, recorder: record.NewFakeRecorder(10)}
+
+	if err := evictor.Evict(pod, "reclaim"); err != nil {
+		t.Fatalf("failed to evict pod: %v", err)
+	}
+
+	got, err := kubeClient.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected pod to be kept, got %v", err)
+	}
+	if !controllerapis.IsShrinkRequested(got) {
+		t.Errorf("expected shrink to be requested, got annotations %v", got.Annotations)
+	}
+}
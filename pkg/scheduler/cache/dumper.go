@@ -19,17 +19,21 @@ package cache
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"path"
 	"runtime"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
 	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
 )
 
 // Dumper writes some information from the scheduler cache to the scheduler logs
@@ -118,3 +122,164 @@ func (d *Dumper) ListenForSignal(stopCh <-chan struct{}) {
 		}
 	}()
 }
+
+// CacheDump is the snapshot of the scheduler cache served on /debug/cache. The resources are in the
+// units of the scheduler: millicores of cpu, bytes of memory and milli-units of the other resources.
+type CacheDump struct {
+	Time   time.Time   `json:"time"`
+	Nodes  []NodeDump  `json:"nodes"`
+	Jobs   []JobDump   `json:"jobs"`
+	Queues []QueueDump `json:"queues"`
+}
+
+// NodeDump is a node of the cache.
+type NodeDump struct {
+	Name        string                      `json:"name"`
+	Phase       string                      `json:"phase"`
+	Reason      string                      `json:"reason,omitempty"`
+	Allocatable map[v1.ResourceName]float64 `json:"allocatable"`
+	Idle        map[v1.ResourceName]float64 `json:"idle"`
+	Used        map[v1.ResourceName]float64 `json:"used"`
+	Releasing   map[v1.ResourceName]float64 `json:"releasing"`
+	Pipelined   map[v1.ResourceName]float64 `json:"pipelined"`
+	Tasks       int                         `json:"tasks"`
+}
+
+// JobDump is a job of the cache.
+type JobDump struct {
+	Namespace    string         `json:"namespace"`
+	Name         string         `json:"name"`
+	Queue        string         `json:"queue"`
+	Phase        string         `json:"phase"`
+	MinAvailable int32          `json:"minAvailable"`
+	Tasks        map[string]int `json:"tasks"`
+	PendingTasks []string       `json:"pendingTasks,omitempty"`
+}
+
+// QueueDump is a queue of the cache, with the share of the last scheduling session if the proportion
+// or the capacity plugin computed it.
+type QueueDump struct {
+	Name       string                      `json:"name"`
+	State      string                      `json:"state"`
+	Weight     int32                       `json:"weight"`
+	Capability map[v1.ResourceName]float64 `json:"capability,omitempty"`
+	Allocated  map[v1.ResourceName]float64 `json:"allocated"`
+	Share      *float64                    `json:"share,omitempty"`
+}
+
+// Dump returns the snapshot of the cache, with only the jobs which have pending tasks if pendingOnly.
+func (d *Dumper) Dump(pendingOnly bool) *CacheDump {
+	snapshot := d.Cache.Snapshot()
+	dump := &CacheDump{
+		Time:   time.Now(),
+		Nodes:  []NodeDump{},
+		Jobs:   []JobDump{},
+		Queues: []QueueDump{},
+	}
+
+	for _, node := range snapshot.Nodes {
+		dump.Nodes = append(dump.Nodes, NodeDump{
+			Name:        node.Name,
+			Phase:       node.State.Phase.String(),
+			Reason:      node.State.Reason,
+			Allocatable: resourceDump(node.Allocatable),
+			Idle:        resourceDump(node.Idle),
+			Used:        resourceDump(node.Used),
+			Releasing:   resourceDump(node.Releasing),
+			Pipelined:   resourceDump(node.Pipelined),
+			Tasks:       len(node.Tasks),
+		})
+	}
+	sort.Slice(dump.Nodes, func(i, j int) bool { return dump.Nodes[i].Name < dump.Nodes[j].Name })
+
+	allocated := map[api.QueueID]*api.Resource{}
+	for _, job := range snapshot.Jobs {
+		if allocated[job.Queue] == nil {
+			allocated[job.Queue] = api.EmptyResource()
+		}
+		if job.Allocated != nil {
+			allocated[job.Queue].Add(job.Allocated)
+		}
+
+		pending := job.TaskStatusIndex[api.Pending]
+		if pendingOnly && len(pending) == 0 {
+			continue
+		}
+		jobDump := JobDump{
+			Namespace:    job.Namespace,
+			Name:         job.Name,
+			Queue:        string(job.Queue),
+			MinAvailable: job.MinAvailable,
+			Tasks:        map[string]int{},
+		}
+		if job.PodGroup != nil {
+			jobDump.Phase = string(job.PodGroup.Status.Phase)
+		}
+		for status, tasks := range job.TaskStatusIndex {
+			if len(tasks) != 0 {
+				jobDump.Tasks[status.String()] = len(tasks)
+			}
+		}
+		for _, task := range pending {
+			jobDump.PendingTasks = append(jobDump.PendingTasks, task.Name)
+		}
+		sort.Strings(jobDump.PendingTasks)
+		dump.Jobs = append(dump.Jobs, jobDump)
+	}
+	sort.Slice(dump.Jobs, func(i, j int) bool {
+		if dump.Jobs[i].Namespace != dump.Jobs[j].Namespace {
+			return dump.Jobs[i].Namespace < dump.Jobs[j].Namespace
+		}
+		return dump.Jobs[i].Name < dump.Jobs[j].Name
+	})
+
+	for _, queue := range snapshot.Queues {
+		queueDump := QueueDump{
+			Name:      queue.Name,
+			Weight:    queue.Weight,
+			Allocated: resourceDump(allocated[queue.UID]),
+		}
+		if queue.Queue != nil {
+			queueDump.State = string(queue.Queue.Status.State)
+			if len(queue.Queue.Spec.Capability) != 0 {
+				queueDump.Capability = resourceDump(api.NewResource(queue.Queue.Spec.Capability))
+			}
+		}
+		if share, found := metrics.QueueShare(queue.Name); found {
+			queueDump.Share = &share
+		}
+		dump.Queues = append(dump.Queues, queueDump)
+	}
+	sort.Slice(dump.Queues, func(i, j int) bool { return dump.Queues[i].Name < dump.Queues[j].Name })
+
+	return dump
+}
+
+// ServeHTTP serves the cache dump as JSON; the "pending" query parameter, e.g. ?pending=true,
+// restricts the jobs to the ones with pending tasks.
+func (d *Dumper) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(d.Dump(r.URL.Query().Get("pending") == "true")); err != nil {
+		klog.Errorf("Failed to write the cache dump: %v", err)
+	}
+}
+
+func resourceDump(resource *api.Resource) map[v1.ResourceName]float64 {
+	dump := map[v1.ResourceName]float64{}
+	if resource == nil {
+		return dump
+	}
+	dump[v1.ResourceCPU] = resource.MilliCPU
+	dump[v1.ResourceMemory] = resource.Memory
+	for name, value := range resource.ScalarResources {
+		dump[name] = value
+	}
+	return dump
+}
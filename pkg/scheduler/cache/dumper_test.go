@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestResourceDump(t *testing.T) {
+	testCases := []struct {
+		name     string
+		resource *api.Resource
+		expected map[v1.ResourceName]float64
+	}{
+		{
+			name:     "nil resource",
+			expected: map[v1.ResourceName]float64{},
+		},
+		{
+			name: "scalar resources",
+			resource: &api.Resource{MilliCPU: 4000, Memory: 1024,
+				ScalarResources: map[v1.ResourceName]float64{"nvidia.com/gpu": 2000}},
+			expected: map[v1.ResourceName]float64{v1.ResourceCPU: 4000, v1.ResourceMemory: 1024, "nvidia.com/gpu": 2000},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if dump := resourceDump(tc.resource); !reflect.DeepEqual(dump, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, dump)
+			}
+		})
+	}
+}
+
+func TestDumperServeHTTP(t *testing.T) {
+	dumper := &Dumper{Cache: NewDefaultMockSchedulerCache("volcano")}
+
+	recorder := httptest.NewRecorder()
+	dumper.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/cache", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d for POST, got %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	dumper.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/cache?pending=true", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected status %d for GET, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected JSON content, got %s", contentType)
+	}
+}
@@ -46,6 +46,7 @@ import (
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/apis/pkg/apis/utils"
 
+	controllerapis "volcano.sh/volcano/pkg/controllers/apis"
 	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/metrics"
 )
@@ -389,6 +390,16 @@ func (sc *SchedulerCache) AddPod(obj interface{}) {
 		return
 	}
 	klog.V(3).Infof("Added pod <%s/%v> into cache.", pod.Namespace, pod.Name)
+
+	// resume the eviction of a pod which was checkpointing when the scheduler restarted
+	if _, found := controllerapis.GetCheckpointRequested(pod); found && pod.DeletionTimestamp == nil &&
+		pod.Status.Phase == v1.PodRunning && sc.Evictor != nil {
+		go func() {
+			if err := sc.Evictor.Evict(pod, "checkpoint"); err != nil {
+				klog.Errorf("Failed to resume eviction of pod <%s/%s>: %v", pod.Namespace, pod.Name, err)
+			}
+		}()
+	}
 }
 
 // UpdatePod update pod to scheduler cache
@@ -1140,6 +1151,7 @@ func (sc *SchedulerCache) addNumaInfo(info *nodeinfov1alpha1.Numatopology) error
 
 		sc.Nodes[info.Name].NumaInfo = newLocalInfo
 	}
+	sc.Nodes[info.Name].MarkChanged()
 
 	for resName, NumaResInfo := range sc.Nodes[info.Name].NumaInfo.NumaResMap {
 		klog.V(3).Infof("resource %s Allocatable %v on node[%s] into cache", resName, NumaResInfo, info.Name)
@@ -1155,6 +1167,7 @@ func (sc *SchedulerCache) deleteNumaInfo(info *nodeinfov1alpha1.Numatopology) {
 	if sc.Nodes[info.Name] != nil {
 		sc.Nodes[info.Name].NumaInfo = nil
 		sc.Nodes[info.Name].NumaChgFlag = schedulingapi.NumaInfoResetFlag
+		sc.Nodes[info.Name].MarkChanged()
 		klog.V(3).Infof("delete numainfo in cahce for node<%s>", info.Name)
 	}
 }
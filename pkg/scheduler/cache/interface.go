@@ -36,6 +36,10 @@ type Cache interface {
 	// Snapshot deep copy overall cache information into snapshot
 	Snapshot() *api.ClusterInfo
 
+	// SessionSnapshot is Snapshot for a scheduling session, which may share the nodes unchanged since
+	// the last session snapshot with it, so that only one session snapshot may be in use at a time.
+	SessionSnapshot() *api.ClusterInfo
+
 	// WaitForCacheSync waits for all cache synced
 	WaitForCacheSync(stopCh <-chan struct{})
 
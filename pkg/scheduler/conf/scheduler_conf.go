@@ -28,6 +28,15 @@ type SchedulerConfiguration struct {
 	// Configurations is configuration for actions
 	Configurations       []Configuration   `yaml:"configurations"`
 	MetricsConfiguration map[string]string `yaml:"metrics"`
+	// Logging defines the settings of the logs
+	Logging *LoggingConfiguration `yaml:"logging"`
+}
+
+// LoggingConfiguration defines the settings of the logs, which are reloaded with the configuration
+type LoggingConfiguration struct {
+	// Components is the verbosity of the logs by component, e.g. an action, a plugin or the cache,
+	// superseding the -v flag for them
+	Components map[string]int32 `yaml:"components"`
 }
 
 // Tier defines plugin tier
@@ -0,0 +1,282 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// BindOperation is the operation binding a task to a node
+	BindOperation = "Bind"
+	// PipelineOperation is the operation pipelining a task to a node with releasing resources
+	PipelineOperation = "Pipeline"
+	// EvictOperation is the operation evicting a task from its node
+	EvictOperation = "Evict"
+)
+
+// decisionLogQueueSize is the number of sessions whose records wait to be written to the decision log.
+const decisionLogQueueSize = 16
+
+// DecisionLog writes the decisions taken in every scheduling session as JSON lines, one per job,
+// so that why a job was or was not scheduled can be answered after the fact. The records are
+// written by Run, so that a slow disk does not delay the scheduling sessions.
+type DecisionLog struct {
+	mutex   sync.Mutex
+	out     io.Writer
+	records chan []*JobDecision
+}
+
+// NewDecisionLog returns a DecisionLog writing to out
+func NewDecisionLog(out io.Writer) *DecisionLog {
+	return &DecisionLog{out: out, records: make(chan []*JobDecision, decisionLogQueueSize)}
+}
+
+// Enqueue queues the records of a session to be written by Run; they are dropped if the
+// records of too many sessions are waiting.
+func (l *DecisionLog) Enqueue(records []*JobDecision) {
+	select {
+	case l.records <- records:
+	default:
+		klog.Warningf("Decision log is %d sessions behind, dropping the records of a session", decisionLogQueueSize)
+	}
+}
+
+// Run writes the queued records until stopCh is closed, then writes the records left in the queue.
+func (l *DecisionLog) Run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case records := <-l.records:
+			l.Write(records)
+		case <-stopCh:
+			for {
+				select {
+				case records := <-l.records:
+					l.Write(records)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// JobDecision is the record of a job in a scheduling session
+type JobDecision struct {
+	Time         time.Time `json:"time"`
+	Session      types.UID `json:"session"`
+	Job          string    `json:"job"`
+	Queue        string    `json:"queue"`
+	Phase        string    `json:"phase,omitempty"`
+	MinAvailable int32     `json:"minAvailable"`
+	// Considered lists the actions which tried to schedule the job
+	Considered []string `json:"considered,omitempty"`
+	// Skipped lists why the actions did not try to schedule the job
+	Skipped  []string        `json:"skipped,omitempty"`
+	FitError string          `json:"fitError,omitempty"`
+	Tasks    []*TaskDecision `json:"tasks,omitempty"`
+}
+
+// TaskDecision is the record of a task in a scheduling session
+type TaskDecision struct {
+	Task   string `json:"task"`
+	Status string `json:"status"`
+	// Candidates are the nodes which passed the predicates for the task
+	Candidates []string           `json:"candidates,omitempty"`
+	Scores     map[string]float64 `json:"scores,omitempty"`
+	// FitErrors are the reasons the task does not fit each node
+	FitErrors  map[string][]string  `json:"fitErrors,omitempty"`
+	Operations []*OperationDecision `json:"operations,omitempty"`
+}
+
+// OperationDecision is the record of an operation committed on a task
+type OperationDecision struct {
+	Operation string `json:"operation"`
+	Node      string `json:"node,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Write writes the records, one JSON object per line
+func (l *DecisionLog) Write(records []*JobDecision) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	encoder := json.NewEncoder(l.out)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			klog.Errorf("Failed to write the decision of job <%s> in session <%s>: %v", record.Job, record.Session, err)
+			return
+		}
+	}
+}
+
+// sessionDecisions collects the decisions taken on the jobs and tasks in a session
+type sessionDecisions struct {
+	log   *DecisionLog
+	jobs  map[api.JobID]*JobDecision
+	tasks map[api.TaskID]*TaskDecision
+}
+
+// SetDecisionLog makes the session record its decisions into log when it is closed
+func (ssn *Session) SetDecisionLog(log *DecisionLog) {
+	if log == nil {
+		ssn.decisions = nil
+		return
+	}
+	ssn.decisions = &sessionDecisions{
+		log:   log,
+		jobs:  map[api.JobID]*JobDecision{},
+		tasks: map[api.TaskID]*TaskDecision{},
+	}
+}
+
+func (ssn *Session) jobDecision(jobID api.JobID) *JobDecision {
+	if d, found := ssn.decisions.jobs[jobID]; found {
+		return d
+	}
+	d := &JobDecision{}
+	ssn.decisions.jobs[jobID] = d
+	return d
+}
+
+func (ssn *Session) taskDecision(task *api.TaskInfo) *TaskDecision {
+	if d, found := ssn.decisions.tasks[task.UID]; found {
+		return d
+	}
+	d := &TaskDecision{}
+	ssn.decisions.tasks[task.UID] = d
+	ssn.jobDecision(task.Job)
+	return d
+}
+
+// RecordJobConsidered records that the action tried to schedule the job
+func (ssn *Session) RecordJobConsidered(job *api.JobInfo, action string) {
+	if ssn.decisions == nil {
+		return
+	}
+	d := ssn.jobDecision(job.UID)
+	d.Considered = append(d.Considered, action)
+}
+
+// RecordJobSkipped records why the action did not try to schedule the job
+func (ssn *Session) RecordJobSkipped(job *api.JobInfo, action, reason string) {
+	if ssn.decisions == nil {
+		return
+	}
+	d := ssn.jobDecision(job.UID)
+	d.Skipped = append(d.Skipped, fmt.Sprintf("%s: %s", action, reason))
+}
+
+// RecordTaskCandidates records the nodes which passed the predicates for the task, and their scores if they were scored
+func (ssn *Session) RecordTaskCandidates(task *api.TaskInfo, nodes []*api.NodeInfo, nodeScores map[float64][]*api.NodeInfo) {
+	if ssn.decisions == nil {
+		return
+	}
+	d := ssn.taskDecision(task)
+	d.Candidates = make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		d.Candidates = append(d.Candidates, node.Name)
+	}
+	sort.Strings(d.Candidates)
+	if len(nodeScores) == 0 {
+		return
+	}
+	d.Scores = map[string]float64{}
+	for score, nodes := range nodeScores {
+		for _, node := range nodes {
+			d.Scores[node.Name] = score
+		}
+	}
+}
+
+func (ssn *Session) recordOperation(task *api.TaskInfo, operation, node, reason string) {
+	if ssn.decisions == nil {
+		return
+	}
+	d := ssn.taskDecision(task)
+	d.Operations = append(d.Operations, &OperationDecision{Operation: operation, Node: node, Reason: reason})
+}
+
+// decisionRecords returns the records of the jobs the session took a decision on or which still have pending tasks,
+// sorted by job and task.
+func (ssn *Session) decisionRecords(now time.Time) []*JobDecision {
+	var records []*JobDecision
+	for _, job := range ssn.Jobs {
+		d, found := ssn.decisions.jobs[job.UID]
+		if !found && len(job.TaskStatusIndex[api.Pending]) == 0 {
+			continue
+		}
+		if d == nil {
+			d = &JobDecision{}
+		}
+		d.Time = now
+		d.Session = ssn.UID
+		d.Job = fmt.Sprintf("%s/%s", job.Namespace, job.Name)
+		d.Queue = string(job.Queue)
+		d.MinAvailable = job.MinAvailable
+		if job.PodGroup != nil {
+			d.Phase = string(job.PodGroup.Status.Phase)
+		}
+		if len(job.TaskStatusIndex[api.Pending]) != 0 {
+			d.FitError = job.FitError()
+		}
+
+		d.Tasks = nil
+		for _, task := range job.Tasks {
+			td, found := ssn.decisions.tasks[task.UID]
+			fitErrors := job.NodesFitErrors[task.UID]
+			if !found && fitErrors == nil && task.Status != api.Pending {
+				continue
+			}
+			if td == nil {
+				td = &TaskDecision{}
+			}
+			td.Task = task.Name
+			td.Status = task.Status.String()
+			if fitErrors != nil {
+				td.FitErrors = fitErrors.NodeReasons()
+			}
+			d.Tasks = append(d.Tasks, td)
+		}
+		sort.Slice(d.Tasks, func(i, j int) bool {
+			return d.Tasks[i].Task < d.Tasks[j].Task
+		})
+		records = append(records, d)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Job < records[j].Job
+	})
+	return records
+}
+
+// writeDecisions queues the decisions of the session to be written into its decision log, if any
+func (ssn *Session) writeDecisions() {
+	if ssn.decisions == nil {
+		return
+	}
+	ssn.decisions.log.Enqueue(ssn.decisionRecords(time.Now()))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func newDecisionJob(name string, pods ...*v1.Pod) *api.JobInfo {
+	var tasks []*api.TaskInfo
+	for _, pod := range pods {
+		tasks = append(tasks, api.NewTaskInfo(pod))
+	}
+	job := api.NewJobInfo(api.JobID("c1/"+name), tasks...)
+	job.Name = name
+	job.Namespace = "c1"
+	job.Queue = "q1"
+	job.MinAvailable = int32(len(pods))
+	return job
+}
+
+func TestDecisionRecords(t *testing.T) {
+	req := api.BuildResourceList("1", "1G")
+	j1 := newDecisionJob("j1",
+		util.BuildPod("c1", "p1", "n1", v1.PodPending, req, "j1", nil, nil),
+		util.BuildPod("c1", "p2", "", v1.PodPending, req, "j1", nil, nil))
+	j2 := newDecisionJob("j2",
+		util.BuildPod("c1", "p3", "n1", v1.PodRunning, req, "j2", nil, nil))
+	j3 := newDecisionJob("j3",
+		util.BuildPod("c1", "p4", "n2", v1.PodRunning, req, "j3", nil, nil))
+
+	p1 := j1.Tasks["c1-p1"]
+	p2 := j1.Tasks["c1-p2"]
+	fitErrors := api.NewFitErrors()
+	fitErrors.SetNodeError("n1", newFitErr("p2", "n1", &api.Status{Code: api.Unschedulable, Reason: "Insufficient cpu"}))
+	j1.NodesFitErrors[p2.UID] = fitErrors
+
+	ssn := &Session{
+		UID:  "s1",
+		Jobs: map[api.JobID]*api.JobInfo{j1.UID: j1, j2.UID: j2, j3.UID: j3},
+	}
+	ssn.SetDecisionLog(NewDecisionLog(&bytes.Buffer{}))
+	ssn.RecordJobConsidered(j1, "allocate")
+	ssn.RecordJobSkipped(j3, "allocate", "queue is draining")
+	n1, n2 := &api.NodeInfo{Name: "n1"}, &api.NodeInfo{Name: "n2"}
+	ssn.RecordTaskCandidates(p1, []*api.NodeInfo{n2, n1}, map[float64][]*api.NodeInfo{10: {n1}, 5: {n2}})
+	ssn.recordOperation(p1, BindOperation, "n1", "")
+
+	now := time.Now()
+	records := ssn.decisionRecords(now)
+	for _, record := range records {
+		record.FitError = ""
+	}
+
+	expected := []*JobDecision{
+		{
+			Time:         now,
+			Session:      "s1",
+			Job:          "c1/j1",
+			Queue:        "q1",
+			MinAvailable: 2,
+			Considered:   []string{"allocate"},
+			Tasks: []*TaskDecision{
+				{
+					Task:       "p1",
+					Status:     "Bound",
+					Candidates: []string{"n1", "n2"},
+					Scores:     map[string]float64{"n1": 10, "n2": 5},
+					Operations: []*OperationDecision{{Operation: BindOperation, Node: "n1"}},
+				},
+				{
+					Task:      "p2",
+					Status:    "Pending",
+					FitErrors: map[string][]string{"n1": {"Insufficient cpu"}},
+				},
+			},
+		},
+		{
+			Time:         now,
+			Session:      "s1",
+			Job:          "c1/j3",
+			Queue:        "q1",
+			MinAvailable: 1,
+			Skipped:      []string{"allocate: queue is draining"},
+		},
+	}
+	assert.Equal(t, expected, records)
+}
+
+func TestDecisionLogWrite(t *testing.T) {
+	out := &bytes.Buffer{}
+	log := NewDecisionLog(out)
+	log.Write([]*JobDecision{
+		{Session: "s1", Job: "c1/j1", Queue: "q1"},
+		{Session: "s1", Job: "c1/j2", Queue: "q1"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	for i, job := range []string{"c1/j1", "c1/j2"} {
+		record := &JobDecision{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[i]), record))
+		assert.Equal(t, job, record.Job)
+	}
+}
+
+func TestDecisionLogRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	log := NewDecisionLog(out)
+	for i := 0; i < decisionLogQueueSize+1; i++ {
+		log.Enqueue([]*JobDecision{{Session: "s1", Job: "c1/j1", Queue: "q1"}})
+	}
+
+	// the records queued are written once stopped, the ones beyond the queue are dropped
+	stopCh := make(chan struct{})
+	close(stopCh)
+	log.Run(stopCh)
+	assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), decisionLogQueueSize)
+}
@@ -57,6 +57,9 @@ func CloseSession(ssn *Session) {
 		plugin.OnSessionClose(ssn)
 		metrics.UpdatePluginDuration(plugin.Name(), metrics.OnSessionClose, metrics.Duration(onSessionCloseStart))
 	}
+	ssn.writeDecisions()
+	ssn.trackUnschedulable()
+	ssn.updateUsageMetrics()
 
 	closeSession(ssn)
 }
@@ -5,12 +5,16 @@ import (
 	"math/rand"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	"volcano.sh/apis/pkg/apis/scheduling"
 	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+	"volcano.sh/volcano/pkg/util/tracing"
 )
 
 const (
@@ -95,8 +99,24 @@ func (ju *jobUpdater) updateJob(index int) {
 	job.PodGroup.Status = jobStatus(ssn, job)
 	oldStatus, found := ssn.podGroupStatus[job.UID]
 	updatePG := !found || isPodGroupStatusUpdated(job.PodGroup.Status, oldStatus)
+	now := time.Now()
+	latencies, timelineUpdated := api.RecordPhaseTimeline(job.PodGroup, oldStatus.Phase, now)
+	updatePG = updatePG || timelineUpdated
 	if _, err := ssn.cache.UpdateJobStatus(job, updatePG); err != nil {
 		klog.Errorf("Failed to update job <%s/%s>: %v",
 			job.Namespace, job.Name, err)
+		return
+	}
+	ctx := tracing.Extract(context.TODO(), job.PodGroup.Annotations)
+	attrs := trace.WithAttributes(attribute.String("namespace", job.Namespace), attribute.String("podgroup", job.Name),
+		attribute.String("queue", string(job.Queue)))
+	for key, latency := range latencies {
+		if key == api.PodGroupQueueingLatencyKey {
+			metrics.UpdatePodGroupQueueingLatency(string(job.Queue), latency)
+			tracing.RecordSpan(ctx, "EnqueuePodGroup", now.Add(-latency), now, attrs)
+		} else {
+			metrics.UpdatePodGroupSchedulingLatency(string(job.Queue), latency)
+			tracing.RecordSpan(ctx, "AllocatePodGroup", now.Add(-latency), now, attrs)
+		}
 	}
 }
@@ -18,6 +18,7 @@ package framework
 
 import (
 	"fmt"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -101,6 +102,15 @@ type Session struct {
 	reservedNodesFns  map[string]api.ReservedNodesFn
 	victimTasksFns    map[string][]api.VictimTasksFn
 	jobStarvingFns    map[string]api.ValidateFn
+
+	// preemptionVictims counts the tasks of each queue evicted by preempt and reclaim in the
+	// session, to keep within the preemption budget of the queue.
+	preemptionVictims map[api.QueueID]int32
+
+	// decisions collects the decisions taken in the session when a decision log is set.
+	decisions *sessionDecisions
+	// unschedulableTracker tracks the podgroups the session fails to schedule when it is set.
+	unschedulableTracker *UnschedulableTracker
 }
 
 func openSession(cache cache.Cache) *Session {
@@ -147,9 +157,11 @@ func openSession(cache cache.Cache) *Session {
 		reservedNodesFns:  map[string]api.ReservedNodesFn{},
 		victimTasksFns:    map[string][]api.VictimTasksFn{},
 		jobStarvingFns:    map[string]api.ValidateFn{},
+
+		preemptionVictims: map[api.QueueID]int32{},
 	}
 
-	snapshot := cache.Snapshot()
+	snapshot := cache.SessionSnapshot()
 
 	ssn.Jobs = snapshot.Jobs
 	for _, job := range ssn.Jobs {
@@ -405,6 +417,7 @@ func (ssn *Session) Pipeline(task *api.TaskInfo, hostname string) error {
 			})
 		}
 	}
+	ssn.recordOperation(task, PipelineOperation, hostname, "")
 
 	return nil
 }
@@ -502,14 +515,19 @@ func (ssn *Session) dispatch(task *api.TaskInfo) error {
 	}
 
 	metrics.UpdateTaskScheduleDuration(metrics.Duration(task.Pod.CreationTimestamp.Time))
+	ssn.recordOperation(task, BindOperation, task.NodeName, "")
 	return nil
 }
 
 // Evict the task in the session
 func (ssn *Session) Evict(reclaimee *api.TaskInfo, reason string) error {
+	if err := ssn.checkPreemptionBudget(reclaimee, reason); err != nil {
+		return err
+	}
 	if err := ssn.cache.Evict(reclaimee, reason); err != nil {
 		return err
 	}
+	ssn.countPreemptionVictim(reclaimee, reason, 1)
 
 	// Update status in session
 	job, found := ssn.Jobs[reclaimee.Job]
@@ -541,10 +559,73 @@ func (ssn *Session) Evict(reclaimee *api.TaskInfo, reason string) error {
 			})
 		}
 	}
+	ssn.recordOperation(reclaimee, EvictOperation, reclaimee.NodeName, reason)
 
 	return nil
 }
 
+func isPreemption(reason string) bool {
+	return reason == "preempt" || reason == "reclaim"
+}
+
+func (ssn *Session) taskQueue(task *api.TaskInfo) *api.QueueInfo {
+	job, found := ssn.Jobs[task.Job]
+	if !found {
+		return nil
+	}
+	return ssn.Queues[job.Queue]
+}
+
+// preemptionCandidates filters out the tasks which preempt and reclaim may not evict by the
+// preemption policy of their queues: the tasks which have not been running for the min runtime
+// of the queue, and the tasks of the queues which run out of preemption budget in the session.
+func (ssn *Session) preemptionCandidates(tasks []*api.TaskInfo) []*api.TaskInfo {
+	now := time.Now()
+	var candidates []*api.TaskInfo
+	for _, task := range tasks {
+		queue := ssn.taskQueue(task)
+		if queue != nil {
+			if queue.ProtectedFromPreemption(task, now) {
+				klog.V(4).Infof("Task <%s/%s> of queue <%s> is protected from preemption by min runtime %v.",
+					task.Namespace, task.Name, queue.Name, queue.PreemptionMinRuntime)
+				continue
+			}
+			if queue.MaxPreemptionVictims > 0 && ssn.preemptionVictims[queue.UID] >= queue.MaxPreemptionVictims {
+				klog.V(4).Infof("Task <%s/%s> is not preemptable: queue <%s> runs out of preemption budget %d.",
+					task.Namespace, task.Name, queue.Name, queue.MaxPreemptionVictims)
+				continue
+			}
+		}
+		candidates = append(candidates, task)
+	}
+	return candidates
+}
+
+// checkPreemptionBudget returns an error if the queue of the task runs out of preemption budget.
+func (ssn *Session) checkPreemptionBudget(task *api.TaskInfo, reason string) error {
+	if !isPreemption(reason) {
+		return nil
+	}
+	queue := ssn.taskQueue(task)
+	if queue == nil || queue.MaxPreemptionVictims <= 0 {
+		return nil
+	}
+	if ssn.preemptionVictims[queue.UID] >= queue.MaxPreemptionVictims {
+		return fmt.Errorf("queue <%s> runs out of preemption budget %d in session <%s>",
+			queue.Name, queue.MaxPreemptionVictims, ssn.UID)
+	}
+	return nil
+}
+
+func (ssn *Session) countPreemptionVictim(task *api.TaskInfo, reason string, delta int32) {
+	if !isPreemption(reason) {
+		return
+	}
+	if queue := ssn.taskQueue(task); queue != nil {
+		ssn.preemptionVictims[queue.UID] += delta
+	}
+}
+
 // BindPodGroup bind PodGroup to specified cluster
 func (ssn *Session) BindPodGroup(job *api.JobInfo, cluster string) error {
 	return ssn.cache.BindPodGroup(job, cluster)
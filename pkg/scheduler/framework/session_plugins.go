@@ -17,11 +17,14 @@ limitations under the License.
 package framework
 
 import (
+	"time"
+
 	k8sframework "k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"volcano.sh/apis/pkg/apis/scheduling"
 	"volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
 	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
@@ -155,6 +158,8 @@ func (ssn *Session) Reclaimable(reclaimer *api.TaskInfo, reclaimees []*api.TaskI
 	var victims []*api.TaskInfo
 	var init bool
 
+	reclaimees = ssn.preemptionCandidates(reclaimees)
+
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
 			if !isEnabled(plugin.EnabledReclaimable) {
@@ -205,6 +210,8 @@ func (ssn *Session) Preemptable(preemptor *api.TaskInfo, preemptees []*api.TaskI
 	var victims []*api.TaskInfo
 	var init bool
 
+	preemptees = ssn.preemptionCandidates(preemptees)
+
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
 			if !isEnabled(plugin.EnabledPreemptable) {
@@ -521,6 +528,15 @@ func (ssn *Session) ReservedNodes() {
 
 // JobOrderFn invoke joborder function of the plugins
 func (ssn *Session) JobOrderFn(l, r interface{}) bool {
+	// the job order policy of the queue goes before the job order of the plugins
+	if lv, rv := l.(*api.JobInfo), r.(*api.JobInfo); lv.Queue == rv.Queue {
+		if queue, found := ssn.Queues[lv.Queue]; found {
+			if j := queue.CompareJobs(lv, rv); j != 0 {
+				return j < 0
+			}
+		}
+	}
+
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
 			if !isEnabled(plugin.EnabledJobOrder) {
@@ -637,7 +653,9 @@ func (ssn *Session) PredicateFn(task *api.TaskInfo, node *api.NodeInfo) error {
 			if !found {
 				continue
 			}
+			start := time.Now()
 			err := pfn(task, node)
+			metrics.UpdatePluginExtensionDuration(plugin.Name, metrics.Predicate, metrics.Duration(start))
 			if err != nil {
 				return err
 			}
@@ -658,7 +676,9 @@ func (ssn *Session) PrePredicateFn(task *api.TaskInfo) error {
 			if !found {
 				continue
 			}
+			start := time.Now()
 			err := pfn(task)
+			metrics.UpdatePluginExtensionDuration(plugin.Name, metrics.PrePredicate, metrics.Duration(start))
 			if err != nil {
 				return err
 			}
@@ -699,7 +719,9 @@ func (ssn *Session) NodeOrderFn(task *api.TaskInfo, node *api.NodeInfo) (float64
 			if !found {
 				continue
 			}
+			start := time.Now()
 			score, err := pfn(task, node)
+			metrics.UpdatePluginExtensionDuration(plugin.Name, metrics.NodeOrder, metrics.Duration(start))
 			if err != nil {
 				return 0, err
 			}
@@ -721,7 +743,9 @@ func (ssn *Session) BatchNodeOrderFn(task *api.TaskInfo, nodes []*api.NodeInfo)
 			if !found {
 				continue
 			}
+			start := time.Now()
 			score, err := pfn(task, nodes)
+			metrics.UpdatePluginExtensionDuration(plugin.Name, metrics.BatchNodeOrder, metrics.Duration(start))
 			if err != nil {
 				return nil, err
 			}
@@ -747,14 +771,18 @@ func (ssn *Session) NodeOrderMapFn(task *api.TaskInfo, node *api.NodeInfo) (map[
 				continue
 			}
 			if pfn, found := ssn.nodeOrderFns[plugin.Name]; found {
+				start := time.Now()
 				score, err := pfn(task, node)
+				metrics.UpdatePluginExtensionDuration(plugin.Name, metrics.NodeOrder, metrics.Duration(start))
 				if err != nil {
 					return nodeScoreMap, priorityScore, err
 				}
 				priorityScore += score
 			}
 			if pfn, found := ssn.nodeMapFns[plugin.Name]; found {
+				start := time.Now()
 				score, err := pfn(task, node)
+				metrics.UpdatePluginExtensionDuration(plugin.Name, metrics.NodeMap, metrics.Duration(start))
 				if err != nil {
 					return nodeScoreMap, priorityScore, err
 				}
@@ -777,7 +805,10 @@ func (ssn *Session) NodeOrderReduceFn(task *api.TaskInfo, pluginNodeScoreMap map
 			if !found {
 				continue
 			}
-			if err := pfn(task, pluginNodeScoreMap[plugin.Name]); err != nil {
+			start := time.Now()
+			err := pfn(task, pluginNodeScoreMap[plugin.Name])
+			metrics.UpdatePluginExtensionDuration(plugin.Name, metrics.NodeReduce, metrics.Duration(start))
+			if err != nil {
 				return nodeScoreMap, err
 			}
 			for _, hp := range pluginNodeScoreMap[plugin.Name] {
@@ -796,6 +827,9 @@ func (ssn *Session) BuildVictimsPriorityQueue(victims []*api.TaskInfo) *util.Pri
 		lv := l.(*api.TaskInfo)
 		rv := r.(*api.TaskInfo)
 		if lv.Job == rv.Job {
+			if less, decided := api.CompareVictimCost(lv, rv); decided {
+				return less
+			}
 			return !ssn.TaskOrderFn(l, r)
 		}
 
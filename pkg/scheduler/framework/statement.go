@@ -58,6 +58,11 @@ func NewStatement(ssn *Session) *Statement {
 
 // Evict the pod
 func (s *Statement) Evict(reclaimee *api.TaskInfo, reason string) error {
+	if err := s.ssn.checkPreemptionBudget(reclaimee, reason); err != nil {
+		return err
+	}
+	s.ssn.countPreemptionVictim(reclaimee, reason, 1)
+
 	// Update status in session
 	if job, found := s.ssn.Jobs[reclaimee.Job]; found {
 		if err := job.UpdateTaskStatus(reclaimee, api.Releasing); err != nil {
@@ -98,7 +103,7 @@ func (s *Statement) Evict(reclaimee *api.TaskInfo, reason string) error {
 
 func (s *Statement) evict(reclaimee *api.TaskInfo, reason string) error {
 	if err := s.ssn.cache.Evict(reclaimee, reason); err != nil {
-		if e := s.unevict(reclaimee); e != nil {
+		if e := s.unevict(reclaimee, reason); e != nil {
 			klog.Errorf("Faled to unevict task <%v/%v>: %v.", reclaimee.Namespace, reclaimee.Name, e)
 		}
 		return err
@@ -107,7 +112,9 @@ func (s *Statement) evict(reclaimee *api.TaskInfo, reason string) error {
 	return nil
 }
 
-func (s *Statement) unevict(reclaimee *api.TaskInfo) error {
+func (s *Statement) unevict(reclaimee *api.TaskInfo, reason string) error {
+	s.ssn.countPreemptionVictim(reclaimee, reason, -1)
+
 	// Update status in session
 	job, found := s.ssn.Jobs[reclaimee.Job]
 	if found {
@@ -356,7 +363,7 @@ func (s *Statement) Discard() {
 		op.task.GenerateLastTxContext()
 		switch op.name {
 		case Evict:
-			err := s.unevict(op.task)
+			err := s.unevict(op.task, op.reason)
 			if err != nil {
 				klog.Errorf("Failed to unevict task: %s", err.Error())
 			}
@@ -384,9 +391,12 @@ func (s *Statement) Commit() {
 			err := s.evict(op.task, op.reason)
 			if err != nil {
 				klog.Errorf("Failed to evict task: %s", err.Error())
+			} else {
+				s.ssn.recordOperation(op.task, EvictOperation, op.task.NodeName, op.reason)
 			}
 		case Pipeline:
 			s.pipeline(op.task)
+			s.ssn.recordOperation(op.task, PipelineOperation, op.task.NodeName, "")
 		case Allocate:
 			err := s.allocate(op.task)
 			if err != nil {
@@ -394,6 +404,8 @@ func (s *Statement) Commit() {
 					klog.Errorf("Failed to unallocate task <%v/%v>: %v.", op.task.Namespace, op.task.Name, e)
 				}
 				klog.Errorf("Failed to allocate task <%v/%v>: %v.", op.task.Namespace, op.task.Name, err)
+			} else {
+				s.ssn.recordOperation(op.task, BindOperation, op.task.NodeName, "")
 			}
 		}
 	}
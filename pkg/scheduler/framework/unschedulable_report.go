@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// maxReportedPodGroups is the most podgroups listed in the report, which keeps the ConfigMap small.
+	maxReportedPodGroups = 200
+	// maxReportedMessageLength is the longest message of a podgroup in the report.
+	maxReportedMessageLength = 256
+)
+
+// UnschedulableTracker tracks the podgroups the sessions fail to schedule and since when, so that the ones
+// unschedulable for long can be published in a report instead of being mined from the logs.
+type UnschedulableTracker struct {
+	mutex     sync.Mutex
+	podGroups map[api.JobID]*api.UnschedulablePodGroup
+}
+
+// NewUnschedulableTracker returns an UnschedulableTracker tracking no podgroup
+func NewUnschedulableTracker() *UnschedulableTracker {
+	return &UnschedulableTracker{podGroups: map[api.JobID]*api.UnschedulablePodGroup{}}
+}
+
+// SetUnschedulableTracker makes the session track the podgroups it fails to schedule into tracker when it is closed
+func (ssn *Session) SetUnschedulableTracker(tracker *UnschedulableTracker) {
+	ssn.unschedulableTracker = tracker
+}
+
+// trackUnschedulable updates the tracker of the session, if any, with the podgroups the session fails to schedule
+func (ssn *Session) trackUnschedulable() {
+	if ssn.unschedulableTracker == nil {
+		return
+	}
+	ssn.unschedulableTracker.observe(ssn.UID, ssn.Jobs, time.Now())
+}
+
+// observe replaces the podgroups tracked with the ones given the Unschedulable condition in the session,
+// keeping the time the ones already tracked are unschedulable since.
+func (t *UnschedulableTracker) observe(session types.UID, jobs map[api.JobID]*api.JobInfo, now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	podGroups := map[api.JobID]*api.UnschedulablePodGroup{}
+	for _, job := range jobs {
+		condition := unschedulableCondition(job.PodGroup, session)
+		if condition == nil {
+			continue
+		}
+		pg, found := t.podGroups[job.UID]
+		if !found {
+			pg = &api.UnschedulablePodGroup{
+				Namespace: job.Namespace,
+				Name:      job.Name,
+				Since:     unschedulableSince(job.PodGroup, now),
+			}
+		}
+		pg.Queue = string(job.Queue)
+		pg.Reason = condition.Reason
+		pg.Message = condition.Message
+		if len(pg.Message) > maxReportedMessageLength {
+			pg.Message = pg.Message[:maxReportedMessageLength] + "..."
+		}
+		pg.MinAvailable = job.MinAvailable
+		pg.PendingTasks = len(job.TaskStatusIndex[api.Pending])
+		podGroups[job.UID] = pg
+	}
+	t.podGroups = podGroups
+}
+
+// unschedulableCondition returns the Unschedulable condition the podgroup is given in the session, nil if none
+func unschedulableCondition(pg *api.PodGroup, session types.UID) *scheduling.PodGroupCondition {
+	if pg == nil {
+		return nil
+	}
+	for i, c := range pg.Status.Conditions {
+		if c.Type == scheduling.PodGroupUnschedulableType && c.Status == v1.ConditionTrue && c.TransitionID == string(session) {
+			return &pg.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// unschedulableSince returns the time a podgroup first seen unschedulable is unschedulable since: the time it is
+// pending since if it never ran, which survives the restarts of the scheduler, or now.
+func unschedulableSince(pg *api.PodGroup, now time.Time) time.Time {
+	if _, found := pg.Annotations[api.PodGroupRunningTimeKey]; found {
+		return now
+	}
+	if pending, err := time.Parse(time.RFC3339, pg.Annotations[api.PodGroupPendingTimeKey]); err == nil {
+		return pending
+	}
+	if !pg.CreationTimestamp.IsZero() {
+		return pg.CreationTimestamp.Time
+	}
+	return now
+}
+
+// Report returns the report of the podgroups unschedulable for longer than threshold at now.
+func (t *UnschedulableTracker) Report(threshold time.Duration, now time.Time) *api.UnschedulableReport {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	report := &api.UnschedulableReport{
+		Time:      now,
+		Threshold: threshold.String(),
+		Reasons:   map[string]int{},
+	}
+	for _, pg := range t.podGroups {
+		if now.Sub(pg.Since) < threshold {
+			continue
+		}
+		report.Reasons[pg.Reason]++
+		report.PodGroups = append(report.PodGroups, *pg)
+	}
+	report.Total = len(report.PodGroups)
+	sort.Slice(report.PodGroups, func(i, j int) bool {
+		pi, pj := report.PodGroups[i], report.PodGroups[j]
+		if !pi.Since.Equal(pj.Since) {
+			return pi.Since.Before(pj.Since)
+		}
+		if pi.Namespace != pj.Namespace {
+			return pi.Namespace < pj.Namespace
+		}
+		return pi.Name < pj.Name
+	})
+	if len(report.PodGroups) > maxReportedPodGroups {
+		report.PodGroups = report.PodGroups[:maxReportedPodGroups]
+	}
+	return report
+}
+
+// Publish writes the report of the podgroups unschedulable for longer than threshold into the ConfigMap
+// UnschedulableReportConfigMap of namespace, creating it if it does not exist.
+func (t *UnschedulableTracker) Publish(ctx context.Context, client kubernetes.Interface, namespace string, threshold time.Duration) error {
+	data, err := json.Marshal(t.Report(threshold, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, api.UnschedulableReportConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: api.UnschedulableReportConfigMap},
+			Data:       map[string]string{api.UnschedulableReportKey: string(data)},
+		}
+		_, err = client.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	cm = cm.DeepCopy()
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[api.UnschedulableReportKey] = string(data)
+	_, err = client.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func newUnschedulableJob(name string, annotations map[string]string, conditions ...scheduling.PodGroupCondition) *api.JobInfo {
+	req := api.BuildResourceList("1", "1G")
+	job := newDecisionJob(name, util.BuildPod("c1", name+"-p1", "", v1.PodPending, req, name, nil, nil))
+	job.PodGroup = &api.PodGroup{PodGroup: scheduling.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "c1", Name: name, Annotations: annotations},
+		Status:     scheduling.PodGroupStatus{Conditions: conditions},
+	}}
+	return job
+}
+
+func unschedulable(session, reason string) scheduling.PodGroupCondition {
+	return scheduling.PodGroupCondition{
+		Type:         scheduling.PodGroupUnschedulableType,
+		Status:       v1.ConditionTrue,
+		TransitionID: session,
+		Reason:       reason,
+		Message:      reason + " message",
+	}
+}
+
+func TestUnschedulableTrackerReport(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pending := now.Add(-time.Hour)
+
+	// j1 never ran, it is unschedulable since it is pending
+	j1 := newUnschedulableJob("j1", map[string]string{api.PodGroupPendingTimeKey: pending.Format(time.RFC3339)},
+		unschedulable("s1", api.NodeResourcesInsufficientReason))
+	// j2 ran before, it is unschedulable since it is first seen
+	j2 := newUnschedulableJob("j2", map[string]string{api.PodGroupRunningTimeKey: pending.Format(time.RFC3339)},
+		unschedulable("s1", api.QueueQuotaInsufficientReason))
+	// j3 is given the condition in an earlier session
+	j3 := newUnschedulableJob("j3", nil, unschedulable("s0", api.NodeTaintsUntoleratedReason))
+
+	tracker := NewUnschedulableTracker()
+	tracker.observe("s1", map[api.JobID]*api.JobInfo{j1.UID: j1, j2.UID: j2, j3.UID: j3}, now)
+
+	report := tracker.Report(30*time.Minute, now)
+	assert.Equal(t, 1, report.Total)
+	assert.Equal(t, map[string]int{api.NodeResourcesInsufficientReason: 1}, report.Reasons)
+	assert.Equal(t, []api.UnschedulablePodGroup{{
+		Namespace:    "c1",
+		Name:         "j1",
+		Queue:        "q1",
+		Since:        pending,
+		Reason:       api.NodeResourcesInsufficientReason,
+		Message:      api.NodeResourcesInsufficientReason + " message",
+		MinAvailable: 1,
+		PendingTasks: 1,
+	}}, report.PodGroups)
+
+	// j2 keeps the time it is unschedulable since, j1 is scheduled and is not tracked anymore
+	later := now.Add(time.Hour)
+	j2.PodGroup.Status.Conditions = []scheduling.PodGroupCondition{unschedulable("s2", api.NodeAffinityMismatchReason)}
+	tracker.observe("s2", map[api.JobID]*api.JobInfo{j1.UID: j1, j2.UID: j2}, later)
+
+	report = tracker.Report(30*time.Minute, later)
+	assert.Equal(t, 1, report.Total)
+	assert.Equal(t, "j2", report.PodGroups[0].Name)
+	assert.Equal(t, now, report.PodGroups[0].Since)
+	assert.Equal(t, api.NodeAffinityMismatchReason, report.PodGroups[0].Reason)
+}
+
+func TestUnschedulableTrackerPublish(t *testing.T) {
+	j1 := newUnschedulableJob("j1", nil, unschedulable("s1", api.NodeResourcesInsufficientReason))
+	tracker := NewUnschedulableTracker()
+	tracker.observe("s1", map[api.JobID]*api.JobInfo{j1.UID: j1}, time.Now())
+	client := fake.NewSimpleClientset()
+
+	for _, expected := range []int{1, 0} {
+		assert.NoError(t, tracker.Publish(context.TODO(), client, "volcano-system", 0))
+
+		cm, err := client.CoreV1().ConfigMaps("volcano-system").Get(context.TODO(), api.UnschedulableReportConfigMap, metav1.GetOptions{})
+		assert.NoError(t, err)
+		report, err := api.ParseUnschedulableReport(cm.Data)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, report.Total)
+
+		// the podgroup is scheduled in the next session
+		tracker.observe("s2", map[api.JobID]*api.JobInfo{j1.UID: j1}, time.Now())
+	}
+}
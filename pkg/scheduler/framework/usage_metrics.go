@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sort"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+// updateUsageMetrics records the capability of the queues and their usage by the namespaces, whichever
+// plugins are enabled, so that the capacity of the queues and the starved namespaces are watched.
+func (ssn *Session) updateUsageMetrics() {
+	for _, queue := range ssn.Queues {
+		if queue.Queue != nil {
+			metrics.UpdateQueueCapability(queue.Name, queue.Queue.Spec.Capability)
+		}
+	}
+	metrics.UpdateQueueNamespaceUsage(queueNamespaceUsage(ssn.Jobs))
+}
+
+// queueNamespaceUsage sums the allocated resources and the pending podgroups of the jobs
+// per queue and namespace, ordered by the queue and the namespace.
+func queueNamespaceUsage(jobs map[api.JobID]*api.JobInfo) []metrics.QueueNamespaceUsage {
+	type key struct {
+		queue     string
+		namespace string
+	}
+	allocated := map[key]*api.Resource{}
+	pending := map[key]int{}
+	queueAllocated := map[string]*api.Resource{}
+
+	for _, job := range jobs {
+		if job.PodGroup == nil {
+			continue
+		}
+		k := key{queue: string(job.Queue), namespace: job.Namespace}
+		if allocated[k] == nil {
+			allocated[k] = api.EmptyResource()
+		}
+		if queueAllocated[k.queue] == nil {
+			queueAllocated[k.queue] = api.EmptyResource()
+		}
+		if job.Allocated != nil {
+			allocated[k].Add(job.Allocated)
+			queueAllocated[k.queue].Add(job.Allocated)
+		}
+		if job.PodGroup.Status.Phase == scheduling.PodGroupPending || job.PodGroup.Status.Phase == "" {
+			pending[k]++
+		}
+	}
+
+	usages := make([]metrics.QueueNamespaceUsage, 0, len(allocated))
+	for k, resource := range allocated {
+		usages = append(usages, metrics.QueueNamespaceUsage{
+			Queue:            k.queue,
+			Namespace:        k.namespace,
+			MilliCPU:         resource.MilliCPU,
+			Memory:           resource.Memory,
+			ScalarResources:  resource.ScalarResources,
+			PendingPodGroups: pending[k],
+			Share:            dominantShare(resource, queueAllocated[k.queue]),
+		})
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Queue != usages[j].Queue {
+			return usages[i].Queue < usages[j].Queue
+		}
+		return usages[i].Namespace < usages[j].Namespace
+	})
+	return usages
+}
+
+// dominantShare is the largest share of a resource of total held by allocated.
+func dominantShare(allocated, total *api.Resource) float64 {
+	share := 0.0
+	for _, name := range total.ResourceNames() {
+		if s := allocated.Get(name) / total.Get(name); s > share {
+			share = s
+		}
+	}
+	return share
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func newUsageJob(queue, namespace string, phase scheduling.PodGroupPhase, allocated *api.Resource) *api.JobInfo {
+	job := &api.JobInfo{Queue: api.QueueID(queue), Namespace: namespace, Allocated: allocated}
+	job.PodGroup = &api.PodGroup{PodGroup: scheduling.PodGroup{Status: scheduling.PodGroupStatus{Phase: phase}}}
+	return job
+}
+
+func TestQueueNamespaceUsage(t *testing.T) {
+	gpu := v1.ResourceName("nvidia.com/gpu")
+	jobs := map[api.JobID]*api.JobInfo{
+		"ns1/j1": newUsageJob("q1", "ns1", scheduling.PodGroupRunning,
+			&api.Resource{MilliCPU: 3000, Memory: 1024, ScalarResources: map[v1.ResourceName]float64{gpu: 1000}}),
+		"ns1/j2": newUsageJob("q1", "ns1", scheduling.PodGroupPending, api.EmptyResource()),
+		"ns2/j1": newUsageJob("q1", "ns2", scheduling.PodGroupRunning,
+			&api.Resource{MilliCPU: 1000, Memory: 3072, ScalarResources: map[v1.ResourceName]float64{gpu: 3000}}),
+		"ns2/j2": newUsageJob("q2", "ns2", scheduling.PodGroupPending, api.EmptyResource()),
+		"ns2/j3": newUsageJob("q2", "ns2", scheduling.PodGroupInqueue, api.EmptyResource()),
+		"ns3/j1": {Queue: "q2", Namespace: "ns3"},
+	}
+
+	usages := queueNamespaceUsage(jobs)
+	if !assert.Len(t, usages, 3) {
+		return
+	}
+
+	assert.Equal(t, "q1", usages[0].Queue)
+	assert.Equal(t, "ns1", usages[0].Namespace)
+	assert.Equal(t, 3000.0, usages[0].MilliCPU)
+	assert.Equal(t, 1, usages[0].PendingPodGroups)
+	assert.Equal(t, 0.75, usages[0].Share)
+
+	assert.Equal(t, "q1", usages[1].Queue)
+	assert.Equal(t, "ns2", usages[1].Namespace)
+	assert.Equal(t, 3000.0, usages[1].ScalarResources[gpu])
+	assert.Equal(t, 0, usages[1].PendingPodGroups)
+	assert.Equal(t, 0.75, usages[1].Share)
+
+	assert.Equal(t, "q2", usages[2].Queue)
+	assert.Equal(t, "ns2", usages[2].Namespace)
+	assert.Equal(t, 1, usages[2].PendingPodGroups)
+	assert.Equal(t, 0.0, usages[2].Share)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
 )
@@ -37,6 +39,24 @@ var (
 			Help:      "Number of retry counts for one job",
 		}, []string{"job_id"},
 	)
+
+	podGroupQueueingLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "podgroup_queueing_latency_seconds",
+			Help:      "Latency of podgroups from Pending to Inqueue in seconds",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 16),
+		}, []string{"queue_name"},
+	)
+
+	podGroupSchedulingLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "podgroup_scheduling_latency_seconds",
+			Help:      "Latency of podgroups from Inqueue to Running in seconds",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 16),
+		}, []string{"queue_name"},
+	)
 )
 
 // UpdateJobShare records share for one job
@@ -49,6 +69,16 @@ func RegisterJobRetries(jobID string) {
 	jobRetryCount.WithLabelValues(jobID).Inc()
 }
 
+// UpdatePodGroupQueueingLatency records the latency of a podgroup of the queue from Pending to Inqueue
+func UpdatePodGroupQueueingLatency(queueName string, latency time.Duration) {
+	podGroupQueueingLatency.WithLabelValues(queueName).Observe(latency.Seconds())
+}
+
+// UpdatePodGroupSchedulingLatency records the latency of a podgroup of the queue from Inqueue to Running
+func UpdatePodGroupSchedulingLatency(queueName string, latency time.Duration) {
+	podGroupSchedulingLatency.WithLabelValues(queueName).Observe(latency.Seconds())
+}
+
 // DeleteJobMetrics delete all metrics related to the job
 func DeleteJobMetrics(jobName, queue, namespace string) {
 	e2eJobSchedulingDuration.DeleteLabelValues(jobName, queue, namespace)
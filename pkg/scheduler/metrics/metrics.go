@@ -32,6 +32,24 @@ const (
 
 	// OnSessionClose label
 	OnSessionClose = "OnSessionClose"
+
+	// PrePredicate extension point label
+	PrePredicate = "PrePredicate"
+
+	// Predicate extension point label
+	Predicate = "Predicate"
+
+	// NodeOrder extension point label
+	NodeOrder = "NodeOrder"
+
+	// BatchNodeOrder extension point label
+	BatchNodeOrder = "BatchNodeOrder"
+
+	// NodeMap extension point label
+	NodeMap = "NodeMap"
+
+	// NodeReduce extension point label
+	NodeReduce = "NodeReduce"
 )
 
 var (
@@ -89,6 +107,15 @@ var (
 		}, []string{"plugin", "OnSession"},
 	)
 
+	pluginExtensionLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "plugin_extension_latency_microseconds",
+			Help:      "Latency of a plugin's predicate and node order functions in microseconds",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 18),
+		}, []string{"plugin", "extension_point"},
+	)
+
 	actionSchedulingLatency = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Subsystem: VolcanoNamespace,
@@ -139,6 +166,39 @@ var (
 		}, []string{"job_id"},
 	)
 
+	unschedulablePodGroups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "unschedulable_podgroups_total",
+			Help:      "Number of times a podgroup was found unschedulable at session close, by reason",
+		}, []string{"reason"},
+	)
+
+	snapshotLatency = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "snapshot_latency_milliseconds",
+			Help:      "Snapshot latency of the scheduler cache in milliseconds",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+		},
+	)
+
+	snapshotNodes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "snapshot_nodes_total",
+			Help:      "Number of nodes given to the sessions by incremental snapshots, cloned or reused from the previous session",
+		}, []string{"result"},
+	)
+
+	predicateEquivalenceCache = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "predicate_equivalence_cache_total",
+			Help:      "Number of predicate results of the tasks on the nodes looked up in the equivalence cache, hit or missed",
+		}, []string{"result"},
+	)
+
 	unscheduleJobCount = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Subsystem: VolcanoNamespace,
@@ -153,6 +213,12 @@ func UpdatePluginDuration(pluginName, onSessionStatus string, duration time.Dura
 	pluginSchedulingLatency.WithLabelValues(pluginName, onSessionStatus).Observe(DurationInMilliseconds(duration))
 }
 
+// UpdatePluginExtensionDuration updates latency of a plugin function called
+// at the given extension point, such as Predicate or NodeOrder
+func UpdatePluginExtensionDuration(pluginName, extensionPoint string, duration time.Duration) {
+	pluginExtensionLatency.WithLabelValues(pluginName, extensionPoint).Observe(DurationInMicroseconds(duration))
+}
+
 // UpdateActionDuration updates latency for every action
 func UpdateActionDuration(actionName string, duration time.Duration) {
 	actionSchedulingLatency.WithLabelValues(actionName).Observe(DurationInMilliseconds(duration))
@@ -204,11 +270,40 @@ func UpdateUnscheduleTaskCount(jobID string, taskCount int) {
 	unscheduleTaskCount.WithLabelValues(jobID).Set(float64(taskCount))
 }
 
+// RegisterUnschedulablePodGroup records a podgroup found unschedulable for the given reason
+func RegisterUnschedulablePodGroup(reason string) {
+	unschedulablePodGroups.WithLabelValues(reason).Inc()
+}
+
 // UpdateUnscheduleJobCount records total number of unscheduleable jobs
 func UpdateUnscheduleJobCount(jobCount int) {
 	unscheduleJobCount.Set(float64(jobCount))
 }
 
+// UpdateSnapshotDuration updates the latency of the snapshot of the scheduler cache
+func UpdateSnapshotDuration(duration time.Duration) {
+	snapshotLatency.Observe(DurationInMilliseconds(duration))
+}
+
+// RegisterSnapshotNode records a node given to a session by an incremental snapshot, reused or cloned
+func RegisterSnapshotNode(reused bool) {
+	result := "cloned"
+	if reused {
+		result = "reused"
+	}
+	snapshotNodes.WithLabelValues(result).Inc()
+}
+
+// UpdatePredicateEquivalenceCache records the predicate results found and not found in the equivalence cache
+func UpdatePredicateEquivalenceCache(hits, misses int) {
+	if hits > 0 {
+		predicateEquivalenceCache.WithLabelValues("hit").Add(float64(hits))
+	}
+	if misses > 0 {
+		predicateEquivalenceCache.WithLabelValues("miss").Add(float64(misses))
+	}
+}
+
 // DurationInMicroseconds gets the time in microseconds.
 func DurationInMicroseconds(duration time.Duration) float64 {
 	return float64(duration.Nanoseconds()) / float64(time.Microsecond.Nanoseconds())
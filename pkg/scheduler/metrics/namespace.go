@@ -17,8 +17,11 @@ limitations under the License.
 package metrics
 
 import (
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
+	v1 "k8s.io/api/core/v1"
 )
 
 var (
@@ -45,8 +48,74 @@ var (
 			Help:      "Weighted share for one namespace",
 		}, []string{"namespace_name"},
 	)
+
+	queueNamespaceAllocatedMilliCPU = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_namespace_allocated_milli_cpu",
+			Help:      "Allocated CPU count for the jobs of one namespace in one queue",
+		}, []string{"queue_name", "namespace_name"},
+	)
+
+	queueNamespaceAllocatedMemory = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_namespace_allocated_memory_bytes",
+			Help:      "Allocated memory for the jobs of one namespace in one queue",
+		}, []string{"queue_name", "namespace_name"},
+	)
+
+	queueNamespaceAllocatedScalar = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_namespace_allocated_scalar_resources",
+			Help:      "Allocated scalar resources for the jobs of one namespace in one queue",
+		}, []string{"queue_name", "namespace_name", "resource"},
+	)
+
+	queueNamespacePodGroupPending = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_namespace_pod_group_pending_count",
+			Help:      "The number of Pending PodGroup of one namespace in one queue",
+		}, []string{"queue_name", "namespace_name"},
+	)
+
+	queueNamespaceShare = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_namespace_share",
+			Help:      "Dominant share of the resources allocated in one queue held by the jobs of one namespace",
+		}, []string{"queue_name", "namespace_name"},
+	)
+
+	// queueNamespaces are the queues and namespaces of the last UpdateQueueNamespaceUsage,
+	// whose series are deleted once the namespace has no job in the queue.
+	queueNamespaces     = map[queueNamespace]struct{}{}
+	queueNamespacesLock sync.Mutex
 )
 
+type queueNamespace struct {
+	queue     string
+	namespace string
+}
+
+// QueueNamespaceUsage is the usage of one queue by the jobs of one namespace.
+type QueueNamespaceUsage struct {
+	Queue     string
+	Namespace string
+
+	// MilliCPU, Memory and ScalarResources are the resources allocated to the jobs,
+	// the scalar resources in milli units as the scheduler accounts them.
+	MilliCPU        float64
+	Memory          float64
+	ScalarResources map[v1.ResourceName]float64
+
+	PendingPodGroups int
+	// Share is the dominant share of the resources allocated in the queue held by the jobs.
+	Share float64
+}
+
 // UpdateNamespaceShare records share for one namespace
 func UpdateNamespaceShare(namespaceName string, share float64) {
 	namespaceShare.WithLabelValues(namespaceName).Set(share)
@@ -61,3 +130,56 @@ func UpdateNamespaceWeight(namespaceName string, weight int64) {
 func UpdateNamespaceWeightedShare(namespaceName string, weightedShare float64) {
 	namespaceWeightedShare.WithLabelValues(namespaceName).Set(weightedShare)
 }
+
+// UpdateQueueNamespaceUsage records the usage of the queues by the namespaces; the series of the
+// namespaces which no longer have jobs in a queue are deleted.
+func UpdateQueueNamespaceUsage(usages []QueueNamespaceUsage) {
+	queueNamespacesLock.Lock()
+	defer queueNamespacesLock.Unlock()
+
+	current := make(map[queueNamespace]struct{}, len(usages))
+	for _, usage := range usages {
+		key := queueNamespace{queue: usage.Queue, namespace: usage.Namespace}
+		current[key] = struct{}{}
+
+		queueNamespaceAllocatedMilliCPU.WithLabelValues(usage.Queue, usage.Namespace).Set(usage.MilliCPU)
+		queueNamespaceAllocatedMemory.WithLabelValues(usage.Queue, usage.Namespace).Set(usage.Memory)
+		queueNamespaceAllocatedScalar.DeletePartialMatch(prometheus.Labels{"queue_name": usage.Queue, "namespace_name": usage.Namespace})
+		for name, value := range usage.ScalarResources {
+			if name == v1.ResourcePods {
+				continue
+			}
+			queueNamespaceAllocatedScalar.WithLabelValues(usage.Queue, usage.Namespace, string(name)).Set(value / 1000)
+		}
+		queueNamespacePodGroupPending.WithLabelValues(usage.Queue, usage.Namespace).Set(float64(usage.PendingPodGroups))
+		queueNamespaceShare.WithLabelValues(usage.Queue, usage.Namespace).Set(usage.Share)
+	}
+
+	for key := range queueNamespaces {
+		if _, found := current[key]; !found {
+			deleteQueueNamespaceSeries(prometheus.Labels{"queue_name": key.queue, "namespace_name": key.namespace})
+		}
+	}
+	queueNamespaces = current
+}
+
+// deleteQueueNamespaceUsage deletes the usage of the queue by all the namespaces.
+func deleteQueueNamespaceUsage(queueName string) {
+	queueNamespacesLock.Lock()
+	defer queueNamespacesLock.Unlock()
+
+	for key := range queueNamespaces {
+		if key.queue == queueName {
+			delete(queueNamespaces, key)
+		}
+	}
+	deleteQueueNamespaceSeries(prometheus.Labels{"queue_name": queueName})
+}
+
+func deleteQueueNamespaceSeries(labels prometheus.Labels) {
+	queueNamespaceAllocatedMilliCPU.DeletePartialMatch(labels)
+	queueNamespaceAllocatedMemory.DeletePartialMatch(labels)
+	queueNamespaceAllocatedScalar.DeletePartialMatch(labels)
+	queueNamespacePodGroupPending.DeletePartialMatch(labels)
+	queueNamespaceShare.DeletePartialMatch(labels)
+}
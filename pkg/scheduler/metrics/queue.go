@@ -17,8 +17,11 @@ limitations under the License.
 package metrics
 
 import (
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
+	v1 "k8s.io/api/core/v1"
 )
 
 var (
@@ -70,6 +73,30 @@ var (
 		}, []string{"queue_name"},
 	)
 
+	queueAllocatedScalar = newScalarGaugeVec(promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_allocated_scalar_resources",
+			Help:      "Allocated scalar resources, e.g. GPUs or hugepages, for one queue",
+		}, []string{"queue_name", "resource"},
+	))
+
+	queueRequestScalar = newScalarGaugeVec(promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_request_scalar_resources",
+			Help:      "Requested scalar resources, e.g. GPUs or hugepages, for one queue",
+		}, []string{"queue_name", "resource"},
+	))
+
+	queueDeservedScalar = newScalarGaugeVec(promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_deserved_scalar_resources",
+			Help:      "Deserved scalar resources, e.g. GPUs or hugepages, for one queue",
+		}, []string{"queue_name", "resource"},
+	))
+
 	queueShare = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Subsystem: VolcanoNamespace,
@@ -86,6 +113,22 @@ var (
 		}, []string{"queue_name"},
 	)
 
+	queueBorrowedMilliCPU = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_borrowed_milli_cpu",
+			Help:      "CPU allocated beyond the deserved resource of one queue",
+		}, []string{"queue_name"},
+	)
+
+	queueBorrowedMemory = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_borrowed_memory_bytes",
+			Help:      "Memory allocated beyond the deserved resource of one queue",
+		}, []string{"queue_name"},
+	)
+
 	queueOverused = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Subsystem: VolcanoNamespace,
@@ -125,6 +168,34 @@ var (
 			Help:      "The number of Unknown PodGroup in this queue",
 		}, []string{"queue_name"},
 	)
+
+	queueCapabilityMilliCPU = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_capability_milli_cpu",
+			Help:      "Capability CPU count for one queue, absent if the queue is not limited",
+		}, []string{"queue_name"},
+	)
+
+	queueCapabilityMemory = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_capability_memory_bytes",
+			Help:      "Capability memory for one queue, absent if the queue is not limited",
+		}, []string{"queue_name"},
+	)
+
+	queueCapabilityScalar = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_capability_scalar_resources",
+			Help:      "Capability scalar resources for one queue",
+		}, []string{"queue_name", "resource"},
+	)
+
+	// queueShares are the last shares of the queues, served by the cache dump.
+	queueShares     = map[string]float64{}
+	queueSharesLock sync.RWMutex
 )
 
 // UpdateQueueAllocated records allocated resources for one queue
@@ -145,9 +216,110 @@ func UpdateQueueDeserved(queueName string, milliCPU, memory float64) {
 	queueDeservedMemory.WithLabelValues(queueName).Set(memory)
 }
 
+// UpdateQueueAllocatedScalar records allocated scalar resources for one queue
+func UpdateQueueAllocatedScalar(queueName string, scalars map[v1.ResourceName]float64) {
+	queueAllocatedScalar.update(queueName, scalars)
+}
+
+// UpdateQueueRequestScalar records request scalar resources for one queue
+func UpdateQueueRequestScalar(queueName string, scalars map[v1.ResourceName]float64) {
+	queueRequestScalar.update(queueName, scalars)
+}
+
+// UpdateQueueDeservedScalar records deserved scalar resources for one queue
+func UpdateQueueDeservedScalar(queueName string, scalars map[v1.ResourceName]float64) {
+	queueDeservedScalar.update(queueName, scalars)
+}
+
+// scalarGaugeVec is a gauge of the scalar resources of the queues which remembers the resources recorded
+// for each queue, so that their series are deleted by their labels; it is updated on each allocation,
+// where scanning all the series of the gauge is too costly.
+type scalarGaugeVec struct {
+	*prometheus.GaugeVec
+
+	mutex sync.Mutex
+	// recorded are the names of the scalar resources recorded, by queue.
+	recorded map[string]map[string]struct{}
+}
+
+func newScalarGaugeVec(gauge *prometheus.GaugeVec) *scalarGaugeVec {
+	return &scalarGaugeVec{GaugeVec: gauge, recorded: map[string]map[string]struct{}{}}
+}
+
+// update records the scalar resources, which the scheduler accounts in milli units, in the units of
+// the resources. The series of the scalar resources no longer accounted for are deleted.
+func (g *scalarGaugeVec) update(queueName string, scalars map[v1.ResourceName]float64) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	names := make(map[string]struct{}, len(scalars))
+	for name, value := range scalars {
+		if name == v1.ResourcePods {
+			continue
+		}
+		names[string(name)] = struct{}{}
+		g.WithLabelValues(queueName, string(name)).Set(value / 1000)
+	}
+	for name := range g.recorded[queueName] {
+		if _, found := names[name]; !found {
+			g.DeleteLabelValues(queueName, name)
+		}
+	}
+	g.recorded[queueName] = names
+}
+
+// delete deletes the series of the scalar resources recorded for the queue.
+func (g *scalarGaugeVec) delete(queueName string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for name := range g.recorded[queueName] {
+		g.DeleteLabelValues(queueName, name)
+	}
+	delete(g.recorded, queueName)
+}
+
+// UpdateQueueCapability records the capability of one queue; a resource the queue is not
+// limited in has no series.
+func UpdateQueueCapability(queueName string, capability v1.ResourceList) {
+	queueCapabilityMilliCPU.DeleteLabelValues(queueName)
+	queueCapabilityMemory.DeleteLabelValues(queueName)
+	queueCapabilityScalar.DeletePartialMatch(prometheus.Labels{"queue_name": queueName})
+	for name, quantity := range capability {
+		switch name {
+		case v1.ResourceCPU:
+			queueCapabilityMilliCPU.WithLabelValues(queueName).Set(float64(quantity.MilliValue()))
+		case v1.ResourceMemory:
+			queueCapabilityMemory.WithLabelValues(queueName).Set(float64(quantity.Value()))
+		case v1.ResourcePods:
+		default:
+			queueCapabilityScalar.WithLabelValues(queueName, string(name)).Set(quantity.AsApproximateFloat64())
+		}
+	}
+}
+
+// UpdateQueueBorrowed records resources allocated beyond the deserved resources for one queue
+func UpdateQueueBorrowed(queueName string, milliCPU, memory float64) {
+	queueBorrowedMilliCPU.WithLabelValues(queueName).Set(milliCPU)
+	queueBorrowedMemory.WithLabelValues(queueName).Set(memory)
+}
+
 // UpdateQueueShare records share for one queue
 func UpdateQueueShare(queueName string, share float64) {
 	queueShare.WithLabelValues(queueName).Set(share)
+
+	queueSharesLock.Lock()
+	defer queueSharesLock.Unlock()
+	queueShares[queueName] = share
+}
+
+// QueueShare returns the last share recorded for one queue, false if none was recorded,
+// e.g. neither the proportion nor the capacity plugin is enabled.
+func QueueShare(queueName string) (float64, bool) {
+	queueSharesLock.RLock()
+	defer queueSharesLock.RUnlock()
+	share, found := queueShares[queueName]
+	return share, found
 }
 
 // UpdateQueueWeight records weight for one queue
@@ -194,11 +366,25 @@ func DeleteQueueMetrics(queueName string) {
 	queueRequestMemory.DeleteLabelValues(queueName)
 	queueDeservedMilliCPU.DeleteLabelValues(queueName)
 	queueDeservedMemory.DeleteLabelValues(queueName)
+	queueAllocatedScalar.delete(queueName)
+	queueRequestScalar.delete(queueName)
+	queueDeservedScalar.delete(queueName)
+	queueBorrowedMilliCPU.DeleteLabelValues(queueName)
+	queueBorrowedMemory.DeleteLabelValues(queueName)
 	queueShare.DeleteLabelValues(queueName)
+	queueSharesLock.Lock()
+	delete(queueShares, queueName)
+	queueSharesLock.Unlock()
 	queueWeight.DeleteLabelValues(queueName)
 	queueOverused.DeleteLabelValues(queueName)
 	queuePodGroupInqueue.DeleteLabelValues(queueName)
 	queuePodGroupPending.DeleteLabelValues(queueName)
 	queuePodGroupRunning.DeleteLabelValues(queueName)
 	queuePodGroupUnknown.DeleteLabelValues(queueName)
+	queueCapabilityMilliCPU.DeleteLabelValues(queueName)
+	queueCapabilityMemory.DeleteLabelValues(queueName)
+	queueCapabilityScalar.DeletePartialMatch(prometheus.Labels{"queue_name": queueName})
+	deleteQueueNamespaceUsage(queueName)
+	podGroupQueueingLatency.DeleteLabelValues(queueName)
+	podGroupSchedulingLatency.DeleteLabelValues(queueName)
 }
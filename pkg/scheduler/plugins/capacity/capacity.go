@@ -17,6 +17,7 @@ limitations under the License.
 package capacity
 
 import (
+	"fmt"
 	"math"
 
 	v1 "k8s.io/api/core/v1"
@@ -123,6 +124,11 @@ func (cp *capacityPlugin) OnSessionOpen(ssn *framework.Session) {
 				realCapability.MinDimensionResource(attr.capability, api.Infinity)
 				attr.realCapability = realCapability
 			}
+			if borrowLimit, err := queue.BorrowLimit(); err != nil {
+				klog.Errorf("Failed to get borrow limit of queue <%s>: %v", queue.Name, err)
+			} else if borrowLimit != nil {
+				attr.realCapability.MinDimensionResource(borrowCapability(queue.Queue.Spec.Deserved, borrowLimit), api.Infinity)
+			}
 			cp.queueOpts[job.Queue] = attr
 			klog.V(4).Infof("Added Queue <%s> attributes.", job.Queue)
 		}
@@ -179,22 +185,26 @@ func (cp *capacityPlugin) OnSessionOpen(ssn *framework.Session) {
 		queue := ssn.Queues[queueID]
 		if attr, ok := cp.queueOpts[queueID]; ok {
 			metrics.UpdateQueueDeserved(attr.name, attr.deserved.MilliCPU, attr.deserved.Memory)
+			metrics.UpdateQueueDeservedScalar(attr.name, attr.deserved.ScalarResources)
 			metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
+			metrics.UpdateQueueAllocatedScalar(attr.name, attr.allocated.ScalarResources)
 			metrics.UpdateQueueRequest(attr.name, attr.request.MilliCPU, attr.request.Memory)
+			metrics.UpdateQueueRequestScalar(attr.name, attr.request.ScalarResources)
+			cp.updateBorrowed(attr)
 			metrics.UpdateQueuePodGroupInqueueCount(attr.name, queue.Queue.Status.Inqueue)
 			metrics.UpdateQueuePodGroupPendingCount(attr.name, queue.Queue.Status.Pending)
 			metrics.UpdateQueuePodGroupRunningCount(attr.name, queue.Queue.Status.Running)
 			metrics.UpdateQueuePodGroupUnknownCount(attr.name, queue.Queue.Status.Unknown)
 			continue
 		}
-		deservedCPU, deservedMem := 0.0, 0.0
-		if queue.Queue.Spec.Deserved != nil {
-			deservedCPU = float64(queue.Queue.Spec.Deserved.Cpu().MilliValue())
-			deservedMem = float64(queue.Queue.Spec.Deserved.Memory().Value())
-		}
-		metrics.UpdateQueueDeserved(queueInfo.Name, deservedCPU, deservedMem)
+		deserved := api.NewResource(queue.Queue.Spec.Deserved)
+		metrics.UpdateQueueDeserved(queueInfo.Name, deserved.MilliCPU, deserved.Memory)
+		metrics.UpdateQueueDeservedScalar(queueInfo.Name, deserved.ScalarResources)
 		metrics.UpdateQueueAllocated(queueInfo.Name, 0, 0)
+		metrics.UpdateQueueAllocatedScalar(queueInfo.Name, nil)
 		metrics.UpdateQueueRequest(queueInfo.Name, 0, 0)
+		metrics.UpdateQueueRequestScalar(queueInfo.Name, nil)
+		metrics.UpdateQueueBorrowed(queueInfo.Name, 0, 0)
 		metrics.UpdateQueuePodGroupInqueueCount(queueInfo.Name, 0)
 		metrics.UpdateQueuePodGroupPendingCount(queueInfo.Name, 0)
 		metrics.UpdateQueuePodGroupRunningCount(queueInfo.Name, 0)
@@ -312,6 +322,9 @@ func (cp *capacityPlugin) OnSessionOpen(ssn *framework.Session) {
 			attr.inqueue.Add(job.DeductSchGatedResources(minReq))
 			return util.Permit
 		}
+		job.JobFitReason = api.QueueQuotaInsufficientReason
+		job.JobFitErrors = fmt.Sprintf("queue %s resource quota insufficient: job requests <%s>, queue capability <%s>, allocated <%s>, inqueue <%s>",
+			queue.Name, minReq.String(), attr.realCapability.String(), attr.allocated.String(), attr.inqueue.String())
 		ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeNormal, string(scheduling.PodGroupUnschedulableType), "queue resource quota insufficient")
 		return util.Reject
 	})
@@ -323,8 +336,10 @@ func (cp *capacityPlugin) OnSessionOpen(ssn *framework.Session) {
 			attr := cp.queueOpts[job.Queue]
 			attr.allocated.Add(event.Task.Resreq)
 			metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
+			metrics.UpdateQueueAllocatedScalar(attr.name, attr.allocated.ScalarResources)
 
 			cp.updateShare(attr)
+			cp.updateBorrowed(attr)
 
 			klog.V(4).Infof("Capacity AllocateFunc: task <%v/%v>, resreq <%v>,  share <%v>",
 				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
@@ -334,8 +349,10 @@ func (cp *capacityPlugin) OnSessionOpen(ssn *framework.Session) {
 			attr := cp.queueOpts[job.Queue]
 			attr.allocated.Sub(event.Task.Resreq)
 			metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
+			metrics.UpdateQueueAllocatedScalar(attr.name, attr.allocated.ScalarResources)
 
 			cp.updateShare(attr)
+			cp.updateBorrowed(attr)
 
 			klog.V(4).Infof("Capacity EvictFunc: task <%v/%v>, resreq <%v>,  share <%v>",
 				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
@@ -362,3 +379,31 @@ func (cp *capacityPlugin) updateShare(attr *queueAttr) {
 	attr.share = res
 	metrics.UpdateQueueShare(attr.name, attr.share)
 }
+
+// updateBorrowed records the resource the queue allocated beyond its deserved resource.
+func (cp *capacityPlugin) updateBorrowed(attr *queueAttr) {
+	borrowed, _ := attr.allocated.Diff(attr.deserved, api.Infinity)
+	metrics.UpdateQueueBorrowed(attr.name, borrowed.MilliCPU, borrowed.Memory)
+}
+
+// borrowCapability returns the resource the queue may use when borrowing up to its borrow limit,
+// the resources which are not limited are infinite.
+func borrowCapability(deserved, borrowLimit v1.ResourceList) *api.Resource {
+	limit := v1.ResourceList{}
+	for name, quantity := range borrowLimit {
+		sum := quantity.DeepCopy()
+		if d, found := deserved[name]; found {
+			sum.Add(d)
+		}
+		limit[name] = sum
+	}
+
+	capability := api.NewResource(limit)
+	if _, found := limit[v1.ResourceCPU]; !found {
+		capability.MilliCPU = math.MaxFloat64
+	}
+	if _, found := limit[v1.ResourceMemory]; !found {
+		capability.Memory = math.MaxFloat64
+	}
+	return capability
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package capacity
 
 import (
+	"math"
 	"os"
 	"testing"
 
@@ -333,3 +334,39 @@ func TestEnqueueAndAllocatable(t *testing.T) {
 		})
 	}
 }
+
+func TestBorrowCapability(t *testing.T) {
+	tests := []struct {
+		name        string
+		deserved    corev1.ResourceList
+		borrowLimit corev1.ResourceList
+		expected    *api.Resource
+	}{
+		{
+			name:        "limit is added to the deserved resource",
+			deserved:    api.BuildResourceList("4", "8Gi"),
+			borrowLimit: api.BuildResourceList("2", "4Gi"),
+			expected:    api.NewResource(api.BuildResourceList("6", "12Gi")),
+		},
+		{
+			name:        "resources without limit are not limited",
+			deserved:    api.BuildResourceList("4", "8Gi"),
+			borrowLimit: corev1.ResourceList{corev1.ResourceCPU: api.BuildResourceList("0", "0")[corev1.ResourceCPU]},
+			expected:    &api.Resource{MilliCPU: 4000, Memory: math.MaxFloat64},
+		},
+		{
+			name:        "limit without deserved resource",
+			borrowLimit: api.BuildResourceList("1", "1Gi"),
+			expected:    api.NewResource(api.BuildResourceList("1", "1Gi")),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			capability := borrowCapability(test.deserved, test.borrowLimit)
+			if capability.MilliCPU != test.expected.MilliCPU || capability.Memory != test.expected.Memory {
+				t.Errorf("expected capability <%v>, got <%v>", test.expected, capability)
+			}
+		})
+	}
+}
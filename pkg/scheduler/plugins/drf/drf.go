@@ -37,6 +37,19 @@ const PluginName = "drf"
 
 var shareDelta = 0.000001
 
+const (
+	// fairShareByKey subdivides the share of each queue by the namespaces or the users of its jobs,
+	// "namespace" or "user", so that the jobs of the namespace or user with the least share of the
+	// queue go first and preempt the jobs of the others.
+	fairShareByKey = "drf.fairShareBy"
+	// fairShareUserLabelKey is the podgroup label naming the user of a job, volcano.sh/user by default.
+	fairShareUserLabelKey = "drf.fairShareUserLabel"
+
+	fairShareByNamespace      = "namespace"
+	fairShareByUser           = "user"
+	defaultFairShareUserLabel = "volcano.sh/user"
+)
+
 // hierarchicalNode represents the node hierarchy
 // and the corresponding weight and drf attribute
 type hierarchicalNode struct {
@@ -110,6 +123,12 @@ type drfPlugin struct {
 	// map[namespaceName]->attr
 	namespaceOpts map[string]*drfAttr
 
+	// fairShareBy and fairShareUserLabel subdivide the share of the queues, see fairShareByKey
+	fairShareBy        string
+	fairShareUserLabel string
+	// map[queue/namespace or queue/user]->attr
+	groupAttrs map[string]*drfAttr
+
 	// hierarchical tree root
 	hierarchicalRoot *hierarchicalNode
 
@@ -124,6 +143,7 @@ func New(arguments framework.Arguments) framework.Plugin {
 		totalAllocated: api.EmptyResource(),
 		jobAttrs:       map[api.JobID]*drfAttr{},
 		namespaceOpts:  map[string]*drfAttr{},
+		groupAttrs:     map[string]*drfAttr{},
 		hierarchicalRoot: &hierarchicalNode{
 			attr:      &drfAttr{allocated: api.EmptyResource()},
 			request:   api.EmptyResource(),
@@ -152,6 +172,51 @@ func (drf *drfPlugin) HierarchyEnabled(ssn *framework.Session) bool {
 	return false
 }
 
+// parseFairShareArguments reads how the share of the queues is subdivided.
+func (drf *drfPlugin) parseFairShareArguments() {
+	drf.fairShareBy, _ = drf.pluginArguments[fairShareByKey].(string)
+	if drf.fairShareBy != "" && drf.fairShareBy != fairShareByNamespace && drf.fairShareBy != fairShareByUser {
+		klog.Errorf("Ignore unknown %s <%s> of plugin %s, expected %s or %s",
+			fairShareByKey, drf.fairShareBy, PluginName, fairShareByNamespace, fairShareByUser)
+		drf.fairShareBy = ""
+	}
+	drf.fairShareUserLabel, _ = drf.pluginArguments[fairShareUserLabelKey].(string)
+	if drf.fairShareUserLabel == "" {
+		drf.fairShareUserLabel = defaultFairShareUserLabel
+	}
+}
+
+// groupKey returns the key of the namespace or the user of the job in its queue, "" if the
+// share of the queues is not subdivided.
+func (drf *drfPlugin) groupKey(job *api.JobInfo) string {
+	switch drf.fairShareBy {
+	case fairShareByNamespace:
+		return string(job.Queue) + "/" + job.Namespace
+	case fairShareByUser:
+		user := ""
+		if job.PodGroup != nil {
+			user = job.PodGroup.Labels[drf.fairShareUserLabel]
+		}
+		return string(job.Queue) + "/" + user
+	}
+	return ""
+}
+
+// groupAttr returns the share of the namespace or the user of the job in its queue, nil if the
+// share of the queues is not subdivided.
+func (drf *drfPlugin) groupAttr(job *api.JobInfo) *drfAttr {
+	key := drf.groupKey(job)
+	if key == "" {
+		return nil
+	}
+	attr, found := drf.groupAttrs[key]
+	if !found {
+		attr = &drfAttr{allocated: api.EmptyResource()}
+		drf.groupAttrs[key] = attr
+	}
+	return attr
+}
+
 func (drf *drfPlugin) compareQueues(root *hierarchicalNode, lqueue *api.QueueInfo, rqueue *api.QueueInfo) float64 {
 	lnode := root
 	lpaths := strings.Split(lqueue.Hierarchy, "/")
@@ -191,6 +256,7 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 	klog.V(4).Infof("Total Allocatable %s", drf.totalResource)
 
 	hierarchyEnabled := drf.HierarchyEnabled(ssn)
+	drf.parseFairShareArguments()
 
 	for _, job := range ssn.Jobs {
 		attr := &drfAttr{
@@ -210,6 +276,10 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 
 		drf.jobAttrs[job.UID] = attr
 
+		if gattr := drf.groupAttr(job); gattr != nil {
+			gattr.allocated.Add(attr.allocated)
+		}
+
 		if hierarchyEnabled {
 			queue := ssn.Queues[job.Queue]
 			drf.totalAllocated.Add(attr.allocated)
@@ -217,6 +287,10 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 		}
 	}
 
+	for _, attr := range drf.groupAttrs {
+		drf.updateShare(attr)
+	}
+
 	preemptableFn := func(preemptor *api.TaskInfo, preemptees []*api.TaskInfo) ([]*api.TaskInfo, int) {
 		var victims []*api.TaskInfo
 
@@ -230,7 +304,28 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 
 		allocations := map[api.JobID]*api.Resource{}
 
+		// the namespaces or users of the queue preempt by their shares
+		lgroup := drf.groupKey(ssn.Jobs[preemptor.Job])
+		var lgroupShare float64
+		if lgroup != "" {
+			_, lgroupShare = drf.calculateShare(drf.groupAttrs[lgroup].allocated.Clone().Add(preemptor.Resreq), drf.totalResource)
+		}
+		groupAllocations := map[string]*api.Resource{}
+
 		for _, preemptee := range preemptees {
+			if rgroup := drf.groupKey(ssn.Jobs[preemptee.Job]); rgroup != lgroup {
+				if _, found := groupAllocations[rgroup]; !found {
+					groupAllocations[rgroup] = drf.groupAttrs[rgroup].allocated.Clone()
+				}
+				_, rgroupShare := drf.calculateShare(groupAllocations[rgroup].Sub(preemptee.Resreq), drf.totalResource)
+				if lgroupShare < rgroupShare || math.Abs(lgroupShare-rgroupShare) <= shareDelta {
+					addVictim(preemptee)
+				} else {
+					groupAllocations[rgroup].Add(preemptee.Resreq)
+				}
+				continue
+			}
+
 			if _, found := allocations[preemptee.Job]; !found {
 				ratt := drf.jobAttrs[preemptee.Job]
 				allocations[preemptee.Job] = ratt.allocated.Clone()
@@ -331,6 +426,17 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 		klog.V(4).Infof("DRF JobOrderFn: <%v/%v> share state: %v, <%v/%v> share state: %v",
 			lv.Namespace, lv.Name, drf.jobAttrs[lv.UID].share, rv.Namespace, rv.Name, drf.jobAttrs[rv.UID].share)
 
+		// the jobs of the namespace or user with the least share of the queue go first
+		if lgroup, rgroup := drf.groupKey(lv), drf.groupKey(rv); lgroup != rgroup {
+			lshare, rshare := drf.groupAttrs[lgroup].share, drf.groupAttrs[rgroup].share
+			if lshare < rshare {
+				return -1
+			}
+			if lshare > rshare {
+				return 1
+			}
+		}
+
 		if drf.jobAttrs[lv.UID].share == drf.jobAttrs[rv.UID].share {
 			return 0
 		}
@@ -352,6 +458,10 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 
 			job := ssn.Jobs[event.Task.Job]
 			drf.updateJobShare(job.Namespace, job.Name, attr)
+			if gattr := drf.groupAttr(job); gattr != nil {
+				gattr.allocated.Add(event.Task.Resreq)
+				drf.updateShare(gattr)
+			}
 
 			nsShare := -1.0
 			if hierarchyEnabled {
@@ -370,6 +480,10 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 
 			job := ssn.Jobs[event.Task.Job]
 			drf.updateJobShare(job.Namespace, job.Name, attr)
+			if gattr := drf.groupAttr(job); gattr != nil {
+				gattr.allocated.Sub(event.Task.Resreq)
+				drf.updateShare(gattr)
+			}
 
 			nsShare := -1.0
 
@@ -518,4 +632,5 @@ func (drf *drfPlugin) OnSessionClose(session *framework.Session) {
 	drf.totalResource = api.EmptyResource()
 	drf.totalAllocated = api.EmptyResource()
 	drf.jobAttrs = map[api.JobID]*drfAttr{}
+	drf.groupAttrs = map[string]*drfAttr{}
 }
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drf
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/cmd/scheduler/app/options"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestFairShareWithinQueue(t *testing.T) {
+	options.Default()
+
+	userLabels := func(user string) map[string]string {
+		return map[string]string{defaultFairShareUserLabel: user}
+	}
+	newPodGroup := func(name, namespace, user string, phase schedulingv1.PodGroupPhase) *schedulingv1.PodGroup {
+		pg := util.BuildPodGroup(name, namespace, "team", 1, nil, phase)
+		pg.Labels = userLabels(user)
+		return pg
+	}
+
+	tests := []struct {
+		name      string
+		arguments framework.Arguments
+		// whether the pending job of ns2/bob goes before the pending job of ns1/alice
+		expectFirst bool
+	}{
+		{
+			name:        "jobs ordered by their own shares",
+			arguments:   framework.Arguments{},
+			expectFirst: false,
+		},
+		{
+			name:        "jobs ordered by the shares of their namespaces",
+			arguments:   framework.Arguments{fairShareByKey: fairShareByNamespace},
+			expectFirst: true,
+		},
+		{
+			name:        "jobs ordered by the shares of their users",
+			arguments:   framework.Arguments{fairShareByKey: fairShareByUser},
+			expectFirst: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testStruct := uthelper.TestCommonStruct{
+				Name:    test.name,
+				Plugins: map[string]framework.PluginBuilder{PluginName: New},
+				PodGroups: []*schedulingv1.PodGroup{
+					newPodGroup("running", "ns1", "alice", schedulingv1.PodGroupRunning),
+					newPodGroup("pending1", "ns1", "alice", schedulingv1.PodGroupInqueue),
+					newPodGroup("pending2", "ns2", "bob", schedulingv1.PodGroupInqueue),
+				},
+				Pods: []*v1.Pod{
+					util.BuildPod("ns1", "running-p0", "n1", v1.PodRunning, api.BuildResourceList("2", "2G"), "running", userLabels("alice"), nil),
+					util.BuildPod("ns1", "pending1-p0", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pending1", userLabels("alice"), nil),
+					util.BuildPod("ns2", "pending2-p0", "", v1.PodPending, api.BuildResourceList("1", "1G"), "pending2", userLabels("bob"), nil),
+				},
+				Nodes: []*v1.Node{
+					util.BuildNode("n1", api.BuildResourceList("8", "8G", []api.ScalarResource{{Name: "pods", Value: "10"}}...), nil),
+				},
+				Queues: []*schedulingv1.Queue{
+					util.BuildQueue("team", 1, nil),
+				},
+			}
+			trueValue := true
+			tiers := []conf.Tier{
+				{
+					Plugins: []conf.PluginOption{
+						{
+							Name:            PluginName,
+							EnabledJobOrder: &trueValue,
+							Arguments:       test.arguments,
+						},
+					},
+				},
+			}
+			ssn := testStruct.RegisterSession(tiers, nil)
+			defer testStruct.Close()
+
+			alice := ssn.Jobs["ns1/pending1"]
+			bob := ssn.Jobs["ns2/pending2"]
+			if first := ssn.JobOrderFn(bob, alice); first != test.expectFirst {
+				t.Errorf("expected job of bob first %v, got %v", test.expectFirst, first)
+			}
+		})
+	}
+}
@@ -186,12 +186,18 @@ func (gp *gangPlugin) OnSessionClose(ssn *framework.Session) {
 			// TODO: If the Job is gang-unschedulable due to scheduling gates
 			// we need a new message and reason to tell users
 			// More detail in design doc pod-scheduling-readiness.md
+			reason := v1beta1.NotEnoughResourcesReason
+			if diagnosedReason, diagnosis := api.DiagnoseJob(job, ssn.Nodes); diagnosedReason != "" {
+				reason = diagnosedReason
+				msg = diagnosis + "; " + msg
+			}
+			metrics.RegisterUnschedulablePodGroup(reason)
 			jc := &scheduling.PodGroupCondition{
 				Type:               scheduling.PodGroupUnschedulableType,
 				Status:             v1.ConditionTrue,
 				LastTransitionTime: metav1.Now(),
 				TransitionID:       string(ssn.UID),
-				Reason:             v1beta1.NotEnoughResourcesReason,
+				Reason:             reason,
 				Message:            msg,
 			}
 
@@ -0,0 +1,309 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/api/helpers"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// rootQueuePath is the path of the root of the queue hierarchy.
+const rootQueuePath = "root"
+
+// queueNode is a node of the queue hierarchy, e.g. root/org/team. Inner nodes group the
+// queues declaring them in their hierarchy annotation, a queue whose hierarchy is the
+// path of an inner node gives the node its capability. The queues holding jobs are the
+// leaves, placed under the node of their own path.
+type queueNode struct {
+	path   string
+	weight float64
+	// attr is set for the leaves only
+	attr *queueAttr
+	// capability is the capability of the queues at this path, nil if not limited
+	capability *api.Resource
+	// realCapability is the capability inherited from the ancestors, LessEqual capability
+	realCapability *api.Resource
+
+	request   *api.Resource
+	guarantee *api.Resource
+	deserved  *api.Resource
+
+	parent   *queueNode
+	children map[string]*queueNode
+}
+
+func newQueueNode(path string, weight float64, parent *queueNode) *queueNode {
+	return &queueNode{
+		path:      path,
+		weight:    weight,
+		request:   api.EmptyResource(),
+		guarantee: api.EmptyResource(),
+		deserved:  api.EmptyResource(),
+		parent:    parent,
+		children:  map[string]*queueNode{},
+	}
+}
+
+// hierarchyEnabled returns whether hierarchical sharing is enabled for proportion.
+func hierarchyEnabled(ssn *framework.Session) bool {
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			if plugin.Name != PluginName {
+				continue
+			}
+			return plugin.EnabledHierarchy != nil && *plugin.EnabledHierarchy
+		}
+	}
+	return false
+}
+
+// queueCapability returns the capability of the queue, nil if it is not set. The cpu and
+// memory which are not set are not limited. The capability schedule of the queue applies as
+// soon as a window starts or ends, without waiting for the queue controller to update the spec.
+func queueCapability(queue *api.QueueInfo) *api.Resource {
+	spec := apis.ScheduledCapability(queue.Queue.Annotations, queue.Queue.Spec.Capability, time.Now())
+	if len(spec) == 0 {
+		return nil
+	}
+	capability := api.NewResource(spec)
+	if capability.MilliCPU <= 0 {
+		capability.MilliCPU = math.MaxFloat64
+	}
+	if capability.Memory <= 0 {
+		capability.Memory = math.MaxFloat64
+	}
+	return capability
+}
+
+// queueHierarchy returns the path of the queue in the hierarchy and the weight of each
+// level. A queue without hierarchy annotation is a child of the root weighted by its weight.
+func queueHierarchy(queue *api.QueueInfo) ([]string, []float64) {
+	if queue.Hierarchy == "" {
+		weight := float64(queue.Weight)
+		if weight <= 0 {
+			weight = 1
+		}
+		return []string{rootQueuePath, queue.Name}, []float64{1, weight}
+	}
+
+	paths := strings.Split(queue.Hierarchy, "/")
+	if paths[0] != rootQueuePath {
+		paths = append([]string{rootQueuePath}, paths...)
+	}
+	weights := strings.Split(queue.Weights, "/")
+	if len(weights) < len(paths) {
+		weights = append(make([]string, len(paths)-len(weights)), weights...)
+	}
+	weights = weights[len(weights)-len(paths):]
+
+	result := make([]float64, len(paths))
+	for i := range paths {
+		weight, err := strconv.ParseFloat(weights[i], 64)
+		if err != nil || weight <= 0 {
+			weight = 1
+		}
+		result[i] = weight
+	}
+	return paths, result
+}
+
+// buildQueueHierarchy builds the hierarchy of the queues, returning its root and the leaf
+// of each queue holding jobs.
+func buildQueueHierarchy(queues map[api.QueueID]*api.QueueInfo, queueOpts map[api.QueueID]*queueAttr,
+	totalResource *api.Resource) (*queueNode, map[api.QueueID]*queueNode) {
+	names := make([]string, 0, len(queues))
+	for _, queue := range queues {
+		names = append(names, queue.Name)
+	}
+	// the weight of a level declared by several queues is the one of the first queue
+	sort.Strings(names)
+
+	root := newQueueNode(rootQueuePath, 1, nil)
+	leaves := map[api.QueueID]*queueNode{}
+	for _, name := range names {
+		queue := queues[api.QueueID(name)]
+		paths, weights := queueHierarchy(queue)
+		node := root
+		for i := 1; i < len(paths); i++ {
+			child, found := node.children[paths[i]]
+			if !found {
+				child = newQueueNode(node.path+"/"+paths[i], weights[i], node)
+				node.children[paths[i]] = child
+				klog.V(4).Infof("Queue hierarchy node <%s> added, weight <%v>", child.path, child.weight)
+			}
+			node = child
+		}
+
+		if capability := queueCapability(queue); capability != nil {
+			if node.capability == nil {
+				node.capability = capability
+			} else {
+				node.capability.MinDimensionResource(capability, api.Infinity)
+			}
+		}
+
+		attr, found := queueOpts[queue.UID]
+		if !found {
+			continue
+		}
+		// the queue competes with the child nodes of its path, if any, with weight 1
+		leaf := newQueueNode(node.path, 1, node)
+		leaf.attr = attr
+		node.children["queue:"+queue.Name] = leaf
+		leaves[queue.UID] = leaf
+	}
+
+	root.deserved = totalResource.Clone()
+	root.realCapability = totalResource.Clone()
+	root.aggregate()
+	return root, leaves
+}
+
+// aggregate sums the request and guarantee of the leaves bottom up and inherits the
+// capability of the ancestors top down.
+func (n *queueNode) aggregate() {
+	if n.attr != nil {
+		n.request = n.attr.request.Clone()
+		n.guarantee = n.attr.guarantee.Clone()
+		n.realCapability = n.parent.realCapability.Clone()
+		if n.attr.realCapability != nil {
+			n.realCapability.MinDimensionResource(n.attr.realCapability, api.Infinity)
+		}
+		n.attr.realCapability = n.realCapability
+		return
+	}
+
+	if n.parent != nil {
+		n.realCapability = n.parent.realCapability.Clone()
+		if n.capability != nil {
+			n.realCapability.MinDimensionResource(n.capability, api.Infinity)
+		}
+	}
+	for _, child := range n.children {
+		child.aggregate()
+		n.request.Add(child.request)
+		n.guarantee.Add(child.guarantee)
+	}
+}
+
+func (n *queueNode) sortedChildren() []*queueNode {
+	keys := make([]string, 0, len(n.children))
+	for key := range n.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	children := make([]*queueNode, 0, len(keys))
+	for _, key := range keys {
+		children = append(children, n.children[key])
+	}
+	return children
+}
+
+// distribute divides the deserved resource of the node among its children by their weights,
+// the resource not used by a child up to its request or capability goes to its siblings.
+func (n *queueNode) distribute() {
+	if n.attr != nil {
+		n.attr.deserved = n.deserved
+		return
+	}
+
+	children := n.sortedChildren()
+	remaining := n.deserved.Clone()
+	meet := map[*queueNode]struct{}{}
+	for {
+		totalWeight := float64(0)
+		for _, child := range children {
+			if _, found := meet[child]; !found {
+				totalWeight += child.weight
+			}
+		}
+		if totalWeight == 0 {
+			break
+		}
+
+		oldRemaining := remaining.Clone()
+		increasedDeserved := api.EmptyResource()
+		decreasedDeserved := api.EmptyResource()
+		for _, child := range children {
+			if _, found := meet[child]; found {
+				continue
+			}
+
+			oldDeserved := child.deserved.Clone()
+			child.deserved.Add(remaining.Clone().Multi(child.weight / totalWeight))
+			child.deserved.MinDimensionResource(child.realCapability, api.Infinity)
+			child.deserved.MinDimensionResource(child.request, api.Zero)
+			child.deserved = helpers.Max(child.deserved, child.guarantee)
+
+			if child.request.LessEqual(child.deserved, api.Zero) ||
+				equality.Semantic.DeepEqual(child.deserved, oldDeserved) {
+				meet[child] = struct{}{}
+			}
+
+			increased, decreased := child.deserved.Diff(oldDeserved, api.Zero)
+			increasedDeserved.Add(increased)
+			decreasedDeserved.Add(decreased)
+		}
+
+		remaining.Sub(increasedDeserved).Add(decreasedDeserved)
+		if remaining.IsEmpty() || equality.Semantic.DeepEqual(remaining, oldRemaining) {
+			break
+		}
+	}
+
+	for _, child := range children {
+		klog.V(4).Infof("Queue hierarchy node <%s>: weight <%v>, deserved <%v>, realCapability <%v>, request <%v>",
+			child.path, child.weight, child.deserved, child.realCapability, child.request)
+		child.distribute()
+	}
+}
+
+// used returns the resource used by the queues under the node, as counted against capability.
+func (n *queueNode) used() *api.Resource {
+	if n.attr != nil {
+		return n.attr.allocated.Clone().Add(n.attr.inqueue).Sub(n.attr.elastic)
+	}
+	used := api.EmptyResource()
+	for _, child := range n.children {
+		used.Add(child.used())
+	}
+	return used
+}
+
+// ancestorsAllow checks the request of the job against the capability of the ancestors of the leaf.
+func ancestorsAllow(leaf *queueNode, minReq *api.Resource) (string, bool) {
+	for node := leaf.parent; node != nil; node = node.parent {
+		if node.capability == nil {
+			continue
+		}
+		if !minReq.Clone().Add(node.used()).LessEqualWithDimension(node.capability, minReq) {
+			return node.path, false
+		}
+	}
+	return "", true
+}
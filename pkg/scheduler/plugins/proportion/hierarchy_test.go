@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"testing"
+
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func buildHierarchicalQueue(name, hierarchy, weights, cpuCapability string) *api.QueueInfo {
+	annotations := map[string]string{}
+	if hierarchy != "" {
+		annotations[v1beta1.KubeHierarchyAnnotationKey] = hierarchy
+		annotations[v1beta1.KubeHierarchyWeightAnnotationKey] = weights
+	}
+	queue := util.BuildQueueWithAnnos(name, 1, nil, annotations)
+	if cpuCapability != "" {
+		queue.Spec.Capability = api.BuildResourceList(cpuCapability, "0")
+	}
+	return api.NewQueueInfo(queue)
+}
+
+func buildQueueAttr(queue *api.QueueInfo, requestCPU float64) *queueAttr {
+	return &queueAttr{
+		queueID:        queue.UID,
+		name:           queue.Name,
+		weight:         queue.Weight,
+		deserved:       api.EmptyResource(),
+		allocated:      api.EmptyResource(),
+		request:        &api.Resource{MilliCPU: requestCPU},
+		elastic:        api.EmptyResource(),
+		inqueue:        api.EmptyResource(),
+		guarantee:      api.EmptyResource(),
+		realCapability: &api.Resource{MilliCPU: 100000, Memory: 100000},
+	}
+}
+
+func TestHierarchicalDeserved(t *testing.T) {
+	total := &api.Resource{MilliCPU: 12000, Memory: 100000}
+
+	tests := []struct {
+		name     string
+		queues   []*api.QueueInfo
+		requests map[string]float64
+		parents  map[string]float64
+		expected map[string]float64
+	}{
+		{
+			name: "siblings share the deserved of their parent by weight",
+			queues: []*api.QueueInfo{
+				buildHierarchicalQueue("dev", "root/sci/dev", "1/2/1", ""),
+				buildHierarchicalQueue("prod", "root/sci/prod", "1/2/3", ""),
+				buildHierarchicalQueue("eng", "root/eng", "1/1", ""),
+			},
+			requests: map[string]float64{"dev": 12000, "prod": 12000, "eng": 12000},
+			expected: map[string]float64{"dev": 2000, "prod": 6000, "eng": 4000},
+		},
+		{
+			name: "resource unused by a subtree goes to the other subtrees",
+			queues: []*api.QueueInfo{
+				buildHierarchicalQueue("dev", "root/sci/dev", "1/2/1", ""),
+				buildHierarchicalQueue("prod", "root/sci/prod", "1/2/3", ""),
+				buildHierarchicalQueue("eng", "root/eng", "1/1", ""),
+			},
+			requests: map[string]float64{"dev": 12000, "prod": 12000, "eng": 1000},
+			expected: map[string]float64{"dev": 2750, "prod": 8250, "eng": 1000},
+		},
+		{
+			name: "capability of the parent queue is inherited by its children",
+			queues: []*api.QueueInfo{
+				buildHierarchicalQueue("sci", "root/sci", "1/1", "4"),
+				buildHierarchicalQueue("dev", "root/sci/dev", "1/1/1", ""),
+				buildHierarchicalQueue("prod", "root/sci/prod", "1/1/1", ""),
+				buildHierarchicalQueue("eng", "root/eng", "1/1", ""),
+			},
+			requests: map[string]float64{"dev": 12000, "prod": 12000, "eng": 12000},
+			expected: map[string]float64{"dev": 2000, "prod": 2000, "eng": 8000},
+		},
+		{
+			name: "queues without hierarchy are children of the root",
+			queues: []*api.QueueInfo{
+				buildHierarchicalQueue("default", "", "", ""),
+				buildHierarchicalQueue("dev", "root/sci/dev", "1/1/1", ""),
+			},
+			requests: map[string]float64{"default": 12000, "dev": 12000},
+			expected: map[string]float64{"default": 6000, "dev": 6000},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queues := map[api.QueueID]*api.QueueInfo{}
+			queueOpts := map[api.QueueID]*queueAttr{}
+			for _, queue := range test.queues {
+				queues[queue.UID] = queue
+				if request, found := test.requests[queue.Name]; found {
+					queueOpts[queue.UID] = buildQueueAttr(queue, request)
+				}
+			}
+
+			root, leaves := buildQueueHierarchy(queues, queueOpts, total)
+			root.distribute()
+
+			if len(leaves) != len(test.requests) {
+				t.Errorf("expected %d leaves, got %d", len(test.requests), len(leaves))
+			}
+			for name, expected := range test.expected {
+				if deserved := queueOpts[api.QueueID(name)].deserved.MilliCPU; deserved != expected {
+					t.Errorf("queue %s: expected deserved cpu %v, got %v", name, expected, deserved)
+				}
+			}
+		})
+	}
+}
+
+func TestAncestorsAllow(t *testing.T) {
+	sci := buildHierarchicalQueue("sci", "root/sci", "1/1", "4")
+	dev := buildHierarchicalQueue("dev", "root/sci/dev", "1/1/1", "")
+	prod := buildHierarchicalQueue("prod", "root/sci/prod", "1/1/1", "")
+	queues := map[api.QueueID]*api.QueueInfo{sci.UID: sci, dev.UID: dev, prod.UID: prod}
+
+	devAttr := buildQueueAttr(dev, 4000)
+	prodAttr := buildQueueAttr(prod, 4000)
+	prodAttr.allocated = &api.Resource{MilliCPU: 3000}
+	queueOpts := map[api.QueueID]*queueAttr{dev.UID: devAttr, prod.UID: prodAttr}
+
+	_, leaves := buildQueueHierarchy(queues, queueOpts, &api.Resource{MilliCPU: 12000, Memory: 100000})
+
+	if _, allowed := ancestorsAllow(leaves[dev.UID], &api.Resource{MilliCPU: 1000}); !allowed {
+		t.Errorf("expected job within the capability of root/sci to be allowed")
+	}
+	if path, allowed := ancestorsAllow(leaves[dev.UID], &api.Resource{MilliCPU: 2000}); allowed || path != "root/sci" {
+		t.Errorf("expected job exceeding the capability of root/sci to be rejected, got %v, %s", allowed, path)
+	}
+}
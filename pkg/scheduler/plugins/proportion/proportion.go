@@ -17,7 +17,7 @@ limitations under the License.
 package proportion
 
 import (
-	"math"
+	"fmt"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -38,6 +38,8 @@ type proportionPlugin struct {
 	totalResource  *api.Resource
 	totalGuarantee *api.Resource
 	queueOpts      map[api.QueueID]*queueAttr
+	// queueLeaves is the leaf of each queue in the queue hierarchy, set if hierarchy is enabled
+	queueLeaves map[api.QueueID]*queueNode
 	// Arguments given for the plugin
 	pluginArguments framework.Arguments
 }
@@ -105,15 +107,7 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 				inqueue:   api.EmptyResource(),
 				guarantee: api.EmptyResource(),
 			}
-			if len(queue.Queue.Spec.Capability) != 0 {
-				attr.capability = api.NewResource(queue.Queue.Spec.Capability)
-				if attr.capability.MilliCPU <= 0 {
-					attr.capability.MilliCPU = math.MaxFloat64
-				}
-				if attr.capability.Memory <= 0 {
-					attr.capability.Memory = math.MaxFloat64
-				}
-			}
+			attr.capability = queueCapability(queue)
 			if len(queue.Queue.Spec.Guarantee.Resource) != 0 {
 				attr.guarantee = api.NewResource(queue.Queue.Spec.Guarantee.Resource)
 			}
@@ -165,7 +159,9 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 	for queueID, queueInfo := range ssn.Queues {
 		if attr, ok := pp.queueOpts[queueID]; ok {
 			metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
+			metrics.UpdateQueueAllocatedScalar(attr.name, attr.allocated.ScalarResources)
 			metrics.UpdateQueueRequest(attr.name, attr.request.MilliCPU, attr.request.Memory)
+			metrics.UpdateQueueRequestScalar(attr.name, attr.request.ScalarResources)
 			metrics.UpdateQueueWeight(attr.name, attr.weight)
 			queue := ssn.Queues[attr.queueID]
 			metrics.UpdateQueuePodGroupInqueueCount(attr.name, queue.Queue.Status.Inqueue)
@@ -175,80 +171,19 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 			continue
 		}
 		metrics.UpdateQueueAllocated(queueInfo.Name, 0, 0)
+		metrics.UpdateQueueAllocatedScalar(queueInfo.Name, nil)
 		metrics.UpdateQueueRequest(queueInfo.Name, 0, 0)
+		metrics.UpdateQueueRequestScalar(queueInfo.Name, nil)
 		metrics.UpdateQueuePodGroupInqueueCount(queueInfo.Name, 0)
 		metrics.UpdateQueuePodGroupPendingCount(queueInfo.Name, 0)
 		metrics.UpdateQueuePodGroupRunningCount(queueInfo.Name, 0)
 		metrics.UpdateQueuePodGroupUnknownCount(queueInfo.Name, 0)
 	}
 
-	remaining := pp.totalResource.Clone()
-	meet := map[api.QueueID]struct{}{}
-	for {
-		totalWeight := int32(0)
-		for _, attr := range pp.queueOpts {
-			if _, found := meet[attr.queueID]; found {
-				continue
-			}
-			totalWeight += attr.weight
-		}
-
-		// If no queues, break
-		if totalWeight == 0 {
-			klog.V(4).Infof("Exiting when total weight is 0")
-			break
-		}
-
-		oldRemaining := remaining.Clone()
-		// Calculates the deserved of each Queue.
-		// increasedDeserved is the increased value for attr.deserved of processed queues
-		// decreasedDeserved is the decreased value for attr.deserved of processed queues
-		increasedDeserved := api.EmptyResource()
-		decreasedDeserved := api.EmptyResource()
-		for _, attr := range pp.queueOpts {
-			klog.V(4).Infof("Considering Queue <%s>: weight <%d>, total weight <%d>.",
-				attr.name, attr.weight, totalWeight)
-			if _, found := meet[attr.queueID]; found {
-				continue
-			}
-
-			oldDeserved := attr.deserved.Clone()
-			attr.deserved.Add(remaining.Clone().Multi(float64(attr.weight) / float64(totalWeight)))
-
-			if attr.realCapability != nil {
-				attr.deserved.MinDimensionResource(attr.realCapability, api.Infinity)
-			}
-			attr.deserved.MinDimensionResource(attr.request, api.Zero)
-
-			attr.deserved = helpers.Max(attr.deserved, attr.guarantee)
-			pp.updateShare(attr)
-			klog.V(4).Infof("Format queue <%s> deserved resource to <%v>", attr.name, attr.deserved)
-
-			if attr.request.LessEqual(attr.deserved, api.Zero) {
-				meet[attr.queueID] = struct{}{}
-				klog.V(4).Infof("queue <%s> is meet", attr.name)
-			} else if equality.Semantic.DeepEqual(attr.deserved, oldDeserved) {
-				meet[attr.queueID] = struct{}{}
-				klog.V(4).Infof("queue <%s> is meet cause of the capability", attr.name)
-			}
-
-			klog.V(4).Infof("The attributes of queue <%s> in proportion: deserved <%v>, realCapability <%v>, allocate <%v>, request <%v>, elastic <%v>, share <%0.2f>",
-				attr.name, attr.deserved, attr.realCapability, attr.allocated, attr.request, attr.elastic, attr.share)
-
-			increased, decreased := attr.deserved.Diff(oldDeserved, api.Zero)
-			increasedDeserved.Add(increased)
-			decreasedDeserved.Add(decreased)
-
-			// Record metrics
-			metrics.UpdateQueueDeserved(attr.name, attr.deserved.MilliCPU, attr.deserved.Memory)
-		}
-
-		remaining.Sub(increasedDeserved).Add(decreasedDeserved)
-		klog.V(4).Infof("Remaining resource is  <%s>", remaining)
-		if remaining.IsEmpty() || equality.Semantic.DeepEqual(remaining, oldRemaining) {
-			klog.V(4).Infof("Exiting when remaining is empty or no queue has more resource request:  <%v>", remaining)
-			break
-		}
+	if hierarchyEnabled(ssn) {
+		pp.updateHierarchicalDeserved(ssn)
+	} else {
+		pp.updateDeserved()
 	}
 
 	ssn.AddQueueOrderFn(pp.Name(), func(l, r interface{}) int {
@@ -289,7 +224,7 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 				continue
 			}
 
-			if !allocated.LessEqual(attr.deserved, api.Zero) {
+			if !allocated.LessEqual(attr.deserved, api.Zero) && reclaimFrees(allocated, attr.deserved, reclaimer.Resreq, reclaimee.Resreq) {
 				allocated.Sub(reclaimee.Resreq)
 				victims = append(victims, reclaimee)
 			}
@@ -349,6 +284,12 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		r := minReq.Clone().Add(attr.allocated).Add(attr.inqueue).Sub(attr.elastic)
 
 		inqueue := r.LessEqualWithDimension(attr.realCapability, minReq)
+		if leaf, found := pp.queueLeaves[queueID]; inqueue && found {
+			if path, allowed := ancestorsAllow(leaf, minReq); !allowed {
+				klog.V(4).Infof("job %s exceeds the capability of queue hierarchy node %s", job.Name, path)
+				inqueue = false
+			}
+		}
 		klog.V(5).Infof("job %s inqueue %v", job.Name, inqueue)
 		if inqueue {
 			// deduct the resources of scheduling gated tasks in a job when calculating inqueued resources
@@ -356,6 +297,9 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 			attr.inqueue.Add(job.DeductSchGatedResources(minReq))
 			return util.Permit
 		}
+		job.JobFitReason = api.QueueQuotaInsufficientReason
+		job.JobFitErrors = fmt.Sprintf("queue %s resource quota insufficient: job requests <%s>, queue capability <%s>, allocated <%s>, inqueue <%s>",
+			queue.Name, minReq.String(), attr.realCapability.String(), attr.allocated.String(), attr.inqueue.String())
 		ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeNormal, string(scheduling.PodGroupUnschedulableType), "queue resource quota insufficient")
 		return util.Reject
 	})
@@ -367,6 +311,7 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 			attr := pp.queueOpts[job.Queue]
 			attr.allocated.Add(event.Task.Resreq)
 			metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
+			metrics.UpdateQueueAllocatedScalar(attr.name, attr.allocated.ScalarResources)
 
 			pp.updateShare(attr)
 
@@ -378,6 +323,7 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 			attr := pp.queueOpts[job.Queue]
 			attr.allocated.Sub(event.Task.Resreq)
 			metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
+			metrics.UpdateQueueAllocatedScalar(attr.name, attr.allocated.ScalarResources)
 
 			pp.updateShare(attr)
 
@@ -391,13 +337,123 @@ func (pp *proportionPlugin) OnSessionClose(ssn *framework.Session) {
 	pp.totalResource = nil
 	pp.totalGuarantee = nil
 	pp.queueOpts = nil
+	pp.queueLeaves = nil
+}
+
+// updateDeserved divides the total resource among the queues by their weights.
+func (pp *proportionPlugin) updateDeserved() {
+	remaining := pp.totalResource.Clone()
+	meet := map[api.QueueID]struct{}{}
+	for {
+		totalWeight := int32(0)
+		for _, attr := range pp.queueOpts {
+			if _, found := meet[attr.queueID]; found {
+				continue
+			}
+			totalWeight += attr.weight
+		}
+
+		// If no queues, break
+		if totalWeight == 0 {
+			klog.V(4).Infof("Exiting when total weight is 0")
+			break
+		}
+
+		oldRemaining := remaining.Clone()
+		// Calculates the deserved of each Queue.
+		// increasedDeserved is the increased value for attr.deserved of processed queues
+		// decreasedDeserved is the decreased value for attr.deserved of processed queues
+		increasedDeserved := api.EmptyResource()
+		decreasedDeserved := api.EmptyResource()
+		for _, attr := range pp.queueOpts {
+			klog.V(4).Infof("Considering Queue <%s>: weight <%d>, total weight <%d>.",
+				attr.name, attr.weight, totalWeight)
+			if _, found := meet[attr.queueID]; found {
+				continue
+			}
+
+			oldDeserved := attr.deserved.Clone()
+			attr.deserved.Add(remaining.Clone().Multi(float64(attr.weight) / float64(totalWeight)))
+
+			if attr.realCapability != nil {
+				attr.deserved.MinDimensionResource(attr.realCapability, api.Infinity)
+			}
+			attr.deserved.MinDimensionResource(attr.request, api.Zero)
+
+			attr.deserved = helpers.Max(attr.deserved, attr.guarantee)
+			pp.updateShare(attr)
+			klog.V(4).Infof("Format queue <%s> deserved resource to <%v>", attr.name, attr.deserved)
+
+			if attr.request.LessEqual(attr.deserved, api.Zero) {
+				meet[attr.queueID] = struct{}{}
+				klog.V(4).Infof("queue <%s> is meet", attr.name)
+			} else if equality.Semantic.DeepEqual(attr.deserved, oldDeserved) {
+				meet[attr.queueID] = struct{}{}
+				klog.V(4).Infof("queue <%s> is meet cause of the capability", attr.name)
+			}
+
+			klog.V(4).Infof("The attributes of queue <%s> in proportion: deserved <%v>, realCapability <%v>, allocate <%v>, request <%v>, elastic <%v>, share <%0.2f>",
+				attr.name, attr.deserved, attr.realCapability, attr.allocated, attr.request, attr.elastic, attr.share)
+
+			increased, decreased := attr.deserved.Diff(oldDeserved, api.Zero)
+			increasedDeserved.Add(increased)
+			decreasedDeserved.Add(decreased)
+
+			// Record metrics
+			metrics.UpdateQueueDeserved(attr.name, attr.deserved.MilliCPU, attr.deserved.Memory)
+			metrics.UpdateQueueDeservedScalar(attr.name, attr.deserved.ScalarResources)
+		}
+
+		remaining.Sub(increasedDeserved).Add(decreasedDeserved)
+		klog.V(4).Infof("Remaining resource is  <%s>", remaining)
+		if remaining.IsEmpty() || equality.Semantic.DeepEqual(remaining, oldRemaining) {
+			klog.V(4).Infof("Exiting when remaining is empty or no queue has more resource request:  <%v>", remaining)
+			break
+		}
+	}
+}
+
+// updateHierarchicalDeserved divides the total resource down the queue hierarchy, among the
+// siblings of each level by their weights, bounded by the capability of their ancestors.
+func (pp *proportionPlugin) updateHierarchicalDeserved(ssn *framework.Session) {
+	var root *queueNode
+	root, pp.queueLeaves = buildQueueHierarchy(ssn.Queues, pp.queueOpts, pp.totalResource)
+	root.distribute()
+
+	for _, attr := range pp.queueOpts {
+		pp.updateShare(attr)
+		klog.V(4).Infof("The attributes of queue <%s> in proportion: deserved <%v>, realCapability <%v>, allocate <%v>, request <%v>, elastic <%v>, share <%0.2f>",
+			attr.name, attr.deserved, attr.realCapability, attr.allocated, attr.request, attr.elastic, attr.share)
+		metrics.UpdateQueueDeserved(attr.name, attr.deserved.MilliCPU, attr.deserved.Memory)
+		metrics.UpdateQueueDeservedScalar(attr.name, attr.deserved.ScalarResources)
+	}
+}
+
+// reclaimFrees returns whether evicting the reclaimee frees a resource requested by the reclaimer
+// of which the queue is allocated more than it deserves, e.g. a task without GPUs is not evicted
+// for a task requesting GPUs.
+func reclaimFrees(allocated, deserved, reclaimer, reclaimee *api.Resource) bool {
+	names := reclaimer.ResourceNames()
+	if len(names) == 0 {
+		return true
+	}
+	for _, rn := range names {
+		if api.IsIgnoredScalarResource(rn) {
+			continue
+		}
+		if reclaimee.Get(rn) > 0 && allocated.Get(rn)-deserved.Get(rn) >= api.GetMinResource() {
+			return true
+		}
+	}
+	return false
 }
 
 func (pp *proportionPlugin) updateShare(attr *queueAttr) {
 	res := float64(0)
 
 	// TODO(k82cn): how to handle fragment issues?
-	for _, rn := range attr.deserved.ResourceNames() {
+	// the resources allocated but not deserved, e.g. GPUs, count as well
+	for _, rn := range append(attr.deserved.ResourceNames(), attr.allocated.ResourceNames()...) {
 		share := helpers.Share(attr.allocated.Get(rn), attr.deserved.Get(rn))
 		if share > res {
 			res = share
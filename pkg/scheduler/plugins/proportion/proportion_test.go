@@ -493,3 +493,71 @@ func TestAllocate(t *testing.T) {
 		})
 	}
 }
+
+func TestReclaimFrees(t *testing.T) {
+	gpu := func(cpu, memory, gpu string) *api.Resource {
+		return api.NewResource(api.BuildResourceListWithGPU(cpu, memory, gpu))
+	}
+	tests := []struct {
+		name      string
+		allocated *api.Resource
+		deserved  *api.Resource
+		reclaimer *api.Resource
+		reclaimee *api.Resource
+		expected  bool
+	}{
+		{
+			name:      "queue overuses the GPUs requested by the reclaimer",
+			allocated: gpu("4", "4Gi", "4"),
+			deserved:  gpu("4", "4Gi", "2"),
+			reclaimer: gpu("1", "1Gi", "1"),
+			reclaimee: gpu("1", "1Gi", "1"),
+			expected:  true,
+		},
+		{
+			name:      "reclaimee frees no GPU",
+			allocated: gpu("4", "4Gi", "4"),
+			deserved:  gpu("4", "4Gi", "2"),
+			reclaimer: gpu("1", "1Gi", "1"),
+			reclaimee: api.NewResource(api.BuildResourceList("1", "1Gi")),
+			expected:  false,
+		},
+		{
+			name:      "queue overuses the GPUs not requested by the reclaimer",
+			allocated: gpu("4", "4Gi", "4"),
+			deserved:  gpu("4", "4Gi", "2"),
+			reclaimer: api.NewResource(api.BuildResourceList("1", "1Gi")),
+			reclaimee: gpu("1", "1Gi", "1"),
+			expected:  false,
+		},
+		{
+			name:      "queue overuses the cpu requested by the reclaimer",
+			allocated: api.NewResource(api.BuildResourceList("4", "4Gi")),
+			deserved:  api.NewResource(api.BuildResourceList("2", "4Gi")),
+			reclaimer: api.NewResource(api.BuildResourceList("1", "1Gi")),
+			reclaimee: api.NewResource(api.BuildResourceList("1", "1Gi")),
+			expected:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := reclaimFrees(test.allocated, test.deserved, test.reclaimer, test.reclaimee); got != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestUpdateShareWithScalarResources(t *testing.T) {
+	pp := &proportionPlugin{}
+	attr := &queueAttr{
+		name:      "q1",
+		deserved:  api.NewResource(api.BuildResourceList("4", "4Gi")),
+		allocated: api.NewResource(api.BuildResourceListWithGPU("1", "1Gi", "2")),
+	}
+	pp.updateShare(attr)
+	if attr.share != 1 {
+		t.Errorf("expected share 1 of the queue allocated GPUs it does not deserve, got %v", attr.share)
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package scheduler
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,16 +27,19 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"gopkg.in/natefinch/lumberjack.v2"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	"volcano.sh/volcano/cmd/scheduler/app/options"
 	"volcano.sh/volcano/pkg/filewatcher"
+	"volcano.sh/volcano/pkg/scheduler/api"
 	schedcache "volcano.sh/volcano/pkg/scheduler/cache"
 	"volcano.sh/volcano/pkg/scheduler/conf"
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/metrics"
+	"volcano.sh/volcano/pkg/util/logging"
 )
 
 // Scheduler represents a "Volcano Scheduler".
@@ -53,6 +58,12 @@ type Scheduler struct {
 	configurations []conf.Configuration
 	metricsConf    map[string]string
 	dumper         schedcache.Dumper
+	decisionLog    *framework.DecisionLog
+
+	unschedulableTracker         *framework.UnschedulableTracker
+	unschedulableReportPeriod    time.Duration
+	unschedulableReportThreshold time.Duration
+	unschedulableReportNamespace string
 }
 
 // NewScheduler returns a Scheduler
@@ -67,6 +78,15 @@ func NewScheduler(config *rest.Config, opt *options.ServerOption) (*Scheduler, e
 		}
 	}
 
+	var decisionLog *framework.DecisionLog
+	if opt.DecisionLogFile != "" {
+		decisionLog = framework.NewDecisionLog(&lumberjack.Logger{
+			Filename:   opt.DecisionLogFile,
+			MaxSize:    opt.DecisionLogMaxSize,
+			MaxBackups: opt.DecisionLogMaxBackups,
+		})
+	}
+
 	cache := schedcache.New(config, opt.SchedulerNames, opt.DefaultQueue, opt.NodeSelector, opt.NodeWorkerThreads, opt.IgnoredCSIProvisioners)
 	scheduler := &Scheduler{
 		schedulerConf:  opt.SchedulerConf,
@@ -74,11 +94,24 @@ func NewScheduler(config *rest.Config, opt *options.ServerOption) (*Scheduler, e
 		cache:          cache,
 		schedulePeriod: opt.SchedulePeriod,
 		dumper:         schedcache.Dumper{Cache: cache, RootDir: opt.CacheDumpFileDir},
+		decisionLog:    decisionLog,
+
+		unschedulableReportPeriod:    opt.UnschedulableReportPeriod,
+		unschedulableReportThreshold: opt.UnschedulableReportThreshold,
+		unschedulableReportNamespace: opt.UnschedulableReportNamespace,
+	}
+	if opt.UnschedulableReportPeriod > 0 {
+		scheduler.unschedulableTracker = framework.NewUnschedulableTracker()
 	}
 
 	return scheduler, nil
 }
 
+// CacheDumper returns the handler serving the snapshot of the cache.
+func (pc *Scheduler) CacheDumper() http.Handler {
+	return &pc.dumper
+}
+
 // Run initializes and starts the Scheduler. It loads the configuration,
 // initializes the cache, and begins the scheduling process.
 func (pc *Scheduler) Run(stopCh <-chan struct{}) {
@@ -88,7 +121,13 @@ func (pc *Scheduler) Run(stopCh <-chan struct{}) {
 	pc.cache.SetMetricsConf(pc.metricsConf)
 	pc.cache.Run(stopCh)
 	klog.V(2).Infof("Scheduler completes Initialization and start to run")
+	if pc.decisionLog != nil {
+		go pc.decisionLog.Run(stopCh)
+	}
 	go wait.Until(pc.runOnce, pc.schedulePeriod, stopCh)
+	if pc.unschedulableTracker != nil {
+		go wait.Until(pc.publishUnschedulableReport, pc.unschedulableReportPeriod, stopCh)
+	}
 	if options.ServerOpts.EnableCacheDumper {
 		pc.dumper.ListenForSignal(stopCh)
 	}
@@ -115,6 +154,8 @@ func (pc *Scheduler) runOnce() {
 	}
 
 	ssn := framework.OpenSession(pc.cache, plugins, configurations)
+	ssn.SetDecisionLog(pc.decisionLog)
+	ssn.SetUnschedulableTracker(pc.unschedulableTracker)
 	defer func() {
 		framework.CloseSession(ssn)
 		metrics.UpdateE2eDuration(metrics.Duration(scheduleStartTime))
@@ -127,6 +168,15 @@ func (pc *Scheduler) runOnce() {
 	}
 }
 
+// publishUnschedulableReport publishes the report of the podgroups unschedulable for longer than the threshold.
+func (pc *Scheduler) publishUnschedulableReport() {
+	err := pc.unschedulableTracker.Publish(context.TODO(), pc.cache.Client(), pc.unschedulableReportNamespace, pc.unschedulableReportThreshold)
+	if err != nil {
+		klog.Errorf("Failed to publish the report of the unschedulable podgroups into %s/%s: %v",
+			pc.unschedulableReportNamespace, api.UnschedulableReportConfigMap, err)
+	}
+}
+
 func (pc *Scheduler) loadSchedulerConf() {
 	klog.V(4).Infof("Start loadSchedulerConf ...")
 	defer func() {
@@ -159,6 +209,12 @@ func (pc *Scheduler) loadSchedulerConf() {
 		klog.Errorf("Scheduler config %s is invalid: %v", config, err)
 		return
 	}
+	componentVerbosity, err := unmarshalLoggingConf(config)
+	if err != nil {
+		klog.Errorf("Logging of scheduler config %s is invalid: %v", config, err)
+		return
+	}
+	logging.SetComponentVerbosity(componentVerbosity)
 
 	pc.mutex.Lock()
 	pc.actions = actions
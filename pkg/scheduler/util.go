@@ -28,6 +28,7 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/plugins"
 	"volcano.sh/volcano/pkg/util"
+	"volcano.sh/volcano/pkg/util/logging"
 )
 
 var DefaultSchedulerConf = `
@@ -87,6 +88,21 @@ func UnmarshalSchedulerConf(confStr string) ([]framework.Action, []conf.Tier, []
 	return actions, schedulerConf.Tiers, schedulerConf.Configurations, schedulerConf.MetricsConfiguration, nil
 }
 
+// unmarshalLoggingConf returns the verbosity of the logs by component of the scheduler conf
+func unmarshalLoggingConf(confStr string) (map[string]int32, error) {
+	schedulerConf := &conf.SchedulerConfiguration{}
+	if err := yaml.Unmarshal([]byte(confStr), schedulerConf); err != nil {
+		return nil, err
+	}
+	if schedulerConf.Logging == nil {
+		return nil, nil
+	}
+	if err := logging.ValidateComponentVerbosity(schedulerConf.Logging.Components); err != nil {
+		return nil, err
+	}
+	return schedulerConf.Logging.Components, nil
+}
+
 func runSchedulerSocket() {
 	fs := flag.CommandLine
 	startKlogLevel := fs.Lookup("v").Value.String()
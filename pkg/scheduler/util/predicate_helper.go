@@ -6,10 +6,13 @@ import (
 	"sync"
 	"sync/atomic"
 
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"volcano.sh/volcano/pkg/features"
 	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
 )
 
 type PredicateHelper interface {
@@ -18,6 +21,15 @@ type PredicateHelper interface {
 
 type predicateHelper struct {
 	taskPredicateErrorCache map[string]map[string]error
+	// equivalenceCache holds the predicate results of the replicas of a task on the nodes,
+	// key_1: task group id, key_2: node name
+	equivalenceCache map[string]map[string]predicateResult
+}
+
+// predicateResult is the predicate result of a task on a node at the given generation of the node.
+type predicateResult struct {
+	generation uint64
+	err        error
 }
 
 // PredicateNodes returns the specified number of nodes that fit a task
@@ -32,6 +44,16 @@ func (ph *predicateHelper) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeI
 		enableErrorCache = false
 	}
 
+	// the replicas of a task are the tasks with the same role in a job, so the results of the tasks with
+	// an empty TaskRole are not reused either
+	enableEquivalenceCache := utilfeature.DefaultFeatureGate.Enabled(features.EquivalenceCache) && len(task.TaskRole) != 0
+	if enableEquivalenceCache && dependsOnOtherNodes(task) {
+		// the placement of the task may change the predicate results on the nodes other than its own,
+		// so neither its results nor the ones cached before it are reused
+		ph.equivalenceCache = map[string]map[string]predicateResult{}
+		enableEquivalenceCache = false
+	}
+
 	allNodes := len(nodes)
 	if allNodes == 0 {
 		return make([]*api.NodeInfo, 0), fe
@@ -49,9 +71,16 @@ func (ph *predicateHelper) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeI
 	if nodeErrorCache == nil {
 		nodeErrorCache = map[string]error{}
 	}
+	nodeResultCache := ph.equivalenceCache[taskGroupid]
+	if enableEquivalenceCache && nodeResultCache == nil {
+		nodeResultCache = map[string]predicateResult{}
+		ph.equivalenceCache[taskGroupid] = nodeResultCache
+	}
+	cacheHits, cacheMisses := int32(0), int32(0)
 
-	//create a context with cancellation
+	//create a context with cancellation, which is cancelled once enough feasible nodes are found
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	checkNode := func(index int) {
 		// Check the nodes starting from where is left off in the previous scheduling cycle,
@@ -61,23 +90,46 @@ func (ph *predicateHelper) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeI
 		klog.V(4).Infof("Considering Task <%v/%v> on node <%v>: <%v> vs. <%v>",
 			task.Namespace, task.Name, node.Name, task.Resreq, node.Idle)
 
-		// Check if the task had "predicate" failure before.
-		// And then check if the task failed to predict on this node before.
-		if enableErrorCache && taskFailedBefore {
+		// Reuse the result of a replica of the task on this node if the node did not change since.
+		var err error
+		cached := false
+		if enableEquivalenceCache {
 			errorLock.RLock()
-			errC, ok := nodeErrorCache[node.Name]
+			result, ok := nodeResultCache[node.Name]
 			errorLock.RUnlock()
 
-			if ok {
+			if ok && result.generation == node.Generation() {
+				atomic.AddInt32(&cacheHits, 1)
+				err, cached = result.err, true
+			}
+		}
+
+		if !cached {
+			// Check if the task had "predicate" failure before.
+			// And then check if the task failed to predict on this node before.
+			if enableErrorCache && taskFailedBefore {
+				errorLock.RLock()
+				errC, ok := nodeErrorCache[node.Name]
+				errorLock.RUnlock()
+
+				if ok {
+					errorLock.Lock()
+					fe.SetNodeError(node.Name, errC)
+					errorLock.Unlock()
+					return
+				}
+			}
+
+			err = fn(task, node)
+			if enableEquivalenceCache {
+				atomic.AddInt32(&cacheMisses, 1)
 				errorLock.Lock()
-				fe.SetNodeError(node.Name, errC)
+				nodeResultCache[node.Name] = predicateResult{generation: node.Generation(), err: err}
 				errorLock.Unlock()
-				return
 			}
 		}
 
-		// TODO (k82cn): Enable eCache for performance improvement.
-		if err := fn(task, node); err != nil {
+		if err != nil {
 			klog.V(3).Infof("Predicates failed: %v", err)
 			errorLock.Lock()
 			nodeErrorCache[node.Name] = err
@@ -90,15 +142,20 @@ func (ph *predicateHelper) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeI
 		//check if the number of found nodes is more than the numNodesTofind
 		length := atomic.AddInt32(&numFoundNodes, 1)
 		if length > numNodesToFind {
-			cancel()
 			atomic.AddInt32(&numFoundNodes, -1)
-		} else {
-			predicateNodes[length-1] = node
+			return
+		}
+		predicateNodes[length-1] = node
+		// stop checking the rest of the nodes as soon as enough feasible nodes are found
+		if length == numNodesToFind {
+			cancel()
 		}
 	}
 
-	//workqueue.ParallelizeUntil(context.TODO(), 16, len(nodes), checkNode)
-	workqueue.ParallelizeUntil(ctx, 16, allNodes, checkNode)
+	workqueue.ParallelizeUntil(ctx, Parallelism(), allNodes, checkNode)
+	if enableEquivalenceCache {
+		metrics.UpdatePredicateEquivalenceCache(int(cacheHits), int(cacheMisses))
+	}
 
 	//processedNodes := int(numFoundNodes) + len(filteredNodesStatuses) + len(failedPredicateMap)
 	lastProcessedNodeIndex = (lastProcessedNodeIndex + int(processedNodes)) % allNodes
@@ -110,6 +167,21 @@ func taskGroupID(task *api.TaskInfo) string {
 	return fmt.Sprintf("%s/%s", task.Job, task.TaskRole)
 }
 
+// dependsOnOtherNodes returns whether the predicate results of the task on a node depend on the pods of the other
+// nodes, i.e. the task has inter-pod affinity, anti-affinity or topology spread constraints.
+func dependsOnOtherNodes(task *api.TaskInfo) bool {
+	if task.Pod == nil {
+		return false
+	}
+	if affinity := task.Pod.Spec.Affinity; affinity != nil && (affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil) {
+		return true
+	}
+	return len(task.Pod.Spec.TopologySpreadConstraints) != 0
+}
+
 func NewPredicateHelper() PredicateHelper {
-	return &predicateHelper{taskPredicateErrorCache: map[string]map[string]error{}}
+	return &predicateHelper{
+		taskPredicateErrorCache: map[string]map[string]error{},
+		equivalenceCache:        map[string]map[string]predicateResult{},
+	}
 }
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"volcano.sh/volcano/cmd/scheduler/app/options"
+	"volcano.sh/volcano/pkg/features"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestPredicateNodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		parallelism int32
+		numNodes    int
+		// infeasible returns whether the node of the index fails the predicates
+		infeasible    func(index int) bool
+		wantFound     int
+		wantProcessed int32
+	}{
+		{
+			name:          "all the nodes are checked when there are few nodes",
+			parallelism:   16,
+			numNodes:      50,
+			wantFound:     50,
+			wantProcessed: 50,
+		},
+		{
+			name:          "stop once enough feasible nodes are found with one worker",
+			parallelism:   1,
+			numNodes:      1000,
+			wantFound:     420,
+			wantProcessed: 420,
+		},
+		{
+			name:          "skip the infeasible nodes until enough feasible nodes are found",
+			parallelism:   1,
+			numNodes:      1000,
+			infeasible:    func(index int) bool { return index%2 == 0 },
+			wantFound:     420,
+			wantProcessed: 840,
+		},
+		{
+			name:          "find the feasible nodes of a large cluster with many workers",
+			parallelism:   16,
+			numNodes:      6000,
+			infeasible:    func(index int) bool { return index%3 == 0 },
+			wantFound:     300,
+			wantProcessed: -1,
+		},
+		{
+			name:          "return fewer nodes when not enough nodes are feasible",
+			parallelism:   8,
+			numNodes:      1000,
+			infeasible:    func(index int) bool { return index >= 100 },
+			wantFound:     100,
+			wantProcessed: 1000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options.ServerOpts = &options.ServerOption{
+				MinPercentageOfNodesToFind: 5,
+				MinNodesToFind:             100,
+				Parallelism:                tt.parallelism,
+			}
+			lastProcessedNodeIndex = 0
+
+			nodes := make([]*api.NodeInfo, tt.numNodes)
+			infeasible := map[string]bool{}
+			for i := range nodes {
+				nodes[i] = &api.NodeInfo{Name: fmt.Sprintf("node-%d", i)}
+				if tt.infeasible != nil && tt.infeasible(i) {
+					infeasible[nodes[i].Name] = true
+				}
+			}
+			var processed int32
+			fn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+				atomic.AddInt32(&processed, 1)
+				if infeasible[node.Name] {
+					return fmt.Errorf("node %s is infeasible", node.Name)
+				}
+				return nil
+			}
+
+			task := &api.TaskInfo{Namespace: "default", Name: "task", Job: "default/job", TaskRole: "worker"}
+			found, fitErrors := NewPredicateHelper().PredicateNodes(task, nodes, fn, true)
+			if len(found) != tt.wantFound {
+				t.Errorf("expected %d feasible nodes, got %d", tt.wantFound, len(found))
+			}
+			for _, node := range found {
+				if node == nil || infeasible[node.Name] {
+					t.Errorf("unexpected node %v in the feasible nodes", node)
+				}
+			}
+			if tt.wantProcessed >= 0 && processed != tt.wantProcessed {
+				t.Errorf("expected %d nodes to be checked, got %d", tt.wantProcessed, processed)
+			}
+			if int(processed) < len(found)+len(fitErrors.NodeReasons()) {
+				t.Errorf("checked %d nodes, fewer than the %d feasible and %d infeasible ones", processed, len(found), len(fitErrors.NodeReasons()))
+			}
+		})
+	}
+}
+
+func TestPredicateNodesEquivalenceCache(t *testing.T) {
+	antiAffinity := &v1.Pod{Spec: v1.PodSpec{Affinity: &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{}}}}
+	replica := func(name, role string, pod *v1.Pod) *api.TaskInfo {
+		return &api.TaskInfo{Namespace: "default", Name: name, Job: "default/job", TaskRole: role, Pod: pod}
+	}
+
+	type step struct {
+		task *api.TaskInfo
+		// changed is the index of the node changed before the predicates
+		changed   int
+		wantCalls int32
+	}
+	tests := []struct {
+		name    string
+		enabled bool
+		steps   []step
+	}{
+		{
+			name: "the results are not reused when the feature is disabled",
+			steps: []step{
+				{task: replica("worker-0", "worker", nil), changed: -1, wantCalls: 10},
+				{task: replica("worker-1", "worker", nil), changed: -1, wantCalls: 10},
+			},
+		},
+		{
+			name:    "the results are reused for the replicas on the nodes not changed since",
+			enabled: true,
+			steps: []step{
+				{task: replica("worker-0", "worker", nil), changed: -1, wantCalls: 10},
+				{task: replica("worker-1", "worker", nil), changed: -1, wantCalls: 0},
+				{task: replica("worker-2", "worker", nil), changed: 3, wantCalls: 1},
+				{task: replica("ps-0", "ps", nil), changed: -1, wantCalls: 10},
+			},
+		},
+		{
+			name:    "the results of the tasks without role are not reused",
+			enabled: true,
+			steps: []step{
+				{task: replica("pod-0", "", nil), changed: -1, wantCalls: 10},
+				{task: replica("pod-1", "", nil), changed: -1, wantCalls: 10},
+			},
+		},
+		{
+			name:    "the results are not reused after a task with inter-pod constraints",
+			enabled: true,
+			steps: []step{
+				{task: replica("worker-0", "worker", nil), changed: -1, wantCalls: 10},
+				{task: replica("master-0", "master", antiAffinity), changed: -1, wantCalls: 10},
+				{task: replica("master-1", "master", antiAffinity), changed: -1, wantCalls: 10},
+				{task: replica("worker-1", "worker", nil), changed: -1, wantCalls: 10},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EquivalenceCache, tt.enabled)()
+			options.ServerOpts = &options.ServerOption{
+				MinPercentageOfNodesToFind: 5,
+				MinNodesToFind:             100,
+				Parallelism:                4,
+			}
+
+			nodes := make([]*api.NodeInfo, 10)
+			for i := range nodes {
+				nodes[i] = &api.NodeInfo{Name: fmt.Sprintf("node-%d", i)}
+			}
+			var calls int32
+			fn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+				atomic.AddInt32(&calls, 1)
+				if node.Name == "node-0" {
+					return fmt.Errorf("node %s is infeasible", node.Name)
+				}
+				return nil
+			}
+
+			ph := NewPredicateHelper()
+			for i, step := range tt.steps {
+				if step.changed >= 0 {
+					nodes[step.changed].MarkChanged()
+				}
+				calls = 0
+				found, fitErrors := ph.PredicateNodes(step.task, nodes, fn, false)
+				if calls != step.wantCalls {
+					t.Errorf("step %d: expected %d predicate calls, got %d", i, step.wantCalls, calls)
+				}
+				if len(found) != 9 || len(fitErrors.NodeReasons()) != 1 {
+					t.Errorf("step %d: expected 9 feasible nodes and 1 infeasible one, got %d and %d", i, len(found), len(fitErrors.NodeReasons()))
+				}
+			}
+		})
+	}
+}
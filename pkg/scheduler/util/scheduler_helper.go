@@ -34,7 +34,9 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/api"
 )
 
-const baselinePercentageOfNodesToFind = 50
+const (
+	baselinePercentageOfNodesToFind = 50
+)
 
 var lastProcessedNodeIndex int
 
@@ -61,6 +63,14 @@ func CalculateNumOfFeasibleNodesToFind(numAllNodes int32) (numNodes int32) {
 	return numNodes
 }
 
+// Parallelism returns the number of workers the predicates and the scores of a task are evaluated with across the nodes.
+func Parallelism() int {
+	if opts := options.ServerOpts; opts != nil && opts.Parallelism > 0 {
+		return int(opts.Parallelism)
+	}
+	return options.DefaultParallelism
+}
+
 // PrioritizeNodes returns a map whose key is node's score and value are corresponding nodes
 func PrioritizeNodes(task *api.TaskInfo, nodes []*api.NodeInfo, batchFn api.BatchNodeOrderFn, mapFn api.NodeOrderMapFn, reduceFn api.NodeOrderReduceFn) map[float64][]*api.NodeInfo {
 	pluginNodeScoreMap := map[string]k8sframework.NodeScoreList{}
@@ -89,7 +99,7 @@ func PrioritizeNodes(task *api.TaskInfo, nodes []*api.NodeInfo, batchFn api.Batc
 		nodeOrderScoreMap[node.Name] = orderScore
 		workerLock.Unlock()
 	}
-	workqueue.ParallelizeUntil(context.TODO(), 16, len(nodes), scoreNode)
+	workqueue.ParallelizeUntil(context.TODO(), Parallelism(), len(nodes), scoreNode)
 	reduceScores, err := reduceFn(task, pluginNodeScoreMap)
 	if err != nil {
 		klog.Errorf("Error in Calculating Priority for the node:%v", err)
@@ -214,3 +214,49 @@ tiers:
 			expectedConfigurations, configurations)
 	}
 }
+
+func TestUnmarshalLoggingConf(t *testing.T) {
+	testCases := []struct {
+		name      string
+		conf      string
+		expected  map[string]int32
+		expectErr bool
+	}{
+		{
+			name: "verbosity by component",
+			conf: `
+actions: "enqueue, allocate"
+logging:
+  components:
+    allocate: 5
+    proportion: 4
+`,
+			expected: map[string]int32{"allocate": 5, "proportion": 4},
+		},
+		{
+			name: "no logging",
+			conf: `actions: "enqueue, allocate"`,
+		},
+		{
+			name: "negative verbosity",
+			conf: `
+logging:
+  components:
+    allocate: -1
+`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := unmarshalLoggingConf(tc.conf)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if !equality.Semantic.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpauth authenticates and authorizes the requests of the debug endpoints with the
+// Kubernetes API server, so that they are granted by RBAC like the other endpoints of the cluster.
+package httpauth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/options"
+	"k8s.io/klog/v2"
+)
+
+// NewDelegatingAuth returns the authenticator of the bearer tokens, by TokenReviews, and the authorizer
+// of the users, by SubjectAccessReviews, of the API server the kubeconfig connects to, or of the cluster
+// if it is empty. Their answers are cached for a few seconds, so that a client polling the endpoint does
+// not send a review to the API server on every request.
+func NewDelegatingAuth(kubeConfig string) (authenticator.Request, authorizer.Authorizer, error) {
+	authenticationOptions := options.NewDelegatingAuthenticationOptions()
+	authenticationOptions.RemoteKubeConfigFile = kubeConfig
+	authenticationOptions.DisableAnonymous = true
+	// only the bearer tokens are authenticated, not the client certificates nor the request headers
+	authenticationOptions.SkipInClusterLookup = true
+	authenticationInfo := server.AuthenticationInfo{}
+	if err := authenticationOptions.ApplyTo(&authenticationInfo, nil, nil); err != nil {
+		return nil, nil, fmt.Errorf("failed to create the delegating authenticator: %v", err)
+	}
+
+	authorizationOptions := options.NewDelegatingAuthorizationOptions()
+	authorizationOptions.RemoteKubeConfigFile = kubeConfig
+	authorizationOptions.AlwaysAllowPaths = nil
+	authorizationInfo := server.AuthorizationInfo{}
+	if err := authorizationOptions.ApplyTo(&authorizationInfo); err != nil {
+		return nil, nil, fmt.Errorf("failed to create the delegating authorizer: %v", err)
+	}
+	return authenticationInfo.Authenticator, authorizationInfo.Authorizer, nil
+}
+
+// WithDelegatedAuth serves the requests whose user is authenticated and may access the path of the
+// request as a non-resource URL, e.g. `get` on `/debug/cache`.
+func WithDelegatedAuth(authn authenticator.Request, authz authorizer.Authorizer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok, err := authn.AuthenticateRequest(r)
+		if err != nil {
+			klog.V(3).Infof("Failed to authenticate the request to %s: %v", r.URL.Path, err)
+		}
+		if err != nil || !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user := response.User
+		decision, reason, err := authz.Authorize(r.Context(), authorizer.AttributesRecord{
+			User:            user,
+			Verb:            strings.ToLower(r.Method),
+			Path:            r.URL.Path,
+			ResourceRequest: false,
+		})
+		if err != nil {
+			klog.Errorf("Failed to authorize the access of %s to %s: %v", user.GetName(), r.URL.Path, err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if decision != authorizer.DecisionAllow {
+			klog.V(3).Infof("Denied the access of %s to %s: %s", user.GetName(), r.URL.Path, reason)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+func TestWithDelegatedAuth(t *testing.T) {
+	tokens := map[string]string{"admin-token": "admin", "viewer-token": "viewer"}
+	allowed := map[string]string{"admin": "/debug/cache", "viewer": "/debug/other"}
+	authn := authenticator.RequestFunc(func(r *http.Request) (*authenticator.Response, bool, error) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found {
+			return nil, false, nil
+		}
+		name, found := tokens[token]
+		if !found {
+			return nil, false, nil
+		}
+		return &authenticator.Response{User: &user.DefaultInfo{Name: name}}, true, nil
+	})
+	authz := authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		if !a.IsResourceRequest() && a.GetVerb() == "get" && allowed[a.GetUser().GetName()] == a.GetPath() {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "", nil
+	})
+	handler := WithDelegatedAuth(authn, authz, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{
+			name:     "no token",
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:          "not a bearer token",
+			authorization: "Basic YWRtaW46YWRtaW4=",
+			expected:      http.StatusUnauthorized,
+		},
+		{
+			name:          "unknown token",
+			authorization: "Bearer unknown",
+			expected:      http.StatusUnauthorized,
+		},
+		{
+			name:          "user not allowed",
+			authorization: "Bearer viewer-token",
+			expected:      http.StatusForbidden,
+		},
+		{
+			name:          "user allowed",
+			authorization: "Bearer admin-token",
+			expected:      http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/debug/cache", nil)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tc.expected {
+				t.Errorf("expected status %d, got %d", tc.expected, recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging writes the structured logs of the volcano components. The logs are written by klog, in its
+// text format or as JSON, by component loggers whose verbosity may be set by component, e.g. an action of the
+// scheduler or a controller, and reloaded at runtime; the components without a verbosity of their own follow
+// the -v flag.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/pflag"
+	logsjson "k8s.io/component-base/logs/json"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// TextFormat is the text format of klog
+	TextFormat = "text"
+	// JSONFormat writes a JSON object per message
+	JSONFormat = "json"
+
+	// JobKey is the key of the namespace/name of a job
+	JobKey = "job"
+	// QueueKey is the key of the name of a queue
+	QueueKey = "queue"
+	// PodGroupKey is the key of the namespace/name of a podgroup
+	PodGroupKey = "podgroup"
+	// PodKey is the key of the namespace/name of a pod
+	PodKey = "pod"
+	// TaskKey is the key of the name of a task of a job
+	TaskKey = "task"
+	// NodeKey is the key of the name of a node
+	NodeKey = "node"
+
+	// maxVerbosity lets the JSON logger write all the messages, which klog filters by verbosity beforehand.
+	maxVerbosity = 127
+)
+
+// componentVerbosity holds the map[string]int32 of the verbosity of the components which do not follow the -v flag.
+var componentVerbosity atomic.Value
+
+// Options configures the format of the logs of a component
+type Options struct {
+	// Format is the format of the logs, text or json.
+	Format string
+}
+
+// AddFlags adds the logging flags to the specified FlagSet
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Format, "logging-format", TextFormat, "The format of the logs, text or json; the klog flags on the output of the logs, e.g. --log-file, only apply to the text format")
+}
+
+// Validate checks the logging options
+func (o *Options) Validate() error {
+	if o.Format != TextFormat && o.Format != JSONFormat {
+		return fmt.Errorf("logging format %q must be %s or %s", o.Format, TextFormat, JSONFormat)
+	}
+	return nil
+}
+
+// Setup makes klog write the logs in the format of the options, before anything is logged.
+func Setup(o Options) {
+	if o.Format != JSONFormat {
+		return
+	}
+	logger, control := logsjson.NewJSONLogger(maxVerbosity, logsjson.AddNopSync(os.Stderr), nil, nil)
+	klog.SetLoggerWithOptions(logger, klog.FlushLogger(control.Flush))
+}
+
+// ValidateComponentVerbosity checks the verbosity of the components.
+func ValidateComponentVerbosity(verbosity map[string]int32) error {
+	for component, level := range verbosity {
+		if level < 0 {
+			return fmt.Errorf("log verbosity of component %s must not be negative, got %d", component, level)
+		}
+	}
+	return nil
+}
+
+// SetComponentVerbosity sets the verbosity of the logs of the components, replacing the previous ones; the
+// components which are not in verbosity follow the -v flag.
+func SetComponentVerbosity(verbosity map[string]int32) {
+	v := make(map[string]int32, len(verbosity))
+	for component, level := range verbosity {
+		v[component] = level
+	}
+	componentVerbosity.Store(v)
+}
+
+// Enabled returns whether the messages of the component at the level are logged.
+func Enabled(component string, level int) bool {
+	if v, ok := componentVerbosity.Load().(map[string]int32); ok {
+		if verbosity, found := v[component]; found {
+			return level <= int(verbosity)
+		}
+	}
+	return klog.V(klog.Level(level)).Enabled()
+}
+
+// Logger returns the logger of the component, e.g. the name of an action, of a plugin or of a controller.
+func Logger(component string) klog.Logger {
+	return logr.New(&componentSink{component: component, name: component})
+}
+
+// componentSink writes the messages of a component through klog, once it checked their level against the
+// verbosity of the component, so that the klog verbosity does not filter them again.
+type componentSink struct {
+	component string
+	// name is the component and the names added by WithName, joined by "/".
+	name   string
+	values []interface{}
+	depth  int
+}
+
+var _ logr.CallDepthLogSink = &componentSink{}
+
+func (s *componentSink) Init(info logr.RuntimeInfo) {
+	s.depth += info.CallDepth
+}
+
+func (s *componentSink) Enabled(level int) bool {
+	return Enabled(s.component, level)
+}
+
+func (s *componentSink) Info(_ int, msg string, keysAndValues ...interface{}) {
+	klog.InfoSDepth(s.depth+1, msg, s.keysAndValues(keysAndValues)...)
+}
+
+func (s *componentSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	klog.ErrorSDepth(s.depth+1, err, msg, s.keysAndValues(keysAndValues)...)
+}
+
+func (s *componentSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	c := *s
+	c.values = append(append(make([]interface{}, 0, len(s.values)+len(keysAndValues)), s.values...), keysAndValues...)
+	return &c
+}
+
+func (s *componentSink) WithName(name string) logr.LogSink {
+	c := *s
+	c.name = s.name + "/" + name
+	return &c
+}
+
+func (s *componentSink) WithCallDepth(depth int) logr.LogSink {
+	c := *s
+	c.depth += depth
+	return &c
+}
+
+// keysAndValues returns the keys and values of a message, led by the name of the logger and the values of
+// the sink.
+func (s *componentSink) keysAndValues(keysAndValues []interface{}) []interface{} {
+	kv := make([]interface{}, 0, 2+len(s.values)+len(keysAndValues))
+	kv = append(kv, "logger", s.name)
+	kv = append(kv, s.values...)
+	return append(kv, keysAndValues...)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		format    string
+		expectErr bool
+	}{
+		{format: TextFormat},
+		{format: JSONFormat},
+		{format: "xml", expectErr: true},
+		{format: "", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			o := Options{Format: tc.format}
+			if err := o.Validate(); (err != nil) != tc.expectErr {
+				t.Errorf("expected error: %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	SetComponentVerbosity(map[string]int32{"allocate": 4})
+	defer SetComponentVerbosity(nil)
+
+	testCases := []struct {
+		name      string
+		component string
+		level     int
+		expected  bool
+	}{
+		{
+			name:      "level of the component",
+			component: "allocate",
+			level:     4,
+			expected:  true,
+		},
+		{
+			name:      "above the level of the component",
+			component: "allocate",
+			level:     5,
+		},
+		{
+			name:      "component following -v",
+			component: "proportion",
+			level:     0,
+			expected:  true,
+		},
+		{
+			name:      "above -v",
+			component: "proportion",
+			level:     1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Enabled(tc.component, tc.level); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+			if got := Logger(tc.component).V(tc.level).Enabled(); got != tc.expected {
+				t.Errorf("expected logger enabled: %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestComponentSink(t *testing.T) {
+	logger := Logger("allocate").WithName("topology").WithValues(JobKey, "ns/job")
+	sink, ok := logger.GetSink().(*componentSink)
+	if !ok {
+		t.Fatalf("expected a component sink, got %T", logger.GetSink())
+	}
+
+	if sink.component != "allocate" {
+		t.Errorf("expected component allocate, got %s", sink.component)
+	}
+	expected := []interface{}{"logger", "allocate/topology", JobKey, "ns/job", TaskKey, "ns/task"}
+	if got := sink.keysAndValues([]interface{}{TaskKey, "ns/task"}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected keys and values %v, got %v", expected, got)
+	}
+}
+
+func TestValidateComponentVerbosity(t *testing.T) {
+	if err := ValidateComponentVerbosity(map[string]int32{"allocate": 5}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := ValidateComponentVerbosity(map[string]int32{"allocate": -1}); err == nil {
+		t.Errorf("expected an error on negative verbosity")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing traces a job across the volcano components with OpenTelemetry. The trace context is
+// propagated from the job to its podgroup and pods by an annotation, so that the spans of the webhook,
+// the job controller and the scheduler on the same job share one trace.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	basetracing "k8s.io/component-base/tracing"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// TraceParentKey is the annotation carrying the W3C trace context of the trace an object belongs to
+	TraceParentKey = "volcano.sh/traceparent"
+
+	traceParentHeader = "traceparent"
+	tracerName        = "volcano.sh/volcano"
+	maxSamplingRate   = 1000000
+)
+
+// Options configures the export of the spans of a component
+type Options struct {
+	// Endpoint is the OTLP gRPC endpoint the spans are exported to, the tracing is disabled when it is empty.
+	Endpoint string
+	// SamplingRatePerMillion is the number of traces sampled per million of traces started by the component,
+	// the spans continuing a trace started by another component follow its sampling decision.
+	SamplingRatePerMillion int32
+}
+
+// AddFlags adds the tracing flags to the specified FlagSet
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Endpoint, "tracing-endpoint", o.Endpoint, "The OTLP gRPC endpoint, e.g. otel-collector:4317, the spans of the jobs are exported to; tracing is disabled when it is empty")
+	fs.Int32Var(&o.SamplingRatePerMillion, "tracing-sampling-rate-per-million", o.SamplingRatePerMillion, "The number of traces sampled per million of traces started by the component")
+}
+
+// Validate checks the tracing options
+func (o *Options) Validate() error {
+	if o.SamplingRatePerMillion < 0 || o.SamplingRatePerMillion > maxSamplingRate {
+		return fmt.Errorf("tracing sampling rate per million %d must be within [0, %d]", o.SamplingRatePerMillion, maxSamplingRate)
+	}
+	return nil
+}
+
+// Setup sets the global tracer provider of the component named service up, exporting its spans to the endpoint
+// of the options. It returns the function flushing and stopping the export, a no-op when tracing is disabled.
+func Setup(ctx context.Context, o Options, service string) (func(context.Context) error, error) {
+	if o.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	endpoint, rate := o.Endpoint, o.SamplingRatePerMillion
+	tp, err := basetracing.NewProvider(ctx, &tracingapi.TracingConfiguration{
+		Endpoint:               &endpoint,
+		SamplingRatePerMillion: &rate,
+	}, nil, []resource.Option{resource.WithAttributes(semconv.ServiceName(service))})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the tracer provider exporting to %s: %v", o.Endpoint, err)
+	}
+	otel.SetTracerProvider(tp)
+	klog.V(2).Infof("Tracing %s to %s, sampling %d traces per million", service, o.Endpoint, o.SamplingRatePerMillion)
+	return tp.Shutdown, nil
+}
+
+// Start starts a span, the child of the span in ctx if any
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// RecordSpan records a span which has already happened, e.g. the time an object spent in a phase
+func RecordSpan(ctx context.Context, name string, start, end time.Time, opts ...trace.SpanStartOption) {
+	_, span := Start(ctx, name, append(opts, trace.WithTimestamp(start))...)
+	span.End(trace.WithTimestamp(end))
+}
+
+// Extract returns ctx with the trace context carried by the annotations as its remote parent, or ctx if
+// they do not carry any.
+func Extract(ctx context.Context, annotations map[string]string) context.Context {
+	traceParent, found := annotations[TraceParentKey]
+	if !found {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{traceParentHeader: traceParent})
+}
+
+// TraceParent returns the W3C trace context of the span in ctx, or "" if there is no valid span in ctx,
+// e.g. when the tracing is disabled.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier[traceParentHeader]
+}
+
+// Inject returns the annotations carrying the trace context of the span in ctx. The annotations are
+// copied instead of changed, as they usually belong to an object of an informer cache.
+func Inject(ctx context.Context, annotations map[string]string) map[string]string {
+	traceParent := TraceParent(ctx)
+	if traceParent == "" || annotations[TraceParentKey] == traceParent {
+		return annotations
+	}
+	ret := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		ret[k] = v
+	}
+	ret[TraceParentKey] = traceParent
+	return ret
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract(t *testing.T) {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+	sc := span.SpanContext()
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			name:        "no span",
+			ctx:         context.Background(),
+			annotations: map[string]string{"a": "b"},
+			expected:    "",
+		},
+		{
+			name:        "span",
+			ctx:         ctx,
+			annotations: map[string]string{"a": "b"},
+			expected:    fmt.Sprintf("00-%s-%s-01", sc.TraceID(), sc.SpanID()),
+		},
+		{
+			name:        "span without annotations",
+			ctx:         ctx,
+			annotations: nil,
+			expected:    fmt.Sprintf("00-%s-%s-01", sc.TraceID(), sc.SpanID()),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := Inject(tc.ctx, tc.annotations)
+			if annotations[TraceParentKey] != tc.expected {
+				t.Errorf("expected trace parent %q, got %q", tc.expected, annotations[TraceParentKey])
+			}
+			if _, found := tc.annotations[TraceParentKey]; found {
+				t.Errorf("expected the annotations not to be changed")
+			}
+			if tc.annotations != nil && annotations["a"] != "b" {
+				t.Errorf("expected the annotations to be kept, got %v", annotations)
+			}
+
+			extracted := trace.SpanContextFromContext(Extract(context.Background(), annotations))
+			if tc.expected == "" {
+				if extracted.IsValid() {
+					t.Errorf("expected no span context, got %v", extracted)
+				}
+				return
+			}
+			if extracted.TraceID() != sc.TraceID() || extracted.SpanID() != sc.SpanID() || !extracted.IsRemote() || !extracted.IsSampled() {
+				t.Errorf("expected the remote span context of %v, got %v", sc, extracted)
+			}
+		})
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rate      int32
+		expectErr bool
+	}{
+		{name: "never", rate: 0},
+		{name: "always", rate: 1000000},
+		{name: "negative", rate: -1, expectErr: true},
+		{name: "more than a million", rate: 1000001, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &Options{SamplingRatePerMillion: tc.rate}
+			if err := o.Validate(); (err != nil) != tc.expectErr {
+				t.Errorf("expected error %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
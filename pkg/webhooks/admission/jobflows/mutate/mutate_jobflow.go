@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	whv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/volcano/pkg/webhooks/router"
+	"volcano.sh/volcano/pkg/webhooks/schema"
+	"volcano.sh/volcano/pkg/webhooks/util"
+)
+
+func init() {
+	router.RegisterAdmission(service)
+}
+
+var service = &router.AdmissionService{
+	Path:   "/jobflows/mutate",
+	Func:   JobFlows,
+	Config: config,
+	MutatingConfig: &whv1.MutatingWebhookConfiguration{
+		Webhooks: []whv1.MutatingWebhook{{
+			Name: "mutatejobflow.volcano.sh",
+			Rules: []whv1.RuleWithOperations{
+				{
+					Operations: []whv1.OperationType{whv1.Create},
+					Rule: whv1.Rule{
+						APIGroups:   []string{flowv1alpha1.SchemeGroupVersion.Group},
+						APIVersions: []string{flowv1alpha1.SchemeGroupVersion.Version},
+						Resources:   []string{"jobflows"},
+					},
+				},
+			},
+		}},
+	},
+}
+
+var config = &router.AdmissionServiceConfig{}
+
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// JobFlows mutate jobflows.
+func JobFlows(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	klog.V(3).Infof("Mutating %s jobflow %s.", ar.Request.Operation, ar.Request.Name)
+
+	jobflow, err := schema.DecodeJobFlow(ar.Request.Object, ar.Request.Resource)
+	if err != nil {
+		return util.ToAdmissionResponse(err)
+	}
+
+	var patchBytes []byte
+	switch ar.Request.Operation {
+	case admissionv1.Create:
+		patchBytes, err = createJobFlowPatch(jobflow)
+	default:
+		return util.ToAdmissionResponse(fmt.Errorf("invalid operation `%s`, "+
+			"expect operation to be `CREATE`", ar.Request.Operation))
+	}
+
+	if err != nil {
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &metav1.Status{Message: err.Error()},
+		}
+	}
+
+	reviewResponse := admissionv1.AdmissionResponse{
+		Allowed: true,
+		Patch:   patchBytes,
+	}
+	if len(patchBytes) > 0 {
+		pt := admissionv1.PatchTypeJSONPatch
+		reviewResponse.PatchType = &pt
+		util.AddPatchAuditAnnotation(&reviewResponse, patchBytes)
+	}
+	return &reviewResponse
+}
+
+func createJobFlowPatch(jobflow *flowv1alpha1.JobFlow) ([]byte, error) {
+	var patch []patchOperation
+	if jobflow.Spec.JobRetainPolicy == "" {
+		patch = append(patch, patchOperation{
+			Op:    "add",
+			Path:  "/spec/jobRetainPolicy",
+			Value: flowv1alpha1.Retain,
+		})
+	}
+	// a flow without targets runs once the jobflow starts, drop the empty dependency
+	for i, flow := range jobflow.Spec.Flows {
+		if flow.DependsOn != nil && len(flow.DependsOn.Targets) == 0 && flow.DependsOn.Probe == nil {
+			patch = append(patch, patchOperation{
+				Op:   "remove",
+				Path: fmt.Sprintf("/spec/flows/%d/dependsOn", i),
+			})
+		}
+	}
+
+	if len(patch) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(patch)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+	"sort"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	whv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/webhooks/router"
+	"volcano.sh/volcano/pkg/webhooks/schema"
+	"volcano.sh/volcano/pkg/webhooks/util"
+)
+
+func init() {
+	router.RegisterAdmission(service)
+}
+
+var service = &router.AdmissionService{
+	Path: "/jobflows/validate",
+	Func: AdmitJobFlows,
+
+	Config: config,
+
+	ValidatingConfig: &whv1.ValidatingWebhookConfiguration{
+		Webhooks: []whv1.ValidatingWebhook{{
+			Name: "validatejobflow.volcano.sh",
+			Rules: []whv1.RuleWithOperations{
+				{
+					Operations: []whv1.OperationType{whv1.Create, whv1.Update},
+					Rule: whv1.Rule{
+						APIGroups:   []string{flowv1alpha1.SchemeGroupVersion.Group},
+						APIVersions: []string{flowv1alpha1.SchemeGroupVersion.Version},
+						Resources:   []string{"jobflows"},
+					},
+				},
+			},
+		}},
+	},
+}
+
+var config = &router.AdmissionServiceConfig{}
+
+// AdmitJobFlows is to admit jobflows and return response.
+func AdmitJobFlows(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	klog.V(3).Infof("Admitting %s jobflow %s.", ar.Request.Operation, ar.Request.Name)
+
+	jobflow, err := schema.DecodeJobFlow(ar.Request.Object, ar.Request.Resource)
+	if err != nil {
+		return util.ToAdmissionResponse(err)
+	}
+
+	switch ar.Request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		err = validateJobFlow(jobflow)
+	default:
+		return util.ToAdmissionResponse(fmt.Errorf("invalid operation `%s`, "+
+			"expect operation to be `CREATE` or `UPDATE`", ar.Request.Operation))
+	}
+
+	if err != nil {
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &metav1.Status{Message: err.Error()},
+		}
+	}
+
+	return &admissionv1.AdmissionResponse{
+		Allowed: true,
+	}
+}
+
+func validateJobFlow(jobflow *flowv1alpha1.JobFlow) error {
+	errs := field.ErrorList{}
+	flowsPath := field.NewPath("spec").Child("flows")
+
+	switch jobflow.Spec.JobRetainPolicy {
+	case "", flowv1alpha1.Retain, flowv1alpha1.Delete:
+	default:
+		errs = append(errs, field.NotSupported(field.NewPath("spec").Child("jobRetainPolicy"),
+			jobflow.Spec.JobRetainPolicy, []string{string(flowv1alpha1.Retain), string(flowv1alpha1.Delete)}))
+	}
+
+	if len(jobflow.Spec.Flows) == 0 {
+		errs = append(errs, field.Required(flowsPath, "jobflow must have at least one flow"))
+	}
+
+	names := map[string]bool{}
+	for i, flow := range jobflow.Spec.Flows {
+		if names[flow.Name] {
+			errs = append(errs, field.Duplicate(flowsPath.Index(i).Child("name"), flow.Name))
+			continue
+		}
+		names[flow.Name] = true
+		// the jobs of the flows are named <jobflow>-<flow>
+		for _, msg := range validation.IsDNS1123Label(jobflow.Name + "-" + flow.Name) {
+			errs = append(errs, field.Invalid(flowsPath.Index(i).Child("name"), flow.Name,
+				fmt.Sprintf("name of the job created for the flow is invalid: %s", msg)))
+		}
+	}
+
+	for i, flow := range jobflow.Spec.Flows {
+		if flow.DependsOn == nil {
+			continue
+		}
+		for j, target := range flow.DependsOn.Targets {
+			targetPath := flowsPath.Index(i).Child("dependsOn", "targets").Index(j)
+			if target == flow.Name {
+				errs = append(errs, field.Invalid(targetPath, target, "flow cannot depend on itself"))
+			} else if !names[target] {
+				errs = append(errs, field.NotFound(targetPath, target))
+			}
+		}
+	}
+
+	if cycle := findCycle(jobflow.Spec.Flows); len(cycle) != 0 {
+		errs = append(errs, field.Invalid(flowsPath, cycle,
+			"dependencies between flows must form a directed acyclic graph(DAG)"))
+	}
+
+	values, err := jobhelpers.GetAllFlowTemplateValues(jobflow)
+	annotationPath := field.NewPath("metadata").Child("annotations").Key(jobhelpers.FlowTemplateValuesKey)
+	if err != nil {
+		errs = append(errs, field.Invalid(annotationPath, jobflow.Annotations[jobhelpers.FlowTemplateValuesKey], err.Error()))
+	}
+	var unknown []string
+	for name := range values {
+		if !names[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		errs = append(errs, field.Invalid(annotationPath, unknown, "parameter values are given for unknown flows"))
+	}
+
+	if len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// findCycle returns the flows of a dependency cycle, sorted by name, or nil if the flows form a DAG.
+// Unknown targets are ignored as they are reported separately.
+func findCycle(flows []flowv1alpha1.Flow) []string {
+	deps := map[string][]string{}
+	for _, flow := range flows {
+		if flow.DependsOn != nil {
+			deps[flow.Name] = append(deps[flow.Name], flow.DependsOn.Targets...)
+		} else if _, found := deps[flow.Name]; !found {
+			deps[flow.Name] = nil
+		}
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var stack []string
+	var cycle []string
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, target := range deps[name] {
+			if _, found := deps[target]; !found || target == name {
+				continue
+			}
+			switch state[target] {
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					cycle = append(cycle, stack[i])
+					if stack[i] == target {
+						break
+					}
+				}
+				return true
+			case 0:
+				if visit(target) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return false
+	}
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if state[name] == 0 && visit(name) {
+			sort.Strings(cycle)
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+func newFlow(name string, targets ...string) flowv1alpha1.Flow {
+	flow := flowv1alpha1.Flow{Name: name}
+	if len(targets) != 0 {
+		flow.DependsOn = &flowv1alpha1.DependsOn{Targets: targets}
+	}
+	return flow
+}
+
+func TestValidateJobFlow(t *testing.T) {
+	testCases := []struct {
+		name        string
+		flows       []flowv1alpha1.Flow
+		retain      flowv1alpha1.RetainPolicy
+		annotations map[string]string
+		expectErr   string
+	}{
+		{
+			name:   "valid DAG",
+			flows:  []flowv1alpha1.Flow{newFlow("a"), newFlow("b", "a"), newFlow("c", "a", "b")},
+			retain: flowv1alpha1.Delete,
+			annotations: map[string]string{
+				jobhelpers.FlowTemplateValuesKey: `{"b":{"epochs":"20"}}`,
+			},
+		},
+		{
+			name:      "no flows",
+			expectErr: "jobflow must have at least one flow",
+		},
+		{
+			name:      "invalid retain policy",
+			flows:     []flowv1alpha1.Flow{newFlow("a")},
+			retain:    "keep",
+			expectErr: `spec.jobRetainPolicy: Unsupported value: "keep"`,
+		},
+		{
+			name:      "duplicated flow",
+			flows:     []flowv1alpha1.Flow{newFlow("a"), newFlow("a")},
+			expectErr: `spec.flows[1].name: Duplicate value: "a"`,
+		},
+		{
+			name:      "unknown target",
+			flows:     []flowv1alpha1.Flow{newFlow("a", "missing")},
+			expectErr: `spec.flows[0].dependsOn.targets[0]: Not found: "missing"`,
+		},
+		{
+			name:      "self dependency",
+			flows:     []flowv1alpha1.Flow{newFlow("a", "a")},
+			expectErr: "flow cannot depend on itself",
+		},
+		{
+			name:      "cycle",
+			flows:     []flowv1alpha1.Flow{newFlow("a", "c"), newFlow("b", "a"), newFlow("c", "b"), newFlow("d")},
+			expectErr: `spec.flows: Invalid value: []string{"a", "b", "c"}: dependencies between flows must form a directed acyclic graph(DAG)`,
+		},
+		{
+			name:  "values of unknown flow",
+			flows: []flowv1alpha1.Flow{newFlow("a")},
+			annotations: map[string]string{
+				jobhelpers.FlowTemplateValuesKey: `{"b":{"epochs":"20"}}`,
+			},
+			expectErr: "parameter values are given for unknown flows",
+		},
+		{
+			name:  "malformed values",
+			flows: []flowv1alpha1.Flow{newFlow("a")},
+			annotations: map[string]string{
+				jobhelpers.FlowTemplateValuesKey: `{"a":`,
+			},
+			expectErr: "failed to parse annotation",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jobflow := &flowv1alpha1.JobFlow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "flow", Annotations: tc.annotations},
+				Spec: flowv1alpha1.JobFlowSpec{
+					Flows:           tc.flows,
+					JobRetainPolicy: tc.retain,
+				},
+			}
+			err := validateJobFlow(jobflow)
+			if tc.expectErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+				t.Errorf("expected error containing %q, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
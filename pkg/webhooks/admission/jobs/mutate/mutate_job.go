@@ -17,9 +17,14 @@ limitations under the License.
 package mutate
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
@@ -31,6 +36,8 @@ import (
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/pytorch"
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/tensorflow"
 	commonutil "volcano.sh/volcano/pkg/util"
+	"volcano.sh/volcano/pkg/util/tracing"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -55,6 +62,10 @@ var service = &router.AdmissionService{
 
 	Config: config,
 
+	Informers: func(config *router.AdmissionServiceConfig) {
+		config.KubeInformerFactory.Core().V1().Namespaces().Informer()
+	},
+
 	MutatingConfig: &whv1.MutatingWebhookConfiguration{
 		Webhooks: []whv1.MutatingWebhook{{
 			Name: "mutatejob.volcano.sh",
@@ -90,9 +101,14 @@ func Jobs(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	}
 
 	var patchBytes []byte
+	defaults := getJobDefaults()
 	switch ar.Request.Operation {
 	case admissionv1.Create:
-		patchBytes, _ = createPatch(job)
+		// the admission starts the trace of the job, or continues the one its creator started
+		ctx, span := tracing.Start(tracing.Extract(context.Background(), job.Annotations), "AdmitJob",
+			trace.WithAttributes(attribute.String("namespace", job.Namespace), attribute.String("job", job.Name)))
+		defer span.End()
+		patchBytes, _ = createPatch(job, defaults, tracing.TraceParent(ctx))
 	default:
 		err = fmt.Errorf("expect operation to be 'CREATE' ")
 		return util.ToAdmissionResponse(err)
@@ -106,22 +122,27 @@ func Jobs(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	if len(patchBytes) > 0 {
 		pt := admissionv1.PatchTypeJSONPatch
 		reviewResponse.PatchType = &pt
+		util.AddPatchAuditAnnotation(&reviewResponse, patchBytes)
 	}
+	queue, source := defaultQueue(job, defaults)
+	util.AddAuditAnnotation(&reviewResponse, util.AuditOriginalQueue, job.Spec.Queue)
+	util.AddAuditAnnotation(&reviewResponse, util.AuditQueue, queue)
+	util.AddAuditAnnotation(&reviewResponse, util.AuditQueueSource, source)
 
 	return &reviewResponse
 }
 
-func createPatch(job *v1alpha1.Job) ([]byte, error) {
+func createPatch(job *v1alpha1.Job, defaults wkconfig.JobDefaultsConfig, traceParent string) ([]byte, error) {
 	var patch []patchOperation
-	pathQueue := patchDefaultQueue(job)
+	pathQueue := patchDefaultQueue(job, defaults)
 	if pathQueue != nil {
 		patch = append(patch, *pathQueue)
 	}
-	pathScheduler := patchDefaultScheduler(job)
+	pathScheduler := patchDefaultScheduler(job, defaults)
 	if pathScheduler != nil {
 		patch = append(patch, *pathScheduler)
 	}
-	pathMaxRetry := patchDefaultMaxRetry(job)
+	pathMaxRetry := patchDefaultMaxRetry(job, defaults)
 	if pathMaxRetry != nil {
 		patch = append(patch, *pathMaxRetry)
 	}
@@ -134,33 +155,77 @@ func createPatch(job *v1alpha1.Job) ([]byte, error) {
 		patch = append(patch, *pathMinAvailable)
 	}
 	// Add default plugins for some distributed-framework plugin cases
-	patchPlugins := patchDefaultPlugins(job)
+	patchPlugins := patchDefaultPlugins(job, defaults)
 	if patchPlugins != nil {
 		patch = append(patch, *patchPlugins)
 	}
+	patchTrace := patchTraceParent(job, traceParent)
+	if patchTrace != nil {
+		patch = append(patch, *patchTrace)
+	}
 	return json.Marshal(patch)
 }
 
-func patchDefaultQueue(job *v1alpha1.Job) *patchOperation {
+// patchTraceParent annotates the job with the trace context of its admission, which the job controller
+// propagates to the podgroup and pods of the job.
+func patchTraceParent(job *v1alpha1.Job, traceParent string) *patchOperation {
+	if traceParent == "" || job.Annotations[tracing.TraceParentKey] == traceParent {
+		return nil
+	}
+	if job.Annotations == nil {
+		return &patchOperation{Op: "add", Path: "/metadata/annotations", Value: map[string]string{tracing.TraceParentKey: traceParent}}
+	}
+	// "/" in a key is escaped as "~1" in a JSON pointer
+	return &patchOperation{Op: "add", Path: "/metadata/annotations/" + strings.ReplaceAll(tracing.TraceParentKey, "/", "~1"), Value: traceParent}
+}
+
+// getJobDefaults returns the job defaults of the admission configuration.
+func getJobDefaults() wkconfig.JobDefaultsConfig {
+	if config.ConfigData == nil {
+		return wkconfig.JobDefaultsConfig{}
+	}
+	config.ConfigData.Lock()
+	defer config.ConfigData.Unlock()
+	return config.ConfigData.JobDefaults
+}
+
+func patchDefaultQueue(job *v1alpha1.Job, defaults wkconfig.JobDefaultsConfig) *patchOperation {
 	//Add default queue if not specified.
 	if job.Spec.Queue == "" {
-		return &patchOperation{Op: "add", Path: "/spec/queue", Value: DefaultQueue}
+		queue, _ := defaultQueue(job, defaults)
+		return &patchOperation{Op: "add", Path: "/spec/queue", Value: queue}
 	}
 	return nil
 }
 
-func patchDefaultScheduler(job *v1alpha1.Job) *patchOperation {
+// defaultQueue returns the queue the job is admitted to and where it comes from.
+func defaultQueue(job *v1alpha1.Job, defaults wkconfig.JobDefaultsConfig) (string, string) {
+	if job.Spec.Queue != "" {
+		return job.Spec.Queue, util.QueueSourceObject
+	}
+	return util.DefaultQueue(util.NamespaceLister(config.KubeInformerFactory), job.Namespace, defaults)
+}
+
+func patchDefaultScheduler(job *v1alpha1.Job, defaults wkconfig.JobDefaultsConfig) *patchOperation {
 	// Add default scheduler name if not specified.
 	if job.Spec.SchedulerName == "" {
-		return &patchOperation{Op: "add", Path: "/spec/schedulerName", Value: commonutil.GenerateSchedulerName(config.SchedulerNames)}
+		schedulerName := defaults.SchedulerName
+		if schedulerName == "" {
+			schedulerName = commonutil.GenerateSchedulerName(config.SchedulerNames)
+		}
+		return &patchOperation{Op: "add", Path: "/spec/schedulerName", Value: schedulerName}
 	}
 	return nil
 }
 
-func patchDefaultMaxRetry(job *v1alpha1.Job) *patchOperation {
+func patchDefaultMaxRetry(job *v1alpha1.Job, defaults wkconfig.JobDefaultsConfig) *patchOperation {
 	// Add default maxRetry if maxRetry is zero.
 	if job.Spec.MaxRetry == 0 {
-		return &patchOperation{Op: "add", Path: "/spec/maxRetry", Value: DefaultMaxRetry}
+		maxRetry := int32(DefaultMaxRetry)
+		if defaults.MaxRetry > 0 {
+			maxRetry = defaults.MaxRetry
+		}
+		return &patchOperation{Op: "add", Path: "/spec/maxRetry", Value: maxRetry}
 	}
 	return nil
 }
@@ -188,6 +253,7 @@ func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string, job *v1alpha1.Job) *
 	// 	mpi.AddDependsOn(job)
 	// }
 	patched := false
+	normalization := getResourceNormalization()
 	for index := range tasks {
 		// add default task name
 		taskName := tasks[index].Name
@@ -211,6 +277,10 @@ func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string, job *v1alpha1.Job) *
 			patched = true
 			tasks[index].MaxRetry = defaultMaxRetry
 		}
+
+		if normalizeResources(&tasks[index].Template, normalization) {
+			patched = true
+		}
 	}
 	if !patched {
 		return nil
@@ -222,8 +292,8 @@ func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string, job *v1alpha1.Job) *
 	}
 }
 
-func patchDefaultPlugins(job *v1alpha1.Job) *patchOperation {
-	if job.Spec.Plugins == nil {
+func patchDefaultPlugins(job *v1alpha1.Job, defaults wkconfig.JobDefaultsConfig) *patchOperation {
+	if job.Spec.Plugins == nil && len(defaults.Plugins) == 0 {
 		return nil
 	}
 	plugins := map[string][]string{}
@@ -231,18 +301,28 @@ func patchDefaultPlugins(job *v1alpha1.Job) *patchOperation {
 		plugins[k] = v
 	}
 
+	// Inject the plugins mandated by the cluster, keeping the arguments given by the job.
+	for k, v := range defaults.Plugins {
+		if _, ok := plugins[k]; !ok {
+			if v == nil {
+				v = []string{}
+			}
+			plugins[k] = v
+		}
+	}
+
 	// Because the tensorflow-plugin and mpi-plugin depends on svc-plugin.
 	// If the svc-plugin is not defined, we should add it.
-	_, hasTf := job.Spec.Plugins[tensorflow.TFPluginName]
-	_, hasMPI := job.Spec.Plugins[mpi.MPIPluginName]
-	_, hasPytorch := job.Spec.Plugins[pytorch.PytorchPluginName]
+	_, hasTf := plugins[tensorflow.TFPluginName]
+	_, hasMPI := plugins[mpi.MPIPluginName]
+	_, hasPytorch := plugins[pytorch.PytorchPluginName]
 	if hasTf || hasMPI || hasPytorch {
 		if _, ok := plugins["svc"]; !ok {
 			plugins["svc"] = []string{}
 		}
 	}
 
-	if _, ok := job.Spec.Plugins["mpi"]; ok {
+	if _, ok := plugins["mpi"]; ok {
 		if _, ok := plugins["ssh"]; !ok {
 			plugins["ssh"] = []string{}
 		}
@@ -17,12 +17,14 @@ limitations under the License.
 package mutate
 
 import (
+	"reflect"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 )
 
 func TestCreatePatchExecution(t *testing.T) {
@@ -145,3 +147,129 @@ func TestCreatePatchExecution(t *testing.T) {
 	}
 
 }
+
+func TestPatchJobDefaults(t *testing.T) {
+	defaults := wkconfig.JobDefaultsConfig{
+		Queue:           "shared",
+		NamespaceQueues: map[string]string{"team-a": "queue-a"},
+		SchedulerName:   "volcano-batch",
+		MaxRetry:        5,
+		Plugins:         map[string][]string{"env": nil, "svc": {"--disable-network-policy=true"}},
+	}
+
+	testCases := []struct {
+		Name              string
+		Job               v1alpha1.Job
+		Defaults          wkconfig.JobDefaultsConfig
+		ExpectedQueue     interface{}
+		ExpectedScheduler interface{}
+		ExpectedMaxRetry  interface{}
+		ExpectedPlugins   interface{}
+	}{
+		{
+			Name:              "built-in defaults without configuration",
+			Job:               v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}},
+			ExpectedQueue:     DefaultQueue,
+			ExpectedScheduler: "volcano",
+			ExpectedMaxRetry:  int32(DefaultMaxRetry),
+		},
+		{
+			Name:              "queue of the namespace",
+			Job:               v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}},
+			Defaults:          defaults,
+			ExpectedQueue:     "queue-a",
+			ExpectedScheduler: "volcano-batch",
+			ExpectedMaxRetry:  int32(5),
+			ExpectedPlugins:   map[string][]string{"env": {}, "svc": {"--disable-network-policy=true"}},
+		},
+		{
+			Name: "fields and plugin arguments of the job are kept",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-b"},
+				Spec: v1alpha1.JobSpec{
+					Queue:         "research",
+					SchedulerName: "volcano",
+					MaxRetry:      1,
+					Plugins:       map[string][]string{"svc": {}},
+				},
+			},
+			Defaults:        defaults,
+			ExpectedPlugins: map[string][]string{"env": {}, "svc": {}},
+		},
+		{
+			Name:              "default queue of other namespaces",
+			Job:               v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b"}},
+			Defaults:          wkconfig.JobDefaultsConfig{Queue: "shared"},
+			ExpectedQueue:     "shared",
+			ExpectedScheduler: "volcano",
+			ExpectedMaxRetry:  int32(DefaultMaxRetry),
+		},
+	}
+
+	value := func(op *patchOperation) interface{} {
+		if op == nil {
+			return nil
+		}
+		return op.Value
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if queue := value(patchDefaultQueue(&testCase.Job, testCase.Defaults)); queue != testCase.ExpectedQueue {
+				t.Errorf("expected queue %v, but got %v", testCase.ExpectedQueue, queue)
+			}
+			if scheduler := value(patchDefaultScheduler(&testCase.Job, testCase.Defaults)); scheduler != testCase.ExpectedScheduler {
+				t.Errorf("expected scheduler %v, but got %v", testCase.ExpectedScheduler, scheduler)
+			}
+			if maxRetry := value(patchDefaultMaxRetry(&testCase.Job, testCase.Defaults)); maxRetry != testCase.ExpectedMaxRetry {
+				t.Errorf("expected maxRetry %v, but got %v", testCase.ExpectedMaxRetry, maxRetry)
+			}
+			if plugins := value(patchDefaultPlugins(&testCase.Job, testCase.Defaults)); !reflect.DeepEqual(plugins, testCase.ExpectedPlugins) {
+				t.Errorf("expected plugins %v, but got %v", testCase.ExpectedPlugins, plugins)
+			}
+		})
+	}
+}
+
+func TestPatchTraceParent(t *testing.T) {
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	testCases := []struct {
+		Name        string
+		Annotations map[string]string
+		TraceParent string
+		Expected    *patchOperation
+	}{
+		{
+			Name:        "tracing disabled",
+			Annotations: map[string]string{"a": "b"},
+			TraceParent: "",
+			Expected:    nil,
+		},
+		{
+			Name:        "no annotations",
+			TraceParent: traceParent,
+			Expected:    &patchOperation{Op: "add", Path: "/metadata/annotations", Value: map[string]string{"volcano.sh/traceparent": traceParent}},
+		},
+		{
+			Name:        "annotations",
+			Annotations: map[string]string{"a": "b"},
+			TraceParent: traceParent,
+			Expected:    &patchOperation{Op: "add", Path: "/metadata/annotations/volcano.sh~1traceparent", Value: traceParent},
+		},
+		{
+			Name:        "already annotated",
+			Annotations: map[string]string{"volcano.sh/traceparent": traceParent},
+			TraceParent: traceParent,
+			Expected:    nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			job := &v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job", Annotations: testCase.Annotations}}
+			ret := patchTraceParent(job, testCase.TraceParent)
+			if !reflect.DeepEqual(ret, testCase.Expected) {
+				t.Errorf("expected patch %v, got %v", testCase.Expected, ret)
+			}
+		})
+	}
+}
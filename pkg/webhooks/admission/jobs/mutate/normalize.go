@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+// getResourceNormalization returns the resource normalization of the admission configuration.
+func getResourceNormalization() wkconfig.ResourceNormalizationConfig {
+	if config.ConfigData == nil {
+		return wkconfig.ResourceNormalizationConfig{}
+	}
+	config.ConfigData.Lock()
+	defer config.ConfigData.Unlock()
+	return config.ConfigData.ResourceNormalization
+}
+
+// normalizeResources normalizes the resource requests of the pod template and returns whether it
+// is changed: portable resource names are translated into the resource names of this cluster, the
+// resources requested by annotations are added to the first container and hugepages are rounded.
+func normalizeResources(template *v1.PodTemplateSpec, normalization wkconfig.ResourceNormalizationConfig) bool {
+	changed := false
+	spec := &template.Spec
+
+	if len(normalization.Annotations) != 0 && len(spec.Containers) != 0 {
+		keys := make([]string, 0, len(normalization.Annotations))
+		for key := range normalization.Annotations {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, found := template.Annotations[key]
+			if !found {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				klog.Warningf("Ignore annotation %s=%s of pod template, invalid quantity: %v", key, value, err)
+				continue
+			}
+			name := v1.ResourceName(normalization.Annotations[key])
+			if requestsResource(spec, name, normalization.ResourceNames) {
+				continue
+			}
+			container := &spec.Containers[0]
+			if container.Resources.Requests == nil {
+				container.Resources.Requests = v1.ResourceList{}
+			}
+			if container.Resources.Limits == nil {
+				container.Resources.Limits = v1.ResourceList{}
+			}
+			container.Resources.Requests[name] = quantity
+			container.Resources.Limits[name] = quantity.DeepCopy()
+			changed = true
+		}
+	}
+
+	for i := range spec.InitContainers {
+		if normalizeContainerResources(&spec.InitContainers[i].Resources, normalization) {
+			changed = true
+		}
+	}
+	for i := range spec.Containers {
+		if normalizeContainerResources(&spec.Containers[i].Resources, normalization) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func requestsResource(spec *v1.PodSpec, name v1.ResourceName, resourceNames map[string]string) bool {
+	vendorName := name
+	if mapped, found := resourceNames[string(name)]; found {
+		vendorName = v1.ResourceName(mapped)
+	}
+	for _, container := range spec.Containers {
+		for _, list := range []v1.ResourceList{container.Resources.Requests, container.Resources.Limits} {
+			if _, found := list[name]; found {
+				return true
+			}
+			if _, found := list[vendorName]; found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeContainerResources(resources *v1.ResourceRequirements, normalization wkconfig.ResourceNormalizationConfig) bool {
+	changed := false
+	for _, list := range []v1.ResourceList{resources.Requests, resources.Limits} {
+		if normalizeResourceList(list, normalization) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func normalizeResourceList(list v1.ResourceList, normalization wkconfig.ResourceNormalizationConfig) bool {
+	changed := false
+	for name, quantity := range list {
+		if vendorName, found := normalization.ResourceNames[string(name)]; found && vendorName != string(name) {
+			delete(list, name)
+			// the vendor name given explicitly wins over the portable name
+			if _, exists := list[v1.ResourceName(vendorName)]; !exists {
+				list[v1.ResourceName(vendorName)] = quantity
+			}
+			changed = true
+		}
+	}
+	if normalization.RoundHugepages {
+		for name, quantity := range list {
+			if rounded, ok := roundHugepages(name, quantity); ok {
+				list[name] = rounded
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// roundHugepages rounds the quantity of the hugepages resource up to a multiple of its page
+// size, it returns false if the resource is not hugepages or the quantity is already aligned.
+func roundHugepages(name v1.ResourceName, quantity resource.Quantity) (resource.Quantity, bool) {
+	if !strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix) {
+		return quantity, false
+	}
+	pageSize, err := resource.ParseQuantity(strings.TrimPrefix(string(name), v1.ResourceHugePagesPrefix))
+	if err != nil || pageSize.Value() <= 0 {
+		return quantity, false
+	}
+	size := pageSize.Value()
+	value := quantity.Value()
+	if value%size == 0 {
+		return quantity, false
+	}
+	return *resource.NewQuantity((value/size+1)*size, resource.BinarySI), true
+}
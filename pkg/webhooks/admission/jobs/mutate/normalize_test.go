@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+func TestNormalizeResources(t *testing.T) {
+	normalization := wkconfig.ResourceNormalizationConfig{
+		ResourceNames:  map[string]string{"volcano.sh/gpu": "nvidia.com/gpu"},
+		Annotations:    map[string]string{"volcano.sh/gpu-memory": "volcano.sh/gpu-memory"},
+		RoundHugepages: true,
+	}
+
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		requests      v1.ResourceList
+		limits        v1.ResourceList
+		expectChanged bool
+		expectReqs    v1.ResourceList
+		expectLimits  v1.ResourceList
+	}{
+		{
+			name:          "translate portable resource name",
+			requests:      v1.ResourceList{"volcano.sh/gpu": resource.MustParse("1")},
+			limits:        v1.ResourceList{"volcano.sh/gpu": resource.MustParse("1")},
+			expectChanged: true,
+			expectReqs:    v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+			expectLimits:  v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+		},
+		{
+			name:          "translate annotation into resource",
+			annotations:   map[string]string{"volcano.sh/gpu-memory": "1024"},
+			expectChanged: true,
+			expectReqs:    v1.ResourceList{"volcano.sh/gpu-memory": resource.MustParse("1024")},
+			expectLimits:  v1.ResourceList{"volcano.sh/gpu-memory": resource.MustParse("1024")},
+		},
+		{
+			name:         "annotation does not override the container resource",
+			annotations:  map[string]string{"volcano.sh/gpu-memory": "1024"},
+			limits:       v1.ResourceList{"volcano.sh/gpu-memory": resource.MustParse("2048")},
+			expectLimits: v1.ResourceList{"volcano.sh/gpu-memory": resource.MustParse("2048")},
+		},
+		{
+			name:          "round hugepages up",
+			requests:      v1.ResourceList{"hugepages-2Mi": resource.MustParse("5Mi")},
+			limits:        v1.ResourceList{"hugepages-2Mi": resource.MustParse("5Mi")},
+			expectChanged: true,
+			expectReqs:    v1.ResourceList{"hugepages-2Mi": resource.MustParse("6Mi")},
+			expectLimits:  v1.ResourceList{"hugepages-2Mi": resource.MustParse("6Mi")},
+		},
+		{
+			name:         "aligned hugepages are kept",
+			requests:     v1.ResourceList{"hugepages-1Gi": resource.MustParse("2Gi")},
+			limits:       v1.ResourceList{"hugepages-1Gi": resource.MustParse("2Gi")},
+			expectReqs:   v1.ResourceList{"hugepages-1Gi": resource.MustParse("2Gi")},
+			expectLimits: v1.ResourceList{"hugepages-1Gi": resource.MustParse("2Gi")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			template := &v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name:      "main",
+						Resources: v1.ResourceRequirements{Requests: tc.requests, Limits: tc.limits},
+					}},
+				},
+			}
+			changed := normalizeResources(template, normalization)
+			if changed != tc.expectChanged {
+				t.Errorf("expected changed %v, got %v", tc.expectChanged, changed)
+			}
+			resources := template.Spec.Containers[0].Resources
+			if !equalResources(resources.Requests, tc.expectReqs) {
+				t.Errorf("expected requests %v, got %v", tc.expectReqs, resources.Requests)
+			}
+			if !equalResources(resources.Limits, tc.expectLimits) {
+				t.Errorf("expected limits %v, got %v", tc.expectLimits, resources.Limits)
+			}
+		})
+	}
+}
+
+func equalResources(a, b v1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, found := b[name]
+		if !found || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -25,9 +25,11 @@ import (
 	whv1 "k8s.io/api/admissionregistration/v1"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	k8score "k8s.io/kubernetes/pkg/apis/core"
 	k8scorev1 "k8s.io/kubernetes/pkg/apis/core/v1"
@@ -40,6 +42,7 @@ import (
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/plugins"
 	controllerMpi "volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/mpi"
+	"volcano.sh/volcano/pkg/webhooks/policy"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -63,6 +66,19 @@ var service = &router.AdmissionService{
 
 	Config: config,
 
+	Informers: func(config *router.AdmissionServiceConfig) {
+		config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Informer()
+		util.AddPodGroupQueueIndex(config.VolcanoInformerFactory.Scheduling().V1beta1().PodGroups().Informer())
+		config.KubeInformerFactory.Scheduling().V1().PriorityClasses().Informer()
+		installed, err := policy.Installed(config.KubeClient.Discovery())
+		if err != nil {
+			klog.Errorf("Failed to discover the Policy CRD, the policies are not enforced: %v", err)
+		}
+		if installed {
+			policyLister = config.DynamicInformerFactory.ForResource(policy.PolicyResource).Lister()
+		}
+	},
+
 	ValidatingConfig: &whv1.ValidatingWebhookConfiguration{
 		Webhooks: []whv1.ValidatingWebhook{{
 			Name: "validatejob.volcano.sh",
@@ -97,11 +113,30 @@ func AdmitJobs(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	switch ar.Request.Operation {
 	case admissionv1.Create:
 		msg = validateJobCreate(job, &reviewResponse)
+		if err := util.AuthorizeQueue(config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Lister(), config.ControllerServiceAccounts,
+			job.Spec.Queue, job.Namespace, ar.Request.UserInfo); err != nil {
+			reviewResponse.Allowed = false
+			msg += fmt.Sprintf(" %v;", err)
+		}
+		if err := util.CheckQueuePendingJobs(config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Lister(),
+			config.VolcanoInformerFactory.Scheduling().V1beta1().PodGroups().Informer().GetIndexer(), job.Spec.Queue); err != nil {
+			reviewResponse.Allowed = false
+			msg += fmt.Sprintf(" %v;", err)
+		}
 	case admissionv1.Update:
 		oldJob, err := schema.DecodeJob(ar.Request.OldObject, ar.Request.Resource)
 		if err != nil {
 			return util.ToAdmissionResponse(err)
 		}
+		if job.Spec.Queue != oldJob.Spec.Queue {
+			if err = util.AuthorizeQueue(config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Lister(), config.ControllerServiceAccounts,
+				job.Spec.Queue, job.Namespace, ar.Request.UserInfo); err != nil {
+				return util.ToAdmissionResponse(err)
+			}
+		}
+		if err = validateJobPoliciesUpdate(oldJob, job); err != nil {
+			return util.ToAdmissionResponse(err)
+		}
 		err = validateJobUpdate(oldJob, job)
 		if err != nil {
 			return util.ToAdmissionResponse(err)
@@ -114,6 +149,9 @@ func AdmitJobs(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	if !reviewResponse.Allowed {
 		reviewResponse.Result = &metav1.Status{Message: strings.TrimSpace(msg)}
 	}
+	if len(reviewResponse.Warnings) != 0 {
+		util.AddAuditAnnotation(&reviewResponse, util.AuditWarnings, strings.Join(reviewResponse.Warnings, "; "))
+	}
 	return &reviewResponse
 }
 
@@ -210,11 +248,11 @@ func validateJobCreate(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionR
 			getValidEvents(), getValidActions())
 	}
 
-	// invalid job plugins
+	// invalid job plugins or plugin arguments
 	if len(job.Spec.Plugins) != 0 {
-		for name := range job.Spec.Plugins {
-			if _, found := plugins.GetPluginBuilder(name); !found {
-				msg += fmt.Sprintf(" unable to find job plugin: %s;", name)
+		for name, arguments := range job.Spec.Plugins {
+			if err := plugins.ValidatePluginArguments(name, arguments); err != nil {
+				msg += fmt.Sprintf(" %v;", err)
 			}
 		}
 	}
@@ -222,6 +260,18 @@ func validateJobCreate(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionR
 	if err := validateIO(job.Spec.Volumes); err != nil {
 		msg += err.Error()
 	}
+	if err := validateVolumeAnnotations(job); err != nil {
+		msg += err.Error()
+	}
+	if err := validateElasticMinMemberPolicy(job); err != nil {
+		msg += fmt.Sprintf(" %v;", err)
+	}
+	if err := validateGrowAfterStart(job); err != nil {
+		msg += fmt.Sprintf(" %v;", err)
+	}
+	if err := jobhelpers.ValidateTaskAutoscaling(job); err != nil {
+		msg += fmt.Sprintf(" %v;", err)
+	}
 
 	queue, err := config.VolcanoClient.SchedulingV1beta1().Queues().Get(context.TODO(), job.Spec.Queue, metav1.GetOptions{})
 	if err != nil {
@@ -229,9 +279,16 @@ func validateJobCreate(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionR
 	} else if queue.Status.State != schedulingv1beta1.QueueStateOpen {
 		msg += fmt.Sprintf(" can only submit job to queue with state `Open`, "+
 			"queue `%s` status is `%s`;", queue.Name, queue.Status.State)
+	} else {
+		msg += validateQueueFeasibility(job, queue, reviewResponse)
 	}
 
+	msg += validatePriorityClasses(job)
+	msg += validateTemplateParameters(job)
+	msg += validateJobPolicies(job, reviewResponse)
+
 	if hasDependenciesBetweenTasks {
+		msg += validateTaskDependencies(job)
 		_, isDag := topoSort(job)
 		if !isDag {
 			msg += " job has dependencies between tasks, but doesn't form a directed acyclic graph(DAG);"
@@ -273,16 +330,37 @@ func validateJobUpdate(old, new *v1alpha1.Job) error {
 	if len(old.Spec.Tasks) != len(new.Spec.Tasks) {
 		return fmt.Errorf("job updates may not add or remove tasks")
 	}
+	if err := validateElasticMinMemberPolicy(new); err != nil {
+		return err
+	}
+	if err := validateGrowAfterStart(new); err != nil {
+		return err
+	}
+	if err := jobhelpers.ValidateTaskAutoscaling(new); err != nil {
+		return err
+	}
 	// other fields under spec are not allowed to mutate
 	new.Spec.MinAvailable = old.Spec.MinAvailable
 	new.Spec.PriorityClassName = old.Spec.PriorityClassName
+	// a job can be moved to another queue until it starts running
+	if new.Spec.Queue != old.Spec.Queue {
+		if err := validateQueueMigration(old, new.Spec.Queue); err != nil {
+			return err
+		}
+		new.Spec.Queue = old.Spec.Queue
+	}
 
 	// K8S also permit mutating spec.schedulingGates
 	// We do not support this for vcjob  (More details in design doc pod-scheduling-readiness.md)
 
+	// task templates can be rolled out to a running job when rolling update is enabled
+	_, rollingUpdate := new.Annotations[jobhelpers.RollingUpdateMaxUnavailableKey]
 	for i := range new.Spec.Tasks {
 		new.Spec.Tasks[i].Replicas = old.Spec.Tasks[i].Replicas
 		new.Spec.Tasks[i].MinAvailable = old.Spec.Tasks[i].MinAvailable
+		if rollingUpdate {
+			new.Spec.Tasks[i].Template = old.Spec.Tasks[i].Template
+		}
 	}
 
 	// job controller will update the pvc name if not provided
@@ -298,7 +376,8 @@ func validateJobUpdate(old, new *v1alpha1.Job) error {
 	}
 
 	if !apiequality.Semantic.DeepEqual(new.Spec, old.Spec) {
-		return fmt.Errorf("job updates may not change fields other than `minAvailable`, `tasks[*].replicas under spec`")
+		return fmt.Errorf("job updates may not change fields other than `minAvailable`, `tasks[*].replicas under spec`, "+
+			"or `tasks[*].template` when annotation %s is set", jobhelpers.RollingUpdateMaxUnavailableKey)
 	}
 
 	return nil
@@ -389,3 +468,130 @@ func guaranteedCPUs(container v1.Container) int {
 
 	return int(cpuQuantity.Value())
 }
+
+// validatePriorityClasses checks the priority classes of the job and of its task templates exist,
+// a task template without one inherits the priority class of the job.
+func validatePriorityClasses(job *v1alpha1.Job) string {
+	var msg string
+	checked := map[string]bool{}
+	check := func(name string) {
+		if name == "" || checked[name] {
+			return
+		}
+		checked[name] = true
+		if _, err := config.KubeClient.SchedulingV1().PriorityClasses().Get(context.TODO(), name, metav1.GetOptions{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				msg += fmt.Sprintf(" priority class %s does not exist;", name)
+			} else {
+				msg += fmt.Sprintf(" unable to get priority class %s: %v;", name, err)
+			}
+		}
+	}
+
+	check(job.Spec.PriorityClassName)
+	for _, task := range job.Spec.Tasks {
+		check(task.Template.Spec.PriorityClassName)
+	}
+	return msg
+}
+
+// validateTemplateParameters checks a job expanded from a JobTemplate was given the required
+// parameters of the template, and that none of the declared parameters is left unsubstituted.
+func validateTemplateParameters(job *v1alpha1.Job) string {
+	templateName, found := job.Annotations[jobhelpers.FromTemplateKey]
+	if !found {
+		return ""
+	}
+
+	template, err := config.VolcanoClient.FlowV1alpha1().JobTemplates(job.Namespace).Get(context.TODO(), templateName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf(" job template %s does not exist;", templateName)
+		}
+		return fmt.Sprintf(" unable to get job template %s: %v;", templateName, err)
+	}
+
+	params, err := jobhelpers.GetTemplateParameters(template)
+	if err != nil {
+		return fmt.Sprintf(" %v;", err)
+	}
+	values, err := jobhelpers.GetTemplateValues(job.Annotations)
+	if err != nil {
+		return fmt.Sprintf(" %v;", err)
+	}
+	if _, err := jobhelpers.ResolveTemplateParameters(params, values); err != nil {
+		return fmt.Sprintf(" invalid parameters of job template %s: %v;", templateName, err)
+	}
+	if names := jobhelpers.GetTemplatePlaceholders(&job.Spec, params); len(names) != 0 {
+		return fmt.Sprintf(" parameters %v of job template %s are not substituted;", names, templateName)
+	}
+	return ""
+}
+
+// validateQueueMigration checks the job can be moved to the queue: the job is pending, its PodGroup
+// is not running yet, and the queue is open.
+func validateQueueMigration(job *v1alpha1.Job, queueName string) error {
+	if job.Status.State.Phase != v1alpha1.Pending {
+		return fmt.Errorf("job in phase %s can not be moved to another queue, only pending jobs can", job.Status.State.Phase)
+	}
+
+	pgName := job.Name + "-" + string(job.UID)
+	pg, err := config.VolcanoClient.SchedulingV1beta1().PodGroups(job.Namespace).Get(context.TODO(), pgName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to get PodGroup of job: %v", err)
+	}
+	if err == nil && pg.Status.Phase != "" && pg.Status.Phase != schedulingv1beta1.PodGroupPending &&
+		pg.Status.Phase != schedulingv1beta1.PodGroupInqueue {
+		return fmt.Errorf("job can not be moved to another queue, its PodGroup is %s", pg.Status.Phase)
+	}
+
+	queue, err := config.VolcanoClient.SchedulingV1beta1().Queues().Get(context.TODO(), queueName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to find job queue: %v", err)
+	}
+	if queue.Status.State != schedulingv1beta1.QueueStateOpen {
+		return fmt.Errorf("can only move job to queue with state `Open`, queue `%s` status is `%s`", queue.Name, queue.Status.State)
+	}
+	return nil
+}
+
+// policyLister lists the policies, nil when the Policy CRD was not installed when the webhook started.
+var policyLister cache.GenericLister
+
+// listPolicies returns the policies of the namespace, none when the Policy CRD is not installed.
+func listPolicies(namespace string) ([]*policy.Policy, error) {
+	if policyLister == nil {
+		return nil, nil
+	}
+	return policy.ListPolicies(policyLister, namespace)
+}
+
+func validateJobPolicies(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionResponse) string {
+	policies, err := listPolicies(job.Namespace)
+	if err != nil {
+		return fmt.Sprintf(" unable to list policies of namespace %s: %v;", job.Namespace, err)
+	}
+	if len(policies) != 0 {
+		names := make([]string, 0, len(policies))
+		for _, p := range policies {
+			names = append(names, p.Name)
+		}
+		util.AddAuditAnnotation(reviewResponse, util.AuditPolicies, strings.Join(names, ","))
+	}
+	msg := ""
+	for _, violation := range policy.ValidateJob(job, policies) {
+		msg += fmt.Sprintf(" %s;", violation)
+	}
+	return msg
+}
+
+func validateJobPoliciesUpdate(old, new *v1alpha1.Job) error {
+	policies, err := listPolicies(new.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to list policies of namespace %s: %v", new.Namespace, err)
+	}
+	if violations := policy.ValidateJobUpdate(old, new, policies); len(violations) != 0 {
+		return fmt.Errorf("%s", strings.Join(violations, "; "))
+	}
+	return nil
+}
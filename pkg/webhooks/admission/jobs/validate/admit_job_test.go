@@ -24,13 +24,17 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 
 	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
 	schedulingv1beta2 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	fakeclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 func TestValidateJobCreate(t *testing.T) {
@@ -88,6 +92,216 @@ func TestValidateJobCreate(t *testing.T) {
 			ret:            "",
 			ExpectErr:      false,
 		},
+		{
+			Name: "job-with-priority-class",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job-with-priority-class",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable:      1,
+					Queue:             "default",
+					PriorityClassName: "high-priority",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "",
+			ExpectErr:      false,
+		},
+		{
+			Name: "task-with-missing-priority-class",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "task-with-missing-priority-class",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable:      1,
+					Queue:             "default",
+					PriorityClassName: "high-priority",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								Spec: v1.PodSpec{
+									PriorityClassName: "missing-priority",
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "priority class missing-priority does not exist",
+			ExpectErr:      true,
+		},
+		{
+			Name: "job-from-template",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job-from-template",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						jobhelpers.FromTemplateKey:   "train",
+						jobhelpers.TemplateValuesKey: `{"image":"busybox:1.24"}`,
+					},
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "",
+			ExpectErr:      false,
+		},
+		{
+			Name: "job-from-template-missing-parameter",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job-from-template-missing-parameter",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						jobhelpers.FromTemplateKey:   "train",
+						jobhelpers.TemplateValuesKey: `{}`,
+					},
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "${image}",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "invalid parameters of job template train: missing required parameters [image]",
+			ExpectErr:      true,
+		},
+		{
+			Name: "job-from-template-not-substituted",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job-from-template-not-substituted",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						jobhelpers.FromTemplateKey:   "train",
+						jobhelpers.TemplateValuesKey: `{"image":"busybox:1.24"}`,
+					},
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "${image}",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "parameters [image] of job template train are not substituted",
+			ExpectErr:      true,
+		},
+		{
+			Name: "job-from-missing-template",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job-from-missing-template",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						jobhelpers.FromTemplateKey: "missing",
+					},
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "job template missing does not exist",
+			ExpectErr:      true,
+		},
 		// duplicate task name
 		{
 			Name: "duplicate-task-job",
@@ -262,6 +476,120 @@ func TestValidateJobCreate(t *testing.T) {
 			ret:            "unable to find job plugin: big_plugin",
 			ExpectErr:      true,
 		},
+		{
+			Name: "Job Plugin arguments malformed",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job-plugin-arguments-malformed",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+					Plugins: map[string][]string{
+						"svc": {"--unknown-flag"},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "invalid arguments [--unknown-flag] of job plugin svc",
+			ExpectErr:      true,
+		},
+		{
+			Name: "Job Plugin port out of range",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job-plugin-port-illegal",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+					Plugins: map[string][]string{
+						"pytorch": {"--port=70000"},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "invalid port 70000",
+			ExpectErr:      true,
+		},
+		{
+			Name: "Task depends on unknown task",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job-depends-on-unknown-task",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							DependsOn: &v1alpha1.DependsOn{
+								Name: []string{"task-0"},
+							},
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "task task-1 depends on unknown task task-0",
+			ExpectErr:      true,
+		},
 		// ttl-illegal
 		{
 			Name: "job-ttl-illegal",
@@ -1233,7 +1561,17 @@ func TestValidateJobCreate(t *testing.T) {
 				},
 			}
 			// create fake volcano clientset
-			config.VolcanoClient = fakeclient.NewSimpleClientset()
+			config.VolcanoClient = fakeclient.NewSimpleClientset(&flowv1alpha1.JobTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "train",
+					Namespace:   namespace,
+					Annotations: map[string]string{jobhelpers.TemplateParametersKey: `[{"name":"image","required":true}]`},
+				},
+			})
+			config.KubeClient = kubefake.NewSimpleClientset(&schedulingv1.PriorityClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "high-priority"},
+				Value:      1000,
+			})
 
 			//create default queue
 			_, err := config.VolcanoClient.SchedulingV1beta1().Queues().Create(context.TODO(), &defaultqueue, metav1.CreateOptions{})
@@ -1271,6 +1609,8 @@ func TestValidateJobUpdate(t *testing.T) {
 		addTask        bool
 		mutateTaskName bool
 		mutateSpec     bool
+		minMember      string
+		growAfterStart string
 		expectErr      bool
 	}{
 		{
@@ -1300,6 +1640,35 @@ func TestValidateJobUpdate(t *testing.T) {
 			mutateSpec:     false,
 			expectErr:      false,
 		},
+		{
+			name:         "scale up with all the replicas gang scheduled",
+			replicas:     8,
+			minAvailable: 5,
+			minMember:    "Replicas",
+			expectErr:    false,
+		},
+		{
+			name:         "invalid minMember policy",
+			replicas:     8,
+			minAvailable: 5,
+			minMember:    "All",
+			expectErr:    true,
+		},
+		{
+			name:           "scale up with the job growing after start",
+			replicas:       8,
+			minAvailable:   5,
+			growAfterStart: "true",
+			expectErr:      false,
+		},
+		{
+			name:           "invalid growing after start with all the replicas gang scheduled",
+			replicas:       8,
+			minAvailable:   5,
+			minMember:      "Replicas",
+			growAfterStart: "true",
+			expectErr:      true,
+		},
 		{
 			name:           "invalid minAvailable",
 			replicas:       4,
@@ -1347,6 +1716,13 @@ func TestValidateJobUpdate(t *testing.T) {
 
 			new.Spec.MinAvailable = tc.minAvailable
 			new.Spec.Tasks[0].Replicas = tc.replicas
+			new.Annotations = map[string]string{}
+			if tc.minMember != "" {
+				new.Annotations[jobhelpers.ElasticMinMemberPolicyKey] = tc.minMember
+			}
+			if tc.growAfterStart != "" {
+				new.Annotations[jobhelpers.GrowAfterStartKey] = tc.growAfterStart
+			}
 
 			if tc.addTask {
 				new.Spec.Tasks = append(new.Spec.Tasks, v1alpha1.TaskSpec{
@@ -1386,6 +1762,82 @@ func TestValidateJobUpdate(t *testing.T) {
 
 }
 
+func TestValidateQueueMigration(t *testing.T) {
+	testCases := []struct {
+		name      string
+		phase     v1alpha1.JobPhase
+		pgPhase   schedulingv1beta2.PodGroupPhase
+		queue     string
+		expectErr bool
+	}{
+		{
+			name:    "move pending job",
+			phase:   v1alpha1.Pending,
+			pgPhase: schedulingv1beta2.PodGroupInqueue,
+			queue:   "open",
+		},
+		{
+			name:      "move running job",
+			phase:     v1alpha1.Running,
+			pgPhase:   schedulingv1beta2.PodGroupRunning,
+			queue:     "open",
+			expectErr: true,
+		},
+		{
+			name:      "move pending job with running podgroup",
+			phase:     v1alpha1.Pending,
+			pgPhase:   schedulingv1beta2.PodGroupRunning,
+			queue:     "open",
+			expectErr: true,
+		},
+		{
+			name:      "move pending job to closed queue",
+			phase:     v1alpha1.Pending,
+			pgPhase:   schedulingv1beta2.PodGroupPending,
+			queue:     "closed",
+			expectErr: true,
+		},
+		{
+			name:      "move pending job to missing queue",
+			phase:     v1alpha1.Pending,
+			queue:     "missing",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			old := newJob()
+			old.UID = "job-uid"
+			old.Status.State.Phase = tc.phase
+			new := old.DeepCopy()
+			new.Spec.Queue = tc.queue
+
+			config.VolcanoClient = fakeclient.NewSimpleClientset(
+				&schedulingv1beta2.Queue{
+					ObjectMeta: metav1.ObjectMeta{Name: "open"},
+					Status:     schedulingv1beta2.QueueStatus{State: schedulingv1beta2.QueueStateOpen},
+				},
+				&schedulingv1beta2.Queue{
+					ObjectMeta: metav1.ObjectMeta{Name: "closed"},
+					Status:     schedulingv1beta2.QueueStatus{State: schedulingv1beta2.QueueStateClosed},
+				},
+			)
+			if tc.pgPhase != "" {
+				config.VolcanoClient.SchedulingV1beta1().PodGroups(old.Namespace).Create(context.TODO(), &schedulingv1beta2.PodGroup{
+					ObjectMeta: metav1.ObjectMeta{Name: old.Name + "-" + string(old.UID), Namespace: old.Namespace},
+					Status:     schedulingv1beta2.PodGroupStatus{Phase: tc.pgPhase},
+				}, metav1.CreateOptions{})
+			}
+
+			err := validateJobUpdate(old, new)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("Expected error: %v, but got: %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
 func newJob() *v1alpha1.Job {
 	return &v1alpha1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+	"sort"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+func getQueueFeasibility() string {
+	if config.ConfigData == nil {
+		return ""
+	}
+	config.ConfigData.Lock()
+	defer config.ConfigData.Unlock()
+	return config.ConfigData.QueueFeasibility
+}
+
+// validateQueueFeasibility checks the minimal request of the job against the queue: a job which
+// exceeds the capability of the queue can never run, so it is rejected or warned about according
+// to the admission configuration; a job which exceeds the quota left in the queue is warned about
+// as it has to wait for the running jobs of the queue.
+func validateQueueFeasibility(job *v1alpha1.Job, queue *schedulingv1beta1.Queue, reviewResponse *admissionv1.AdmissionResponse) string {
+	mode := getQueueFeasibility()
+	if mode != wkconfig.QueueFeasibilityWarn && mode != wkconfig.QueueFeasibilityReject {
+		return ""
+	}
+	if len(queue.Spec.Capability) == 0 {
+		return ""
+	}
+
+	minReq := getJobMinRequest(job)
+	if exceeded := exceededResources(minReq, queue.Spec.Capability); len(exceeded) != 0 {
+		msg := fmt.Sprintf("job requires at least %s which exceeds the capability of queue %s, the job can never run",
+			formatResources(minReq, exceeded), queue.Name)
+		if mode == wkconfig.QueueFeasibilityReject {
+			return fmt.Sprintf(" %s;", msg)
+		}
+		reviewResponse.Warnings = append(reviewResponse.Warnings, msg)
+		return ""
+	}
+
+	remaining := quotav1.SubtractWithNonNegativeResult(queue.Spec.Capability, queue.Status.Allocated)
+	if exceeded := exceededResources(minReq, remaining); len(exceeded) != 0 {
+		reviewResponse.Warnings = append(reviewResponse.Warnings, fmt.Sprintf(
+			"job requires at least %s which exceeds the remaining quota of queue %s, the job waits until enough resources are released",
+			formatResources(minReq, exceeded), queue.Name))
+	}
+	return ""
+}
+
+// getJobMinRequest returns the resources requested by the minimal pods of the tasks scheduled
+// together with the job, which the queue must be able to hold at once. They are computed as the
+// minResources of the PodGroup of the job, from the minAvailable of the job and of its tasks.
+func getJobMinRequest(job *v1alpha1.Job) v1.ResourceList {
+	var tasks []v1alpha1.TaskSpec
+	priorities := map[string]int32{}
+	for _, task := range job.Spec.Tasks {
+		// tasks of other schedulers are not gang scheduled with the job
+		if !jobhelpers.IsGangTask(job, &task) {
+			continue
+		}
+		tasks = append(tasks, task)
+		priorities[task.Name] = getTaskPriority(job, &task)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return priorities[tasks[i].Name] > priorities[tasks[j].Name]
+	})
+	return jobhelpers.CalcMinResources(job, tasks)
+}
+
+// getTaskPriority returns the value of the priority class of the task, inherited from the job if
+// the task template has none.
+func getTaskPriority(job *v1alpha1.Job, task *v1alpha1.TaskSpec) int32 {
+	name := task.Template.Spec.PriorityClassName
+	if name == "" {
+		name = job.Spec.PriorityClassName
+	}
+	if name == "" {
+		return 0
+	}
+	priorityClass, err := config.KubeInformerFactory.Scheduling().V1().PriorityClasses().Lister().Get(name)
+	if err != nil {
+		klog.V(4).Infof("Ignore task %s priority class %s: %v", task.Name, name, err)
+		return 0
+	}
+	return priorityClass.Value
+}
+
+// exceededResources returns the names of the limited resources which the request exceeds.
+func exceededResources(request, limit v1.ResourceList) []v1.ResourceName {
+	var exceeded []v1.ResourceName
+	for name, quantity := range limit {
+		if requested, found := request[name]; found && requested.Cmp(quantity) > 0 {
+			exceeded = append(exceeded, name)
+		}
+	}
+	sort.Slice(exceeded, func(i, j int) bool {
+		return exceeded[i] < exceeded[j]
+	})
+	return exceeded
+}
+
+func formatResources(resources v1.ResourceList, names []v1.ResourceName) string {
+	msg := ""
+	for i, name := range names {
+		if i > 0 {
+			msg += ", "
+		}
+		quantity := resources[name]
+		msg += fmt.Sprintf("%s: %s", name, quantity.String())
+	}
+	return msg
+}
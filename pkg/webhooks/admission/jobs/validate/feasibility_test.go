@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+func TestValidateQueueFeasibility(t *testing.T) {
+	minAvailable := int32(2)
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "job"},
+		Spec: v1alpha1.JobSpec{
+			Queue:        "q1",
+			MinAvailable: 2,
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:         "worker",
+					Replicas:     4,
+					MinAvailable: &minAvailable,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{{
+								Name: "worker",
+								Resources: v1.ResourceRequirements{
+									Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+								},
+							}},
+						},
+					},
+				},
+				{
+					Name:     "sidecar",
+					Replicas: 1,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							SchedulerName: "default-scheduler",
+							Containers: []v1.Container{{
+								Name: "sidecar",
+								Resources: v1.ResourceRequirements{
+									Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100")},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	newQueue := func(capability, allocated string) *schedulingv1beta1.Queue {
+		return &schedulingv1beta1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: "q1"},
+			Spec:       schedulingv1beta1.QueueSpec{Capability: v1.ResourceList{v1.ResourceCPU: resource.MustParse(capability)}},
+			Status:     schedulingv1beta1.QueueStatus{Allocated: v1.ResourceList{v1.ResourceCPU: resource.MustParse(allocated)}},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		mode           string
+		queue          *schedulingv1beta1.Queue
+		expectMsg      string
+		expectWarnings string
+	}{
+		{
+			name:  "disabled",
+			mode:  "",
+			queue: newQueue("2", "0"),
+		},
+		{
+			name:      "reject job exceeding capability",
+			mode:      wkconfig.QueueFeasibilityReject,
+			queue:     newQueue("3", "0"),
+			expectMsg: "job requires at least cpu: 4 which exceeds the capability of queue q1",
+		},
+		{
+			name:           "warn job exceeding capability",
+			mode:           wkconfig.QueueFeasibilityWarn,
+			queue:          newQueue("3", "0"),
+			expectWarnings: "job requires at least cpu: 4 which exceeds the capability of queue q1",
+		},
+		{
+			name:           "warn job exceeding remaining quota",
+			mode:           wkconfig.QueueFeasibilityReject,
+			queue:          newQueue("6", "4"),
+			expectWarnings: "job requires at least cpu: 4 which exceeds the remaining quota of queue q1",
+		},
+		{
+			name:  "job fits, tasks of other schedulers are not counted",
+			mode:  wkconfig.QueueFeasibilityReject,
+			queue: newQueue("4", "0"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config.ConfigData = &wkconfig.AdmissionConfiguration{QueueFeasibility: tc.mode}
+			defer func() { config.ConfigData = nil }()
+
+			response := &admissionv1.AdmissionResponse{Allowed: true}
+			msg := validateQueueFeasibility(job, tc.queue, response)
+			if tc.expectMsg == "" && msg != "" || !strings.Contains(msg, tc.expectMsg) {
+				t.Errorf("expected message %q, got %q", tc.expectMsg, msg)
+			}
+			warnings := strings.Join(response.Warnings, ";")
+			if tc.expectWarnings == "" && warnings != "" || !strings.Contains(warnings, tc.expectWarnings) {
+				t.Errorf("expected warnings %q, got %q", tc.expectWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestGetJobMinRequest(t *testing.T) {
+	config.KubeInformerFactory = informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0)
+	defer func() { config.KubeInformerFactory = nil }()
+	config.KubeInformerFactory.Scheduling().V1().PriorityClasses().Informer().GetIndexer().Add(&schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "high-priority"},
+		Value:      1000,
+	})
+
+	newTask := func(name string, replicas, minAvailable int32, cpu, priorityClassName string) v1alpha1.TaskSpec {
+		return v1alpha1.TaskSpec{
+			Name:         name,
+			Replicas:     replicas,
+			MinAvailable: &minAvailable,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					PriorityClassName: priorityClassName,
+					Containers: []v1.Container{{
+						Name: name,
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+						},
+					}},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		minAvailable int32
+		tasks        []v1alpha1.TaskSpec
+		expectedCPU  string
+	}{
+		{
+			name:         "minAvailable of the tasks",
+			minAvailable: 3,
+			tasks:        []v1alpha1.TaskSpec{newTask("master", 1, 1, "1", ""), newTask("worker", 4, 2, "2", "")},
+			expectedCPU:  "5",
+		},
+		{
+			name:         "minAvailable of the job above the one of the tasks",
+			minAvailable: 4,
+			tasks:        []v1alpha1.TaskSpec{newTask("master", 1, 1, "1", ""), newTask("worker", 4, 2, "2", "")},
+			expectedCPU:  "7",
+		},
+		{
+			name:         "minAvailable of the job below the one of the tasks, higher priority first",
+			minAvailable: 2,
+			tasks:        []v1alpha1.TaskSpec{newTask("master", 1, 1, "1", ""), newTask("worker", 4, 2, "2", "high-priority")},
+			expectedCPU:  "4",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "job"},
+				Spec:       v1alpha1.JobSpec{MinAvailable: tc.minAvailable, Tasks: tc.tasks},
+			}
+			minReq := getJobMinRequest(job)
+			if cpu := minReq[v1.ResourceCPU]; cpu.Cmp(resource.MustParse(tc.expectedCPU)) != 0 {
+				t.Errorf("expected cpu %s, got %s", tc.expectedCPU, cpu.String())
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package validate
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/hashicorp/go-multierror"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -25,6 +27,8 @@ import (
 
 	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 // policyEventMap defines all policy events and whether to allow external use.
@@ -38,6 +42,7 @@ var policyEventMap = map[busv1alpha1.Event]bool{
 	busv1alpha1.OutOfSyncEvent:     false,
 	busv1alpha1.CommandIssuedEvent: false,
 	busv1alpha1.JobUpdatedEvent:    true,
+	apis.PodPendingTimeoutEvent:    true,
 }
 
 // policyActionMap defines all policy actions and whether to allow external use.
@@ -48,11 +53,14 @@ var policyActionMap = map[busv1alpha1.Action]bool{
 	busv1alpha1.TerminateJobAction: true,
 	busv1alpha1.CompleteJobAction:  true,
 	busv1alpha1.ResumeJobAction:    true,
+	apis.RestartPodAction:          true,
+	apis.RequeueJobAction:          true,
 	busv1alpha1.SyncJobAction:      false,
 	busv1alpha1.EnqueueAction:      false,
 	busv1alpha1.SyncQueueAction:    false,
 	busv1alpha1.OpenQueueAction:    false,
 	busv1alpha1.CloseQueueAction:   false,
+	apis.DrainQueueAction:          false,
 }
 
 func validatePolicies(policies []batchv1alpha1.LifecyclePolicy, fldPath *field.Path) error {
@@ -86,6 +94,11 @@ func validatePolicies(policies []batchv1alpha1.LifecyclePolicy, fldPath *field.P
 					bFlag = true
 					break
 				}
+				if event == apis.PodPendingTimeoutEvent && (policy.Timeout == nil || policy.Timeout.Duration <= 0) {
+					err = multierror.Append(err, fmt.Errorf("policy with event %s must specify a positive timeout", event))
+					bFlag = true
+					break
+				}
 				if _, found := policyEvents[event]; found {
 					err = multierror.Append(err, fmt.Errorf("duplicate event %v  across different policy", event))
 					bFlag = true
@@ -146,6 +159,7 @@ func getValidEvents() []busv1alpha1.Event {
 			events = append(events, e)
 		}
 	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
 
 	return events
 }
@@ -157,6 +171,7 @@ func getValidActions() []busv1alpha1.Action {
 			actions = append(actions, a)
 		}
 	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
 
 	return actions
 }
@@ -189,6 +204,79 @@ func validateIO(volumes []batchv1alpha1.VolumeSpec) error {
 	return nil
 }
 
+// validateVolumeAnnotations validates the per replica volumes and the volume retention policy of the job.
+func validateVolumeAnnotations(job *batchv1alpha1.Job) error {
+	volumeClaims := map[string]bool{}
+	for _, volume := range job.Spec.Volumes {
+		volumeClaims[volume.MountPath] = volume.VolumeClaim != nil
+	}
+	for mountPath := range jobhelpers.GetPerReplicaVolumes(job) {
+		hasVolumeClaim, found := volumeClaims[mountPath]
+		if !found {
+			return fmt.Errorf(" per replica volume %s is not found in volumes;", mountPath)
+		}
+		if !hasVolumeClaim {
+			return fmt.Errorf(" per replica volume %s must specify VolumeClaim;", mountPath)
+		}
+	}
+
+	if policy, found := job.Annotations[jobhelpers.VolumeRetentionPolicyKey]; found &&
+		policy != jobhelpers.VolumeRetentionPolicyRetain && policy != jobhelpers.VolumeRetentionPolicyDelete {
+		return fmt.Errorf(" invalid %s %s, valid policies are %s and %s;", jobhelpers.VolumeRetentionPolicyKey,
+			policy, jobhelpers.VolumeRetentionPolicyRetain, jobhelpers.VolumeRetentionPolicyDelete)
+	}
+	return nil
+}
+
+// validateElasticMinMemberPolicy validates the podgroup minMember policy of the job when its replicas are scaled.
+func validateElasticMinMemberPolicy(job *batchv1alpha1.Job) error {
+	if policy, found := job.Annotations[jobhelpers.ElasticMinMemberPolicyKey]; found &&
+		policy != jobhelpers.ElasticMinMemberPolicyMinAvailable && policy != jobhelpers.ElasticMinMemberPolicyReplicas {
+		return fmt.Errorf("invalid %s %s, valid policies are %s and %s", jobhelpers.ElasticMinMemberPolicyKey,
+			policy, jobhelpers.ElasticMinMemberPolicyMinAvailable, jobhelpers.ElasticMinMemberPolicyReplicas)
+	}
+	return nil
+}
+
+// validateGrowAfterStart validates that a job growing after start can start with part of its replicas.
+func validateGrowAfterStart(job *batchv1alpha1.Job) error {
+	value, found := job.Annotations[jobhelpers.GrowAfterStartKey]
+	if !found {
+		return nil
+	}
+	grow, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %s, must be true or false", jobhelpers.GrowAfterStartKey, value)
+	}
+	if grow && job.Annotations[jobhelpers.ElasticMinMemberPolicyKey] == jobhelpers.ElasticMinMemberPolicyReplicas {
+		return fmt.Errorf("%s conflicts with %s %s, which gang schedules all the replicas",
+			jobhelpers.GrowAfterStartKey, jobhelpers.ElasticMinMemberPolicyKey, jobhelpers.ElasticMinMemberPolicyReplicas)
+	}
+	return nil
+}
+
+// validateTaskDependencies checks the tasks depend on existing tasks other than themselves.
+func validateTaskDependencies(job *batchv1alpha1.Job) string {
+	var msg string
+	taskNames := map[string]bool{}
+	for _, task := range job.Spec.Tasks {
+		taskNames[task.Name] = true
+	}
+	for _, task := range job.Spec.Tasks {
+		if task.DependsOn == nil {
+			continue
+		}
+		for _, name := range task.DependsOn.Name {
+			if name == task.Name {
+				msg += fmt.Sprintf(" task %s can not depend on itself;", task.Name)
+			} else if !taskNames[name] {
+				msg += fmt.Sprintf(" task %s depends on unknown task %s;", task.Name, name)
+			}
+		}
+	}
+	return msg
+}
+
 // topoSort uses topo sort to sort job tasks based on dependsOn field
 // it will return an array contains all sorted task names and a bool which indicates whether it's a valid dag
 func topoSort(job *batchv1alpha1.Job) ([]string, bool) {
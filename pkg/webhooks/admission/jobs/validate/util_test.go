@@ -3,8 +3,12 @@ package validate
 import (
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 func TestTopoSort(t *testing.T) {
@@ -134,3 +138,48 @@ func TestTopoSort(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateVolumeAnnotations(t *testing.T) {
+	volumes := []v1alpha1.VolumeSpec{
+		{MountPath: "/data", VolumeClaim: &v1.PersistentVolumeClaimSpec{}},
+		{MountPath: "/shared", VolumeClaimName: "shared"},
+	}
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expectErr   bool
+	}{
+		{
+			name:        "per replica volume with volume claim",
+			annotations: map[string]string{jobhelpers.PerReplicaVolumesKey: "/data", jobhelpers.VolumeRetentionPolicyKey: "Delete"},
+		},
+		{
+			name:        "per replica volume without volume claim",
+			annotations: map[string]string{jobhelpers.PerReplicaVolumesKey: "/data,/shared"},
+			expectErr:   true,
+		},
+		{
+			name:        "unknown per replica volume",
+			annotations: map[string]string{jobhelpers.PerReplicaVolumesKey: "/scratch"},
+			expectErr:   true,
+		},
+		{
+			name:        "invalid retention policy",
+			annotations: map[string]string{jobhelpers.VolumeRetentionPolicyKey: "Keep"},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Spec:       v1alpha1.JobSpec{Volumes: volumes},
+			}
+			if err := validateVolumeAnnotations(job); (err != nil) != tc.expectErr {
+				t.Errorf("expected error: %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package mutate
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 
@@ -27,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -40,6 +40,9 @@ var service = &router.AdmissionService{
 	Path:   "/podgroups/mutate",
 	Func:   PodGroups,
 	Config: config,
+	Informers: func(config *router.AdmissionServiceConfig) {
+		config.KubeInformerFactory.Core().V1().Namespaces().Informer()
+	},
 	MutatingConfig: &whv1.MutatingWebhookConfiguration{
 		Webhooks: []whv1.MutatingWebhook{{
 			Name: "mutatepodgroup.volcano.sh",
@@ -75,9 +78,11 @@ func PodGroups(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	}
 
 	var patchBytes []byte
+	var queue, source string
 	switch ar.Request.Operation {
 	case admissionv1.Create:
-		patchBytes, err = createPodGroupPatch(podgroup)
+		queue, source = defaultQueue(podgroup)
+		patchBytes, err = createPodGroupPatch(podgroup, queue)
 	default:
 		return util.ToAdmissionResponse(fmt.Errorf("invalid operation `%s`, "+
 			"expect operation to be `CREATE`", ar.Request.Operation))
@@ -97,26 +102,42 @@ func PodGroups(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	if len(patchBytes) > 0 {
 		pt := admissionv1.PatchTypeJSONPatch
 		reviewResponse.PatchType = &pt
+		util.AddPatchAuditAnnotation(&reviewResponse, patchBytes)
 	}
+	util.AddAuditAnnotation(&reviewResponse, util.AuditOriginalQueue, podgroup.Spec.Queue)
+	util.AddAuditAnnotation(&reviewResponse, util.AuditQueue, queue)
+	util.AddAuditAnnotation(&reviewResponse, util.AuditQueueSource, source)
 	return &reviewResponse
 }
 
-func createPodGroupPatch(podgroup *schedulingv1beta1.PodGroup) ([]byte, error) {
+func createPodGroupPatch(podgroup *schedulingv1beta1.PodGroup, queue string) ([]byte, error) {
 	var patch []patchOperation
 	if len(podgroup.Spec.Queue) == 0 {
-		queueName := schedulingv1beta1.DefaultQueue
-		ns, err := config.KubeClient.CoreV1().Namespaces().Get(context.TODO(), podgroup.Namespace, metav1.GetOptions{})
-		if err == nil {
-			if val, ok := ns.GetAnnotations()[schedulingv1beta1.QueueNameAnnotationKey]; ok {
-				queueName = val
-			}
-		}
 		patch = append(patch, patchOperation{
 			Op:    "add",
 			Path:  "/spec/queue",
-			Value: queueName,
+			Value: queue,
 		})
 	}
 
 	return json.Marshal(patch)
 }
+
+// defaultQueue returns the queue the podgroup is admitted to and where it comes from.
+func defaultQueue(podgroup *schedulingv1beta1.PodGroup) (string, string) {
+	if len(podgroup.Spec.Queue) != 0 {
+		return podgroup.Spec.Queue, util.QueueSourceObject
+	}
+	return util.DefaultQueue(util.NamespaceLister(config.KubeInformerFactory), podgroup.Namespace, getJobDefaults())
+}
+
+// getJobDefaults returns the job defaults of the admission configuration, which also apply to
+// the podgroups.
+func getJobDefaults() wkconfig.JobDefaultsConfig {
+	if config.ConfigData == nil {
+		return wkconfig.JobDefaultsConfig{}
+	}
+	config.ConfigData.Lock()
+	defer config.ConfigData.Unlock()
+	return config.ConfigData.JobDefaults
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	whv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/webhooks/router"
+	"volcano.sh/volcano/pkg/webhooks/schema"
+	"volcano.sh/volcano/pkg/webhooks/util"
+)
+
+func init() {
+	router.RegisterAdmission(service)
+}
+
+var service = &router.AdmissionService{
+	Path: "/podgroups/validate",
+	Func: AdmitPodGroups,
+
+	Config: config,
+
+	Informers: func(config *router.AdmissionServiceConfig) {
+		config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Informer()
+		util.AddPodGroupQueueIndex(config.VolcanoInformerFactory.Scheduling().V1beta1().PodGroups().Informer())
+	},
+
+	ValidatingConfig: &whv1.ValidatingWebhookConfiguration{
+		Webhooks: []whv1.ValidatingWebhook{{
+			Name: "validatepodgroup.volcano.sh",
+			Rules: []whv1.RuleWithOperations{
+				{
+					Operations: []whv1.OperationType{whv1.Create, whv1.Update},
+					Rule: whv1.Rule{
+						APIGroups:   []string{schedulingv1beta1.SchemeGroupVersion.Group},
+						APIVersions: []string{schedulingv1beta1.SchemeGroupVersion.Version},
+						Resources:   []string{"podgroups"},
+					},
+				},
+			},
+		}},
+	},
+}
+
+var config = &router.AdmissionServiceConfig{}
+
+// AdmitPodGroups is to admit podgroups and return response.
+func AdmitPodGroups(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	klog.V(3).Infof("Admitting %s podgroup %s.", ar.Request.Operation, ar.Request.Name)
+
+	podgroup, err := schema.DecodePodGroup(ar.Request.Object, ar.Request.Resource)
+	if err != nil {
+		return util.ToAdmissionResponse(err)
+	}
+
+	var oldPodgroup *schedulingv1beta1.PodGroup
+	switch ar.Request.Operation {
+	case admissionv1.Create:
+		err = validatePodGroup(nil, podgroup)
+	case admissionv1.Update:
+		var decodeErr error
+		oldPodgroup, decodeErr = schema.DecodePodGroup(ar.Request.OldObject, ar.Request.Resource)
+		if decodeErr != nil {
+			return util.ToAdmissionResponse(decodeErr)
+		}
+		err = validatePodGroup(oldPodgroup, podgroup)
+	default:
+		return util.ToAdmissionResponse(fmt.Errorf("invalid operation `%s`, "+
+			"expect operation to be `CREATE` or `UPDATE`", ar.Request.Operation))
+	}
+
+	// the podgroups created by the controllers on behalf of the jobs and pods admitted before are
+	// allowed by their service accounts
+	if err == nil && queueChanged(oldPodgroup, podgroup) {
+		err = util.AuthorizeQueue(config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Lister(), config.ControllerServiceAccounts,
+			podgroup.Spec.Queue, podgroup.Namespace, ar.Request.UserInfo)
+	}
+	// the podgroups of the jobs are checked when the jobs are created
+	if err == nil && oldPodgroup == nil && !util.IsControllerServiceAccount(config.ControllerServiceAccounts, ar.Request.UserInfo) {
+		err = util.CheckQueuePendingJobs(config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Lister(),
+			config.VolcanoInformerFactory.Scheduling().V1beta1().PodGroups().Informer().GetIndexer(), podgroup.Spec.Queue)
+	}
+
+	if err != nil {
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &metav1.Status{Message: err.Error()},
+		}
+	}
+
+	return &admissionv1.AdmissionResponse{
+		Allowed: true,
+	}
+}
+
+// validatePodGroup validates a podgroup on create, oldPodgroup is nil, or on update. The minMember
+// and the queue are only checked when they change, so that the podgroups admitted before are still
+// updated, e.g. by the status updates of the scheduler.
+func validatePodGroup(oldPodgroup, podgroup *schedulingv1beta1.PodGroup) error {
+	errs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if (oldPodgroup == nil || oldPodgroup.Spec.MinMember != podgroup.Spec.MinMember) && podgroup.Spec.MinMember < 1 {
+		errs = append(errs, field.Invalid(specPath.Child("minMember"), podgroup.Spec.MinMember,
+			"podgroup minMember must be at least 1"))
+	}
+	for task, member := range podgroup.Spec.MinTaskMember {
+		if member < 0 {
+			errs = append(errs, field.Invalid(specPath.Child("minTaskMember").Key(task), member,
+				"podgroup minTaskMember must not be negative"))
+		}
+	}
+	if podgroup.Spec.MinResources != nil {
+		errs = append(errs, validateMinResources(*podgroup.Spec.MinResources, specPath.Child("minResources"))...)
+	}
+	if _, err := api.ParseTopologyConstraint(podgroup.Annotations); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "annotations"), podgroup.Annotations, err.Error()))
+	}
+	if queueChanged(oldPodgroup, podgroup) {
+		errs = append(errs, validateQueue(podgroup.Spec.Queue, specPath.Child("queue"))...)
+	}
+
+	if len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// queueChanged returns whether the podgroup is created, or moved to another queue.
+func queueChanged(oldPodgroup, podgroup *schedulingv1beta1.PodGroup) bool {
+	return oldPodgroup == nil || oldPodgroup.Spec.Queue != podgroup.Spec.Queue
+}
+
+func validateMinResources(resources v1.ResourceList, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for name, quantity := range resources {
+		if quantity.Sign() < 0 {
+			errs = append(errs, field.Invalid(fldPath.Key(string(name)), quantity.String(),
+				"podgroup minResources must not be negative"))
+		}
+	}
+	return errs
+}
+
+func validateQueue(queueName string, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	// the queue is defaulted by the mutating webhook
+	if queueName == "" {
+		return errs
+	}
+
+	queue, err := config.VolcanoClient.SchedulingV1beta1().Queues().Get(context.TODO(), queueName, metav1.GetOptions{})
+	if err != nil {
+		return append(errs, field.Invalid(fldPath, queueName, fmt.Sprintf("unable to find queue: %v", err)))
+	}
+	if queue.Status.State != schedulingv1beta1.QueueStateOpen {
+		return append(errs, field.Invalid(fldPath, queueName,
+			fmt.Sprintf("can only submit podgroup to queue with state `Open`, queue status is `%s`", queue.Status.State)))
+	}
+	return errs
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestValidatePodGroup(t *testing.T) {
+	openQueue := &schedulingv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "open"},
+		Status:     schedulingv1beta1.QueueStatus{State: schedulingv1beta1.QueueStateOpen},
+	}
+	closedQueue := &schedulingv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "closed"},
+		Status:     schedulingv1beta1.QueueStatus{State: schedulingv1beta1.QueueStateClosed},
+	}
+
+	config.VolcanoClient = vcclient.NewSimpleClientset()
+	for _, queue := range []*schedulingv1beta1.Queue{openQueue, closedQueue} {
+		if _, err := config.VolcanoClient.SchedulingV1beta1().Queues().Create(context.TODO(), queue, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create queue %s: %v", queue.Name, err)
+		}
+	}
+
+	testCases := []struct {
+		Name         string
+		MinMember    int32
+		Resources    *v1.ResourceList
+		Queue        string
+		Annotations  map[string]string
+		Update       bool
+		OldMinMember int32
+		OldQueue     string
+		ExpectErr    string
+	}{
+		{
+			Name:      "valid podgroup",
+			MinMember: 2,
+			Resources: &v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+			Queue:     "open",
+		},
+		{
+			Name:      "zero minMember",
+			MinMember: 0,
+			Queue:     "open",
+			ExpectErr: "podgroup minMember must be at least 1",
+		},
+		{
+			Name:         "zero minMember unchanged on update",
+			MinMember:    0,
+			Queue:        "open",
+			Update:       true,
+			OldMinMember: 0,
+			OldQueue:     "open",
+		},
+		{
+			Name:         "minMember changed to zero on update",
+			MinMember:    0,
+			Queue:        "open",
+			Update:       true,
+			OldMinMember: 2,
+			OldQueue:     "open",
+			ExpectErr:    "podgroup minMember must be at least 1",
+		},
+		{
+			Name:      "negative minResources",
+			MinMember: 1,
+			Resources: &v1.ResourceList{v1.ResourceMemory: resource.MustParse("-1Gi")},
+			Queue:     "open",
+			ExpectErr: "podgroup minResources must not be negative",
+		},
+		{
+			Name:      "queue not found",
+			MinMember: 1,
+			Queue:     "missing",
+			ExpectErr: "unable to find queue",
+		},
+		{
+			Name:      "closed queue",
+			MinMember: 1,
+			Queue:     "closed",
+			ExpectErr: "can only submit podgroup to queue with state `Open`",
+		},
+		{
+			Name:         "closed queue unchanged on update",
+			MinMember:    1,
+			Queue:        "closed",
+			Update:       true,
+			OldMinMember: 1,
+			OldQueue:     "closed",
+		},
+		{
+			Name:         "moved to closed queue on update",
+			MinMember:    1,
+			Queue:        "closed",
+			Update:       true,
+			OldMinMember: 1,
+			OldQueue:     "open",
+			ExpectErr:    "can only submit podgroup to queue with state `Open`",
+		},
+		{
+			Name:      "valid topology constraint",
+			MinMember: 1,
+			Queue:     "open",
+			Annotations: map[string]string{
+				api.JobTopologyKeyAnnotationKey:  "topology.kubernetes.io/zone",
+				api.JobTopologyModeAnnotationKey: api.TopologyModePreferred,
+			},
+		},
+		{
+			Name:      "invalid topology mode",
+			MinMember: 1,
+			Queue:     "open",
+			Annotations: map[string]string{
+				api.JobTopologyKeyAnnotationKey:  "topology.kubernetes.io/zone",
+				api.JobTopologyModeAnnotationKey: "strict",
+			},
+			ExpectErr: "invalid volcano.sh/topology-mode <strict>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			podgroup := &schedulingv1beta1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg", Annotations: testCase.Annotations},
+				Spec: schedulingv1beta1.PodGroupSpec{
+					MinMember:    testCase.MinMember,
+					MinResources: testCase.Resources,
+					Queue:        testCase.Queue,
+				},
+			}
+			var oldPodgroup *schedulingv1beta1.PodGroup
+			if testCase.Update {
+				oldPodgroup = podgroup.DeepCopy()
+				oldPodgroup.Spec.MinMember = testCase.OldMinMember
+				oldPodgroup.Spec.Queue = testCase.OldQueue
+			}
+			err := validatePodGroup(oldPodgroup, podgroup)
+			if testCase.ExpectErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.ExpectErr) {
+				t.Errorf("expected error containing %q, got %v", testCase.ExpectErr, err)
+			}
+		})
+	}
+}
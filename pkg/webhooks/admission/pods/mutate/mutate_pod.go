@@ -25,6 +25,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
+	commonutil "volcano.sh/volcano/pkg/util"
 	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
@@ -38,6 +39,13 @@ type patchOperation struct {
 	Value interface{} `json:"value,omitempty"`
 }
 
+const (
+	// ScheduleWithVolcanoKey is the pod annotation or the namespace label opting pods in to be
+	// scheduled by volcano, e.g. the pods created by third-party operators. The PodGroup of such
+	// a pod is created by the podgroup controller. A pod annotated with "false" opts out of its namespace.
+	ScheduleWithVolcanoKey = "volcano.sh/schedule-with-volcano"
+)
+
 // init register mutate pod
 func init() {
 	router.RegisterAdmission(service)
@@ -47,6 +55,9 @@ var service = &router.AdmissionService{
 	Path:   "/pods/mutate",
 	Func:   Pods,
 	Config: config,
+	Informers: func(config *router.AdmissionServiceConfig) {
+		config.KubeInformerFactory.Core().V1().Namespaces().Informer()
+	},
 	MutatingConfig: &whv1.MutatingWebhookConfiguration{
 		Webhooks: []whv1.MutatingWebhook{{
 			Name: "mutatepod.volcano.sh",
@@ -94,6 +105,7 @@ func Pods(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	if len(patchBytes) > 0 {
 		pt := admissionv1.PatchTypeJSONPatch
 		reviewResponse.PatchType = &pt
+		util.AddPatchAuditAnnotation(&reviewResponse, patchBytes)
 	}
 
 	return &reviewResponse
@@ -101,12 +113,20 @@ func Pods(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 
 // createPatch patch pod
 func createPatch(pod *v1.Pod) ([]byte, error) {
+	var patch []patchOperation
+	// the scheduler of the resource group, if any, takes precedence as it is patched later
+	if patchScheduler := patchVolcanoSchedulerName(pod); patchScheduler != nil {
+		patch = append(patch, *patchScheduler)
+	}
+
 	if config.ConfigData == nil {
 		klog.V(5).Infof("admission configuration is empty.")
-		return nil, nil
+		if len(patch) == 0 {
+			return nil, nil
+		}
+		return json.Marshal(patch)
 	}
 
-	var patch []patchOperation
 	config.ConfigData.Lock()
 	defer config.ConfigData.Unlock()
 
@@ -204,3 +224,35 @@ func patchSchedulerName(resGroupConfig wkconfig.ResGroupConfig) *patchOperation
 
 	return &patchOperation{Op: "add", Path: "/spec/schedulerName", Value: resGroupConfig.SchedulerName}
 }
+
+// patchVolcanoSchedulerName routes the pods opted in to volcano to the volcano scheduler,
+// pods which specify another scheduler are left to it.
+func patchVolcanoSchedulerName(pod *v1.Pod) *patchOperation {
+	if pod.Spec.SchedulerName != "" && pod.Spec.SchedulerName != v1.DefaultSchedulerName {
+		return nil
+	}
+	if !scheduleWithVolcano(pod) {
+		return nil
+	}
+
+	klog.V(3).Infof("Pod <%s/%s> is opted in to be scheduled by volcano", pod.Namespace, pod.Name)
+	return &patchOperation{Op: "add", Path: "/spec/schedulerName", Value: commonutil.GenerateSchedulerName(config.SchedulerNames)}
+}
+
+// scheduleWithVolcano returns whether the pod or its namespace is opted in to volcano.
+func scheduleWithVolcano(pod *v1.Pod) bool {
+	if value, found := pod.Annotations[ScheduleWithVolcanoKey]; found {
+		return value == "true"
+	}
+	namespaceLister := util.NamespaceLister(config.KubeInformerFactory)
+	if namespaceLister == nil {
+		return false
+	}
+
+	ns, err := namespaceLister.Get(pod.Namespace)
+	if err != nil {
+		klog.Errorf("Failed to get namespace %s of pod %s: %v", pod.Namespace, pod.Name, err)
+		return false
+	}
+	return ns.Labels[ScheduleWithVolcanoKey] == "true"
+}
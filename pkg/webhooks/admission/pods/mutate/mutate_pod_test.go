@@ -23,6 +23,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
 
 	webconfig "volcano.sh/volcano/pkg/webhooks/config"
 )
@@ -216,3 +218,62 @@ func TestMutatePods(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchVolcanoSchedulerName(t *testing.T) {
+	config.KubeInformerFactory = informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	defer func() { config.KubeInformerFactory = nil }()
+	for _, ns := range []*v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "opted-in", Labels: map[string]string{ScheduleWithVolcanoKey: "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	} {
+		config.KubeInformerFactory.Core().V1().Namespaces().Informer().GetIndexer().Add(ns)
+	}
+
+	newPod := func(namespace, schedulerName string, annotations map[string]string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "pod", Annotations: annotations},
+			Spec:       v1.PodSpec{SchedulerName: schedulerName},
+		}
+	}
+
+	testCases := []struct {
+		Name   string
+		Pod    *v1.Pod
+		Routed bool
+	}{
+		{
+			Name:   "annotated pod",
+			Pod:    newPod("other", v1.DefaultSchedulerName, map[string]string{ScheduleWithVolcanoKey: "true"}),
+			Routed: true,
+		},
+		{
+			Name:   "pod in opted-in namespace",
+			Pod:    newPod("opted-in", "", nil),
+			Routed: true,
+		},
+		{
+			Name: "pod opted out of its namespace",
+			Pod:  newPod("opted-in", "", map[string]string{ScheduleWithVolcanoKey: "false"}),
+		},
+		{
+			Name: "pod of another scheduler",
+			Pod:  newPod("opted-in", "my-scheduler", nil),
+		},
+		{
+			Name: "pod in other namespace",
+			Pod:  newPod("other", "", nil),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			patch := patchVolcanoSchedulerName(testCase.Pod)
+			if (patch != nil) != testCase.Routed {
+				t.Fatalf("expected routed %v, but got patch %v", testCase.Routed, patch)
+			}
+			if patch != nil && patch.Value != "volcano" {
+				t.Errorf("expected scheduler volcano, but got %v", patch.Value)
+			}
+		})
+	}
+}
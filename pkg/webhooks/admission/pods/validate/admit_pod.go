@@ -25,6 +25,7 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +34,7 @@ import (
 
 	"volcano.sh/apis/pkg/apis/helpers"
 	vcv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -48,6 +50,10 @@ var service = &router.AdmissionService{
 
 	Config: config,
 
+	Informers: func(config *router.AdmissionServiceConfig) {
+		config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Informer()
+	},
+
 	ValidatingConfig: &whv1.ValidatingWebhookConfiguration{
 		Webhooks: []whv1.ValidatingWebhook{{
 			Name: "validatepod.volcano.sh",
@@ -83,6 +89,12 @@ func AdmitPods(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	switch ar.Request.Operation {
 	case admissionv1.Create:
 		msg = validatePod(pod, &reviewResponse)
+		if reviewResponse.Allowed {
+			if err := authorizePodQueue(pod, ar.Request.UserInfo); err != nil {
+				msg = err.Error()
+				reviewResponse.Allowed = false
+			}
+		}
 	default:
 		err := fmt.Errorf("expect operation to be 'CREATE'")
 		return util.ToAdmissionResponse(err)
@@ -145,6 +157,17 @@ func validatePod(pod *v1.Pod, reviewResponse *admissionv1.AdmissionResponse) str
 	return msg
 }
 
+// authorizePodQueue checks the ACL of the queue the pod is submitted to by its queue-name annotation,
+// since the podgroup of the pod is created by the controllers on its behalf. The pods of a podgroup
+// join the queue of the podgroup, which is checked when the podgroup is admitted.
+func authorizePodQueue(pod *v1.Pod, user authenticationv1.UserInfo) error {
+	if !slices.Contains(config.SchedulerNames, pod.Spec.SchedulerName) || pod.Annotations[vcv1beta1.KubeGroupNameAnnotationKey] != "" {
+		return nil
+	}
+	return util.AuthorizeQueue(config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Lister(), config.ControllerServiceAccounts,
+		pod.Annotations[vcv1beta1.QueueNameAnnotationKey], pod.Namespace, user)
+}
+
 func checkPG(pod *v1.Pod, pgName string, isVCJob bool) error {
 	_, err := config.VolcanoClient.SchedulingV1beta1().PodGroups(pod.Namespace).Get(context.TODO(), pgName, metav1.GetOptions{})
 	if err != nil {
@@ -181,6 +204,9 @@ func checkQueueState(queueName string) error {
 }
 
 func validateAnnotation(pod *v1.Pod) error {
+	if _, _, err := apis.ParseGang(pod.Annotations); err != nil {
+		return err
+	}
 	num := 0
 	if len(pod.Annotations) > 0 {
 		keys := []string{
@@ -22,11 +22,15 @@ import (
 	"testing"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	vcschedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/webhooks/util"
 )
 
 func TestValidatePod(t *testing.T) {
@@ -229,6 +233,50 @@ func TestValidatePod(t *testing.T) {
 			ExpectErr:      false,
 			queueName:      "",
 		},
+		// validate gang pod with volcano scheduler
+		{
+			Name: "validate gang pod with volcano scheduler",
+			Pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "volcano-pod-9",
+					Annotations: map[string]string{
+						apis.GangNameKey:      "spark-app",
+						apis.GangMinMemberKey: "3",
+					},
+				},
+				Spec: v1.PodSpec{
+					SchedulerName: "volcano",
+				},
+			},
+
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "",
+			ExpectErr:      false,
+			disabledPG:     true,
+		},
+		// validate gang pod with invalid min member
+		{
+			Name: "validate gang pod with invalid min member",
+			Pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "volcano-pod-10",
+					Annotations: map[string]string{
+						apis.GangNameKey:      "spark-app",
+						apis.GangMinMemberKey: "0",
+					},
+				},
+				Spec: v1.PodSpec{
+					SchedulerName: "volcano",
+				},
+			},
+
+			reviewResponse: admissionv1.AdmissionResponse{Allowed: true},
+			ret:            "must be a positive integer",
+			ExpectErr:      true,
+			disabledPG:     true,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -297,3 +345,75 @@ func TestValidatePod(t *testing.T) {
 		}
 	}
 }
+
+func TestAuthorizePodQueue(t *testing.T) {
+	config.VolcanoClient = vcclient.NewSimpleClientset()
+	config.VolcanoInformerFactory = vcinformer.NewSharedInformerFactory(config.VolcanoClient, 0)
+	config.SchedulerNames = []string{"volcano"}
+	config.ControllerServiceAccounts = []string{"volcano-system/volcano-controllers"}
+	defer func() {
+		config.VolcanoInformerFactory = nil
+		config.ControllerServiceAccounts = nil
+	}()
+	premium := &vcschedulingv1.Queue{ObjectMeta: metav1.ObjectMeta{
+		Name:        "premium",
+		Annotations: map[string]string{util.QueueACLKey: `{"users":["alice"]}`},
+	}}
+	if err := config.VolcanoInformerFactory.Scheduling().V1beta1().Queues().Informer().GetIndexer().Add(premium); err != nil {
+		t.Fatalf("failed to add queue: %v", err)
+	}
+
+	newPod := func(schedulerName string, annotations map[string]string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "pod", Annotations: annotations},
+			Spec:       v1.PodSpec{SchedulerName: schedulerName},
+		}
+	}
+	toPremium := map[string]string{vcschedulingv1.QueueNameAnnotationKey: "premium"}
+	bob := authenticationv1.UserInfo{Username: "bob"}
+
+	testCases := []struct {
+		name      string
+		pod       *v1.Pod
+		user      authenticationv1.UserInfo
+		expectErr bool
+	}{
+		{name: "pod without queue", pod: newPod("volcano", nil), user: bob},
+		{name: "pod of another scheduler", pod: newPod("default-scheduler", toPremium), user: bob},
+		{name: "allowed user", pod: newPod("volcano", toPremium), user: authenticationv1.UserInfo{Username: "alice"}},
+		{name: "user not allowed", pod: newPod("volcano", toPremium), user: bob, expectErr: true},
+		{
+			name: "user not allowed with a forged owner",
+			pod: func() *v1.Pod {
+				pod := newPod("volcano", toPremium)
+				controller := true
+				pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs", Controller: &controller}}
+				return pod
+			}(),
+			user:      bob,
+			expectErr: true,
+		},
+		{
+			name: "pod of a podgroup",
+			pod: newPod("volcano", map[string]string{
+				vcschedulingv1.QueueNameAnnotationKey:     "premium",
+				vcschedulingv1.KubeGroupNameAnnotationKey: "pg",
+			}),
+			user: bob,
+		},
+		{
+			name: "controller service account",
+			pod:  newPod("volcano", toPremium),
+			user: authenticationv1.UserInfo{Username: "system:serviceaccount:volcano-system:volcano-controllers"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := authorizePodQueue(tc.pod, tc.user)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expected error %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
@@ -95,6 +95,7 @@ func Queues(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	if len(patchBytes) > 0 {
 		pt := admissionv1.PatchTypeJSONPatch
 		reviewResponse.PatchType = &pt
+		util.AddPatchAuditAnnotation(&reviewResponse, patchBytes)
 	}
 	return &reviewResponse
 }
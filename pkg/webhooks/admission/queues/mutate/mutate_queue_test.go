@@ -147,9 +147,10 @@ func TestMutateQueues(t *testing.T) {
 				},
 			},
 			reviewResponse: &admissionv1.AdmissionResponse{
-				Allowed:   true,
-				PatchType: &pt,
-				Patch:     refreshPatchJSON,
+				Allowed:          true,
+				PatchType:        &pt,
+				Patch:            refreshPatchJSON,
+				AuditAnnotations: map[string]string{util.AuditPatchedPaths: "add /spec/reclaimable"},
 			},
 		},
 		{
@@ -209,6 +210,8 @@ func TestMutateQueues(t *testing.T) {
 				Allowed:   true,
 				PatchType: &pt,
 				Patch:     appendRootPatchJSON,
+				AuditAnnotations: map[string]string{util.AuditPatchedPaths: fmt.Sprintf("add %s,add %s",
+					appendRootPatch[0].Path, appendRootPatch[1].Path)},
 			},
 		},
 	}
@@ -19,16 +19,22 @@ package validate
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -44,6 +50,10 @@ var service = &router.AdmissionService{
 
 	Config: config,
 
+	Informers: func(config *router.AdmissionServiceConfig) {
+		config.VolcanoInformerFactory.Scheduling().V1beta1().PodGroups().Informer()
+	},
+
 	ValidatingConfig: &whv1.ValidatingWebhookConfiguration{
 		Webhooks: []whv1.ValidatingWebhook{{
 			Name: "validatequeue.volcano.sh",
@@ -63,6 +73,10 @@ var service = &router.AdmissionService{
 
 var config = &router.AdmissionServiceConfig{}
 
+// ForceCapabilityUpdateKey is the queue annotation allowing to shrink the capability of the queue
+// below its allocated resources, the jobs holding the resources are not evicted by the update.
+const ForceCapabilityUpdateKey = "volcano.sh/force-capability-update"
+
 // AdmitQueues is to admit queues and return response.
 func AdmitQueues(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	klog.V(3).Infof("Admitting %s queue %s.", ar.Request.Operation, ar.Request.Name)
@@ -73,8 +87,16 @@ func AdmitQueues(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse
 	}
 
 	switch ar.Request.Operation {
-	case admissionv1.Create, admissionv1.Update:
+	case admissionv1.Create:
 		err = validateQueue(queue)
+	case admissionv1.Update:
+		err = validateQueue(queue)
+		if err == nil {
+			var oldQueue *schedulingv1beta1.Queue
+			if oldQueue, err = schema.DecodeQueue(ar.Request.OldObject, ar.Request.Resource); err == nil {
+				err = validateQueueCapability(oldQueue, queue, ar.Request.UserInfo)
+			}
+		}
 	case admissionv1.Delete:
 		err = validateQueueDeleting(ar.Request.Name)
 	default:
@@ -101,6 +123,14 @@ func validateQueue(queue *schedulingv1beta1.Queue) error {
 	errs = append(errs, validateStateOfQueue(queue.Status.State, resourcePath.Child("spec").Child("state"))...)
 	errs = append(errs, validateWeightOfQueue(queue.Spec.Weight, resourcePath.Child("spec").Child("weight"))...)
 	errs = append(errs, validateHierarchicalAttributes(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validateBorrowLimit(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validateJobLimits(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validateJobOrderPolicy(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validatePreemptionPolicy(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validateBackfill(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validateCapabilitySchedule(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validateACL(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validateDrainPolicy(queue, resourcePath.Child("metadata").Child("annotations"))...)
 
 	if len(errs) > 0 {
 		return errs.ToAggregate()
@@ -108,6 +138,87 @@ func validateQueue(queue *schedulingv1beta1.Queue) error {
 
 	return nil
 }
+
+func validateBorrowLimit(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if _, err := api.ParseQueueBorrowLimit(queue.Annotations); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Key(api.QueueBorrowLimitAnnotationKey),
+			queue.Annotations[api.QueueBorrowLimitAnnotationKey], err.Error()))
+	}
+	return errs
+}
+
+func validateJobLimits(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for _, key := range []string{api.QueueMaxRunningJobsAnnotationKey, api.QueueMaxPendingJobsAnnotationKey} {
+		if _, err := api.ParseQueueJobLimit(queue.Annotations, key); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Key(key), queue.Annotations[key], err.Error()))
+		}
+	}
+	return errs
+}
+
+func validateJobOrderPolicy(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if _, err := api.ParseQueueJobOrderPolicy(queue.Annotations); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Key(api.QueueJobOrderPolicyAnnotationKey),
+			queue.Annotations[api.QueueJobOrderPolicyAnnotationKey], err.Error()))
+	}
+	return errs
+}
+
+func validatePreemptionPolicy(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if _, err := api.ParseQueueJobLimit(queue.Annotations, api.QueueMaxPreemptionVictimsAnnotationKey); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Key(api.QueueMaxPreemptionVictimsAnnotationKey),
+			queue.Annotations[api.QueueMaxPreemptionVictimsAnnotationKey], err.Error()))
+	}
+	if _, err := api.ParseQueueDuration(queue.Annotations, api.QueuePreemptionMinRuntimeAnnotationKey); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Key(api.QueuePreemptionMinRuntimeAnnotationKey),
+			queue.Annotations[api.QueuePreemptionMinRuntimeAnnotationKey], err.Error()))
+	}
+	return errs
+}
+
+func validateACL(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if _, err := util.ParseQueueACL(queue.Annotations); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Key(util.QueueACLKey), queue.Annotations[util.QueueACLKey], err.Error()))
+	}
+	return errs
+}
+
+func validateDrainPolicy(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if target := queue.Annotations[apis.QueueDrainTargetKey]; target == queue.Name {
+		errs = append(errs, field.Invalid(fldPath.Key(apis.QueueDrainTargetKey), target,
+			"the podgroups of a queue can not be drained to the queue itself"))
+	}
+	if _, err := apis.ParseDrainEvictAfter(queue.Annotations); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Key(apis.QueueDrainEvictAfterKey),
+			queue.Annotations[apis.QueueDrainEvictAfterKey], err.Error()))
+	}
+	return errs
+}
+
+func validateBackfill(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if _, err := api.ParseQueueDuration(queue.Annotations, api.QueueBackfillMaxDurationAnnotationKey); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Key(api.QueueBackfillMaxDurationAnnotationKey),
+			queue.Annotations[api.QueueBackfillMaxDurationAnnotationKey], err.Error()))
+	}
+	return errs
+}
+
+func validateCapabilitySchedule(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if _, err := apis.ParseCapabilitySchedule(queue.Annotations); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Key(apis.QueueCapabilityScheduleKey),
+			queue.Annotations[apis.QueueCapabilityScheduleKey], err.Error()))
+	}
+	return errs
+}
+
 func validateHierarchicalAttributes(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
 	errs := field.ErrorList{}
 	hierarchy := queue.Annotations[schedulingv1beta1.KubeHierarchyAnnotationKey]
@@ -176,6 +287,7 @@ func validateStateOfQueue(value schedulingv1beta1.QueueState, fldPath *field.Pat
 	validQueueStates := []schedulingv1beta1.QueueState{
 		schedulingv1beta1.QueueStateOpen,
 		schedulingv1beta1.QueueStateClosed,
+		apis.QueueStateDraining,
 	}
 
 	for _, validQueue := range validQueueStates {
@@ -205,5 +317,75 @@ func validateQueueDeleting(queue string) error {
 		return err
 	}
 
+	blocking, err := listQueuePodGroups(queue, func(pg *schedulingv1beta1.PodGroup) bool {
+		return pg.Status.Phase != schedulingv1beta1.PodGroupCompleted
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list podgroups of queue %s: %v", queue, err)
+	}
+	if len(blocking) != 0 {
+		return fmt.Errorf("queue %s can not be deleted, it still has jobs %v", queue, blocking)
+	}
+
 	return nil
 }
+
+// validateQueueCapability rejects shrinking the capability of the queue below its allocated
+// resources, unless the update is forced by annotation or made by the controllers, which change the
+// capability by the schedule of the queue. Only the resources whose capability is changed by the
+// update are checked, so that the updates of the status or of the annotations of the queue are
+// always allowed.
+func validateQueueCapability(oldQueue, queue *schedulingv1beta1.Queue, user authenticationv1.UserInfo) error {
+	if queue.Annotations[ForceCapabilityUpdateKey] == "true" || len(queue.Spec.Capability) == 0 ||
+		equality.Semantic.DeepEqual(oldQueue.Spec.Capability, queue.Spec.Capability) ||
+		util.IsControllerServiceAccount(config.ControllerServiceAccounts, user) {
+		return nil
+	}
+
+	var exceeded []string
+	for name, allocated := range queue.Status.Allocated {
+		capability, found := queue.Spec.Capability[name]
+		if old, ok := oldQueue.Spec.Capability[name]; ok && old.Cmp(capability) == 0 {
+			continue
+		}
+		if found && capability.Cmp(allocated) < 0 {
+			exceeded = append(exceeded, fmt.Sprintf("%s capability %s < allocated %s", name, capability.String(), allocated.String()))
+		}
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+	sort.Strings(exceeded)
+
+	blocking, err := listQueuePodGroups(queue.Name, func(pg *schedulingv1beta1.PodGroup) bool {
+		return pg.Status.Phase == schedulingv1beta1.PodGroupRunning || pg.Status.Phase == schedulingv1beta1.PodGroupUnknown
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list podgroups of queue %s: %v", queue.Name, err)
+	}
+	return fmt.Errorf("capability of queue %s is below its allocated resources (%s) held by jobs %v, "+
+		"set annotation %s=true to force the update", queue.Name, strings.Join(exceeded, ", "), blocking, ForceCapabilityUpdateKey)
+}
+
+// listQueuePodGroups returns the jobs of the podgroups in the queue matching the filter, as
+// namespace/name of the owner of the podgroup.
+func listQueuePodGroups(queue string, filter func(pg *schedulingv1beta1.PodGroup) bool) ([]string, error) {
+	pgs, err := config.VolcanoInformerFactory.Scheduling().V1beta1().PodGroups().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []string
+	for _, pg := range pgs {
+		if pg.Spec.Queue != queue || !filter(pg) {
+			continue
+		}
+		name := pg.Name
+		if owner := metav1.GetControllerOf(pg); owner != nil {
+			name = owner.Name
+		}
+		jobs = append(jobs, pg.Namespace+"/"+name)
+	}
+	sort.Strings(jobs)
+	return jobs, nil
+}
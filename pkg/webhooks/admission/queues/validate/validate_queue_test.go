@@ -20,16 +20,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	fakeclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/webhooks/util"
 )
 
@@ -287,7 +294,7 @@ func TestAdmitQueues(t *testing.T) {
 	if err != nil {
 		t.Errorf("Marshal  hierarchicalQueueWithNameThatIsSubstringOfOtherQueue failed for %v.", err)
 	}
-	config.VolcanoClient = fakeclient.NewSimpleClientset()
+	setVolcanoClient(t)
 	_, err = config.VolcanoClient.SchedulingV1beta1().Queues().Create(context.TODO(), &openStateForDelete, metav1.CreateOptions{})
 	if err != nil {
 		t.Errorf("Create queue with open state failed for %v.", err)
@@ -956,3 +963,298 @@ func TestAdmitQueues(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateQueueInUse(t *testing.T) {
+	newPodGroup := func(name, queue string, phase schedulingv1beta1.PodGroupPhase) *schedulingv1beta1.PodGroup {
+		return &schedulingv1beta1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      name + "-pg",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "Job", Name: name, Controller: func() *bool { b := true; return &b }()},
+				},
+			},
+			Spec:   schedulingv1beta1.PodGroupSpec{Queue: queue},
+			Status: schedulingv1beta1.PodGroupStatus{Phase: phase},
+		}
+	}
+	newQueue := func(name, capability string, annotations map[string]string) *schedulingv1beta1.Queue {
+		return &schedulingv1beta1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+			Spec: schedulingv1beta1.QueueSpec{
+				Weight:     1,
+				Capability: v1.ResourceList{v1.ResourceCPU: resource.MustParse(capability)},
+			},
+			Status: schedulingv1beta1.QueueStatus{
+				Allocated: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			},
+		}
+	}
+
+	setVolcanoClient(t,
+		newQueue("busy", "8", nil),
+		newQueue("idle", "8", nil),
+		newPodGroup("train", "busy", schedulingv1beta1.PodGroupRunning),
+		newPodGroup("serve", "busy", schedulingv1beta1.PodGroupPending),
+		newPodGroup("finished", "idle", schedulingv1beta1.PodGroupCompleted),
+	)
+	busy := newQueue("busy", "8", nil)
+	user := authenticationv1.UserInfo{Username: "alice"}
+	config.ControllerServiceAccounts = []string{"volcano-system/volcano-controllers"}
+	defer func() { config.ControllerServiceAccounts = nil }()
+
+	testCases := []struct {
+		Name      string
+		Validate  func() error
+		ExpectErr string
+	}{
+		{
+			Name:      "queue with jobs can not be deleted",
+			Validate:  func() error { return validateQueueDeleting("busy") },
+			ExpectErr: "it still has jobs [test/serve test/train]",
+		},
+		{
+			Name:     "queue with completed jobs only can be deleted",
+			Validate: func() error { return validateQueueDeleting("idle") },
+		},
+		{
+			Name:     "capability above allocated",
+			Validate: func() error { return validateQueueCapability(busy, newQueue("busy", "4", nil), user) },
+		},
+		{
+			Name:      "capability below allocated",
+			Validate:  func() error { return validateQueueCapability(busy, newQueue("busy", "2", nil), user) },
+			ExpectErr: "(cpu capability 2 < allocated 4) held by jobs [test/train]",
+		},
+		{
+			Name: "forced capability below allocated",
+			Validate: func() error {
+				return validateQueueCapability(busy, newQueue("busy", "2", map[string]string{ForceCapabilityUpdateKey: "true"}), user)
+			},
+		},
+		{
+			Name: "capability below allocated changed by the schedule of the queue controller",
+			Validate: func() error {
+				return validateQueueCapability(busy, newQueue("busy", "2", nil),
+					authenticationv1.UserInfo{Username: "system:serviceaccount:volcano-system:volcano-controllers"})
+			},
+		},
+		{
+			Name: "unchanged capability below allocated on status update",
+			Validate: func() error {
+				return validateQueueCapability(newQueue("busy", "2", nil), newQueue("busy", "2", nil), user)
+			},
+		},
+		{
+			Name: "unchanged capability below allocated on annotation update",
+			Validate: func() error {
+				return validateQueueCapability(newQueue("busy", "2", nil), newQueue("busy", "2", map[string]string{"note": "shrunk"}), user)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			err := testCase.Validate()
+			if testCase.ExpectErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, but got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.ExpectErr) {
+				t.Errorf("expected error %q, but got %v", testCase.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateBorrowLimit(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		BorrowLimit string
+		ExpectErr   bool
+	}{
+		{Name: "no borrow limit"},
+		{Name: "valid borrow limit", BorrowLimit: `{"cpu":"4","memory":"8Gi"}`},
+		{Name: "malformed borrow limit", BorrowLimit: `cpu=4`, ExpectErr: true},
+		{Name: "negative borrow limit", BorrowLimit: `{"cpu":"-1"}`, ExpectErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			queue := &schedulingv1beta1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "borrow", Annotations: map[string]string{}},
+				Spec:       schedulingv1beta1.QueueSpec{Weight: 1},
+				Status:     schedulingv1beta1.QueueStatus{State: schedulingv1beta1.QueueStateOpen},
+			}
+			if testCase.BorrowLimit != "" {
+				queue.Annotations[api.QueueBorrowLimitAnnotationKey] = testCase.BorrowLimit
+			}
+			err := validateQueue(queue)
+			if (err != nil) != testCase.ExpectErr {
+				t.Errorf("expected error %v, got %v", testCase.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateJobPolicies(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Annotations map[string]string
+		ExpectErr   bool
+	}{
+		{Name: "no job limits"},
+		{
+			Name: "valid job limits",
+			Annotations: map[string]string{
+				api.QueueMaxRunningJobsAnnotationKey: "10",
+				api.QueueMaxPendingJobsAnnotationKey: "100",
+			},
+		},
+		{
+			Name:        "zero max running jobs",
+			Annotations: map[string]string{api.QueueMaxRunningJobsAnnotationKey: "0"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "malformed max pending jobs",
+			Annotations: map[string]string{api.QueueMaxPendingJobsAnnotationKey: "many"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "valid job order policy",
+			Annotations: map[string]string{api.QueueJobOrderPolicyAnnotationKey: "sjf"},
+		},
+		{
+			Name:        "unknown job order policy",
+			Annotations: map[string]string{api.QueueJobOrderPolicyAnnotationKey: "lifo"},
+			ExpectErr:   true,
+		},
+		{
+			Name: "valid preemption policy",
+			Annotations: map[string]string{
+				api.QueueMaxPreemptionVictimsAnnotationKey: "2",
+				api.QueuePreemptionMinRuntimeAnnotationKey: "30m",
+			},
+		},
+		{
+			Name:        "zero max preemption victims",
+			Annotations: map[string]string{api.QueueMaxPreemptionVictimsAnnotationKey: "0"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "malformed preemption min runtime",
+			Annotations: map[string]string{api.QueuePreemptionMinRuntimeAnnotationKey: "30"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "valid backfill max duration",
+			Annotations: map[string]string{api.QueueBackfillMaxDurationAnnotationKey: "30m"},
+		},
+		{
+			Name:        "negative backfill max duration",
+			Annotations: map[string]string{api.QueueBackfillMaxDurationAnnotationKey: "-30m"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "valid ACL",
+			Annotations: map[string]string{util.QueueACLKey: `{"namespaces":["team-a"],"serviceAccounts":["ci/runner"]}`},
+		},
+		{
+			Name:        "ACL with malformed service account",
+			Annotations: map[string]string{util.QueueACLKey: `{"serviceAccounts":["runner"]}`},
+			ExpectErr:   true,
+		},
+		{
+			Name: "valid drain policy",
+			Annotations: map[string]string{
+				apis.QueueDrainTargetKey:     "default",
+				apis.QueueDrainEvictAfterKey: "2h",
+			},
+		},
+		{
+			Name:        "drain to the queue itself",
+			Annotations: map[string]string{apis.QueueDrainTargetKey: "limits"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "malformed drain eviction deadline",
+			Annotations: map[string]string{apis.QueueDrainEvictAfterKey: "tomorrow"},
+			ExpectErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			queue := &schedulingv1beta1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "limits", Annotations: testCase.Annotations},
+				Spec:       schedulingv1beta1.QueueSpec{Weight: 1},
+				Status:     schedulingv1beta1.QueueStatus{State: schedulingv1beta1.QueueStateOpen},
+			}
+			err := validateQueue(queue)
+			if (err != nil) != testCase.ExpectErr {
+				t.Errorf("expected error %v, got %v", testCase.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateCapabilitySchedule(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Schedule  string
+		ExpectErr bool
+	}{
+		{Name: "no capability schedule"},
+		{
+			Name:     "valid capability schedule",
+			Schedule: `{"timeZone":"UTC","windows":[{"days":["Sat","Sun"],"capability":{"nvidia.com/gpu":"32"}}]}`,
+		},
+		{Name: "malformed capability schedule", Schedule: `nights`, ExpectErr: true},
+		{
+			Name:      "unknown day",
+			Schedule:  `{"windows":[{"days":["Weekend"],"capability":{"cpu":"8"}}]}`,
+			ExpectErr: true,
+		},
+		{
+			Name:      "invalid time",
+			Schedule:  `{"windows":[{"start":"8pm","capability":{"cpu":"8"}}]}`,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			queue := &schedulingv1beta1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "schedule", Annotations: map[string]string{}},
+				Spec:       schedulingv1beta1.QueueSpec{Weight: 1},
+				Status:     schedulingv1beta1.QueueStatus{State: schedulingv1beta1.QueueStateOpen},
+			}
+			if testCase.Schedule != "" {
+				queue.Annotations[apis.QueueCapabilityScheduleKey] = testCase.Schedule
+			}
+			err := validateQueue(queue)
+			if (err != nil) != testCase.ExpectErr {
+				t.Errorf("expected error %v, got %v", testCase.ExpectErr, err)
+			}
+		})
+	}
+}
+
+// setVolcanoClient sets the fake client of the objects, and the synced informers the admission lists from.
+func setVolcanoClient(t *testing.T, objects ...runtime.Object) {
+	config.VolcanoClient = fakeclient.NewSimpleClientset(objects...)
+	config.VolcanoInformerFactory = informerfactory.NewSharedInformerFactory(config.VolcanoClient, 0)
+	service.Informers(config)
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	config.VolcanoInformerFactory.Start(stopCh)
+	for informerType, ok := range config.VolcanoInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			t.Fatalf("failed to sync the informer of %v", informerType)
+		}
+	}
+}
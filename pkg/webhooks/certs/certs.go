@@ -0,0 +1,310 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// CACertKey is the key of the CA certificate in the certificate secret.
+	CACertKey = "ca.crt"
+	// TLSCertKey is the key of the serving certificate in the certificate secret.
+	TLSCertKey = v1.TLSCertKey
+	// TLSKeyKey is the key of the serving private key in the certificate secret.
+	TLSKeyKey = v1.TLSPrivateKeyKey
+
+	rsaKeySize = 2048
+)
+
+// Certificates is a serving certificate together with the CA which signs it, all PEM encoded.
+type Certificates struct {
+	CACert []byte
+	Cert   []byte
+	Key    []byte
+}
+
+// GenerateCertificates generates a self-signed CA and a serving certificate signed by it,
+// valid for the in-cluster DNS names of the service.
+func GenerateCertificates(service, namespace string, validity time.Duration, now time.Time) (*Certificates, error) {
+	caKey, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(now.UnixNano()),
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s.%s.svc-ca", service, namespace)},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serving key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano() + 1),
+		Subject:      pkix.Name{CommonName: fmt.Sprintf("%s.%s.svc", service, namespace)},
+		DNSNames: []string{
+			service,
+			fmt.Sprintf("%s.%s", service, namespace),
+			fmt.Sprintf("%s.%s.svc", service, namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace),
+		},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create serving certificate: %v", err)
+	}
+
+	return &Certificates{
+		CACert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		Cert:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:    pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}, nil
+}
+
+// NeedsRotation returns whether the certificates are invalid or expire within the given duration.
+func NeedsRotation(certs *Certificates, before time.Duration, now time.Time) bool {
+	if certs == nil || len(certs.CACert) == 0 {
+		return true
+	}
+	pair, err := tls.X509KeyPair(certs.Cert, certs.Key)
+	if err != nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return true
+	}
+	return now.Add(before).After(cert.NotAfter)
+}
+
+// Provisioner keeps the serving certificates of the admission service in a secret, generating
+// them when missing and rotating them before they expire. The secret is shared by all the
+// replicas of the admission service.
+type Provisioner struct {
+	KubeClient    kubernetes.Interface
+	Namespace     string
+	ServiceName   string
+	SecretName    string
+	Validity      time.Duration
+	RotateBefore  time.Duration
+	CheckInterval time.Duration
+}
+
+// Ensure returns the certificates in the secret, generating new ones if they are missing or
+// about to expire.
+func (p *Provisioner) Ensure() (*Certificates, error) {
+	secret, err := p.KubeClient.CoreV1().Secrets(p.Namespace).Get(context.TODO(), p.SecretName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get certificate secret %s/%s: %v", p.Namespace, p.SecretName, err)
+	}
+
+	var current *Certificates
+	if err == nil {
+		current = &Certificates{CACert: secret.Data[CACertKey], Cert: secret.Data[TLSCertKey], Key: secret.Data[TLSKeyKey]}
+		if !NeedsRotation(current, p.RotateBefore, time.Now()) {
+			return current, nil
+		}
+	}
+
+	certs, err := GenerateCertificates(p.ServiceName, p.Namespace, p.Validity, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		// keep trusting the previous CA until all the replicas serve the new certificate
+		if block, _ := pem.Decode(current.CACert); block != nil {
+			certs.CACert = append(certs.CACert, pem.EncodeToMemory(block)...)
+		}
+	}
+	data := map[string][]byte{CACertKey: certs.CACert, TLSCertKey: certs.Cert, TLSKeyKey: certs.Key}
+
+	if current == nil {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: p.Namespace, Name: p.SecretName},
+			Type:       v1.SecretTypeOpaque,
+			Data:       data,
+		}
+		if _, err := p.KubeClient.CoreV1().Secrets(p.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// another replica provisioned the certificates first
+				return p.Ensure()
+			}
+			return nil, fmt.Errorf("failed to create certificate secret %s/%s: %v", p.Namespace, p.SecretName, err)
+		}
+		klog.Infof("Provisioned certificates of service %s/%s in secret %s", p.Namespace, p.ServiceName, p.SecretName)
+		return certs, nil
+	}
+
+	secret.Data = data
+	if _, err := p.KubeClient.CoreV1().Secrets(p.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			return p.Ensure()
+		}
+		return nil, fmt.Errorf("failed to update certificate secret %s/%s: %v", p.Namespace, p.SecretName, err)
+	}
+	klog.Infof("Rotated certificates of service %s/%s in secret %s", p.Namespace, p.ServiceName, p.SecretName)
+	return certs, nil
+}
+
+// Run checks the certificates periodically, calling onChange with the certificates when they
+// are rotated, by this or another replica. A rotation onChange fails on is retried on the next check.
+func (p *Provisioner) Run(current *Certificates, onChange func(*Certificates) error, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(p.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			certs, err := p.Ensure()
+			if err != nil {
+				klog.Errorf("Failed to rotate certificates: %v", err)
+				continue
+			}
+			if !bytes.Equal(certs.Cert, current.Cert) {
+				if err := onChange(certs); err != nil {
+					klog.Errorf("Failed to apply the rotated certificates, retrying: %v", err)
+					continue
+				}
+				current = certs
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// FileSource reloads the certificates from files, e.g. a secret mounted and renewed by cert-manager.
+type FileSource struct {
+	// Load reads the certificates from the files, decoding them the same way as on startup,
+	// e.g. decrypting them.
+	Load func() (*Certificates, error)
+}
+
+// Run reloads the files periodically, calling onChange with the certificates when they change.
+// A change onChange fails on is retried on the next reload.
+func (f *FileSource) Run(current *Certificates, interval time.Duration, onChange func(*Certificates) error, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			certs, err := f.Load()
+			if err != nil {
+				klog.Errorf("Failed to reload certificates: %v", err)
+				continue
+			}
+			if !bytes.Equal(certs.Cert, current.Cert) || !bytes.Equal(certs.CACert, current.CACert) {
+				if err := onChange(certs); err != nil {
+					klog.Errorf("Failed to apply the reloaded certificates, retrying: %v", err)
+					continue
+				}
+				current = certs
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// Holder serves the current certificate to the TLS server, so that the certificate can be
+// rotated without restarting the server.
+type Holder struct {
+	sync.RWMutex
+	cert *tls.Certificate
+}
+
+// Set replaces the served certificate.
+func (h *Holder) Set(certs *Certificates) error {
+	cert, err := tls.X509KeyPair(certs.Cert, certs.Key)
+	if err != nil {
+		return err
+	}
+	h.Lock()
+	defer h.Unlock()
+	h.cert = &cert
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (h *Holder) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	h.RLock()
+	defer h.RUnlock()
+	if h.cert == nil {
+		return nil, fmt.Errorf("no serving certificate")
+	}
+	return h.cert, nil
+}
+
+// Rotator applies the rotated certificates: it publishes their CA bundle first, and serves them only
+// once it is published, so that the API server trusts the certificate of this replica at all times.
+type Rotator struct {
+	Holder *Holder
+	// PublishCABundle publishes the CA bundle, e.g. into the webhook configurations; it is skipped if nil.
+	PublishCABundle func(caBundle []byte) error
+}
+
+// OnChange publishes the CA bundle of the certificates, then serves them; the served certificate is
+// kept on error.
+func (r *Rotator) OnChange(certs *Certificates) error {
+	if _, err := tls.X509KeyPair(certs.Cert, certs.Key); err != nil {
+		return fmt.Errorf("invalid serving certificate: %v", err)
+	}
+	if r.PublishCABundle != nil {
+		if err := r.PublishCABundle(certs.CACert); err != nil {
+			return fmt.Errorf("failed to publish the CA bundle: %v", err)
+		}
+	}
+	if err := r.Holder.Set(certs); err != nil {
+		return err
+	}
+	klog.Infof("Serving the rotated certificate")
+	return nil
+}
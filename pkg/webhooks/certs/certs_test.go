@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGenerateCertificates(t *testing.T) {
+	now := time.Now()
+	certs, err := GenerateCertificates("volcano-admission-service", "volcano-system", 24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(certs.CACert) {
+		t.Fatalf("invalid CA certificate")
+	}
+	holder := &Holder{}
+	if err := holder.Set(certs); err != nil {
+		t.Fatalf("invalid serving certificate: %v", err)
+	}
+	served, _ := holder.GetCertificate(nil)
+	cert, err := x509.ParseCertificate(served.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse serving certificate: %v", err)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		DNSName:     "volcano-admission-service.volcano-system.svc",
+		Roots:       pool,
+		CurrentTime: now,
+	}); err != nil {
+		t.Errorf("failed to verify serving certificate: %v", err)
+	}
+
+	testCases := []struct {
+		Name   string
+		Before time.Duration
+		Expect bool
+	}{
+		{Name: "valid certificates", Before: time.Hour, Expect: false},
+		{Name: "certificates about to expire", Before: 48 * time.Hour, Expect: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if rotate := NeedsRotation(certs, testCase.Before, now); rotate != testCase.Expect {
+				t.Errorf("expected rotation %v, but got %v", testCase.Expect, rotate)
+			}
+		})
+	}
+}
+
+func TestProvisionerEnsure(t *testing.T) {
+	provisioner := &Provisioner{
+		KubeClient:   fake.NewSimpleClientset(),
+		Namespace:    "volcano-system",
+		ServiceName:  "volcano-admission-service",
+		SecretName:   "volcano-admission-secret",
+		Validity:     24 * time.Hour,
+		RotateBefore: time.Hour,
+	}
+
+	provisioned, err := provisioner.Ensure()
+	if err != nil {
+		t.Fatalf("failed to provision certificates: %v", err)
+	}
+	current, err := provisioner.Ensure()
+	if err != nil {
+		t.Fatalf("failed to get certificates: %v", err)
+	}
+	if !bytes.Equal(provisioned.Cert, current.Cert) {
+		t.Errorf("expected the provisioned certificates are kept")
+	}
+
+	// all the certificates expire within the rotation window
+	provisioner.RotateBefore = 48 * time.Hour
+	rotated, err := provisioner.Ensure()
+	if err != nil {
+		t.Fatalf("failed to rotate certificates: %v", err)
+	}
+	if bytes.Equal(provisioned.Cert, rotated.Cert) {
+		t.Errorf("expected the certificates are rotated")
+	}
+	if !bytes.HasSuffix(rotated.CACert, provisioned.CACert) {
+		t.Errorf("expected the previous CA is still trusted after rotation")
+	}
+}
+
+func TestRotatorOnChange(t *testing.T) {
+	now := time.Now()
+	previous, err := GenerateCertificates("volcano-admission-service", "volcano-system", 24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+	rotated, err := GenerateCertificates("volcano-admission-service", "volcano-system", 24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+	holder := &Holder{}
+	if err := holder.Set(previous); err != nil {
+		t.Fatalf("invalid serving certificate: %v", err)
+	}
+	served := func() []byte {
+		cert, _ := holder.GetCertificate(nil)
+		return cert.Certificate[0]
+	}
+	previousCert := served()
+
+	var published [][]byte
+	publishErr := fmt.Errorf("webhook configuration unavailable")
+	rotator := &Rotator{Holder: holder, PublishCABundle: func(caBundle []byte) error {
+		if !bytes.Equal(served(), previousCert) {
+			t.Errorf("expected the CA bundle is published before the rotated certificate is served")
+		}
+		published = append(published, caBundle)
+		return publishErr
+	}}
+
+	if err := rotator.OnChange(rotated); err == nil {
+		t.Errorf("expected an error when the CA bundle is not published")
+	}
+	if !bytes.Equal(served(), previousCert) {
+		t.Errorf("expected the previous certificate is served when the CA bundle is not published")
+	}
+
+	publishErr = nil
+	if err := rotator.OnChange(rotated); err != nil {
+		t.Fatalf("failed to rotate the certificates: %v", err)
+	}
+	if bytes.Equal(served(), previousCert) {
+		t.Errorf("expected the rotated certificate is served once the CA bundle is published")
+	}
+	if len(published) != 2 || !bytes.Equal(published[1], rotated.CACert) {
+		t.Errorf("expected the CA bundle of the rotated certificates is published twice, got %d", len(published))
+	}
+}
+
+func TestProvisionerRunRetries(t *testing.T) {
+	provisioner := &Provisioner{
+		KubeClient:    fake.NewSimpleClientset(),
+		Namespace:     "volcano-system",
+		ServiceName:   "volcano-admission-service",
+		SecretName:    "volcano-admission-secret",
+		Validity:      24 * time.Hour,
+		RotateBefore:  time.Hour,
+		CheckInterval: 10 * time.Millisecond,
+	}
+	provisioned, err := provisioner.Ensure()
+	if err != nil {
+		t.Fatalf("failed to provision certificates: %v", err)
+	}
+	// the certificates served before another replica provisioned the secret
+	current, err := GenerateCertificates("volcano-admission-service", "volcano-system", 24*time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+
+	calls := make(chan *Certificates, 10)
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		attempts := 0
+		provisioner.Run(current, func(certs *Certificates) error {
+			calls <- certs
+			if attempts++; attempts == 1 {
+				return fmt.Errorf("failed to patch the CA bundle")
+			}
+			return nil
+		}, stopCh)
+	}()
+
+	// the failed change is retried on the next check, and not applied again once it succeeds
+	for i := 0; i < 2; i++ {
+		select {
+		case certs := <-calls:
+			if !bytes.Equal(certs.Cert, provisioned.Cert) {
+				t.Errorf("expected the provisioned certificates")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the change applied %d times", i+1)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(stopCh)
+	<-done
+	if len(calls) != 0 {
+		t.Errorf("expected the change not applied again, got %d more", len(calls))
+	}
+}
@@ -45,10 +45,52 @@ type ResGroupConfig struct {
 	Affinity      string            `yaml:"affinity"`
 }
 
+// JobDefaultsConfig defines the cluster defaults applied to the jobs which do not specify them.
+type JobDefaultsConfig struct {
+	// Queue is the default queue of the jobs and podgroups in the namespaces without a default queue.
+	Queue string `yaml:"queue"`
+	// NamespaceQueues maps a namespace to the default queue of its jobs and podgroups. The
+	// scheduling.volcano.sh/queue-name annotation of the namespace takes precedence.
+	NamespaceQueues map[string]string `yaml:"namespaceQueues"`
+	// SchedulerName is the default scheduler of the jobs.
+	SchedulerName string `yaml:"schedulerName"`
+	// MaxRetry is the default maxRetry of the jobs.
+	MaxRetry int32 `yaml:"maxRetry"`
+	// Plugins are the plugins injected into every job, the arguments given by the job are kept.
+	Plugins map[string][]string `yaml:"plugins"`
+}
+
+// ResourceNormalizationConfig defines how the resource requests of jobs are normalized, so that
+// job specs are portable across clusters with different device plugins.
+type ResourceNormalizationConfig struct {
+	// ResourceNames maps a portable resource name to the resource name of the device plugin
+	// in this cluster, e.g. volcano.sh/gpu: nvidia.com/gpu.
+	ResourceNames map[string]string `yaml:"resourceNames"`
+	// Annotations maps a pod annotation to the resource it requests, e.g. volcano.sh/gpu-memory:
+	// volcano.sh/gpu-memory. The value of the annotation is set as the request and limit of the
+	// resource of the first container, unless a container already requests the resource.
+	Annotations map[string]string `yaml:"annotations"`
+	// RoundHugepages rounds hugepages requests and limits up to a multiple of the page size.
+	RoundHugepages bool `yaml:"roundHugepages"`
+}
+
+const (
+	// QueueFeasibilityWarn returns an admission warning for the jobs which can never fit into their queue.
+	QueueFeasibilityWarn = "Warn"
+	// QueueFeasibilityReject rejects the jobs which can never fit into their queue.
+	QueueFeasibilityReject = "Reject"
+)
+
 // AdmissionConfiguration defines the configuration of admission.
 type AdmissionConfiguration struct {
 	sync.Mutex
-	ResGroupsConfig []ResGroupConfig `yaml:"resourceGroups"`
+	ResGroupsConfig []ResGroupConfig  `yaml:"resourceGroups"`
+	JobDefaults     JobDefaultsConfig `yaml:"jobDefaults"`
+	// QueueFeasibility is how jobs whose minimal request exceeds the capability of their queue are
+	// handled, either Warn or Reject. The check is disabled when it is empty.
+	QueueFeasibility string `yaml:"queueFeasibility"`
+	// ResourceNormalization is how the resource requests of the jobs are normalized.
+	ResourceNormalization ResourceNormalizationConfig `yaml:"resourceNormalization"`
 }
 
 var admissionConf AdmissionConfiguration
@@ -73,6 +115,9 @@ func LoadAdmissionConf(confPath string) *AdmissionConfiguration {
 
 	admissionConf.Lock()
 	admissionConf.ResGroupsConfig = data.ResGroupsConfig
+	admissionConf.JobDefaults = data.JobDefaults
+	admissionConf.QueueFeasibility = data.QueueFeasibility
+	admissionConf.ResourceNormalization = data.ResourceNormalization
 	admissionConf.Unlock()
 	return &admissionConf
 }
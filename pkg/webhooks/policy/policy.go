@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/distribution/reference"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/cache"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+const (
+	// GroupName is the API group of the Policy CRD.
+	GroupName = "policy.volcano.sh"
+	// Version is the API version of the Policy CRD.
+	Version = "v1alpha1"
+)
+
+// PolicyResource is the resource of the namespaced Policy CRD.
+var PolicyResource = schema.GroupVersionResource{Group: GroupName, Version: Version, Resource: "policies"}
+
+// Policy is a set of guardrails which the jobs submitted to its namespace must comply with.
+// All the policies of a namespace are enforced.
+type Policy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PolicySpec `json:"spec,omitempty"`
+}
+
+// PolicySpec describes the constraints of a Policy, an unset field does not constrain the job.
+type PolicySpec struct {
+	// MaxReplicasPerJob is the maximum of the total replicas of all the tasks of a job.
+	MaxReplicasPerJob *int32 `json:"maxReplicasPerJob,omitempty"`
+	// AllowedQueues are the queues jobs can be submitted to.
+	AllowedQueues []string `json:"allowedQueues,omitempty"`
+	// RequiredPriorityClasses are the priority classes one of which the job must use.
+	RequiredPriorityClasses []string `json:"requiredPriorityClasses,omitempty"`
+	// ForbidHostNetwork rejects jobs whose pods use the host network.
+	ForbidHostNetwork bool `json:"forbidHostNetwork,omitempty"`
+	// AllowedImageRegistries are the registries, or registry/repository prefixes, the images
+	// of the job must be pulled from. An image without registry is pulled from docker.io, and
+	// an official image from docker.io/library, e.g. busybox is docker.io/library/busybox.
+	AllowedImageRegistries []string `json:"allowedImageRegistries,omitempty"`
+}
+
+// Installed returns whether the Policy CRD is installed, so that its informer can sync.
+func Installed(client discovery.DiscoveryInterface) (bool, error) {
+	resources, err := client.ServerResourcesForGroupVersion(PolicyResource.GroupVersion().String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == PolicyResource.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListPolicies returns the policies of the namespace from the lister of the informer of the policies.
+func ListPolicies(lister cache.GenericLister, namespace string) ([]*Policy, error) {
+	objs, err := lister.ByNamespace(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*Policy, 0, len(objs))
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected policy type %T", obj)
+		}
+		policy := &Policy{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, policy); err != nil {
+			return nil, fmt.Errorf("failed to convert policy <%s/%s>: %v", namespace, u.GetName(), err)
+		}
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies, nil
+}
+
+// violation is a breach of a policy rule, the key identifies the rule and the subject breaching it.
+type violation struct {
+	key     string
+	message string
+}
+
+// ValidateJob returns the violations of the policies by the job.
+func ValidateJob(job *batch.Job, policies []*Policy) []string {
+	var messages []string
+	for _, policy := range policies {
+		for _, v := range validateJob(job, &policy.Spec) {
+			messages = append(messages, fmt.Sprintf("policy %s: %s", policy.Name, v.message))
+		}
+	}
+	return messages
+}
+
+// ValidateJobUpdate returns the violations of the policies introduced by the update of the job.
+// Violations the job already had, e.g. when it was admitted before the policy was created, are
+// tolerated as long as the update does not make them worse, so that the job can still be scaled down.
+func ValidateJobUpdate(old, new *batch.Job, policies []*Policy) []string {
+	var messages []string
+	for _, policy := range policies {
+		existing := map[string]bool{}
+		for _, v := range validateJob(old, &policy.Spec) {
+			existing[v.key] = true
+		}
+		for _, v := range validateJob(new, &policy.Spec) {
+			if existing[v.key] && (v.key != maxReplicasKey || totalReplicas(new) <= totalReplicas(old)) {
+				continue
+			}
+			messages = append(messages, fmt.Sprintf("policy %s: %s", policy.Name, v.message))
+		}
+	}
+	return messages
+}
+
+const maxReplicasKey = "maxReplicasPerJob"
+
+func validateJob(job *batch.Job, spec *PolicySpec) []violation {
+	var violations []violation
+
+	if spec.MaxReplicasPerJob != nil {
+		if replicas := totalReplicas(job); replicas > *spec.MaxReplicasPerJob {
+			violations = append(violations, violation{maxReplicasKey,
+				fmt.Sprintf("job has %d replicas, more than the maximum %d", replicas, *spec.MaxReplicasPerJob)})
+		}
+	}
+
+	if len(spec.AllowedQueues) != 0 && !contains(spec.AllowedQueues, job.Spec.Queue) {
+		violations = append(violations, violation{"allowedQueues/" + job.Spec.Queue,
+			fmt.Sprintf("queue %s is not allowed, allowed queues are %v", job.Spec.Queue, spec.AllowedQueues)})
+	}
+
+	if len(spec.RequiredPriorityClasses) != 0 {
+		if !contains(spec.RequiredPriorityClasses, job.Spec.PriorityClassName) {
+			violations = append(violations, violation{"requiredPriorityClasses/" + job.Spec.PriorityClassName,
+				fmt.Sprintf("job priority class %q is not one of %v", job.Spec.PriorityClassName, spec.RequiredPriorityClasses)})
+		}
+		for _, task := range job.Spec.Tasks {
+			name := task.Template.Spec.PriorityClassName
+			if name != "" && !contains(spec.RequiredPriorityClasses, name) {
+				violations = append(violations, violation{"requiredPriorityClasses/" + task.Name + "/" + name,
+					fmt.Sprintf("task %s priority class %q is not one of %v", task.Name, name, spec.RequiredPriorityClasses)})
+			}
+		}
+	}
+
+	for _, task := range job.Spec.Tasks {
+		if spec.ForbidHostNetwork && task.Template.Spec.HostNetwork {
+			violations = append(violations, violation{"forbidHostNetwork/" + task.Name,
+				fmt.Sprintf("task %s uses the host network", task.Name)})
+		}
+		if len(spec.AllowedImageRegistries) == 0 {
+			continue
+		}
+		for _, container := range allContainers(&task.Template.Spec) {
+			allowed, err := imageAllowed(container.Image, spec.AllowedImageRegistries)
+			if err != nil {
+				violations = append(violations, violation{"allowedImageRegistries/" + task.Name + "/" + container.Image,
+					fmt.Sprintf("image %s of task %s is invalid: %v", container.Image, task.Name, err)})
+			} else if !allowed {
+				violations = append(violations, violation{"allowedImageRegistries/" + task.Name + "/" + container.Image,
+					fmt.Sprintf("image %s of task %s is not from the allowed registries %v",
+						container.Image, task.Name, spec.AllowedImageRegistries)})
+			}
+		}
+	}
+
+	return violations
+}
+
+func totalReplicas(job *batch.Job) int32 {
+	var replicas int32
+	for _, task := range job.Spec.Tasks {
+		replicas += task.Replicas
+	}
+	return replicas
+}
+
+func allContainers(spec *v1.PodSpec) []v1.Container {
+	containers := make([]v1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(containers, spec.InitContainers...)
+	return append(containers, spec.Containers...)
+}
+
+// imageAllowed returns whether the image, normalized as the container runtimes pull it, is within one of
+// the registries, e.g. image "nginx" is "docker.io/library/nginx" which matches the registry "docker.io".
+func imageAllowed(image string, registries []string) (bool, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return false, err
+	}
+	name := named.Name()
+	for _, registry := range registries {
+		prefix := strings.TrimSuffix(registry, "/") + "/"
+		if strings.HasPrefix(name, prefix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
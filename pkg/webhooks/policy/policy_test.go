@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func newJob(queue string, replicas int32, image string, hostNetwork bool) *batch.Job {
+	return &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "job"},
+		Spec: batch.JobSpec{
+			Queue: queue,
+			Tasks: []batch.TaskSpec{{
+				Name:     "worker",
+				Replicas: replicas,
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						HostNetwork: hostNetwork,
+						Containers:  []v1.Container{{Name: "worker", Image: image}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestValidateJob(t *testing.T) {
+	maxReplicas := int32(4)
+	policies := []*Policy{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "guardrails"},
+		Spec: PolicySpec{
+			MaxReplicasPerJob:       &maxReplicas,
+			AllowedQueues:           []string{"team-a"},
+			RequiredPriorityClasses: []string{"batch"},
+			ForbidHostNetwork:       true,
+			AllowedImageRegistries:  []string{"docker.io/library", "registry.example.com"},
+		},
+	}}
+
+	testCases := []struct {
+		name     string
+		job      *batch.Job
+		expected []string
+	}{
+		{
+			name: "compliant job",
+			job: func() *batch.Job {
+				job := newJob("team-a", 4, "registry.example.com/train:v1", false)
+				job.Spec.PriorityClassName = "batch"
+				return job
+			}(),
+		},
+		{
+			name: "image without registry is from docker.io",
+			job: func() *batch.Job {
+				job := newJob("team-a", 1, "library/busybox", false)
+				job.Spec.PriorityClassName = "batch"
+				return job
+			}(),
+		},
+		{
+			name: "official image is from docker.io/library",
+			job: func() *batch.Job {
+				job := newJob("team-a", 1, "busybox:1.36", false)
+				job.Spec.PriorityClassName = "batch"
+				return job
+			}(),
+		},
+		{
+			name: "invalid image",
+			job: func() *batch.Job {
+				job := newJob("team-a", 1, "busybox::1", false)
+				job.Spec.PriorityClassName = "batch"
+				return job
+			}(),
+			expected: []string{
+				"policy guardrails: image busybox::1 of task worker is invalid: invalid reference format",
+			},
+		},
+		{
+			name: "all rules violated",
+			job:  newJob("default", 5, "quay.io/busybox", true),
+			expected: []string{
+				"policy guardrails: job has 5 replicas, more than the maximum 4",
+				"policy guardrails: queue default is not allowed, allowed queues are [team-a]",
+				`policy guardrails: job priority class "" is not one of [batch]`,
+				"policy guardrails: task worker uses the host network",
+				"policy guardrails: image quay.io/busybox of task worker is not from the allowed registries [docker.io/library registry.example.com]",
+			},
+		},
+		{
+			name: "registry prefix is not a partial match",
+			job: func() *batch.Job {
+				job := newJob("team-a", 1, "registry.example.com.evil/train:v1", false)
+				job.Spec.PriorityClassName = "batch"
+				return job
+			}(),
+			expected: []string{
+				"policy guardrails: image registry.example.com.evil/train:v1 of task worker is not from the allowed registries [docker.io/library registry.example.com]",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ValidateJob(tc.job, policies)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected violations %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateJobUpdate(t *testing.T) {
+	maxReplicas := int32(4)
+	policies := []*Policy{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "guardrails"},
+		Spec: PolicySpec{
+			MaxReplicasPerJob: &maxReplicas,
+			ForbidHostNetwork: true,
+		},
+	}}
+
+	testCases := []struct {
+		name     string
+		old      *batch.Job
+		new      *batch.Job
+		expected []string
+	}{
+		{
+			name: "scale down a job admitted before the policy",
+			old:  newJob("team-a", 8, "busybox", true),
+			new:  newJob("team-a", 6, "busybox", true),
+		},
+		{
+			name:     "scale up a job admitted before the policy",
+			old:      newJob("team-a", 6, "busybox", false),
+			new:      newJob("team-a", 8, "busybox", false),
+			expected: []string{"policy guardrails: job has 8 replicas, more than the maximum 4"},
+		},
+		{
+			name:     "scale up beyond the maximum",
+			old:      newJob("team-a", 2, "busybox", false),
+			new:      newJob("team-a", 5, "busybox", false),
+			expected: []string{"policy guardrails: job has 5 replicas, more than the maximum 4"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ValidateJobUpdate(tc.old, tc.new, policies)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected violations %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -19,10 +19,13 @@ package router
 import (
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 
 	"volcano.sh/apis/pkg/client/clientset/versioned"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
 	"volcano.sh/volcano/pkg/webhooks/config"
 )
 
@@ -35,6 +38,14 @@ type AdmissionServiceConfig struct {
 	VolcanoClient  versioned.Interface
 	Recorder       record.EventRecorder
 	ConfigData     *config.AdmissionConfiguration
+	// ControllerServiceAccounts are the service accounts of the Volcano controllers, as namespace/name,
+	// which submit to the queues on behalf of the owners checked before.
+	ControllerServiceAccounts []string
+	// KubeInformerFactory, VolcanoInformerFactory and DynamicInformerFactory provide the listers the admissions
+	// read the objects from, rather than getting them from the apiserver on every admission.
+	KubeInformerFactory    informers.SharedInformerFactory
+	VolcanoInformerFactory vcinformer.SharedInformerFactory
+	DynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
 }
 
 type AdmissionService struct {
@@ -46,4 +57,8 @@ type AdmissionService struct {
 	MutatingConfig   *whv1.MutatingWebhookConfiguration
 
 	Config *AdmissionServiceConfig
+
+	// Informers requests the informers of the factories of the config the admission reads from;
+	// they are synced before the admissions are served.
+	Informers func(config *AdmissionServiceConfig)
 }
@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	schedulinglisters "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+// QueueACLKey is the queue annotation restricting who may submit jobs and podgroups to the queue,
+// as a JSON object, e.g. {"namespaces":["team-a"],"groups":["gpu-users"],"serviceAccounts":["ci/runner"]}.
+// Everyone may submit to the queues without the annotation.
+const QueueACLKey = "volcano.sh/queue-acl"
+
+const serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+// QueueACL lists who may submit to a queue: a submission is allowed if its namespace, its user,
+// one of the groups of its user or its service account is listed.
+type QueueACL struct {
+	Namespaces []string `json:"namespaces,omitempty"`
+	Users      []string `json:"users,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	// ServiceAccounts are given as namespace/name.
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+}
+
+// ParseQueueACL parses the ACL annotation of a queue, nil if it is not set.
+func ParseQueueACL(annotations map[string]string) (*QueueACL, error) {
+	value, found := annotations[QueueACLKey]
+	if !found {
+		return nil, nil
+	}
+	acl := &QueueACL{}
+	if err := json.Unmarshal([]byte(value), acl); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %v", QueueACLKey, err)
+	}
+	for _, sa := range acl.ServiceAccounts {
+		if ns, name, found := strings.Cut(sa, "/"); !found || ns == "" || name == "" {
+			return nil, fmt.Errorf("invalid annotation %s: service account <%s> must be namespace/name", QueueACLKey, sa)
+		}
+	}
+	return acl, nil
+}
+
+// Allows returns whether the user may submit to the queue in the namespace.
+func (acl *QueueACL) Allows(namespace string, user authenticationv1.UserInfo) bool {
+	if contains(acl.Namespaces, namespace) || contains(acl.Users, user.Username) {
+		return true
+	}
+	for _, group := range user.Groups {
+		if contains(acl.Groups, group) {
+			return true
+		}
+	}
+	if sa := serviceAccountOf(user); sa != "" {
+		return contains(acl.ServiceAccounts, sa)
+	}
+	return false
+}
+
+// serviceAccountOf returns the service account of the user as namespace/name, empty if the user
+// is not a service account.
+func serviceAccountOf(user authenticationv1.UserInfo) string {
+	if sa, found := strings.CutPrefix(user.Username, serviceAccountUsernamePrefix); found {
+		return strings.Replace(sa, ":", "/", 1)
+	}
+	return ""
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsControllerServiceAccount returns whether the user is one of the controller service accounts,
+// given as namespace/name.
+func IsControllerServiceAccount(controllerServiceAccounts []string, user authenticationv1.UserInfo) bool {
+	sa := serviceAccountOf(user)
+	return sa != "" && contains(controllerServiceAccounts, sa)
+}
+
+// AuthorizeQueue checks the ACL of the queue allows the user of the request to submit to the
+// queue in the namespace. The controller service accounts, given as namespace/name, are always
+// allowed: they submit the podgroups and pods and move the jobs on behalf of owners checked
+// before. A missing queue is left to the other checks of the webhooks.
+func AuthorizeQueue(queueLister schedulinglisters.QueueLister, controllerServiceAccounts []string, queueName, namespace string, user authenticationv1.UserInfo) error {
+	if queueName == "" {
+		return nil
+	}
+	if IsControllerServiceAccount(controllerServiceAccounts, user) {
+		return nil
+	}
+	queue, err := queueLister.Get(queueName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to get queue %s: %v", queueName, err)
+	}
+	acl, err := ParseQueueACL(queue.Annotations)
+	if err != nil {
+		// the queue webhook rejects invalid ACLs, so fail closed on the ones admitted before it
+		return fmt.Errorf("queue %s has an invalid ACL: %v", queueName, err)
+	}
+	if acl == nil || acl.Allows(namespace, user) {
+		return nil
+	}
+	return fmt.Errorf("user %s is not allowed to submit to queue %s in namespace %s", user.Username, queueName, namespace)
+}
+
+// podGroupQueueIndex indexes the podgroups by queue.
+const podGroupQueueIndex = "queue"
+
+// AddPodGroupQueueIndex indexes the podgroups of the informer by queue, for CheckQueuePendingJobs.
+// It is called when the informers of the webhooks are registered, before they are started.
+func AddPodGroupQueueIndex(informer cache.SharedIndexInformer) {
+	if _, found := informer.GetIndexer().GetIndexers()[podGroupQueueIndex]; found {
+		return
+	}
+	if err := informer.AddIndexers(cache.Indexers{podGroupQueueIndex: func(obj interface{}) ([]string, error) {
+		pg, ok := obj.(*schedulingv1beta1.PodGroup)
+		if !ok {
+			return nil, nil
+		}
+		return []string{pg.Spec.Queue}, nil
+	}}); err != nil {
+		klog.Errorf("Failed to index the podgroups by queue: %v", err)
+	}
+}
+
+// CheckQueuePendingJobs rejects a new job or podgroup of the queue while the queue has as many pending
+// podgroups as its volcano.sh/max-pending-jobs annotation allows, so that a single user can not flood a
+// shared queue. The podgroups are looked up in the indexer of AddPodGroupQueueIndex. A missing queue is
+// left to the other checks of the webhooks.
+func CheckQueuePendingJobs(queueLister schedulinglisters.QueueLister, podGroupIndexer cache.Indexer, queueName string) error {
+	if queueName == "" {
+		return nil
+	}
+	queue, err := queueLister.Get(queueName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to get queue %s: %v", queueName, err)
+	}
+	limit, err := api.ParseQueueJobLimit(queue.Annotations, api.QueueMaxPendingJobsAnnotationKey)
+	if err != nil || limit == 0 {
+		return nil
+	}
+
+	objs, err := podGroupIndexer.ByIndex(podGroupQueueIndex, queueName)
+	if err != nil {
+		return fmt.Errorf("unable to list the podgroups of queue %s: %v", queueName, err)
+	}
+	var pending int32
+	for _, obj := range objs {
+		if pg, ok := obj.(*schedulingv1beta1.PodGroup); ok &&
+			(pg.Status.Phase == schedulingv1beta1.PodGroupPending || pg.Status.Phase == "") {
+			pending++
+		}
+	}
+	if pending >= limit {
+		return fmt.Errorf("queue %s has %d pending jobs, reaching its max pending jobs %d (annotation %s)",
+			queueName, pending, limit, api.QueueMaxPendingJobsAnnotationKey)
+	}
+	return nil
+}
+
+// NamespaceLister returns the lister of the namespaces of the informer factory, nil without factory.
+func NamespaceLister(factory informers.SharedInformerFactory) corelisters.NamespaceLister {
+	if factory == nil {
+		return nil
+	}
+	return factory.Core().V1().Namespaces().Lister()
+}
+
+// DefaultQueue returns the queue of the jobs and podgroups of the namespace which do not specify
+// their queue, and where it comes from. The namespace is mapped to its default queue by its
+// scheduling.volcano.sh/queue-name annotation, then by the namespace queues of the admission
+// configuration; other namespaces use the default queue of the admission configuration, or the
+// builtin default queue.
+func DefaultQueue(namespaceLister corelisters.NamespaceLister, namespace string, defaults wkconfig.JobDefaultsConfig) (string, string) {
+	if namespaceLister != nil {
+		ns, err := namespaceLister.Get(namespace)
+		if err != nil {
+			klog.V(4).Infof("Failed to get namespace %s for its default queue: %v", namespace, err)
+		} else if queue := ns.Annotations[schedulingv1beta1.QueueNameAnnotationKey]; queue != "" {
+			return queue, QueueSourceNamespace
+		}
+	}
+	if queue := defaults.NamespaceQueues[namespace]; queue != "" {
+		return queue, QueueSourceNamespace
+	}
+	if defaults.Queue != "" {
+		return defaults.Queue, QueueSourceCluster
+	}
+	return schedulingv1beta1.DefaultQueue, QueueSourceBuiltin
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	schedulinglisters "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+func TestDefaultQueue(t *testing.T) {
+	namespaceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range []*v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a",
+			Annotations: map[string]string{schedulingv1beta1.QueueNameAnnotationKey: "queue-a"},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+	} {
+		namespaceIndexer.Add(ns)
+	}
+	namespaceLister := corelisters.NewNamespaceLister(namespaceIndexer)
+	defaults := wkconfig.JobDefaultsConfig{
+		Queue:           "shared",
+		NamespaceQueues: map[string]string{"team-a": "config-a", "team-b": "config-b"},
+	}
+
+	testCases := []struct {
+		name            string
+		namespaceLister corelisters.NamespaceLister
+		namespace       string
+		defaults        wkconfig.JobDefaultsConfig
+		expectedQueue   string
+		expectedSource  string
+	}{
+		{
+			name:            "annotation of the namespace",
+			namespaceLister: namespaceLister,
+			namespace:       "team-a",
+			defaults:        defaults,
+			expectedQueue:   "queue-a",
+			expectedSource:  QueueSourceNamespace,
+		},
+		{
+			name:            "namespace queue of the configuration",
+			namespaceLister: namespaceLister,
+			namespace:       "team-b",
+			defaults:        defaults,
+			expectedQueue:   "config-b",
+			expectedSource:  QueueSourceNamespace,
+		},
+		{
+			name:           "namespace queue of the configuration without lister",
+			namespace:      "team-a",
+			defaults:       defaults,
+			expectedQueue:  "config-a",
+			expectedSource: QueueSourceNamespace,
+		},
+		{
+			name:            "default queue of the configuration",
+			namespaceLister: namespaceLister,
+			namespace:       "team-c",
+			defaults:        defaults,
+			expectedQueue:   "shared",
+			expectedSource:  QueueSourceCluster,
+		},
+		{
+			name:            "builtin default queue",
+			namespaceLister: namespaceLister,
+			namespace:       "team-b",
+			expectedQueue:   schedulingv1beta1.DefaultQueue,
+			expectedSource:  QueueSourceBuiltin,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queue, source := DefaultQueue(tc.namespaceLister, tc.namespace, tc.defaults)
+			if queue != tc.expectedQueue || source != tc.expectedSource {
+				t.Errorf("expected queue %s from %s, got %s from %s", tc.expectedQueue, tc.expectedSource, queue, source)
+			}
+		})
+	}
+}
+
+func TestAuthorizeQueue(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, queue := range []*schedulingv1beta1.Queue{
+		{ObjectMeta: metav1.ObjectMeta{Name: "shared"}},
+		{ObjectMeta: metav1.ObjectMeta{
+			Name: "premium",
+			Annotations: map[string]string{
+				QueueACLKey: `{"namespaces":["team-a"],"users":["alice"],"groups":["gpu-users"],"serviceAccounts":["ci/runner"]}`,
+			},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{
+			Name:        "broken",
+			Annotations: map[string]string{QueueACLKey: `{"namespaces":`},
+		}},
+	} {
+		if err := indexer.Add(queue); err != nil {
+			t.Fatalf("failed to add queue %s: %v", queue.Name, err)
+		}
+	}
+	queueLister := schedulinglisters.NewQueueLister(indexer)
+	controllers := []string{"volcano-system/volcano-controllers"}
+	testCases := []struct {
+		name      string
+		queue     string
+		namespace string
+		user      authenticationv1.UserInfo
+		expectErr bool
+	}{
+		{name: "queue without ACL", queue: "shared", namespace: "team-b", user: authenticationv1.UserInfo{Username: "bob"}},
+		{name: "missing queue", queue: "missing", namespace: "team-b", user: authenticationv1.UserInfo{Username: "bob"}},
+		{name: "allowed namespace", queue: "premium", namespace: "team-a", user: authenticationv1.UserInfo{Username: "bob"}},
+		{name: "allowed user", queue: "premium", namespace: "team-b", user: authenticationv1.UserInfo{Username: "alice"}},
+		{
+			name:      "allowed group",
+			queue:     "premium",
+			namespace: "team-b",
+			user:      authenticationv1.UserInfo{Username: "bob", Groups: []string{"system:authenticated", "gpu-users"}},
+		},
+		{
+			name:      "allowed service account",
+			queue:     "premium",
+			namespace: "ci",
+			user:      authenticationv1.UserInfo{Username: "system:serviceaccount:ci:runner"},
+		},
+		{
+			name:      "other service account",
+			queue:     "premium",
+			namespace: "ci",
+			user:      authenticationv1.UserInfo{Username: "system:serviceaccount:ci:builder"},
+			expectErr: true,
+		},
+		{
+			name:      "not allowed",
+			queue:     "premium",
+			namespace: "team-b",
+			user:      authenticationv1.UserInfo{Username: "bob", Groups: []string{"system:authenticated"}},
+			expectErr: true,
+		},
+		{name: "invalid ACL", queue: "broken", namespace: "team-a", user: authenticationv1.UserInfo{Username: "alice"}, expectErr: true},
+		{
+			name:      "controller service account",
+			queue:     "premium",
+			namespace: "team-b",
+			user:      authenticationv1.UserInfo{Username: "system:serviceaccount:volcano-system:volcano-controllers"},
+		},
+		{
+			name:      "user named as the controller service account",
+			queue:     "premium",
+			namespace: "team-b",
+			user:      authenticationv1.UserInfo{Username: "volcano-system/volcano-controllers"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := AuthorizeQueue(queueLister, controllers, tc.queue, tc.namespace, tc.user)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expected error %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckQueuePendingJobs(t *testing.T) {
+	queueIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, queue := range []*schedulingv1beta1.Queue{
+		{ObjectMeta: metav1.ObjectMeta{Name: "unlimited"}},
+		{ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared",
+			Annotations: map[string]string{api.QueueMaxPendingJobsAnnotationKey: "2"},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{
+			Name:        "quiet",
+			Annotations: map[string]string{api.QueueMaxPendingJobsAnnotationKey: "2"},
+		}},
+	} {
+		if err := queueIndexer.Add(queue); err != nil {
+			t.Fatalf("failed to add queue %s: %v", queue.Name, err)
+		}
+	}
+	queueLister := schedulinglisters.NewQueueLister(queueIndexer)
+
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &schedulingv1beta1.PodGroup{}, 0, cache.Indexers{})
+	AddPodGroupQueueIndex(informer)
+	for i, pg := range []struct {
+		queue string
+		phase schedulingv1beta1.PodGroupPhase
+	}{
+		{"shared", schedulingv1beta1.PodGroupPending},
+		{"shared", ""},
+		{"unlimited", schedulingv1beta1.PodGroupPending},
+		{"unlimited", schedulingv1beta1.PodGroupPending},
+		{"quiet", schedulingv1beta1.PodGroupPending},
+		{"quiet", schedulingv1beta1.PodGroupRunning},
+	} {
+		podGroup := &schedulingv1beta1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("pg-%d", i)},
+			Spec:       schedulingv1beta1.PodGroupSpec{Queue: pg.queue},
+			Status:     schedulingv1beta1.PodGroupStatus{Phase: pg.phase},
+		}
+		if err := informer.GetIndexer().Add(podGroup); err != nil {
+			t.Fatalf("failed to add podgroup %s: %v", podGroup.Name, err)
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		queue     string
+		expectErr bool
+	}{
+		{name: "queue without limit", queue: "unlimited"},
+		{name: "missing queue", queue: "missing"},
+		{name: "queue below its max pending jobs", queue: "quiet"},
+		{name: "queue reaching its max pending jobs", queue: "shared", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckQueuePendingJobs(queueLister, informer.GetIndexer(), tc.queue)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expected error %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
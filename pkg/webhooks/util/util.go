@@ -17,11 +17,67 @@ limitations under the License.
 package util
 
 import (
+	"encoding/json"
+	"strings"
+
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 )
 
+// The audit annotations attached to the admission responses, so that auditors can reconstruct
+// why an object is configured the way it is. The API server prefixes them with the webhook name.
+const (
+	// AuditPatchedPaths lists the operations and paths of the patch of a mutating webhook.
+	AuditPatchedPaths = "patched-paths"
+	// AuditOriginalQueue is the queue given by the object before the mutation.
+	AuditOriginalQueue = "original-queue"
+	// AuditQueue is the queue the object is admitted to.
+	AuditQueue = "queue"
+	// AuditQueueSource is where the queue comes from, one of the QueueSource values.
+	AuditQueueSource = "queue-source"
+	// AuditPolicies lists the namespace policies the object is checked against.
+	AuditPolicies = "policies"
+	// AuditWarnings lists the warnings returned to the client.
+	AuditWarnings = "warnings"
+)
+
+const (
+	// QueueSourceObject is the queue given by the object itself.
+	QueueSourceObject = "object"
+	// QueueSourceNamespace is the default queue of the namespace.
+	QueueSourceNamespace = "namespace"
+	// QueueSourceCluster is the default queue of the admission configuration.
+	QueueSourceCluster = "cluster"
+	// QueueSourceBuiltin is the builtin default queue.
+	QueueSourceBuiltin = "builtin"
+)
+
+// AddAuditAnnotation sets the audit annotation on the admission response.
+func AddAuditAnnotation(response *admissionv1.AdmissionResponse, key, value string) {
+	if response.AuditAnnotations == nil {
+		response.AuditAnnotations = map[string]string{}
+	}
+	response.AuditAnnotations[key] = value
+}
+
+// AddPatchAuditAnnotation records the operations and paths of the JSON patch on the admission
+// response, e.g. "add /spec/queue,replace /spec/tasks".
+func AddPatchAuditAnnotation(response *admissionv1.AdmissionResponse, patch []byte) {
+	var operations []struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+	}
+	if len(patch) == 0 || json.Unmarshal(patch, &operations) != nil || len(operations) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		paths = append(paths, operation.Op+" "+operation.Path)
+	}
+	AddAuditAnnotation(response, AuditPatchedPaths, strings.Join(paths, ","))
+}
+
 // ToAdmissionResponse updates the admission response with the input error.
 func ToAdmissionResponse(err error) *admissionv1.AdmissionResponse {
 	klog.Error(err)
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+func TestAddPatchAuditAnnotation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		patch    string
+		expected map[string]string
+	}{
+		{
+			name: "empty patch",
+		},
+		{
+			name:  "empty operations",
+			patch: `[]`,
+		},
+		{
+			name:     "operations",
+			patch:    `[{"op":"add","path":"/spec/queue","value":"default"},{"op":"replace","path":"/spec/tasks","value":[]}]`,
+			expected: map[string]string{AuditPatchedPaths: "add /spec/queue,replace /spec/tasks"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := &admissionv1.AdmissionResponse{Allowed: true}
+			AddPatchAuditAnnotation(response, []byte(tc.patch))
+			if !reflect.DeepEqual(response.AuditAnnotations, tc.expected) {
+				t.Errorf("expected audit annotations %v, got %v", tc.expected, response.AuditAnnotations)
+			}
+		})
+	}
+}